@@ -23,12 +23,16 @@ import (
 	"github.com/projectdiscovery/interactsh/pkg/client"
 	"github.com/projectdiscovery/nuclei/v3/internal/runner"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/tarpit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/waf"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/matchervars"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/extensions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
@@ -42,9 +46,10 @@ import (
 )
 
 var (
-	cfgFile    string
-	memProfile string // optional profile file path
-	options    = &types.Options{}
+	cfgFile     string
+	profileFile string // optional scan profile file path or name
+	memProfile  string // optional profile file path
+	options     = &types.Options{}
 )
 
 func main() {
@@ -62,6 +67,16 @@ func main() {
 		return
 	}
 
+	if options.ListMatcherVariables != "" {
+		printable, err := matchervars.GetPrintableVariables(options.ListMatcherVariables, options.NoColor)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not list matcher variables: %s\n", err)
+		}
+		gologger.Info().Msgf("The available matcher variables are:")
+		fmt.Println(printable)
+		return
+	}
+
 	// sign the templates if requested - only glob syntax is supported
 	if options.SignTemplates {
 		// use parsed options when initializing signer instead of default options
@@ -180,6 +195,9 @@ func main() {
 	if fileutil.FileExists(resumeFileName) {
 		os.Remove(resumeFileName)
 	}
+	if code := nucleiRunner.ExitCode(); code != 0 {
+		os.Exit(code)
+	}
 }
 
 func readConfig() *goflags.FlagSet {
@@ -203,6 +221,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.TargetsFilePath, "list", "l", "", "path to file containing a list of target URLs/hosts to scan (one per line)"),
 		flagSet.StringSliceVarP(&options.ExcludeTargets, "exclude-hosts", "eh", nil, "hosts to exclude to scan from the input list (ip, cidr, hostname)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.StringVar(&options.Resume, "resume", "", "resume scan using resume.cfg (clustering will be disabled)"),
+		flagSet.IntVarP(&options.ShardIndex, "shard-index", "shi", 0, "index of the slice of targets to scan when sharding input (0 to shard-count-1)"),
+		flagSet.IntVarP(&options.ShardCount, "shard-count", "shc", 0, "number of slices to deterministically partition targets into (by target hash)"),
 		flagSet.BoolVarP(&options.ScanAllIPs, "scan-all-ips", "sa", false, "scan all the IP's associated with dns record"),
 		flagSet.StringSliceVarP(&options.IPVersion, "ip-version", "iv", nil, "IP version to scan of hostname (4,6) - (default 4)", goflags.CommaSeparatedStringSliceOptions),
 	)
@@ -211,6 +231,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.InputFileMode, "input-mode", "im", "list", fmt.Sprintf("mode of input file (%v)", provider.SupportedInputFormats())),
 		flagSet.BoolVarP(&options.FormatUseRequiredOnly, "required-only", "ro", false, "use only required fields in input format when generating requests"),
 		flagSet.BoolVarP(&options.SkipFormatValidation, "skip-format-validation", "sfv", false, "skip format validation (like missing vars) when parsing input file"),
+		flagSet.StringSliceVarP(&options.ReplayTargets, "replay-targets", "rpt", nil, "targets to replay requests of the input file against rewriting their host (cli, file)", goflags.FileStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("templates", "Templates",
@@ -229,6 +250,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVar(&options.SignTemplates, "sign", false, "signs the templates with the private key defined in NUCLEI_SIGNATURE_PRIVATE_KEY env variable"),
 		flagSet.BoolVar(&options.EnableCodeTemplates, "code", false, "enable loading code protocol-based templates"),
 		flagSet.BoolVarP(&options.DisableUnsignedTemplates, "disable-unsigned-templates", "dut", false, "disable running unsigned templates or templates with mismatched signature"),
+		flagSet.StringSliceVarP(&options.TrustStore, "trust-store", "tst", nil, "trusted certificate files or directories to verify templates against (comma-separated)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.TrustStorePolicy, "trust-store-policy", "tsp", "reject", "policy for templates failing trust store verification (warn, reject, fail)"),
 	)
 
 	flagSet.CreateGroup("filters", "Filtering",
@@ -250,6 +273,9 @@ on extensive configurability, massive extensibility and ease of use.`)
 
 	flagSet.CreateGroup("output", "Output",
 		flagSet.StringVarP(&options.Output, "output", "o", "", "output file to write found issues/vulnerabilities"),
+		flagSet.IntVarP(&options.OutputRotateSize, "output-rotate-size", "ors", 0, "rotate output files (-o, -jle) after given size in MB (0 to disable)"),
+		flagSet.DurationVarP(&options.OutputRotateInterval, "output-rotate-interval", "ori", 0, "rotate output files (-o, -jle) after given duration (ex: 1h)"),
+		flagSet.DurationVarP(&options.OutputFlushInterval, "output-flush-interval", "ofi", 0, "interval to flush written results of output files (-o, -jle, -se, -sqe) to disk (ex: 10s)"),
 		flagSet.BoolVarP(&options.StoreResponse, "store-resp", "sresp", false, "store all request/response passed through nuclei to output directory"),
 		flagSet.StringVarP(&options.StoreResponseDir, "store-resp-dir", "srd", runner.DefaultDumpTrafficOutputFolder, "store all request/response passed through nuclei to custom directory"),
 		flagSet.BoolVar(&options.Silent, "silent", false, "display findings only"),
@@ -258,25 +284,42 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.JSONRequests, "include-rr", "irr", true, "include request/response pairs in the JSON, JSONL, and Markdown outputs (for findings only) [DEPRECATED use `-omit-raw`]"),
 		flagSet.BoolVarP(&options.OmitRawRequests, "omit-raw", "or", false, "omit request/response pairs in the JSON, JSONL, and Markdown outputs (for findings only)"),
 		flagSet.BoolVarP(&options.OmitTemplate, "omit-template", "ot", false, "omit encoded template in the JSON, JSONL output"),
+		flagSet.BoolVarP(&options.HTTPieCommand, "httpie-command", "hic", false, "include httpie command reproducing http requests (besides curl) in the JSON, JSONL, and Markdown outputs"),
+		flagSet.StringSliceVarP(&options.GeoIPDatabases, "geoip-db", "gdb", nil, "maxmind database files (city, country, asn) to enrich results with geo and asn of ip", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.AnnotationsFile, "annotations", "ant", "", "yaml file mapping template ids to remediation, references and owner merged into results"),
 		flagSet.BoolVarP(&options.NoMeta, "no-meta", "nm", false, "disable printing result metadata in cli output"),
 		flagSet.BoolVarP(&options.Timestamp, "timestamp", "ts", false, "enables printing timestamp in cli output"),
 		flagSet.StringVarP(&options.ReportingDB, "report-db", "rdb", "", "nuclei reporting database (always use this to persist report data)"),
 		flagSet.BoolVarP(&options.MatcherStatus, "matcher-status", "ms", false, "display match failure status"),
+		flagSet.IntVarP(&options.PartialMatchThreshold, "partial-match", "pm", 0, "write partial match events for and matchers when at least given percent of matchers fired (0 to disable)"),
+		flagSet.BoolVarP(&options.RequestFailureEvents, "request-failure-events", "rfe", false, "write failure events with error type (dns_error, tls_error, timeout, connection_refused, read_error) for failed http requests"),
+		flagSet.StringSliceVarP(&options.ExitCodes, "exit-code", "ec", nil, "exit code for findings of severities (ex: high,critical:code=2,count=1), highest severity found wins, errors exit 1 (default 0) (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringVarP(&options.DriftBaseline, "drift-baseline", "dfb", "", "file storing target fingerprints built from results, writing drift events when they change across scans"),
+		flagSet.StringSliceVarP(&options.DriftFingerprint, "drift-fingerprint", "dff", nil, "components of drift fingerprints (matches,extracts,ip) (default matches,extracts)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.IntVarP(&options.DriftThreshold, "drift-threshold", "dft", 0, "percentage of changed fingerprint attributes above which drift is reported"),
 		flagSet.StringVarP(&options.MarkdownExportDirectory, "markdown-export", "me", "", "directory to export results in markdown format"),
 		flagSet.StringVarP(&options.SarifExport, "sarif-export", "se", "", "file to export results in SARIF format"),
 		flagSet.StringVarP(&options.JSONExport, "json-export", "je", "", "file to export results in JSON format"),
 		flagSet.StringVarP(&options.JSONLExport, "jsonl-export", "jle", "", "file to export results in JSONL(ine) format"),
+		flagSet.StringVarP(&options.SQLiteExport, "sqlite-export", "sqe", "", "sqlite database file to export results to (appends a new scan)"),
+		flagSet.StringVarP(&options.WebhookExport, "webhook-export", "whe", "", "webhook url to post results to as they are found (signing, batching and retries in report-config)"),
+		flagSet.StringVarP(&options.BundleExport, "bundle-export", "be", "", "directory to write a reproducible proof bundle of each finding to (zip archives in report-config)"),
 	)
 
 	flagSet.CreateGroup("configs", "Configurations",
 		flagSet.StringVar(&cfgFile, "config", "", "path to the nuclei configuration file"),
+		flagSet.StringVarP(&profileFile, "profile", "sprof", "", "scan profile file (or name in profiles directory of templates) bundling flags, cli flags and config file take precedence"),
 		flagSet.BoolVarP(&options.FollowRedirects, "follow-redirects", "fr", false, "enable following redirects for http templates"),
 		flagSet.BoolVarP(&options.FollowHostRedirects, "follow-host-redirects", "fhr", false, "follow redirects on the same host"),
 		flagSet.IntVarP(&options.MaxRedirects, "max-redirects", "mr", 10, "max number of redirects to follow for http templates"),
 		flagSet.BoolVarP(&options.DisableRedirects, "disable-redirects", "dr", false, "disable redirects for http templates"),
 		flagSet.StringVarP(&options.ReportingConfig, "report-config", "rc", "", "nuclei reporting module configuration file"), // TODO merge into the config file or rename to issue-tracking
 		flagSet.StringSliceVarP(&options.CustomHeaders, "header", "H", nil, "custom header/cookie to include in all http request in header:value format (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringVarP(&options.CookieFile, "cookie-file", "cookies", "", "netscape/json cookie file with cookies to include in matching http requests"),
+		flagSet.BoolVarP(&options.CookieFileUpdate, "cookie-file-update", "cfu", false, "update cookies of the cookie file with cookies set by responses during the scan"),
 		flagSet.RuntimeMapVarP(&options.Vars, "var", "V", nil, "custom vars in key=value format"),
+		flagSet.StringVarP(&options.InputVarsFile, "input-vars", "ivars", "", "csv/json file with per-input vars keyed by target (takes precedence over -var)"),
+		flagSet.StringSliceVarP(&options.BasePathMap, "base-path-map", "bpm", nil, "map input hosts to a base path prefixed to http request paths in host=/prefix format (cli, file)", goflags.FileStringSliceOptions),
 		flagSet.StringVarP(&options.ResolversFile, "resolvers", "r", "", "file containing resolver list for nuclei"),
 		flagSet.BoolVarP(&options.SystemResolvers, "system-resolvers", "sr", false, "use system DNS resolving as error fallback"),
 		flagSet.BoolVarP(&options.DisableClustering, "disable-clustering", "dc", false, "disable clustering of requests"),
@@ -292,6 +335,13 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.DurationVarP(&options.DialerTimeout, "dialer-timeout", "dt", 0, "timeout for network requests."),
 		flagSet.DurationVarP(&options.DialerKeepAlive, "dialer-keep-alive", "dka", 0, "keep-alive duration for network requests."),
 		flagSet.BoolVarP(&options.AllowLocalFileAccess, "allow-local-file-access", "lfa", false, "allows file (payload) access anywhere on the system"),
+		flagSet.BoolVarP(&options.AllowRemotePayloads, "allow-remote-payloads", "arp", false, "allows loading payloads from remote urls (http/https/s3/gs)"),
+		flagSet.StringVarP(&options.RemotePayloadCacheDir, "remote-payload-cache-dir", "rpcd", "", "directory to cache remote payloads in (default nuclei cache dir)"),
+		flagSet.DurationVarP(&options.RemotePayloadCacheExpiry, "remote-payload-cache-expiry", "rpce", 24*time.Hour, "duration after which cached remote payloads are revalidated"),
+		flagSet.StringVarP(&options.ErrorPageSignatures, "error-page-signatures", "eps", "", "yaml file of error page signatures overriding the built-in ones for error-page matchers"),
+		flagSet.StringVarP(&options.SSRFTargets, "ssrf-targets", "sst", "", "yaml file of internal targets overriding the built-in ones for ssrf matchers"),
+		flagSet.StringVarP(&options.DSLFunctions, "dsl-functions", "dslf", "", "javascript file of user defined functions callable by name from dsl expressions of templates"),
+		flagSet.DurationVarP(&options.DSLFunctionTimeout, "dsl-function-timeout", "dslft", time.Second, "max execution time of a user defined dsl function call"),
 		flagSet.BoolVarP(&options.RestrictLocalNetworkAccess, "restrict-local-network-access", "lna", false, "blocks connections to the local / private network"),
 		flagSet.StringVarP(&options.Interface, "interface", "i", "", "network interface to use for network scan"),
 		flagSet.StringVarP(&options.AttackType, "attack-type", "at", "", "type of payload combinations to perform (batteringram,pitchfork,clusterbomb)"),
@@ -301,16 +351,22 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.DurationVarP(&options.ResponseReadTimeout, "response-read-timeout", "rrt", time.Duration(5*time.Second), "response read timeout in seconds"),
 		flagSet.CallbackVar(resetCallback, "reset", "reset removes all nuclei configuration and data files (including nuclei-templates)"),
 		flagSet.BoolVarP(&options.TlsImpersonate, "tls-impersonate", "tlsi", false, "enable experimental client hello (ja3) tls randomization"),
+		flagSet.BoolVarP(&options.TLSFingerprint, "tls-fingerprint", "tlsfp", false, "expose ja3 of client hello sent and ja3s of server hello received by http requests"),
+		flagSet.StringVarP(&options.TLSClientHello, "tls-client-hello", "tlsch", "", "client hello profile sent by http requests (chrome,firefox,safari,edge,ios or ja3 string)"),
 		flagSet.StringVarP(&options.HttpApiEndpoint, "http-api-endpoint", "hae", "", "experimental http api endpoint"),
 	)
 
 	flagSet.CreateGroup("interactsh", "interactsh",
 		flagSet.StringVarP(&options.InteractshURL, "interactsh-server", "iserver", "", fmt.Sprintf("interactsh server url for self-hosted instance (default: %s)", client.DefaultOptions.ServerURL)),
 		flagSet.StringVarP(&options.InteractshToken, "interactsh-token", "itoken", "", "authentication token for self-hosted interactsh server"),
+		flagSet.StringVarP(&options.InteractshProxy, "interactsh-proxy", "iproxy", "", "http/socks5 proxy to use for interactsh registration and polling"),
 		flagSet.IntVar(&options.InteractionsCacheSize, "interactions-cache-size", 5000, "number of requests to keep in the interactions cache"),
 		flagSet.IntVar(&options.InteractionsEviction, "interactions-eviction", 60, "number of seconds to wait before evicting requests from cache"),
 		flagSet.IntVar(&options.InteractionsPollDuration, "interactions-poll-duration", 5, "number of seconds to wait before each interaction poll request"),
 		flagSet.IntVar(&options.InteractionsCoolDownPeriod, "interactions-cooldown-period", 5, "extra time for interaction polling before exiting"),
+		flagSet.IntVar(&options.InteractionsPollMaxDuration, "interactions-poll-max-duration", 0, "maximum number of seconds between interaction polls when backing off while idle (0 to disable)"),
+		flagSet.IntVar(&options.InteractionsBatchSize, "interactions-batch-size", 1, "number of correlation ids registered resetting backed off interaction polling"),
+		flagSet.IntVar(&options.InteractionsDrainTimeout, "interactions-drain-timeout", 0, "maximum number of seconds to wait for interactions of pending requests after cooldown"),
 		flagSet.BoolVarP(&options.NoInteractsh, "no-interactsh", "ni", false, "disable interactsh server for OAST testing, exclude OAST based templates"),
 	)
 
@@ -319,6 +375,19 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.FuzzingMode, "fuzzing-mode", "fm", "", "overrides fuzzing mode set in template (multiple, single)"),
 		flagSet.BoolVar(&fuzzFlag, "fuzz", false, "enable loading fuzzing templates (Deprecated: use -dast instead)"),
 		flagSet.BoolVar(&options.DAST, "dast", false, "enable / run dast (fuzz) nuclei templates"),
+		flagSet.BoolVarP(&options.SafeMode, "safe-mode", "sm", false, "skip fuzzing requests matching known-destructive payloads and methods (recommended)"),
+		flagSet.StringVarP(&options.SafeModeDenylist, "safe-mode-denylist", "smd", "", "file containing additional destructive patterns for safe mode (one per line, method:<name> to block methods)"),
+		flagSet.IntVarP(&options.FuzzReplayWindow, "fuzz-replay-window", "frw", 0, "number of preceding fuzzing requests per input to dump on a match (max 100)"),
+		flagSet.BoolVarP(&options.FuzzParamMining, "fuzz-param-mining", "fpm", false, "mine hidden parameters of all inputs for templates with param-mining (per input with param_mining input var)"),
+		flagSet.StringVarP(&options.FuzzSeenParams, "fuzz-seen-params", "fsp", "", "file storing fuzzed (template, host, parameter) tuples to only fuzz new parameters in later runs"),
+		flagSet.BoolVarP(&options.FuzzWAFDetect, "fuzz-waf-detect", "fwd", false, "detect waf blocking fuzzing payloads and report them as waf-detected info events"),
+		flagSet.IntVarP(&options.FuzzWAFThreshold, "fuzz-waf-threshold", "fwt", waf.DefaultThreshold, "number of blocked fuzzing responses per input before a waf is reported"),
+		flagSet.StringVarP(&options.FuzzDiffDir, "fuzz-diff-dir", "fdd", "", "directory to save baseline and matched response bodies with their diff for size/length matchers"),
+		flagSet.IntVarP(&options.FuzzDiffMax, "fuzz-diff-max", "fdm", bodydiff.DefaultMaxDiffs, "maximum number of body diffs to save"),
+		flagSet.IntVarP(&options.FuzzDiffMaxSize, "fuzz-diff-max-size", "fdms", bodydiff.DefaultMaxSize, "maximum size in bytes of bodies saved for diffs"),
+		flagSet.DurationVarP(&options.FuzzTarpitThreshold, "fuzz-tarpit-threshold", "ftt", 0, "median response latency of a host above which remaining fuzzing requests to it are skipped as tarpit (ex: 10s)"),
+		flagSet.IntVarP(&options.FuzzTarpitWindow, "fuzz-tarpit-window", "ftw", tarpit.DefaultWindow, "number of requests per host the median latency for tarpit detection is computed over"),
+		flagSet.BoolVarP(&options.FuzzConfirm, "fuzz-confirm", "fcm", false, "re-send matched fuzzing requests and report matches not reproduced as flaky info events"),
 	)
 
 	flagSet.CreateGroup("uncover", "Uncover",
@@ -341,6 +410,13 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVarP(&options.JsConcurrency, "js-concurrency", "jsc", 120, "maximum number of javascript runtimes to be executed in parallel"),
 		flagSet.IntVarP(&options.PayloadConcurrency, "payload-concurrency", "pc", 25, "max payload concurrency for each template"),
 		flagSet.IntVarP(&options.ProbeConcurrency, "probe-concurrency", "prc", 50, "http probe concurrency with httpx"),
+		flagSet.BoolVarP(&options.AutoTune, "auto-tune", "atn", false, "auto-tune concurrency and rate limit based on error rate and latency (values above are used as maxima)"),
+		flagSet.StringVarP(&options.RateLimitCoordinator, "rate-limit-coordinator", "rlc", "", "redis url of coordinator sharing per-host rate limit between nuclei processes (ex: redis://localhost:6379/0)"),
+		flagSet.IntVarP(&options.SharedRateLimit, "shared-rate-limit", "srl", 0, "maximum number of requests per rate-limit-duration to a host across processes (default rate-limit)"),
+		flagSet.IntVarP(&options.HostRateLimit, "host-rate-limit", "hrl", 0, "maximum number of requests per rate-limit-duration to a single host, spaced evenly to interleave hosts"),
+		flagSet.BoolVarP(&options.PriorityOrder, "priority-order", "po", false, "execute templates by decreasing priority sharing extracted values with later templates of the same input (reduces parallelism)"),
+		flagSet.StringSliceVarP(&options.SeverityPolicies, "severity-policy", "svp", nil, "rate and concurrency policy of templates of severities (ex: high,critical:rate=10,concurrency=2) (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.IntVarP(&options.TemplateConcurrency, "template-concurrency", "tpc", 0, "maximum number of in-flight requests of a single template across hosts and payloads (0 for no limit)"),
 	)
 	flagSet.CreateGroup("optimization", "Optimizations",
 		flagSet.IntVar(&options.Timeout, "timeout", 10, "time to wait in seconds before timeout"),
@@ -359,8 +435,14 @@ on extensive configurability, massive extensibility and ease of use.`)
 			scanstrategy.TemplateSpray.String(): goflags.EnumVariable(2),
 		}),
 		flagSet.DurationVarP(&options.InputReadTimeout, "input-read-timeout", "irt", time.Duration(3*time.Minute), "timeout on input read"),
+		flagSet.DurationVarP(&options.MaxDuration, "max-duration", "md", 0, "max wall-clock duration of the scan after which it is stopped gracefully (ex: 30m)"),
 		flagSet.BoolVarP(&options.DisableHTTPProbe, "no-httpx", "nh", false, "disable httpx probing for non-url input"),
 		flagSet.BoolVar(&options.DisableStdin, "no-stdin", false, "disable stdin processing"),
+		flagSet.BoolVarP(&options.StdinStream, "stdin-stream", "sis", false, "stream targets from stdin with bounded buffering instead of loading all (implies host-spray)"),
+		flagSet.BoolVarP(&options.RequestDedupe, "request-dedupe", "rdd", false, "skip duplicate requests of a template across inputs sharing the same host"),
+		flagSet.BoolVarP(&options.ResponseCache, "response-cache", "rsc", false, "cache responses of safe (GET/HEAD) non-fuzz requests shared by templates"),
+		flagSet.DurationVarP(&options.ResponseCacheTTL, "response-cache-ttl", "rsct", 5*time.Minute, "duration responses are cached for"),
+		flagSet.IntVarP(&options.ResponseCacheSize, "response-cache-size", "rscs", 1000, "maximum number of cached responses"),
 	)
 
 	flagSet.CreateGroup("headless", "Headless",
@@ -370,17 +452,30 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.HeadlessOptionalArguments, "headless-options", "ho", nil, "start headless chrome with additional options", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.UseInstalledChrome, "system-chrome", "sc", false, "use local installed Chrome browser instead of nuclei installed"),
 		flagSet.BoolVarP(&options.ShowActions, "list-headless-action", "lha", false, "list available headless actions"),
+		flagSet.BoolVarP(&options.ChallengeDetect, "challenge-detect", "chd", false, "detect and log anti-automation challenge pages (js challenges) in http responses"),
+		flagSet.BoolVarP(&options.ChallengeSolve, "challenge-solve", "chs", false, "pass detected challenges with the headless browser and reuse its cookies for http requests (requires -headless)"),
+		flagSet.StringSliceVarP(&options.ChallengePatterns, "challenge-pattern", "chp", nil, "custom regex of challenge pages detected in addition to built-in signatures (cli, file)", goflags.FileStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("debug", "Debug",
 		flagSet.BoolVar(&options.Debug, "debug", false, "show all requests and responses"),
 		flagSet.BoolVarP(&options.DebugRequests, "debug-req", "dreq", false, "show all sent requests"),
 		flagSet.BoolVarP(&options.DebugResponse, "debug-resp", "dresp", false, "show all received responses"),
+		flagSet.BoolVarP(&options.RequestDump, "request-dump", "rdump", false, "print all http requests templates would send without sending them (dry-run, non-http templates are skipped)"),
+		flagSet.IntVarP(&options.RequestDumpLimit, "request-dump-limit", "rdl", 100, "maximum number of requests printed per template in request dump mode"),
 		flagSet.StringSliceVarP(&options.Proxy, "proxy", "p", nil, "list of http/socks5 proxy to use (comma separated or file input)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.ProxyInternal, "proxy-internal", "pi", false, "proxy all internal requests"),
+		flagSet.StringVarP(&options.ProxyRotation, "proxy-rotation", "prot", "", "rotate http requests through all proxies skipping dead ones (round-robin, random, sticky per host)"),
+		flagSet.StringVarP(&options.SSHJumpHost, "ssh-jump-host", "sjh", "", "ssh jump host ([user@]host[:port]) to tunnel http and network requests through"),
+		flagSet.StringVarP(&options.SSHJumpKey, "ssh-jump-key", "sjk", "", "private key file to authenticate to the ssh jump host"),
+		flagSet.StringVarP(&options.SSHJumpPassword, "ssh-jump-password", "sjp", "", "password to authenticate to the ssh jump host"),
+		flagSet.StringVarP(&options.SSHJumpKnownHosts, "ssh-jump-known-hosts", "sjkh", "", "known hosts file to verify the host key of the ssh jump host (default ~/.ssh/known_hosts)"),
+		flagSet.BoolVarP(&options.SSHJumpInsecure, "ssh-jump-insecure", "sji", false, "disable host key verification of the ssh jump host"),
 		flagSet.BoolVarP(&options.ListDslSignatures, "list-dsl-function", "ldf", false, "list all supported DSL function signatures"),
+		flagSet.StringVarP(&options.ListMatcherVariables, "list-matcher-vars", "lmv", "", "list variables available to matchers for a protocol (all for every protocol)"),
 		flagSet.StringVarP(&options.TraceLogFile, "trace-log", "tlog", "", "file to write sent requests trace log"),
 		flagSet.StringVarP(&options.ErrorLogFile, "error-log", "elog", "", "file to write sent requests error log"),
+		flagSet.StringVarP(&options.OtelEndpoint, "otel-endpoint", "otel", "", "opentelemetry otlp/http endpoint to export execution traces to (ex: http://localhost:4318)"),
 		flagSet.CallbackVar(printVersion, "version", "show nuclei version"),
 		flagSet.BoolVarP(&options.HangMonitor, "hang-monitor", "hm", false, "enable nuclei hang monitoring"),
 		flagSet.BoolVarP(&options.Verbose, "verbose", "v", false, "show verbose output"),
@@ -402,6 +497,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 	flagSet.CreateGroup("stats", "Statistics",
 		flagSet.BoolVar(&options.EnableProgressBar, "stats", false, "display statistics about the running scan"),
 		flagSet.BoolVarP(&options.StatsJSON, "stats-json", "sj", false, "display statistics in JSONL(ines) format"),
+		flagSet.BoolVar(&options.TUI, "tui", false, "display live terminal dashboard of the running scan"),
 		flagSet.IntVarP(&options.StatsInterval, "stats-interval", "si", 5, "number of seconds to wait between showing a statistics update"),
 		flagSet.IntVarP(&options.MetricsPort, "metrics-port", "mp", 9092, "port to expose nuclei metrics on"),
 	)
@@ -493,6 +589,21 @@ Additional documentation is available at: https://docs.nuclei.sh/getting-started
 			gologger.Fatal().Msgf("Could not read config: %s\n", err)
 		}
 	}
+	// profiles are applied after config files as they only set flags
+	// which were neither given on the command line nor set by config files
+	if profileFile != "" {
+		profilePath, err := runner.ResolveProfile(profileFile)
+		if err != nil {
+			gologger.Fatal().Msgf("Could not load profile: %s\n", err)
+		}
+		if err := runner.LoadProfile(flagSet, profilePath); err != nil {
+			gologger.Fatal().Msgf("Could not load profile: %s\n", err)
+		}
+		// profiles may enable fuzzing using the deprecated fuzz flag
+		if fuzzFlag {
+			options.DAST = true
+		}
+	}
 	if options.NewTemplatesDirectory != "" {
 		config.DefaultConfig.SetTemplatesDir(options.NewTemplatesDirectory)
 	}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// exitCodeRule sets the exit code of the process if at least count
+// findings of its severities were found
+type exitCodeRule struct {
+	severities severity.Severities
+	code       int
+	count      int
+}
+
+// parseExitCodes parses exit code rules in the format <severities>:code=<n>[,count=<n>]
+// where severities is a comma separated list of severities. ex: high,critical:code=2
+func parseExitCodes(values []string) ([]*exitCodeRule, error) {
+	var rules []*exitCodeRule
+	for _, value := range values {
+		names, settings, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(settings) == "" {
+			return nil, fmt.Errorf("invalid exit code %s: expected <severities>:code=<n>,count=<n>", value)
+		}
+		rule := &exitCodeRule{count: 1}
+		if err := rule.severities.Set(names); err != nil {
+			return nil, fmt.Errorf("invalid exit code %s: %w", value, err)
+		}
+		for _, setting := range strings.Split(settings, ",") {
+			key, number, _ := strings.Cut(strings.TrimSpace(setting), "=")
+			parsed, err := strconv.Atoi(strings.TrimSpace(number))
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid exit code %s: %s must be a positive number", value, key)
+			}
+			switch strings.ToLower(key) {
+			case "code":
+				if parsed > 255 {
+					return nil, fmt.Errorf("invalid exit code %s: code must be between 1 and 255", value)
+				}
+				rule.code = parsed
+			case "count":
+				rule.count = parsed
+			default:
+				return nil, fmt.Errorf("invalid exit code %s: unknown setting %s", value, key)
+			}
+		}
+		if rule.code == 0 {
+			return nil, fmt.Errorf("invalid exit code %s: code is required", value)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// findingsWriter is an output writer counting the findings of the scan
+// by severity to compute the exit code of the process
+type findingsWriter struct {
+	output.Writer
+	rules []*exitCodeRule
+
+	mutex  sync.Mutex
+	counts map[severity.Severity]int
+}
+
+// newFindingsWriter wraps the writer to count findings for the exit code rules
+func newFindingsWriter(writer output.Writer, rules []*exitCodeRule) *findingsWriter {
+	return &findingsWriter{Writer: writer, rules: rules, counts: make(map[severity.Severity]int)}
+}
+
+// Write counts the finding and writes it to the wrapped writer. Partial
+// match events are not findings and are not counted.
+func (w *findingsWriter) Write(event *output.ResultEvent) error {
+	if !event.Partial {
+		w.mutex.Lock()
+		w.counts[event.Info.SeverityHolder.Severity]++
+		w.mutex.Unlock()
+	}
+	return w.Writer.Write(event)
+}
+
+// ExitCode returns the exit code of the rule whose threshold is reached by
+// findings of the highest severity, the first such rule if multiple rules
+// cover it, or 0 if no threshold is reached.
+func (w *findingsWriter) ExitCode() int {
+	if w == nil {
+		return 0
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	code, highest := 0, -1
+	for _, rule := range w.rules {
+		var count int
+		ruleHighest := -1
+		for _, sev := range rule.severities {
+			if found := w.counts[sev]; found > 0 {
+				count += found
+				ruleHighest = max(ruleHighest, severityRank(sev))
+			}
+		}
+		if count >= rule.count && ruleHighest > highest {
+			code, highest = rule.code, ruleHighest
+		}
+	}
+	return code
+}
+
+// severityRank returns the rank of the severity, unknown severities
+// ranking below info
+func severityRank(sev severity.Severity) int {
+	if sev == severity.Unknown {
+		return int(severity.Undefined)
+	}
+	return int(sev)
+}
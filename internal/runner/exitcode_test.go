@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+func TestExitCode(t *testing.T) {
+	_, err := parseExitCodes([]string{"high"})
+	require.NotNil(t, err, "parsed exit code without settings")
+	_, err = parseExitCodes([]string{"high:count=2"})
+	require.NotNil(t, err, "parsed exit code without code")
+	_, err = parseExitCodes([]string{"high:code=256"})
+	require.NotNil(t, err, "parsed out of range exit code")
+
+	rules, err := parseExitCodes([]string{"medium:code=1,count=3", "high,critical:code=2"})
+	require.Nil(t, err, "could not parse exit codes")
+
+	writer := newFindingsWriter(testutils.NewMockOutputWriter(false), rules)
+	write := func(sev severity.Severity) {
+		require.Nil(t, writer.Write(&output.ResultEvent{Info: model.Info{SeverityHolder: severity.Holder{Severity: sev}}}))
+	}
+	write(severity.Info)
+	write(severity.Medium)
+	write(severity.Medium)
+	require.Nil(t, writer.Write(&output.ResultEvent{Partial: true, Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Medium}}}))
+	require.Equal(t, 0, writer.ExitCode(), "exit code below count threshold")
+
+	write(severity.Medium)
+	require.Equal(t, 1, writer.ExitCode(), "could not get exit code of count threshold")
+
+	write(severity.Critical)
+	require.Equal(t, 2, writer.ExitCode(), "could not get exit code of highest severity")
+
+	var nilWriter *findingsWriter
+	require.Equal(t, 0, nilWriter.ExitCode(), "nil writer returned exit code")
+}
@@ -17,15 +17,25 @@ import (
 	"github.com/projectdiscovery/gologger/formatter"
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output/drift"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/severitypolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/sshtunnel"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/tlsfingerprint"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/bundle"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/jsonexporter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/jsonl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sarif"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sqlite"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/webhook"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/scanstrategy"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
 	fileutil "github.com/projectdiscovery/utils/file"
 	"github.com/projectdiscovery/utils/generic"
@@ -64,6 +74,12 @@ func ParseOptions(options *types.Options) {
 	// Show the user the banner
 	showBanner()
 
+	// Streamed stdin targets can only be iterated once
+	if options.StdinStream && options.Stdin && options.ScanStrategy != scanstrategy.HostSpray.String() {
+		gologger.Info().Msgf("Using host-spray scan strategy for streamed stdin targets\n")
+		options.ScanStrategy = scanstrategy.HostSpray.String()
+	}
+
 	if options.ShowVarDump {
 		vardump.EnableVarDump = true
 	}
@@ -107,6 +123,10 @@ func ParseOptions(options *types.Options) {
 	if options.OfflineHTTP {
 		options.DisableHTTPProbe = true
 	}
+	// inputs are not probed in request dump mode as no requests are sent
+	if options.RequestDump {
+		options.DisableHTTPProbe = true
+	}
 }
 
 // validateOptions validates the configuration options passed
@@ -130,12 +150,86 @@ func ValidateOptions(options *types.Options) error {
 		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc or -lha are set")
 	}
 
+	if options.ChallengeSolve && !options.Headless {
+		return errors.New("headless mode (-headless) is required to pass challenges (-challenge-solve)")
+	}
+
 	if options.FollowHostRedirects && options.FollowRedirects {
 		return errors.New("both follow host redirects and follow redirects specified")
 	}
 	if options.ShouldFollowHTTPRedirects() && options.DisableRedirects {
 		return errors.New("both follow redirects and disable redirects specified")
 	}
+	if err := shard.Validate(options.ShardIndex, options.ShardCount); err != nil {
+		return err
+	}
+	if _, err := severitypolicy.Parse(options.SeverityPolicies); err != nil {
+		return err
+	}
+	if _, err := parseExitCodes(options.ExitCodes); err != nil {
+		return err
+	}
+	if options.PartialMatchThreshold < 0 || options.PartialMatchThreshold >= 100 {
+		return errors.New("partial match threshold (-pm) must be between 0 and 99")
+	}
+	if options.DriftBaseline != "" {
+		if _, err := drift.ParseComponents(options.DriftFingerprint); err != nil {
+			return err
+		}
+		if options.DriftThreshold < 0 || options.DriftThreshold >= 100 {
+			return errors.New("drift threshold (-dft) must be between 0 and 99")
+		}
+	}
+	if options.OutputRotateSize < 0 || options.OutputRotateInterval < 0 || options.OutputFlushInterval < 0 {
+		return errors.New("output rotate size (-ors), rotate interval (-ori) and flush interval (-ofi) must not be negative")
+	}
+	if options.InteractshProxy != "" {
+		if err := types.ValidateProxyURL(options.InteractshProxy); err != nil {
+			return errors.Wrap(err, "invalid interactsh proxy (-iproxy)")
+		}
+	}
+	if options.SSHJumpHost != "" {
+		if _, _, err := sshtunnel.ParseJumpHost(options.SSHJumpHost); err != nil {
+			return err
+		}
+		if options.SSHJumpKey == "" && options.SSHJumpPassword == "" {
+			return errors.New("ssh jump host (-sjh) requires a private key (-sjk) or a password (-sjp)")
+		}
+		if options.SSHJumpKnownHosts != "" && options.SSHJumpInsecure {
+			return errors.New("ssh jump known hosts (-sjkh) and ssh jump insecure (-sji) cannot be used together")
+		}
+	} else if options.SSHJumpKey != "" || options.SSHJumpPassword != "" || options.SSHJumpKnownHosts != "" || options.SSHJumpInsecure {
+		return errors.New("ssh jump host options (-sjk, -sjp, -sjkh, -sji) require an ssh jump host (-sjh)")
+	}
+	if options.TLSClientHello != "" {
+		if options.TlsImpersonate {
+			return errors.New("tls client hello (-tlsch) and tls impersonate (-tlsi) cannot be used together")
+		}
+		if _, err := tlsfingerprint.ParseProfile(options.TLSClientHello); err != nil {
+			return err
+		}
+	}
+	if options.ResponseCache && (options.ResponseCacheSize <= 0 || options.ResponseCacheTTL <= 0) {
+		return errors.New("response cache size (-rscs) and ttl (-rsct) must be positive")
+	}
+	if options.DSLFunctions != "" && options.DSLFunctionTimeout <= 0 {
+		return errors.New("dsl function timeout (-dslft) must be positive")
+	}
+	// streamed stdin replaces the input provider combining the other sources
+	if options.StdinStream && (options.TargetsFilePath != "" || len(options.ExcludeTargets) > 0 || options.Uncover) {
+		return errors.New("stdin stream (-sis) cannot be used with target list (-l), exclude hosts (-eh) or uncover (-uc)")
+	}
+	if len(options.ReplayTargets) > 0 && (options.InputFileMode == "" || strings.EqualFold(options.InputFileMode, "list")) {
+		return errors.New("replay targets (-rpt) require an input file of captured requests (-im burp, jsonl, yaml etc)")
+	}
+	if options.ProxyRotation != "" {
+		if len(options.Proxy) == 0 {
+			return errors.New("proxy rotation (-prot) requires a list of proxies (-proxy)")
+		}
+		if !proxypool.IsValidStrategy(options.ProxyRotation) {
+			return fmt.Errorf("invalid proxy rotation (-prot) %s (round-robin, random, sticky)", options.ProxyRotation)
+		}
+	}
 	// loading the proxy server list from file or cli and test the connectivity
 	if err := loadProxyServers(options); err != nil {
 		return err
@@ -268,7 +362,7 @@ func createReportingOptions(options *types.Options) (*reporting.Options, error)
 		}
 	}
 	if options.SarifExport != "" {
-		reportingOptions.SarifExporter = &sarif.Options{File: options.SarifExport}
+		reportingOptions.SarifExporter = &sarif.Options{File: options.SarifExport, FlushInterval: options.OutputFlushInterval}
 	}
 	if options.JSONExport != "" {
 		reportingOptions.JSONExporter = &jsonexporter.Options{
@@ -278,9 +372,36 @@ func createReportingOptions(options *types.Options) (*reporting.Options, error)
 	}
 	if options.JSONLExport != "" {
 		reportingOptions.JSONLExporter = &jsonl.Options{
-			File:    options.JSONLExport,
-			OmitRaw: options.OmitRawRequests,
+			File:           options.JSONLExport,
+			OmitRaw:        options.OmitRawRequests,
+			RotateSize:     options.OutputRotateSize,
+			RotateInterval: options.OutputRotateInterval,
+			FlushInterval:  options.OutputFlushInterval,
+			Append:         options.Resume != "",
+		}
+	}
+
+	if options.SQLiteExport != "" {
+		reportingOptions.SQLiteExporter = &sqlite.Options{
+			File:          options.SQLiteExport,
+			OmitRaw:       options.OmitRawRequests,
+			FlushInterval: options.OutputFlushInterval,
+		}
+	}
+
+	if options.WebhookExport != "" {
+		// signing, batching and retries of the report-config are kept
+		if reportingOptions.WebhookExporter == nil {
+			reportingOptions.WebhookExporter = &webhook.Options{}
+		}
+		reportingOptions.WebhookExporter.URL = options.WebhookExport
+	}
+
+	if options.BundleExport != "" {
+		if reportingOptions.BundleExporter == nil {
+			reportingOptions.BundleExporter = &bundle.Options{}
 		}
+		reportingOptions.BundleExporter.Directory = options.BundleExport
 	}
 
 	reportingOptions.OmitRaw = options.OmitRawRequests
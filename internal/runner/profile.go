@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/goflags"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	fileutil "github.com/projectdiscovery/utils/file"
+	"gopkg.in/yaml.v2"
+)
+
+// profilesDirectory is the directory of nuclei-templates containing scan profiles
+const profilesDirectory = "profiles"
+
+// ResolveProfile returns the path of a scan profile which is either a
+// file path or the name of a profile in the profiles directory of templates
+func ResolveProfile(profile string) (string, error) {
+	if fileutil.FileExists(profile) {
+		return profile, nil
+	}
+	name := profile
+	if ext := filepath.Ext(name); ext != ".yaml" && ext != ".yml" {
+		name += ".yaml"
+	}
+	path := filepath.Join(config.DefaultConfig.GetTemplateDir(), profilesDirectory, name)
+	if fileutil.FileExists(path) {
+		return path, nil
+	}
+	return "", errors.Errorf("profile %s does not exist", profile)
+}
+
+// LoadProfile merges a scan profile with the parsed flags of flagSet.
+//
+// A profile is a yaml file of flag names (long or short) to values bundling
+// template selectors, rate limits, output and fuzzing options. Profiles are
+// applied beneath the config file: flags given on the command line or set by
+// the config file (which only sets flags having default values) take
+// precedence over profile values. Slice values of the profile replace the
+// default values instead of being appended to them.
+//
+// Unknown flags, flags specified more than once (ex: by long and short name)
+// and invalid values are reported as errors.
+func LoadProfile(flagSet *goflags.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read profile")
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return errors.Wrap(err, "could not parse profile")
+	}
+
+	// long and short names of a flag share the same value and are
+	// grouped together to detect options specified more than once
+	names := make(map[uintptr][]string)
+	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
+		names[profileFlagKey(fl)] = append(names[profileFlagKey(fl)], fl.Name)
+	})
+	setOnCLI := make(map[uintptr]struct{})
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		setOnCLI[profileFlagKey(fl)] = struct{}{}
+	})
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unknown, problems []string
+	seen := make(map[uintptr]string)
+	for _, key := range keys {
+		fl := flagSet.CommandLine.Lookup(key)
+		if fl == nil {
+			unknown = append(unknown, key)
+			continue
+		}
+		if previous, ok := seen[profileFlagKey(fl)]; ok {
+			problems = append(problems, fmt.Sprintf("%s is specified more than once (%s, %s)", profileFlagName(names[profileFlagKey(fl)]), previous, key))
+			continue
+		}
+		seen[profileFlagKey(fl)] = key
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown options %s", strings.Join(unknown, ", ")))
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("invalid profile %s: %s", path, strings.Join(problems, "; "))
+	}
+
+	for _, key := range keys {
+		fl := flagSet.CommandLine.Lookup(key)
+		name := profileFlagName(names[profileFlagKey(fl)])
+		if _, ok := setOnCLI[profileFlagKey(fl)]; ok {
+			gologger.Info().Msgf("profile: using -%s given on the command line instead of profile value", name)
+			continue
+		}
+		if !strings.EqualFold(fl.DefValue, fl.Value.String()) {
+			gologger.Info().Msgf("profile: using -%s from the config file instead of profile value", name)
+			continue
+		}
+		items, ok := values[key].([]interface{})
+		if !ok {
+			items = []interface{}{values[key]}
+		}
+		resetProfileFlag(fl)
+		for _, item := range items {
+			if err := fl.Value.Set(fmt.Sprint(item)); err != nil {
+				return errors.Errorf("invalid profile %s: invalid value %v for %s: %s", path, item, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// profileFlagKey returns the key identifying a flag by its value which
+// is shared by the long and short names of the flag
+func profileFlagKey(fl *flag.Flag) uintptr {
+	if value := reflect.ValueOf(fl.Value); value.Kind() == reflect.Pointer {
+		return value.Pointer()
+	}
+	return reflect.ValueOf(fl).Pointer()
+}
+
+// resetProfileFlag clears the default values of slice flags which are
+// appended to by each value set
+func resetProfileFlag(fl *flag.Flag) {
+	value := reflect.ValueOf(fl.Value)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Slice {
+		return
+	}
+	value.Elem().Set(reflect.Zero(value.Elem().Type()))
+}
+
+// profileFlagName returns the long name of a flag from its names
+func profileFlagName(names []string) string {
+	var long string
+	for _, name := range names {
+		if len(name) > len(long) {
+			long = name
+		}
+	}
+	return long
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/goflags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfile(t *testing.T) {
+	var excludeTypes goflags.StringSlice
+	newFlagSet := func(args ...string) (*goflags.FlagSet, *goflags.StringSlice, *int, *bool) {
+		flagSet := goflags.NewFlagSet()
+		var tags goflags.StringSlice
+		var rateLimit int
+		var dast bool
+		excludeTypes = nil
+		flagSet.StringSliceVar(&tags, "tags", nil, "tags to run", goflags.NormalizedStringSliceOptions)
+		flagSet.StringSliceVarP(&excludeTypes, "exclude-type", "ept", []string{"dns"}, "protocol types to exclude", goflags.NormalizedStringSliceOptions)
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "maximum number of requests to send per second")
+		flagSet.BoolVar(&dast, "dast", false, "enable fuzzing")
+		require.NoError(t, flagSet.CommandLine.Parse(args), "could not parse flags")
+		return flagSet, &tags, &rateLimit, &dast
+	}
+	writeProfile := func(content string) string {
+		path := filepath.Join(t.TempDir(), "profile.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "could not write profile")
+		return path
+	}
+
+	path := writeProfile("tags:\n  - sqli\n  - xss\nrl: 10\ndast: true\n")
+	flagSet, tags, rateLimit, dast := newFlagSet()
+	require.NoError(t, LoadProfile(flagSet, path), "could not load profile")
+	require.Equal(t, []string{"sqli", "xss"}, []string(*tags), "could not set slice from profile")
+	require.Equal(t, 10, *rateLimit, "could not set int from profile")
+	require.True(t, *dast, "could not set bool from profile")
+
+	t.Run("cli-wins", func(t *testing.T) {
+		flagSet, _, rateLimit, _ := newFlagSet("-rate-limit", "50")
+		require.NoError(t, LoadProfile(flagSet, path), "could not load profile")
+		require.Equal(t, 50, *rateLimit, "profile overrode cli value")
+	})
+	t.Run("config-wins", func(t *testing.T) {
+		flagSet, tags, rateLimit, _ := newFlagSet()
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("rate-limit: 20\n"), 0644), "could not write config")
+		require.NoError(t, flagSet.MergeConfigFile(configPath), "could not merge config")
+		require.NoError(t, LoadProfile(flagSet, path), "could not load profile")
+		require.Equal(t, 20, *rateLimit, "profile overrode config file value")
+		require.Equal(t, []string{"sqli", "xss"}, []string(*tags), "could not set slice from profile with config file")
+	})
+	t.Run("reset-slice", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		require.NoError(t, LoadProfile(flagSet, writeProfile("ept:\n  - http\n")), "could not load profile")
+		require.Equal(t, []string{"http"}, []string(excludeTypes), "profile slice appended to default value")
+	})
+	t.Run("unknown", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		err := LoadProfile(flagSet, writeProfile("rl: 10\nunknown-option: 1\n"))
+		require.ErrorContains(t, err, "unknown options unknown-option")
+	})
+	t.Run("conflict", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		err := LoadProfile(flagSet, writeProfile("rl: 10\nrate-limit: 20\n"))
+		require.ErrorContains(t, err, "rate-limit is specified more than once")
+	})
+	t.Run("invalid-value", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		err := LoadProfile(flagSet, writeProfile("rl: fast\n"))
+		require.ErrorContains(t, err, "invalid value fast for rate-limit")
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -39,6 +40,15 @@ func loadProxyServers(options *types.Options) error {
 			proxyList = append(proxyList, p)
 		}
 	}
+	// http requests are rotated through all proxies, other protocols use the first alive proxy
+	if options.ProxyRotation != "" {
+		pool, err := proxypool.New(proxyList, options.ProxyRotation)
+		if err != nil {
+			return err
+		}
+		proxypool.Default = pool
+		gologger.Verbose().Msgf("Rotating http requests through %d proxies (%s)", len(proxyList), options.ProxyRotation)
+	}
 	aliveProxy, err := proxyutils.GetAnyAliveProxy(options.Timeout, proxyList...)
 	if err != nil {
 		return err
@@ -16,6 +16,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/internal/pdcp"
 	"github.com/projectdiscovery/nuclei/v3/pkg/authprovider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/loader/parser"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan/events"
@@ -23,6 +24,7 @@ import (
 	pdcpauth "github.com/projectdiscovery/utils/auth/pdcp"
 	"github.com/projectdiscovery/utils/env"
 	fileutil "github.com/projectdiscovery/utils/file"
+	mapsutil "github.com/projectdiscovery/utils/maps"
 	permissionutil "github.com/projectdiscovery/utils/permission"
 	updateutils "github.com/projectdiscovery/utils/update"
 
@@ -39,17 +41,40 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
 	"github.com/projectdiscovery/nuclei/v3/pkg/core"
 	"github.com/projectdiscovery/nuclei/v3/pkg/external/customtemplates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/safemode"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/seenparams"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/tarpit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input"
 	parsers "github.com/projectdiscovery/nuclei/v3/pkg/loader/workflow"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output/annotations"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output/drift"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/projectfile"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/automaticscan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/autotune"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/basepath"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/challenge"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/cookiefile"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostsched"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/inputvars"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/pause"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdedupe"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/responsecache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/severitypolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/sharedlimit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/templateslots"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
@@ -57,9 +82,11 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/stats"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/tracing"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
 	"github.com/projectdiscovery/retryablehttp-go"
 	ptrutil "github.com/projectdiscovery/utils/ptr"
@@ -84,6 +111,8 @@ type Runner struct {
 	issuesClient     reporting.Client
 	browser          *engine.Browser
 	rateLimiter      *ratelimit.Limiter
+	sharedLimiter    *sharedlimit.Limiter
+	severityPolicies *severitypolicy.Policies
 	hostErrors       hosterrorscache.CacheInterface
 	resumeCfg        *types.ResumeCfg
 	pprofServer      *http.Server
@@ -93,6 +122,11 @@ type Runner struct {
 	tmpDir          string
 	parser          parser.Parser
 	httpApiEndpoint *httpapi.Server
+	tracingShutdown func(context.Context) error
+	seenParams      *seenparams.Store
+	annotations     *annotations.Annotator
+	findings        *findingsWriter
+	drift           *drift.Detector
 }
 
 const pprofServerAddress = "127.0.0.1:8086"
@@ -224,6 +258,15 @@ func New(options *types.Options) (*Runner, error) {
 		}()
 	}
 
+	if options.OtelEndpoint != "" {
+		shutdown, err := tracing.Init(context.Background(), options.OtelEndpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not initialize tracing")
+		}
+		gologger.Info().Msgf("Exporting execution traces to: %s", options.OtelEndpoint)
+		runner.tracingShutdown = shutdown
+	}
+
 	if options.HttpApiEndpoint != "" {
 		apiServer := httpapi.New(options.HttpApiEndpoint, options)
 		gologger.Info().Msgf("Listening api endpoint on: %s", options.HttpApiEndpoint)
@@ -251,6 +294,7 @@ func New(options *types.Options) (*Runner, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create output file")
 	}
+	runner.annotations = outputWriter.Annotations()
 	// setup a proxy writer to automatically upload results to PDCP
 	runner.output = runner.setupPDCPUpload(outputWriter)
 
@@ -263,10 +307,33 @@ func New(options *types.Options) (*Runner, error) {
 	// Creates the progress tracking object
 	var progressErr error
 	statsInterval := options.StatsInterval
-	runner.progress, progressErr = progress.NewStatsTicker(statsInterval, options.EnableProgressBar, options.StatsJSON, false, options.MetricsPort)
+	if options.TUI && !options.StatsJSON {
+		runner.progress, progressErr = progress.NewTUI(statsInterval, options.MetricsPort)
+	} else {
+		runner.progress, progressErr = progress.NewStatsTicker(statsInterval, options.EnableProgressBar, options.StatsJSON, false, options.MetricsPort)
+	}
 	if progressErr != nil {
 		return nil, progressErr
 	}
+	if tui, ok := runner.progress.(*progress.TUI); ok {
+		runner.output = newTUIWriter(runner.output, outputWriter, tui)
+	}
+	if options.DriftBaseline != "" {
+		detector, err := drift.New(options.DriftBaseline, options.DriftFingerprint, options.DriftThreshold)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create drift detector")
+		}
+		runner.drift = detector
+		runner.output = drift.NewWriter(runner.output, detector)
+	}
+	if len(options.ExitCodes) > 0 {
+		rules, err := parseExitCodes(options.ExitCodes)
+		if err != nil {
+			return nil, err
+		}
+		runner.findings = newFindingsWriter(runner.output, rules)
+		runner.output = runner.findings
+	}
 
 	// create project file if requested or load the existing one
 	if options.Project {
@@ -291,6 +358,12 @@ func New(options *types.Options) (*Runner, error) {
 		}
 		resumeCfg.Compile()
 	}
+	// resume indexes are relative to the targets of the shard
+	targetShard := shard.New(options.ShardIndex, options.ShardCount).String()
+	if runner.options.ShouldLoadResume() && resumeCfg.Shard != targetShard {
+		return nil, fmt.Errorf("resume file was saved with shard %q but current shard is %q", resumeCfg.Shard, targetShard)
+	}
+	resumeCfg.Shard = targetShard
 	runner.resumeCfg = resumeCfg
 
 	opts := interactsh.DefaultOptions(runner.output, runner.issuesClient, runner.progress)
@@ -300,10 +373,14 @@ func New(options *types.Options) (*Runner, error) {
 		opts.ServerURL = options.InteractshURL
 	}
 	opts.Authorization = options.InteractshToken
+	opts.Proxy = options.InteractshProxy
 	opts.CacheSize = options.InteractionsCacheSize
 	opts.Eviction = time.Duration(options.InteractionsEviction) * time.Second
 	opts.CooldownPeriod = time.Duration(options.InteractionsCoolDownPeriod) * time.Second
 	opts.PollDuration = time.Duration(options.InteractionsPollDuration) * time.Second
+	opts.MaxPollDuration = time.Duration(options.InteractionsPollMaxDuration) * time.Second
+	opts.BatchSize = options.InteractionsBatchSize
+	opts.DrainTimeout = time.Duration(options.InteractionsDrainTimeout) * time.Second
 	opts.NoInteractsh = runner.options.NoInteractsh
 	opts.StopAtFirstMatch = runner.options.StopAtFirstMatch
 	opts.Debug = runner.options.Debug
@@ -341,6 +418,16 @@ func New(options *types.Options) (*Runner, error) {
 	} else {
 		runner.rateLimiter = ratelimit.New(context.Background(), uint(options.RateLimit), options.RateLimitDuration)
 	}
+	sharedLimiter, err := sharedlimit.NewFromOptions(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create shared rate limiter")
+	}
+	runner.sharedLimiter = sharedLimiter
+	severityPolicies, err := severitypolicy.Parse(options.SeverityPolicies)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse severity policies")
+	}
+	runner.severityPolicies = severityPolicies
 
 	if tmpDir, err := os.MkdirTemp("", "nuclei-tmp-*"); err == nil {
 		runner.tmpDir = tmpDir
@@ -357,6 +444,12 @@ func (r *Runner) runStandardEnumeration(executerOpts protocols.ExecutorOptions,
 	return r.executeTemplatesInput(store, engine)
 }
 
+// ExitCode returns the exit code of the process for the findings of the
+// scan as configured by the exit code rules (0 if none matched)
+func (r *Runner) ExitCode() int {
+	return r.findings.ExitCode()
+}
+
 // Close releases all the resources and cleans up
 func (r *Runner) Close() {
 	if r.output != nil {
@@ -371,6 +464,7 @@ func (r *Runner) Close() {
 	if r.inputProvider != nil {
 		r.inputProvider.Close()
 	}
+	r.seenParams.Close()
 	protocolinit.Close()
 	if r.pprofServer != nil {
 		_ = r.pprofServer.Shutdown(context.Background())
@@ -378,6 +472,9 @@ func (r *Runner) Close() {
 	if r.rateLimiter != nil {
 		r.rateLimiter.Stop()
 	}
+	if r.sharedLimiter != nil {
+		_ = r.sharedLimiter.Close()
+	}
 	r.progress.Stop()
 	if r.browser != nil {
 		r.browser.Close()
@@ -385,6 +482,11 @@ func (r *Runner) Close() {
 	if r.tmpDir != "" {
 		_ = os.RemoveAll(r.tmpDir)
 	}
+	if r.tracingShutdown != nil {
+		if err := r.tracingShutdown(context.Background()); err != nil {
+			gologger.Warning().Msgf("Could not flush execution traces: %s\n", err)
+		}
+	}
 }
 
 // setupPDCPUpload sets up the PDCP upload writer
@@ -490,6 +592,116 @@ func (r *Runner) RunEnumeration() error {
 		executorOpts.HostErrorsCache = cache
 	}
 
+	if r.options.SafeMode {
+		filter, err := safemode.New(r.options.SafeModeDenylist)
+		if err != nil {
+			return errors.Wrap(err, "could not create safe mode filter")
+		}
+		executorOpts.SafeModeFilter = filter
+	}
+	if r.options.FuzzSeenParams != "" {
+		store, err := seenparams.New(r.options.FuzzSeenParams)
+		if err != nil {
+			return errors.Wrap(err, "could not create seen parameters store")
+		}
+		r.seenParams = store
+		executorOpts.SeenParams = store
+	}
+	if r.options.RequestDedupe {
+		executorOpts.RequestDedupe = requestdedupe.New()
+	}
+	if r.options.ResponseCache {
+		executorOpts.ResponseCache = responsecache.New(r.options.ResponseCacheSize, r.options.ResponseCacheTTL)
+	}
+	if r.options.ChallengeDetect || r.options.ChallengeSolve || len(r.options.ChallengePatterns) > 0 {
+		handler, err := challenge.New(r.options.ChallengePatterns)
+		if err != nil {
+			return errors.Wrap(err, "could not create challenge handler")
+		}
+		executorOpts.Challenges = handler
+	}
+	if r.options.RequestDump {
+		executorOpts.RequestDump = requestdump.New(r.options.RequestDumpLimit)
+	}
+	if r.options.InputVarsFile != "" {
+		store, err := inputvars.New(r.options.InputVarsFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load input vars")
+		}
+		executorOpts.InputVars = store
+	}
+	if r.options.PriorityOrder {
+		executorOpts.ScanValues = scanvalues.New()
+	}
+	if len(r.options.BasePathMap) > 0 {
+		paths, err := basepath.New(r.options.BasePathMap)
+		if err != nil {
+			return errors.Wrap(err, "could not load base path map")
+		}
+		executorOpts.BasePaths = paths
+	}
+	if r.options.CookieFile != "" {
+		jar, err := cookiefile.New(r.options.CookieFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load cookie file")
+		}
+		executorOpts.CookieFile = jar
+	}
+	if r.options.FuzzDiffDir != "" {
+		writer, err := bodydiff.New(r.options.FuzzDiffDir, r.options.FuzzDiffMax, r.options.FuzzDiffMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "could not create body diff writer")
+		}
+		executorOpts.BodyDiff = writer
+	}
+	if len(r.options.TrustStore) > 0 {
+		store, err := signer.NewTrustStore(r.options.TrustStore, r.options.TrustStorePolicy)
+		if err != nil {
+			return errors.Wrap(err, "could not load trust store")
+		}
+		executorOpts.TrustStore = store
+	}
+	if r.severityPolicies != nil {
+		executorOpts.SeverityPolicies = r.severityPolicies
+	}
+	if r.sharedLimiter != nil {
+		executorOpts.SharedLimiter = r.sharedLimiter
+	}
+	// extended statistics are only displayed by progress drivers supporting them
+	progressStats, hasStats := r.progress.(progress.Extension)
+	if scheduler := hostsched.NewFromOptions(r.options); scheduler != nil {
+		executorOpts.HostScheduler = scheduler
+		if hasStats {
+			progressStats.SetHostQueues(scheduler.QueueDepths)
+		}
+	}
+	if limiter := templateslots.NewFromOptions(r.options); limiter != nil {
+		executorOpts.TemplateSlots = limiter
+		if hasStats {
+			progressStats.SetTemplateInFlight(limiter.InFlight)
+		}
+	}
+	if proxypool.Default != nil && hasStats {
+		progressStats.SetProxyHealth(proxypool.Default.Health)
+	}
+	// SIGUSR1 pauses and SIGUSR2 resumes request dispatch
+	executorOpts.Pauser = pause.New()
+	defer executorOpts.Pauser.ListenSignals()()
+	if r.options.FuzzTarpitThreshold > 0 {
+		executorOpts.Tarpit = tarpit.New(r.options.FuzzTarpitThreshold, r.options.FuzzTarpitWindow, func(host string) {
+			if hasStats {
+				progressStats.IncrementTarpitHosts()
+			}
+		})
+	}
+	if r.options.AutoTune {
+		executorOpts.AutoTuner = autotune.New(r.options, func(values autotune.Values) {
+			if hasStats {
+				progressStats.SetTunedValues(values.Map())
+			}
+		})
+	}
+
 	executorEngine := core.New(r.options)
 	executorEngine.SetExecuterOptions(executorOpts)
 
@@ -522,6 +734,11 @@ func (r *Runner) RunEnumeration() error {
 		return nil // exit
 	}
 	store.Load()
+	defer store.Close()
+	if err := r.reportTrustFailures(executorOpts.TrustStore); err != nil {
+		return err
+	}
+	r.skipUnknownAnnotations(store)
 	// TODO: remove below functions after v3 or update warning messages
 	disk.PrintDeprecatedPathsMsgIfApplicable(r.options.Silent)
 	templates.PrintDeprecatedProtocolNameMsgIfApplicable(r.options.Silent, r.options.Verbose)
@@ -560,7 +777,8 @@ func (r *Runner) RunEnumeration() error {
 	// If not explicitly disabled, check if http based protocols
 	// are used, and if inputs are non-http to pre-perform probing
 	// of urls and storing them for execution.
-	if !r.options.DisableHTTPProbe && loader.IsHTTPBasedProtocolUsed(store) && r.isInputNonHTTP() {
+	// Streamed inputs are skipped as probing would consume the stream.
+	if !r.options.DisableHTTPProbe && !(r.options.StdinStream && r.options.Stdin) && loader.IsHTTPBasedProtocolUsed(store) && r.isInputNonHTTP() {
 		inputHelpers, err := r.initializeTemplatesHTTPInput()
 		if err != nil {
 			return errors.Wrap(err, "could not probe http input")
@@ -584,8 +802,12 @@ func (r *Runner) RunEnumeration() error {
 
 	enumeration := false
 	var results *atomic.Bool
+	executorOpts.AutoTuner.Start()
 	results, err = r.runStandardEnumeration(executorOpts, store, executorEngine)
 	enumeration = true
+	if executorOpts.AutoTuner != nil {
+		gologger.Info().Msgf("Auto-tuned concurrency values (pin with flags): %s", executorOpts.AutoTuner.Stop())
+	}
 
 	if !enumeration {
 		return err
@@ -597,9 +819,31 @@ func (r *Runner) RunEnumeration() error {
 			results.CompareAndSwap(false, true)
 		}
 	}
+	if r.writeDrifts() {
+		results.CompareAndSwap(false, true)
+	}
 	if executorOpts.InputHelper != nil {
 		_ = executorOpts.InputHelper.Close()
 	}
+	if total, hidden := executorOpts.RequestDump.Summary(); total > 0 {
+		counts := executorOpts.RequestDump.Counts()
+		for _, templateID := range mapsutil.GetSortedKeys(counts) {
+			gologger.Info().Msgf("[%s] Built %d requests", templateID, counts[templateID])
+		}
+		gologger.Info().Msgf("Built %d requests without sending them (%d not printed due to -request-dump-limit)", total, hidden)
+	}
+	if skipped := executorOpts.RequestDedupe.Skipped(); skipped > 0 {
+		gologger.Info().Msgf("Skipped %d duplicate requests across inputs", skipped)
+	}
+	if hits, misses := executorOpts.ResponseCache.Stats(); hits+misses > 0 {
+		gologger.Info().Msgf("Served %d of %d cacheable requests from response cache (%.1f%% hit rate)", hits, hits+misses, float64(hits)*100/float64(hits+misses))
+	}
+	if hosts := executorOpts.Tarpit.Hosts(); len(hosts) > 0 {
+		gologger.Info().Msgf("Skipped fuzzing of %d tarpit suspected hosts: %s", len(hosts), strings.Join(hosts, ", "))
+	}
+	if detected, passed, failed := executorOpts.Challenges.Stats(); detected > 0 {
+		gologger.Info().Msgf("Detected anti-automation challenges for %d hosts (%d passed, %d not passed)", detected, passed, failed)
+	}
 
 	// todo: error propagation without canonical straight error check is required by cloud?
 	// use safe dereferencing to avoid potential panics in case of previous unchecked errors
@@ -669,10 +913,108 @@ func (r *Runner) executeTemplatesInput(store *loader.Store, engine *core.Engine)
 	if r.inputProvider == nil {
 		return nil, errors.New("no input provider found")
 	}
-	results := engine.ExecuteScanWithOpts(context.Background(), finalTemplates, r.inputProvider, r.options.DisableClustering)
+	ctx, cancel := r.scanContext()
+	defer cancel()
+	if tui, ok := r.progress.(*progress.TUI); ok {
+		var fuzzingTemplates []string
+		for _, template := range finalTemplates {
+			if template.IsFuzzing() {
+				fuzzingTemplates = append(fuzzingTemplates, template.Path)
+			}
+		}
+		tui.SetFuzzingTemplates(fuzzingTemplates)
+	}
+	results := engine.ExecuteScanWithOpts(ctx, finalTemplates, r.inputProvider, r.options.DisableClustering)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		r.logMaxDurationSummary()
+	}
 	return results, nil
 }
 
+// scanContext returns the context of the scan which expires once the max
+// scan duration (if any) is reached
+func (r *Runner) scanContext() (context.Context, context.CancelFunc) {
+	if r.options.MaxDuration <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.options.MaxDuration)
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			gologger.Info().Msgf("Max scan duration of %s reached, waiting for in-flight requests to finish", r.options.MaxDuration)
+		}
+	})
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// reportTrustFailures logs templates which failed trust store verification
+// and returns an error if the trust store policy requires failing the scan
+func (r *Runner) reportTrustFailures(trustStore *signer.TrustStore) error {
+	if trustStore == nil {
+		return nil
+	}
+	failures := trustStore.Failures()
+	for _, failure := range failures {
+		gologger.Print().Msgf("[%v] Template %s failed trust store verification: %s", r.colorizer.BrightYellow("WRN"), failure.Path, failure.Reason)
+	}
+	return trustStore.Err()
+}
+
+// skipUnknownAnnotations skips annotations of template ids which are not
+// loaded for the scan as they can't be applied to any result
+func (r *Runner) skipUnknownAnnotations(store *loader.Store) {
+	if r.annotations == nil {
+		return
+	}
+	var ids []string
+	for _, template := range store.Templates() {
+		ids = append(ids, template.ID)
+	}
+	for _, workflow := range store.Workflows() {
+		ids = append(ids, workflow.ID)
+	}
+	for _, id := range r.annotations.SkipUnknown(ids) {
+		gologger.Warning().Msgf("Skipping annotations of unknown template %s", id)
+	}
+}
+
+// writeDrifts writes the fingerprint drift events of the scan as results so
+// that they are exported and reported like template results
+func (r *Runner) writeDrifts() bool {
+	if r.drift == nil {
+		return false
+	}
+	var matched bool
+	for _, drift := range r.drift.Drifts() {
+		event := &output.InternalWrappedEvent{
+			OperatorsResult: &operators.Result{Matched: true},
+			Results:         []*output.ResultEvent{drift.Event()},
+		}
+		if writer.WriteResult(event, r.output, r.progress, r.issuesClient) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// logMaxDurationSummary logs how much of the scan was completed before
+// it was stopped due to max duration
+func (r *Runner) logMaxDurationSummary() {
+	ticker, ok := r.progress.(*progress.StatsTicker)
+	if !ok {
+		gologger.Warning().Msgf("Scan stopped after max duration of %s", r.options.MaxDuration)
+		return
+	}
+	requests, total := ticker.RequestStats()
+	var percentage float64
+	if total > 0 {
+		percentage = float64(requests) * 100 / float64(total)
+	}
+	gologger.Warning().Msgf("Scan stopped after max duration of %s: %d/%d requests completed (%.2f%%)", r.options.MaxDuration, requests, total, percentage)
+}
+
 // displayExecutionInfo displays misc info about the nuclei engine execution
 func (r *Runner) displayExecutionInfo(store *loader.Store) {
 	// Display stats for any loaded templates' syntax warnings or errors
@@ -700,6 +1042,7 @@ func (r *Runner) displayExecutionInfo(store *loader.Store) {
 	}
 	stats.DisplayAsWarning(httpProtocol.SetThreadToCountZero)
 	stats.ForceDisplayWarning(templates.SkippedUnsignedStats)
+	stats.ForceDisplayWarning(templates.SkippedUntrustedStats)
 	stats.ForceDisplayWarning(templates.SkippedRequestSignatureStats)
 
 	cfg := config.DefaultConfig
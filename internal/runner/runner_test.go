@@ -1,8 +1,10 @@
 package runner
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -142,3 +144,26 @@ func TestWalkReflectStructHandlesNestedStructs(t *testing.T) {
 	require.Equal(t, "2", testStruct.Struct.B)
 	require.Equal(t, "true", testStruct.Struct.C)
 }
+
+func TestScanContextMaxDuration(t *testing.T) {
+	r := &Runner{options: &types.Options{}}
+	ctx, cancel := r.scanContext()
+	_, ok := ctx.Deadline()
+	require.False(t, ok, "could not create scan context without deadline")
+	cancel()
+	require.ErrorIs(t, ctx.Err(), context.Canceled, "could not cancel scan context")
+
+	r = &Runner{options: &types.Options{MaxDuration: 50 * time.Millisecond}}
+	ctx, cancel = r.scanContext()
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok, "could not create scan context with deadline")
+	require.WithinDuration(t, time.Now().Add(50*time.Millisecond), deadline, 50*time.Millisecond, "could not set deadline to max duration")
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "scan context did not expire after max duration")
+	}
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded, "could not stop scan after max duration")
+}
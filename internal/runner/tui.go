@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+)
+
+// tuiRecorder records the results and requests displayed by the dashboard
+type tuiRecorder interface {
+	RecordResult(templateID, severity, matched string)
+	RecordRequest(templatePath, input string, err error)
+}
+
+// tuiWriter is an output writer reporting results and requests
+// to the live terminal dashboard
+type tuiWriter struct {
+	output.Writer
+	tui tuiRecorder
+}
+
+// newTUIWriter wraps the writer to report events to the dashboard. Results
+// printed on a terminal are printed above the dashboard.
+func newTUIWriter(writer output.Writer, standardWriter *output.StandardWriter, tui *progress.TUI) output.Writer {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		standardWriter.ScreenWriter = tui.ScreenWriter()
+	}
+	return &tuiWriter{Writer: writer, tui: tui}
+}
+
+// Write writes the event and records it in the recent matches
+func (w *tuiWriter) Write(event *output.ResultEvent) error {
+	// partial match events are not findings
+	if event.Partial {
+		return w.Writer.Write(event)
+	}
+	matched := event.Matched
+	if matched == "" {
+		matched = event.Host
+	}
+	w.tui.RecordResult(event.TemplateID, event.Info.SeverityHolder.Severity.String(), matched)
+	return w.Writer.Write(event)
+}
+
+// Request logs the request and records it for the dashboard
+func (w *tuiWriter) Request(templateID, url, requestType string, err error) {
+	w.tui.RecordRequest(templateID, url, err)
+	w.Writer.Request(templateID, url, requestType, err)
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+type mockTUIRecorder struct {
+	results  []string
+	requests []string
+}
+
+func (m *mockTUIRecorder) RecordResult(templateID, severity, matched string) {
+	m.results = append(m.results, templateID+" "+severity+" "+matched)
+}
+
+func (m *mockTUIRecorder) RecordRequest(templatePath, input string, err error) {
+	m.requests = append(m.requests, templatePath+" "+input+" "+errorString(err))
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func TestTUIWriter(t *testing.T) {
+	var written, requests int
+	mock := testutils.NewMockOutputWriter(false)
+	mock.WriteCallback = func(o *output.ResultEvent) { written++ }
+	mock.RequestCallback = func(templateID, url, requestType string, err error) { requests++ }
+	recorder := &mockTUIRecorder{}
+	writer := &tuiWriter{Writer: mock, tui: recorder}
+
+	info := model.Info{SeverityHolder: severity.Holder{Severity: severity.High}}
+	require.Nil(t, writer.Write(&output.ResultEvent{TemplateID: "matched", Info: info, Host: "example.com", Matched: "https://example.com/admin"}), "could not write result")
+	require.Nil(t, writer.Write(&output.ResultEvent{TemplateID: "host-only", Info: info, Host: "example.com"}), "could not write result without matched")
+	require.Nil(t, writer.Write(&output.ResultEvent{TemplateID: "partial", Info: info, Host: "example.com", Partial: true}), "could not write partial result")
+	require.Equal(t, 3, written, "could not write results to wrapped writer")
+	require.Equal(t, []string{
+		"matched high https://example.com/admin",
+		"host-only high example.com",
+	}, recorder.results, "could not record results on dashboard")
+
+	writer.Request("templates/a.yaml", "https://example.com", "http", nil)
+	writer.Request("templates/a.yaml", "https://example.com", "http", errors.New("timeout"))
+	require.Equal(t, 2, requests, "could not log requests to wrapped writer")
+	require.Equal(t, []string{
+		"templates/a.yaml https://example.com <nil>",
+		"templates/a.yaml https://example.com timeout",
+	}, recorder.requests, "could not record requests on dashboard")
+}
@@ -3,6 +3,7 @@ package nuclei
 import (
 	"context"
 	"errors"
+	"net"
 	"time"
 
 	"github.com/projectdiscovery/goflags"
@@ -173,6 +174,37 @@ func WithGlobalRateLimit(maxTokens int, duration time.Duration) NucleiSDKOptions
 	}
 }
 
+// WithSharedRateLimit shares a per-host rate limit of maxTokens per rate limit
+// duration with other processes using the redis coordinator at url. Local
+// rate limiting is used when the coordinator is unreachable.
+func WithSharedRateLimit(url string, maxTokens int) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.RateLimitCoordinator = url
+		e.opts.SharedRateLimit = maxTokens
+		return nil
+	}
+}
+
+// WithHostRateLimit limits requests to a single host to maxTokens per rate
+// limit duration spacing them evenly so that requests to different hosts
+// are interleaved instead of being sent to a host in bursts.
+func WithHostRateLimit(maxTokens int) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.HostRateLimit = maxTokens
+		return nil
+	}
+}
+
+// WithTemplateConcurrency limits the in-flight requests of a single template
+// across hosts and payloads so that an expensive template cannot starve the
+// other templates of the scan.
+func WithTemplateConcurrency(maxRequests int) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.TemplateConcurrency = maxRequests
+		return nil
+	}
+}
+
 // HeadlessOpts contains options for headless templates
 type HeadlessOpts struct {
 	PageTimeout     int // timeout for page load
@@ -292,6 +324,19 @@ func WithNetworkConfig(opts NetworkConfig) NucleiSDKOptions {
 	}
 }
 
+// WithCustomDialer allows dialing connections of http (including fuzzing) and
+// network requests using a custom dial function (ex: a userspace vpn or service
+// mesh sdk). Exclusions of the network policy are still enforced.
+func WithCustomDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode == threadSafe {
+			return ErrOptionsNotSupported.Msgf("WithCustomDialer")
+		}
+		e.opts.CustomDialer = dial
+		return nil
+	}
+}
+
 // WithProxy allows setting proxy options
 func WithProxy(proxy []string, proxyInternalRequests bool) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -312,6 +357,17 @@ func WithScanStrategy(strategy string) NucleiSDKOptions {
 	}
 }
 
+// EnablePriorityOrder executes templates in groups of decreasing priority so
+// that values extracted by templates of higher priority (ex: fingerprints) can
+// be referenced by later templates for the same input. Parallelism is reduced
+// since each group is executed before the next one starts.
+func EnablePriorityOrder() NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.PriorityOrder = true
+		return nil
+	}
+}
+
 // OutputWriter
 type OutputWriter output.Writer
 
@@ -358,6 +414,17 @@ func WithTemplateUpdateCallback(disableTemplatesAutoUpgrade bool, callback func(
 	}
 }
 
+// WithInputCompleteCallback allows setting a callback which will be called once per
+// target after all templates were executed for it (including templates which errored
+// or were skipped for the target) with the results found for the target.
+// Results of out-of-band interactions received after completion are not included.
+func WithInputCompleteCallback(callback func(input string, results []*output.ResultEvent)) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.onInputCompleteCallback = callback
+		return nil
+	}
+}
+
 // WithSandboxOptions allows setting supported sandbox options
 func WithSandboxOptions(allowLocalFileAccess bool, restrictLocalNetworkAccess bool) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -419,6 +486,53 @@ func DASTMode() NucleiSDKOptions {
 	}
 }
 
+// SafeMode skips fuzzing requests matching known-destructive patterns
+// with optional file containing additional denylist patterns
+func SafeMode(denylistFile string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.SafeMode = true
+		e.opts.SafeModeDenylist = denylistFile
+		return nil
+	}
+}
+
+// FuzzSeenParams skips fuzzing parameters recorded as fuzzed in previous
+// runs using the given file, recording newly fuzzed parameters to it
+func FuzzSeenParams(file string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.FuzzSeenParams = file
+		return nil
+	}
+}
+
+// RequestDedupe skips duplicate requests of a template across inputs sharing the same host
+func RequestDedupe() NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.RequestDedupe = true
+		return nil
+	}
+}
+
+// WithInputVars loads per-input variables keyed by target from a csv or json file
+// which take precedence over global variables for their input
+func WithInputVars(file string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.InputVarsFile = file
+		return nil
+	}
+}
+
+// WithCookieFile loads cookies from a netscape or json cookie file and applies
+// them to matching http requests. If update is true, cookies set by responses
+// update the loaded cookies for the duration of the scan.
+func WithCookieFile(file string, update bool) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.CookieFile = file
+		e.opts.CookieFileUpdate = update
+		return nil
+	}
+}
+
 // SignedTemplatesOnly only run signed templates and disabled loading all unsigned templates
 func SignedTemplatesOnly() NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -427,6 +541,18 @@ func SignedTemplatesOnly() NucleiSDKOptions {
 	}
 }
 
+// WithTrustStore verifies templates against the trusted certificates of the given
+// files or directories. Templates failing verification are handled as per the
+// policy (warn, reject or fail). With the fail policy, loading and executing
+// templates returns an error if any template failed verification.
+func WithTrustStore(paths []string, policy string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.TrustStore = paths
+		e.opts.TrustStorePolicy = policy
+		return nil
+	}
+}
+
 // WithCatalog uses a supplied catalog
 func WithCatalog(cat catalog.Catalog) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -40,6 +40,7 @@ func createEphemeralObjects(base *NucleiEngine, opts *types.Options) (*unsafeOpt
 		Colorizer:       aurora.NewAurora(true),
 		ResumeCfg:       types.NewResumeCfg(),
 		Parser:          base.parser,
+		TrustStore:      base.executerOpts.TrustStore,
 	}
 	if opts.RateLimitMinute > 0 {
 		opts.RateLimit = opts.RateLimitMinute
@@ -144,6 +145,11 @@ func (e *ThreadSafeNucleiEngine) ExecuteNucleiWithOpts(targets []string, opts ..
 		return errorutil.New("Could not create loader client: %s\n", err)
 	}
 	store.Load()
+	if unsafeOpts.executerOpts.TrustStore != nil {
+		if err := unsafeOpts.executerOpts.TrustStore.Err(); err != nil {
+			return err
+		}
+	}
 
 	inputProvider := provider.NewSimpleInputProviderWithUrls(targets...)
 
@@ -156,6 +162,7 @@ func (e *ThreadSafeNucleiEngine) ExecuteNucleiWithOpts(targets []string, opts ..
 
 	engine := core.New(tmpEngine.opts)
 	engine.SetExecuterOptions(unsafeOpts.executerOpts)
+	engine.OnInputComplete = inputCompleteFunc(tmpEngine.onInputCompleteCallback)
 
 	_ = engine.ExecuteScanWithOpts(context.Background(), store.Templates(), inputProvider, false)
 
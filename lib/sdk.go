@@ -59,6 +59,7 @@ type NucleiEngine struct {
 	disableTemplatesAutoUpgrade bool
 	enableStats                 bool
 	onUpdateAvailableCallback   func(newVersion string)
+	onInputCompleteCallback     func(input string, results []*output.ResultEvent)
 
 	// ready-status fields
 	templatesLoaded bool
@@ -100,6 +101,9 @@ func (e *NucleiEngine) LoadAllTemplates() error {
 		return errorutil.New("Could not create loader client: %s\n", err)
 	}
 	e.store.Load()
+	if e.executerOpts.TrustStore != nil {
+		return e.executerOpts.TrustStore.Err()
+	}
 	return nil
 }
 
@@ -155,6 +159,17 @@ func (e *NucleiEngine) GetExecuterOptions() *protocols.ExecutorOptions {
 	return &e.executerOpts
 }
 
+// Pause pauses request dispatch of the running scan. Requests in flight
+// complete while new requests wait until the scan is resumed.
+func (e *NucleiEngine) Pause() {
+	e.executerOpts.Pauser.Pause()
+}
+
+// Resume resumes request dispatch of a paused scan
+func (e *NucleiEngine) Resume() {
+	e.executerOpts.Pauser.Resume()
+}
+
 // ParseTemplate parses a template from given data
 // template verification status can be accessed from template.Verified
 func (e *NucleiEngine) ParseTemplate(data []byte) (*templates.Template, error) {
@@ -200,12 +215,19 @@ func (e *NucleiEngine) Close() {
 	if e.hostErrCache != nil {
 		e.hostErrCache.Close()
 	}
+	if e.store != nil {
+		e.store.Close()
+	}
+	e.executerOpts.SeenParams.Close()
 	if e.executerOpts.RateLimiter != nil {
 		e.executerOpts.RateLimiter.Stop()
 	}
 	if e.rateLimiter != nil {
 		e.rateLimiter.Stop()
 	}
+	if e.executerOpts.SharedLimiter != nil {
+		_ = e.executerOpts.SharedLimiter.Close()
+	}
 	// close global shared resources
 	protocolstate.Close()
 	if e.inputProvider != nil {
@@ -222,7 +244,9 @@ func (e *NucleiEngine) Close() {
 // ExecuteWithCallback executes templates on targets and calls callback on each result(only if results are found)
 func (e *NucleiEngine) ExecuteWithCallback(callback ...func(event *output.ResultEvent)) error {
 	if !e.templatesLoaded {
-		_ = e.LoadAllTemplates()
+		if err := e.LoadAllTemplates(); err != nil {
+			return err
+		}
 	}
 	if len(e.store.Templates()) == 0 && len(e.store.Workflows()) == 0 {
 		return ErrNoTemplatesAvailable
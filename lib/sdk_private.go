@@ -17,18 +17,34 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
 	"github.com/projectdiscovery/nuclei/v3/pkg/core"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/safemode"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/seenparams"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/tarpit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/basepath"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/cookiefile"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostsched"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/inputvars"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/pause"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdedupe"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/sharedlimit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/templateslots"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	nucleiUtils "github.com/projectdiscovery/nuclei/v3/pkg/utils"
@@ -169,6 +185,64 @@ func (e *NucleiEngine) init() error {
 		Browser:         e.browserInstance,
 		Parser:          e.parser,
 	}
+	if e.opts.SafeMode {
+		filter, err := safemode.New(e.opts.SafeModeDenylist)
+		if err != nil {
+			return errors.Wrap(err, "could not create safe mode filter")
+		}
+		e.executerOpts.SafeModeFilter = filter
+	}
+	if e.opts.FuzzSeenParams != "" {
+		store, err := seenparams.New(e.opts.FuzzSeenParams)
+		if err != nil {
+			return errors.Wrap(err, "could not create seen parameters store")
+		}
+		e.executerOpts.SeenParams = store
+	}
+	if e.opts.RequestDedupe {
+		e.executerOpts.RequestDedupe = requestdedupe.New()
+	}
+	if e.opts.RequestDump {
+		e.executerOpts.RequestDump = requestdump.New(e.opts.RequestDumpLimit)
+	}
+	if e.opts.InputVarsFile != "" {
+		store, err := inputvars.New(e.opts.InputVarsFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load input vars")
+		}
+		e.executerOpts.InputVars = store
+	}
+	if e.opts.PriorityOrder && e.executerOpts.ScanValues == nil {
+		e.executerOpts.ScanValues = scanvalues.New()
+	}
+	if len(e.opts.BasePathMap) > 0 {
+		paths, err := basepath.New(e.opts.BasePathMap)
+		if err != nil {
+			return errors.Wrap(err, "could not load base path map")
+		}
+		e.executerOpts.BasePaths = paths
+	}
+	if e.opts.CookieFile != "" {
+		jar, err := cookiefile.New(e.opts.CookieFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load cookie file")
+		}
+		e.executerOpts.CookieFile = jar
+	}
+	if e.opts.FuzzDiffDir != "" {
+		writer, err := bodydiff.New(e.opts.FuzzDiffDir, e.opts.FuzzDiffMax, e.opts.FuzzDiffMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "could not create body diff writer")
+		}
+		e.executerOpts.BodyDiff = writer
+	}
+	if len(e.opts.TrustStore) > 0 {
+		store, err := signer.NewTrustStore(e.opts.TrustStore, e.opts.TrustStorePolicy)
+		if err != nil {
+			return errors.Wrap(err, "could not load trust store")
+		}
+		e.executerOpts.TrustStore = store
+	}
 	if len(e.opts.SecretsFile) > 0 {
 		authTmplStore, err := runner.GetAuthTmplStore(*e.opts, e.catalog, e.executerOpts)
 		if err != nil {
@@ -211,9 +285,29 @@ func (e *NucleiEngine) init() error {
 			e.executerOpts.RateLimiter = ratelimit.New(context.Background(), uint(e.opts.RateLimit), e.opts.RateLimitDuration)
 		}
 	}
+	if e.opts.FuzzTarpitThreshold > 0 && e.executerOpts.Tarpit == nil {
+		e.executerOpts.Tarpit = tarpit.New(e.opts.FuzzTarpitThreshold, e.opts.FuzzTarpitWindow, nil)
+	}
+	if e.executerOpts.Pauser == nil {
+		e.executerOpts.Pauser = pause.New()
+	}
+	if e.executerOpts.HostScheduler == nil {
+		e.executerOpts.HostScheduler = hostsched.NewFromOptions(e.opts)
+	}
+	if e.executerOpts.TemplateSlots == nil {
+		e.executerOpts.TemplateSlots = templateslots.NewFromOptions(e.opts)
+	}
+	if e.executerOpts.SharedLimiter == nil {
+		sharedLimiter, err := sharedlimit.NewFromOptions(e.opts)
+		if err != nil {
+			return errors.Wrap(err, "could not create shared rate limiter")
+		}
+		e.executerOpts.SharedLimiter = sharedLimiter
+	}
 
 	e.engine = core.New(e.opts)
 	e.engine.SetExecuterOptions(e.executerOpts)
+	e.engine.OnInputComplete = inputCompleteFunc(e.onInputCompleteCallback)
 
 	httpxOptions := httpx.DefaultOptions
 	httpxOptions.Timeout = 5 * time.Second
@@ -249,3 +343,13 @@ func (e *NucleiEngine) processUpdateCheckResults() error {
 	})
 	return err
 }
+
+// inputCompleteFunc adapts the sdk input complete callback to the engine one
+func inputCompleteFunc(callback func(input string, results []*output.ResultEvent)) core.InputCompleteFunc {
+	if callback == nil {
+		return nil
+	}
+	return func(input *contextargs.MetaInput, results []*output.ResultEvent) {
+		callback(input.Input, results)
+	}
+}
@@ -0,0 +1,38 @@
+package authx
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+var (
+	_ AuthStrategy = &DigestAuthStrategy{}
+)
+
+// DigestAuthStrategy is a strategy for digest auth
+type DigestAuthStrategy struct {
+	Data *Secret
+}
+
+// NewDigestAuthStrategy creates a new digest auth strategy
+func NewDigestAuthStrategy(data *Secret) *DigestAuthStrategy {
+	return &DigestAuthStrategy{Data: data}
+}
+
+// Apply applies the digest auth strategy to the request
+//
+// digest auth requires a challenge-response handshake which is only
+// supported for retryable requests, so this is a no-op.
+func (s *DigestAuthStrategy) Apply(req *http.Request) {}
+
+// ApplyOnRR applies the digest auth strategy to the retryable request.
+// The client answers the 401 digest challenge (including qop and
+// nonce-count) and retries the request.
+func (s *DigestAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
+	req.Auth = &retryablehttp.Auth{
+		Type:     retryablehttp.DigestAuth,
+		Username: s.Data.Username,
+		Password: s.Data.Password,
+	}
+}
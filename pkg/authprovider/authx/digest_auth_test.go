@@ -0,0 +1,82 @@
+package authx
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+// digestHash returns the hex encoded md5 hash of the values joined by colons
+func digestHash(values ...string) string {
+	hash := md5.Sum([]byte(strings.Join(values, ":")))
+	return hex.EncodeToString(hash[:])
+}
+
+// digestParams parses the parameters of a digest authorization header
+func digestParams(header string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(header, "Digest "), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[key] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
+
+func TestDigestAuthStrategy(t *testing.T) {
+	const realm, nonce = "nuclei", "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+
+	var challenges int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := digestParams(r.Header.Get("Authorization"))
+		if params["username"] == "" {
+			challenges++
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="%s", nonce="%s", qop="auth", algorithm=MD5`, realm, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		expected := digestHash(
+			digestHash(params["username"], realm, "secret"),
+			params["nonce"], params["nc"], params["cnonce"], params["qop"],
+			digestHash(r.Method, params["uri"]),
+		)
+		if params["username"] != "admin" || params["response"] != expected {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("authenticated"))
+	}))
+	defer ts.Close()
+
+	secret := &Secret{Type: string(DigestAuth), Domains: []string{"127.0.0.1"}, Username: "admin", Password: "secret"}
+	require.Nil(t, secret.Validate(), "could not validate digest auth secret")
+	strategy, ok := secret.GetStrategy().(*DigestAuthStrategy)
+	require.True(t, ok, "could not get digest auth strategy")
+
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	send := func(strategy AuthStrategy) int {
+		req, err := retryablehttp.NewRequest(http.MethodGet, ts.URL+"/protected?id=1", nil)
+		require.Nil(t, err, "could not create request")
+		strategy.ApplyOnRR(req)
+		resp, err := client.Do(req)
+		require.Nil(t, err, "could not send request")
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, send(strategy), "could not authenticate with digest auth")
+	require.Equal(t, 1, challenges, "could not answer digest challenge")
+
+	invalid := &Secret{Type: string(DigestAuth), Username: "admin", Password: "invalid"}
+	require.Equal(t, http.StatusForbidden, send(invalid.GetStrategy()), "could authenticate with invalid password")
+
+	require.NotNil(t, (&Secret{Type: string(DigestAuth), Username: "admin"}).Validate(), "could validate digest auth without password")
+}
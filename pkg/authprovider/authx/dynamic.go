@@ -3,8 +3,10 @@ package authx
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/replacer"
@@ -13,6 +15,10 @@ import (
 
 type LazyFetchSecret func(d *Dynamic) error
 
+// minReauthInterval is the minimum interval between warmups of a per host
+// dynamic secret so that concurrent unauthorized responses re-run it once
+const minReauthInterval = 10 * time.Second
+
 var (
 	_ json.Unmarshaler = &Dynamic{}
 )
@@ -22,15 +28,26 @@ var (
 // ex: username and password are dynamic secrets, the actual secret is the token obtained
 // after authenticating with the username and password
 type Dynamic struct {
-	Secret        `yaml:",inline"`       // this is a static secret that will be generated after the dynamic secret is resolved
-	TemplatePath  string                 `json:"template" yaml:"template"`
-	Variables     []KV                   `json:"variables" yaml:"variables"`
-	Input         string                 `json:"input" yaml:"input"` // (optional) target for the dynamic secret
-	Extracted     map[string]interface{} `json:"-" yaml:"-"`         // extracted values from the dynamic secret
-	fetchCallback LazyFetchSecret        `json:"-" yaml:"-"`
-	m             *sync.Mutex            `json:"-" yaml:"-"` // mutex for lazy fetch
-	fetched       bool                   `json:"-" yaml:"-"` // flag to check if the secret has been fetched
-	error         error                  `json:"-" yaml:"-"` // error if any
+	Secret          `yaml:",inline"`       // this is a static secret that will be generated after the dynamic secret is resolved
+	TemplatePath    string                 `json:"template" yaml:"template"`
+	Variables       []KV                   `json:"variables" yaml:"variables"`
+	Input           string                 `json:"input" yaml:"input"`                       // (optional) target for the dynamic secret
+	PerHost         bool                   `json:"per-host" yaml:"per-host"`                 // (optional) run the template once per host with the host as input
+	RefreshInterval string                 `json:"refresh-interval" yaml:"refresh-interval"` // (optional) re-run the per host template for secrets older than the interval (ex: 15m)
+	Extracted       map[string]interface{} `json:"-" yaml:"-"`                               // extracted values from the dynamic secret
+	fetchCallback   LazyFetchSecret        `json:"-" yaml:"-"`
+	m               *sync.Mutex            `json:"-" yaml:"-"` // mutex for lazy fetch
+	fetched         bool                   `json:"-" yaml:"-"` // flag to check if the secret has been fetched
+	error           error                  `json:"-" yaml:"-"` // error if any
+	fetchedAt       time.Time              `json:"-" yaml:"-"` // time the secret was last fetched
+	hosts           *hostSecrets           `json:"-" yaml:"-"` // per host copies of the secret (if per-host is enabled)
+	refresh         time.Duration          `json:"-" yaml:"-"` // parsed refresh interval of per host secrets
+}
+
+// hostSecrets contains the per host copies of a dynamic secret
+type hostSecrets struct {
+	sync.Mutex
+	items map[string]*Dynamic
 }
 
 func (d *Dynamic) UnmarshalJSON(data []byte) error {
@@ -54,6 +71,20 @@ func (d *Dynamic) Validate() error {
 	if len(d.Variables) == 0 {
 		return errorutil.New("variables are required for dynamic secret")
 	}
+	if d.PerHost && d.Input != "" {
+		return errorutil.New("input cannot be used with per-host dynamic secret")
+	}
+	if d.RefreshInterval != "" {
+		if !d.PerHost {
+			return errorutil.New("refresh-interval can only be used with per-host dynamic secret")
+		}
+		refresh, err := time.ParseDuration(d.RefreshInterval)
+		if err != nil || refresh <= 0 {
+			return errorutil.New("invalid refresh-interval %s for dynamic secret", d.RefreshInterval)
+		}
+		d.refresh = refresh
+	}
+	d.hosts = &hostSecrets{items: make(map[string]*Dynamic)}
 	d.skipCookieParse = true // skip cookie parsing in dynamic secrets during validation
 	if err := d.Secret.Validate(); err != nil {
 		return err
@@ -66,6 +97,7 @@ func (d *Dynamic) SetLazyFetchCallback(callback LazyFetchSecret) {
 	d.fetchCallback = func(d *Dynamic) error {
 		err := callback(d)
 		d.fetched = true
+		d.fetchedAt = time.Now()
 		if err != nil {
 			d.error = err
 			return err
@@ -146,9 +178,93 @@ func (d *Dynamic) GetStrategy() AuthStrategy {
 	return d.Secret.GetStrategy()
 }
 
+// GetStrategyForURL returns the auth strategy of the dynamic secret for the url.
+// For per-host secrets the template is run once for the host of the url and
+// concurrent callers for the same host wait for it to complete.
+func (d *Dynamic) GetStrategyForURL(u *url.URL) AuthStrategy {
+	if !d.PerHost || u == nil || d.hosts == nil {
+		return d.GetStrategy()
+	}
+	secret := d.forHost(u)
+	if err := secret.Fetch(false); err != nil {
+		return nil
+	}
+	if secret.Error() != nil {
+		return nil
+	}
+	return secret.Secret.GetStrategy()
+}
+
+// Reauthenticate drops the per host secret of the url so that the template
+// is run again by the next request of the host. Secrets fetched within the
+// last few seconds (or being fetched) are kept.
+func (d *Dynamic) Reauthenticate(u *url.URL) {
+	if !d.PerHost || u == nil || d.hosts == nil {
+		return
+	}
+	key := hostSecretKey(u)
+
+	d.hosts.Lock()
+	defer d.hosts.Unlock()
+	secret, ok := d.hosts.items[key]
+	if !ok || !secret.m.TryLock() {
+		return
+	}
+	defer secret.m.Unlock()
+	if secret.fetched && time.Since(secret.fetchedAt) >= minReauthInterval {
+		delete(d.hosts.items, key)
+	}
+}
+
+// forHost returns the copy of the dynamic secret for the host of the url.
+// Copies older than the refresh interval (if any) are replaced so that the
+// template is run again for the host.
+func (d *Dynamic) forHost(u *url.URL) *Dynamic {
+	key := hostSecretKey(u)
+
+	d.hosts.Lock()
+	defer d.hosts.Unlock()
+	if secret, ok := d.hosts.items[key]; ok && !d.expired(secret) {
+		return secret
+	}
+	secret := &Dynamic{
+		Secret:        d.Secret.clone(),
+		TemplatePath:  d.TemplatePath,
+		Variables:     d.Variables,
+		Input:         key,
+		fetchCallback: d.fetchCallback,
+		m:             &sync.Mutex{},
+	}
+	d.hosts.items[key] = secret
+	return secret
+}
+
+// expired returns true if the per host secret was fetched before the
+// refresh interval. Secrets being fetched are never expired.
+func (d *Dynamic) expired(secret *Dynamic) bool {
+	if d.refresh <= 0 || !secret.m.TryLock() {
+		return false
+	}
+	defer secret.m.Unlock()
+	return secret.fetched && time.Since(secret.fetchedAt) >= d.refresh
+}
+
+// hostSecretKey returns the key (and template input) of a per host secret
+func hostSecretKey(u *url.URL) string {
+	scheme := u.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return strings.ToLower(scheme + "://" + u.Host)
+}
+
 // Fetch fetches the dynamic secret
 // if isFatal is true, it will stop the execution if the secret could not be fetched
 func (d *Dynamic) Fetch(isFatal bool) error {
+	if d.PerHost && d.hosts != nil {
+		// per host secrets are fetched when first used for a host
+		return nil
+	}
 	d.m.Lock()
 	defer d.m.Unlock()
 	if d.fetched {
@@ -0,0 +1,95 @@
+package authx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicPerHost(t *testing.T) {
+	var fetches atomic.Int32
+	d := &Dynamic{
+		Secret:       Secret{Type: string(HeadersAuth), Domains: []string{"example.com"}, Headers: []KV{{Key: "Authorization", Value: "{{token}}"}}},
+		TemplatePath: "login.yaml",
+		Variables:    []KV{{Key: "username", Value: "admin"}},
+		PerHost:      true,
+	}
+	require.Nil(t, d.Validate(), "could not validate dynamic")
+	d.SetLazyFetchCallback(func(d *Dynamic) error {
+		fetches.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		d.Extracted = map[string]interface{}{"token": "token-" + d.Input}
+		return nil
+	})
+	strategy := &DynamicAuthStrategy{Dynamic: *d}
+
+	apply := func(target string) string {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		require.Nil(t, err, "could not create request")
+		strategy.Apply(req)
+		return req.Header.Get("Authorization")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.Equal(t, "token-https://example.com", apply("https://example.com/path"))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), fetches.Load(), "could not run warmup once for concurrent requests")
+
+	require.Equal(t, "token-http://other.example.com:8080", apply("http://other.example.com:8080/"))
+	require.Equal(t, int32(2), fetches.Load(), "could not run warmup per host")
+
+	target, _ := url.Parse("https://example.com/admin")
+	strategy.Reauthenticate(target)
+	apply("https://example.com/")
+	require.Equal(t, int32(2), fetches.Load(), "could not keep recently fetched secret")
+
+	strategy.Dynamic.hosts.items["https://example.com"].fetchedAt = time.Now().Add(-minReauthInterval)
+	strategy.Reauthenticate(target)
+	apply("https://example.com/")
+	require.Equal(t, int32(3), fetches.Load(), "could not re-run warmup after unauthorized response")
+}
+
+func TestDynamicRefreshInterval(t *testing.T) {
+	var fetches atomic.Int32
+	d := &Dynamic{
+		Secret:          Secret{Type: string(HeadersAuth), Domains: []string{"example.com"}, Headers: []KV{{Key: "Cookie", Value: "{{session_cookies}}"}}},
+		TemplatePath:    "headless-login.yaml",
+		Variables:       []KV{{Key: "username", Value: "admin"}},
+		PerHost:         true,
+		RefreshInterval: "15m",
+	}
+	require.Nil(t, d.Validate(), "could not validate dynamic")
+	d.SetLazyFetchCallback(func(d *Dynamic) error {
+		fetches.Add(1)
+		d.Extracted = map[string]interface{}{"session_cookies": fmt.Sprintf("sid=%d", fetches.Load())}
+		return nil
+	})
+	strategy := &DynamicAuthStrategy{Dynamic: *d}
+
+	apply := func() string {
+		req, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		require.Nil(t, err, "could not create request")
+		strategy.Apply(req)
+		return req.Header.Get("Cookie")
+	}
+	require.Equal(t, "sid=1", apply())
+	require.Equal(t, "sid=1", apply(), "could not reuse session within refresh interval")
+
+	strategy.Dynamic.hosts.items["https://example.com"].fetchedAt = time.Now().Add(-15 * time.Minute)
+	require.Equal(t, "sid=2", apply(), "could not refresh session after refresh interval")
+
+	invalid := &Dynamic{Secret: d.Secret.clone(), TemplatePath: "login.yaml", Variables: d.Variables, RefreshInterval: "15m"}
+	require.NotNil(t, invalid.Validate(), "could validate refresh interval without per-host")
+}
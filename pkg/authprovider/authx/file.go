@@ -18,6 +18,7 @@ type AuthType string
 
 const (
 	BasicAuth       AuthType = "BasicAuth"
+	DigestAuth      AuthType = "DigestAuth"
 	BearerTokenAuth AuthType = "BearerToken"
 	HeadersAuth     AuthType = "Header"
 	CookiesAuth     AuthType = "Cookie"
@@ -28,6 +29,7 @@ const (
 func SupportedAuthTypes() []string {
 	return []string{
 		string(BasicAuth),
+		string(DigestAuth),
 		string(BearerTokenAuth),
 		string(HeadersAuth),
 		string(CookiesAuth),
@@ -69,6 +71,8 @@ func (s *Secret) GetStrategy() AuthStrategy {
 	switch {
 	case strings.EqualFold(s.Type, string(BasicAuth)):
 		return NewBasicAuthStrategy(s)
+	case strings.EqualFold(s.Type, string(DigestAuth)):
+		return NewDigestAuthStrategy(s)
 	case strings.EqualFold(s.Type, string(BearerTokenAuth)):
 		return NewBearerTokenAuthStrategy(s)
 	case strings.EqualFold(s.Type, string(HeadersAuth)):
@@ -81,6 +85,15 @@ func (s *Secret) GetStrategy() AuthStrategy {
 	return nil
 }
 
+// clone returns a copy of the secret that can be evaluated independently
+func (s *Secret) clone() Secret {
+	cloned := *s
+	cloned.Headers = append([]KV(nil), s.Headers...)
+	cloned.Cookies = append([]Cookie(nil), s.Cookies...)
+	cloned.Params = append([]KV(nil), s.Params...)
+	return cloned
+}
+
 func (s *Secret) Validate() error {
 	if !stringsutil.EqualFoldAny(s.Type, SupportedAuthTypes()...) {
 		return fmt.Errorf("invalid type: %s", s.Type)
@@ -105,6 +118,13 @@ func (s *Secret) Validate() error {
 		if s.Password == "" {
 			return fmt.Errorf("password cannot be empty in basic auth")
 		}
+	case strings.EqualFold(s.Type, string(DigestAuth)):
+		if s.Username == "" {
+			return fmt.Errorf("username cannot be empty in digest auth")
+		}
+		if s.Password == "" {
+			return fmt.Errorf("password cannot be empty in digest auth")
+		}
 	case strings.EqualFold(s.Type, string(BearerTokenAuth)):
 		if s.Token == "" {
 			return fmt.Errorf("token cannot be empty in bearer token auth")
@@ -2,6 +2,7 @@ package authx
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/projectdiscovery/retryablehttp-go"
 )
@@ -24,7 +25,7 @@ type DynamicAuthStrategy struct {
 
 // Apply applies the strategy to the request
 func (d *DynamicAuthStrategy) Apply(req *http.Request) {
-	strategy := d.Dynamic.GetStrategy()
+	strategy := d.Dynamic.GetStrategyForURL(req.URL)
 	if strategy != nil {
 		strategy.Apply(req)
 	}
@@ -32,8 +33,18 @@ func (d *DynamicAuthStrategy) Apply(req *http.Request) {
 
 // ApplyOnRR applies the strategy to the retryable request
 func (d *DynamicAuthStrategy) ApplyOnRR(req *retryablehttp.Request) {
-	strategy := d.Dynamic.GetStrategy()
+	var u *url.URL
+	if req.URL != nil {
+		u = req.URL.URL
+	}
+	strategy := d.Dynamic.GetStrategyForURL(u)
 	if strategy != nil {
 		strategy.ApplyOnRR(req)
 	}
 }
+
+// Reauthenticate re-runs the per host dynamic secret of the url on next use
+// (ex: when a request using it was unauthorized)
+func (d *DynamicAuthStrategy) Reauthenticate(u *url.URL) {
+	d.Dynamic.Reauthenticate(u)
+}
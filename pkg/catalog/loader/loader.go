@@ -237,6 +237,16 @@ func (store *Store) Workflows() []*templates.Template {
 	return store.workflows
 }
 
+// Close closes the loaded templates and workflows
+func (store *Store) Close() {
+	for _, template := range store.templates {
+		template.Close()
+	}
+	for _, workflow := range store.workflows {
+		workflow.Close()
+	}
+}
+
 // RegisterPreprocessor allows a custom preprocessor to be passed to the store to run against templates
 func (store *Store) RegisterPreprocessor(preprocessor templates.Preprocessor) {
 	store.preprocessor = preprocessor
@@ -370,6 +380,8 @@ func (store *Store) LoadWorkflows(workflowsList []string) []*templates.Template
 			parsed, err := templates.Parse(workflowPath, store.preprocessor, store.config.ExecutorOptions)
 			if err != nil {
 				gologger.Warning().Msgf("Could not parse workflow %s: %s\n", workflowPath, err)
+			} else if parsed != nil && parsed.Untrusted {
+				stats.Increment(templates.SkippedUntrustedStats)
 			} else if parsed != nil {
 				loadedWorkflows = append(loadedWorkflows, parsed)
 			}
@@ -406,8 +418,8 @@ func (store *Store) LoadTemplatesWithTags(templatesList, tags []string) []*templ
 		if loaded || store.pathFilter.MatchIncluded(templatePath) {
 			parsed, err := templates.Parse(templatePath, store.preprocessor, store.config.ExecutorOptions)
 			if err != nil {
-				// exclude templates not compatible with offline matching from total runtime warning stats
-				if !errors.Is(err, templates.ErrIncompatibleWithOfflineMatching) {
+				// exclude templates not compatible with offline matching or request dump mode from total runtime warning stats
+				if !errors.Is(err, templates.ErrIncompatibleWithOfflineMatching) && !errors.Is(err, templates.ErrIncompatibleWithRequestDump) {
 					stats.Increment(templates.RuntimeWarningsStats)
 				}
 				gologger.Warning().Msgf("Could not parse template %s: %s\n", templatePath, err)
@@ -417,6 +429,11 @@ func (store *Store) LoadTemplatesWithTags(templatesList, tags []string) []*templ
 					stats.Increment(templates.SkippedUnsignedStats)
 					continue
 				}
+				if parsed.Untrusted {
+					// skip templates failing trust store verification
+					stats.Increment(templates.SkippedUntrustedStats)
+					continue
+				}
 				// if template has request signature like aws then only signed and verified templates are allowed
 				if parsed.UsesRequestSignature() && !parsed.Verified {
 					stats.Increment(templates.SkippedRequestSignatureStats)
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"sync/atomic"
+
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -19,6 +21,13 @@ type Engine struct {
 	options      *types.Options
 	executerOpts protocols.ExecutorOptions
 	Callback     func(*output.ResultEvent) // Executed on results
+	// OnInputComplete is executed once per input after all templates ran for it
+	OnInputComplete InputCompleteFunc
+
+	// inputs tracks the templates executed for inputs of the current scan
+	inputs *inputTracker
+	// skipped is the number of template executions skipped out of scope
+	skipped atomic.Int64
 }
 
 // New returns a new Engine instance
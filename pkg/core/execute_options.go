@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -38,13 +39,23 @@ func (e *Engine) ExecuteScanWithOpts(ctx context.Context, templatesList []*templ
 
 	totalReqBeforeCluster := getRequestCount(templatesList) * int(target.Count())
 
-	// attempt to cluster templates if noCluster is false
+	// templates are executed in groups of decreasing priority in priority order
+	groups := [][]*templates.Template{templatesList}
+	if e.options.PriorityOrder {
+		groups = priorityGroups(templatesList)
+	}
+
+	// attempt to cluster templates if noCluster is false. Templates are
+	// clustered within their group so that the priority order is kept.
 	var finalTemplates []*templates.Template
 	clusterCount := 0
-	if !noCluster {
-		finalTemplates, clusterCount = templates.ClusterTemplates(templatesList, e.executerOpts)
-	} else {
-		finalTemplates = templatesList
+	for i, group := range groups {
+		if !noCluster {
+			clustered, count := templates.ClusterTemplates(group, e.executerOpts)
+			groups[i] = clustered
+			clusterCount += count
+		}
+		finalTemplates = append(finalTemplates, groups[i]...)
 	}
 
 	totalReqAfterClustering := getRequestCount(finalTemplates) * int(target.Count())
@@ -73,28 +84,53 @@ func (e *Engine) ExecuteScanWithOpts(ctx context.Context, templatesList []*templ
 	}
 
 	filtered := []*templates.Template{}
+	filteredGroups := make([][]*templates.Template, 0, len(groups))
 	selfContained := []*templates.Template{}
 	// Filter Self Contained templates since they are not bound to target
-	for _, v := range finalTemplates {
-		if v.SelfContained {
-			selfContained = append(selfContained, v)
-		} else {
-			filtered = append(filtered, v)
+	for _, group := range groups {
+		var filteredGroup []*templates.Template
+		for _, v := range group {
+			if v.SelfContained {
+				selfContained = append(selfContained, v)
+			} else {
+				filteredGroup = append(filteredGroup, v)
+			}
+		}
+		if len(filteredGroup) > 0 {
+			filtered = append(filtered, filteredGroup...)
+			filteredGroups = append(filteredGroups, filteredGroup)
 		}
 	}
 
+	// track inputs whose templates are executed (if a callback is set)
+	e.inputs = newInputTracker(len(filtered), e.OnInputComplete)
+	e.warnUnscopedTech(filtered)
+	e.skipped.Store(0)
+
 	// Execute All SelfContained in parallel
 	e.executeAllSelfContained(ctx, selfContained, results, selfcontainedWg)
 
 	strategyResult := &atomic.Bool{}
 	switch e.options.ScanStrategy {
 	case scanstrategy.TemplateSpray.String():
-		strategyResult = e.executeTemplateSpray(ctx, filtered, target)
+		// each group is executed on all targets before the next one
+		for _, group := range filteredGroups {
+			if ctx.Err() != nil {
+				break
+			}
+			groupResult := e.executeTemplateSpray(ctx, group, target)
+			strategyResult.CompareAndSwap(false, groupResult.Load())
+		}
 	case scanstrategy.HostSpray.String():
-		strategyResult = e.executeHostSpray(ctx, filtered, target)
+		strategyResult = e.executeHostSpray(ctx, filteredGroups, target)
 	}
 
 	results.CompareAndSwap(false, strategyResult.Load())
+	// inputs of an interrupted scan are completed with the templates executed
+	e.inputs.flush()
+	if skipped := e.skipped.Load(); skipped > 0 {
+		gologger.Info().Msgf("Skipped %d template executions on inputs out of template scope", skipped)
+	}
 
 	selfcontainedWg.Wait()
 	return results
@@ -139,8 +175,9 @@ func (e *Engine) executeTemplateSpray(ctx context.Context, templatesList []*temp
 	return results
 }
 
-// executeHostSpray executes scan using host spray strategy where templates are iterated over each target
-func (e *Engine) executeHostSpray(ctx context.Context, templatesList []*templates.Template, target provider.InputProvider) *atomic.Bool {
+// executeHostSpray executes scan using host spray strategy where templates are iterated over each target.
+// Groups of templates are executed on a target one after the other.
+func (e *Engine) executeHostSpray(ctx context.Context, groups [][]*templates.Template, target provider.InputProvider) *atomic.Bool {
 	results := &atomic.Bool{}
 	wp, _ := syncutil.New(syncutil.WithSize(e.options.BulkSize + e.options.HeadlessBulkSize))
 
@@ -154,7 +191,9 @@ func (e *Engine) executeHostSpray(ctx context.Context, templatesList []*template
 		wp.Add()
 		go func(targetval *contextargs.MetaInput) {
 			defer wp.Done()
-			e.executeTemplatesOnTarget(ctx, templatesList, targetval, results)
+			for _, group := range groups {
+				e.executeTemplatesOnTarget(ctx, group, targetval, results)
+			}
 		}(value)
 		return true
 	})
@@ -162,6 +201,25 @@ func (e *Engine) executeHostSpray(ctx context.Context, templatesList []*template
 	return results
 }
 
+// priorityGroups returns the templates grouped by decreasing priority
+// keeping the order of templates of the same priority
+func priorityGroups(templatesList []*templates.Template) [][]*templates.Template {
+	sorted := make([]*templates.Template, len(templatesList))
+	copy(sorted, templatesList)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	var groups [][]*templates.Template
+	for i, template := range sorted {
+		if i == 0 || template.Priority != sorted[i-1].Priority {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], template)
+	}
+	return groups
+}
+
 // returns total requests count
 func getRequestCount(templates []*templates.Template) int {
 	count := 0
@@ -7,6 +7,7 @@ import (
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
@@ -108,6 +109,13 @@ func (e *Engine) executeTemplateWithTargets(ctx context.Context, template *templ
 
 		// Skip if the host has had errors
 		if e.executerOpts.HostErrorsCache != nil && e.executerOpts.HostErrorsCache.Check(scannedValue.ID()) {
+			e.inputs.done(scannedValue, nil)
+			return true
+		}
+		if !skip && template.Scope != nil && e.skipOutOfScope(template, scannedValue) {
+			cleanupInFlight(index)
+			e.inputs.done(scannedValue, nil)
+			index++
 			return true
 		}
 
@@ -115,9 +123,18 @@ func (e *Engine) executeTemplateWithTargets(ctx context.Context, template *templ
 		go func(index uint32, skip bool, value *contextargs.MetaInput) {
 			defer wg.Done()
 			defer cleanupInFlight(index)
+			var inputResults []*output.ResultEvent
+			defer func() {
+				e.inputs.done(value, inputResults)
+			}()
 			if skip {
 				return
 			}
+			release, ok := e.executerOpts.SeverityPolicies.Acquire(ctx, template.Info.SeverityHolder.Severity)
+			if !ok {
+				return
+			}
+			defer release()
 
 			var match bool
 			var err error
@@ -142,6 +159,7 @@ func (e *Engine) executeTemplateWithTargets(ctx context.Context, template *templ
 			if err != nil {
 				gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.executerOpts.Colorizer.BrightBlue(template.ID), err)
 			}
+			inputResults = ctx.GenerateResult()
 			results.CompareAndSwap(false, match)
 		}(index, skip, scannedValue)
 		index++
@@ -183,6 +201,18 @@ func (e *Engine) executeTemplatesOnTarget(ctx context.Context, alltemplates []*t
 		sg.Add()
 		go func(template *templates.Template, value *contextargs.MetaInput, wg *syncutil.AdaptiveWaitGroup) {
 			defer wg.Done()
+			var inputResults []*output.ResultEvent
+			defer func() {
+				e.inputs.done(value, inputResults)
+			}()
+			if template.Scope != nil && e.skipOutOfScope(template, value) {
+				return
+			}
+			release, ok := e.executerOpts.SeverityPolicies.Acquire(ctx, template.Info.SeverityHolder.Severity)
+			if !ok {
+				return
+			}
+			defer release()
 
 			var match bool
 			var err error
@@ -207,6 +237,7 @@ func (e *Engine) executeTemplatesOnTarget(ctx context.Context, alltemplates []*t
 			if err != nil {
 				gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.executerOpts.Colorizer.BrightBlue(template.ID), err)
 			}
+			inputResults = ctx.GenerateResult()
 			results.CompareAndSwap(false, match)
 		}(tpl, target, sg)
 	}
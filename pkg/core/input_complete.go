@@ -0,0 +1,89 @@
+package core
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// InputCompleteFunc is invoked once per input after all templates of the
+// scan were executed (or skipped) for it with the results found for the input
+type InputCompleteFunc func(input *contextargs.MetaInput, results []*output.ResultEvent)
+
+// inputTracker tracks the templates executed for each input of a scan
+// invoking the input complete callback once the last one finishes
+type inputTracker struct {
+	templates int
+	callback  InputCompleteFunc
+
+	mu     sync.Mutex
+	inputs map[string]*trackedInput
+}
+
+// trackedInput is the state of an input whose templates are being executed
+type trackedInput struct {
+	input     *contextargs.MetaInput
+	completed int
+	results   []*output.ResultEvent
+}
+
+// newInputTracker returns a tracker of inputs scanned with a number of templates
+// or nil if no callback is set
+func newInputTracker(templates int, callback InputCompleteFunc) *inputTracker {
+	if callback == nil || templates == 0 {
+		return nil
+	}
+	return &inputTracker{templates: templates, callback: callback, inputs: make(map[string]*trackedInput)}
+}
+
+// done records the completion of a template for the input with its results.
+// Templates which errored or were skipped for the input are recorded as well.
+func (t *inputTracker) done(input *contextargs.MetaInput, results []*output.ResultEvent) {
+	if t == nil {
+		return
+	}
+	key := input.ID()
+
+	t.mu.Lock()
+	tracked, ok := t.inputs[key]
+	if !ok {
+		tracked = &trackedInput{input: input}
+		t.inputs[key] = tracked
+	}
+	tracked.completed++
+	tracked.results = append(tracked.results, results...)
+	if tracked.completed < t.templates {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.inputs, key)
+	t.mu.Unlock()
+
+	t.callback(tracked.input, tracked.results)
+}
+
+// flush invokes the callback for inputs whose templates were partially
+// executed when the scan ended (ex: the scan was interrupted)
+func (t *inputTracker) flush() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	keys := make([]string, 0, len(t.inputs))
+	for key := range t.inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pending := make([]*trackedInput, 0, len(keys))
+	for _, key := range keys {
+		pending = append(pending, t.inputs[key])
+	}
+	t.inputs = make(map[string]*trackedInput)
+	t.mu.Unlock()
+
+	for _, tracked := range pending {
+		t.callback(tracked.input, tracked.results)
+	}
+}
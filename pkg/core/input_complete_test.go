@@ -0,0 +1,35 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputTracker(t *testing.T) {
+	require.Nil(t, newInputTracker(2, nil), "could not disable tracker without callback")
+
+	completed := map[string]int{}
+	var flushed []string
+	tracker := newInputTracker(2, func(input *contextargs.MetaInput, results []*output.ResultEvent) {
+		completed[input.Input] = len(results)
+		flushed = append(flushed, input.Input)
+	})
+
+	first := &contextargs.MetaInput{Input: "https://first.example.com"}
+	second := &contextargs.MetaInput{Input: "https://second.example.com"}
+
+	tracker.done(first, []*output.ResultEvent{{TemplateID: "a"}})
+	require.Empty(t, completed, "could not wait for all templates of input")
+	tracker.done(first, nil) // skipped or errored template
+	require.Equal(t, map[string]int{first.Input: 1}, completed, "could not complete input")
+
+	tracker.done(second, nil)
+	tracker.flush()
+	require.Equal(t, []string{first.Input, second.Input}, flushed, "could not flush pending input")
+
+	tracker.flush()
+	require.Len(t, flushed, 2, "could not flush pending input once")
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+)
+
+func TestPriorityGroups(t *testing.T) {
+	list := []*templates.Template{
+		{ID: "fuzz-a"},
+		{ID: "tech-detect", Priority: 10},
+		{ID: "fuzz-b"},
+		{ID: "version-detect", Priority: 5},
+		{ID: "waf-detect", Priority: 10},
+	}
+	groups := priorityGroups(list)
+
+	var ids [][]string
+	for _, group := range groups {
+		var groupIDs []string
+		for _, template := range group {
+			groupIDs = append(groupIDs, template.ID)
+		}
+		ids = append(ids, groupIDs)
+	}
+	require.Equal(t, [][]string{{"tech-detect", "waf-detect"}, {"version-detect"}, {"fuzz-a", "fuzz-b"}}, ids, "could not group templates by priority")
+	require.Equal(t, "fuzz-a", list[0].ID, "templates were reordered in place")
+	require.Empty(t, priorityGroups(nil), "could not group empty templates")
+}
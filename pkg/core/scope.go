@@ -0,0 +1,49 @@
+package core
+
+import (
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+)
+
+// skipOutOfScope returns true if the input is out of the scope of the
+// template, reporting the skip and removing the requests of the template
+// for the input from the progress
+func (e *Engine) skipOutOfScope(template *templates.Template, input *contextargs.MetaInput) bool {
+	reason := template.Scope.Check(input.Input, e.executerOpts.ScanValues)
+	if reason == "" {
+		return false
+	}
+	e.skipped.Add(1)
+	gologger.Verbose().Msgf("[%s] Skipping %s out of scope: %s\n", template.ID, input.Input, reason)
+	if e.executerOpts.Progress != nil {
+		e.executerOpts.Progress.AddToTotal(-int64(template.TotalRequests))
+	}
+	if skipWriter, ok := e.executerOpts.Output.(output.SkipWriter); ok {
+		skipWriter.WriteSkip(&output.SkipEvent{
+			Template:   template.Path,
+			TemplateID: template.ID,
+			Input:      input.Input,
+			Reason:     reason,
+			Timestamp:  time.Now(),
+		})
+	}
+	return true
+}
+
+// warnUnscopedTech warns that tech scopes of templates are not checked if
+// values of templates are not shared (without priority order)
+func (e *Engine) warnUnscopedTech(templatesList []*templates.Template) {
+	if e.executerOpts.ScanValues != nil {
+		return
+	}
+	for _, template := range templatesList {
+		if template.Scope != nil && len(template.Scope.Tech) > 0 {
+			gologger.Warning().Msgf("Tech scope of templates (ex: %s) is only checked with priority order (-priority-order)\n", template.ID)
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/projectdiscovery/ratelimit"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func TestSkipOutOfScope(t *testing.T) {
+	var skips []*output.SkipEvent
+	writer := testutils.NewMockOutputWriter(false)
+	writer.SkipCallback = func(event *output.SkipEvent) {
+		skips = append(skips, event)
+	}
+	engine := New(testutils.DefaultOptions)
+	engine.SetExecuterOptions(protocols.ExecutorOptions{Output: writer})
+
+	template := &templates.Template{ID: "wp-fuzz", Path: "wp-fuzz.yaml", Scope: &templates.Scope{Hosts: []string{"*.example.com"}}}
+	require.False(t, engine.skipOutOfScope(template, &contextargs.MetaInput{Input: "https://blog.example.com"}), "skipped input in scope")
+	require.True(t, engine.skipOutOfScope(template, &contextargs.MetaInput{Input: "https://example.org"}), "could not skip input out of scope")
+
+	require.Len(t, skips, 1, "could not report skip")
+	require.Equal(t, "wp-fuzz", skips[0].TemplateID, "could not report skipped template")
+	require.Equal(t, "https://example.org", skips[0].Input, "could not report skipped input")
+	require.Contains(t, skips[0].Reason, "does not match scope hosts", "could not report skip reason")
+	require.Equal(t, int64(1), engine.skipped.Load(), "could not count skip")
+}
+
+func TestScopeClusteredTech(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("powered by wordpress"))
+	}))
+	defer server.Close()
+
+	options := *testutils.DefaultOptions
+	options.PriorityOrder = true
+	options.HeadlessBulkSize, options.HeadlessTemplateThreads = 1, 1
+	testutils.Init(&options)
+
+	var mu sync.Mutex
+	var matched, skipped []string
+	writer := testutils.NewMockOutputWriter(false)
+	writer.WriteCallback = func(event *output.ResultEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		matched = append(matched, event.TemplateID)
+	}
+	writer.SkipCallback = func(event *output.SkipEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		skipped = append(skipped, event.TemplateID)
+	}
+	progressImpl, _ := progress.NewStatsTicker(0, false, false, false, 0)
+	executerOpts := protocols.ExecutorOptions{
+		Output:      writer,
+		Options:     &options,
+		Progress:    progressImpl,
+		Catalog:     disk.NewCatalog(""),
+		RateLimiter: ratelimit.New(context.Background(), uint(options.RateLimit), time.Second),
+		Parser:      templates.NewParser(),
+		Colorizer:   aurora.NewAurora(false),
+		ResumeCfg:   types.NewResumeCfg(),
+		ScanValues:  scanvalues.New(),
+		DoNotCache:  true,
+	}
+
+	// the detectors send the same request and are clustered
+	detector := func(id, word string) string {
+		return fmt.Sprintf("id: %s\ninfo:\n  name: %s\n  author: pdteam\n  severity: info\npriority: 10\nhttp:\n  - method: GET\n    path:\n      - \"{{BaseURL}}\"\n    matchers:\n      - type: word\n        words:\n          - %s\n", id, id, word)
+	}
+	scoped := func(id, tech string) string {
+		return fmt.Sprintf("id: %s\ninfo:\n  name: %s\n  author: pdteam\n  severity: info\nscope:\n  tech:\n    - %s\nhttp:\n  - method: GET\n    path:\n      - \"{{BaseURL}}/%s\"\n    matchers:\n      - type: status\n        status:\n          - 200\n", id, id, tech, id)
+	}
+	var list []*templates.Template
+	for _, data := range []string{detector("wp-detect", "wordpress"), detector("joomla-detect", "joomla"), scoped("wp-check", "wp-detect"), scoped("joomla-check", "joomla-detect")} {
+		template, err := templates.ParseTemplateFromReader(strings.NewReader(data), nil, executerOpts.Copy())
+		require.Nil(t, err, "could not parse template")
+		template.Path = template.ID + ".yaml"
+		list = append(list, template)
+	}
+
+	engine := New(&options)
+	engine.SetExecuterOptions(executerOpts)
+	var inputResults []*output.ResultEvent
+	engine.OnInputComplete = func(input *contextargs.MetaInput, results []*output.ResultEvent) {
+		inputResults = append(inputResults, results...)
+	}
+	engine.ExecuteScanWithOpts(context.Background(), list, provider.NewSimpleInputProviderWithUrls(server.URL), false)
+
+	require.ElementsMatch(t, []string{"wp-detect", "wp-check"}, matched, "could not execute template in detected tech scope")
+	require.Equal(t, []string{"joomla-check"}, skipped, "could not skip template out of detected tech scope")
+	require.Equal(t, []string{"wp-check", "wp-detect"}, executerOpts.ScanValues.Techs(server.URL), "could not record techs of clustered template")
+	require.Len(t, inputResults, 2, "could not collect results of clustered templates")
+}
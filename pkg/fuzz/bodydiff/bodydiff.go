@@ -0,0 +1,112 @@
+// Package bodydiff implements saving of baseline and anomalous fuzzing
+// response bodies to disk along with a unified diff between them for
+// manual triage of subtle body differences.
+package bodydiff
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/pmezard/go-difflib/difflib"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+const (
+	// DefaultMaxDiffs is the default maximum number of saved diffs
+	DefaultMaxDiffs = 100
+	// DefaultMaxSize is the default maximum size in bytes of saved bodies
+	DefaultMaxSize = 1024 * 1024
+	// maxNameLength is the maximum length of the readable part of file names
+	maxNameLength = 80
+)
+
+// ErrLimitReached is returned when the maximum number of diffs has been saved
+var ErrLimitReached = errorutil.New("maximum number of body diffs saved")
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Writer writes baseline and current bodies along with their diff to a directory
+type Writer struct {
+	dir     string
+	max     int
+	maxSize int
+
+	mu    sync.Mutex
+	count int
+}
+
+// New creates a new writer saving at most max diffs with bodies
+// truncated to maxSize bytes to dir. Defaults are used for zero values.
+func New(dir string, max, maxSize int) (*Writer, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create body diff directory")
+	}
+	if max <= 0 {
+		max = DefaultMaxDiffs
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Writer{dir: dir, max: max, maxSize: maxSize}, nil
+}
+
+// Write saves baseline and current bodies as <name>.baseline and <name>.current
+// with a unified diff as <name>.diff and returns the path of the diff file.
+// name is typically built from the template, host, parameter and payload.
+func (w *Writer) Write(name, baseline, current string) (string, error) {
+	w.mu.Lock()
+	if w.count >= w.max {
+		w.mu.Unlock()
+		return "", ErrLimitReached
+	}
+	w.count++
+	w.mu.Unlock()
+
+	baseline = w.truncate(baseline)
+	current = w.truncate(current)
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(baseline),
+		B:        difflib.SplitLines(current),
+		FromFile: "baseline",
+		ToFile:   "current",
+		Context:  3,
+	})
+	if err != nil {
+		return "", errorutil.NewWithErr(err).Msgf("could not generate body diff")
+	}
+
+	base := filepath.Join(w.dir, FileName(name))
+	files := map[string]string{
+		base + ".baseline": baseline,
+		base + ".current":  current,
+		base + ".diff":     diff,
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			return "", errorutil.NewWithErr(err).Msgf("could not write body diff file")
+		}
+	}
+	return base + ".diff", nil
+}
+
+func (w *Writer) truncate(body string) string {
+	if len(body) > w.maxSize {
+		return body[:w.maxSize]
+	}
+	return body
+}
+
+// FileName returns a file system safe name for name suffixed
+// by a short hash of it to avoid collisions after sanitization
+func FileName(name string) string {
+	sum := sha1.Sum([]byte(name))
+	safe := unsafeChars.ReplaceAllString(name, "_")
+	if len(safe) > maxNameLength {
+		safe = safe[:maxNameLength]
+	}
+	return safe + "-" + hex.EncodeToString(sum[:])[:8]
+}
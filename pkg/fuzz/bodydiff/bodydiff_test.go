@@ -0,0 +1,37 @@
+package bodydiff
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	writer, err := New(t.TempDir(), 1, 64)
+	require.Nil(t, err, "could not create writer")
+
+	path, err := writer.Write("template-id_q_' OR 1=1-- -", "line one\nline two\n", "line one\nsql error\n")
+	require.Nil(t, err, "could not write diff")
+	require.True(t, strings.HasSuffix(path, ".diff"), "could not get diff path")
+
+	diff, err := os.ReadFile(path)
+	require.Nil(t, err, "could not read diff")
+	require.Contains(t, string(diff), "-line two")
+	require.Contains(t, string(diff), "+sql error")
+
+	current, err := os.ReadFile(strings.TrimSuffix(path, ".diff") + ".current")
+	require.Nil(t, err, "could not read current body")
+	require.Equal(t, "line one\nsql error\n", string(current))
+
+	_, err = writer.Write("another", "a", "b")
+	require.ErrorIs(t, err, ErrLimitReached, "could write more than max diffs")
+}
+
+func TestFileName(t *testing.T) {
+	name := FileName("id_q_<script>alert(1)</script>")
+	require.NotContains(t, name, "<")
+	require.NotContains(t, name, "/")
+	require.NotEqual(t, FileName("a/b"), FileName("a?b"), "sanitized names collide")
+}
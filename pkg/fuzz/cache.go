@@ -0,0 +1,152 @@
+package fuzz
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cacheStatusHeaders are the response headers of caches and cdns reporting
+// if the response was served from the cache (ex: X-Cache: HIT)
+var cacheStatusHeaders = []string{"X-Cache", "Cf-Cache-Status", "X-Cache-Status", "X-Proxy-Cache", "X-Drupal-Cache", "X-Varnish-Cache", "Akamai-Cache-Status", "X-Fastly-Cache-Status", "Cdn-Cache"}
+
+// CacheStatus returns the status reported by cache headers of the response:
+// hit, miss or empty if no cache reported a status
+func CacheStatus(header http.Header) string {
+	var status string
+	for _, name := range cacheStatusHeaders {
+		value := strings.ToLower(strings.Join(header.Values(name), ","))
+		switch {
+		case strings.Contains(value, "hit"):
+			return "hit"
+		case strings.Contains(value, "miss"), strings.Contains(value, "expired"), strings.Contains(value, "stale"):
+			status = "miss"
+		}
+	}
+	return status
+}
+
+// IsCacheable returns true if the response can be stored by shared caches
+// and served for requests differing in the given request headers.
+//
+// Responses with no-store or private cache-control, a zero s-maxage (or
+// max-age without s-maxage) or Vary: * are not cacheable, as are responses
+// varying on any of the headers (they are part of the cache key). Otherwise
+// responses with a positive max-age, public cache-control, an Age or
+// Expires header or a cache status are considered cacheable.
+func IsCacheable(header http.Header, headers ...string) bool {
+	vary := VaryHeaders(header)
+	for _, name := range vary {
+		if name == "*" {
+			return false
+		}
+		for _, keyed := range headers {
+			if strings.EqualFold(name, keyed) {
+				return false
+			}
+		}
+	}
+
+	directives := cacheControlDirectives(header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+	if _, ok := directives["private"]; ok {
+		return false
+	}
+	if value, ok := directives["s-maxage"]; ok {
+		return positiveSeconds(value)
+	}
+	if value, ok := directives["max-age"]; ok {
+		return positiveSeconds(value)
+	}
+	if _, ok := directives["public"]; ok {
+		return true
+	}
+	if header.Get("Age") != "" || header.Get("Expires") != "" {
+		return true
+	}
+	return CacheStatus(header) != ""
+}
+
+// VaryHeaders returns the lowercased request headers listed by the Vary
+// header of the response
+func VaryHeaders(header http.Header) []string {
+	var vary []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				vary = append(vary, name)
+			}
+		}
+	}
+	return vary
+}
+
+// ReflectedHeaders returns the sorted lowercased names of the request
+// headers whose values are reflected in the body or headers of the response
+func ReflectedHeaders(requestHeaders map[string]string, header http.Header, body string) []string {
+	var reflected []string
+	for name, value := range requestHeaders {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(body, value) || headersContain(header, value) {
+			reflected = append(reflected, strings.ToLower(name))
+		}
+	}
+	sort.Strings(reflected)
+	return reflected
+}
+
+// CachePoisoningValues returns the variables assessing if values of the
+// injected request headers are reflected in a cacheable response:
+// reflected_header, reflected_headers, is_cacheable and cache_status.
+func CachePoisoningValues(requestHeaders map[string]string, header http.Header, body string) map[string]interface{} {
+	reflected := ReflectedHeaders(requestHeaders, header, body)
+	values := map[string]interface{}{
+		"reflected_header":  "",
+		"reflected_headers": strings.Join(reflected, ","),
+		"is_cacheable":      IsCacheable(header, reflected...),
+		"cache_status":      CacheStatus(header),
+	}
+	if len(reflected) > 0 {
+		values["reflected_header"] = reflected[0]
+	}
+	return values
+}
+
+// cacheControlDirectives returns the lowercased directives of the
+// cache-control headers of the response mapped to their values
+func cacheControlDirectives(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				directives[name] = strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// positiveSeconds returns true if value is a positive number of seconds
+func positiveSeconds(value string) bool {
+	seconds, err := strconv.Atoi(value)
+	return err == nil && seconds > 0
+}
+
+// headersContain returns true if any header value of the response contains value
+func headersContain(header http.Header, value string) bool {
+	for _, values := range header {
+		for _, headerValue := range values {
+			if strings.Contains(headerValue, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package fuzz
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCacheable(t *testing.T) {
+	tests := []struct {
+		header    http.Header
+		reflected []string
+		cacheable bool
+	}{
+		{header: http.Header{"Cache-Control": {"public, max-age=3600"}}, cacheable: true},
+		{header: http.Header{"Cache-Control": {"max-age=0"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"max-age=0, s-maxage=60"}}, cacheable: true},
+		{header: http.Header{"Cache-Control": {"private, max-age=60"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"no-store"}, "X-Cache": {"HIT"}}, cacheable: false},
+		{header: http.Header{"Age": {"12"}}, cacheable: true},
+		{header: http.Header{"Cf-Cache-Status": {"MISS"}}, cacheable: true},
+		{header: http.Header{"Content-Type": {"text/html"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Encoding, X-Forwarded-Host"}}, reflected: []string{"x-forwarded-host"}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Encoding"}}, reflected: []string{"x-forwarded-host"}, cacheable: true},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.cacheable, IsCacheable(test.header, test.reflected...), "could not assess cacheability of %v", test.header)
+	}
+}
+
+func TestCachePoisoningValues(t *testing.T) {
+	requestHeaders := map[string]string{"X-Forwarded-Host": "poison.example", "X-Original-URL": "/admin", "X-Empty": ""}
+	header := http.Header{
+		"Cache-Control": {"public, max-age=60"},
+		"X-Cache":       {"miss"},
+		"Location":      {"https://poison.example/"},
+	}
+
+	values := CachePoisoningValues(requestHeaders, header, `<link href="//poison.example/app.js">`)
+	require.Equal(t, "x-forwarded-host", values["reflected_header"], "could not get reflected header")
+	require.Equal(t, "x-forwarded-host", values["reflected_headers"], "could not get reflected headers")
+	require.Equal(t, true, values["is_cacheable"], "could not assess cacheability")
+	require.Equal(t, "miss", values["cache_status"], "could not get cache status")
+
+	values = CachePoisoningValues(requestHeaders, http.Header{"X-Cache": {"TCP_HIT"}}, "/admin")
+	require.Equal(t, "x-original-url", values["reflected_header"], "could not get reflected body header")
+	require.Equal(t, "hit", values["cache_status"], "could not get cache hit status")
+
+	values = CachePoisoningValues(requestHeaders, http.Header{}, "nothing")
+	require.Equal(t, "", values["reflected_header"], "got reflected header without reflection")
+	require.Equal(t, false, values["is_cacheable"], "got cacheable response without cache headers")
+}
@@ -0,0 +1,59 @@
+package fuzz
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleCanary(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+
+	execute := func(rule *Rule) []GeneratedRequest {
+		require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+		baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/?a=1&b=2", nil)
+		require.NoError(t, err, "could not create base request")
+		input := contextargs.NewWithInput(context.Background(), "https://example.com/?a=1&b=2")
+
+		var generated []GeneratedRequest
+		err = rule.Execute(&ExecuteRuleInput{
+			Input:       input,
+			BaseRequest: baseRequest,
+			Callback: func(gr GeneratedRequest) bool {
+				generated = append(generated, gr)
+				return true
+			},
+		})
+		require.NoError(t, err, "could not execute rule")
+		return generated
+	}
+
+	generated := execute(&Rule{Part: "query", Type: "postfix", Mode: "single", Fuzz: SliceOrMapSlice{Value: []string{"'"}}, Canary: true})
+	require.Len(t, generated, 2, "could not generate requests")
+	seen := make(map[string]struct{})
+	for _, gr := range generated {
+		canary := types.ToString(gr.DynamicValues["canary"])
+		require.NotEmpty(t, canary, "could not expose canary")
+		seen[canary] = struct{}{}
+
+		value, _ := url.QueryUnescape(gr.Request.URL.RawQuery)
+		require.True(t, strings.Contains(value, "'"+canary), "could not append canary to payload")
+	}
+	require.Len(t, seen, 2, "canary is not unique per request")
+
+	generated = execute(&Rule{Part: "query", Type: "replace", Mode: "single", Keys: []string{"a"}, Fuzz: SliceOrMapSlice{Value: []string{"<{{canary}}>"}}, Canary: true})
+	require.Len(t, generated, 1, "could not generate requests")
+	canary := types.ToString(generated[0].DynamicValues["canary"])
+	require.Equal(t, "<"+canary+">", generated[0].Request.URL.Query().Get("a"), "could not place canary in payload")
+
+	generated = execute(&Rule{Part: "query", Type: "postfix", Mode: "single", Fuzz: SliceOrMapSlice{Value: []string{"'"}}})
+	require.NotContains(t, generated[0].DynamicValues, "canary", "canary exposed without being enabled")
+}
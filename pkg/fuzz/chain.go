@@ -0,0 +1,37 @@
+package fuzz
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+)
+
+// IsChained returns true if values extracted from responses of the
+// rule are fed forward to its next payloads
+func (rule *Rule) IsChained() bool {
+	return len(rule.Chain) > 0
+}
+
+// compileChain compiles the chain extractors of the rule
+func (rule *Rule) compileChain() error {
+	for _, extractor := range rule.Chain {
+		if extractor.Name == "" {
+			return errors.New("chain extractors must have a name")
+		}
+		if err := extractor.CompileExtractors(); err != nil {
+			return errors.Wrap(err, "could not compile chain extractor")
+		}
+	}
+	return nil
+}
+
+// Chain sets values extracted from the response of the last generated
+// request which are used by the payloads of the following requests.
+//
+// Values not extracted from the last response keep their previous value.
+func (input *ExecuteRuleInput) Chain(values map[string]interface{}) {
+	if len(values) == 0 {
+		return
+	}
+	input.chained = generators.MergeMaps(input.chained, values)
+}
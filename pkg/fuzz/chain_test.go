@@ -0,0 +1,56 @@
+package fuzz
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleChain(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+
+	rule := &Rule{
+		Part: "query",
+		Type: "replace",
+		Mode: "single",
+		Keys: []string{"token"},
+		Fuzz: SliceOrMapSlice{Value: []string{"{{next}}", "{{next}}", "{{next}}"}},
+		Chain: []*extractors.Extractor{{
+			Name:  "next",
+			Type:  extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+			Regex: []string{"[0-9]+"},
+		}},
+	}
+	require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+	baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/?token=0", nil)
+	require.NoError(t, err, "could not create base request")
+
+	var tokens []string
+	input := &ExecuteRuleInput{
+		Input:       contextargs.NewWithInput(context.Background(), "https://example.com/?token=0"),
+		BaseRequest: baseRequest,
+		Values:      map[string]interface{}{"next": "1"},
+	}
+	input.Callback = func(gr GeneratedRequest) bool {
+		token := gr.Request.URL.Query().Get("token")
+		tokens = append(tokens, token)
+		// simulate the response returning the next token
+		current, _ := strconv.Atoi(token)
+		input.Chain(map[string]interface{}{"next": strconv.Itoa(current + 1)})
+		return true
+	}
+	require.NoError(t, rule.Execute(input), "could not execute rule")
+	require.Equal(t, []string{"1", "2", "3"}, tokens, "could not chain extracted values")
+	require.True(t, rule.IsChained(), "could not detect chained rule")
+
+	invalid := &Rule{Part: "query", Fuzz: SliceOrMapSlice{Value: []string{"x"}}, Chain: []*extractors.Extractor{{Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor}, Regex: []string{"x"}}}}
+	require.Error(t, invalid.Compile(nil, executorOpts), "compiled chain extractor without name")
+}
@@ -33,6 +33,14 @@ func (b *Body) Name() string {
 	return RequestBodyComponent
 }
 
+// DataFormat returns the dataformat of the parsed body
+func (b *Body) DataFormat() string {
+	if b.value == nil {
+		return ""
+	}
+	return b.value.dataFormat
+}
+
 // Parse parses the component and returns the
 // parsed component
 func (b *Body) Parse(req *retryablehttp.Request) (bool, error) {
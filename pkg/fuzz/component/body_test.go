@@ -173,3 +173,36 @@ func TestMultiPartFormComponent(t *testing.T) {
 	require.Contains(t, string(newBody), "username", "unexpected body content")
 	require.Contains(t, string(newBody), "testuser", "unexpected body content")
 }
+
+func TestBodyGraphQLComponent(t *testing.T) {
+	req, err := retryablehttp.NewRequest("POST", "https://example.com/graphql", strings.NewReader(`{"query":"{ user(id: 1) { name } }","variables":{"token":"abc"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	body := NewBody()
+	_, err = body.Parse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, "graphql", body.DataFormat(), "unexpected dataformat")
+
+	var keys []string
+	_ = body.Iterate(func(key string, value interface{}) error {
+		keys = append(keys, key)
+		return nil
+	})
+	require.Equal(t, []string{"user(id)", "$token"}, keys, "unexpected keys")
+
+	_ = body.SetValue("user(id)", "1 union select 1")
+	rebuilt, err := body.Rebuild()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBody, err := io.ReadAll(rebuilt.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, `{"query":"{ user(id: \"1 union select 1\") { name } }","variables":{"token":"abc"}}`, string(newBody), "unexpected body")
+}
@@ -61,9 +61,18 @@ const (
 	RequestHeaderComponent = "header"
 	// RequestCookieComponent is the name of the request cookie component
 	RequestCookieComponent = "cookie"
+	// RequestHostComponent is the name of the request host header component
+	RequestHostComponent = "host"
+	// RequestLineComponent is the name of the request line component
+	RequestLineComponent = "request-line"
+	// RequestRawPathComponent is the name of the request raw path component
+	RequestRawPathComponent = "raw-path"
 )
 
 // Components is a list of all available components
+//
+// host, request-line and raw-path components are not included as they are
+// only fuzzed when requested explicitly.
 var Components = []string{
 	RequestBodyComponent,
 	RequestQueryComponent,
@@ -85,6 +94,12 @@ func New(componentType string) Component {
 		return NewHeader()
 	case "cookie":
 		return NewCookie()
+	case "host":
+		return NewHost()
+	case "request-line":
+		return NewRequestLine()
+	case "raw-path":
+		return NewRawPath()
 	}
 	return nil
 }
@@ -2,21 +2,36 @@ package component
 
 import (
 	"context"
-	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/retryablehttp-go"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 )
 
-// Cookie is a component for a request cookie
+// Cookie is a component for request cookies fuzzed individually by name.
+//
+// The Cookie header is parsed into name=value pairs as sent by the client,
+// values are not unquoted or sanitized so that the header is rebuilt with
+// the original order and the payload exactly as generated. Attributes
+// (ex: Path=/ or HttpOnly copied from a Set-Cookie header) and repeated
+// names are preserved as is but are not fuzzed.
 type Cookie struct {
 	value *Value
+	pairs []cookiePair
 
 	req *retryablehttp.Request
 }
 
+// cookiePair is a pair of the Cookie header in order of appearance
+type cookiePair struct {
+	name  string
+	value string
+	// raw is set for pairs which are not fuzzed (attributes, repeated names)
+	raw string
+}
+
 var _ Component = &Cookie{}
 
 // NewCookie creates a new cookie component
@@ -32,23 +47,61 @@ func (c *Cookie) Name() string {
 // Parse parses the component and returns the
 // parsed component
 func (c *Cookie) Parse(req *retryablehttp.Request) (bool, error) {
-	if len(req.Cookies()) == 0 {
-		return false, nil
-	}
-	c.req = req
-	c.value = NewValue("")
+	c.pairs = parseCookieHeader(req.Header.Values("Cookie"))
 
 	parsedCookies := mapsutil.NewOrderedMap[string, any]()
-	for _, cookie := range req.Cookies() {
-		parsedCookies.Set(cookie.Name, cookie.Value)
+	for _, pair := range c.pairs {
+		if pair.raw == "" {
+			parsedCookies.Set(pair.name, pair.value)
+		}
 	}
 	if parsedCookies.Len() == 0 {
 		return false, nil
 	}
+	c.req = req
+	c.value = NewValue("")
 	c.value.SetParsed(dataformat.KVOrderedMap(&parsedCookies), "")
 	return true, nil
 }
 
+// cookieAttributes are the attributes of Set-Cookie headers
+var cookieAttributes = map[string]struct{}{
+	"path":        {},
+	"domain":      {},
+	"expires":     {},
+	"max-age":     {},
+	"secure":      {},
+	"httponly":    {},
+	"samesite":    {},
+	"partitioned": {},
+	"priority":    {},
+}
+
+// parseCookieHeader parses the pairs of Cookie header values
+func parseCookieHeader(values []string) []cookiePair {
+	var pairs []cookiePair
+	seen := make(map[string]struct{})
+	for _, value := range values {
+		for _, part := range strings.Split(value, ";") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, cookieValue, found := strings.Cut(part, "=")
+			name = strings.TrimSpace(name)
+			_, attribute := cookieAttributes[strings.ToLower(name)]
+			_, repeated := seen[name]
+			if !found || name == "" || attribute || repeated {
+				pairs = append(pairs, cookiePair{raw: part})
+				continue
+			}
+			seen[name] = struct{}{}
+			pairs = append(pairs, cookiePair{name: name, value: strings.TrimSpace(cookieValue)})
+		}
+	}
+	return pairs
+}
+
 // Iterate iterates through the component
 func (c *Cookie) Iterate(callback func(key string, value interface{}) error) (err error) {
 	c.value.parsed.Iterate(func(key string, value any) bool {
@@ -87,15 +140,33 @@ func (c *Cookie) Delete(key string) error {
 func (c *Cookie) Rebuild() (*retryablehttp.Request, error) {
 	cloned := c.req.Clone(context.Background())
 
-	cloned.Header.Del("Cookie")
+	parts := make([]string, 0, len(c.pairs))
+	written := make(map[string]struct{}, len(c.pairs))
+	for _, pair := range c.pairs {
+		if pair.raw != "" {
+			parts = append(parts, pair.raw)
+			continue
+		}
+		// deleted cookies are not written
+		value := c.value.parsed.Get(pair.name)
+		if value == nil {
+			continue
+		}
+		written[pair.name] = struct{}{}
+		parts = append(parts, pair.name+"="+types.ToString(value))
+	}
+	// cookies added by key-value payloads are appended
 	c.value.parsed.Iterate(func(key string, value any) bool {
-		cookie := &http.Cookie{
-			Name:  key,
-			Value: fmt.Sprint(value), // Assume the value is always a string for cookies
+		if _, ok := written[key]; !ok {
+			parts = append(parts, key+"="+types.ToString(value))
 		}
-		cloned.AddCookie(cookie)
 		return true
 	})
+
+	cloned.Header.Del("Cookie")
+	if len(parts) > 0 {
+		cloned.Header.Set("Cookie", strings.Join(parts, "; "))
+	}
 	return cloned, nil
 }
 
@@ -103,6 +174,7 @@ func (c *Cookie) Rebuild() (*retryablehttp.Request, error) {
 func (c *Cookie) Clone() Component {
 	return &Cookie{
 		value: c.value.Clone(),
+		pairs: c.pairs,
 		req:   c.req.Clone(context.Background()),
 	}
 }
@@ -55,3 +55,32 @@ func TestCookieComponent(t *testing.T) {
 	newCookie, _ := rebuilt.Cookie("session")
 	require.Equal(t, "new-session", newCookie.Value, "unexpected cookie value")
 }
+
+func TestCookieComponentPreservesHeader(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("Cookie", `theme=dark; session=abc; Path=/; HttpOnly; theme=light; prefs="a b"`)
+
+	cookieComponent := NewCookie()
+	parsed, err := cookieComponent.Parse(req)
+	require.Nil(t, err, "could not parse cookies")
+	require.True(t, parsed, "could not parse cookies")
+
+	var names []string
+	_ = cookieComponent.Iterate(func(key string, value interface{}) error {
+		names = append(names, key)
+		return nil
+	})
+	require.Equal(t, []string{"theme", "session", "prefs"}, names, "could not skip attributes and repeated cookies")
+
+	err = cookieComponent.SetValue("session", "' OR 1=1--")
+	require.Nil(t, err, "could not set cookie value")
+	rebuilt, err := cookieComponent.Rebuild()
+	require.Nil(t, err, "could not rebuild request")
+	require.Equal(t, `theme=dark; session=' OR 1=1--; Path=/; HttpOnly; theme=light; prefs="a b"`, rebuilt.Header.Get("Cookie"), "could not preserve cookie header")
+
+	require.Nil(t, cookieComponent.Delete("theme"), "could not delete cookie")
+	rebuilt, err = cookieComponent.Rebuild()
+	require.Nil(t, err, "could not rebuild request")
+	require.Equal(t, `session=' OR 1=1--; Path=/; HttpOnly; theme=light; prefs="a b"`, rebuilt.Header.Get("Cookie"), "could not delete cookie")
+}
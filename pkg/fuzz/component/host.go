@@ -0,0 +1,94 @@
+package component
+
+import (
+	"context"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// hostKey is the key of the host value in the component
+const hostKey = "Host"
+
+// Host is a component for the host header of a request.
+//
+// Only the Host header sent to the server is rewritten, the connection
+// is still made to the original target of the request.
+type Host struct {
+	value *Value
+
+	req *retryablehttp.Request
+}
+
+var _ Component = &Host{}
+
+// NewHost creates a new host component
+func NewHost() *Host {
+	return &Host{}
+}
+
+// Name returns the name of the component
+func (q *Host) Name() string {
+	return RequestHostComponent
+}
+
+// Parse parses the component and returns the
+// parsed component
+func (q *Host) Parse(req *retryablehttp.Request) (bool, error) {
+	q.req = req
+	q.value = NewValue("")
+
+	host := req.Host
+	if host == "" && req.URL != nil {
+		host = req.URL.Host
+	}
+	if host == "" {
+		return false, nil
+	}
+	q.value.SetParsed(dataformat.KVMap(map[string]interface{}{hostKey: host}), "")
+	return true, nil
+}
+
+// Iterate iterates through the component
+func (q *Host) Iterate(callback func(key string, value interface{}) error) (errx error) {
+	q.value.parsed.Iterate(func(key string, value any) bool {
+		if err := callback(key, value); err != nil {
+			errx = err
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// SetValue sets a value in the component
+// for a key
+func (q *Host) SetValue(key string, value string) error {
+	if !q.value.SetParsedValue(key, value) {
+		return ErrSetValue
+	}
+	return nil
+}
+
+// Delete deletes a key from the component
+func (q *Host) Delete(key string) error {
+	return ErrKeyNotFound
+}
+
+// Rebuild returns a new request with the
+// component rebuilt
+func (q *Host) Rebuild() (*retryablehttp.Request, error) {
+	cloned := q.req.Clone(context.Background())
+	if value, ok := q.value.parsed.Get(hostKey).(string); ok {
+		cloned.Host = value
+	}
+	return cloned, nil
+}
+
+// Clones current state of this component
+func (q *Host) Clone() Component {
+	return &Host{
+		value: q.value.Clone(),
+		req:   q.req.Clone(context.Background()),
+	}
+}
@@ -0,0 +1,46 @@
+package component
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostComponent(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "https://example.com:8443/admin", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := NewHost()
+	_, err = host.Parse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var values []string
+	_ = host.Iterate(func(key string, value interface{}) error {
+		keys = append(keys, key)
+		values = append(values, value.(string))
+		return nil
+	})
+
+	require.Equal(t, []string{"Host"}, keys, "unexpected keys")
+	require.Equal(t, []string{"example.com:8443"}, values, "unexpected values")
+
+	err = host.SetValue("Host", "internal.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt, err := host.Rebuild()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Equal(t, "internal.example.com", rebuilt.Host, "unexpected sent host")
+	require.Equal(t, "example.com:8443", rebuilt.URL.Host, "unexpected connection host")
+}
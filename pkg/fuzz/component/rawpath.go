@@ -0,0 +1,107 @@
+package component
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// RawPath is a component for the path of a request written verbatim on
+// the wire, used to test path normalization with encoded separators
+// (ex: %2f, %2e%2e) that the path component decodes and re-encodes.
+//
+// The fuzzed path is only written verbatim by raw socket requests, the
+// http client normalizes (or rejects) it otherwise.
+type RawPath struct {
+	value *Value
+
+	req *retryablehttp.Request
+}
+
+var _ Component = &RawPath{}
+
+// NewRawPath creates a new raw path component
+func NewRawPath() *RawPath {
+	return &RawPath{}
+}
+
+// Name returns the name of the component
+func (q *RawPath) Name() string {
+	return RequestRawPathComponent
+}
+
+// Parse parses the component and returns the
+// parsed component
+func (q *RawPath) Parse(req *retryablehttp.Request) (bool, error) {
+	q.req = req
+
+	path := req.Request.URL.EscapedPath()
+	if req.Request.RequestURI != "" {
+		path, _, _ = strings.Cut(req.Request.RequestURI, "?")
+	}
+	q.value = NewValue(path)
+
+	parsed, err := dataformat.Get(dataformat.RawDataFormat).Decode(q.value.String())
+	if err != nil {
+		return false, err
+	}
+	q.value.SetParsed(parsed, dataformat.RawDataFormat)
+	return true, nil
+}
+
+// Iterate iterates through the component
+func (q *RawPath) Iterate(callback func(key string, value interface{}) error) (err error) {
+	q.value.parsed.Iterate(func(key string, value any) bool {
+		if errx := callback(key, value); errx != nil {
+			err = errx
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// SetValue sets a value in the component
+// for a key
+func (q *RawPath) SetValue(key string, value string) error {
+	if !q.value.SetParsedValue(key, value) {
+		return ErrSetValue
+	}
+	return nil
+}
+
+// Delete deletes a key from the component
+func (q *RawPath) Delete(key string) error {
+	if !q.value.Delete(key) {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Rebuild returns a new request with the
+// component rebuilt
+func (q *RawPath) Rebuild() (*retryablehttp.Request, error) {
+	encoded, err := q.value.Encode()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode path")
+	}
+	cloned := q.req.Clone(context.Background())
+	// the query of the request target is kept unchanged
+	target := encoded
+	if query := cloned.Request.URL.RawQuery; query != "" {
+		target += "?" + query
+	}
+	cloned.Request.RequestURI = target
+	return cloned, nil
+}
+
+// Clones current state to a new component
+func (q *RawPath) Clone() Component {
+	return &RawPath{
+		value: q.value.Clone(),
+		req:   q.req.Clone(context.Background()),
+	}
+}
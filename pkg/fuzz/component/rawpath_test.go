@@ -0,0 +1,46 @@
+package component
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawPathComponent(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "https://example.com/static/%2fadmin?a=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawPath := NewRawPath()
+	_, err = rawPath.Parse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var values []string
+	_ = rawPath.Iterate(func(key string, value interface{}) error {
+		keys = append(keys, key)
+		values = append(values, value.(string))
+		return nil
+	})
+
+	require.Equal(t, []string{"value"}, keys, "unexpected keys")
+	require.Equal(t, []string{"/static/%2fadmin"}, values, "unexpected values")
+
+	err = rawPath.SetValue("value", "/static/%2e%2e%2fadmin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt, err := rawPath.Rebuild()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Equal(t, "/static/%2e%2e%2fadmin?a=1", rebuilt.Request.RequestURI, "unexpected target")
+	require.Equal(t, "/static//admin", rebuilt.URL.Path, "unexpected connection url")
+}
@@ -0,0 +1,114 @@
+package component
+
+import (
+	"context"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
+	"github.com/projectdiscovery/retryablehttp-go"
+	mapsutil "github.com/projectdiscovery/utils/maps"
+)
+
+const (
+	// requestLineMethodKey is the key of the method token of the request line
+	requestLineMethodKey = "method"
+	// requestLineTargetKey is the key of the request target of the request line
+	requestLineTargetKey = "target"
+	// requestLineVersionKey is the key of the version token of the request line
+	requestLineVersionKey = "version"
+)
+
+// RequestLine is a component for the request line (method, target and
+// version) of a request used to test the tolerance of http parsers.
+//
+// The fuzzed request target and version are only written verbatim by raw
+// socket requests, the http client normalizes (or rejects) them otherwise.
+type RequestLine struct {
+	value *Value
+
+	req *retryablehttp.Request
+}
+
+var _ Component = &RequestLine{}
+
+// NewRequestLine creates a new request line component
+func NewRequestLine() *RequestLine {
+	return &RequestLine{}
+}
+
+// Name returns the name of the component
+func (q *RequestLine) Name() string {
+	return RequestLineComponent
+}
+
+// Parse parses the component and returns the
+// parsed component
+func (q *RequestLine) Parse(req *retryablehttp.Request) (bool, error) {
+	q.req = req
+	q.value = NewValue("")
+
+	target := req.Request.RequestURI
+	if target == "" && req.Request.URL != nil {
+		target = req.Request.URL.RequestURI()
+	}
+	version := req.Proto
+	if version == "" {
+		version = "HTTP/1.1"
+	}
+	// keys are added in order of the request line
+	parsed := mapsutil.NewOrderedMap[string, any]()
+	parsed.Set(requestLineMethodKey, req.Method)
+	parsed.Set(requestLineTargetKey, target)
+	parsed.Set(requestLineVersionKey, version)
+	q.value.SetParsed(dataformat.KVOrderedMap(&parsed), "")
+	return true, nil
+}
+
+// Iterate iterates through the component
+func (q *RequestLine) Iterate(callback func(key string, value interface{}) error) (errx error) {
+	q.value.parsed.Iterate(func(key string, value any) bool {
+		if err := callback(key, value); err != nil {
+			errx = err
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// SetValue sets a value in the component
+// for a key
+func (q *RequestLine) SetValue(key string, value string) error {
+	if !q.value.SetParsedValue(key, value) {
+		return ErrSetValue
+	}
+	return nil
+}
+
+// Delete deletes a key from the component
+func (q *RequestLine) Delete(key string) error {
+	return ErrKeyNotFound
+}
+
+// Rebuild returns a new request with the
+// component rebuilt
+func (q *RequestLine) Rebuild() (*retryablehttp.Request, error) {
+	cloned := q.req.Clone(context.Background())
+	if value, ok := q.value.parsed.Get(requestLineMethodKey).(string); ok {
+		cloned.Method = value
+	}
+	if value, ok := q.value.parsed.Get(requestLineTargetKey).(string); ok {
+		cloned.Request.RequestURI = value
+	}
+	if value, ok := q.value.parsed.Get(requestLineVersionKey).(string); ok {
+		cloned.Proto = value
+	}
+	return cloned, nil
+}
+
+// Clones current state of this component
+func (q *RequestLine) Clone() Component {
+	return &RequestLine{
+		value: q.value.Clone(),
+		req:   q.req.Clone(context.Background()),
+	}
+}
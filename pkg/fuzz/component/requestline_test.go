@@ -0,0 +1,46 @@
+package component
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLineComponent(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "https://example.com/admin?a=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestLine := NewRequestLine()
+	_, err = requestLine.Parse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var values []string
+	_ = requestLine.Iterate(func(key string, value interface{}) error {
+		keys = append(keys, key)
+		values = append(values, value.(string))
+		return nil
+	})
+
+	require.Equal(t, []string{"method", "target", "version"}, keys, "unexpected keys")
+	require.Equal(t, []string{"GET", "/admin?a=1", "HTTP/1.1"}, values, "unexpected values")
+
+	require.Nil(t, requestLine.SetValue("target", "/admin/../../etc/passwd"), "could not set target")
+	require.Nil(t, requestLine.SetValue("version", "HTTP/0.9"), "could not set version")
+
+	rebuilt, err := requestLine.Rebuild()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Equal(t, "GET", rebuilt.Method, "unexpected method")
+	require.Equal(t, "/admin/../../etc/passwd", rebuilt.Request.RequestURI, "unexpected target")
+	require.Equal(t, "HTTP/0.9", rebuilt.Proto, "unexpected version")
+	require.Equal(t, "/admin", rebuilt.URL.Path, "unexpected connection url")
+}
@@ -1,6 +1,7 @@
 package component
 
 import (
+	"encoding/json"
 	"reflect"
 	"strconv"
 
@@ -96,6 +97,11 @@ func (v *Value) SetParsedValue(key string, value string) bool {
 			return false
 		}
 		origValue = parsed
+	case json.Number:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return false
+		}
+		origValue = json.Number(value)
 	case bool:
 		parsed, err := strconv.ParseBool(value)
 		if err != nil {
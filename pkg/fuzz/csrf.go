@@ -0,0 +1,145 @@
+package fuzz
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultCSRFPatterns contains default case-insensitive patterns matched against
+// names of hidden inputs, meta tags, headers and cookies carrying csrf tokens.
+var DefaultCSRFPatterns = []string{
+	`csrf`,
+	`xsrf`,
+	`^_token$`,
+	`^authenticity_token$`,
+	`^__requestverificationtoken$`,
+}
+
+// csrfComponents are the request components rewritten with fresh csrf token
+var csrfComponents = []string{
+	component.RequestBodyComponent,
+	component.RequestQueryComponent,
+	component.RequestHeaderComponent,
+}
+
+// CSRF contains configuration for automatic csrf token handling while fuzzing.
+//
+// When enabled, a fresh token is extracted from a baseline response of the
+// input and injected into matching fields of fuzz-generated requests.
+type CSRF struct {
+	// description: |
+	//   Patterns is the optional list of regex patterns matching csrf token
+	//   field, header and cookie names. Defaults are used if empty.
+	// examples:
+	//   - value: >
+	//       []string{"csrf", "^_token$"}
+	Patterns []string `yaml:"patterns,omitempty" json:"patterns,omitempty" jsonschema:"title=csrf token name patterns,description=Regex patterns matching csrf token field names"`
+	// description: |
+	//   Refresh fetches a new token before each fuzzing request instead of
+	//   once per input. Useful for single-use tokens.
+	Refresh bool `yaml:"refresh,omitempty" json:"refresh,omitempty" jsonschema:"title=refresh csrf token,description=Fetch a new csrf token before each fuzzing request"`
+
+	patterns []*regexp.Regexp
+}
+
+// Compile compiles the csrf token name patterns
+func (c *CSRF) Compile() error {
+	patterns := c.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultCSRFPatterns
+	}
+	c.patterns = make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return errors.Wrapf(err, "could not compile csrf pattern %s", pattern)
+		}
+		c.patterns = append(c.patterns, compiled)
+	}
+	return nil
+}
+
+// IsTokenName returns true if name matches any csrf token pattern
+func (c *CSRF) IsTokenName(name string) bool {
+	for _, pattern := range c.patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract extracts csrf token from baseline response looking at hidden
+// inputs, meta tags, response headers and cookies in that order.
+func (c *CSRF) Extract(resp *http.Response, body string) string {
+	if doc, err := htmlquery.Parse(strings.NewReader(body)); err == nil {
+		for _, node := range htmlquery.Find(doc, "//input[@name]") {
+			if c.IsTokenName(htmlquery.SelectAttr(node, "name")) {
+				if value := htmlquery.SelectAttr(node, "value"); value != "" {
+					return value
+				}
+			}
+		}
+		for _, node := range htmlquery.Find(doc, "//meta[@name]") {
+			if c.IsTokenName(htmlquery.SelectAttr(node, "name")) {
+				if value := htmlquery.SelectAttr(node, "content"); value != "" {
+					return value
+				}
+			}
+		}
+	}
+	if resp == nil {
+		return ""
+	}
+	for name, values := range resp.Header {
+		if c.IsTokenName(name) && len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	for _, cookie := range resp.Cookies() {
+		if c.IsTokenName(cookie.Name) && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// Apply returns request with all csrf token fields in body, query and headers
+// set to token. The parameter currently being fuzzed is never rewritten.
+func (c *CSRF) Apply(gr GeneratedRequest, token string) (*retryablehttp.Request, error) {
+	req := gr.Request
+	for _, name := range csrfComponents {
+		comp := component.New(name)
+		if ok, err := comp.Parse(req); err != nil || !ok {
+			continue
+		}
+		var updated bool
+		_ = comp.Iterate(func(key string, value interface{}) error {
+			if !c.IsTokenName(key) {
+				return nil
+			}
+			if gr.Component != nil && gr.Component.Name() == name && gr.Parameter == key {
+				return nil
+			}
+			if err := comp.SetValue(key, token); err == nil {
+				updated = true
+			}
+			return nil
+		})
+		if !updated {
+			continue
+		}
+		rebuilt, err := comp.Rebuild()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not rebuild %s with csrf token", name)
+		}
+		req = rebuilt.WithContext(req.Context())
+	}
+	return req, nil
+}
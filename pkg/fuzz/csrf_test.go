@@ -0,0 +1,48 @@
+package fuzz
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRFExtract(t *testing.T) {
+	csrf := &CSRF{}
+	require.Nil(t, csrf.Compile(), "could not compile csrf")
+
+	body := `<html><form><input type="hidden" name="csrf_token" value="fresh-token"><input name="q"></form></html>`
+	require.Equal(t, "fresh-token", csrf.Extract(&http.Response{}, body), "could not extract hidden input token")
+
+	body = `<html><head><meta name="csrf-token" content="meta-token"></head></html>`
+	require.Equal(t, "meta-token", csrf.Extract(&http.Response{}, body), "could not extract meta token")
+
+	resp := &http.Response{Header: http.Header{"X-Csrf-Token": []string{"header-token"}}}
+	require.Equal(t, "header-token", csrf.Extract(resp, "<html></html>"), "could not extract header token")
+}
+
+func TestCSRFApply(t *testing.T) {
+	csrf := &CSRF{}
+	require.Nil(t, csrf.Compile(), "could not compile csrf")
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, "https://example.com/update?_token=stale", strings.NewReader("name=test&csrf_token=stale"))
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	updated, err := csrf.Apply(GeneratedRequest{Request: req}, "fresh")
+	require.Nil(t, err, "could not apply csrf token")
+	require.Equal(t, "fresh", updated.URL.Query().Get("_token"), "could not rewrite query token")
+	body, err := updated.BodyBytes()
+	require.Nil(t, err, "could not read body")
+	require.Contains(t, string(body), "csrf_token=fresh", "could not rewrite body token")
+
+	// fuzzed parameter must not be rewritten
+	req, err = retryablehttp.NewRequest(http.MethodGet, "https://example.com/update?_token=payload", nil)
+	require.Nil(t, err, "could not create request")
+	updated, err = csrf.Apply(GeneratedRequest{Request: req, Component: component.NewQuery(), Parameter: "_token"}, "fresh")
+	require.Nil(t, err, "could not apply csrf token")
+	require.Equal(t, "payload", updated.URL.Query().Get("_token"), "could not preserve fuzzed parameter")
+}
@@ -23,6 +23,7 @@ func init() {
 	RegisterDataFormat(NewRaw())
 	RegisterDataFormat(NewForm())
 	RegisterDataFormat(NewMultiPartForm())
+	RegisterDataFormat(NewGraphQL())
 }
 
 const (
@@ -36,8 +37,15 @@ const (
 	FormDataFormat = "form"
 	// MultiPartFormDataFormat is the name of the MultiPartForm data format
 	MultiPartFormDataFormat = "multipart/form-data"
+	// GraphQLDataFormat is the name of the GraphQL data format
+	GraphQLDataFormat = "graphql"
 )
 
+// priorityDataformats are checked before other dataformats
+// as they are more specific than them (ex: graphql requests
+// are also json objects)
+var priorityDataformats = []string{GraphQLDataFormat}
+
 // Get returns the dataformat by name
 func Get(name string) DataFormat {
 	return dataformats[name]
@@ -70,22 +78,31 @@ type Decoded struct {
 
 // Decode decodes the data from a format
 func Decode(data string) (*Decoded, error) {
+	for _, name := range priorityDataformats {
+		if dataformat, ok := dataformats[name]; ok && dataformat.IsType(data) {
+			return decodeWith(dataformat, data)
+		}
+	}
 	for _, dataformat := range dataformats {
 		if dataformat.IsType(data) {
-			decoded, err := dataformat.Decode(data)
-			if err != nil {
-				return nil, err
-			}
-			value := &Decoded{
-				DataFormat: dataformat.Name(),
-				Data:       decoded,
-			}
-			return value, nil
+			return decodeWith(dataformat, data)
 		}
 	}
 	return nil, nil
 }
 
+func decodeWith(dataformat DataFormat, data string) (*Decoded, error) {
+	decoded, err := dataformat.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	value := &Decoded{
+		DataFormat: dataformat.Name(),
+		Data:       decoded,
+	}
+	return value, nil
+}
+
 // Encode encodes the data into a format
 func Encode(data KV, dataformat string) (string, error) {
 	if dataformat == "" {
@@ -1,6 +1,7 @@
 package dataformat
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -27,6 +28,22 @@ func TestDataformatDecodeEncode_JSON(t *testing.T) {
 	}
 }
 
+func TestDataformatDecodeEncode_JSONNumbers(t *testing.T) {
+	for _, obj := range []string{`{"id":12345678901234567890}`, `{"price":1.50}`, `{"rate":1e-7}`} {
+		decoded, err := Decode(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, err := Encode(decoded.Data, decoded.DataFormat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != obj {
+			t.Fatalf("numbers were not preserved: %s", encoded)
+		}
+	}
+}
+
 func TestDataformatDecodeEncode_XML(t *testing.T) {
 	obj := `<foo attr="baz">bar</foo>`
 
@@ -60,3 +77,100 @@ func TestDataformatDecodeEncode_XML(t *testing.T) {
 		t.Fatal("unexpected data")
 	}
 }
+
+func TestDataformatDecodeEncode_GraphQL(t *testing.T) {
+	obj := `{"operationName":"GetUser","query":"query GetUser($id: ID!, $limit: Int = 10) { user(id: $id) { name posts(first: 5, order: DESC, filter: {tag: \"go\"}) { title } } } query Other { admin(id: 1) { name } }","variables":{"id":"1","limit":3}}`
+
+	decoded, err := Decode(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.DataFormat != "graphql" {
+		t.Fatal("unexpected data format")
+	}
+	if decoded.Data.Get("user.posts(first)") != "5" || decoded.Data.Get("user.posts(filter.tag)") != "go" || decoded.Data.Get("$id") != "1" {
+		t.Fatal("unexpected data")
+	}
+	if decoded.Data.Get("admin(id)") != nil {
+		t.Fatal("unexpected argument of other operation")
+	}
+
+	decoded.Data.Set("user.posts(first)", "1 OR 1=1")
+	decoded.Data.Set("user.posts(order)", "ASC")
+	decoded.Data.Set("user.posts(filter.tag)", `x"y`)
+	decoded.Data.Set("$limit", "1'")
+	encoded, err := Encode(decoded.Data, decoded.DataFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"operationName":"GetUser","query":"query GetUser($id: ID!, $limit: Int = 10) { user(id: $id) { name posts(first: \"1 OR 1=1\", order: ASC, filter: {tag: \"x\\\"y\"}) { title } } } query Other { admin(id: 1) { name } }","variables":{"id":"1","limit":"1'"}}`
+	if encoded != expected {
+		t.Fatalf("unexpected data: %s", encoded)
+	}
+
+	field, argument, variable := ParseGraphQLKey("user.posts(filter.tag)")
+	if field != "user.posts" || argument != "filter.tag" || variable != "" {
+		t.Fatal("unexpected key")
+	}
+}
+
+func TestDataformatDecodeEncode_MultiPart(t *testing.T) {
+	obj := "--xyz\r\n" +
+		"Content-Disposition: form-data; name=\"user\"\r\n\r\nadmin\r\n" +
+		"--xyz\r\n" +
+		"Content-Disposition: form-data; name=\"avatar\"; filename=\"me.png\"\r\nContent-Type: image/png\r\n\r\n\x89PNG\r\n" +
+		"--xyz\r\n" +
+		"Content-Disposition: form-data; name=\"avatar\"; filename=\"../other.png\"\r\nContent-Type: image/png\r\n\r\nother\r\n" +
+		"--xyz--\r\n"
+
+	m := NewMultiPartForm()
+	m.boundary = "xyz"
+	decoded, err := m.Decode(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Get("user") != "admin" || decoded.Get("avatar") != "\x89PNG" || decoded.Get("avatar:filename") != "me.png" || decoded.Get("avatar:content-type") != "image/png" {
+		t.Fatal("unexpected data")
+	}
+	if decoded.Get("avatar[1]:filename") != "../other.png" {
+		t.Fatal("unexpected filename of additional file part")
+	}
+
+	// polyglot and filter bypass filenames keep the multipart structure valid
+	filenames := []string{"shell.php.png", "shell.php%00.png", "shell.php;.png", "shell.pHp5", "..%2f..%2fshell.php", `sh"ell.php`, "shell.php\r\nContent-Type: image/png", "shéll.php"}
+	for _, filename := range filenames {
+		cloned := decoded.Clone()
+		cloned.Set("avatar:filename", filename)
+		cloned.Set("avatar:content-type", "image/png; charset=php")
+		encoded, err := m.Encode(cloned)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reencoded, err := m.Decode(encoded)
+		if err != nil {
+			t.Fatalf("could not decode body with filename %q: %s", filename, err)
+		}
+		expected := multipartHeaderEscaper.Replace(filename)
+		if reencoded.Get("avatar:filename") != expected || reencoded.Get("avatar:content-type") != "image/png; charset=php" {
+			t.Fatalf("unexpected filename %q for %q", reencoded.Get("avatar:filename"), filename)
+		}
+		if reencoded.Get("user") != "admin" || reencoded.Get("avatar[1]:filename") != "../other.png" {
+			t.Fatalf("unexpected parts for filename %q", filename)
+		}
+	}
+
+	// null bytes are sent as is even though strict parsers reject them
+	decoded.Set("avatar:filename", "shell.php\x00.png")
+	encoded, err := m.Encode(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(encoded, "filename=\"shell.php\x00.png\"") {
+		t.Fatal("unexpected null byte filename")
+	}
+
+	field, aspect := ParseMultiPartKey("avatar[1]:content-type")
+	if field != "avatar[1]" || aspect != "content-type" {
+		t.Fatal("unexpected key")
+	}
+}
@@ -0,0 +1,798 @@
+package dataformat
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	mapsutil "github.com/projectdiscovery/utils/maps"
+)
+
+// == GraphQL Fuzzing ==
+// GraphQL requests are JSON bodies containing a query document along with
+// optional variables and operation name. Instead of fuzzing the query string
+// as a whole, literal argument values of fields are exposed as keys and
+// payloads are injected in place keeping the query valid.
+//
+// Arguments are keyed by field path and argument path (ex: `user(id)`,
+// `user.posts(first)`, `createUser(input.name)`) and variables by their
+// name prefixed with `$` (ex: `$id`, `$input.name`).
+//
+// When a document contains multiple operations, only arguments of the
+// operation selected by operationName (along with fragments) are exposed.
+
+const (
+	// graphqlRawKey is the hidden key containing the original request
+	graphqlRawKey = "#_graphql"
+)
+
+var reGraphQLNumber = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+var reGraphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// GraphQL is a GraphQL request encoder
+type GraphQL struct{}
+
+var (
+	_ DataFormat = &GraphQL{}
+)
+
+// NewGraphQL returns a new GraphQL encoder
+func NewGraphQL() *GraphQL {
+	return &GraphQL{}
+}
+
+// IsType returns true if the data is a JSON encoded GraphQL request
+func (g *GraphQL) IsType(data string) bool {
+	if !strings.HasPrefix(data, "{") || !strings.HasSuffix(data, "}") || !strings.Contains(data, `"query"`) {
+		return false
+	}
+	_, err := parseGraphQLRequest(data)
+	return err == nil
+}
+
+// Encode encodes the data into a GraphQL request injecting changed
+// argument values in the query and changed variables
+func (g *GraphQL) Encode(data KV) (string, error) {
+	raw, ok := data.Get(graphqlRawKey).(string)
+	if !ok {
+		return "", errors.New("original graphql request not found")
+	}
+	request, err := parseGraphQLRequest(raw)
+	if err != nil {
+		return "", err
+	}
+
+	builder := &strings.Builder{}
+	last := 0
+	for _, argument := range request.arguments {
+		value, ok := graphQLValue(data, argument.key)
+		if !ok || value == argument.value {
+			continue
+		}
+		builder.WriteString(request.query[last:argument.start])
+		builder.WriteString(argument.literal(value))
+		last = argument.end
+	}
+	builder.WriteString(request.query[last:])
+	request.body["query"] = builder.String()
+
+	for _, variable := range request.variables {
+		value, ok := graphQLValue(data, variable.key)
+		if !ok || value == variable.value {
+			continue
+		}
+		request.setVariable(variable.path, typedVariable(variable.original, value))
+	}
+
+	encoded, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(request.body)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode graphql request")
+	}
+	return string(encoded), nil
+}
+
+// Decode decodes the GraphQL request into arguments and variables
+func (g *GraphQL) Decode(data string) (KV, error) {
+	request, err := parseGraphQLRequest(data)
+	if err != nil {
+		return KV{}, err
+	}
+	kv := mapsutil.NewOrderedMap[string, any]()
+	kv.Set(graphqlRawKey, data)
+	for _, argument := range request.arguments {
+		kv.Set(argument.key, argument.value)
+	}
+	for _, variable := range request.variables {
+		kv.Set(variable.key, variable.value)
+	}
+	return KVOrderedMap(&kv), nil
+}
+
+// Name returns the name of the encoder
+func (g *GraphQL) Name() string {
+	return GraphQLDataFormat
+}
+
+// ParseGraphQLKey returns the field path and argument path of an argument
+// key or the variable path of a variable key
+func ParseGraphQLKey(key string) (field, argument, variable string) {
+	if strings.HasPrefix(key, "$") {
+		return "", "", strings.TrimPrefix(key, "$")
+	}
+	if index := strings.Index(key, "("); index > 0 && strings.HasSuffix(key, ")") {
+		return key[:index], key[index+1 : len(key)-1], ""
+	}
+	return "", "", ""
+}
+
+func graphQLValue(data KV, key string) (string, bool) {
+	value := data.Get(key)
+	if value == nil {
+		return "", false
+	}
+	if str, ok := value.(string); ok {
+		return str, true
+	}
+	return fmt.Sprint(value), true
+}
+
+// graphQLRequest is a parsed graphql request
+type graphQLRequest struct {
+	body      map[string]interface{}
+	query     string
+	arguments []graphQLArgument
+	variables []graphQLVariable
+}
+
+// graphQLArgument is a literal argument value in the query
+type graphQLArgument struct {
+	key        string
+	kind       graphQLTokenKind
+	value      string
+	start, end int
+}
+
+// literal returns the query literal for a value replacing the argument
+func (a graphQLArgument) literal(value string) string {
+	switch a.kind {
+	case graphQLInt, graphQLFloat:
+		if reGraphQLNumber.MatchString(value) {
+			return value
+		}
+	case graphQLName:
+		// booleans, null and enum values
+		if reGraphQLName.MatchString(value) {
+			return value
+		}
+	}
+	return quoteGraphQLString(value)
+}
+
+// graphQLVariable is a leaf value of the variables object
+type graphQLVariable struct {
+	key      string
+	path     []string
+	value    string
+	original interface{}
+}
+
+func parseGraphQLRequest(data string) (*graphQLRequest, error) {
+	var body map[string]interface{}
+	decoder := jsoniter.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "could not decode graphql request")
+	}
+	query, ok := body["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, errors.New("graphql request has no query")
+	}
+	operationName, _ := body["operationName"].(string)
+
+	parser := &graphQLParser{lexer: &graphQLLexer{data: query}, operationName: operationName}
+	if err := parser.parseDocument(); err != nil {
+		return nil, errors.Wrap(err, "could not parse graphql query")
+	}
+	request := &graphQLRequest{body: body, query: query, arguments: parser.arguments}
+	if variables, ok := body["variables"].(map[string]interface{}); ok {
+		request.variables = flattenGraphQLVariables(variables, nil)
+	}
+	return request, nil
+}
+
+// flattenGraphQLVariables returns the leaf values of variables in sorted order
+func flattenGraphQLVariables(value interface{}, path []string) []graphQLVariable {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var variables []graphQLVariable
+		for _, key := range keys {
+			variables = append(variables, flattenGraphQLVariables(v[key], append(append([]string{}, path...), key))...)
+		}
+		return variables
+	case []interface{}:
+		var variables []graphQLVariable
+		for i, item := range v {
+			variables = append(variables, flattenGraphQLVariables(item, append(append([]string{}, path...), strconv.Itoa(i)))...)
+		}
+		return variables
+	default:
+		str := ""
+		if v != nil {
+			str = fmt.Sprint(v)
+		}
+		return []graphQLVariable{{key: "$" + strings.Join(path, "."), path: path, value: str, original: v}}
+	}
+}
+
+// setVariable sets the value at the path of variables
+func (r *graphQLRequest) setVariable(path []string, value interface{}) {
+	var current interface{} = r.body["variables"]
+	for i, element := range path {
+		last := i == len(path)-1
+		switch v := current.(type) {
+		case map[string]interface{}:
+			if last {
+				v[element] = value
+				return
+			}
+			current = v[element]
+		case []interface{}:
+			index, err := strconv.Atoi(element)
+			if err != nil || index >= len(v) {
+				return
+			}
+			if last {
+				v[index] = value
+				return
+			}
+			current = v[index]
+		default:
+			return
+		}
+	}
+}
+
+// typedVariable converts the value to the type of the original variable if possible
+func typedVariable(original interface{}, value string) interface{} {
+	switch original.(type) {
+	case jsoniter.Number:
+		if reGraphQLNumber.MatchString(value) {
+			return jsoniter.Number(value)
+		}
+	case bool:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// quoteGraphQLString returns the value as a graphql string literal
+func quoteGraphQLString(value string) string {
+	builder := &strings.Builder{}
+	builder.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(builder, `\u%04x`, r)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+type graphQLTokenKind int
+
+const (
+	graphQLEOF graphQLTokenKind = iota
+	graphQLPunctuator
+	graphQLName
+	graphQLInt
+	graphQLFloat
+	graphQLString
+)
+
+type graphQLToken struct {
+	kind       graphQLTokenKind
+	value      string
+	start, end int
+}
+
+// graphQLLexer is a lexer for graphql documents
+type graphQLLexer struct {
+	data string
+	pos  int
+}
+
+func (l *graphQLLexer) next() (graphQLToken, error) {
+	// skip ignored tokens
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+			continue
+		}
+		if strings.HasPrefix(l.data[l.pos:], "\uFEFF") {
+			l.pos += len("\uFEFF")
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+	start := l.pos
+	if l.pos >= len(l.data) {
+		return graphQLToken{kind: graphQLEOF, start: start, end: start}, nil
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case strings.HasPrefix(l.data[l.pos:], "..."):
+		l.pos += 3
+		return graphQLToken{kind: graphQLPunctuator, value: "...", start: start, end: l.pos}, nil
+	case strings.ContainsRune("!$&():=@[]{}|", rune(c)):
+		l.pos++
+		return graphQLToken{kind: graphQLPunctuator, value: string(c), start: start, end: l.pos}, nil
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		for l.pos < len(l.data) && isGraphQLNameChar(l.data[l.pos]) {
+			l.pos++
+		}
+		return graphQLToken{kind: graphQLName, value: l.data[start:l.pos], start: start, end: l.pos}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return l.number(start)
+	case strings.HasPrefix(l.data[l.pos:], `"""`):
+		return l.blockString(start)
+	case c == '"':
+		return l.string(start)
+	}
+	return graphQLToken{}, fmt.Errorf("unexpected character %q at %d", c, l.pos)
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (l *graphQLLexer) number(start int) (graphQLToken, error) {
+	kind := graphQLInt
+	if l.data[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() int {
+		begin := l.pos
+		for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+			l.pos++
+		}
+		return l.pos - begin
+	}
+	if digits() == 0 {
+		return graphQLToken{}, fmt.Errorf("invalid number at %d", start)
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '.' {
+		kind = graphQLFloat
+		l.pos++
+		if digits() == 0 {
+			return graphQLToken{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+	if l.pos < len(l.data) && (l.data[l.pos] == 'e' || l.data[l.pos] == 'E') {
+		kind = graphQLFloat
+		l.pos++
+		if l.pos < len(l.data) && (l.data[l.pos] == '+' || l.data[l.pos] == '-') {
+			l.pos++
+		}
+		if digits() == 0 {
+			return graphQLToken{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+	return graphQLToken{kind: kind, value: l.data[start:l.pos], start: start, end: l.pos}, nil
+}
+
+func (l *graphQLLexer) string(start int) (graphQLToken, error) {
+	l.pos++
+	builder := &strings.Builder{}
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return graphQLToken{kind: graphQLString, value: builder.String(), start: start, end: l.pos}, nil
+		case c == '\n' || c == '\r':
+			return graphQLToken{}, fmt.Errorf("unterminated string at %d", start)
+		case c == '\\':
+			if l.pos+1 >= len(l.data) {
+				return graphQLToken{}, fmt.Errorf("unterminated string at %d", start)
+			}
+			escaped := l.data[l.pos+1]
+			l.pos += 2
+			switch escaped {
+			case '"', '\\', '/':
+				builder.WriteByte(escaped)
+			case 'b':
+				builder.WriteByte('\b')
+			case 'f':
+				builder.WriteByte('\f')
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.data) {
+					return graphQLToken{}, fmt.Errorf("invalid unicode escape at %d", l.pos)
+				}
+				code, err := strconv.ParseUint(l.data[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return graphQLToken{}, fmt.Errorf("invalid unicode escape at %d", l.pos)
+				}
+				builder.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return graphQLToken{}, fmt.Errorf("invalid escape at %d", l.pos)
+			}
+		default:
+			builder.WriteByte(c)
+			l.pos++
+		}
+	}
+	return graphQLToken{}, fmt.Errorf("unterminated string at %d", start)
+}
+
+func (l *graphQLLexer) blockString(start int) (graphQLToken, error) {
+	l.pos += 3
+	builder := &strings.Builder{}
+	for l.pos < len(l.data) {
+		switch {
+		case strings.HasPrefix(l.data[l.pos:], `\"""`):
+			builder.WriteString(`"""`)
+			l.pos += 4
+		case strings.HasPrefix(l.data[l.pos:], `"""`):
+			l.pos += 3
+			return graphQLToken{kind: graphQLString, value: builder.String(), start: start, end: l.pos}, nil
+		default:
+			builder.WriteByte(l.data[l.pos])
+			l.pos++
+		}
+	}
+	return graphQLToken{}, fmt.Errorf("unterminated block string at %d", start)
+}
+
+// graphQLParser is a parser for graphql documents collecting
+// literal argument values of fields
+type graphQLParser struct {
+	lexer         *graphQLLexer
+	token         graphQLToken
+	operationName string
+	record        bool
+	arguments     []graphQLArgument
+}
+
+func (p *graphQLParser) advance() error {
+	token, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = token
+	return nil
+}
+
+func (p *graphQLParser) peek(value string) bool {
+	return p.token.kind == graphQLPunctuator && p.token.value == value
+}
+
+func (p *graphQLParser) expect(value string) error {
+	if !p.peek(value) {
+		return fmt.Errorf("expected %q at %d", value, p.token.start)
+	}
+	return p.advance()
+}
+
+func (p *graphQLParser) name() (string, error) {
+	if p.token.kind != graphQLName {
+		return "", fmt.Errorf("expected name at %d", p.token.start)
+	}
+	value := p.token.value
+	return value, p.advance()
+}
+
+func (p *graphQLParser) parseDocument() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.token.kind == graphQLEOF {
+		return errors.New("empty document")
+	}
+	for p.token.kind != graphQLEOF {
+		if err := p.parseDefinition(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *graphQLParser) parseDefinition() error {
+	if p.peek("{") {
+		p.record = true
+		return p.parseSelectionSet("")
+	}
+	keyword, err := p.name()
+	if err != nil {
+		return err
+	}
+	switch keyword {
+	case "query", "mutation", "subscription":
+		var name string
+		if p.token.kind == graphQLName {
+			if name, err = p.name(); err != nil {
+				return err
+			}
+		}
+		p.record = p.operationName == "" || p.operationName == name
+		if p.peek("(") {
+			if err := p.parseVariableDefinitions(); err != nil {
+				return err
+			}
+		}
+		if err := p.parseDirectives(); err != nil {
+			return err
+		}
+		return p.parseSelectionSet("")
+	case "fragment":
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if on, err := p.name(); err != nil || on != "on" {
+			return fmt.Errorf("expected type condition at %d", p.token.start)
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		p.record = true
+		if err := p.parseDirectives(); err != nil {
+			return err
+		}
+		return p.parseSelectionSet("")
+	}
+	return fmt.Errorf("unexpected definition %s", keyword)
+}
+
+func (p *graphQLParser) parseVariableDefinitions() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return err
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if p.peek("=") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if err := p.parseValue("", false); err != nil {
+				return err
+			}
+		}
+		if err := p.parseDirectives(); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
+
+func (p *graphQLParser) parseType() error {
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.peek("!") {
+		return p.advance()
+	}
+	return nil
+}
+
+func (p *graphQLParser) parseDirectives() error {
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if p.peek("(") {
+			if err := p.parseArguments("", false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *graphQLParser) parseSelectionSet(path string) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		if p.token.kind == graphQLEOF {
+			return errors.New("unterminated selection set")
+		}
+		if err := p.parseSelection(path); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
+
+func (p *graphQLParser) parseSelection(path string) error {
+	if p.peek("...") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.token.kind == graphQLName && p.token.value != "on" {
+			// fragment spread
+			if _, err := p.name(); err != nil {
+				return err
+			}
+			return p.parseDirectives()
+		}
+		// inline fragment
+		if p.token.kind == graphQLName {
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if _, err := p.name(); err != nil {
+				return err
+			}
+		}
+		if err := p.parseDirectives(); err != nil {
+			return err
+		}
+		return p.parseSelectionSet(path)
+	}
+
+	element, err := p.name()
+	if err != nil {
+		return err
+	}
+	if p.peek(":") {
+		// the alias is used as it is unique within the selection set
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+	}
+	if path != "" {
+		element = path + "." + element
+	}
+	if p.peek("(") {
+		if err := p.parseArguments(element, p.record); err != nil {
+			return err
+		}
+	}
+	if err := p.parseDirectives(); err != nil {
+		return err
+	}
+	if p.peek("{") {
+		return p.parseSelectionSet(element)
+	}
+	return nil
+}
+
+func (p *graphQLParser) parseArguments(field string, record bool) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		key := ""
+		if record {
+			key = field + "(" + name
+		}
+		if err := p.parseValue(key, record); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
+
+// parseValue parses a value recording literals with the key when record is true.
+// Keys are recorded closed with the argument delimiter.
+func (p *graphQLParser) parseValue(key string, record bool) error {
+	token := p.token
+	switch {
+	case p.peek("$"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		_, err := p.name()
+		return err
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		for i := 0; !p.peek("]"); i++ {
+			if p.token.kind == graphQLEOF {
+				return errors.New("unterminated list")
+			}
+			if err := p.parseValue(fmt.Sprintf("%s.%d", key, i), record); err != nil {
+				return err
+			}
+		}
+		return p.advance()
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			if err := p.parseValue(key+"."+name, record); err != nil {
+				return err
+			}
+		}
+		return p.advance()
+	case token.kind == graphQLName, token.kind == graphQLInt, token.kind == graphQLFloat, token.kind == graphQLString:
+		if record {
+			p.arguments = append(p.arguments, graphQLArgument{key: key + ")", kind: token.kind, value: token.value, start: token.start, end: token.end})
+		}
+		return p.advance()
+	}
+	return fmt.Errorf("unexpected value at %d", token.start)
+}
@@ -17,6 +17,10 @@ type JSON struct{}
 
 var (
 	_ DataFormat = &JSON{}
+
+	// jsonNumberConfig decodes numbers as json.Number so that they are
+	// encoded back exactly as observed (ex: 1.50, large integers)
+	jsonNumberConfig = jsoniter.Config{UseNumber: true}.Froze()
 )
 
 // NewJSON returns a new JSON encoder
@@ -38,7 +42,7 @@ func (j *JSON) Encode(data KV) (string, error) {
 // Decode decodes the data from JSON format
 func (j *JSON) Decode(data string) (KV, error) {
 	var decoded map[string]interface{}
-	err := jsoniter.Unmarshal([]byte(data), &decoded)
+	err := jsonNumberConfig.Unmarshal([]byte(data), &decoded)
 	return KVMap(decoded), err
 }
 
@@ -6,10 +6,48 @@ import (
 	"io"
 	"mime"
 	"mime/multipart"
+	"net/textproto"
+	"strings"
 
 	mapsutil "github.com/projectdiscovery/utils/maps"
 )
 
+// == Multipart Fuzzing ==
+// Form field values of multipart bodies are exposed by their name. File parts
+// additionally expose their filename and part Content-Type as separate keys
+// suffixed by `:filename` and `:content-type` (ex: `avatar:filename`) so rules
+// can target them specifically (ex: to bypass image-only upload filters).
+//
+// Additional file parts of the same field are keyed with their index
+// (ex: `files[1]`, `files[1]:filename`). The order of parts is preserved
+// when the body is encoded again.
+
+const (
+	// multipartPartsKey is the hidden key containing the parts of the body
+	multipartPartsKey = "#_multipart"
+
+	// MultiPartFilenameSuffix is the key suffix of filenames of file parts
+	MultiPartFilenameSuffix = ":filename"
+	// MultiPartContentTypeSuffix is the key suffix of content types of file parts
+	MultiPartContentTypeSuffix = ":content-type"
+)
+
+// multipartHeaderEscaper escapes header values the way browsers do
+// so that payloads keep the multipart structure valid
+var multipartHeaderEscaper = strings.NewReplacer("\"", "%22", "\r", "%0D", "\n", "%0A")
+
+// multipartPart is a part of a decoded multipart body
+type multipartPart struct {
+	// key is the key of the part value
+	key string
+	// name is the form field name of the part
+	name string
+	// index is the index of the value of repeated fields (-1 otherwise)
+	index int
+	// file is true if the part is a file part
+	file bool
+}
+
 type MultiPartForm struct {
 	boundary string
 }
@@ -37,29 +75,96 @@ func (m *MultiPartForm) Encode(data KV) (string, error) {
 		return "", err
 	}
 
-	var Itererr error
+	parts, _ := data.Get(multipartPartsKey).([]multipartPart)
+	written := make(map[string]struct{})
+	for _, part := range parts {
+		written[part.key] = struct{}{}
+		value := data.Get(part.key)
+		if value == nil {
+			// part was deleted
+			continue
+		}
+		if part.file {
+			written[part.key+MultiPartFilenameSuffix] = struct{}{}
+			written[part.key+MultiPartContentTypeSuffix] = struct{}{}
+			if err := writeMultipartFile(w, part.name, data, part.key); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if part.index >= 0 {
+			values, ok := value.([]interface{})
+			if !ok || part.index >= len(values) {
+				continue
+			}
+			value = values[part.index]
+		}
+		if err := writeMultipartField(w, part.name, value); err != nil {
+			return "", err
+		}
+	}
+
+	var iterErr error
 	data.Iterate(func(key string, value any) bool {
-		var fw io.Writer
-		var err error
-		// Add field
-		if fw, err = w.CreateFormField(key); err != nil {
-			Itererr = err
-			return false
+		if _, ok := written[key]; ok || strings.HasPrefix(key, "#_") {
+			return true
 		}
-		if _, err = fw.Write([]byte(value.(string))); err != nil {
-			Itererr = err
+		if err := writeMultipartField(w, key, value); err != nil {
+			iterErr = err
 			return false
 		}
 		return true
 	})
-	if Itererr != nil {
-		return "", Itererr
+	if iterErr != nil {
+		return "", iterErr
 	}
 
 	w.Close()
 	return b.String(), nil
 }
 
+// writeMultipartField writes a form field part
+func writeMultipartField(w *multipart.Writer, name string, value interface{}) error {
+	fw, err := w.CreateFormField(name)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write([]byte(multipartValue(value)))
+	return err
+}
+
+// writeMultipartFile writes a file part with filename and content type of the key
+func writeMultipartFile(w *multipart.Writer, name string, data KV, key string) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		multipartHeaderEscaper.Replace(name), multipartHeaderEscaper.Replace(multipartValue(data.Get(key+MultiPartFilenameSuffix)))))
+	if contentType := multipartValue(data.Get(key + MultiPartContentTypeSuffix)); contentType != "" {
+		header.Set("Content-Type", multipartHeaderEscaper.Replace(contentType))
+	}
+	fw, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write([]byte(multipartValue(data.Get(key))))
+	return err
+}
+
+// multipartValue returns the string value of a part
+func multipartValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		if len(v) > 0 {
+			return multipartValue(v[len(v)-1])
+		}
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
 // ParseBoundary parses the boundary from the content type
 func (m *MultiPartForm) ParseBoundary(contentType string) error {
 	_, params, err := mime.ParseMediaType(contentType)
@@ -75,49 +180,100 @@ func (m *MultiPartForm) ParseBoundary(contentType string) error {
 
 // Decode decodes the data from MultiPartForm format
 func (m *MultiPartForm) Decode(data string) (KV, error) {
-	// Create a buffer from the string data
-	b := bytes.NewBufferString(data)
-	// The boundary parameter should be extracted from the Content-Type header of the HTTP request
-	// which is not available in this context, so this is a placeholder for demonstration.
-	// You will need to pass the actual boundary value to this function.
-	r := multipart.NewReader(b, m.boundary)
-
-	form, err := r.ReadForm(32 << 20) // 32MB is the max memory used to parse the form
-	if err != nil {
-		return KV{}, err
-	}
-	defer func() {
-		_ = form.RemoveAll()
-	}()
+	r := multipart.NewReader(strings.NewReader(data), m.boundary)
 
 	result := mapsutil.NewOrderedMap[string, any]()
-	for key, values := range form.Value {
-		if len(values) > 1 {
-			result.Set(key, values)
-		} else {
-			result.Set(key, values[0])
+	var parts []multipartPart
+	fileCount := make(map[string]int)
+	valueCount := make(map[string]int)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
 		}
-	}
-	for key, files := range form.File {
-		fileContents := []interface{}{}
-		for _, fileHeader := range files {
-			file, err := fileHeader.Open()
-			if err != nil {
-				return KV{}, err
+		if err != nil {
+			return KV{}, err
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return KV{}, err
+		}
+
+		if filename := part.FileName(); filename != "" || part.Header.Get("Content-Type") != "" {
+			key := name
+			if count := fileCount[name]; count > 0 || valueCount[name] > 0 {
+				key = fmt.Sprintf("%s[%d]", name, count)
 			}
-			defer file.Close()
+			fileCount[name]++
+			result.Set(key, string(content))
+			result.Set(key+MultiPartFilenameSuffix, rawFileName(part, filename))
+			result.Set(key+MultiPartContentTypeSuffix, part.Header.Get("Content-Type"))
+			parts = append(parts, multipartPart{key: key, name: name, index: -1, file: true})
+			continue
+		}
 
-			buffer := new(bytes.Buffer)
-			if _, err := buffer.ReadFrom(file); err != nil {
-				return KV{}, err
+		if fileCount[name] > 0 {
+			// a file part already uses the field name as key
+			key := fmt.Sprintf("%s[%d]", name, fileCount[name])
+			fileCount[name]++
+			result.Set(key, string(content))
+			parts = append(parts, multipartPart{key: key, name: name, index: -1})
+			continue
+		}
+		count := valueCount[name]
+		valueCount[name]++
+		switch count {
+		case 0:
+			result.Set(name, string(content))
+			parts = append(parts, multipartPart{key: name, name: name, index: -1})
+		case 1:
+			first, _ := result.Get(name)
+			result.Set(name, []interface{}{first, string(content)})
+			for i := range parts {
+				if parts[i].key == name {
+					parts[i].index = 0
+				}
 			}
-			fileContents = append(fileContents, buffer.String())
+			parts = append(parts, multipartPart{key: name, name: name, index: 1})
+		default:
+			existing, _ := result.Get(name)
+			values, _ := existing.([]interface{})
+			result.Set(name, append(values, string(content)))
+			parts = append(parts, multipartPart{key: name, name: name, index: count})
 		}
-		result.Set(key, fileContents)
 	}
+	result.Set(multipartPartsKey, parts)
 	return KVOrderedMap(&result), nil
 }
 
+// rawFileName returns the filename of the part as sent by the client.
+// The standard library only returns the base name of the filename which
+// drops path components (ex: ../../shell.php).
+func rawFileName(part *multipart.Part, filename string) string {
+	if _, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition")); err == nil {
+		if raw, ok := params["filename"]; ok {
+			return raw
+		}
+	}
+	return filename
+}
+
+// ParseMultiPartKey returns the form field name of a multipart key and
+// the fuzzed aspect of the part (value, filename or content-type)
+func ParseMultiPartKey(key string) (field, aspect string) {
+	switch {
+	case strings.HasSuffix(key, MultiPartFilenameSuffix):
+		return strings.TrimSuffix(key, MultiPartFilenameSuffix), "filename"
+	case strings.HasSuffix(key, MultiPartContentTypeSuffix):
+		return strings.TrimSuffix(key, MultiPartContentTypeSuffix), "content-type"
+	}
+	return key, "value"
+}
+
 // Name returns the name of the encoder
 func (m *MultiPartForm) Name() string {
 	return "multipart/form-data"
@@ -0,0 +1,161 @@
+package fuzz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Early abort metrics comparing responses of fuzzing requests
+const (
+	EarlyAbortStatus     = "status"
+	EarlyAbortLength     = "length"
+	EarlyAbortSimilarity = "similarity"
+)
+
+// defaultSimilarityThreshold is the default similarity above which
+// response bodies are indistinguishable
+const defaultSimilarityThreshold = 0.95
+
+// EarlyAbort contains configuration for skipping the remaining payloads
+// of a rule once its responses prove the payloads ineffective.
+type EarlyAbort struct {
+	// description: |
+	//   After is the number of consecutive indistinguishable responses
+	//   after which the remaining payloads of the rule are skipped.
+	// examples:
+	//   - value: "10"
+	After int `yaml:"after,omitempty" json:"after,omitempty" jsonschema:"title=indistinguishable responses,description=Number of consecutive indistinguishable responses after which remaining payloads are skipped"`
+	// description: |
+	//   Metric is the metric comparing responses.
+	//
+	//   status compares status codes, length compares status codes and body
+	//   lengths and similarity compares status codes and the token similarity
+	//   of bodies. Default is length.
+	// values:
+	//   - "status"
+	//   - "length"
+	//   - "similarity"
+	Metric string `yaml:"metric,omitempty" json:"metric,omitempty" jsonschema:"title=response comparison metric,description=Metric comparing responses,enum=status,enum=length,enum=similarity"`
+	// description: |
+	//   Threshold is the minimum similarity (0-1) of indistinguishable
+	//   bodies for the similarity metric. Default is 0.95.
+	// examples:
+	//   - value: "0.9"
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty" jsonschema:"title=similarity threshold,description=Minimum similarity of indistinguishable bodies for the similarity metric"`
+}
+
+// Compile validates the early abort configuration
+func (e *EarlyAbort) Compile() error {
+	if e.After <= 0 {
+		return errors.Errorf("early-abort after must be positive")
+	}
+	if e.Metric == "" {
+		e.Metric = EarlyAbortLength
+	}
+	switch e.Metric {
+	case EarlyAbortStatus, EarlyAbortLength, EarlyAbortSimilarity:
+	default:
+		return errors.Errorf("invalid early-abort metric specified: %s", e.Metric)
+	}
+	if e.Threshold == 0 {
+		e.Threshold = defaultSimilarityThreshold
+	}
+	if e.Threshold < 0 || e.Threshold > 1 {
+		return errors.Errorf("early-abort threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// EarlyAbortTracker tracks the responses of the requests of a rule for a
+// parameter of an input. It is not safe for concurrent use.
+type EarlyAbortTracker struct {
+	config *EarlyAbort
+
+	statusCode int
+	body       string
+	tokens     map[string]struct{}
+	similar    int
+	// responsive is true once the endpoint returned distinguishable responses
+	responsive bool
+}
+
+// NewEarlyAbortTracker returns a tracker of the responses of the rule or
+// nil if early abort is not enabled for the rule
+func (rule *Rule) NewEarlyAbortTracker() *EarlyAbortTracker {
+	if rule.EarlyAbort == nil {
+		return nil
+	}
+	return &EarlyAbortTracker{config: rule.EarlyAbort}
+}
+
+// Observe records the response of a request and returns true with the
+// reason once the remaining payloads of the rule should be skipped.
+//
+// Early abort is disabled for the rule once a response is matched or
+// differs from the first response so that responsive endpoints are
+// fuzzed with all payloads.
+func (t *EarlyAbortTracker) Observe(statusCode int, body string, matched bool) (bool, string) {
+	if t == nil || t.responsive {
+		return false, ""
+	}
+	if matched {
+		t.responsive = true
+		return false, ""
+	}
+	if t.similar == 0 {
+		t.statusCode, t.body = statusCode, body
+		if t.config.Metric == EarlyAbortSimilarity {
+			t.tokens = tokenSet(body)
+		}
+		t.similar = 1
+	} else if t.indistinguishable(statusCode, body) {
+		t.similar++
+	} else {
+		t.responsive = true
+		return false, ""
+	}
+	if t.similar < t.config.After {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%d responses indistinguishable by %s (status %d)", t.similar, t.config.Metric, t.statusCode)
+}
+
+// indistinguishable returns true if the response cannot be distinguished
+// from the first response of the rule by the metric
+func (t *EarlyAbortTracker) indistinguishable(statusCode int, body string) bool {
+	if statusCode != t.statusCode {
+		return false
+	}
+	switch t.config.Metric {
+	case EarlyAbortLength:
+		return len(body) == len(t.body)
+	case EarlyAbortSimilarity:
+		return jaccardSimilarity(t.tokens, tokenSet(body)) >= t.config.Threshold
+	}
+	return true
+}
+
+// tokenSet returns the set of whitespace separated tokens of the data
+func tokenSet(data string) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	for _, token := range strings.Fields(data) {
+		tokens[token] = struct{}{}
+	}
+	return tokens
+}
+
+// jaccardSimilarity returns the jaccard similarity of two token sets
+func jaccardSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var common int
+	for token := range a {
+		if _, ok := b[token]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
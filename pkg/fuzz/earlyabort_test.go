@@ -0,0 +1,38 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarlyAbort(t *testing.T) {
+	rule := &Rule{EarlyAbort: &EarlyAbort{After: 3, Metric: EarlyAbortSimilarity, Threshold: 0.5}}
+	require.NoError(t, rule.Compile(nil, nil), "could not compile rule")
+
+	tracker := rule.NewEarlyAbortTracker()
+	abort, _ := tracker.Observe(200, "no results found for id 1", false)
+	require.False(t, abort, "aborted after first response")
+	abort, _ = tracker.Observe(200, "no results found for id 2", false)
+	require.False(t, abort, "aborted before threshold")
+	abort, reason := tracker.Observe(200, "no results found for id 3", false)
+	require.True(t, abort, "could not abort after indistinguishable responses")
+	require.Contains(t, reason, "3 responses indistinguishable by similarity", "could not get abort reason")
+
+	tracker = rule.NewEarlyAbortTracker()
+	_, _ = tracker.Observe(200, "no results found for id 1", false)
+	_, _ = tracker.Observe(500, "sql syntax error near '", false)
+	abort, _ = tracker.Observe(200, "no results found for id 3", false)
+	require.False(t, abort, "aborted for responsive endpoint")
+
+	rule = &Rule{EarlyAbort: &EarlyAbort{After: 2}}
+	require.NoError(t, rule.Compile(nil, nil), "could not compile rule")
+	tracker = rule.NewEarlyAbortTracker()
+	_, _ = tracker.Observe(200, "abc", false)
+	abort, _ = tracker.Observe(200, "abcd", false)
+	require.False(t, abort, "aborted with different lengths")
+
+	require.Nil(t, (&Rule{}).NewEarlyAbortTracker(), "created tracker without early abort")
+	invalid := &Rule{EarlyAbort: &EarlyAbort{After: 2, Metric: "hash"}}
+	require.Error(t, invalid.Compile(nil, nil), "compiled invalid early abort metric")
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/retryablehttp-go"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	urlutil "github.com/projectdiscovery/utils/url"
@@ -45,6 +48,22 @@ type ExecuteRuleInput struct {
 	Values map[string]interface{}
 	// BaseRequest is the base http request for fuzzing rule
 	BaseRequest *retryablehttp.Request
+	// DecodePayload optionally decodes evaluated payloads to the raw bytes
+	// set in requests (ex: hex encoded payloads of requests with an encoding)
+	DecodePayload func(payload string) (string, error)
+
+	// canary is the canary of the request currently generated (if enabled)
+	canary string
+	// chained contains values extracted from previous responses of the rule
+	chained map[string]interface{}
+	// xxe is the external entity of the request currently generated (if any)
+	xxe *xxeInjection
+	// jsonMutation is the json mutation of the request currently generated (if any)
+	jsonMutation string
+	// optionVars are the cli variables merged with the variables of the input
+	optionVars map[string]interface{}
+	// sample contains the payloads of the component selected by sampling (if any)
+	sample *payloadSample
 }
 
 // GeneratedRequest is a single generated request for rule
@@ -57,6 +76,88 @@ type GeneratedRequest struct {
 	DynamicValues map[string]interface{}
 	// Component is the component for the request
 	Component component.Component
+	// Parameter is the key of component fuzzed in request (empty in multiple mode)
+	Parameter string
+	// JSONMutation is the json mutation applied to the parameter (if any)
+	JSONMutation string
+}
+
+// FuzzedValue returns the value of the fuzzed parameter of the
+// request (including the payload) or empty string in multiple mode
+func (gr GeneratedRequest) FuzzedValue() string {
+	if gr.Component == nil || gr.Parameter == "" {
+		return ""
+	}
+	var fuzzedValue string
+	_ = gr.Component.Iterate(func(key string, value interface{}) error {
+		if key == gr.Parameter {
+			fuzzedValue = types.ToString(value)
+		}
+		return nil
+	})
+	return fuzzedValue
+}
+
+// GraphQLMetadata returns the targeted field and argument (or variable)
+// of the request if a graphql body parameter was fuzzed
+func (gr GeneratedRequest) GraphQLMetadata() map[string]interface{} {
+	body, ok := gr.Component.(*component.Body)
+	if !ok || gr.Parameter == "" || body.DataFormat() != dataformat.GraphQLDataFormat {
+		return nil
+	}
+	field, argument, variable := dataformat.ParseGraphQLKey(gr.Parameter)
+	if variable != "" {
+		return map[string]interface{}{"graphql_variable": variable}
+	}
+	if field == "" {
+		return nil
+	}
+	return map[string]interface{}{"graphql_field": field, "graphql_argument": argument}
+}
+
+// MultiPartMetadata returns the targeted form field and aspect of the part
+// (value, filename or content-type) if a multipart body parameter was fuzzed
+func (gr GeneratedRequest) MultiPartMetadata() map[string]interface{} {
+	body, ok := gr.Component.(*component.Body)
+	if !ok || gr.Parameter == "" || body.DataFormat() != dataformat.MultiPartFormDataFormat {
+		return nil
+	}
+	field, aspect := dataformat.ParseMultiPartKey(gr.Parameter)
+	return map[string]interface{}{"multipart_field": field, "multipart_target": aspect}
+}
+
+// JSONMutationMetadata returns the json mutation applied to the fuzzed
+// parameter of a json body if any
+func (gr GeneratedRequest) JSONMutationMetadata() map[string]interface{} {
+	if gr.JSONMutation == "" {
+		return nil
+	}
+	return map[string]interface{}{"json_mutation": gr.JSONMutation}
+}
+
+// CookieMetadata returns the name of the targeted cookie if a cookie was fuzzed
+func (gr GeneratedRequest) CookieMetadata() map[string]interface{} {
+	if _, ok := gr.Component.(*component.Cookie); !ok || gr.Parameter == "" {
+		return nil
+	}
+	return map[string]interface{}{"cookie": gr.Parameter}
+}
+
+// FuzzedHeaders returns the request headers fuzzed by the header component
+// mapped to their values (including the payload). All headers of the
+// component are returned in multiple mode.
+func (gr GeneratedRequest) FuzzedHeaders() map[string]string {
+	if _, ok := gr.Component.(*component.Header); !ok {
+		return nil
+	}
+	headers := make(map[string]string)
+	_ = gr.Component.Iterate(func(key string, value interface{}) error {
+		if gr.Parameter == "" || key == gr.Parameter {
+			headers[key] = types.ToString(value)
+		}
+		return nil
+	})
+	return headers
 }
 
 // Execute executes a fuzzing rule accepting a callback on which
@@ -72,9 +173,19 @@ func (rule *Rule) Execute(input *ExecuteRuleInput) (err error) {
 		return ErrRuleNotApplicable.Msgf("both base request and reqresp are nil for %v", input.Input.MetaInput.Input)
 	}
 
+	componentNames := component.Components
+	switch rule.partType {
+	case hostPartType:
+		componentNames = []string{component.RequestHostComponent}
+	case requestLinePartType:
+		componentNames = []string{component.RequestLineComponent}
+	case rawPathPartType:
+		componentNames = []string{component.RequestRawPathComponent}
+	}
+
 	var finalComponentList []component.Component
 	// match rule part with component name
-	for _, componentName := range component.Components {
+	for _, componentName := range componentNames {
 		if rule.partType != requestPartType && rule.Part != componentName {
 			continue
 		}
@@ -88,7 +199,7 @@ func (rule *Rule) Execute(input *ExecuteRuleInput) (err error) {
 			continue
 		}
 		// check rule applicable on this component
-		if !rule.checkRuleApplicableOnComponent(component) {
+		if !rule.checkRuleApplicableOnComponent(input, component) {
 			continue
 		}
 		finalComponentList = append(finalComponentList, component)
@@ -99,14 +210,16 @@ func (rule *Rule) Execute(input *ExecuteRuleInput) (err error) {
 	}
 
 	baseValues := input.Values
+	input.optionVars = rule.options.BuildPayloadFromOptions(input.Input.MetaInput)
 	if rule.generator == nil {
 		for _, component := range finalComponentList {
 			// get vars from variables while replacing interactsh urls
 			evaluatedValues, interactURLs := rule.options.Variables.EvaluateWithInteractsh(baseValues, rule.options.Interactsh)
-			input.Values = generators.MergeMaps(evaluatedValues, baseValues, rule.options.Options.Vars.AsMap(), rule.options.Constants)
+			input.Values = generators.MergeMaps(evaluatedValues, baseValues, input.optionVars, rule.options.Constants)
 			// evaluate all vars with interactsh
 			input.Values, interactURLs = rule.evaluateVarsWithInteractsh(input.Values, interactURLs)
 			input.InteractURLs = interactURLs
+			input.sample = rule.newPayloadSample(component, 1)
 			err := rule.executeRuleValues(input, component)
 			if err != nil {
 				return err
@@ -114,26 +227,50 @@ func (rule *Rule) Execute(input *ExecuteRuleInput) (err error) {
 		}
 		return nil
 	}
+	executeValues := func(values map[string]interface{}, component component.Component) error {
+		// get vars from variables while replacing interactsh urls
+		evaluatedValues, interactURLs := rule.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(values, baseValues), rule.options.Interactsh)
+		input.Values = generators.MergeMaps(values, evaluatedValues, baseValues, input.optionVars, rule.options.Constants)
+		// evaluate all vars with interactsh
+		input.Values, interactURLs = rule.evaluateVarsWithInteractsh(input.Values, interactURLs)
+		input.InteractURLs = interactURLs
+
+		if err := rule.executeRuleValues(input, component); err != nil {
+			if err != io.EOF {
+				gologger.Warning().Msgf("[%s] Could not execute rule: %s\n", rule.options.TemplateID, err)
+			}
+			return err
+		}
+		return nil
+	}
 mainLoop:
 	for _, component := range finalComponentList {
 		iterator := rule.generator.NewIterator()
+		input.sample = rule.newPayloadSample(component, iterator.Total())
+		// weighted payloads are collected and tried in the order of their weights
+		if len(rule.Weights) > 0 {
+			for _, values := range rule.weightedValues(iterator) {
+				if input.sample.done() {
+					continue mainLoop
+				}
+				if err := executeValues(values, component); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+			}
+			continue
+		}
 		for {
 			values, next := iterator.Value()
-			if !next {
+			if !next || input.sample.done() {
 				continue mainLoop
 			}
-			// get vars from variables while replacing interactsh urls
-			evaluatedValues, interactURLs := rule.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(values, baseValues), rule.options.Interactsh)
-			input.Values = generators.MergeMaps(values, evaluatedValues, baseValues, rule.options.Options.Vars.AsMap(), rule.options.Constants)
-			// evaluate all vars with interactsh
-			input.Values, interactURLs = rule.evaluateVarsWithInteractsh(input.Values, interactURLs)
-			input.InteractURLs = interactURLs
-
-			if err := rule.executeRuleValues(input, component); err != nil {
+			if err := executeValues(values, component); err != nil {
 				if err == io.EOF {
 					return nil
 				}
-				gologger.Warning().Msgf("[%s] Could not execute rule: %s\n", rule.options.TemplateID, err)
 				return err
 			}
 		}
@@ -177,11 +314,39 @@ func (rule *Rule) isInputURLValid(input *contextargs.Context) bool {
 	return err == nil
 }
 
-// executeRuleValues executes a rule with a set of values
+// executeRuleValues executes a rule with a set of values for
+// each of the iterations of the rule (if any)
 func (rule *Rule) executeRuleValues(input *ExecuteRuleInput, ruleComponent component.Component) error {
+	if rule.iterations == 0 {
+		return rule.executeRuleIteration(input, ruleComponent)
+	}
+	// each iteration tries the same sampled payloads
+	var position int
+	if input.sample != nil {
+		position = input.sample.position
+	}
+	for iteration := 1; iteration <= rule.iterations; iteration++ {
+		if input.sample != nil {
+			input.sample.position = position
+		}
+		input.Values = generators.MergeMaps(input.Values, map[string]interface{}{"iteration": iteration})
+		if err := rule.executeRuleIteration(input, ruleComponent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeRuleIteration executes a rule with a set of values
+func (rule *Rule) executeRuleIteration(input *ExecuteRuleInput, ruleComponent component.Component) error {
+	payloads := rule.payloadsFor(ruleComponent.Name())
+
 	// if we are only fuzzing values
-	if len(rule.Fuzz.Value) > 0 {
-		for _, value := range rule.Fuzz.Value {
+	if len(payloads.Value) > 0 {
+		for _, value := range payloads.Value {
+			if !input.sample.next() {
+				continue
+			}
 			if err := rule.executePartRule(input, ValueOrKeyValue{Value: value}, ruleComponent); err != nil {
 				if component.IsErrSetValue(err) {
 					// this are errors due to format restrictions
@@ -195,9 +360,14 @@ func (rule *Rule) executeRuleValues(input *ExecuteRuleInput, ruleComponent compo
 	}
 
 	// if we are fuzzing both keys and values
-	if rule.Fuzz.KV != nil {
+	if payloads.KV != nil {
 		var gotErr error
-		rule.Fuzz.KV.Iterate(func(key, value string) bool {
+		sampled := make(map[string]struct{})
+		payloads.KV.Iterate(func(key, value string) bool {
+			if !input.sample.next() {
+				return true
+			}
+			sampled[key] = struct{}{}
 			if err := rule.executePartRule(input, ValueOrKeyValue{Key: key, Value: value}, ruleComponent); err != nil {
 				if component.IsErrSetValue(err) {
 					// this are errors due to format restrictions
@@ -210,10 +380,14 @@ func (rule *Rule) executeRuleValues(input *ExecuteRuleInput, ruleComponent compo
 			return true
 		})
 		// if mode is multiple now build and execute it
-		if rule.modeType == multipleModeType {
-			rule.Fuzz.KV.Iterate(func(key, value string) bool {
+		if rule.modeType == multipleModeType && len(sampled) > 0 {
+			rule.newCanary(input)
+			payloads.KV.Iterate(func(key, value string) bool {
+				if _, ok := sampled[key]; !ok {
+					return true
+				}
 				var evaluated string
-				evaluated, input.InteractURLs = rule.executeEvaluate(input, key, "", value, input.InteractURLs)
+				evaluated, input.InteractURLs = rule.executeEvaluate(input, ruleComponent, "", value, input.InteractURLs)
 				if err := ruleComponent.SetValue(key, evaluated); err != nil {
 					return true
 				}
@@ -223,7 +397,7 @@ func (rule *Rule) executeRuleValues(input *ExecuteRuleInput, ruleComponent compo
 			if err != nil {
 				return err
 			}
-			if gotErr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent); gotErr != nil {
+			if gotErr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent, ""); gotErr != nil {
 				return gotErr
 			}
 		}
@@ -308,5 +482,78 @@ func (rule *Rule) Compile(generator *generators.PayloadGenerator, options *proto
 		}
 		rule.replaceRegex = compiled
 	}
+	if err := rule.compileComponentPayloads(); err != nil {
+		return err
+	}
+	if err := rule.appendGrammarPayloads(); err != nil {
+		return err
+	}
+	if rule.Sampling != nil {
+		if err := rule.Sampling.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile sampling")
+		}
+	}
+	if rule.EarlyAbort != nil {
+		if err := rule.EarlyAbort.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile early abort")
+		}
+	}
+	if rule.StatusFilter != nil {
+		if err := rule.StatusFilter.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile status filter")
+		}
+	}
+	if len(rule.Weights) > 0 {
+		rule.Fuzz = rule.applyWeights(rule.Fuzz)
+		for name, payloads := range rule.ComponentPayloads {
+			rule.ComponentPayloads[name] = rule.applyWeights(payloads)
+		}
+	}
+	if rule.Iterations != "" {
+		iterations, err := rule.resolveIterations()
+		if err != nil {
+			return errors.Wrap(err, "could not resolve iterations")
+		}
+		rule.iterations = iterations
+	}
+	if rule.MaxMatches < 0 {
+		return errors.Errorf("max-matches must be positive, got %d", rule.MaxMatches)
+	}
+	if err := rule.compileChain(); err != nil {
+		return err
+	}
+	if err := rule.compileJSONMutations(); err != nil {
+		return err
+	}
 	return nil
 }
+
+// maxIterations is the maximum number of iterations allowed for a rule
+const maxIterations = 1000
+
+// resolveIterations resolves the number of iterations of the rule
+// from scan-time variables and template constants
+func (rule *Rule) resolveIterations() (int, error) {
+	values := make(map[string]interface{})
+	if rule.options != nil {
+		values = generators.MergeMaps(values, rule.options.Constants)
+		if rule.options.Options != nil {
+			values = generators.MergeMaps(values, rule.options.Options.Vars.AsMap())
+		}
+	}
+	evaluated, err := expressions.Evaluate(rule.Iterations, values)
+	if err != nil {
+		return 0, err
+	}
+	if err := expressions.ContainsUnresolvedVariables(evaluated); err != nil {
+		return 0, errors.Wrap(err, "iterations reference undefined variables (pass them with -var)")
+	}
+	iterations, err := strconv.Atoi(strings.TrimSpace(evaluated))
+	if err != nil {
+		return 0, errors.Errorf("iterations must resolve to a number, got %q", evaluated)
+	}
+	if iterations <= 0 || iterations > maxIterations {
+		return 0, errors.Errorf("iterations must be between 1 and %d, got %d", maxIterations, iterations)
+	}
+	return iterations, nil
+}
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 )
@@ -26,9 +27,25 @@ type Rule struct {
 	//   Part is the part of request to fuzz.
 	//
 	//   query fuzzes the query part of url. More parts will be added later.
+	//
+	//   cookie fuzzes the value of each cookie of the Cookie header keeping the
+	//   other cookies unchanged, keys target cookies by name. The fuzzed cookie
+	//   is added to the metadata of results.
+	//
+	//   request-line fuzzes the method, target and version keys of the request
+	//   line. It requires raw-socket requests as the http client normalizes the
+	//   request line otherwise.
+	//
+	//   raw-path fuzzes the path of the request as written on the wire keeping
+	//   encoded separators (ex: %2f, %2e%2e) of the path and payloads verbatim,
+	//   whereas path decodes the path and encodes payloads. It requires raw-socket
+	//   requests, the path sent is exposed as sent_path.
 	// values:
 	//   - "query"
-	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=request"`
+	//   - "cookie"
+	//   - "request-line"
+	//   - "raw-path"
+	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=host,enum=request,enum=request-line,enum=raw-path"`
 	partType partType
 	// description: |
 	//   Mode is the mode of fuzzing to perform.
@@ -42,10 +59,16 @@ type Rule struct {
 
 	// description: |
 	//   Keys is the optional list of key named parameters to fuzz.
+	//
+	//   Filenames and content types of multipart file parts are keyed by
+	//   the field name suffixed with :filename and :content-type.
 	// examples:
 	//   - name: Examples of keys
 	//     value: >
 	//       []string{"url", "file", "host"}
+	//   - name: Filename and content type of multipart file upload
+	//     value: >
+	//       []string{"avatar:filename", "avatar:content-type"}
 	Keys    []string `yaml:"keys,omitempty" json:"keys,omitempty" jsonschema:"title=keys of parameters to fuzz,description=Keys of parameters to fuzz"`
 	keysMap map[string]struct{}
 	// description: |
@@ -64,6 +87,16 @@ type Rule struct {
 	//       []string{"https?://.*"}
 	ValuesRegex []string `yaml:"values,omitempty" json:"values,omitempty" jsonschema:"title=values regex to fuzz,description=Regex of parameter values to fuzz"`
 	valuesRegex []*regexp.Regexp
+	// description: |
+	//   ParamTypes is the optional list of schema types of parameters to fuzz.
+	//
+	//   Types are defined by api specification inputs (ex: openapi), parameters
+	//   without a schema are not fuzzed if specified.
+	// examples:
+	//   - name: Fuzz string and integer parameters
+	//     value: >
+	//       []string{"string", "integer"}
+	ParamTypes []string `yaml:"param-types,omitempty" json:"param-types,omitempty" jsonschema:"title=schema types of parameters to fuzz,description=Schema types of parameters to fuzz defined by api specification inputs"`
 
 	// description: |
 	//   Fuzz is the list of payloads to perform substitutions with.
@@ -76,6 +109,19 @@ type Rule struct {
 	//       x-header: 2
 	Fuzz SliceOrMapSlice `yaml:"fuzz,omitempty" json:"fuzz,omitempty" jsonschema:"title=payloads of fuzz rule,description=Payloads to perform fuzzing substitutions with"`
 	// description: |
+	//   ComponentPayloads maps components (query, header, path, body, cookie)
+	//   of the rule to the payloads used for them, pairing payloads with the
+	//   components they are relevant for in a single rule (ex: with part request).
+	//
+	//   Payloads of a listed component take precedence over the default fuzz
+	//   payloads which are used for the other components. Components which are
+	//   not listed are not fuzzed if the rule has no default fuzz payloads.
+	// examples:
+	//   - name: Traversal payloads for path and sqli payloads for query
+	//     value: >
+	//       map[string]SliceOrMapSlice{"path": {Value: []string{"../../etc/passwd"}}, "query": {Value: []string{"' OR 1=1-- -"}}}
+	ComponentPayloads map[string]SliceOrMapSlice `yaml:"component-payloads,omitempty" json:"component-payloads,omitempty" jsonschema:"title=payloads per component,description=Components of the rule mapped to the payloads used for them instead of the default fuzz payloads"`
+	// description: |
 	//  replace-regex is regex for regex-replace rule type
 	//  it is only required for replace-regex rule type
 	// examples:
@@ -83,8 +129,145 @@ type Rule struct {
 	//     replace-regex: "https?://.*"
 	ReplaceRegex string         `yaml:"replace-regex,omitempty" json:"replace-regex,omitempty" jsonschema:"title=replace regex of rule,description=Regex for regex-replace rule type"`
 	replaceRegex *regexp.Regexp `yaml:"-" json:"-"`
-	options      *protocols.ExecutorOptions
-	generator    *generators.PayloadGenerator
+	// description: |
+	//   SNI is the optional TLS server name used for fuzzed requests.
+	//
+	//   By default the hostname of the dialed target is used. sent-host uses
+	//   the (fuzzed) host header value, any other value is used as is.
+	// examples:
+	//   - value: "\"sent-host\""
+	SNI string `yaml:"sni,omitempty" json:"sni,omitempty" jsonschema:"title=tls sni for fuzzed requests,description=TLS server name used for fuzzed requests"`
+	// description: |
+	//   Grammar generates structured payloads from a BNF-like grammar (or a
+	//   builtin sql, json or xml grammar) appended to the fuzz payloads.
+	//
+	//   Payloads are random distinct expansions of the start rule bounded by
+	//   max, a seed makes them reproducible.
+	// examples:
+	//   - name: 50 payloads of the builtin json grammar
+	//     value: >
+	//       &Grammar{Builtin: "json", Max: 50, Seed: 1337}
+	Grammar *Grammar `yaml:"grammar,omitempty" json:"grammar,omitempty" jsonschema:"title=grammar of generated payloads,description=BNF-like grammar generating structured payloads appended to fuzz payloads"`
+	// description: |
+	//   Sampling bounds the payloads used by the rule to a subset (first N,
+	//   random N or every nth) for quick triage runs. It is applied once to
+	//   the payloads of each component in the order they are tried (after
+	//   weights).
+	// examples:
+	//   - name: Random 50 payloads with reproducible seed
+	//     value: >
+	//       &Sampling{Max: 50, Strategy: "random", Seed: 1337}
+	Sampling *Sampling `yaml:"sampling,omitempty" json:"sampling,omitempty" jsonschema:"title=payload sampling,description=Sampling bounds the payloads used by the rule to a subset"`
+	// description: |
+	//   EarlyAbort skips the remaining payloads of the rule for a parameter
+	//   of an input (a component in multiple mode) once a number of consecutive
+	//   responses are indistinguishable (by status, length or body similarity),
+	//   the payloads proving ineffective.
+	//
+	//   Parameters returning a matched or distinguishable response are fuzzed
+	//   with all payloads of the rule.
+	// examples:
+	//   - name: Skip remaining payloads after 10 similar responses
+	//     value: >
+	//       &EarlyAbort{After: 10, Metric: "similarity", Threshold: 0.9}
+	EarlyAbort *EarlyAbort `yaml:"early-abort,omitempty" json:"early-abort,omitempty" jsonschema:"title=early abort of ineffective payloads,description=Skip remaining payloads of the rule after consecutive indistinguishable responses"`
+	// description: |
+	//   StatusFilter limits the rule to responses with interesting status
+	//   codes or classes (ex: 5xx, 403), cutting noise of endpoints returning
+	//   the same status for everything.
+	//
+	//   Matchers are not evaluated for fuzzed responses with other status
+	//   codes (interactsh matchers excepted) and skip-component skips the
+	//   remaining payloads of their component. With baseline the rule is
+	//   only run for inputs whose unmodified request returns a status of the set.
+	// examples:
+	//   - name: Only match server errors and skip components returning others
+	//     value: >
+	//       &StatusFilter{Status: []string{"5xx"}, SkipComponent: true}
+	StatusFilter *StatusFilter `yaml:"status-filter,omitempty" json:"status-filter,omitempty" jsonschema:"title=status filter of rule,description=Limit matchers and payloads of the rule to responses with status codes or classes"`
+	// description: |
+	//   Weights maps payload values to priority weights. Payloads with higher
+	//   weights are tried first so high-signal payloads are sent before
+	//   stop-at-first-match or max fuzz requests cut off the rule.
+	//
+	//   Payloads without a weight default to 0 and keep their file order.
+	// examples:
+	//   - name: Try time based and oob payloads first
+	//     value: >
+	//       map[string]int{"{{interactsh-url}}": 10, "' AND SLEEP(5)-- -": 5}
+	Weights map[string]int `yaml:"weights,omitempty" json:"weights,omitempty" jsonschema:"title=payload priority weights,description=Payload values mapped to priority weights tried in descending order"`
+	// description: |
+	//   Iterations is the number of times fuzz payloads are generated, resolved
+	//   from scan-time variables (ex: -var depth=5) when the template is loaded.
+	//
+	//   The current iteration (starting at 1) is available to payloads
+	//   as `iteration` variable.
+	// examples:
+	//   - name: Traversal payloads scaled by depth variable
+	//     value: "\"{{depth}}\""
+	Iterations string `yaml:"iterations,omitempty" json:"iterations,omitempty" jsonschema:"title=fuzz payload iterations,description=Number of times payloads are generated resolved from scan-time variables"`
+	// description: |
+	//   MaxMatches is the maximum number of matches collected for the rule
+	//   per input after which the rule stops while other rules continue.
+	//
+	//   Unlike stop-at-first-match which stops all rules, it allows collecting
+	//   up to N distinct matches (ex: vulnerable parameters) for each rule.
+	// examples:
+	//   - name: Stop the rule after 5 matches
+	//     value: 5
+	MaxMatches int `yaml:"max-matches,omitempty" json:"max-matches,omitempty" jsonschema:"title=maximum matches of rule,description=Maximum number of matches per input after which the rule stops"`
+	// description: |
+	//   Canary appends a unique random canary to the payloads of each request
+	//   of the rule to detect reflection of payloads with high confidence.
+	//
+	//   The canary of a request is available as `canary` variable to payloads
+	//   (placing it explicitly instead of appending it) and matchers.
+	//
+	//   Reflections of the canary in the response are classified by their
+	//   html/js context (html-text, html-attribute, url, script, script-string
+	//   etc.) exposed as `reflection_context` (first reflection),
+	//   `reflection_contexts` and indexed `reflection_context0..N` variables.
+	// examples:
+	//   - name: Detect reflection of payloads using canary
+	//     value: true
+	Canary bool `yaml:"canary,omitempty" json:"canary,omitempty" jsonschema:"title=append canary to payloads,description=Append a unique random canary to payloads of each request to detect reflection"`
+	// description: |
+	//   Chain is the list of named extractors run on the response of each
+	//   request of the rule. Extracted values are available by name to the
+	//   payloads of the following requests (ex: an incrementing token).
+	//
+	//   Requests of a chained rule are sent one at a time in payload order
+	//   (after weights and sampling), each payload being built only after the
+	//   response of the previous request was received. The first value of
+	//   an extractor is used and values not found keep their previous value.
+	// examples:
+	//   - name: Feed the token of each response to the next payload
+	//     value: >
+	//       []*extractors.Extractor{{Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor}, Name: "token", Regex: []string{"token=([a-f0-9]+)"}, RegexGroup: 1}}
+	Chain []*extractors.Extractor `yaml:"chain,omitempty" json:"chain,omitempty" jsonschema:"title=chained extractors of rule,description=Named extractors whose values are fed to the next payloads of the rule"`
+	// description: |
+	//   JSONMutations is the list of structural mutations applied with each
+	//   payload to the targeted keys of json bodies, each sent as an additional
+	//   request to find parameter pollution and type confusion bugs.
+	//
+	//   wrap-array wraps the payload in an array, duplicate-key sends the key
+	//   twice with the original value and the payload, change-type sends the
+	//   payload with another type than the original value (ex: a number or
+	//   object for a string). The applied mutation is added to the metadata
+	//   of results as json_mutation.
+	//
+	//   Mutations only apply to json bodies in single mode, other bodies are
+	//   fuzzed without them.
+	// values:
+	//   - "wrap-array"
+	//   - "duplicate-key"
+	//   - "change-type"
+	JSONMutations []string `yaml:"json-mutations,omitempty" json:"json-mutations,omitempty" jsonschema:"title=json body mutations,description=Structural mutations applied with payloads to json body keys,enum=wrap-array,enum=duplicate-key,enum=change-type"`
+	jsonMutations []jsonMutation
+
+	iterations int
+	options    *protocols.ExecutorOptions
+	generator  *generators.PayloadGenerator
 }
 
 // ruleType is the type of rule enum declaration
@@ -116,6 +299,9 @@ const (
 	bodyPartType
 	cookiePartType
 	requestPartType
+	hostPartType
+	requestLinePartType
+	rawPathPartType
 )
 
 var stringToPartType = map[string]partType{
@@ -125,6 +311,11 @@ var stringToPartType = map[string]partType{
 	"body":    bodyPartType,
 	"cookie":  cookiePartType,
 	"request": requestPartType, // request means all request parts
+	"host":    hostPartType,    // host only rewrites the sent host header
+	// request-line fuzzes the method, target and version (requires raw-socket)
+	"request-line": requestLinePartType,
+	// raw-path fuzzes the path written verbatim on the wire (requires raw-socket)
+	"raw-path": rawPathPartType,
 }
 
 // modeType is the mode of rule enum declaration
@@ -3,6 +3,8 @@ package fuzz
 import (
 	"testing"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +37,21 @@ func TestRuleMatchKeyOrValue(t *testing.T) {
 		require.False(t, result, "could not get correct result")
 	})
 }
+
+func TestRuleIterations(t *testing.T) {
+	options := &types.Options{}
+	require.NoError(t, options.Vars.Set("depth=5"))
+	executorOpts := &protocols.ExecutorOptions{Options: options}
+
+	rule := &Rule{Iterations: "{{depth}}"}
+	require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+	require.Equal(t, 5, rule.iterations, "could not resolve iterations")
+
+	rule = &Rule{Iterations: "3"}
+	require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+	require.Equal(t, 3, rule.iterations, "could not resolve static iterations")
+
+	rule = &Rule{Iterations: "{{missing}}"}
+	err := rule.Compile(nil, executorOpts)
+	require.ErrorContains(t, err, "undefined variables", "could compile rule with undefined variable")
+}
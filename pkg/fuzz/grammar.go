@@ -0,0 +1,301 @@
+package fuzz
+
+import (
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultGrammarMax is the default number of payloads generated by grammars
+	defaultGrammarMax = 100
+	// maxGrammarMax is the maximum number of payloads generated by grammars
+	maxGrammarMax = 10000
+	// defaultGrammarMaxDepth is the default depth after which grammars are
+	// expanded by their shortest alternatives
+	defaultGrammarMaxDepth = 8
+	// grammarAttemptsFactor bounds the expansions tried per generated payload
+	// as small grammars produce less distinct payloads than requested
+	grammarAttemptsFactor = 20
+)
+
+// grammarReferenceRegex matches <rule> references in grammar alternatives
+var grammarReferenceRegex = regexp.MustCompile(`<([a-zA-Z0-9_-]+)>`)
+
+// Grammar contains configuration for generating structured fuzzing
+// payloads from a BNF-like grammar.
+type Grammar struct {
+	// description: |
+	//   Builtin is the name of a prebuilt grammar to expand.
+	//
+	//   Rules of the template are merged with the rules of the builtin
+	//   grammar, replacing builtin rules of the same name.
+	// values:
+	//   - "sql"
+	//   - "json"
+	//   - "xml"
+	Builtin string `yaml:"builtin,omitempty" json:"builtin,omitempty" jsonschema:"title=builtin grammar,description=Name of a prebuilt grammar to expand,enum=sql,enum=json,enum=xml"`
+	// description: |
+	//   Rules maps rule names to their alternatives.
+	//
+	//   Alternatives reference other rules as <name>, text not referencing
+	//   a rule of the grammar is used as is (ex: xml tags).
+	// examples:
+	//   - name: Numeric comparisons
+	//     value: >
+	//       map[string][]string{"start": {"<num><op><num>"}, "op": {"=", "<", ">"}, "num": {"0", "-1", "<num><num>"}}
+	Rules map[string][]string `yaml:"rules,omitempty" json:"rules,omitempty" jsonschema:"title=grammar rules,description=Rule names mapped to their alternatives referencing rules as <name>"`
+	// description: |
+	//   Start is the name of the rule payloads are expanded from. Default is start.
+	Start string `yaml:"start,omitempty" json:"start,omitempty" jsonschema:"title=start rule,description=Name of the rule payloads are expanded from"`
+	// description: |
+	//   Max is the maximum number of distinct payloads generated. Default is 100.
+	// examples:
+	//   - value: "50"
+	Max int `yaml:"max,omitempty" json:"max,omitempty" jsonschema:"title=maximum payloads,description=Maximum number of distinct payloads generated"`
+	// description: |
+	//   MaxDepth is the depth of expansion after which the shortest
+	//   alternatives of rules are used to bound payloads. Default is 8.
+	MaxDepth int `yaml:"max-depth,omitempty" json:"max-depth,omitempty" jsonschema:"title=maximum expansion depth,description=Depth of expansion after which the shortest alternatives of rules are used"`
+	// description: |
+	//   Seed is the seed of expansion to make generated payloads reproducible.
+	//   A random seed is used if not specified.
+	// examples:
+	//   - value: "1337"
+	Seed int64 `yaml:"seed,omitempty" json:"seed,omitempty" jsonschema:"title=grammar seed,description=Seed for reproducible payload generation"`
+
+	rules map[string][]grammarAlternative
+	// depths is the minimum expansion depth of rules
+	depths map[string]int
+}
+
+// grammarAlternative is an alternative of a rule split into text and references
+type grammarAlternative struct {
+	tokens []grammarToken
+	depth  int
+}
+
+// grammarToken is a text or a reference to a rule of an alternative
+type grammarToken struct {
+	text      string
+	reference string
+}
+
+// Compile validates the grammar and compiles its rules
+func (g *Grammar) Compile() error {
+	if g.Start == "" {
+		g.Start = "start"
+	}
+	if g.Max == 0 {
+		g.Max = defaultGrammarMax
+	}
+	if g.Max < 0 || g.Max > maxGrammarMax {
+		return errors.Errorf("grammar max must be between 1 and %d", maxGrammarMax)
+	}
+	if g.MaxDepth == 0 {
+		g.MaxDepth = defaultGrammarMaxDepth
+	}
+	if g.MaxDepth < 0 {
+		return errors.Errorf("grammar max-depth must not be negative")
+	}
+	if g.Seed == 0 {
+		g.Seed = time.Now().UnixNano()
+	}
+
+	definitions := make(map[string][]string)
+	if g.Builtin != "" {
+		builtin, ok := builtinGrammars[strings.ToLower(g.Builtin)]
+		if !ok {
+			return errors.Errorf("invalid builtin grammar specified: %s", g.Builtin)
+		}
+		for name, alternatives := range builtin {
+			definitions[name] = alternatives
+		}
+	}
+	for name, alternatives := range g.Rules {
+		definitions[name] = alternatives
+	}
+	if _, ok := definitions[g.Start]; !ok {
+		return errors.Errorf("grammar start rule %s is not defined", g.Start)
+	}
+
+	g.rules = make(map[string][]grammarAlternative, len(definitions))
+	for name, alternatives := range definitions {
+		if len(alternatives) == 0 {
+			return errors.Errorf("grammar rule %s has no alternatives", name)
+		}
+		for _, alternative := range alternatives {
+			g.rules[name] = append(g.rules[name], parseGrammarAlternative(alternative, definitions))
+		}
+	}
+	return g.computeDepths()
+}
+
+// parseGrammarAlternative splits the alternative into text and references
+// to rules defined by the grammar
+func parseGrammarAlternative(value string, definitions map[string][]string) grammarAlternative {
+	var alternative grammarAlternative
+	var text strings.Builder
+	last := 0
+	for _, match := range grammarReferenceRegex.FindAllStringSubmatchIndex(value, -1) {
+		name := value[match[2]:match[3]]
+		if _, ok := definitions[name]; !ok {
+			continue
+		}
+		text.WriteString(value[last:match[0]])
+		if text.Len() > 0 {
+			alternative.tokens = append(alternative.tokens, grammarToken{text: text.String()})
+			text.Reset()
+		}
+		alternative.tokens = append(alternative.tokens, grammarToken{reference: name})
+		last = match[1]
+	}
+	text.WriteString(value[last:])
+	if text.Len() > 0 {
+		alternative.tokens = append(alternative.tokens, grammarToken{text: text.String()})
+	}
+	return alternative
+}
+
+// computeDepths computes the minimum expansion depth of rules and their
+// alternatives, failing for rules which never terminate
+func (g *Grammar) computeDepths() error {
+	g.depths = make(map[string]int, len(g.rules))
+	for name := range g.rules {
+		g.depths[name] = math.MaxInt
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, alternatives := range g.rules {
+			for i, alternative := range alternatives {
+				depth := 1
+				for _, token := range alternative.tokens {
+					if token.reference == "" {
+						continue
+					}
+					if referenced := g.depths[token.reference]; referenced == math.MaxInt {
+						depth = math.MaxInt
+						break
+					} else if referenced+1 > depth {
+						depth = referenced + 1
+					}
+				}
+				alternatives[i].depth = depth
+				if depth < g.depths[name] {
+					g.depths[name] = depth
+					changed = true
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(g.depths))
+	for name, depth := range g.depths {
+		if depth == math.MaxInt {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return errors.Errorf("grammar rules never terminate: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Generate returns the distinct payloads expanded from the start rule
+func (g *Grammar) Generate() []string {
+	random := rand.New(rand.NewSource(g.Seed))
+	seen := make(map[string]struct{}, g.Max)
+	payloads := make([]string, 0, g.Max)
+	for attempts := g.Max * grammarAttemptsFactor; attempts > 0 && len(payloads) < g.Max; attempts-- {
+		var builder strings.Builder
+		g.expand(random, g.Start, 0, &builder)
+		payload := builder.String()
+		if _, ok := seen[payload]; ok {
+			continue
+		}
+		seen[payload] = struct{}{}
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+// expand writes a random expansion of the rule to the builder. Beyond the
+// maximum depth only alternatives of the minimum depth of the rule are used.
+func (g *Grammar) expand(random *rand.Rand, name string, depth int, builder *strings.Builder) {
+	alternatives := g.rules[name]
+	if depth >= g.MaxDepth {
+		shortest := make([]grammarAlternative, 0, len(alternatives))
+		for _, alternative := range alternatives {
+			if alternative.depth == g.depths[name] {
+				shortest = append(shortest, alternative)
+			}
+		}
+		alternatives = shortest
+	}
+	alternative := alternatives[random.Intn(len(alternatives))]
+	for _, token := range alternative.tokens {
+		if token.reference == "" {
+			builder.WriteString(token.text)
+			continue
+		}
+		g.expand(random, token.reference, depth+1, builder)
+	}
+}
+
+// appendGrammarPayloads appends the payloads generated by the grammar
+// of the rule to its fuzz values
+func (rule *Rule) appendGrammarPayloads() error {
+	if rule.Grammar == nil {
+		return nil
+	}
+	if rule.Fuzz.KV != nil {
+		return errors.Errorf("grammar can not be used with key-value fuzz payloads")
+	}
+	if err := rule.Grammar.Compile(); err != nil {
+		return errors.Wrap(err, "could not compile grammar")
+	}
+	rule.Fuzz.Value = append(rule.Fuzz.Value, rule.Grammar.Generate()...)
+	return nil
+}
+
+// builtinGrammars are the prebuilt grammars of structured inputs
+var builtinGrammars = map[string]map[string][]string{
+	"sql": {
+		"start":     {"<injection>"},
+		"injection": {"<quote><space><logic><space><condition><comment>", "<quote><space>UNION<space>SELECT<space><columns><comment>", "<quote>;<space><statement><comment>", "<number><space><logic><space><condition>"},
+		"quote":     {"'", "\"", "')", "\")", "`", ""},
+		"space":     {" ", "/**/", "\t"},
+		"logic":     {"OR", "AND", "||", "&&"},
+		"condition": {"<number>=<number>", "'<word>'='<word>'", "SLEEP(<number>)", "<number>", "(<condition>)"},
+		"columns":   {"NULL", "NULL,<columns>", "@@version", "version()"},
+		"statement": {"SELECT <number>", "WAITFOR DELAY '0:0:<number>'", "SELECT pg_sleep(<number>)"},
+		"comment":   {"-- -", "#", "/*", ""},
+		"number":    {"0", "1", "2", "5"},
+		"word":      {"a", "x"},
+	},
+	"json": {
+		"start":    {"<value>"},
+		"value":    {"<object>", "<array>", "<string>", "<number>", "true", "false", "null"},
+		"object":   {"{}", "{<members>}", "{<members>,}"},
+		"members":  {"<pair>", "<pair>,<members>"},
+		"pair":     {"<string>:<value>"},
+		"array":    {"[]", "[<elements>]", "[<elements>,]"},
+		"elements": {"<value>", "<value>,<elements>"},
+		"string":   {`""`, `"a"`, `"__proto__"`, `"constructor"`, `"\u0000"`, `"\ud800"`, `"\"`},
+		"number":   {"0", "-0", "-1", "1e308", "1e-999", "9007199254740993", "0.1", "01", "NaN"},
+	},
+	"xml": {
+		"start":     {"<prolog><element>"},
+		"prolog":    {"", `<?xml version="1.0"?>`, `<?xml version="1.0" encoding="UTF-8"?>`, `<?xml version="1.1"?>`},
+		"element":   {"<<name>/>", "<<name> <attribute>/>", "<<name>><content></<name>>", "<<name> <attribute>><content></<name>>"},
+		"content":   {"", "<text>", "<element>", "<element><content>", "<![CDATA[<text>]]>", "<!--<text>-->"},
+		"attribute": {`<name>="<text>"`, `<name>='<text>'`, `xmlns:<name>="<text>"`},
+		"name":      {"a", "root", "x:y", "_"},
+		"text":      {"a", "&amp;", "&lt;", "]]>", "&#0;", "&#x10FFFF;"},
+	},
+}
@@ -0,0 +1,66 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrammar(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		grammar := &Grammar{Rules: map[string][]string{
+			"start": {"<num><op><num>"},
+			"op":    {"=", "<"},
+			"num":   {"0", "1"},
+		}, Max: 100, Seed: 1337}
+		require.NoError(t, grammar.Compile(), "could not compile grammar")
+		payloads := grammar.Generate()
+		require.ElementsMatch(t, []string{"0=0", "0=1", "1=0", "1=1", "0<0", "0<1", "1<0", "1<1"}, payloads, "could not generate distinct payloads")
+	})
+	t.Run("reproducible", func(t *testing.T) {
+		first := &Grammar{Builtin: "sql", Max: 20, Seed: 42}
+		require.NoError(t, first.Compile(), "could not compile grammar")
+		second := &Grammar{Builtin: "sql", Max: 20, Seed: 42}
+		require.NoError(t, second.Compile(), "could not compile grammar")
+
+		payloads := first.Generate()
+		require.Len(t, payloads, 20)
+		require.Equal(t, payloads, second.Generate(), "could not generate reproducible payloads")
+	})
+	t.Run("bounded-depth", func(t *testing.T) {
+		grammar := &Grammar{Builtin: "json", Rules: map[string][]string{"start": {"<array>"}, "string": {`"a"`}}, Max: 50, MaxDepth: 4, Seed: 7}
+		require.NoError(t, grammar.Compile(), "could not compile grammar")
+		for _, payload := range grammar.Generate() {
+			require.True(t, strings.HasPrefix(payload, "["), "could not use overridden start rule")
+			require.LessOrEqual(t, strings.Count(payload, "["), 4, "could not bound expansion depth")
+		}
+	})
+	t.Run("xml-tags", func(t *testing.T) {
+		grammar := &Grammar{Rules: map[string][]string{"start": {"<a><name></a>"}, "name": {"x"}}, Max: 1, Seed: 1}
+		require.NoError(t, grammar.Compile(), "could not compile grammar")
+		require.Equal(t, []string{"<a>x</a>"}, grammar.Generate(), "could not keep undefined references as text")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, (&Grammar{Builtin: "yaml"}).Compile(), "could compile unknown builtin grammar")
+		require.Error(t, (&Grammar{Rules: map[string][]string{"value": {"a"}}}).Compile(), "could compile grammar without start rule")
+		require.Error(t, (&Grammar{Rules: map[string][]string{"start": {"<start>a"}}}).Compile(), "could compile non terminating grammar")
+		require.Error(t, (&Grammar{Builtin: "sql", Max: maxGrammarMax + 1}).Compile(), "could compile grammar above max")
+	})
+}
+
+func TestRuleGrammarPayloads(t *testing.T) {
+	rule := &Rule{Fuzz: SliceOrMapSlice{Value: []string{"static"}}, Grammar: &Grammar{Builtin: "json", Max: 10, Seed: 1337}}
+	require.NoError(t, rule.Compile(nil, nil), "could not compile rule")
+	require.Len(t, rule.Fuzz.Value, 11)
+	require.Equal(t, "static", rule.Fuzz.Value[0], "could not keep static payloads")
+
+	valid := 0
+	for _, payload := range rule.Fuzz.Value[1:] {
+		if json.Valid([]byte(payload)) {
+			valid++
+		}
+	}
+	require.Positive(t, valid, "could not generate json payloads")
+}
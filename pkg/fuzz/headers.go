@@ -0,0 +1,53 @@
+package fuzz
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// InjectedHeaders returns response headers of a fuzzed request which are
+// not present in the baseline response and are attributable to the payload
+// (both header name and value appear in the url decoded fuzzed request).
+//
+// Header names are lowercased and values trimmed before comparison, and
+// the returned headers are sorted in `name: value` format.
+func InjectedHeaders(baseline, fuzzed http.Header, request string) []string {
+	decoded := strings.ToLower(request)
+	if unescaped, err := url.QueryUnescape(decoded); err == nil {
+		decoded = decoded + "\n" + unescaped
+	}
+
+	known := make(map[string]struct{})
+	for name, values := range baseline {
+		for _, value := range values {
+			known[normalizeHeader(name, value)] = struct{}{}
+		}
+	}
+
+	var injected []string
+	for name, values := range fuzzed {
+		lowerName := strings.ToLower(name)
+		if !strings.Contains(decoded, lowerName) {
+			continue
+		}
+		for _, value := range values {
+			header := normalizeHeader(name, value)
+			if _, ok := known[header]; ok {
+				continue
+			}
+			if trimmed := strings.TrimSpace(value); trimmed != "" && !strings.Contains(decoded, strings.ToLower(trimmed)) {
+				continue
+			}
+			injected = append(injected, header)
+		}
+	}
+	sort.Strings(injected)
+	return injected
+}
+
+// normalizeHeader returns normalized `name: value` representation of a header
+func normalizeHeader(name, value string) string {
+	return strings.ToLower(strings.TrimSpace(name)) + ": " + strings.TrimSpace(value)
+}
@@ -0,0 +1,28 @@
+package fuzz
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectedHeaders(t *testing.T) {
+	baseline := http.Header{
+		"Content-Type": {"text/html"},
+		"Set-Cookie":   {"session=abc"},
+	}
+	fuzzed := http.Header{
+		"Content-Type": {"text/html"},
+		"Set-Cookie":   {"session=abc", "injected=nuclei"},
+		"X-Injected":   {"nuclei"},
+		"Date":         {"Mon, 01 Jan 2024 00:00:00 GMT"},
+	}
+	request := "GET /?q=%0d%0aX-Injected:%20nuclei%0d%0aSet-Cookie:%20injected=nuclei HTTP/1.1\r\nHost: example.com\r\n\r\n"
+
+	got := InjectedHeaders(baseline, fuzzed, request)
+	require.Equal(t, []string{"set-cookie: injected=nuclei", "x-injected: nuclei"}, got, "could not get injected headers")
+
+	got = InjectedHeaders(baseline, fuzzed, "GET /?q=test HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	require.Empty(t, got, "got injected headers not attributable to payload")
+}
@@ -0,0 +1,89 @@
+package fuzz
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/inputvars"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleInputVars(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vars.json")
+	err := os.WriteFile(file, []byte(`{"https://example.com/?a=1": {"token": "secret"}}`), 0600)
+	require.NoError(t, err, "could not write input vars")
+	store, err := inputvars.New(file)
+	require.NoError(t, err, "could not load input vars")
+
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions(), InputVars: store}
+	rule := &Rule{Part: "query", Type: "replace", Mode: "single", Fuzz: SliceOrMapSlice{Value: []string{"{{token}}"}}}
+	require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+	baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/?a=1", nil)
+	require.NoError(t, err, "could not create base request")
+
+	var generated []GeneratedRequest
+	err = rule.Execute(&ExecuteRuleInput{
+		Input:       contextargs.NewWithInput(context.Background(), "https://example.com/?a=1"),
+		BaseRequest: baseRequest,
+		Callback: func(gr GeneratedRequest) bool {
+			generated = append(generated, gr)
+			return true
+		},
+	})
+	require.NoError(t, err, "could not execute rule")
+	require.Len(t, generated, 1, "could not generate requests")
+	require.Equal(t, "secret", generated[0].Request.URL.Query().Get("a"), "could not resolve input variable in payload")
+	require.Equal(t, "secret", generated[0].DynamicValues["token"], "could not expose input variable")
+}
+
+func TestRuleDecodePayload(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+	decodeHex := func(payload string) (string, error) {
+		decoded, err := hex.DecodeString(payload)
+		return string(decoded), err
+	}
+	execute := func(part string, baseRequest *retryablehttp.Request) *retryablehttp.Request {
+		rule := &Rule{Part: part, Type: "replace", Mode: "single", Fuzz: SliceOrMapSlice{Value: []string{"00ff41"}}}
+		require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+		var generated []GeneratedRequest
+		err := rule.Execute(&ExecuteRuleInput{
+			Input:         contextargs.NewWithInput(context.Background(), baseRequest.URL.String()),
+			BaseRequest:   baseRequest,
+			DecodePayload: decodeHex,
+			Callback: func(gr GeneratedRequest) bool {
+				generated = append(generated, gr)
+				return true
+			},
+		})
+		require.NoError(t, err, "could not execute rule")
+		require.Len(t, generated, 1, "could not generate requests")
+		return generated[0].Request
+	}
+
+	baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/", nil)
+	require.NoError(t, err, "could not create base request")
+	baseRequest.Header.Set("X-Value", "1")
+	require.Equal(t, "\x00\xffA", execute("header", baseRequest).Header.Get("X-Value"), "could not decode header payload to raw bytes")
+
+	baseRequest, err = retryablehttp.NewRequest("GET", "https://example.com/?a=1", nil)
+	require.NoError(t, err, "could not create base request")
+	require.Equal(t, "a=%00%FFA", execute("query", baseRequest).URL.RawQuery, "could not encode raw bytes of query payload")
+
+	baseRequest, err = retryablehttp.NewRequest("POST", "https://example.com/", strings.NewReader("a=1"))
+	require.NoError(t, err, "could not create base request")
+	baseRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := io.ReadAll(execute("body", baseRequest).Body)
+	require.NoError(t, err, "could not read body")
+	require.Equal(t, "a=%00%FFA", string(body), "could not encode raw bytes of form payload")
+}
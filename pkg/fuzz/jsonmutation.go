@@ -0,0 +1,196 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
+	readerutil "github.com/projectdiscovery/utils/reader"
+	"github.com/rs/xid"
+)
+
+// jsonMutation is a structural mutation of a json body parameter
+type jsonMutation string
+
+const (
+	// wrapArrayJSONMutation sends the payload wrapped in an array (ex: {"id":["1"]})
+	wrapArrayJSONMutation jsonMutation = "wrap-array"
+	// duplicateKeyJSONMutation sends the key twice, with the original value
+	// first and the payload second (ex: {"id":1,"id":"2"})
+	duplicateKeyJSONMutation jsonMutation = "duplicate-key"
+	// changeTypeJSONMutation sends the payload with a type other than the
+	// type of the original value (ex: {"id":"1"} => {"id":1})
+	changeTypeJSONMutation jsonMutation = "change-type"
+)
+
+var stringToJSONMutation = map[string]jsonMutation{
+	"wrap-array":    wrapArrayJSONMutation,
+	"duplicate-key": duplicateKeyJSONMutation,
+	"change-type":   changeTypeJSONMutation,
+}
+
+// jsonMutationConfig decodes numbers as json.Number and sorts keys so that
+// mutated bodies are encoded in a stable order
+var jsonMutationConfig = jsoniter.Config{UseNumber: true, SortMapKeys: true}.Froze()
+
+// compileJSONMutations validates the json mutations of the rule
+func (rule *Rule) compileJSONMutations() error {
+	rule.jsonMutations = nil
+	for _, name := range rule.JSONMutations {
+		mutation, ok := stringToJSONMutation[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return errors.Errorf("invalid json mutation specified: %s", name)
+		}
+		rule.jsonMutations = append(rule.jsonMutations, mutation)
+	}
+	return nil
+}
+
+// executeJSONMutations sends a request for each json mutation of the rule
+// applied with the payload to the key of a json body. Bodies which are not
+// json (or fail to decode) are fuzzed without mutations.
+func (rule *Rule) executeJSONMutations(input *ExecuteRuleInput, ruleComponent component.Component, key string, original interface{}, payload string) error {
+	if len(rule.jsonMutations) == 0 || rule.modeType != singleModeType {
+		return nil
+	}
+	body, ok := ruleComponent.(*component.Body)
+	if !ok || body.DataFormat() != dataformat.JSONDataFormat {
+		return nil
+	}
+	baseReq, err := ruleComponent.Rebuild()
+	if err != nil {
+		return err
+	}
+	data, err := baseReq.BodyBytes()
+	if err != nil {
+		return err
+	}
+	xxe := input.xxe
+	for _, mutation := range rule.jsonMutations {
+		mutated, err := mutateJSON(data, strings.Split(key, "~"), original, payload, mutation)
+		if err != nil {
+			gologger.Verbose().Msgf("fuzz: skipping json mutation %s of %s: %s\n", mutation, key, err)
+			continue
+		}
+		if mutated == nil {
+			// mutation is not applicable to the value
+			continue
+		}
+		req := baseReq.Clone(baseReq.Context())
+		reader, err := readerutil.NewReusableReadCloser(mutated)
+		if err != nil {
+			return err
+		}
+		req.Body = reader
+		req.ContentLength = int64(len(mutated))
+		req.Header.Set("Content-Length", strconv.Itoa(len(mutated)))
+
+		input.xxe = xxe
+		input.jsonMutation = string(mutation)
+		err = rule.execWithInput(input, req, input.InteractURLs, ruleComponent, key)
+		input.jsonMutation = ""
+		if err != nil {
+			return err
+		}
+	}
+	input.xxe = nil
+	return nil
+}
+
+// mutateJSON applies the mutation with the payload to the value at path of
+// the json document. It returns nil if the mutation is not applicable.
+func mutateJSON(data []byte, path []string, original interface{}, payload string, mutation jsonMutation) ([]byte, error) {
+	var document map[string]interface{}
+	if err := jsonMutationConfig.Unmarshal(data, &document); err != nil {
+		return nil, errors.Wrap(err, "could not decode json body")
+	}
+	parent := document
+	for _, segment := range path[:len(path)-1] {
+		child, ok := parent[segment].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("could not find json path %s", strings.Join(path, "."))
+		}
+		parent = child
+	}
+	name := path[len(path)-1]
+	if _, ok := parent[name]; !ok {
+		return nil, errors.Errorf("could not find json path %s", strings.Join(path, "."))
+	}
+
+	switch mutation {
+	case wrapArrayJSONMutation:
+		if _, ok := original.([]interface{}); ok {
+			return nil, nil
+		}
+		parent[name] = []interface{}{payloadWithTypeOf(original, payload)}
+	case changeTypeJSONMutation:
+		changed, ok := payloadWithOtherType(original, payload)
+		if !ok {
+			return nil, nil
+		}
+		parent[name] = changed
+	case duplicateKeyJSONMutation:
+		// maps cannot hold duplicate keys so the value is replaced by a
+		// placeholder expanded into both members once encoded
+		placeholder := "nduplicate" + xid.New().String()
+		originalValue, err := jsonMutationConfig.Marshal(parent[name])
+		if err != nil {
+			return nil, err
+		}
+		encodedName, err := jsonMutationConfig.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		duplicate, err := jsonMutationConfig.Marshal(payloadWithTypeOf(original, payload))
+		if err != nil {
+			return nil, err
+		}
+		parent[name] = placeholder
+		encoded, err := jsonMutationConfig.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		members := string(originalValue) + "," + string(encodedName) + ":" + string(duplicate)
+		return []byte(strings.Replace(string(encoded), `"`+placeholder+`"`, members, 1)), nil
+	}
+	return jsonMutationConfig.Marshal(document)
+}
+
+// payloadWithTypeOf returns the payload with the type of the original value
+// if it can be represented with it or as a string otherwise
+func payloadWithTypeOf(original interface{}, payload string) interface{} {
+	switch original.(type) {
+	case json.Number:
+		if _, err := strconv.ParseFloat(payload, 64); err == nil {
+			return json.Number(payload)
+		}
+	case bool:
+		if parsed, err := strconv.ParseBool(payload); err == nil {
+			return parsed
+		}
+	}
+	return payload
+}
+
+// payloadWithOtherType returns the payload with a type other than the type
+// of the original value. Payloads of string values are decoded as json
+// literals (numbers, booleans, null, objects or arrays) while payloads of
+// other values are sent as strings.
+func payloadWithOtherType(original interface{}, payload string) (interface{}, bool) {
+	if _, ok := original.(string); !ok {
+		return payload, true
+	}
+	var decoded interface{}
+	if err := jsonMutationConfig.UnmarshalFromString(payload, &decoded); err != nil {
+		return nil, false
+	}
+	if _, ok := decoded.(string); ok {
+		return nil, false
+	}
+	return decoded, true
+}
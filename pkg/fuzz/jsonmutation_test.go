@@ -0,0 +1,63 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMutations(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+
+	rule := &Rule{
+		Part:          "body",
+		Type:          "replace",
+		Mode:          "single",
+		KeysRegex:     []string{`(^|~)id$`},
+		Fuzz:          SliceOrMapSlice{Value: []string{"2"}},
+		JSONMutations: []string{"wrap-array", "duplicate-key", "change-type"},
+	}
+	require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+	execute := func(body string) map[string]string {
+		baseRequest, err := retryablehttp.NewRequest("POST", "https://example.com/", bytes.NewReader([]byte(body)))
+		require.NoError(t, err, "could not create base request")
+		baseRequest.Header.Set("Content-Type", "application/json")
+
+		bodies := make(map[string]string)
+		input := &ExecuteRuleInput{
+			Input:       contextargs.NewWithInput(context.Background(), "https://example.com/"),
+			BaseRequest: baseRequest,
+			Callback: func(gr GeneratedRequest) bool {
+				data, err := gr.Request.BodyBytes()
+				require.NoError(t, err, "could not read body")
+				bodies[gr.JSONMutation] = string(data)
+				return true
+			},
+		}
+		require.NoError(t, rule.Execute(input), "could not execute rule")
+		return bodies
+	}
+
+	bodies := execute(`{"id":"1","name":"test"}`)
+	require.JSONEq(t, `{"id":"2","name":"test"}`, bodies[""], "could not fuzz value")
+	require.Equal(t, `{"id":["2"],"name":"test"}`, bodies["wrap-array"], "could not wrap value in array")
+	require.Equal(t, `{"id":"1","id":"2","name":"test"}`, bodies["duplicate-key"], "could not duplicate key")
+	require.Equal(t, `{"id":2,"name":"test"}`, bodies["change-type"], "could not change type of value")
+
+	bodies = execute(`{"user":{"id":1}}`)
+	require.Equal(t, `{"user":{"id":[2]}}`, bodies["wrap-array"], "could not wrap nested value in array")
+	require.Equal(t, `{"user":{"id":"2"}}`, bodies["change-type"], "could not change type of nested value")
+
+	invalid := &Rule{Part: "body", Fuzz: SliceOrMapSlice{Value: []string{"x"}}, JSONMutations: []string{"unknown"}}
+	require.Error(t, invalid.Compile(nil, executorOpts), "compiled unknown json mutation")
+
+	_, err := mutateJSON([]byte(`{"id":`), []string{"id"}, "1", "2", wrapArrayJSONMutation)
+	require.Error(t, err, "mutated invalid json body")
+}
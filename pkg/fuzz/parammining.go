@@ -0,0 +1,228 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	readerutil "github.com/projectdiscovery/utils/reader"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// Detection heuristics supported for parameter mining
+const (
+	MiningDetectionReflection = "reflection"
+	MiningDetectionSize       = "size"
+	MiningDetectionAny        = "any"
+)
+
+// Request parts supported for parameter mining
+const (
+	MiningPartQuery = "query"
+	MiningPartBody  = "body"
+)
+
+const (
+	defaultMiningSizeThreshold = 20
+	defaultMiningMaxRequests   = 500
+)
+
+// DefaultMiningWordlist contains default parameter names tried while mining
+var DefaultMiningWordlist = []string{
+	"id", "q", "query", "search", "s", "page", "url", "redirect", "next",
+	"return", "returnUrl", "callback", "file", "path", "dir", "name", "user",
+	"username", "email", "token", "key", "lang", "debug", "test", "admin",
+	"action", "cmd", "type", "view", "template", "format", "sort", "order",
+	"filter", "limit", "offset", "ref", "source", "target", "data",
+}
+
+// ParamMining contains configuration for discovering hidden query and body
+// parameters of an input before fuzzing.
+//
+// Each parameter name of the wordlist is sent with a random canary value and
+// is considered discovered if the canary is reflected in the response or the
+// response size changes compared to the baseline. Discovered parameters are
+// added to the base request so fuzzing rules target them as well.
+type ParamMining struct {
+	// description: |
+	//   Wordlist is the list of parameter names or a file containing
+	//   parameter names (one per line). A small default list is used if empty.
+	// examples:
+	//   - value: >
+	//       []string{"debug", "admin", "callback"}
+	//   - value: "\"helpers/wordlists/params.txt\""
+	Wordlist interface{} `yaml:"wordlist,omitempty" json:"wordlist,omitempty" jsonschema:"title=parameter names wordlist,description=List of parameter names or a file containing them"`
+	// description: |
+	//   Parts is the list of request parts parameters are mined in.
+	//   Default is query.
+	// values:
+	//   - "query"
+	//   - "body"
+	Parts []string `yaml:"parts,omitempty" json:"parts,omitempty" jsonschema:"title=request parts to mine,description=Request parts parameters are mined in,enum=query,enum=body"`
+	// description: |
+	//   Detection is the heuristic used to decide if a parameter exists.
+	//
+	//   reflection requires the canary value to be reflected in the response,
+	//   size requires the response size to change and any accepts both.
+	//   Default is any.
+	// values:
+	//   - "reflection"
+	//   - "size"
+	//   - "any"
+	Detection string `yaml:"detection,omitempty" json:"detection,omitempty" jsonschema:"title=parameter detection heuristic,description=Heuristic used to detect parameters,enum=reflection,enum=size,enum=any"`
+	// description: |
+	//   SizeThreshold is the minimum difference in bytes between the baseline
+	//   and mining response sizes for size detection. Default is 20.
+	// examples:
+	//   - value: "50"
+	SizeThreshold int `yaml:"size-threshold,omitempty" json:"size-threshold,omitempty" jsonschema:"title=response size threshold,description=Minimum response size difference in bytes for size detection"`
+	// description: |
+	//   MaxRequests is the maximum number of mining requests sent per input.
+	//   Default is 500.
+	// examples:
+	//   - value: "100"
+	MaxRequests int `yaml:"max-requests,omitempty" json:"max-requests,omitempty" jsonschema:"title=maximum mining requests,description=Maximum number of mining requests sent per input"`
+
+	words []string
+}
+
+// Compile validates the mining configuration and loads the wordlist
+func (m *ParamMining) Compile(options *protocols.ExecutorOptions) error {
+	if m.Detection == "" {
+		m.Detection = MiningDetectionAny
+	}
+	switch m.Detection {
+	case MiningDetectionReflection, MiningDetectionSize, MiningDetectionAny:
+	default:
+		return errors.Errorf("invalid param mining detection specified: %s", m.Detection)
+	}
+	if len(m.Parts) == 0 {
+		m.Parts = []string{MiningPartQuery}
+	}
+	for _, part := range m.Parts {
+		if part != MiningPartQuery && part != MiningPartBody {
+			return errors.Errorf("invalid param mining part specified: %s", part)
+		}
+	}
+	if m.SizeThreshold < 0 || m.MaxRequests < 0 {
+		return errors.Errorf("param mining size-threshold and max-requests must not be negative")
+	}
+	if m.SizeThreshold == 0 {
+		m.SizeThreshold = defaultMiningSizeThreshold
+	}
+	if m.MaxRequests == 0 {
+		m.MaxRequests = defaultMiningMaxRequests
+	}
+
+	if m.Wordlist == nil {
+		m.words = DefaultMiningWordlist
+		return nil
+	}
+	generator, err := generators.New(map[string]interface{}{"param": m.Wordlist}, generators.BatteringRamAttack, options.TemplatePath, options.Catalog, "", options.Options)
+	if err != nil {
+		return errors.Wrap(err, "could not load param mining wordlist")
+	}
+	seen := make(map[string]struct{})
+	iterator := generator.NewIterator()
+	for {
+		value, ok := iterator.Value()
+		if !ok {
+			break
+		}
+		word := strings.TrimSpace(types.ToString(value["param"]))
+		if _, ok := seen[word]; ok || word == "" {
+			continue
+		}
+		seen[word] = struct{}{}
+		m.words = append(m.words, word)
+	}
+	if len(m.words) == 0 {
+		return errors.New("param mining wordlist is empty")
+	}
+	return nil
+}
+
+// Words returns the parameter names tried while mining
+func (m *ParamMining) Words() []string {
+	return m.words
+}
+
+// IsDiscovered returns true if the mining response body for a parameter
+// carrying canary indicates that the parameter exists. variance is the
+// size difference observed between repeated baseline responses.
+func (m *ParamMining) IsDiscovered(baselineSize, variance int, body, canary string) bool {
+	reflected := canary != "" && strings.Contains(body, canary)
+	diff := len(body) - baselineSize
+	if diff < 0 {
+		diff = -diff
+	}
+	threshold := m.SizeThreshold
+	if variance > threshold {
+		threshold = variance
+	}
+	sizeChanged := diff > threshold
+
+	switch m.Detection {
+	case MiningDetectionReflection:
+		return reflected
+	case MiningDetectionSize:
+		return sizeChanged
+	default:
+		return reflected || sizeChanged
+	}
+}
+
+// AddParameter returns a copy of req with name=value added to the query
+// or the form encoded body depending on part
+func AddParameter(req *retryablehttp.Request, part, name, value string) (*retryablehttp.Request, error) {
+	cloned := req.Clone(context.Background())
+	switch part {
+	case MiningPartQuery:
+		params := urlutil.NewOrderedParams()
+		params.Decode(cloned.URL.RawQuery)
+		params.Add(name, value)
+
+		encoded := params.Encode()
+		cloned.URL.RawQuery = encoded
+		cloned.Params = urlutil.NewOrderedParams()
+		cloned.Params.Decode(encoded)
+		cloned.Update()
+	case MiningPartBody:
+		var data []byte
+		if cloned.Body != nil {
+			var err error
+			if data, err = io.ReadAll(cloned.Body); err != nil {
+				return nil, errors.Wrap(err, "could not read body")
+			}
+		}
+		if len(data) > 0 && !strings.Contains(cloned.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+			return nil, errors.New("param mining is only supported for form encoded bodies")
+		}
+		buffer := bytes.NewBuffer(data)
+		if len(data) > 0 {
+			buffer.WriteString("&")
+		}
+		buffer.WriteString(url.QueryEscape(name) + "=" + url.QueryEscape(value))
+
+		encoded := buffer.String()
+		reusableReader, err := readerutil.NewReusableReadCloser(encoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create reusable reader")
+		}
+		cloned.Body = reusableReader
+		cloned.ContentLength = int64(len(encoded))
+		cloned.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
+		cloned.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	default:
+		return nil, errors.Errorf("invalid param mining part specified: %s", part)
+	}
+	return cloned, nil
+}
@@ -0,0 +1,54 @@
+package fuzz
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamMiningCompile(t *testing.T) {
+	mining := &ParamMining{Wordlist: []string{"debug", "admin", "debug"}}
+	require.Nil(t, mining.Compile(&protocols.ExecutorOptions{Options: types.DefaultOptions()}), "could not compile param mining")
+	require.Equal(t, []string{"debug", "admin"}, mining.Words(), "could not load wordlist")
+	require.Equal(t, []string{MiningPartQuery}, mining.Parts, "could not set default parts")
+
+	mining = &ParamMining{Detection: "unknown"}
+	require.NotNil(t, mining.Compile(&protocols.ExecutorOptions{Options: types.DefaultOptions()}), "could compile invalid detection")
+}
+
+func TestParamMiningIsDiscovered(t *testing.T) {
+	mining := &ParamMining{Detection: MiningDetectionAny, SizeThreshold: 20}
+	require.True(t, mining.IsDiscovered(100, 0, "value: canary123", "canary123"), "could not detect reflection")
+	require.True(t, mining.IsDiscovered(100, 0, strings.Repeat("a", 200), "canary123"), "could not detect size change")
+	require.False(t, mining.IsDiscovered(100, 0, strings.Repeat("a", 110), "canary123"), "detected change below threshold")
+	require.False(t, mining.IsDiscovered(100, 150, strings.Repeat("a", 200), "canary123"), "detected change within baseline variance")
+
+	mining.Detection = MiningDetectionReflection
+	require.False(t, mining.IsDiscovered(100, 0, strings.Repeat("a", 200), "canary123"), "detected size change for reflection")
+}
+
+func TestAddParameter(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodPost, "https://example.com/search?q=test", strings.NewReader("name=test"))
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	updated, err := AddParameter(req, MiningPartQuery, "debug", "1")
+	require.Nil(t, err, "could not add query parameter")
+	require.Equal(t, "1", updated.URL.Query().Get("debug"), "could not add query parameter")
+	require.Equal(t, "test", updated.URL.Query().Get("q"), "could not preserve query parameter")
+
+	updated, err = AddParameter(req, MiningPartBody, "admin", "true")
+	require.Nil(t, err, "could not add body parameter")
+	body, err := updated.BodyBytes()
+	require.Nil(t, err, "could not read body")
+	require.Equal(t, "name=test&admin=true", string(body), "could not add body parameter")
+
+	original, err := req.BodyBytes()
+	require.Nil(t, err, "could not read body")
+	require.Equal(t, "name=test", string(original), "modified original request body")
+}
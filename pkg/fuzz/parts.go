@@ -1,14 +1,21 @@
 package fuzz
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"net"
 	"strings"
 
+	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/dataformat"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/rs/xid"
 )
 
 // executePartRule executes part rules based on type
@@ -17,13 +24,16 @@ func (rule *Rule) executePartRule(input *ExecuteRuleInput, payload ValueOrKeyVal
 }
 
 // checkRuleApplicableOnComponent checks if a rule is applicable on given component
-func (rule *Rule) checkRuleApplicableOnComponent(component component.Component) bool {
-	if rule.Part != component.Name() {
+func (rule *Rule) checkRuleApplicableOnComponent(input *ExecuteRuleInput, component component.Component) bool {
+	if rule.partType != requestPartType && rule.Part != component.Name() {
+		return false
+	}
+	if !rule.hasPayloadsFor(component.Name()) {
 		return false
 	}
 	foundAny := false
 	_ = component.Iterate(func(key string, value interface{}) error {
-		if rule.matchKeyOrValue(key, types.ToString(value)) {
+		if rule.matchKeyOrValue(key, types.ToString(value)) && rule.matchParamType(input, component.Name(), key) {
 			foundAny = true
 			return io.EOF
 		}
@@ -48,18 +58,25 @@ func (rule *Rule) executePartComponent(input *ExecuteRuleInput, payload ValueOrK
 // this supports both single and multiple [ruleType] modes
 // i.e if component has multiple values, they can be replaced once or all depending on mode
 func (rule *Rule) executePartComponentOnValues(input *ExecuteRuleInput, payloadStr string, ruleComponent component.Component) error {
+	if rule.modeType == multipleModeType {
+		rule.newCanary(input)
+	}
 	finalErr := ruleComponent.Iterate(func(key string, value interface{}) error {
 		valueStr := types.ToString(value)
-		if !rule.matchKeyOrValue(key, valueStr) {
+		if !rule.matchKeyOrValue(key, valueStr) || !rule.matchParamType(input, ruleComponent.Name(), key) {
 			// ignore non-matching keys
 			return nil
 		}
+		if rule.modeType == singleModeType {
+			rule.newCanary(input)
+		}
 
 		var evaluated string
-		evaluated, input.InteractURLs = rule.executeEvaluate(input, key, valueStr, payloadStr, input.InteractURLs)
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, ruleComponent, valueStr, payloadStr, input.InteractURLs)
 		if err := ruleComponent.SetValue(key, evaluated); err != nil {
 			// gologger.Warning().Msgf("could not set value due to format restriction original(%s, %s[%T]) , new(%s,%s[%T])", key, valueStr, value, key, evaluated, evaluated)
-			return nil
+			// mutations can still send the payload with another json type
+			return rule.executeJSONMutations(input, ruleComponent, key, value, evaluated)
 		}
 
 		if rule.modeType == singleModeType {
@@ -68,7 +85,7 @@ func (rule *Rule) executePartComponentOnValues(input *ExecuteRuleInput, payloadS
 				return err
 			}
 
-			if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent); qerr != nil {
+			if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent, key); qerr != nil {
 				return qerr
 			}
 			// fmt.Printf("executed with value: %s\n", evaluated)
@@ -76,6 +93,7 @@ func (rule *Rule) executePartComponentOnValues(input *ExecuteRuleInput, payloadS
 			if err != nil {
 				return err
 			}
+			return rule.executeJSONMutations(input, ruleComponent, key, value, evaluated)
 		}
 		return nil
 	})
@@ -90,7 +108,7 @@ func (rule *Rule) executePartComponentOnValues(input *ExecuteRuleInput, payloadS
 		if err != nil {
 			return err
 		}
-		if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent); qerr != nil {
+		if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent, ""); qerr != nil {
 			err = qerr
 			return err
 		}
@@ -112,10 +130,11 @@ func (rule *Rule) executePartComponentOnKV(input *ExecuteRuleInput, payload Valu
 		}
 		return nil
 	})
+	rule.newCanary(input)
 	// iterate over given kv instead of component ones
 	return func(key, value string) error {
 		var evaluated string
-		evaluated, input.InteractURLs = rule.executeEvaluate(input, key, "", value, input.InteractURLs)
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, ruleComponent, "", value, input.InteractURLs)
 		if err := ruleComponent.SetValue(key, evaluated); err != nil {
 			return err
 		}
@@ -125,7 +144,7 @@ func (rule *Rule) executePartComponentOnKV(input *ExecuteRuleInput, payload Valu
 				return err
 			}
 
-			if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent); qerr != nil {
+			if qerr := rule.execWithInput(input, req, input.InteractURLs, ruleComponent, key); qerr != nil {
 				return err
 			}
 
@@ -144,12 +163,33 @@ func (rule *Rule) executePartComponentOnKV(input *ExecuteRuleInput, payload Valu
 }
 
 // execWithInput executes a rule with input via callback
-func (rule *Rule) execWithInput(input *ExecuteRuleInput, httpReq *retryablehttp.Request, interactURLs []string, component component.Component) error {
+func (rule *Rule) execWithInput(input *ExecuteRuleInput, httpReq *retryablehttp.Request, interactURLs []string, component component.Component, parameter string) error {
+	if input.xxe != nil {
+		injected, err := injectXXE(httpReq, input.xxe)
+		input.xxe = nil
+		if err != nil {
+			return err
+		}
+		httpReq = injected
+	}
+	if sni := rule.getSNI(httpReq); sni != "" {
+		httpReq = httpReq.WithContext(context.WithValue(httpReq.Context(), fastdialer.SniName, sni))
+	}
+	dynamicValues := input.Values
+	schema := parameterSchema(input, component.Name(), parameter)
+	if input.canary != "" || len(input.chained) > 0 || schema != nil {
+		dynamicValues = generators.MergeMaps(input.Values, input.chained, schemaValues(schema))
+		if input.canary != "" {
+			dynamicValues["canary"] = input.canary
+		}
+	}
 	request := GeneratedRequest{
 		Request:       httpReq,
 		InteractURLs:  interactURLs,
-		DynamicValues: input.Values,
+		DynamicValues: dynamicValues,
 		Component:     component,
+		Parameter:     parameter,
+		JSONMutation:  input.jsonMutation,
 	}
 	if !input.Callback(request) {
 		return types.ErrNoMoreRequests
@@ -157,21 +197,95 @@ func (rule *Rule) execWithInput(input *ExecuteRuleInput, httpReq *retryablehttp.
 	return nil
 }
 
+// getSNI returns the tls server name to use for the request if any
+func (rule *Rule) getSNI(httpReq *retryablehttp.Request) string {
+	switch rule.SNI {
+	case "":
+		return ""
+	case "sent-host":
+		if host := httpReq.Host; host != "" {
+			if hostname, _, err := net.SplitHostPort(host); err == nil {
+				return hostname
+			}
+			return host
+		}
+		return httpReq.URL.Hostname()
+	default:
+		return rule.SNI
+	}
+}
+
+// newCanary generates the canary of the next request of the rule (if enabled)
+func (rule *Rule) newCanary(input *ExecuteRuleInput) {
+	if rule.Canary {
+		input.canary = xid.New().String()
+	}
+}
+
 // executeEvaluate executes evaluation of payload on a key and value and
 // returns completed values to be replaced and processed
 // for fuzzing.
-func (rule *Rule) executeEvaluate(input *ExecuteRuleInput, _, value, payload string, interactshURLs []string) (string, []string) {
+func (rule *Rule) executeEvaluate(input *ExecuteRuleInput, ruleComponent component.Component, value, payload string, interactshURLs []string) (string, []string) {
 	// TODO: Handle errors
 	values := generators.MergeMaps(input.Values, map[string]interface{}{
 		"value": value,
-	}, rule.options.Options.Vars.AsMap(), rule.options.Variables.GetAll())
+	}, input.optionVars, rule.options.Variables.GetAll(), input.chained)
+	var appendedCanary string
+	if input.canary != "" {
+		// the canary is appended unless explicitly placed in the payload
+		if !strings.Contains(payload, "{{canary}}") {
+			payload += input.canary
+			appendedCanary = input.canary
+		}
+		values["canary"] = input.canary
+	}
 	firstpass, _ := expressions.Evaluate(payload, values)
+	if interactsh.HasXXEMarkers(firstpass) {
+		firstpass, interactshURLs = rule.replaceXXEMarkers(input, firstpass, interactshURLs)
+	}
 	interactData, interactshURLs := rule.options.Interactsh.Replace(firstpass, interactshURLs)
 	evaluated, _ := expressions.Evaluate(interactData, values)
+	if input.DecodePayload != nil {
+		// appended canaries are not encoded
+		if decoded, err := input.DecodePayload(strings.TrimSuffix(evaluated, appendedCanary)); err == nil {
+			evaluated = decoded + appendedCanary
+		}
+	}
 	replaced := rule.executeRuleTypes(input, value, evaluated)
+	if input.DecodePayload != nil && isURLEncoded(ruleComponent) {
+		replaced = escapeRawBytes(replaced)
+	}
 	return replaced, interactshURLs
 }
 
+// isURLEncoded returns true if values of the component are url encoded
+// on rebuild (query and form bodies)
+func isURLEncoded(ruleComponent component.Component) bool {
+	switch c := ruleComponent.(type) {
+	case *component.Query:
+		return true
+	case *component.Body:
+		return c.DataFormat() == dataformat.FormDataFormat
+	}
+	return false
+}
+
+// escapeRawBytes percent encodes the bytes of value outside of printable
+// ascii as url encoding of rebuilt components replaces invalid utf-8 bytes
+// of decoded payloads. Printable characters are encoded by the component.
+func escapeRawBytes(value string) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < 0x20 || c >= 0x7f {
+			builder.WriteString(fmt.Sprintf("%%%02X", c))
+			continue
+		}
+		builder.WriteByte(value[i])
+	}
+	return builder.String()
+}
+
 // executeRuleTypes executes replacement for a key and value
 // ex: prefix, postfix, infix, replace , replace-regex
 func (rule *Rule) executeRuleTypes(_ *ExecuteRuleInput, value, replacement string) string {
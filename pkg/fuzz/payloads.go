@@ -0,0 +1,59 @@
+package fuzz
+
+import (
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// compileComponentPayloads validates the components of the payloads per
+// component of the rule, which must be fuzzed by the part of the rule
+func (rule *Rule) compileComponentPayloads() error {
+	for name, payloads := range rule.ComponentPayloads {
+		if !sliceutil.Contains(component.Components, name) {
+			return errors.Errorf("invalid component %s in component-payloads", name)
+		}
+		if rule.partType != requestPartType && rule.Part != name {
+			return errors.Errorf("component %s in component-payloads is not fuzzed by part %s of rule", name, rule.Part)
+		}
+		if payloads.isEmpty() {
+			return errors.Errorf("no payloads specified for component %s in component-payloads", name)
+		}
+	}
+	return nil
+}
+
+// payloadsFor returns the payloads of the rule for the component. Payloads
+// specific to the component take precedence over the default fuzz payloads.
+func (rule *Rule) payloadsFor(name string) SliceOrMapSlice {
+	if payloads, ok := rule.ComponentPayloads[name]; ok {
+		return payloads
+	}
+	return rule.Fuzz
+}
+
+// hasPayloadsFor returns true if the rule has payloads for the component.
+// Rules without any payloads are considered applicable to keep reporting
+// them as misconfigured on execution.
+func (rule *Rule) hasPayloadsFor(name string) bool {
+	if len(rule.ComponentPayloads) == 0 {
+		return true
+	}
+	return !rule.payloadsFor(name).isEmpty()
+}
+
+// isEmpty returns true if no payloads are specified
+func (v SliceOrMapSlice) isEmpty() bool {
+	return len(v.Value) == 0 && (v.KV == nil || v.KV.Len() == 0)
+}
+
+// len returns the number of payloads specified
+func (v SliceOrMapSlice) len() int {
+	if len(v.Value) > 0 {
+		return len(v.Value)
+	}
+	if v.KV != nil {
+		return v.KV.Len()
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package fuzz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentPayloads(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+
+	execute := func(rule *Rule) []GeneratedRequest {
+		require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+		baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/?a=1", nil)
+		require.NoError(t, err, "could not create base request")
+		baseRequest.Header.Set("X-Test", "1")
+		input := contextargs.NewWithInput(context.Background(), "https://example.com/?a=1")
+
+		var generated []GeneratedRequest
+		err = rule.Execute(&ExecuteRuleInput{
+			Input:       input,
+			BaseRequest: baseRequest,
+			Callback: func(gr GeneratedRequest) bool {
+				generated = append(generated, gr)
+				return true
+			},
+		})
+		require.NoError(t, err, "could not execute rule")
+		return generated
+	}
+
+	t.Run("per-component", func(t *testing.T) {
+		generated := execute(&Rule{Part: "request", Type: "replace", Mode: "single", Keys: []string{"a", "X-Test"}, ComponentPayloads: map[string]SliceOrMapSlice{
+			"query":  {Value: []string{"sqli"}},
+			"header": {Value: []string{"crlf"}},
+		}})
+		require.Len(t, generated, 2, "could not generate requests")
+		for _, gr := range generated {
+			switch gr.Component.Name() {
+			case "query":
+				require.Equal(t, "sqli", gr.Request.URL.Query().Get("a"), "could not use query payloads")
+			case "header":
+				require.Equal(t, "crlf", gr.Request.Header.Get("X-Test"), "could not use header payloads")
+			default:
+				t.Fatalf("fuzzed component %s without payloads", gr.Component.Name())
+			}
+		}
+	})
+	t.Run("default", func(t *testing.T) {
+		generated := execute(&Rule{Part: "request", Type: "replace", Mode: "single", Keys: []string{"a", "X-Test"}, Fuzz: SliceOrMapSlice{Value: []string{"default"}}, ComponentPayloads: map[string]SliceOrMapSlice{
+			"query": {Value: []string{"sqli"}},
+		}})
+		require.Len(t, generated, 2, "could not generate requests")
+		for _, gr := range generated {
+			if gr.Component.Name() == "header" {
+				require.Equal(t, "default", gr.Request.Header.Get("X-Test"), "could not fallback to default payloads")
+			} else {
+				require.Equal(t, "sqli", gr.Request.URL.Query().Get("a"), "default payloads took precedence")
+			}
+		}
+	})
+	t.Run("validate", func(t *testing.T) {
+		rule := &Rule{Part: "request", Type: "replace", ComponentPayloads: map[string]SliceOrMapSlice{"unknown": {Value: []string{"a"}}}}
+		require.Error(t, rule.Compile(nil, executorOpts), "could compile unknown component")
+
+		rule = &Rule{Part: "query", Type: "replace", ComponentPayloads: map[string]SliceOrMapSlice{"header": {Value: []string{"a"}}}}
+		require.Error(t, rule.Compile(nil, executorOpts), "could compile component not fuzzed by part")
+
+		rule = &Rule{Part: "query", Type: "replace", ComponentPayloads: map[string]SliceOrMapSlice{"query": {}}}
+		require.Error(t, rule.Compile(nil, executorOpts), "could compile component without payloads")
+	})
+}
@@ -0,0 +1,219 @@
+package fuzz
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reflection contexts of a payload in a html response
+const (
+	ReflectionHTMLText      = "html-text"
+	ReflectionHTMLComment   = "html-comment"
+	ReflectionHTMLTag       = "html-tag"
+	ReflectionHTMLAttribute = "html-attribute"
+	ReflectionUnquotedAttr  = "html-attribute-unquoted"
+	ReflectionEventHandler  = "event-handler"
+	ReflectionURL           = "url"
+	ReflectionScript        = "script"
+	ReflectionScriptString  = "script-string"
+	ReflectionStyle         = "style"
+)
+
+// states of the html tokenizer locating reflections
+const (
+	reflectionStateText       = iota
+	reflectionStateComment    // inside <!-- -->
+	reflectionStateTag        // inside a tag (name or attribute names)
+	reflectionStateAttrValue  // inside an attribute value
+	reflectionStateScript     // inside a script block
+	reflectionStateStyle      // inside a style block
+	reflectionStateTagClosing // inside a closing tag
+)
+
+// urlAttributes are attributes whose values are interpreted as urls
+var urlAttributes = map[string]struct{}{
+	"href": {}, "src": {}, "action": {}, "formaction": {}, "data": {},
+	"srcset": {}, "poster": {}, "background": {}, "xlink:href": {}, "cite": {},
+}
+
+// ReflectionContexts returns the html/js context of each reflection of
+// value in body in the order of their appearance.
+//
+// The body is scanned with a lightweight html tokenizer tracking tags,
+// attribute values, comments, script (and js string literals) and style
+// blocks, which is enough for triaging exploitability of reflections.
+func ReflectionContexts(body, value string) []string {
+	if value == "" || !strings.Contains(body, value) {
+		return nil
+	}
+	var (
+		contexts []string
+		state    = reflectionStateText
+		tagName  string
+		attrName string
+		quote    byte // quote of the current attribute value or js string
+	)
+	for i := 0; i < len(body); {
+		if strings.HasPrefix(body[i:], value) {
+			contexts = append(contexts, reflectionContext(state, attrName, quote))
+			i += len(value)
+			continue
+		}
+		c := body[i]
+		switch state {
+		case reflectionStateText:
+			switch {
+			case strings.HasPrefix(body[i:], "<!--"):
+				state = reflectionStateComment
+				i += 4
+				continue
+			case c == '<' && i+1 < len(body) && body[i+1] == '/':
+				state = reflectionStateTagClosing
+			case c == '<' && i+1 < len(body) && isLetter(body[i+1]):
+				end := i + 1
+				for end < len(body) && isTagNameChar(body[end]) {
+					end++
+				}
+				tagName = strings.ToLower(body[i+1 : end])
+				attrName = ""
+				state = reflectionStateTag
+				i = end
+				continue
+			}
+		case reflectionStateComment:
+			if strings.HasPrefix(body[i:], "-->") {
+				state = reflectionStateText
+				i += 3
+				continue
+			}
+		case reflectionStateTagClosing:
+			if c == '>' {
+				state = reflectionStateText
+			}
+		case reflectionStateTag:
+			switch {
+			case c == '>':
+				switch tagName {
+				case "script":
+					state = reflectionStateScript
+				case "style":
+					state = reflectionStateStyle
+				default:
+					state = reflectionStateText
+				}
+				quote = 0
+			case c == '=':
+				i++
+				for i < len(body) && isSpace(body[i]) {
+					i++
+				}
+				state = reflectionStateAttrValue
+				quote = 0
+				if i < len(body) && (body[i] == '"' || body[i] == '\'') {
+					quote = body[i]
+					i++
+				}
+				continue
+			case isSpace(c) || c == '/':
+			default:
+				if i > 0 && (isSpace(body[i-1]) || body[i-1] == '/' || body[i-1] == '"' || body[i-1] == '\'') {
+					attrName = ""
+				}
+				attrName += strings.ToLower(string(c))
+			}
+		case reflectionStateAttrValue:
+			if quote != 0 && c == quote {
+				state = reflectionStateTag
+				quote = 0
+			} else if quote == 0 && (isSpace(c) || c == '>') {
+				state = reflectionStateTag
+				continue
+			}
+		case reflectionStateScript:
+			if quote != 0 {
+				if c == '\\' {
+					i += 2
+					continue
+				}
+				if c == quote {
+					quote = 0
+				}
+			} else if c == '"' || c == '\'' || c == '`' {
+				quote = c
+			} else if hasPrefixFold(body[i:], "</script") {
+				state = reflectionStateTagClosing
+			}
+		case reflectionStateStyle:
+			if hasPrefixFold(body[i:], "</style") {
+				state = reflectionStateTagClosing
+			}
+		}
+		i++
+	}
+	return contexts
+}
+
+// ReflectionValues returns the reflection contexts of value in body as
+// variables for matchers. reflection_context is the context of the first
+// reflection, reflection_contexts the comma separated contexts of all
+// reflections and reflection_context<N> the context of the nth (0 based)
+// reflection. nil is returned if value is not reflected.
+func ReflectionValues(body, value string) map[string]interface{} {
+	contexts := ReflectionContexts(body, value)
+	if len(contexts) == 0 {
+		return nil
+	}
+	values := map[string]interface{}{
+		"reflection_context":  contexts[0],
+		"reflection_contexts": strings.Join(contexts, ","),
+	}
+	for index, context := range contexts {
+		values[fmt.Sprintf("reflection_context%d", index)] = context
+	}
+	return values
+}
+
+// reflectionContext returns the reflection context for the tokenizer state
+func reflectionContext(state int, attrName string, quote byte) string {
+	switch state {
+	case reflectionStateComment:
+		return ReflectionHTMLComment
+	case reflectionStateTag, reflectionStateTagClosing:
+		return ReflectionHTMLTag
+	case reflectionStateAttrValue:
+		if strings.HasPrefix(attrName, "on") {
+			return ReflectionEventHandler
+		}
+		if _, ok := urlAttributes[attrName]; ok {
+			return ReflectionURL
+		}
+		if quote == 0 {
+			return ReflectionUnquotedAttr
+		}
+		return ReflectionHTMLAttribute
+	case reflectionStateScript:
+		if quote != 0 {
+			return ReflectionScriptString
+		}
+		return ReflectionScript
+	case reflectionStateStyle:
+		return ReflectionStyle
+	}
+	return ReflectionHTMLText
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isTagNameChar(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,44 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReflectionContexts(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected []string
+	}{
+		{body: "<p>hello CANARY</p>", expected: []string{ReflectionHTMLText}},
+		{body: `<input value="CANARY">`, expected: []string{ReflectionHTMLAttribute}},
+		{body: `<input value=CANARY>`, expected: []string{ReflectionUnquotedAttr}},
+		{body: `<a href="/x?q=CANARY">x</a>`, expected: []string{ReflectionURL}},
+		{body: `<img src=x onerror='CANARY'>`, expected: []string{ReflectionEventHandler}},
+		{body: `<div CANARY=1>`, expected: []string{ReflectionHTMLTag}},
+		{body: `<!-- CANARY -->`, expected: []string{ReflectionHTMLComment}},
+		{body: `<script>var a = CANARY;</script>`, expected: []string{ReflectionScript}},
+		{body: `<SCRIPT>var a = "x\"CANARY";</SCRIPT>`, expected: []string{ReflectionScriptString}},
+		{body: `<style>body{color:CANARY}</style>`, expected: []string{ReflectionStyle}},
+		{
+			body:     `<title>CANARY</title><script>q='CANARY'</script><a title="CANARY" href=CANARY>CANARY</a>`,
+			expected: []string{ReflectionHTMLText, ReflectionScriptString, ReflectionHTMLAttribute, ReflectionURL, ReflectionHTMLText},
+		},
+		{body: "<p>no reflection</p>"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, ReflectionContexts(test.body, "CANARY"), "could not classify %s", test.body)
+	}
+}
+
+func TestReflectionValues(t *testing.T) {
+	values := ReflectionValues(`<p>CANARY</p><script>a="CANARY"</script>`, "CANARY")
+	require.Equal(t, map[string]interface{}{
+		"reflection_context":  ReflectionHTMLText,
+		"reflection_contexts": "html-text,script-string",
+		"reflection_context0": ReflectionHTMLText,
+		"reflection_context1": ReflectionScriptString,
+	}, values, "could not get reflection values")
+	require.Nil(t, ReflectionValues("<p></p>", "CANARY"), "got values without reflection")
+}
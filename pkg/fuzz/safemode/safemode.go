@@ -0,0 +1,104 @@
+// Package safemode implements detection of known-destructive fuzzing
+// requests (ex: shutdown commands, DELETE with wildcards) which are
+// skipped when nuclei is running in safe mode.
+package safemode
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// methodPrefix is the prefix used in denylist entries to block http methods
+const methodPrefix = "method:"
+
+// DefaultDenylist contains default destructive patterns matched (case-insensitive)
+// against the generated request. Entries prefixed with `method:` block that http method.
+var DefaultDenylist = []string{
+	`method:DELETE`,
+	`method:PURGE`,
+	`\b(shutdown|halt|poweroff|reboot|init\s+0)\b`,
+	`\brm\s+-[a-z]*[rf][a-z]*\s+(/|\*|~)`,
+	`\bmkfs(\.\w+)?\b`,
+	`\bdd\s+if=`,
+	`:\(\)\s*\{\s*:\|:&\s*\}\s*;\s*:`,
+	`\bformat\s+[a-z]:`,
+	`\b(drop|truncate)\s+(table|database|schema)\b`,
+	`\bdelete\s+from\s+\w+\s*(;|--|#|$)`,
+	`\bflushall\b`,
+	`\bdropdatabase\(`,
+}
+
+// Filter checks generated requests against a denylist of destructive patterns
+type Filter struct {
+	methods  map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// New creates a new safe mode filter from default denylist and entries
+// (one per line, `#` comments allowed) from optional denylist file.
+func New(denylistFile string) (*Filter, error) {
+	entries := append([]string{}, DefaultDenylist...)
+	if denylistFile != "" {
+		file, err := os.Open(denylistFile)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not open safe mode denylist file")
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				entries = append(entries, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not read safe mode denylist file")
+		}
+	}
+
+	filter := &Filter{methods: make(map[string]struct{})}
+	for _, entry := range entries {
+		if strings.HasPrefix(strings.ToLower(entry), methodPrefix) {
+			filter.methods[strings.ToUpper(strings.TrimSpace(entry[len(methodPrefix):]))] = struct{}{}
+			continue
+		}
+		compiled, err := regexp.Compile("(?i)" + entry)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not compile safe mode pattern %v", entry)
+		}
+		filter.patterns = append(filter.patterns, compiled)
+	}
+	return filter, nil
+}
+
+// IsDestructive returns true along with the matched denylist entry if
+// the request method or any part of the request is destructive
+func (f *Filter) IsDestructive(req *retryablehttp.Request) (bool, string) {
+	if f == nil || req == nil {
+		return false, ""
+	}
+	if _, ok := f.methods[strings.ToUpper(req.Method)]; ok {
+		return true, methodPrefix + req.Method
+	}
+	dumped, err := req.Dump()
+	if err != nil {
+		return false, ""
+	}
+	// match on both raw and url decoded request to catch encoded payloads
+	data := string(dumped)
+	if unescaped, err := url.QueryUnescape(data); err == nil {
+		data = data + "\n" + unescaped
+	}
+	for _, pattern := range f.patterns {
+		if pattern.MatchString(data) {
+			return true, pattern.String()
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,39 @@
+package safemode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterIsDestructive(t *testing.T) {
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(denylist, []byte("# custom entries\nmethod:patch\nself-destruct\n"), 0600)
+	require.Nil(t, err, "could not write denylist")
+
+	filter, err := New(denylist)
+	require.Nil(t, err, "could not create safe mode filter")
+
+	tests := []struct {
+		method      string
+		url         string
+		destructive bool
+	}{
+		{"GET", "https://example.com/?q=test", false},
+		{"DELETE", "https://example.com/users/1", true},
+		{"PATCH", "https://example.com/users/1", true},
+		{"GET", "https://example.com/?cmd=%3Bshutdown%20-h%20now", true},
+		{"GET", "https://example.com/?id=1;DROP%20TABLE%20users", true},
+		{"GET", "https://example.com/?q=self-destruct", true},
+	}
+	for _, test := range tests {
+		req, err := retryablehttp.NewRequest(test.method, test.url, nil)
+		require.Nil(t, err, "could not create request")
+
+		destructive, _ := filter.IsDestructive(req)
+		require.Equal(t, test.destructive, destructive, "could not get correct result for %s %s", test.method, test.url)
+	}
+}
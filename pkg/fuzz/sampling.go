@@ -0,0 +1,142 @@
+package fuzz
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+)
+
+// Sampling strategies supported for fuzzing payloads
+const (
+	SamplingFirst  = "first"
+	SamplingRandom = "random"
+	SamplingNth    = "nth"
+)
+
+// Sampling contains configuration for bounding the payloads
+// of a fuzzing rule to a subset for quick triage runs.
+type Sampling struct {
+	// description: |
+	//   Max is the maximum number of payloads to use for the rule, each
+	//   combination of payload values and fuzz values counting once.
+	// examples:
+	//   - value: "100"
+	Max int `yaml:"max,omitempty" json:"max,omitempty" jsonschema:"title=maximum payloads,description=Maximum number of payloads to use for the rule"`
+	// description: |
+	//   Strategy is the strategy used to select payloads.
+	//
+	//   first selects first payloads, random selects random payloads
+	//   and nth selects every nth payload. Default is first.
+	// values:
+	//   - "first"
+	//   - "random"
+	//   - "nth"
+	Strategy string `yaml:"strategy,omitempty" json:"strategy,omitempty" jsonschema:"title=sampling strategy,description=Strategy used to select payloads,enum=first,enum=random,enum=nth"`
+	// description: |
+	//   Step is the interval between selected payloads for nth strategy.
+	// examples:
+	//   - value: "10"
+	Step int `yaml:"step,omitempty" json:"step,omitempty" jsonschema:"title=sampling step,description=Interval between selected payloads for nth strategy"`
+	// description: |
+	//   Seed is the seed for random strategy to make sampling reproducible.
+	//   A random seed is used if not specified.
+	// examples:
+	//   - value: "1337"
+	Seed int64 `yaml:"seed,omitempty" json:"seed,omitempty" jsonschema:"title=sampling seed,description=Seed for reproducible random sampling"`
+}
+
+// Compile validates the sampling configuration
+func (s *Sampling) Compile() error {
+	if s.Strategy == "" {
+		s.Strategy = SamplingFirst
+	}
+	if s.Max < 0 {
+		return errors.Errorf("sampling max must not be negative")
+	}
+	switch s.Strategy {
+	case SamplingFirst, SamplingRandom:
+		if s.Max <= 0 {
+			return errors.Errorf("sampling max must be greater than zero for %s strategy", s.Strategy)
+		}
+	case SamplingNth:
+		if s.Step <= 0 {
+			return errors.Errorf("sampling step must be greater than zero for nth strategy")
+		}
+	default:
+		return errors.Errorf("invalid sampling strategy specified: %s", s.Strategy)
+	}
+	if s.Seed == 0 {
+		s.Seed = time.Now().UnixNano()
+	}
+	return nil
+}
+
+// Indices returns the sorted indices of payloads selected out of total
+func (s *Sampling) Indices(total int) []int {
+	var indices []int
+	switch s.Strategy {
+	case SamplingRandom:
+		indices = rand.New(rand.NewSource(s.Seed)).Perm(total)
+		if s.Max < len(indices) {
+			indices = indices[:s.Max]
+		}
+		sort.Ints(indices)
+	case SamplingNth:
+		for i := 0; i < total; i += s.Step {
+			indices = append(indices, i)
+		}
+	default:
+		for i := 0; i < total; i++ {
+			indices = append(indices, i)
+		}
+	}
+	if s.Max > 0 && len(indices) > s.Max {
+		indices = indices[:s.Max]
+	}
+	return indices
+}
+
+// payloadSample tracks the payloads of a component selected by sampling
+// out of the combinations of payload values and fuzz payloads of a rule,
+// in the order they are tried.
+type payloadSample struct {
+	selected map[int]struct{}
+	// last is the position of the last selected payload
+	last     int
+	position int
+}
+
+// newPayloadSample returns the sample of the payloads of the component
+// or nil if the rule has no sampling
+func (rule *Rule) newPayloadSample(ruleComponent component.Component, values int) *payloadSample {
+	if rule.Sampling == nil {
+		return nil
+	}
+	indices := rule.Sampling.Indices(values * rule.payloadsFor(ruleComponent.Name()).len())
+	sample := &payloadSample{selected: make(map[int]struct{}, len(indices)), last: -1}
+	for _, index := range indices {
+		sample.selected[index] = struct{}{}
+	}
+	if len(indices) > 0 {
+		sample.last = indices[len(indices)-1]
+	}
+	return sample
+}
+
+// next returns true if the next payload is selected
+func (s *payloadSample) next() bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s.selected[s.position]
+	s.position++
+	return ok
+}
+
+// done returns true if no further payloads are selected
+func (s *payloadSample) done() bool {
+	return s != nil && s.position > s.last
+}
@@ -0,0 +1,77 @@
+package fuzz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+// executeSampledRule executes a query rule with given sampling (and payload
+// generator if any) and returns the fuzzed values of generated requests
+func executeSampledRule(t *testing.T, sampling *Sampling, generator *generators.PayloadGenerator) []string {
+	fuzz := []string{"a", "b", "c", "d", "e", "f"}
+	if generator != nil {
+		fuzz = []string{"a{{payload}}", "b{{payload}}", "c{{payload}}", "d{{payload}}", "e{{payload}}", "f{{payload}}"}
+	}
+	rule := &Rule{Part: "query", Type: "replace", Mode: "single", Fuzz: SliceOrMapSlice{Value: fuzz}, Sampling: sampling}
+	require.NoError(t, rule.Compile(generator, &protocols.ExecutorOptions{Options: types.DefaultOptions()}), "could not compile rule")
+
+	baseRequest, err := retryablehttp.NewRequest("GET", "https://example.com/?a=1", nil)
+	require.NoError(t, err, "could not create base request")
+	var generated []string
+	err = rule.Execute(&ExecuteRuleInput{
+		Input:       contextargs.NewWithInput(context.Background(), "https://example.com/?a=1"),
+		BaseRequest: baseRequest,
+		Callback: func(gr GeneratedRequest) bool {
+			generated = append(generated, gr.Request.URL.Query().Get("a"))
+			return true
+		},
+	})
+	require.NoError(t, err, "could not execute rule")
+	return generated
+}
+
+func TestSampling(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e", "f"}
+
+	t.Run("first", func(t *testing.T) {
+		generated := executeSampledRule(t, &Sampling{Max: 2}, nil)
+		require.Equal(t, []string{"a", "b"}, generated, "could not sample first payloads")
+	})
+	t.Run("max", func(t *testing.T) {
+		// sampling is applied once to the combinations of payload values and fuzz values
+		generator, err := generators.New(map[string]interface{}{"payload": []string{"1", "2", "3"}}, generators.BatteringRamAttack, "", nil, "", types.DefaultOptions())
+		require.NoError(t, err, "could not create generator")
+		for _, sampling := range []*Sampling{{Max: 4}, {Max: 4, Strategy: SamplingRandom, Seed: 1337}, {Max: 4, Strategy: SamplingNth, Step: 2}} {
+			generated := executeSampledRule(t, sampling, generator)
+			require.Len(t, generated, 4, "could not bound requests of %s sampling", sampling.Strategy)
+		}
+		generated := executeSampledRule(t, &Sampling{Max: 4}, generator)
+		require.Equal(t, []string{"a1", "b1", "c1", "d1"}, generated, "could not sample first payloads")
+	})
+	t.Run("nth", func(t *testing.T) {
+		sampling := &Sampling{Strategy: SamplingNth, Step: 2}
+		require.NoError(t, sampling.Compile(), "could not compile sampling")
+		require.Equal(t, []int{0, 2, 4}, sampling.Indices(len(values)))
+	})
+	t.Run("random", func(t *testing.T) {
+		first := &Sampling{Strategy: SamplingRandom, Max: 3, Seed: 1337}
+		require.NoError(t, first.Compile(), "could not compile sampling")
+		second := &Sampling{Strategy: SamplingRandom, Max: 3, Seed: 1337}
+		require.NoError(t, second.Compile(), "could not compile sampling")
+
+		indices := first.Indices(100)
+		require.Len(t, indices, 3)
+		require.Equal(t, indices, second.Indices(100), "could not get reproducible sample")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		sampling := &Sampling{Strategy: SamplingRandom}
+		require.Error(t, sampling.Compile(), "could compile sampling without max")
+	})
+}
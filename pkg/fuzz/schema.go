@@ -0,0 +1,44 @@
+package fuzz
+
+import (
+	"strings"
+
+	inputTypes "github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// parameterSchema returns the schema of the parameter of the component
+// defined by api specification inputs (ex: openapi) if any
+func parameterSchema(input *ExecuteRuleInput, componentName, key string) *inputTypes.ParameterSchema {
+	if key == "" || input.Input == nil || input.Input.MetaInput == nil {
+		return nil
+	}
+	return input.Input.MetaInput.ReqResp.Parameter(componentName, key)
+}
+
+// matchParamType returns true if the schema type of the parameter
+// of the component is one of the types fuzzed by the rule
+func (rule *Rule) matchParamType(input *ExecuteRuleInput, componentName, key string) bool {
+	if len(rule.ParamTypes) == 0 {
+		return true
+	}
+	schema := parameterSchema(input, componentName, key)
+	return schema != nil && sliceutil.Contains(rule.ParamTypes, schema.Type)
+}
+
+// schemaValues returns the dynamic values exposing the schema of the
+// fuzzed parameter to payloads and operators
+func schemaValues(schema *inputTypes.ParameterSchema) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	values := map[string]interface{}{
+		"param_type":     schema.Type,
+		"param_format":   schema.Format,
+		"param_required": schema.Required,
+	}
+	if len(schema.Enum) > 0 {
+		values["param_enum"] = strings.Join(schema.Enum, ",")
+	}
+	return values
+}
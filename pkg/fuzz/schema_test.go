@@ -0,0 +1,54 @@
+package fuzz
+
+import (
+	"context"
+	"testing"
+
+	inputTypes "github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleParamTypes(t *testing.T) {
+	executorOpts := &protocols.ExecutorOptions{Options: types.DefaultOptions()}
+
+	rr, err := inputTypes.ParseRawRequestWithURL("GET /items?id=1&sort=asc HTTP/1.1\r\nHost: example.com\r\n\r\n", "https://example.com/items?id=1&sort=asc")
+	require.NoError(t, err, "could not parse request")
+	rr.Parameters = []*inputTypes.ParameterSchema{
+		{Name: "id", In: "query", Type: "integer", Required: true},
+		{Name: "sort", In: "query", Type: "string", Enum: []string{"asc", "desc"}},
+	}
+	baseRequest, err := rr.BuildRequest()
+	require.NoError(t, err, "could not build request")
+
+	execute := func(rule *Rule) []GeneratedRequest {
+		require.NoError(t, rule.Compile(nil, executorOpts), "could not compile rule")
+
+		input := contextargs.NewWithInput(context.Background(), rr.URL.String())
+		input.MetaInput.ReqResp = rr
+
+		var generated []GeneratedRequest
+		err := rule.Execute(&ExecuteRuleInput{
+			Input:       input,
+			BaseRequest: baseRequest,
+			Callback: func(gr GeneratedRequest) bool {
+				generated = append(generated, gr)
+				return true
+			},
+		})
+		require.NoError(t, err, "could not execute rule")
+		return generated
+	}
+
+	generated := execute(&Rule{Part: "query", Type: "postfix", Mode: "single", ParamTypes: []string{"integer"}, Fuzz: SliceOrMapSlice{Value: []string{"'"}}})
+	require.Len(t, generated, 1, "could not restrict fuzzing to parameter types")
+	require.Equal(t, "id", generated[0].Parameter)
+	require.Equal(t, "integer", generated[0].DynamicValues["param_type"])
+	require.Equal(t, true, generated[0].DynamicValues["param_required"])
+
+	generated = execute(&Rule{Part: "query", Type: "postfix", Mode: "single", Keys: []string{"sort"}, Fuzz: SliceOrMapSlice{Value: []string{"'"}}})
+	require.Len(t, generated, 1, "could not generate requests")
+	require.Equal(t, "asc,desc", generated[0].DynamicValues["param_enum"], "could not expose enum of parameter")
+}
@@ -0,0 +1,107 @@
+// Package seenparams implements a persistent store of (template, host,
+// parameter) tuples fuzzed in previous runs, allowing incremental fuzzing
+// of only newly discovered parameters by each template.
+package seenparams
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+
+	fuzzcomponent "github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// separator separates template, host, component and parameter in a store entry
+const separator = "\t"
+
+// Store is a file backed set of fuzzed parameters.
+//
+// Parameters loaded from the file are reported as seen, while parameters
+// added during the run are appended to the file (and are only skipped
+// in future runs so that all payloads of the current run are sent).
+type Store struct {
+	mu    sync.Mutex
+	file  *os.File
+	known map[string]struct{}
+	added map[string]struct{}
+}
+
+// New creates a new store loading previously seen parameters from
+// file (one `template<TAB>host<TAB>component<TAB>parameter` entry per line).
+func New(path string) (*Store, error) {
+	store := &Store{known: make(map[string]struct{}), added: make(map[string]struct{})}
+	if existing, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				store.known[line] = struct{}{}
+			}
+		}
+		_ = existing.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not read seen parameters file")
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not open seen parameters file")
+	}
+	store.file = file
+	return store, nil
+}
+
+// Key returns the store key for a parameter of a request component
+// on a host fuzzed by a template. Header names are case-insensitive
+// and normalized.
+func Key(templateID, input, component, parameter string) string {
+	host := input
+	if parsed, err := urlutil.Parse(input); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	if component == fuzzcomponent.RequestHeaderComponent {
+		parameter = strings.ToLower(parameter)
+	}
+	return templateID + separator + strings.ToLower(host) + separator + component + separator + parameter
+}
+
+// Seen returns true if the parameter was fuzzed by the template in a previous run
+func (s *Store) Seen(templateID, input, component, parameter string) bool {
+	if s == nil || parameter == "" {
+		return false
+	}
+	_, ok := s.known[Key(templateID, input, component, parameter)]
+	return ok
+}
+
+// Add records the parameter as fuzzed by the template persisting it to the store file
+func (s *Store) Add(templateID, input, component, parameter string) {
+	if s == nil || parameter == "" {
+		return
+	}
+	key := Key(templateID, input, component, parameter)
+	if _, ok := s.known[key]; ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.added[key]; ok {
+		return
+	}
+	s.added[key] = struct{}{}
+	_, _ = s.file.WriteString(key + "\n")
+}
+
+// Close closes the store file
+func (s *Store) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.file.Close()
+}
@@ -0,0 +1,29 @@
+package seenparams
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenParamsStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.txt")
+
+	store, err := New(path)
+	require.Nil(t, err)
+	require.False(t, store.Seen("tmpl-a", "https://example.com/a?id=1", "query", "id"))
+	store.Add("tmpl-a", "https://example.com/a?id=1", "query", "id")
+	store.Add("tmpl-a", "https://example.com/b", "header", "X-Forwarded-For")
+	require.False(t, store.Seen("tmpl-a", "https://example.com/a?id=1", "query", "id"), "parameter added in current run reported as seen")
+	store.Close()
+
+	store, err = New(path)
+	require.Nil(t, err)
+	defer store.Close()
+	require.True(t, store.Seen("tmpl-a", "https://example.com/c?id=2", "query", "id"))
+	require.True(t, store.Seen("tmpl-a", "https://EXAMPLE.com", "header", "x-forwarded-for"))
+	require.False(t, store.Seen("tmpl-a", "https://example.com", "body", "id"))
+	require.False(t, store.Seen("tmpl-a", "https://other.com", "query", "id"))
+	require.False(t, store.Seen("tmpl-b", "https://example.com/c?id=2", "query", "id"), "parameter fuzzed by another template reported as seen")
+}
@@ -0,0 +1,85 @@
+package fuzz
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StatusFilter contains configuration for limiting fuzzing of a rule to
+// responses with interesting status codes.
+type StatusFilter struct {
+	// description: |
+	//   Status is the list of status codes (ex: 500) or classes (ex: 5xx)
+	//   of responses the rule is limited to.
+	// examples:
+	//   - value: >
+	//       []string{"5xx", "403"}
+	Status []string `yaml:"status,omitempty" json:"status,omitempty" jsonschema:"title=status codes or classes,description=Status codes (500) or classes (5xx) of responses the rule is limited to"`
+	// description: |
+	//   Baseline limits the rule on the status of the unmodified request
+	//   instead of the fuzzed responses. The rule is skipped for inputs
+	//   whose baseline status is not in the set and fuzzed normally otherwise.
+	Baseline bool `yaml:"baseline,omitempty" json:"baseline,omitempty" jsonschema:"title=filter on baseline status,description=Limit the rule on the status of the unmodified request instead of fuzzed responses"`
+	// description: |
+	//   SkipComponent skips the remaining payloads of a component (ex: query)
+	//   once a fuzzed response has a status not in the set. By default only
+	//   matchers are not evaluated for such responses.
+	SkipComponent bool `yaml:"skip-component,omitempty" json:"skip-component,omitempty" jsonschema:"title=skip component on filtered status,description=Skip the remaining payloads of a component once a response has a status not in the set"`
+
+	codes   map[int]struct{}
+	classes map[int]struct{}
+}
+
+// Compile validates the status filter and compiles its status codes and classes
+func (f *StatusFilter) Compile() error {
+	if len(f.Status) == 0 {
+		return errors.Errorf("status-filter status must not be empty")
+	}
+	if f.Baseline && f.SkipComponent {
+		return errors.Errorf("status-filter skip-component can not be used with baseline")
+	}
+	f.codes = make(map[int]struct{})
+	f.classes = make(map[int]struct{})
+	for _, value := range f.Status {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len(value) == 3 && strings.HasSuffix(value, "xx") {
+			class, err := strconv.Atoi(value[:1])
+			if err != nil || class < 1 || class > 5 {
+				return errors.Errorf("invalid status-filter class specified: %s", value)
+			}
+			f.classes[class] = struct{}{}
+			continue
+		}
+		code, err := strconv.Atoi(value)
+		if err != nil || code < 100 || code > 599 {
+			return errors.Errorf("invalid status-filter status specified: %s", value)
+		}
+		f.codes[code] = struct{}{}
+	}
+	return nil
+}
+
+// Allows returns true if the status code is in the set of the filter
+func (f *StatusFilter) Allows(statusCode int) bool {
+	if f == nil {
+		return true
+	}
+	if _, ok := f.codes[statusCode]; ok {
+		return true
+	}
+	_, ok := f.classes[statusCode/100]
+	return ok
+}
+
+// GatesResponses returns true if the filter applies to fuzzed responses
+// rather than to the baseline status of inputs
+func (f *StatusFilter) GatesResponses() bool {
+	return f != nil && !f.Baseline
+}
+
+// GatesBaseline returns true if the filter applies to the baseline status of inputs
+func (f *StatusFilter) GatesBaseline() bool {
+	return f != nil && f.Baseline
+}
@@ -0,0 +1,31 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusFilter(t *testing.T) {
+	rule := &Rule{StatusFilter: &StatusFilter{Status: []string{"5xx", "403"}}}
+	require.NoError(t, rule.Compile(nil, nil), "could not compile rule")
+	require.True(t, rule.StatusFilter.Allows(500), "could not allow status of class")
+	require.True(t, rule.StatusFilter.Allows(503), "could not allow status of class")
+	require.True(t, rule.StatusFilter.Allows(403), "could not allow status code")
+	require.False(t, rule.StatusFilter.Allows(404), "allowed filtered status code")
+	require.True(t, rule.StatusFilter.GatesResponses(), "could not gate responses")
+	require.False(t, rule.StatusFilter.GatesBaseline(), "gated baseline")
+
+	var filter *StatusFilter
+	require.True(t, filter.Allows(404), "nil filter filtered status")
+	require.False(t, filter.GatesResponses(), "nil filter gated responses")
+
+	for _, invalid := range []*StatusFilter{
+		{},
+		{Status: []string{"6xx"}},
+		{Status: []string{"abc"}},
+		{Status: []string{"500"}, Baseline: true, SkipComponent: true},
+	} {
+		require.Error(t, (&Rule{StatusFilter: invalid}).Compile(nil, nil), "compiled invalid status filter %v", invalid.Status)
+	}
+}
@@ -0,0 +1,143 @@
+// Package tarpit implements detection of hosts deliberately slowing down
+// responses to waste scanner time. Hosts whose median response latency over
+// a window of requests exceeds a threshold are suspected to be tarpits and
+// remaining fuzzing requests to them are skipped.
+package tarpit
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// DefaultWindow is the default number of requests per host the median
+// latency is computed over
+const DefaultWindow = 10
+
+// Detector tracks response latencies of hosts and flags tarpit suspected hosts
+type Detector struct {
+	threshold time.Duration
+	window    int
+	onDetect  func(host string)
+
+	mu    sync.Mutex
+	hosts map[string]*latencies
+}
+
+// latencies is the window of latest response latencies of a host
+type latencies struct {
+	samples []time.Duration
+	next    int
+	tarpit  bool
+}
+
+// New creates a detector flagging hosts whose median latency over window
+// requests exceeds threshold. onDetect is an optional callback invoked once
+// for every flagged host.
+func New(threshold time.Duration, window int, onDetect func(host string)) *Detector {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Detector{threshold: threshold, window: window, onDetect: onDetect, hosts: make(map[string]*latencies)}
+}
+
+// Observe records the response latency of a request to input
+func (d *Detector) Observe(input string, latency time.Duration) {
+	if d == nil {
+		return
+	}
+	host := hostOf(input)
+
+	d.mu.Lock()
+	state, ok := d.hosts[host]
+	if !ok {
+		state = &latencies{samples: make([]time.Duration, 0, d.window)}
+		d.hosts[host] = state
+	}
+	if state.tarpit {
+		d.mu.Unlock()
+		return
+	}
+	if len(state.samples) < d.window {
+		state.samples = append(state.samples, latency)
+	} else {
+		state.samples[state.next] = latency
+		state.next = (state.next + 1) % d.window
+	}
+	if len(state.samples) < d.window {
+		d.mu.Unlock()
+		return
+	}
+	value := median(state.samples)
+	if value < d.threshold {
+		d.mu.Unlock()
+		return
+	}
+	state.tarpit = true
+	state.samples = nil
+	d.mu.Unlock()
+
+	gologger.Warning().Msgf("Host %s is suspected to be a tarpit (median latency %s over %d requests exceeds %s), skipping remaining fuzzing requests\n", host, value.Round(time.Millisecond), d.window, d.threshold)
+	if d.onDetect != nil {
+		d.onDetect(host)
+	}
+}
+
+// Check returns true if the host of input is suspected to be a tarpit
+func (d *Detector) Check(input string) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.hosts[hostOf(input)]
+	return ok && state.tarpit
+}
+
+// Hosts returns the sorted list of tarpit suspected hosts
+func (d *Detector) Hosts() []string {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var hosts []string
+	for host, state := range d.hosts {
+		if state.tarpit {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// median returns the median of samples without modifying them
+func median(samples []time.Duration) time.Duration {
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// hostOf returns the host of the input latencies are tracked for
+func hostOf(input string) string {
+	if parsed, err := urlutil.Parse(input); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(input); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(input)
+}
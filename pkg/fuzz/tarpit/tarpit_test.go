@@ -0,0 +1,34 @@
+package tarpit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector(t *testing.T) {
+	var detected []string
+	detector := New(time.Second, 3, func(host string) {
+		detected = append(detected, host)
+	})
+
+	detector.Observe("https://slow.example.com/a", 5*time.Second)
+	detector.Observe("https://slow.example.com/b?x=1", 4*time.Second)
+	require.False(t, detector.Check("https://slow.example.com"), "host should not be flagged before the window is filled")
+
+	detector.Observe("slow.example.com:443", 100*time.Millisecond)
+	require.True(t, detector.Check("https://slow.example.com/c"), "could not flag host with high median latency")
+	require.Equal(t, []string{"slow.example.com"}, detected, "could not invoke detection callback once")
+
+	for i := 0; i < 5; i++ {
+		detector.Observe("https://fast.example.com", 50*time.Millisecond)
+	}
+	detector.Observe("https://fast.example.com", 10*time.Second)
+	require.False(t, detector.Check("https://fast.example.com"), "host with occasional slow response should not be flagged")
+	require.Equal(t, []string{"slow.example.com"}, detector.Hosts(), "could not get tarpit hosts")
+
+	var nilDetector *Detector
+	nilDetector.Observe("https://example.com", time.Minute)
+	require.False(t, nilDetector.Check("https://example.com"), "nil detector should not flag hosts")
+}
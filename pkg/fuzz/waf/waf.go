@@ -0,0 +1,136 @@
+// Package waf implements heuristics detecting web application firewalls
+// and filtering blocking fuzzing payloads, fingerprinting the apparent
+// vendor from blocked responses when possible.
+package waf
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultThreshold is the default number of blocked fuzzing responses
+// of an input after which a waf is reported
+const DefaultThreshold = 5
+
+// blockedStatusCodes are status codes commonly returned by waf on blocked requests
+var blockedStatusCodes = map[int]struct{}{
+	403: {},
+	406: {},
+	419: {},
+	429: {},
+	501: {},
+}
+
+// challengeMarkers are case-insensitive body markers of waf block and challenge pages
+var challengeMarkers = []string{
+	"captcha",
+	"cf-chl-",
+	"attention required!",
+	"access denied",
+	"request rejected",
+	"request blocked",
+	"web application firewall",
+	"has been blocked",
+}
+
+// fingerprint contains patterns identifying a waf vendor
+type fingerprint struct {
+	vendor  string
+	pattern *regexp.Regexp
+}
+
+// fingerprints are matched against headers and body of blocked responses
+var fingerprints = []fingerprint{
+	{vendor: "cloudflare", pattern: regexp.MustCompile(`(?i)server: cloudflare|cf-ray:|__cfduid|cf-chl-`)},
+	{vendor: "akamai", pattern: regexp.MustCompile(`(?i)server: akamaighost|akamai-grn|reference #[0-9a-f.]+`)},
+	{vendor: "imperva", pattern: regexp.MustCompile(`(?i)x-iinfo:|incap_ses_|visid_incap_|incapsula incident`)},
+	{vendor: "sucuri", pattern: regexp.MustCompile(`(?i)x-sucuri-id:|server: sucuri|sucuri website firewall`)},
+	{vendor: "aws", pattern: regexp.MustCompile(`(?i)server: awselb|x-amzn-waf-|aws-waf-token`)},
+	{vendor: "azure", pattern: regexp.MustCompile(`(?i)x-azure-ref:|azure front door`)},
+	{vendor: "f5-bigip", pattern: regexp.MustCompile(`(?i)the requested url was rejected|bigipserver|ts[0-9a-f]{6,}=`)},
+	{vendor: "modsecurity", pattern: regexp.MustCompile(`(?i)mod_security|modsecurity|not acceptable!`)},
+	{vendor: "fortiweb", pattern: regexp.MustCompile(`(?i)fortiwafsid=|fortigate|fortiweb`)},
+	{vendor: "barracuda", pattern: regexp.MustCompile(`(?i)barra_counter_session=|barracuda`)},
+	{vendor: "fastly", pattern: regexp.MustCompile(`(?i)x-fastly-request-id:|fastly error`)},
+}
+
+// IsBlocked returns true if the response looks like a waf block or challenge page
+func IsBlocked(statusCode int, body string) bool {
+	if _, ok := blockedStatusCodes[statusCode]; ok {
+		return true
+	}
+	lowered := strings.ToLower(body)
+	for _, marker := range challengeMarkers {
+		if strings.Contains(lowered, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint returns the apparent waf vendor of a response or empty
+// string if the vendor is unknown
+func Fingerprint(headers, body string) string {
+	for _, fingerprint := range fingerprints {
+		if fingerprint.pattern.MatchString(headers) || fingerprint.pattern.MatchString(body) {
+			return fingerprint.vendor
+		}
+	}
+	return ""
+}
+
+// Detector tracks fuzzing responses of an input and reports a waf when
+// the payloads are consistently blocked while a benign control request is not.
+type Detector struct {
+	threshold int
+
+	mu       sync.Mutex
+	control  bool
+	total    int
+	blocked  int
+	vendor   string
+	reported bool
+}
+
+// New creates a new detector reporting a waf after threshold blocked responses
+func New(threshold int) *Detector {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Detector{threshold: threshold}
+}
+
+// SetControl records the response of the benign control request. Detection
+// is disabled if the control request is blocked as well.
+func (d *Detector) SetControl(statusCode int, body string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.control = !IsBlocked(statusCode, body)
+}
+
+// Observe records a fuzzing response and returns true along with the apparent
+// vendor the first time payloads are considered blocked by a waf. Payloads are
+// considered blocked when at least threshold responses and at least half of
+// all responses are blocked.
+func (d *Detector) Observe(statusCode int, headers, body string) (bool, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.control || d.reported {
+		return false, ""
+	}
+	d.total++
+	if !IsBlocked(statusCode, body) {
+		return false, ""
+	}
+	d.blocked++
+	if d.vendor == "" {
+		d.vendor = Fingerprint(headers, body)
+	}
+	if d.blocked < d.threshold || d.blocked*2 < d.total {
+		return false, ""
+	}
+	d.reported = true
+	return true, d.vendor
+}
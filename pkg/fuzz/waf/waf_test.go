@@ -0,0 +1,40 @@
+package waf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	require.Equal(t, "cloudflare", Fingerprint("Server: cloudflare\r\nCF-RAY: 1234-AMS", ""), "could not fingerprint cloudflare")
+	require.Equal(t, "modsecurity", Fingerprint("", "This error was generated by Mod_Security."), "could not fingerprint modsecurity")
+	require.Equal(t, "", Fingerprint("Server: nginx", "hello"), "fingerprinted unknown vendor")
+}
+
+func TestDetector(t *testing.T) {
+	t.Run("blocked", func(t *testing.T) {
+		detector := New(3)
+		detector.SetControl(200, "welcome")
+		headers := "Server: cloudflare"
+
+		detected, _ := detector.Observe(200, "", "welcome")
+		require.False(t, detected, "detected waf on allowed response")
+		for i := 0; i < 2; i++ {
+			detected, _ = detector.Observe(403, headers, "Attention Required!")
+			require.False(t, detected, "detected waf below threshold")
+		}
+		detected, vendor := detector.Observe(403, headers, "Attention Required!")
+		require.True(t, detected, "could not detect waf")
+		require.Equal(t, "cloudflare", vendor, "could not fingerprint vendor")
+
+		detected, _ = detector.Observe(403, headers, "Attention Required!")
+		require.False(t, detected, "reported waf more than once")
+	})
+	t.Run("blocked-control", func(t *testing.T) {
+		detector := New(1)
+		detector.SetControl(403, "forbidden")
+		detected, _ := detector.Observe(403, "", "forbidden")
+		require.False(t, detected, "detected waf with blocked control request")
+	})
+}
@@ -0,0 +1,47 @@
+package fuzz
+
+import (
+	"sort"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// weightOf returns the priority weight of a set of payload values
+// which is the sum of weights of each of the values
+func (rule *Rule) weightOf(values map[string]interface{}) int {
+	var weight int
+	for _, value := range values {
+		weight += rule.Weights[types.ToString(value)]
+	}
+	return weight
+}
+
+// weightedValues returns the payload values of the iterator ordered by
+// descending weight. Values with equal weight keep their original order.
+func (rule *Rule) weightedValues(iterator *generators.Iterator) []map[string]interface{} {
+	var ordered []map[string]interface{}
+	for {
+		values, next := iterator.Value()
+		if !next {
+			break
+		}
+		ordered = append(ordered, values)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rule.weightOf(ordered[i]) > rule.weightOf(ordered[j])
+	})
+	return ordered
+}
+
+// applyWeights orders the fuzz values of a rule by descending weight
+func (rule *Rule) applyWeights(fuzz SliceOrMapSlice) SliceOrMapSlice {
+	if len(fuzz.Value) == 0 {
+		return fuzz
+	}
+	values := append([]string{}, fuzz.Value...)
+	sort.SliceStable(values, func(i, j int) bool {
+		return rule.Weights[values[i]] > rule.Weights[values[j]]
+	})
+	return SliceOrMapSlice{Value: values}
+}
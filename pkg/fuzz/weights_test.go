@@ -0,0 +1,29 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeights(t *testing.T) {
+	t.Run("fuzz-values", func(t *testing.T) {
+		rule := &Rule{Fuzz: SliceOrMapSlice{Value: []string{"a", "b", "c", "d"}}, Weights: map[string]int{"c": 10, "d": 5}}
+		err := rule.Compile(nil, nil)
+		require.NoError(t, err, "could not compile rule")
+		require.Equal(t, []string{"c", "d", "a", "b"}, rule.Fuzz.Value)
+	})
+	t.Run("payloads", func(t *testing.T) {
+		generator, err := generators.New(map[string]interface{}{"payload": []string{"a", "b", "c", "d"}}, generators.BatteringRamAttack, "", nil, "", types.DefaultOptions())
+		require.NoError(t, err, "could not create generator")
+
+		rule := &Rule{Weights: map[string]int{"d": 3, "b": 1}}
+		var ordered []string
+		for _, values := range rule.weightedValues(generator.NewIterator()) {
+			ordered = append(ordered, types.ToString(values["payload"]))
+		}
+		require.Equal(t, []string{"d", "b", "a", "c"}, ordered)
+	})
+}
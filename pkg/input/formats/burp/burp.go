@@ -1,14 +1,15 @@
 package burp
 
 import (
-	"encoding/base64"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
-	"github.com/projectdiscovery/utils/conversion"
 	"github.com/seh-msft/burpxml"
 )
 
@@ -42,26 +43,78 @@ func (j *BurpFormat) Parse(input string, resultsCb formats.ParseReqRespCallback)
 	}
 	defer file.Close()
 
+	// base64 encoded request and responses are decoded
+	// by the library into the Body field of the item
 	items, err := burpxml.Parse(file, true)
 	if err != nil {
 		return errors.Wrap(err, "could not decode burp xml schema")
 	}
 
-	// Print the parsed data for verification
 	for _, item := range items.Items {
 		item := item
-		binx, err := base64.StdEncoding.DecodeString(item.Request.Raw)
-		if err != nil {
-			return errors.Wrap(err, "could not decode base64")
-		}
-		if strings.TrimSpace(conversion.String(binx)) == "" {
+		rawRequest := getRaw(item.Request.Base64, item.Request.Raw, item.Request.Body)
+		if strings.TrimSpace(rawRequest) == "" {
 			continue
 		}
-		rawRequest, err := types.ParseRawRequestWithURL(conversion.String(binx), item.Url)
+		reqResp, err := types.ParseRawRequestWithURL(rawRequest, getItemURL(item))
 		if err != nil {
 			return errors.Wrap(err, "could not parse raw request")
 		}
-		resultsCb(rawRequest) // TODO: Handle false and true from callback
+
+		// preserve the observed response for baseline comparison
+		if rawResponse := getRaw(item.Response.Base64, item.Response.Raw, item.Response.Body); strings.TrimSpace(rawResponse) != "" {
+			resp, err := types.ParseRawResponse(rawResponse)
+			if err != nil {
+				gologger.Warning().Msgf("burp: Could not parse raw response %s: %s\n", reqResp.URL.String(), err)
+			} else {
+				reqResp.Response = resp
+			}
+		}
+		resultsCb(reqResp) // TODO: Handle false and true from callback
 	}
 	return nil
 }
+
+// getRaw returns the decoded raw data of a burp request or response
+func getRaw(base64, raw, decoded string) string {
+	if isBase64, _ := strconv.ParseBool(base64); isBase64 {
+		return decoded
+	}
+	return raw
+}
+
+// getItemURL returns the url of the burp item reconstructing it
+// from protocol, host, port and path if url is missing.
+func getItemURL(item burpxml.Item) string {
+	scheme := strings.ToLower(strings.TrimSpace(item.Protocol))
+	if item.Url != "" {
+		// burp urls may lack scheme or disagree with the protocol flag
+		idx := strings.Index(item.Url, "://")
+		switch {
+		case idx > 0 && scheme == "":
+			return item.Url
+		case idx > 0:
+			return scheme + item.Url[idx:]
+		case scheme == "":
+			scheme = "http"
+		}
+		return scheme + "://" + strings.TrimPrefix(item.Url, "//")
+	}
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	host := strings.TrimSpace(item.Host.Name)
+	if host == "" {
+		host = item.Host.Ip
+	}
+	port := strings.TrimSpace(item.Port)
+	if port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host = net.JoinHostPort(host, port)
+	}
+	path := item.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return scheme + "://" + host + path
+}
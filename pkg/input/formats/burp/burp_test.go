@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	"github.com/seh-msft/burpxml"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,3 +32,35 @@ func TestBurpParse(t *testing.T) {
 	}
 	require.ElementsMatch(t, expectedURLs, gotMethodsToURLs, "could not get burp urls")
 }
+
+func TestBurpParseResponse(t *testing.T) {
+	format := New()
+
+	var responses []*types.HttpResponse
+	err := format.Parse("../testdata/burp.xml", func(request *types.RequestResponse) bool {
+		responses = append(responses, request.Response)
+		return false
+	})
+	require.Nil(t, err, "could not parse burp xml")
+	require.Len(t, responses, 2, "could not get burp responses")
+
+	var statusCodes []int
+	for _, resp := range responses {
+		require.NotNil(t, resp, "could not get burp response")
+		statusCodes = append(statusCodes, resp.StatusCode)
+	}
+	require.ElementsMatch(t, []int{200, 301}, statusCodes, "could not get burp response status codes")
+}
+
+func TestBurpItemURL(t *testing.T) {
+	item := burpxml.Item{Protocol: "https", Port: "8443", Path: "/login"}
+	item.Host.Name = "example.com"
+	require.Equal(t, "https://example.com:8443/login", getItemURL(item))
+
+	item = burpxml.Item{Protocol: "https", Port: "443", Path: "/"}
+	item.Host.Name = "example.com"
+	require.Equal(t, "https://example.com/", getItemURL(item))
+
+	item = burpxml.Item{Protocol: "https", Url: "http://example.com/a"}
+	require.Equal(t, "https://example.com/a", getItemURL(item))
+}
@@ -74,7 +74,7 @@ func GenerateRequestsFromSchema(schema *openapi3.T, opts formats.InputFormatOpti
 	}
 
 	for _, serverURL := range schema.Servers {
-		pathURL := serverURL.URL
+		pathURL := expandServerVariables(serverURL, opts.Variables)
 
 		for path, v := range schema.Paths.Map() {
 			// a path item can have parameters
@@ -176,6 +176,8 @@ func generateRequestsFromOp(opts *generateReqOptions) error {
 	}
 
 	query := url.Values{}
+	// params contains the schema of parameters set in the request
+	var params []*httpTypes.ParameterSchema
 	for _, parameter := range reqParams {
 		value := parameter.Value
 
@@ -236,6 +238,7 @@ func generateRequestsFromOp(opts *generateReqOptions) error {
 			opts.requestPath = strings.Replace(opts.requestPath, fmt.Sprintf("{%s}", value.Name), "", -1)
 			continue // Skip this parameter if it is not required and we want only required ones
 		}
+		params = append(params, newParameterSchema(value.Name, value.In, value.Schema.Value, value.Required))
 
 		switch value.In {
 		case "query":
@@ -351,6 +354,8 @@ func generateRequestsFromOp(opts *generateReqOptions) error {
 			if err != nil {
 				return errors.Wrap(err, "could not parse raw request")
 			}
+			// capped to not share the backing array between body content types
+			rr.Parameters = append(params[:len(params):len(params)], bodyParameterSchemas(value.Schema.Value)...)
 			opts.callback(rr)
 			continue
 		}
@@ -368,6 +373,7 @@ func generateRequestsFromOp(opts *generateReqOptions) error {
 	if err != nil {
 		return errors.Wrap(err, "could not parse raw request")
 	}
+	rr.Parameters = params
 	opts.callback(rr)
 	return nil
 }
@@ -1,9 +1,12 @@
 package openapi
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
 	"github.com/stretchr/testify/require"
 )
@@ -61,3 +64,78 @@ func TestOpenAPIParser(t *testing.T) {
 		require.ElementsMatch(t, urls, methodToURLs[method], "invalid urls for method %s", method)
 	}
 }
+
+const typedSchema = `openapi: 3.0.0
+info:
+  title: typed
+  version: "1"
+servers:
+  - url: "https://{env}.{region}.example.com"
+    variables:
+      env:
+        default: api
+      region:
+        enum: [eu, us]
+paths:
+  /items:
+    get:
+      parameters:
+        - name: id
+          in: query
+          required: true
+          schema:
+            type: integer
+            example: 1
+        - name: sort
+          in: query
+          schema:
+            type: string
+            enum: [asc, desc]
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  example: item
+                count:
+                  type: integer
+                  example: 1
+`
+
+func TestOpenAPIParameterSchemas(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(input, []byte(typedSchema), 0600), "could not write schema")
+
+	format := New()
+	format.SetOptions(formats.InputFormatOptions{Variables: map[string]interface{}{"env": "staging"}})
+
+	requests := make(map[string]*types.RequestResponse)
+	err := format.Parse(input, func(rr *types.RequestResponse) bool {
+		requests[rr.Request.Method] = rr
+		return false
+	})
+	require.NoError(t, err, "could not parse schema")
+	require.Len(t, requests, 2)
+
+	get := requests["GET"]
+	require.True(t, strings.HasPrefix(get.URL.String(), "https://staging.eu.example.com/items"), "could not expand server variables: %s", get.URL.String())
+	id := get.Parameter("query", "id")
+	require.NotNil(t, id, "could not get schema of query parameter")
+	require.Equal(t, "integer", id.Type)
+	require.True(t, id.Required)
+	sort := get.Parameter("query", "sort")
+	require.NotNil(t, sort, "could not get schema of query parameter")
+	require.Equal(t, []string{"asc", "desc"}, sort.Enum)
+
+	post := requests["POST"]
+	name := post.Parameter("body", "name")
+	require.NotNil(t, name, "could not get schema of body parameter")
+	require.Equal(t, "string", name.Type)
+	require.True(t, name.Required)
+	require.Equal(t, "integer", post.Parameter("body", "count").Type)
+}
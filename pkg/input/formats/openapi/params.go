@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	httpTypes "github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/valyala/fasttemplate"
+)
+
+// expandServerVariables returns the url of the server with its variables
+// replaced by the value of global variables with the same name or their
+// default value
+func expandServerVariables(server *openapi3.Server, variables map[string]interface{}) string {
+	if len(server.Variables) == 0 {
+		return server.URL
+	}
+	values := make(map[string]interface{}, len(server.Variables))
+	for name, variable := range server.Variables {
+		value := variable.Default
+		if val, ok := variables[name]; ok {
+			value = types.ToString(val)
+		} else if value == "" && len(variable.Enum) > 0 {
+			value = variable.Enum[0]
+		}
+		values[name] = value
+	}
+	return fasttemplate.ExecuteStringStd(server.URL, "{", "}", values)
+}
+
+// newParameterSchema returns the schema of a parameter used by the fuzz engine
+func newParameterSchema(name, in string, schema *openapi3.Schema, required bool) *httpTypes.ParameterSchema {
+	param := &httpTypes.ParameterSchema{
+		Name:      name,
+		In:        in,
+		Type:      schema.Type,
+		Format:    schema.Format,
+		Required:  required,
+		Pattern:   schema.Pattern,
+		Minimum:   schema.Min,
+		Maximum:   schema.Max,
+		MaxLength: schema.MaxLength,
+	}
+	for _, value := range schema.Enum {
+		param.Enum = append(param.Enum, fmt.Sprint(value))
+	}
+	return param
+}
+
+// bodyParameterSchemas returns the schema of top level properties of a request body
+func bodyParameterSchemas(schema *openapi3.Schema) []*httpTypes.ParameterSchema {
+	if schema == nil {
+		return nil
+	}
+	required := make(map[string]struct{}, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = struct{}{}
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var params []*httpTypes.ParameterSchema
+	for _, name := range names {
+		property := schema.Properties[name]
+		if property == nil || property.Value == nil {
+			continue
+		}
+		_, ok := required[name]
+		params = append(params, newParameterSchema(name, "body", property.Value, ok))
+	}
+	return params
+}
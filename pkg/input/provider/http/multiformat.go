@@ -11,6 +11,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats/openapi"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats/swagger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats/yaml"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 )
@@ -23,14 +24,21 @@ type HttpMultiFormatOptions struct {
 	InputFile string
 	// InputMode is the mode of input
 	InputMode string
+	// ReplayTargets are the targets every request of the input is replayed
+	// against instead of its captured host
+	ReplayTargets []string
+	// Shard is the slice of requests to provide when sharding input
+	Shard *shard.Shard
 }
 
 // HttpInputProvider implements an input provider for nuclei that loads
 // inputs from multiple formats like burp, openapi, postman,proxify, etc.
 type HttpInputProvider struct {
-	format    formats.Format
-	inputFile string
-	count     int64
+	format        formats.Format
+	inputFile     string
+	count         int64
+	replayTargets []string
+	shard         *shard.Shard
 }
 
 // NewHttpInputProvider creates a new input provider for nuclei from a file
@@ -49,13 +57,18 @@ func NewHttpInputProvider(opts *HttpMultiFormatOptions) (*HttpInputProvider, err
 	// and get the count of the input file as well
 	count := int64(0)
 	parseErr := format.Parse(opts.InputFile, func(request *types.RequestResponse) bool {
-		count++
+		if opts.Shard.Contains(request.URL.String()) {
+			count++
+		}
 		return false
 	})
 	if parseErr != nil {
 		return nil, errors.Wrap(parseErr, "could not parse input file")
 	}
-	return &HttpInputProvider{format: format, inputFile: opts.InputFile, count: count}, nil
+	if len(opts.ReplayTargets) > 0 {
+		count *= int64(len(opts.ReplayTargets))
+	}
+	return &HttpInputProvider{format: format, inputFile: opts.InputFile, count: count, replayTargets: opts.ReplayTargets, shard: opts.Shard}, nil
 }
 
 // Count returns the number of items for input provider
@@ -66,9 +79,26 @@ func (i *HttpInputProvider) Count() int64 {
 // Iterate over all inputs in order
 func (i *HttpInputProvider) Iterate(callback func(value *contextargs.MetaInput) bool) {
 	err := i.format.Parse(i.inputFile, func(request *types.RequestResponse) bool {
-		return callback(&contextargs.MetaInput{
-			ReqResp: request,
-		})
+		// replayed requests are assigned to the shard of the captured request
+		if !i.shard.Contains(request.URL.String()) {
+			return true
+		}
+		if len(i.replayTargets) == 0 {
+			return callback(&contextargs.MetaInput{
+				ReqResp: request,
+			})
+		}
+		for _, target := range i.replayTargets {
+			replayed, err := request.Retarget(target)
+			if err != nil {
+				gologger.Warning().Msgf("Could not replay request %s against %s: %s\n", request.URL.String(), target, err)
+				continue
+			}
+			if !callback(&contextargs.MetaInput{ReqResp: replayed}) {
+				return false
+			}
+		}
+		return true
 	})
 	if err != nil {
 		gologger.Warning().Msgf("Could not parse input file while iterating: %s\n", err)
@@ -3,17 +3,20 @@ package provider
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/formats"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider/list"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	configTypes "github.com/projectdiscovery/nuclei/v3/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
+	readerutil "github.com/projectdiscovery/utils/reader"
 )
 
 var (
@@ -47,6 +50,8 @@ var (
 	_ InputProvider = &http.HttpInputProvider{}
 	// ListInputProvider provides support for simple list of urls or files etc
 	_ InputProvider = &list.ListInputProvider{}
+	// StreamInputProvider provides support for streaming targets from stdin
+	_ InputProvider = &StreamInputProvider{}
 )
 
 // InputProvider is unified input provider interface that provides
@@ -99,6 +104,16 @@ func NewInputProvider(opts InputOptions) (InputProvider, error) {
 		}
 	}
 
+	// stream targets from stdin instead of loading them up front
+	if opts.Options.StdinStream && opts.Options.Stdin {
+		streamProvider := NewStreamInputProvider(readerutil.TimeoutReader{Reader: os.Stdin, Timeout: opts.Options.InputReadTimeout})
+		streamProvider.shard = shard.New(opts.Options.ShardIndex, opts.Options.ShardCount)
+		for _, target := range opts.Options.Targets {
+			streamProvider.Set(target)
+		}
+		return streamProvider, nil
+	}
+
 	// check if input provider is supported
 	if strings.EqualFold(opts.Options.InputFileMode, "list") {
 		// create a new list input provider
@@ -109,8 +124,10 @@ func NewInputProvider(opts InputOptions) (InputProvider, error) {
 	} else {
 		// use HttpInputProvider
 		return http.NewHttpInputProvider(&http.HttpMultiFormatOptions{
-			InputFile: opts.Options.TargetsFilePath,
-			InputMode: opts.Options.InputFileMode,
+			InputFile:     opts.Options.TargetsFilePath,
+			InputMode:     opts.Options.InputFileMode,
+			ReplayTargets: opts.Options.ReplayTargets,
+			Shard:         shard.New(opts.Options.ShardIndex, opts.Options.ShardCount),
 			Options: formats.InputFormatOptions{
 				Variables:            generators.MergeMaps(extraVars, opts.Options.Vars.AsMap()),
 				SkipFormatValidation: opts.Options.SkipFormatValidation,
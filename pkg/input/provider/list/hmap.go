@@ -19,6 +19,7 @@ import (
 	"github.com/projectdiscovery/hmap/filekv"
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/projectdiscovery/mapcidr/asn"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
 	providerTypes "github.com/projectdiscovery/nuclei/v3/pkg/input/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
@@ -43,6 +44,8 @@ type ListInputProvider struct {
 	excludedCount     int64
 	dupeCount         int64
 	skippedCount      int64
+	shardSkippedCount int64
+	shard             *shard.Shard
 	hostMap           *hybrid.HybridMap
 	excludedHosts     map[string]struct{}
 	hostMapStream     *filekv.FileDB
@@ -77,6 +80,7 @@ func New(opts *Options) (*ListInputProvider, error) {
 			IPV6:       sliceutil.Contains(options.IPVersion, "6"),
 		},
 		excludedHosts: make(map[string]struct{}),
+		shard:         shard.New(options.ShardIndex, options.ShardCount),
 	}
 	if options.Stream {
 		fkvOptions := filekv.DefaultOptions
@@ -104,6 +108,9 @@ func New(opts *Options) (*ListInputProvider, error) {
 	if input.skippedCount > 0 {
 		gologger.Info().Msgf("Number of hosts skipped from input due to exclusion: %d", input.skippedCount)
 	}
+	if input.shardSkippedCount > 0 {
+		gologger.Info().Msgf("Number of hosts skipped from input for other shards (shard %s): %d", input.shard, input.shardSkippedCount)
+	}
 	return input, nil
 }
 
@@ -457,6 +464,11 @@ func (i *ListInputProvider) setItem(metaInput *contextargs.MetaInput) {
 		i.dupeCount++
 		return
 	}
+	// all the ips of a host are assigned to the same shard
+	if !i.shard.Contains(metaInput.Input) {
+		i.shardSkippedCount++
+		return
+	}
 
 	i.inputCount++ // tracks target count
 	_ = i.hostMap.Set(key, nil)
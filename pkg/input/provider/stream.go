@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/mapcidr/asn"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/expand"
+	iputil "github.com/projectdiscovery/utils/ip"
+)
+
+const (
+	// StreamInputProviderType is the type of stream input provider
+	StreamInputProviderType = "StreamInputProvider"
+	// streamBufferSize is the number of targets read ahead of the scan
+	streamBufferSize = 100
+	// streamDedupeWindow is the number of recent targets used for deduplication
+	streamDedupeWindow = 10000
+)
+
+// StreamInputProvider is an input provider consuming targets from a reader
+// (ex: stdin pipe) as a stream instead of loading all of them up front.
+//
+// Targets are read ahead with bounded buffering so that the scan applies
+// backpressure on the producer, and are deduplicated within a bounded window
+// of recent targets. Targets of other shards are skipped as they are read
+// since sharding does not depend on the whole input. A stream can only be
+// iterated once and hence must be used with host-spray scan strategy.
+type StreamInputProvider struct {
+	reader  io.Reader
+	queued  []*contextargs.MetaInput
+	dedupe  *dedupeWindow
+	shard   *shard.Shard
+	count   atomic.Int64
+	mu      sync.Mutex
+	started bool
+}
+
+// NewStreamInputProvider creates a new stream input provider reading targets from reader
+func NewStreamInputProvider(reader io.Reader) *StreamInputProvider {
+	return &StreamInputProvider{
+		reader: reader,
+		dedupe: newDedupeWindow(streamDedupeWindow),
+	}
+}
+
+// Count returns the number of targets read from the stream so far
+func (s *StreamInputProvider) Count() int64 {
+	return s.count.Load()
+}
+
+// Iterate over all inputs in order as they are read from the stream
+func (s *StreamInputProvider) Iterate(callback func(value *contextargs.MetaInput) bool) {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		gologger.Warning().Msgf("Stream input can only be iterated once, use host-spray scan strategy\n")
+		return
+	}
+	s.started = true
+	queued := s.queued
+	s.queued = nil
+	s.mu.Unlock()
+
+	for _, input := range queued {
+		if !callback(input) {
+			return
+		}
+	}
+
+	items := make(chan string, streamBufferSize)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(items)
+		scanner := bufio.NewScanner(s.reader)
+		for scanner.Scan() {
+			for _, item := range expandStreamItem(scanner.Text()) {
+				select {
+				case items <- item:
+				case <-done:
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			gologger.Warning().Msgf("Could not read stream input: %s\n", err)
+		}
+	}()
+
+	for item := range items {
+		if !s.shard.Contains(item) || !s.dedupe.Add(item) {
+			continue
+		}
+		s.count.Add(1)
+		if !callback(&contextargs.MetaInput{Input: item}) {
+			return
+		}
+	}
+}
+
+// Set adds an item to the input provider to be iterated before the stream
+func (s *StreamInputProvider) Set(value string) {
+	for _, item := range expandStreamItem(value) {
+		if !s.shard.Contains(item) || !s.dedupe.Add(item) {
+			continue
+		}
+		s.mu.Lock()
+		s.queued = append(s.queued, &contextargs.MetaInput{Input: item})
+		s.mu.Unlock()
+		s.count.Add(1)
+	}
+}
+
+// SetWithProbe adds an item to the input provider with HTTP probing
+func (s *StreamInputProvider) SetWithProbe(value string, probe types.InputLivenessProbe) error {
+	probedValue, err := probe.ProbeURL(value)
+	if err != nil {
+		return err
+	}
+	s.Set(probedValue)
+	return nil
+}
+
+// SetWithExclusions adds an item to the input provider
+func (s *StreamInputProvider) SetWithExclusions(value string) error {
+	s.Set(value)
+	return nil
+}
+
+// InputType returns the type of input provider
+func (s *StreamInputProvider) InputType() string {
+	return StreamInputProviderType
+}
+
+// Close the input provider and cleanup any resources
+func (s *StreamInputProvider) Close() {
+	// no-op
+}
+
+// expandStreamItem returns targets for a line of input expanding cidr and asn
+func expandStreamItem(value string) []string {
+	item := strings.TrimSpace(value)
+	switch {
+	case item == "":
+		return nil
+	case iputil.IsCIDR(item):
+		return expand.CIDR(item)
+	case asn.IsASN(item):
+		return expand.ASN(item)
+	default:
+		return []string{item}
+	}
+}
+
+// dedupeWindow is a set of most recently added items with bounded size
+type dedupeWindow struct {
+	mu    sync.Mutex
+	items map[string]struct{}
+	ring  []string
+	next  int
+}
+
+func newDedupeWindow(size int) *dedupeWindow {
+	return &dedupeWindow{items: make(map[string]struct{}, size), ring: make([]string, size)}
+}
+
+// Add adds item to the window returning false if it was already present
+func (d *dedupeWindow) Add(item string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.items[item]; ok {
+		return false
+	}
+	if evicted := d.ring[d.next]; evicted != "" {
+		delete(d.items, evicted)
+	}
+	d.ring[d.next] = item
+	d.next = (d.next + 1) % len(d.ring)
+	d.items[item] = struct{}{}
+	return true
+}
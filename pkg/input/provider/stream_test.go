@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider/list"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/shard"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamInputProvider(t *testing.T) {
+	reader := strings.NewReader("b.com\na.com\n\nb.com\nc.com\n")
+	provider := NewStreamInputProvider(reader)
+	provider.Set("a.com")
+	provider.Set("d.com")
+
+	var got []string
+	provider.Iterate(func(value *contextargs.MetaInput) bool {
+		got = append(got, value.Input)
+		return true
+	})
+	require.Equal(t, []string{"a.com", "d.com", "b.com", "c.com"}, got)
+	require.Equal(t, int64(4), provider.Count())
+
+	t.Run("stop", func(t *testing.T) {
+		provider := NewStreamInputProvider(strings.NewReader("a.com\nb.com\nc.com\n"))
+		var got []string
+		provider.Iterate(func(value *contextargs.MetaInput) bool {
+			got = append(got, value.Input)
+			return len(got) < 2
+		})
+		require.Equal(t, []string{"a.com", "b.com"}, got)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		window := newDedupeWindow(2)
+		require.True(t, window.Add("a"))
+		require.False(t, window.Add("a"))
+		require.True(t, window.Add("b"))
+		require.True(t, window.Add("c"))
+		require.True(t, window.Add("a"), "evicted item should be added again")
+	})
+}
+
+func TestShardedInputProviders(t *testing.T) {
+	var targets []string
+	for i := 0; i < 50; i++ {
+		targets = append(targets, fmt.Sprintf("https://host%d.example.com", i))
+	}
+
+	seen := make(map[string]int)
+	for index := 0; index < 3; index++ {
+		stream := NewStreamInputProvider(strings.NewReader(strings.Join(targets, "\n")))
+		stream.shard = shard.New(index, 3)
+		var streamed []string
+		stream.Iterate(func(value *contextargs.MetaInput) bool {
+			streamed = append(streamed, value.Input)
+			return true
+		})
+
+		options := types.DefaultOptions()
+		options.Targets = targets
+		options.IPVersion = []string{"4"}
+		options.ShardIndex = index
+		options.ShardCount = 3
+		listProvider, err := list.New(&list.Options{Options: options})
+		require.Nil(t, err)
+		var listed []string
+		listProvider.Iterate(func(value *contextargs.MetaInput) bool {
+			listed = append(listed, value.Input)
+			return true
+		})
+		listProvider.Close()
+
+		require.ElementsMatch(t, streamed, listed, "stream and list providers should agree on shard %d", index)
+		for _, target := range streamed {
+			seen[target]++
+		}
+	}
+	require.Len(t, seen, len(targets), "all targets should be assigned to a shard")
+	for target, count := range seen {
+		require.Equal(t, 1, count, "target %s should belong to a single shard", target)
+	}
+}
@@ -0,0 +1,73 @@
+// Package shard implements deterministic partitioning of scan targets
+// between multiple nuclei instances (ex: nodes of a cluster) so that
+// each instance scans a disjoint slice of the same input.
+//
+// A target belongs to the shard whose index equals the hash of the
+// normalized target modulo the shard count. The hash does not depend on
+// the order or the number of inputs, hence sharding is stable across
+// runs and works with streamed input.
+//
+// Deduplication of inputs is performed before sharding, since duplicates
+// hash to the same shard this does not change the partitioning. The
+// resume file of an instance is only valid for the shard it was created
+// with and hence must be used with the same shard options.
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Shard is a slice of the targets to be scanned by an instance
+type Shard struct {
+	Index int
+	Count int
+}
+
+// New returns the shard with the given index and count. A nil shard
+// containing all targets is returned if sharding is disabled (count <= 1).
+func New(index, count int) *Shard {
+	if count <= 1 {
+		return nil
+	}
+	return &Shard{Index: index, Count: count}
+}
+
+// Validate validates the shard index and count
+func Validate(index, count int) error {
+	if count < 0 {
+		return fmt.Errorf("invalid shard count %d", count)
+	}
+	if count == 0 && index != 0 {
+		return fmt.Errorf("shard index requires shard count")
+	}
+	if count > 0 && (index < 0 || index >= count) {
+		return fmt.Errorf("shard index %d must be in range [0, %d)", index, count)
+	}
+	return nil
+}
+
+// Contains returns true if the target belongs to the shard
+func (s *Shard) Contains(target string) bool {
+	if s == nil {
+		return true
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(normalize(target)))
+	return hasher.Sum64()%uint64(s.Count) == uint64(s.Index)
+}
+
+// String returns the shard as index/count
+func (s *Shard) String() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// normalize returns the target used for hashing so that
+// formatting differences do not change the shard of a target
+func normalize(target string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(target)), "/")
+}
@@ -0,0 +1,97 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+		count int
+		valid bool
+	}{
+		{name: "disabled", index: 0, count: 0, valid: true},
+		{name: "index-without-count", index: 1, count: 0, valid: false},
+		{name: "negative-count", index: 0, count: -1, valid: false},
+		{name: "negative-index", index: -1, count: 3, valid: false},
+		{name: "index-equal-count", index: 3, count: 3, valid: false},
+		{name: "index-above-count", index: 4, count: 3, valid: false},
+		{name: "first", index: 0, count: 3, valid: true},
+		{name: "last", index: 2, count: 3, valid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.index, test.count)
+			if test.valid {
+				require.NoError(t, err, "could not validate shard %d/%d", test.index, test.count)
+			} else {
+				require.Error(t, err, "could validate invalid shard %d/%d", test.index, test.count)
+			}
+		})
+	}
+}
+
+func TestShardPartition(t *testing.T) {
+	targets := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		targets = append(targets, fmt.Sprintf("https://host-%d.example.com", i))
+	}
+
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{name: "disabled", count: 1},
+		{name: "two", count: 2},
+		{name: "seven", count: 7},
+		{name: "more-shards-than-targets", count: 2000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			owners := make(map[string]int, len(targets))
+			for index := 0; index < test.count; index++ {
+				shard := New(index, test.count)
+				for _, target := range targets {
+					if !shard.Contains(target) {
+						continue
+					}
+					previous, ok := owners[target]
+					require.False(t, ok, "target %s in shards %d and %d", target, previous, index)
+					owners[target] = index
+				}
+			}
+			require.Len(t, owners, len(targets), "could not cover all targets")
+
+			// assignment is stable across runs and does not depend on the other targets
+			for _, target := range targets[:10] {
+				shard := New(owners[target], test.count)
+				require.True(t, shard.Contains(target), "could not get stable shard of %s", target)
+			}
+		})
+	}
+}
+
+func TestShardStable(t *testing.T) {
+	// shards of targets must not change between runs and releases since
+	// instances of a cluster may run different versions
+	expected := map[string]int{
+		"https://example.com": 3,
+		"https://scanme.sh":   2,
+		"192.168.1.1:8080":    2,
+		"example.org":         1,
+	}
+	for target, index := range expected {
+		require.True(t, New(index, 4).Contains(target), "could not get stable shard of %s", target)
+	}
+}
+
+func TestShardNormalize(t *testing.T) {
+	shard := New(0, 5)
+	for _, target := range []string{"https://Example.com/", " https://example.com", "HTTPS://EXAMPLE.COM"} {
+		require.Equal(t, shard.Contains("https://example.com"), shard.Contains(target), "could not normalize %q", target)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/textproto"
+	"sort"
 	"strings"
 	"sync"
 
@@ -35,6 +37,9 @@ type RequestResponse struct {
 	Request *HttpRequest `json:"request"`
 	// Response is the response of the request
 	Response *HttpResponse `json:"response"`
+	// Parameters contains the schema of parameters of the request
+	// defined by api specification inputs (if any)
+	Parameters []*ParameterSchema `json:"parameters,omitempty"`
 
 	// unexported / internal fields
 	// lazy build request
@@ -46,7 +51,8 @@ type RequestResponse struct {
 // Clone clones the request response
 func (rr *RequestResponse) Clone() *RequestResponse {
 	cloned := &RequestResponse{
-		URL: *rr.URL.Clone(),
+		URL:        *rr.URL.Clone(),
+		Parameters: rr.Parameters,
 	}
 	if rr.Request != nil {
 		cloned.Request = rr.Request.Clone()
@@ -114,6 +120,9 @@ func (rr *RequestResponse) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 	m["response"] = respBin
+	if len(rr.Parameters) > 0 {
+		m["parameters"] = rr.Parameters
+	}
 	return json.Marshal(m)
 }
 
@@ -150,6 +159,12 @@ func (rr *RequestResponse) UnmarshalJSON(data []byte) error {
 		}
 		rr.Response = &resp
 	}
+
+	if params, ok := m["parameters"]; ok {
+		if err := json.Unmarshal(params, &rr.Parameters); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -290,6 +305,37 @@ func ParseRawRequest(raw string) (rr *RequestResponse, err error) {
 	return rr, nil
 }
 
+// ParseRawResponse parses a raw http response from a string
+func ParseRawResponse(raw string) (*HttpResponse, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(strings.NewReader(raw)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %s", err)
+	}
+	defer resp.Body.Close()
+
+	hr := &HttpResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    mapsutil.NewOrderedMap[string, string](),
+		Raw:        raw,
+	}
+	keys := make([]string, 0, len(resp.Header))
+	for k := range resp.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		hr.Headers.Set(k, strings.Join(resp.Header[k], ", "))
+	}
+	// exported responses may have a content-length not matching the
+	// actual body so tolerate truncated bodies
+	body, err := io.ReadAll(resp.Body)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read response body: %s", err)
+	}
+	hr.Body = conversion.String(body)
+	return hr, nil
+}
+
 // ParseRawRequestWithURL parses a raw request from a string with given url
 func ParseRawRequestWithURL(raw, url string) (rr *RequestResponse, err error) {
 	rr, err = ParseRawRequest(raw)
@@ -303,3 +349,71 @@ func ParseRawRequestWithURL(raw, url string) (rr *RequestResponse, err error) {
 	rr.URL = *urlx
 	return rr, nil
 }
+
+// Retarget returns a copy of the request response sent to target instead
+// of the captured host. target is a host (keeping the captured scheme) or
+// a url whose scheme and host are used.
+//
+// Path, headers and body are preserved while the host header and absolute
+// urls of the captured origin in headers and body are rewritten.
+// The captured response is dropped since it does not belong to target.
+func (rr *RequestResponse) Retarget(target string) (*RequestResponse, error) {
+	target = strings.TrimSpace(target)
+	if !strings.Contains(target, "://") {
+		target = rr.URL.Scheme + "://" + target
+	}
+	parsed, err := urlutil.ParseAbsoluteURL(target, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse target %s: %s", target, err)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("target %s does not contain a host", target)
+	}
+
+	oldOrigin := rr.URL.Scheme + "://" + rr.URL.Host
+	newOrigin := parsed.Scheme + "://" + parsed.Host
+	replacer := strings.NewReplacer(
+		oldOrigin, newOrigin,
+		strings.ReplaceAll(oldOrigin, "/", `\/`), strings.ReplaceAll(newOrigin, "/", `\/`),
+	)
+
+	cloned := &RequestResponse{URL: *rr.URL.Clone(), Parameters: rr.Parameters}
+	cloned.URL.Scheme = parsed.Scheme
+	cloned.URL.Host = parsed.Host
+	if rr.Request == nil {
+		return cloned, nil
+	}
+	cloned.Request = rr.Request.Clone()
+	cloned.Request.Body = replacer.Replace(rr.Request.Body)
+	cloned.Request.Raw = replacer.Replace(rr.Request.Raw)
+
+	headers := mapsutil.NewOrderedMap[string, string]()
+	rr.Request.Headers.Iterate(func(k, v string) bool {
+		switch {
+		case strings.EqualFold(k, "Host"):
+			v = parsed.Host
+		case strings.EqualFold(k, "Content-Length") && len(cloned.Request.Body) != len(rr.Request.Body):
+			v = fmt.Sprint(len(cloned.Request.Body))
+		default:
+			v = replacer.Replace(v)
+		}
+		headers.Set(k, v)
+		return true
+	})
+	cloned.Request.Headers = headers
+
+	if cloned.Request.Raw != "" {
+		// rewrite the host header of the raw request as well
+		lines := strings.SplitAfter(cloned.Request.Raw, "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				break
+			}
+			if name, _, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Host") {
+				lines[i] = name + ": " + parsed.Host + line[len(strings.TrimRight(line, "\r\n")):]
+			}
+		}
+		cloned.Request.Raw = strings.Join(lines, "")
+	}
+	return cloned, nil
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -65,3 +66,35 @@ func TestParseHttpRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestRetargetRequest(t *testing.T) {
+	raw := "POST /api/v1/users?next=https://prod.example.com/home HTTP/1.1\r\nHost: prod.example.com\r\nOrigin: https://prod.example.com\r\nContent-Type: application/json\r\nContent-Length: 61\r\n\r\n{\"avatar\":\"https:\\/\\/prod.example.com\\/a.png\",\"name\":\"nuclei\"}"
+	rr, err := ParseRawRequestWithURL(raw, "https://prod.example.com/api/v1/users?next=https://prod.example.com/home")
+	require.NoError(t, err, "could not parse raw request")
+	rr.Response = &HttpResponse{StatusCode: 200}
+	// host header of captured requests of other formats is part of headers
+	rr.Request.Headers.Set("Host", "prod.example.com")
+
+	replayed, err := rr.Retarget("staging.example.com:8443")
+	require.NoError(t, err, "could not retarget request")
+	require.Equal(t, "https://staging.example.com:8443/api/v1/users?next=https://prod.example.com/home", replayed.URL.String(), "could not rewrite url")
+	require.Nil(t, replayed.Response, "captured response should be dropped")
+
+	host, _ := replayed.Request.Headers.Get("Host")
+	require.Equal(t, "staging.example.com:8443", host, "could not rewrite host header")
+	origin, _ := replayed.Request.Headers.Get("Origin")
+	require.Equal(t, "https://staging.example.com:8443", origin, "could not rewrite origin header")
+	require.Equal(t, `{"avatar":"https:\/\/staging.example.com:8443\/a.png","name":"nuclei"}`, replayed.Request.Body, "could not rewrite body urls")
+	length, _ := replayed.Request.Headers.Get("Content-Length")
+	require.Equal(t, strconv.Itoa(len(replayed.Request.Body)), length, "could not update content length")
+	require.Contains(t, replayed.Request.Raw, "Host: staging.example.com:8443\r\n", "could not rewrite raw host header")
+
+	req, err := replayed.BuildRequest()
+	require.NoError(t, err, "could not build replayed request")
+	require.Equal(t, "staging.example.com:8443", req.URL.Host, "could not build request for target")
+
+	replayed, err = rr.Retarget("http://dev.example.com/ignored")
+	require.NoError(t, err, "could not retarget request with scheme")
+	require.Equal(t, "http://dev.example.com/api/v1/users?next=https://prod.example.com/home", replayed.URL.String(), "could not rewrite scheme")
+	require.Equal(t, "https://prod.example.com/api/v1/users?next=https://prod.example.com/home", rr.URL.String(), "original request should not be modified")
+}
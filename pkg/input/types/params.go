@@ -0,0 +1,45 @@
+package types
+
+import "strings"
+
+// ParameterSchema is the schema of a request parameter defined by
+// api specification inputs (ex: openapi) used to fuzz parameters per
+// their type and constraints.
+type ParameterSchema struct {
+	// Name is the name of the parameter
+	Name string `json:"name"`
+	// In is the location of the parameter (query, header, path, cookie, body)
+	In string `json:"in"`
+	// Type is the type of the parameter (ex: string, integer, boolean)
+	Type string `json:"type,omitempty"`
+	// Format is the format of the parameter (ex: uuid, date-time, int64)
+	Format string `json:"format,omitempty"`
+	// Enum contains the allowed values of the parameter
+	Enum []string `json:"enum,omitempty"`
+	// Required is true if the parameter is required
+	Required bool `json:"required,omitempty"`
+	// Pattern is the regex values of the parameter must match
+	Pattern string `json:"pattern,omitempty"`
+	// Minimum and Maximum are the bounds of numeric parameters
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MaxLength is the maximum length of string parameters
+	MaxLength *uint64 `json:"max-length,omitempty"`
+}
+
+// Parameter returns the schema of the parameter at location with name
+// or nil if the input does not define it. Header names are case-insensitive.
+func (rr *RequestResponse) Parameter(in, name string) *ParameterSchema {
+	if rr == nil {
+		return nil
+	}
+	for _, param := range rr.Parameters {
+		if param.In != in {
+			continue
+		}
+		if param.Name == name || (in == "header" && strings.EqualFold(param.Name, name)) {
+			return param
+		}
+	}
+	return nil
+}
@@ -113,6 +113,22 @@ func init() {
 		return port, nil
 	}))
 
+	_ = dsl.AddFunction(dsl.NewWithSingleSignature("get_header",
+		"(headers map, name string) interface{}",
+		false, func(args ...interface{}) (interface{}, error) {
+			if len(args) != 2 {
+				return nil, dsl.ErrInvalidDslFunction
+			}
+			headers, ok := args[0].(map[string]interface{})
+			if !ok {
+				return "", nil
+			}
+			if value, ok := headers[strings.ToLower(types.ToString(args[1]))]; ok {
+				return value, nil
+			}
+			return "", nil
+		}))
+
 	dsl.PrintDebugCallback = func(args ...interface{}) error {
 		gologger.Info().Msgf("print_debug value: %s", fmt.Sprint(args))
 		return nil
@@ -0,0 +1,205 @@
+package dsl
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/dsl"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// User defined functions are javascript functions declared at the top level
+// of a script file which are callable by name from dsl expressions of
+// templates (matchers, extractors, variables, requests and fuzzing payloads).
+//
+//	// sign(secret, data) returns the signature of data
+//	function sign(secret, data) {
+//		return _checksum(secret + ":" + data).toString(16);
+//	}
+//
+//	// _checksum returns the 32-bit string hash of value
+//	function _checksum(value) {
+//		var hash = 0;
+//		for (var i = 0; i < value.length; i++) {
+//			hash = (hash * 31 + value.charCodeAt(i)) >>> 0;
+//		}
+//		return hash;
+//	}
+//
+// Functions receive the arguments of the dsl call as javascript values
+// (strings, numbers, booleans or arrays) and may return any of them,
+// undefined and null are returned as an empty string. Thrown errors fail
+// the evaluation of the expression. Functions whose name starts with an
+// underscore are private helpers of the script and are not registered.
+//
+// The script runs in a sandbox with the ecmascript built-ins only (no
+// require, filesystem, network or nuclei javascript modules) and each call
+// is interrupted once it exceeds the configured timeout.
+
+// userFunctionNameRegex is the allowed name of user defined functions
+var userFunctionNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+const (
+	// DefaultUserFunctionTimeout is the timeout of user defined functions if none is set
+	DefaultUserFunctionTimeout = time.Second
+	// maxUserFunctionCallStack is the max call stack size of user defined functions
+	maxUserFunctionCallStack = 1024
+)
+
+var (
+	userFunctionsMutex sync.Mutex
+	userFunctions      = make(map[string]*userFunctionSet)
+)
+
+// userFunctionSet is the set of functions of a script with a pool of
+// runtimes since a runtime can not be used concurrently
+type userFunctionSet struct {
+	program *goja.Program
+	timeout time.Duration
+	pool    sync.Pool
+}
+
+// InitUserFunctions registers the user defined functions of the script file (if any)
+func InitUserFunctions(options *types.Options) error {
+	if options.DSLFunctions == "" {
+		return nil
+	}
+	_, err := LoadUserFunctions(options.DSLFunctions, options.DSLFunctionTimeout)
+	return err
+}
+
+// LoadUserFunctions registers the functions of the script file as dsl helper
+// functions returning their names. Functions previously registered by a script
+// are replaced while conflicts with built-in helper functions are an error.
+func LoadUserFunctions(path string, timeout time.Duration) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read dsl functions")
+	}
+	program, err := goja.Compile(path, string(data), true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not compile dsl functions %s", path)
+	}
+	if timeout <= 0 {
+		timeout = DefaultUserFunctionTimeout
+	}
+	set := &userFunctionSet{program: program, timeout: timeout}
+	runtime, err := set.newRuntime()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not run dsl functions %s", path)
+	}
+	set.pool.Put(runtime)
+
+	names := userFunctionNames(runtime)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no functions declared in dsl functions %s", path)
+	}
+
+	userFunctionsMutex.Lock()
+	defer userFunctionsMutex.Unlock()
+
+	for _, name := range names {
+		if _, ok := HelperFunctions[name]; ok && userFunctions[name] == nil {
+			return nil, fmt.Errorf("dsl function %s conflicts with a built-in helper function", name)
+		}
+	}
+	for _, name := range names {
+		if userFunctions[name] == nil {
+			name := name
+			function := dsl.NewWithSingleSignature(name, "(args ...interface{}) interface{}", false, func(args ...interface{}) (interface{}, error) {
+				return callUserFunction(name, args...)
+			})
+			_ = dsl.AddFunction(function)
+			HelperFunctions[name] = function.Exec
+			FunctionNames = append(FunctionNames, name)
+		}
+		userFunctions[name] = set
+	}
+	return names, nil
+}
+
+// callUserFunction calls the registered user defined function
+func callUserFunction(name string, args ...interface{}) (interface{}, error) {
+	userFunctionsMutex.Lock()
+	set := userFunctions[name]
+	userFunctionsMutex.Unlock()
+
+	runtime, err := set.get()
+	if err != nil {
+		return nil, err
+	}
+	function, ok := goja.AssertFunction(runtime.Get(name))
+	if !ok {
+		return nil, fmt.Errorf("dsl function %s is not a function", name)
+	}
+	values := make([]goja.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, runtime.ToValue(arg))
+	}
+
+	timer := time.AfterFunc(set.timeout, func() {
+		runtime.Interrupt(fmt.Sprintf("dsl function %s exceeded timeout of %s", name, set.timeout))
+	})
+	result, err := function(goja.Undefined(), values...)
+	timer.Stop()
+	// the interrupt may be pending if the timer fired as the call returned
+	runtime.ClearInterrupt()
+	set.pool.Put(runtime)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not execute dsl function %s", name)
+	}
+
+	if goja.IsUndefined(result) || goja.IsNull(result) {
+		return "", nil
+	}
+	return result.Export(), nil
+}
+
+// get returns a runtime of the set from the pool or a new one
+func (s *userFunctionSet) get() (*goja.Runtime, error) {
+	if runtime, ok := s.pool.Get().(*goja.Runtime); ok {
+		return runtime, nil
+	}
+	return s.newRuntime()
+}
+
+// newRuntime returns a sandboxed runtime with the functions of the script declared
+func (s *userFunctionSet) newRuntime() (*goja.Runtime, error) {
+	runtime := goja.New()
+	runtime.SetMaxCallStackSize(maxUserFunctionCallStack)
+
+	timer := time.AfterFunc(s.timeout, func() {
+		runtime.Interrupt(fmt.Sprintf("dsl functions exceeded timeout of %s", s.timeout))
+	})
+	_, err := runtime.RunProgram(s.program)
+	timer.Stop()
+	if err != nil {
+		return nil, err
+	}
+	runtime.ClearInterrupt()
+	return runtime, nil
+}
+
+// userFunctionNames returns the names of the public functions
+// declared at the top level of the script of the runtime
+func userFunctionNames(runtime *goja.Runtime) []string {
+	builtins := goja.New().GlobalObject()
+
+	var names []string
+	global := runtime.GlobalObject()
+	for _, name := range global.Keys() {
+		if !userFunctionNameRegex.MatchString(name) || builtins.Get(name) != nil {
+			continue
+		}
+		if _, ok := goja.AssertFunction(global.Get(name)); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+package dsl
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserFunctions(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "functions.js")
+	err := os.WriteFile(script, []byte(`
+function wrap_value(prefix, value) { return prefix + "-" + _upper(value); }
+function spin() { while (true) {} }
+function nothing() {}
+function _upper(value) { return value.toUpperCase(); }
+var counter = 1;
+`), 0600)
+	require.Nil(t, err, "could not write script")
+
+	names, err := LoadUserFunctions(script, 100*time.Millisecond)
+	require.Nil(t, err, "could not load user functions")
+	require.ElementsMatch(t, []string{"wrap_value", "spin", "nothing"}, names, "could not get user function names")
+	require.Contains(t, FunctionNames, "wrap_value", "could not add function name")
+
+	evaluate := func(expression string) (interface{}, error) {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, HelperFunctions)
+		require.Nil(t, err, "could not compile expression")
+		return compiled.Evaluate(nil)
+	}
+
+	result, err := evaluate(`wrap_value("id", to_lower("ABC"))`)
+	require.Nil(t, err, "could not evaluate user function")
+	require.Equal(t, "id-ABC", result, "could not get user function result")
+
+	result, err = evaluate(`nothing()`)
+	require.Nil(t, err, "could not evaluate user function")
+	require.Equal(t, "", result, "could not get empty result")
+
+	started := time.Now()
+	_, err = evaluate(`spin()`)
+	require.NotNil(t, err, "could not interrupt user function")
+	require.Less(t, time.Since(started), 5*time.Second, "could not cap execution time")
+
+	// reloading replaces the functions of the previous script
+	_, err = LoadUserFunctions(script, time.Second)
+	require.Nil(t, err, "could not reload user functions")
+
+	conflicting := filepath.Join(dir, "conflicting.js")
+	err = os.WriteFile(conflicting, []byte(`function md5(value) { return value; }`), 0600)
+	require.Nil(t, err, "could not write script")
+	_, err = LoadUserFunctions(conflicting, time.Second)
+	require.NotNil(t, err, "could not detect conflict with built-in function")
+}
+
+func TestUserFunctionsDocExample(t *testing.T) {
+	// the example of the package documentation is extracted from its
+	// indented lines so that it can not diverge from this test
+	file, err := parser.ParseFile(token.NewFileSet(), "userfuncs.go", nil, parser.ParseComments)
+	require.Nil(t, err, "could not parse userfuncs.go")
+	var example []string
+	for _, group := range file.Comments {
+		text := group.Text()
+		if !strings.HasPrefix(text, "User defined functions are") {
+			continue
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if strings.HasPrefix(line, "\t") {
+				example = append(example, strings.TrimPrefix(line, "\t"))
+			}
+		}
+	}
+	require.NotEmpty(t, example, "could not get documented example")
+
+	script := filepath.Join(t.TempDir(), "example.js")
+	err = os.WriteFile(script, []byte(strings.Join(example, "\n")), 0600)
+	require.Nil(t, err, "could not write script")
+	names, err := LoadUserFunctions(script, time.Second)
+	require.Nil(t, err, "could not load documented example")
+	require.Equal(t, []string{"sign"}, names, "could not get public functions of documented example")
+
+	var checksum uint32
+	for _, char := range []byte("secret:data") {
+		checksum = checksum*31 + uint32(char)
+	}
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(`sign("secret", "data")`, HelperFunctions)
+	require.Nil(t, err, "could not compile expression")
+	result, err := compiled.Evaluate(nil)
+	require.Nil(t, err, "could not run documented example")
+	require.Equal(t, fmt.Sprintf("%x", checksum), result, "could not get signature of documented example")
+}
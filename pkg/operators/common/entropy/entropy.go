@@ -0,0 +1,131 @@
+// Package entropy implements shannon entropy calculations used by entropy
+// matchers and extractors to find high entropy segments (ex: secrets, keys
+// or encrypted blobs) in responses.
+package entropy
+
+import (
+	"math"
+)
+
+const (
+	// DefaultThreshold is the default entropy threshold in bits per byte
+	DefaultThreshold = 4.0
+	// DefaultMinLength is the default minimum length of extracted segments
+	DefaultMinLength = 20
+)
+
+// Shannon returns the shannon entropy of data in bits per byte
+func Shannon(data string) float64 {
+	if data == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(data); i++ {
+		counts[data[i]]++
+	}
+	length := float64(len(data))
+	var entropy float64
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		probability := float64(count) / length
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
+
+// Max returns the highest entropy of windows of data along with the window.
+// The entropy of the whole data is returned if window is not positive or
+// larger than data.
+func Max(data string, window int) (float64, string) {
+	if window <= 0 || window >= len(data) {
+		return Shannon(data), data
+	}
+	var maxEntropy float64
+	var maxWindow string
+	for _, start := range windowStarts(len(data), window) {
+		segment := data[start : start+window]
+		if entropy := Shannon(segment); entropy > maxEntropy {
+			maxEntropy, maxWindow = entropy, segment
+		}
+	}
+	return maxEntropy, maxWindow
+}
+
+// Segments returns the token segments of data with at least minLength bytes
+// whose entropy is at least threshold, in the order they appear.
+//
+// Tokens are runs of alphanumeric and base64/url-safe characters. If window
+// is positive, tokens longer than window are evaluated by windows and the
+// spans of adjacent high entropy windows are returned instead.
+func Segments(data string, threshold float64, minLength, window int) []string {
+	var segments []string
+	for _, token := range tokens(data, minLength) {
+		if window <= 0 || len(token) <= window {
+			if Shannon(token) >= threshold {
+				segments = append(segments, token)
+			}
+			continue
+		}
+		start, end := -1, -1
+		for _, offset := range windowStarts(len(token), window) {
+			if Shannon(token[offset:offset+window]) < threshold {
+				continue
+			}
+			if start != -1 && offset <= end {
+				end = offset + window
+				continue
+			}
+			if start != -1 {
+				segments = append(segments, token[start:end])
+			}
+			start, end = offset, offset+window
+		}
+		if start != -1 {
+			segments = append(segments, token[start:end])
+		}
+	}
+	return segments
+}
+
+// windowStarts returns the offsets of windows over length bytes. Windows
+// overlap by three quarters and the last window ends at length.
+func windowStarts(length, window int) []int {
+	step := window / 4
+	if step < 1 {
+		step = 1
+	}
+	var starts []int
+	for start := 0; start+window <= length; start += step {
+		starts = append(starts, start)
+	}
+	if last := length - window; len(starts) > 0 && starts[len(starts)-1] != last {
+		starts = append(starts, last)
+	}
+	return starts
+}
+
+// tokens returns the runs of token characters of data with at least minLength bytes
+func tokens(data string, minLength int) []string {
+	var results []string
+	start := -1
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && isTokenChar(data[i]) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 && i-start >= minLength {
+			results = append(results, data[start:i])
+		}
+		start = -1
+	}
+	return results
+}
+
+func isTokenChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+		c == '+' || c == '/' || c == '=' || c == '_' || c == '-'
+}
@@ -0,0 +1,155 @@
+// Package errorpage implements detection of framework error pages and stack
+// traces (ex: django debug pages, spring whitelabel pages or php warnings)
+// used by error-page matchers and extractors.
+//
+// A built-in set of signatures is embedded, signatures of the same framework
+// can be replaced (or new frameworks added) with a signatures file.
+package errorpage
+
+import (
+	_ "embed"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+//go:embed signatures.yaml
+var defaultSignatures []byte
+
+// Signature is the list of regex identifying error pages of a framework
+type Signature struct {
+	Framework string   `yaml:"framework"`
+	Regex     []string `yaml:"regex"`
+
+	compiled []*regexp.Regexp
+}
+
+var (
+	signaturesMutex sync.RWMutex
+	signatures      []*Signature
+)
+
+func init() {
+	parsed, err := parseSignatures(defaultSignatures)
+	if err != nil {
+		panic(err)
+	}
+	signatures = parsed
+}
+
+// Init loads the signatures file (if any) over the built-in signatures
+func Init(options *types.Options) error {
+	if options.ErrorPageSignatures == "" {
+		return nil
+	}
+	return Load(options.ErrorPageSignatures)
+}
+
+// Load loads signatures from the file replacing the built-in signatures
+// of the same framework. Signatures without regex disable the framework.
+func Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read error page signatures")
+	}
+	custom, err := parseSignatures(data)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse error page signatures %s", path)
+	}
+	builtin, _ := parseSignatures(defaultSignatures)
+
+	merged := make([]*Signature, 0, len(builtin)+len(custom))
+	overridden := make(map[string]*Signature, len(custom))
+	for _, signature := range custom {
+		overridden[signature.Framework] = signature
+	}
+	for _, signature := range builtin {
+		if replacement, ok := overridden[signature.Framework]; ok {
+			signature = replacement
+			delete(overridden, signature.Framework)
+		}
+		merged = append(merged, signature)
+	}
+	for _, signature := range custom {
+		if _, ok := overridden[signature.Framework]; ok {
+			merged = append(merged, signature)
+		}
+	}
+
+	signaturesMutex.Lock()
+	signatures = merged
+	signaturesMutex.Unlock()
+	return nil
+}
+
+// parseSignatures parses and compiles a yaml list of signatures
+func parseSignatures(data []byte) ([]*Signature, error) {
+	var parsed []*Signature
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	for _, signature := range parsed {
+		signature.Framework = strings.ToLower(strings.TrimSpace(signature.Framework))
+		if signature.Framework == "" {
+			return nil, errors.New("framework is required for error page signatures")
+		}
+		for _, regex := range signature.Regex {
+			compiled, err := regexp.Compile(regex)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regex for %s", signature.Framework)
+			}
+			signature.compiled = append(signature.compiled, compiled)
+		}
+	}
+	return parsed, nil
+}
+
+// Frameworks returns the names of frameworks with signatures
+func Frameworks() []string {
+	signaturesMutex.RLock()
+	defer signaturesMutex.RUnlock()
+
+	names := make([]string, 0, len(signatures))
+	for _, signature := range signatures {
+		if len(signature.compiled) > 0 {
+			names = append(names, signature.Framework)
+		}
+	}
+	return names
+}
+
+// Detect returns the frameworks whose error page signatures are found in
+// corpus. If frameworks is not empty only the listed frameworks are detected.
+func Detect(corpus string, frameworks []string) []string {
+	signaturesMutex.RLock()
+	defer signaturesMutex.RUnlock()
+
+	var detected []string
+	for _, signature := range signatures {
+		if len(frameworks) > 0 && !containsFold(frameworks, signature.Framework) {
+			continue
+		}
+		for _, compiled := range signature.compiled {
+			if compiled.MatchString(corpus) {
+				detected = append(detected, signature.Framework)
+				break
+			}
+		}
+	}
+	return detected
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Package golden implements comparison of responses against reference
+// (golden) responses used by golden matchers for regression and contract
+// testing of apis.
+//
+// Volatile values of responses (ex: timestamps, ids) are ignored by masking
+// the matches of regexes in both the golden and the compared response.
+package golden
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// maskPlaceholder replaces the matches of masks
+	maskPlaceholder = "<masked>"
+	// maxDiffLines is the maximum number of lines of the reported diff
+	maxDiffLines = 100
+)
+
+// Golden is a compiled golden response
+type Golden struct {
+	expected string
+	masks    []*regexp.Regexp
+}
+
+// Compile compiles the golden response with the regexes masking volatile
+// values. A single trailing newline of the golden response is ignored since
+// it is usually added by editors.
+func Compile(expected string, masks []string) (*Golden, error) {
+	golden := &Golden{}
+	for _, mask := range masks {
+		compiled, err := regexp.Compile(mask)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile mask %s: %w", mask, err)
+		}
+		golden.masks = append(golden.masks, compiled)
+	}
+	expected = strings.TrimSuffix(strings.TrimSuffix(expected, "\n"), "\r")
+	golden.expected = golden.normalize(expected)
+	return golden, nil
+}
+
+// Compare compares data against the golden response returning the unified
+// diff between them if they are not equal
+func (g *Golden) Compare(data string) (equal bool, diff []string) {
+	actual := g.normalize(data)
+	if actual == g.expected {
+		return true, nil
+	}
+	unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(g.expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "golden",
+		ToFile:   "response",
+		Context:  1,
+	})
+	if err != nil || unified == "" {
+		return false, []string{"response does not match golden file"}
+	}
+	lines := strings.Split(strings.TrimSuffix(unified, "\n"), "\n")
+	if len(lines) > maxDiffLines {
+		lines = append(lines[:maxDiffLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxDiffLines))
+	}
+	return false, []string{strings.Join(lines, "\n")}
+}
+
+// normalize masks the volatile values of data
+func (g *Golden) normalize(data string) string {
+	for _, mask := range g.masks {
+		data = mask.ReplaceAllString(data, maskPlaceholder)
+	}
+	return data
+}
@@ -0,0 +1,88 @@
+// Package jsonschema implements validation of json documents against
+// inline json schemas used by json-schema matchers and extractors to
+// assert responses conform to an api contract.
+//
+// Schemas are specified inline in the template either as json or yaml.
+// References to definitions of the schema itself (ex: #/definitions/user)
+// are supported while references to external documents are not loaded.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// errExternalReference is returned when loading external schema references
+var errExternalReference = errors.New("external schema references are not supported")
+
+// Schema is a compiled json schema
+type Schema struct {
+	schema *gojsonschema.Schema
+}
+
+// Compile compiles an inline json schema written as json or yaml
+func Compile(source string) (*Schema, error) {
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(source), &document); err != nil {
+		return nil, err
+	}
+	if _, ok := document.(map[string]interface{}); !ok {
+		return nil, errors.New("json schema must be an object")
+	}
+	compiled, err := gojsonschema.NewSchemaLoader().Compile(localLoader{gojsonschema.NewGoLoader(document)})
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: compiled}, nil
+}
+
+// Validate validates the json document data against the schema returning
+// the validation errors if any. ok is false if data is not a json document.
+func (s *Schema) Validate(data string) (valid bool, validationErrors []string, ok bool) {
+	if !json.Valid([]byte(data)) {
+		return false, nil, false
+	}
+	result, err := s.schema.Validate(gojsonschema.NewStringLoader(data))
+	if err != nil {
+		return false, nil, false
+	}
+	for _, resultErr := range result.Errors() {
+		validationErrors = append(validationErrors, resultErr.String())
+	}
+	return result.Valid(), validationErrors, true
+}
+
+// localLoader is a schema loader refusing to load external references
+type localLoader struct {
+	gojsonschema.JSONLoader
+}
+
+// LoaderFactory returns a factory of loaders failing for every reference
+func (l localLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return localLoaderFactory{}
+}
+
+type localLoaderFactory struct{}
+
+// New returns a loader failing to load the external reference
+func (localLoaderFactory) New(source string) gojsonschema.JSONLoader {
+	return failingLoader{gojsonschema.NewReferenceLoader(source)}
+}
+
+// failingLoader is a reference loader whose documents can not be loaded
+type failingLoader struct {
+	gojsonschema.JSONLoader
+}
+
+// LoadJSON returns an error for the external reference
+func (l failingLoader) LoadJSON() (interface{}, error) {
+	return nil, errExternalReference
+}
+
+// LoaderFactory returns a factory of loaders failing for every reference
+func (l failingLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return localLoaderFactory{}
+}
@@ -0,0 +1,98 @@
+// Package noise implements normalization of response bodies removing
+// dynamic fragments (ex: csrf tokens, timestamps, nonces) which differ
+// between otherwise identical responses and defeat exact or similarity
+// based comparisons.
+//
+// Dynamic fragments are masked by regexes. If a regex has capture groups
+// only the first group is masked (ex: the value of a csrf token input),
+// otherwise the whole match is masked.
+package noise
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maskPlaceholder replaces the dynamic fragments
+const maskPlaceholder = "<masked>"
+
+// DefaultMasks are the regexes of common dynamic fragments
+var DefaultMasks = []string{
+	// csrf tokens and nonces of hidden inputs and meta tags
+	`(?i)name=["']?[\w.-]*(?:csrf|xsrf|token|nonce|authenticity)[\w.-]*["']?[^>]*?\s(?:value|content)=["']([^"']*)`,
+	// csrf tokens and nonces of json and javascript values
+	`(?i)["']?[\w.-]*(?:csrf|xsrf|nonce)[\w.-]*["']?\s*[:=]\s*["']([^"']*)`,
+	// nonces of script and style tags
+	`(?i)\snonce=["']([^"']+)`,
+	// uuids
+	`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`,
+	// iso 8601 timestamps
+	`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	// http dates
+	`\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} (?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) \d{4} \d{2}:\d{2}:\d{2} GMT`,
+	// unix timestamps in seconds and milliseconds
+	`\b1[5-9]\d{8}(?:\d{3})?\b`,
+	// hex digests and random identifiers
+	`\b[0-9a-fA-F]{32,}\b`,
+}
+
+// Normalizer masks the dynamic fragments of response bodies
+type Normalizer struct {
+	masks []*regexp.Regexp
+}
+
+// New compiles a normalizer from the regexes of dynamic fragments, along
+// with the default masks if defaults is true
+func New(masks []string, defaults bool) (*Normalizer, error) {
+	if defaults {
+		masks = append(append([]string{}, DefaultMasks...), masks...)
+	}
+	normalizer := &Normalizer{}
+	for _, mask := range masks {
+		compiled, err := regexp.Compile(mask)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile mask %s: %w", mask, err)
+		}
+		normalizer.masks = append(normalizer.masks, compiled)
+	}
+	return normalizer, nil
+}
+
+// Normalize returns the data with its dynamic fragments masked
+func (n *Normalizer) Normalize(data string) string {
+	if n == nil {
+		return data
+	}
+	for _, mask := range n.masks {
+		data = maskString(mask, data)
+	}
+	return data
+}
+
+// maskString replaces the matches of the mask in data, or the first
+// group of the matches if the mask has capture groups
+func maskString(mask *regexp.Regexp, data string) string {
+	if mask.NumSubexp() == 0 {
+		return mask.ReplaceAllString(data, maskPlaceholder)
+	}
+	matches := mask.FindAllStringSubmatchIndex(data, -1)
+	if len(matches) == 0 {
+		return data
+	}
+	builder := &strings.Builder{}
+	builder.Grow(len(data))
+	last := 0
+	for _, match := range matches {
+		start, end := match[2], match[3]
+		// the group did not participate in the match or is empty
+		if start < 0 || start == end {
+			continue
+		}
+		builder.WriteString(data[last:start])
+		builder.WriteString(maskPlaceholder)
+		last = end
+	}
+	builder.WriteString(data[last:])
+	return builder.String()
+}
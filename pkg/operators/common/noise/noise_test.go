@@ -0,0 +1,34 @@
+package noise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	normalizer, err := New(nil, true)
+	require.Nil(t, err, "could not compile default masks")
+
+	first := `<form><input type="hidden" name="csrf_token" value="a8f3k2"><input name="q" value="test"></form>
+<script nonce="r4nd0m">var config = {"xsrfToken": "zz91", "requestId": "6f1c2a7e-3b4d-4e5f-8a9b-0c1d2e3f4a5b"};</script>
+<p>Generated at 2024-05-01T10:22:33Z (1714558953)</p>`
+	second := `<form><input type="hidden" name="csrf_token" value="q81mzx"><input name="q" value="test"></form>
+<script nonce="0th3r">var config = {"xsrfToken": "k2p0", "requestId": "0a1b2c3d-4e5f-4a6b-9c8d-7e6f5a4b3c2d"};</script>
+<p>Generated at 2024-05-01T10:22:41Z (1714558961)</p>`
+
+	normalized := normalizer.Normalize(first)
+	require.Equal(t, normalized, normalizer.Normalize(second), "could not mask dynamic fragments")
+	require.Contains(t, normalized, `name="q" value="test"`, "masked static value")
+	require.Contains(t, normalized, `name="csrf_token" value="<masked>"`, "could not mask only token value")
+
+	normalizer, err = New([]string{`build-\d+`}, false)
+	require.Nil(t, err, "could not compile masks")
+	require.Equal(t, "version <masked> 2024-05-01T10:22:33Z", normalizer.Normalize("version build-42 2024-05-01T10:22:33Z"), "could not mask custom fragment only")
+
+	_, err = New([]string{"("}, false)
+	require.NotNil(t, err, "compiled invalid mask")
+
+	var nilNormalizer *Normalizer
+	require.Equal(t, "body", nilNormalizer.Normalize("body"), "nil normalizer modified data")
+}
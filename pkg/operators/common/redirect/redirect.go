@@ -0,0 +1,107 @@
+// Package redirect implements parsing of redirect targets (ex: Location
+// headers) the way browsers do, used by redirect matchers to confirm open
+// redirects to attacker controlled hosts.
+//
+// Parsing handles the usual tricks used to bypass redirect validation such as
+// scheme relative urls (//host), backslashes (/\host), credentials
+// (trusted.com@host) and whitespace or control characters.
+package redirect
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// specialSchemes are the schemes whose authority is parsed by browsers
+// regardless of the number of slashes (or backslashes) following the scheme
+var specialSchemes = []string{"http:", "https:", "ftp:", "ws:", "wss:"}
+
+// Host returns the normalized host of the redirect target as browsers would
+// navigate to it. An empty string is returned for relative targets (which
+// stay on the same host) and non navigable schemes (ex: javascript:).
+func Host(location string) string {
+	location = normalize(location)
+	lowered := strings.ToLower(location)
+
+	var authority string
+	switch {
+	case strings.HasPrefix(location, "//"):
+		authority = strings.TrimLeft(location, "/")
+	case hasSpecialScheme(lowered):
+		rest := location[strings.Index(location, ":")+1:]
+		if !strings.HasPrefix(rest, "/") {
+			// scheme without slashes (ex: https:host) is relative for same scheme bases
+			return ""
+		}
+		authority = strings.TrimLeft(rest, "/")
+	default:
+		return ""
+	}
+	if i := strings.IndexAny(authority, "/?#"); i != -1 {
+		authority = authority[:i]
+	}
+	if i := strings.LastIndex(authority, "@"); i != -1 {
+		authority = authority[i+1:]
+	}
+	return NormalizeHost(authority)
+}
+
+// Resolve returns the url of the redirect target resolved against the url of
+// the request for relative targets. The target is normalized as browsers do
+// before parsing (ex: backslashes are converted to slashes).
+func Resolve(location, base string) (*url.URL, error) {
+	location = normalize(location)
+	lowered := strings.ToLower(location)
+	if hasSpecialScheme(lowered) {
+		rest := location[strings.Index(location, ":")+1:]
+		if !strings.HasPrefix(rest, "/") {
+			// scheme without slashes (ex: https:path) is relative for same scheme bases
+			location = rest
+		} else {
+			// any number of slashes separates the scheme from the authority
+			location = location[:strings.Index(location, ":")+1] + "//" + strings.TrimLeft(rest, "/")
+		}
+	}
+	target, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return target, nil
+	}
+	return baseURL.ResolveReference(target), nil
+}
+
+// normalize removes whitespace and control characters ignored by browsers
+// and converts backslashes to slashes
+func normalize(location string) string {
+	location = strings.TrimFunc(location, func(r rune) bool {
+		return r <= ' '
+	})
+	location = strings.NewReplacer("\t", "", "\r", "", "\n", "").Replace(location)
+	return strings.ReplaceAll(location, `\`, "/")
+}
+
+func hasSpecialScheme(location string) bool {
+	for _, scheme := range specialSchemes {
+		if strings.HasPrefix(location, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeHost returns the lowercased host without port, trailing dot
+// and percent encoding
+func NormalizeHost(host string) string {
+	if unescaped, err := url.PathUnescape(host); err == nil {
+		host = unescaped
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
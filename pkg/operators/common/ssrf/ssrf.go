@@ -0,0 +1,283 @@
+// Package ssrf implements confirmation of server side request forgery by
+// comparing the response of a request fetching an internal target (ex: the
+// cloud metadata service) with the response of the same request fetching an
+// external control host, used by ssrf matchers.
+//
+// SSRF is confirmed if the response of the internal target contains content
+// distinctive of the target (ex: metadata json) absent from the control
+// response. For targets without distinctive content (ex: private networks)
+// the response must succeed and be dissimilar to the control response once
+// the internal and control hosts and dynamic fragments (ex: timestamps,
+// nonces) are masked.
+//
+// A built-in set of targets is embedded, targets of the same name can be
+// replaced (or new targets added) with a targets file.
+package ssrf
+
+import (
+	_ "embed"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/noise"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+//go:embed targets.yaml
+var defaultTargets []byte
+
+// DefaultControlHost is the external host fetched by control requests
+const DefaultControlHost = "example.com"
+
+// maxControlSimilarity is the token similarity of normalized bodies above
+// which responses of targets without regex are not distinguishable from
+// the control response
+const maxControlSimilarity = 0.8
+
+// Target is an internal target fetched through ssrf
+type Target struct {
+	Name  string   `yaml:"target"`
+	Hosts []string `yaml:"hosts"`
+	CIDRs []string `yaml:"cidrs"`
+	Regex []string `yaml:"regex"`
+
+	networks []*net.IPNet
+	compiled []*regexp.Regexp
+}
+
+var (
+	targetsMutex sync.RWMutex
+	targets      []*Target
+
+	// ipRegex matches ipv4 addresses and bracketed ipv6 addresses
+	ipRegex = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|\[[0-9a-fA-F:.]+\]`)
+	// fetchErrorRegex matches errors of failed fetches embedded in responses
+	fetchErrorRegex = regexp.MustCompile(`(?i)connection refused|timed? ?out|could not (connect|resolve)|failed to (connect|fetch|open)|no route to host|name or service not known|invalid url|not allowed`)
+	// normalizer masks dynamic fragments of compared bodies
+	normalizer *noise.Normalizer
+)
+
+func init() {
+	parsed, err := parseTargets(defaultTargets)
+	if err != nil {
+		panic(err)
+	}
+	targets = parsed
+	normalizer, err = noise.New(nil, true)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Init loads the targets file (if any) over the built-in targets
+func Init(options *types.Options) error {
+	if options.SSRFTargets == "" {
+		return nil
+	}
+	return Load(options.SSRFTargets)
+}
+
+// Load loads targets from the file replacing the built-in targets
+// of the same name. Targets without hosts and cidrs are disabled.
+func Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read ssrf targets")
+	}
+	custom, err := parseTargets(data)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse ssrf targets %s", path)
+	}
+	builtin, _ := parseTargets(defaultTargets)
+
+	overridden := make(map[string]*Target, len(custom))
+	for _, target := range custom {
+		overridden[target.Name] = target
+	}
+	merged := make([]*Target, 0, len(builtin)+len(custom))
+	for _, target := range builtin {
+		if replacement, ok := overridden[target.Name]; ok {
+			target = replacement
+			delete(overridden, target.Name)
+		}
+		merged = append(merged, target)
+	}
+	for _, target := range custom {
+		if _, ok := overridden[target.Name]; ok {
+			merged = append(merged, target)
+		}
+	}
+
+	targetsMutex.Lock()
+	targets = merged
+	targetsMutex.Unlock()
+	return nil
+}
+
+// parseTargets parses and compiles a yaml list of targets
+func parseTargets(data []byte) ([]*Target, error) {
+	var parsed []*Target
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	for _, target := range parsed {
+		target.Name = strings.ToLower(strings.TrimSpace(target.Name))
+		if target.Name == "" {
+			return nil, errors.New("name is required for ssrf targets")
+		}
+		for i, host := range target.Hosts {
+			target.Hosts[i] = strings.ToLower(strings.TrimSpace(host))
+		}
+		for _, cidr := range target.CIDRs {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid cidr for %s", target.Name)
+			}
+			target.networks = append(target.networks, network)
+		}
+		for _, regex := range target.Regex {
+			compiled, err := regexp.Compile(regex)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regex for %s", target.Name)
+			}
+			target.compiled = append(target.compiled, compiled)
+		}
+	}
+	return parsed, nil
+}
+
+// Detect returns the first internal target fetched by the request along with
+// the host of the target found in the request. The request must not contain
+// the host of the scanned url itself (ex: path, query, body and headers).
+func Detect(request string) (*Target, string) {
+	targetsMutex.RLock()
+	defer targetsMutex.RUnlock()
+
+	lowered := strings.ToLower(request)
+	ips := ipRegex.FindAllString(request, -1)
+	for _, target := range targets {
+		for _, host := range target.Hosts {
+			if host != "" && strings.Contains(lowered, host) {
+				return target, host
+			}
+		}
+		for _, ip := range ips {
+			parsed := net.ParseIP(strings.Trim(ip, "[]"))
+			if parsed == nil {
+				continue
+			}
+			for _, network := range target.networks {
+				if network.Contains(parsed) {
+					return target, ip
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Lookup returns the target with the name
+func Lookup(name string) *Target {
+	targetsMutex.RLock()
+	defer targetsMutex.RUnlock()
+
+	for _, target := range targets {
+		if target.Name == name {
+			return target
+		}
+	}
+	return nil
+}
+
+// Response is a response compared by ssrf matchers
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Confirm compares the response of the internal target fetched from host with
+// the control response returning true along with the reason if ssrf is confirmed.
+func (t *Target) Confirm(host string, internal, control Response, controlHost string) (bool, string) {
+	if len(t.compiled) > 0 {
+		for _, compiled := range t.compiled {
+			if match := compiled.FindString(internal.Body); match != "" && !compiled.MatchString(control.Body) {
+				return true, match
+			}
+		}
+		return false, ""
+	}
+	if internal.StatusCode < 200 || internal.StatusCode >= 300 || strings.TrimSpace(internal.Body) == "" {
+		return false, ""
+	}
+	if fetchErrorRegex.MatchString(internal.Body) {
+		return false, ""
+	}
+	similarity := jaccardSimilarity(tokenSet(normalize(internal.Body, host, controlHost)), tokenSet(normalize(control.Body, host, controlHost)))
+	if similarity >= maxControlSimilarity {
+		return false, ""
+	}
+	return true, fmt.Sprintf("response differs from control (similarity %.2f)", similarity)
+}
+
+// normalize masks the hosts and the dynamic fragments of the body
+func normalize(body string, hosts ...string) string {
+	for _, host := range hosts {
+		body = ReplaceFold(body, host, "<host>")
+	}
+	return normalizer.Normalize(body)
+}
+
+// ReplaceFold replaces the case insensitive occurrences of old in value
+// with replacement
+func ReplaceFold(value, old, replacement string) string {
+	if old == "" || len(old) > len(value) {
+		return value
+	}
+	var builder strings.Builder
+	var last int
+	for i := 0; i+len(old) <= len(value); {
+		if strings.EqualFold(value[i:i+len(old)], old) {
+			builder.WriteString(value[last:i])
+			builder.WriteString(replacement)
+			i += len(old)
+			last = i
+			continue
+		}
+		i++
+	}
+	if last == 0 {
+		return value
+	}
+	builder.WriteString(value[last:])
+	return builder.String()
+}
+
+// tokenSet returns the set of whitespace separated tokens of the data
+func tokenSet(data string) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	for _, token := range strings.Fields(data) {
+		tokens[token] = struct{}{}
+	}
+	return tokens
+}
+
+// jaccardSimilarity returns the jaccard similarity of two token sets
+func jaccardSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var common int
+	for token := range a {
+		if _, ok := b[token]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
@@ -0,0 +1,99 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// BinaryFormatHex extracts the field as hex encoded bytes
+	BinaryFormatHex = "hex"
+	// BinaryFormatASCII extracts the field as text up to the first null byte
+	BinaryFormatASCII = "ascii"
+)
+
+// binaryFormatSizes contains the size in bytes of supported binary formats.
+// Formats of variable size have a size of 0.
+var binaryFormatSizes = map[string]int{
+	BinaryFormatHex:   0,
+	BinaryFormatASCII: 0,
+	"uint8":           1,
+	"int8":            1,
+	"uint16-be":       2,
+	"uint16-le":       2,
+	"int16-be":        2,
+	"int16-le":        2,
+	"uint32-be":       4,
+	"uint32-le":       4,
+	"int32-be":        4,
+	"int32-le":        4,
+	"uint64-be":       8,
+	"uint64-le":       8,
+	"int64-be":        8,
+	"int64-le":        8,
+}
+
+// decodeBinaryField decodes the field at offset of data according to format.
+// It returns false if the field is out of range of data.
+func decodeBinaryField(data []byte, offset, length int, format string) (string, bool) {
+	if offset < 0 {
+		offset += len(data)
+	}
+	if offset < 0 || offset >= len(data) {
+		return "", false
+	}
+	size, ok := binaryFormatSizes[format]
+	if !ok {
+		return "", false
+	}
+	if size == 0 {
+		size = length
+		if size == 0 {
+			size = len(data) - offset
+		}
+	}
+	if offset+size > len(data) {
+		return "", false
+	}
+	field := data[offset : offset+size]
+
+	switch format {
+	case BinaryFormatHex:
+		return hex.EncodeToString(field), true
+	case BinaryFormatASCII:
+		if index := bytes.IndexByte(field, 0); index != -1 {
+			field = field[:index]
+		}
+		return string(field), true
+	case "uint8":
+		return strconv.FormatUint(uint64(field[0]), 10), true
+	case "int8":
+		return strconv.FormatInt(int64(int8(field[0])), 10), true
+	}
+
+	var order binary.ByteOrder = binary.BigEndian
+	if format[len(format)-3:] == "-le" {
+		order = binary.LittleEndian
+	}
+	var value uint64
+	switch size {
+	case 2:
+		value = uint64(order.Uint16(field))
+	case 4:
+		value = uint64(order.Uint32(field))
+	case 8:
+		value = order.Uint64(field)
+	}
+	if format[0] == 'u' {
+		return strconv.FormatUint(value, 10), true
+	}
+	switch size {
+	case 2:
+		return strconv.FormatInt(int64(int16(value)), 10), true
+	case 4:
+		return strconv.FormatInt(int64(int32(value)), 10), true
+	}
+	return strconv.FormatInt(int64(value), 10), true
+}
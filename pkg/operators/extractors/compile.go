@@ -8,6 +8,8 @@ import (
 	"github.com/Knetic/govaluate"
 	"github.com/itchyny/gojq"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/entropy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/jsonschema"
 )
 
 // CompileExtractors performs the initial setup operation on an extractor
@@ -42,6 +44,14 @@ func (e *Extractor) CompileExtractors() error {
 		e.jsonCompiled = append(e.jsonCompiled, compiled)
 	}
 
+	for _, selector := range e.CSS {
+		compiled, err := compileCSSSelector(selector, e.Attribute)
+		if err != nil {
+			return err
+		}
+		e.cssCompiled = append(e.cssCompiled, compiled)
+	}
+
 	for _, dslExp := range e.DSL {
 		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(dslExp, dsl.HelperFunctions)
 		if err != nil {
@@ -50,6 +60,64 @@ func (e *Extractor) CompileExtractors() error {
 		e.dslCompiled = append(e.dslCompiled, compiled)
 	}
 
+	if e.GetType() == EntropyExtractor {
+		if e.EntropyThreshold <= 0 {
+			e.EntropyThreshold = entropy.DefaultThreshold
+		}
+		if e.EntropyMinLength <= 0 {
+			e.EntropyMinLength = entropy.DefaultMinLength
+		}
+		if e.EntropyWindow < 0 {
+			return fmt.Errorf("entropy-window must not be negative")
+		}
+	}
+
+	if e.GetType() == BinaryExtractor {
+		if e.BinaryFormat == "" {
+			e.BinaryFormat = BinaryFormatHex
+		}
+		if _, ok := binaryFormatSizes[e.BinaryFormat]; !ok {
+			return fmt.Errorf("unknown binary-format specified: %s", e.BinaryFormat)
+		}
+		if e.BinaryLength < 0 {
+			return fmt.Errorf("binary-length must not be negative")
+		}
+	}
+
+	if e.GetType() == JSONSchemaExtractor {
+		if e.JSONSchema == "" {
+			return fmt.Errorf("json-schema must be specified for json-schema extractors")
+		}
+		compiled, err := jsonschema.Compile(e.JSONSchema)
+		if err != nil {
+			return fmt.Errorf("could not compile json schema: %w", err)
+		}
+		e.schema = compiled
+	}
+
+	if e.GetType() == CSSExtractor && len(e.CSS) == 0 {
+		return fmt.Errorf("css selectors must be specified for css extractors")
+	}
+
+	// cookies are only set by headers, so default to them
+	if e.GetType() == CookieExtractor && e.Part == "" {
+		e.Part = "header"
+	}
+
+	switch e.Sort {
+	case "", SortLexical, SortAppearance:
+	default:
+		return fmt.Errorf("unknown sort order specified: %s", e.Sort)
+	}
+	if e.DisableDedupe && e.GetType() != RegexExtractor {
+		return fmt.Errorf("disable-dedupe flag is supported only for 'regex' extractors (not '%s')", e.Type)
+	}
+	if e.OutputFile != "" {
+		if err := e.validateOutputFile(); err != nil {
+			return err
+		}
+	}
+
 	if e.CaseInsensitive {
 		if e.GetType() != KValExtractor {
 			return fmt.Errorf("case-insensitive flag is supported only for 'kval' extractors (not '%s')", e.Type)
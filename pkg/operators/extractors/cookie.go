@@ -0,0 +1,94 @@
+package extractors
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultCookiePrefix is the variable prefix used by unnamed cookie extractors
+const defaultCookiePrefix = "cookie"
+
+// ExtractCookie extracts values of cookies set by Set-Cookie headers in corpus.
+// Cookies are filtered by the configured names (case-insensitive) if any.
+func (e *Extractor) ExtractCookie(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	for _, cookie := range e.matchingCookies(corpus) {
+		results[cookie.Value] = struct{}{}
+	}
+	return results
+}
+
+// CookieVariables returns variables for each cookie set in corpus named
+// <name>_<cookie> along with its attributes as <name>_<cookie>_<attribute>
+// (httponly, secure, samesite, path, domain and expires).
+func (e *Extractor) CookieVariables(corpus string) map[string]interface{} {
+	prefix := e.Name
+	if prefix == "" {
+		prefix = defaultCookiePrefix
+	}
+	variables := make(map[string]interface{})
+	for _, cookie := range e.matchingCookies(corpus) {
+		name := prefix + "_" + normalizeCookieName(cookie.Name)
+		variables[name] = cookie.Value
+		variables[name+"_httponly"] = strconv.FormatBool(cookie.HttpOnly)
+		variables[name+"_secure"] = strconv.FormatBool(cookie.Secure)
+		variables[name+"_samesite"] = sameSiteString(cookie.SameSite)
+		variables[name+"_path"] = cookie.Path
+		variables[name+"_domain"] = cookie.Domain
+		variables[name+"_expires"] = cookie.RawExpires
+	}
+	return variables
+}
+
+// matchingCookies parses all Set-Cookie headers of corpus returning
+// cookies matching the configured names
+func (e *Extractor) matchingCookies(corpus string) []*http.Cookie {
+	header := make(http.Header)
+	scanner := bufio.NewScanner(strings.NewReader(corpus))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "set-cookie") {
+			header.Add("Set-Cookie", strings.TrimSpace(value))
+		}
+	}
+	cookies := (&http.Response{Header: header}).Cookies()
+	if len(e.Cookie) == 0 {
+		return cookies
+	}
+	var matched []*http.Cookie
+	for _, cookie := range cookies {
+		for _, name := range e.Cookie {
+			if strings.EqualFold(cookie.Name, name) {
+				matched = append(matched, cookie)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// normalizeCookieName returns a cookie name usable as a variable name
+func normalizeCookieName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+}
+
+func sameSiteString(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,123 @@
+package extractors
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+var (
+	// cssPseudoElementRegex matches the trailing pseudo element of css
+	// selectors choosing the extracted value (::text, ::html or ::attr(name))
+	cssPseudoElementRegex = regexp.MustCompile(`::(text|html|attr\(\s*([^)\s]+)\s*\))\s*$`)
+	// cssIndexRegex matches the trailing :eq(n) index of css selectors
+	cssIndexRegex = regexp.MustCompile(`:eq\(\s*(-?\d+)\s*\)\s*$`)
+)
+
+// cssSelector is a compiled css selector of css extractors
+type cssSelector struct {
+	selector cascadia.Sel
+	// index is the index of the matched element to extract (if indexed)
+	index   int
+	indexed bool
+	// value is the extracted value of matched elements: text, html or attr
+	value     string
+	attribute string
+}
+
+// compileCSSSelector compiles a css selector with an optional :eq(n) index
+// of the matched element (negative from the last match) and an optional
+// ::text, ::html or ::attr(name) pseudo element of the extracted value.
+func compileCSSSelector(value, attribute string) (*cssSelector, error) {
+	compiled := &cssSelector{value: "text", attribute: attribute}
+	if attribute != "" {
+		compiled.value = "attr"
+	}
+	selector := strings.TrimSpace(value)
+	if match := cssPseudoElementRegex.FindStringSubmatch(selector); match != nil {
+		compiled.value, compiled.attribute = match[1], match[2]
+		if compiled.attribute != "" {
+			compiled.value = "attr"
+		}
+		selector = strings.TrimSpace(selector[:len(selector)-len(match[0])])
+	}
+	if match := cssIndexRegex.FindStringSubmatch(selector); match != nil {
+		compiled.index, _ = strconv.Atoi(match[1])
+		compiled.indexed = true
+		selector = strings.TrimSpace(selector[:len(selector)-len(match[0])])
+	}
+	parsed, err := cascadia.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile css selector %s: %w", value, err)
+	}
+	compiled.selector = parsed
+	return compiled, nil
+}
+
+// ExtractCSS extracts the text, html or attributes of html elements
+// matched by css selectors. Malformed html is parsed leniently.
+func (e *Extractor) ExtractCSS(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+
+	doc, err := html.Parse(strings.NewReader(corpus))
+	if err != nil {
+		return results
+	}
+	for _, selector := range e.cssCompiled {
+		nodes := cascadia.QueryAll(doc, selector.selector)
+		if selector.indexed {
+			index := selector.index
+			if index < 0 {
+				index += len(nodes)
+			}
+			if index < 0 || index >= len(nodes) {
+				continue
+			}
+			nodes = nodes[index : index+1]
+		}
+		for _, node := range nodes {
+			value, ok := selector.extract(node)
+			if !ok {
+				continue
+			}
+			results[value] = struct{}{}
+		}
+	}
+	return results
+}
+
+// extract returns the value of the node selected by the pseudo element
+func (s *cssSelector) extract(node *html.Node) (string, bool) {
+	switch s.value {
+	case "attr":
+		for _, attr := range node.Attr {
+			if strings.EqualFold(attr.Key, s.attribute) {
+				return attr.Val, true
+			}
+		}
+		return "", false
+	case "html":
+		builder := &strings.Builder{}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			_ = html.Render(builder, child)
+		}
+		return builder.String(), true
+	}
+	return strings.TrimSpace(nodeText(node)), true
+}
+
+// nodeText returns the text content of the node and its children
+func nodeText(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	builder := &strings.Builder{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.WriteString(nodeText(child))
+	}
+	return builder.String()
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/antchfx/htmlquery"
 	"github.com/antchfx/xmlquery"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/entropy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/errorpage"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
@@ -184,3 +186,42 @@ func (e *Extractor) ExtractDSL(data map[string]interface{}) map[string]struct{}
 	}
 	return results
 }
+
+// ExtractBinary extracts the field at the configured offset of corpus
+// interpreted according to the binary format
+func (e *Extractor) ExtractBinary(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	if value, ok := decodeBinaryField([]byte(corpus), e.BinaryOffset, e.BinaryLength, e.BinaryFormat); ok {
+		results[value] = struct{}{}
+	}
+	return results
+}
+
+// ExtractJSONSchema extracts the errors of validating the json document
+// corpus against the schema. Nothing is extracted for corpus which is not json.
+func (e *Extractor) ExtractJSONSchema(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	_, validationErrors, _ := e.schema.Validate(corpus)
+	for _, validationError := range validationErrors {
+		results[validationError] = struct{}{}
+	}
+	return results
+}
+
+// ExtractErrorPage extracts the frameworks whose error page signatures are found in corpus
+func (e *Extractor) ExtractErrorPage(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	for _, framework := range errorpage.Detect(corpus, e.Frameworks) {
+		results[framework] = struct{}{}
+	}
+	return results
+}
+
+// ExtractEntropy extracts segments of corpus with entropy above the threshold
+func (e *Extractor) ExtractEntropy(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+	for _, segment := range entropy.Segments(corpus, e.EntropyThreshold, e.EntropyMinLength, e.EntropyWindow) {
+		results[segment] = struct{}{}
+	}
+	return results
+}
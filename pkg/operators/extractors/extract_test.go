@@ -1,6 +1,8 @@
 package extractors
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -88,3 +90,177 @@ func TestExtractor_ExtractDSL(t *testing.T) {
 	got = e.ExtractDSL(map[string]interface{}{"hi": "hello"})
 	require.Equal(t, map[string]struct{}{}, got)
 }
+
+func TestExtractor_OrderResults(t *testing.T) {
+	corpus := "id=zeta id=alpha id=zeta id=beta"
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: RegexExtractor}, Regex: []string{`id=(\w+)`}, RegexGroup: 1}
+	err := e.CompileExtractors()
+	require.Nil(t, err)
+	require.False(t, e.RequiresCorpus(), "could not keep lexical order as default")
+	require.Equal(t, []string{"alpha", "beta", "zeta"}, e.OrderResults(e.ExtractRegex(corpus), corpus))
+
+	e.Sort = SortAppearance
+	require.Equal(t, []string{"zeta", "alpha", "beta"}, e.OrderResults(e.ExtractRegex(corpus), corpus))
+
+	e.DisableDedupe = true
+	require.Equal(t, []string{"zeta", "alpha", "zeta", "beta"}, e.OrderResults(e.ExtractRegex(corpus), corpus))
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: KValExtractor}, KVal: []string{"b", "a"}, Sort: SortAppearance}
+	err = e.CompileExtractors()
+	require.Nil(t, err)
+	require.Equal(t, []string{"second", "first"}, e.OrderResults(map[string]struct{}{"first": {}, "second": {}}, "second first"))
+
+	e.DisableDedupe = true
+	require.NotNil(t, e.CompileExtractors(), "could not get error for disable-dedupe on kval extractor")
+}
+
+func TestExtractorOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	outputDirectory = dir
+	defer func() { outputDirectory = "" }()
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: RegexExtractor}, Regex: []string{"[0-9]+"}, OutputFile: "out/values.txt", OutputDedupe: true}
+	require.Nil(t, e.CompileExtractors())
+
+	e.WriteOutput([]string{"1", "2"})
+	e.WriteOutput([]string{"2", "3"})
+	CloseOutputFiles()
+
+	data, err := os.ReadFile(filepath.Join(dir, "out", "values.txt"))
+	require.Nil(t, err)
+	require.Equal(t, "1\n2\n3\n", string(data))
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: RegexExtractor}, Regex: []string{"[0-9]+"}, OutputFile: "../values.txt"}
+	require.NotNil(t, e.CompileExtractors(), "could compile extractor writing outside current directory")
+}
+
+func TestExtractCookie(t *testing.T) {
+	headers := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nSet-Cookie: PHPSESSID=abc123; Path=/; HttpOnly; Secure; SameSite=Strict\r\nset-cookie: theme=dark; Domain=example.com\r\n\r\n"
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: CookieExtractor}, Name: "session", Cookie: []string{"phpsessid"}}
+	require.Nil(t, e.CompileExtractors())
+	require.Equal(t, "header", e.Part)
+	require.Equal(t, map[string]struct{}{"abc123": {}}, e.ExtractCookie(headers))
+
+	variables := e.CookieVariables(headers)
+	require.Equal(t, "abc123", variables["session_phpsessid"])
+	require.Equal(t, "true", variables["session_phpsessid_httponly"])
+	require.Equal(t, "true", variables["session_phpsessid_secure"])
+	require.Equal(t, "Strict", variables["session_phpsessid_samesite"])
+	require.Equal(t, "/", variables["session_phpsessid_path"])
+	require.NotContains(t, variables, "session_theme")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CookieExtractor}}
+	require.Nil(t, e.CompileExtractors())
+	require.Equal(t, map[string]struct{}{"abc123": {}, "dark": {}}, e.ExtractCookie(headers))
+	variables = e.CookieVariables(headers)
+	require.Equal(t, "dark", variables["cookie_theme"])
+	require.Equal(t, "example.com", variables["cookie_theme_domain"])
+	require.Equal(t, "false", variables["cookie_theme_httponly"])
+}
+
+func TestExtractEntropy(t *testing.T) {
+	corpus := `{"name":"aaaaaaaaaaaaaaaaaaaaaaaaa","key":"AKIAZx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6Hf0Jg5Ce","id":"short"}`
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: EntropyExtractor}}
+	require.Nil(t, e.CompileExtractors())
+	require.Equal(t, map[string]struct{}{"AKIAZx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6Hf0Jg5Ce": {}}, e.ExtractEntropy(corpus))
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: EntropyExtractor}, EntropyThreshold: 3.5, EntropyMinLength: 10, EntropyWindow: 16}
+	require.Nil(t, e.CompileExtractors())
+	segments := e.ExtractEntropy("prefix_aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaZx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6")
+	require.Len(t, segments, 1, "could not extract high entropy window")
+	for segment := range segments {
+		require.Contains(t, segment, "Zx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6", "could not extract high entropy span")
+		require.NotContains(t, segment, "aaaaaaaaaa", "extracted low entropy span")
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	corpus := string([]byte{0x01, 0x00, 0x02, 0xff, 0xfe, 0x00, 0x00, 0x01, 'n', 'u', 'c', 'l', 'e', 'i', 0x00, 0xaa})
+
+	tests := []struct {
+		offset, length int
+		format         string
+		expected       string
+	}{
+		{offset: 0, format: "uint8", expected: "1"},
+		{offset: 1, format: "uint16-be", expected: "2"},
+		{offset: 1, format: "uint16-le", expected: "512"},
+		{offset: 3, format: "int16-be", expected: "-2"},
+		{offset: 4, format: "uint32-le", expected: "16777470"},
+		{offset: 8, format: "ascii", expected: "nuclei"},
+		{offset: 8, length: 3, format: "ascii", expected: "nuc"},
+		{offset: -2, format: "hex", expected: "00aa"},
+		{offset: 0, length: 2, expected: "0100"},
+	}
+	for _, test := range tests {
+		e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: BinaryExtractor}, BinaryOffset: test.offset, BinaryLength: test.length, BinaryFormat: test.format}
+		require.Nil(t, e.CompileExtractors(), "could not compile binary extractor")
+		require.Equal(t, map[string]struct{}{test.expected: {}}, e.ExtractBinary(corpus), "could not extract %s at offset %d", test.format, test.offset)
+	}
+
+	for _, test := range []struct {
+		offset int
+		format string
+	}{{offset: 15, format: "uint16-be"}, {offset: 16, format: "uint8"}, {offset: -17, format: "hex"}} {
+		e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: BinaryExtractor}, BinaryOffset: test.offset, BinaryFormat: test.format}
+		require.Nil(t, e.CompileExtractors(), "could not compile binary extractor")
+		require.Empty(t, e.ExtractBinary(corpus), "extracted out of range field")
+	}
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: BinaryExtractor}, BinaryFormat: "uint24-be"}
+	require.NotNil(t, e.CompileExtractors(), "compiled unknown binary format")
+}
+
+func TestExtractJSONSchema(t *testing.T) {
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: JSONSchemaExtractor}, JSONSchema: "type: object\nrequired: [id]\nproperties:\n  id:\n    type: integer"}
+	require.Nil(t, e.CompileExtractors(), "could not compile json-schema extractor")
+
+	got := e.ExtractJSONSchema(`{"id": "1"}`)
+	require.Len(t, got, 1, "could not extract validation errors")
+	for validationError := range got {
+		require.Contains(t, validationError, "id", "could not extract field of validation error")
+	}
+	require.Empty(t, e.ExtractJSONSchema(`{"id": 1}`), "extracted errors of conforming document")
+	require.Empty(t, e.ExtractJSONSchema(`not json`), "extracted errors of non json corpus")
+}
+
+func TestExtractCSS(t *testing.T) {
+	body := `<html><body>
+<form action="/login">
+  <input type="hidden" name="csrf_token" value="abc123">
+  <input type="text" name="user">
+</form>
+<ul class="items"><li>first</li><li>second <b>item</b></li><li>third
+</ul>`
+
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"input[name=csrf_token]::attr(value)"}}
+	err := e.CompileExtractors()
+	require.Nil(t, err, "could not compile css extractor")
+	require.Equal(t, map[string]struct{}{"abc123": {}}, e.ExtractCSS(body), "could not extract attribute")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"ul.items li:eq(1)"}}
+	err = e.CompileExtractors()
+	require.Nil(t, err, "could not compile css extractor")
+	require.Equal(t, map[string]struct{}{"second item": {}}, e.ExtractCSS(body), "could not extract indexed text")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"ul.items li:eq(-1)::text", "ul.items li:eq(5)"}}
+	err = e.CompileExtractors()
+	require.Nil(t, err, "could not compile css extractor")
+	require.Equal(t, map[string]struct{}{"third": {}}, e.ExtractCSS(body), "could not extract text of malformed html")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"input"}, Attribute: "name"}
+	err = e.CompileExtractors()
+	require.Nil(t, err, "could not compile css extractor")
+	require.Equal(t, map[string]struct{}{"csrf_token": {}, "user": {}}, e.ExtractCSS(body), "could not extract attribute of all matches")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"li:eq(1)::html"}}
+	err = e.CompileExtractors()
+	require.Nil(t, err, "could not compile css extractor")
+	require.Equal(t, map[string]struct{}{"second <b>item</b>": {}}, e.ExtractCSS(body), "could not extract inner html")
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: CSSExtractor}, CSS: []string{"input[name="}}
+	require.NotNil(t, e.CompileExtractors(), "could compile invalid css selector")
+}
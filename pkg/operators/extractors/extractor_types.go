@@ -23,16 +23,34 @@ const (
 	JSONExtractor
 	// name:dsl
 	DSLExtractor
+	// name:cookie
+	CookieExtractor
+	// name:entropy
+	EntropyExtractor
+	// name:binary
+	BinaryExtractor
+	// name:json-schema
+	JSONSchemaExtractor
+	// name:error-page
+	ErrorPageExtractor
+	// name:css
+	CSSExtractor
 	limit
 )
 
 // extractorMappings is a table for conversion of extractor type from string.
 var extractorMappings = map[ExtractorType]string{
-	RegexExtractor: "regex",
-	KValExtractor:  "kval",
-	XPathExtractor: "xpath",
-	JSONExtractor:  "json",
-	DSLExtractor:   "dsl",
+	RegexExtractor:      "regex",
+	KValExtractor:       "kval",
+	XPathExtractor:      "xpath",
+	JSONExtractor:       "json",
+	DSLExtractor:        "dsl",
+	CookieExtractor:     "cookie",
+	EntropyExtractor:    "entropy",
+	BinaryExtractor:     "binary",
+	JSONSchemaExtractor: "json-schema",
+	ErrorPageExtractor:  "error-page",
+	CSSExtractor:        "css",
 }
 
 // GetType returns the type of the matcher
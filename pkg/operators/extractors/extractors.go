@@ -5,6 +5,8 @@ import (
 
 	"github.com/Knetic/govaluate"
 	"github.com/itchyny/gojq"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/jsonschema"
 )
 
 // Extractor is used to extract part of response using a regex.
@@ -62,6 +64,89 @@ type Extractor struct {
 	//       []string{"content_type"}
 	KVal []string `yaml:"kval,omitempty" json:"kval,omitempty" jsonschema:"title=kval pairs to extract from response,description=Kval pairs to extract from response"`
 
+	// description: |
+	//   Cookie contains names of cookies to extract from Set-Cookie headers of the response.
+	//   All cookies are extracted if empty.
+	//
+	//   Each cookie is also made available as <name>_<cookie> variable along with its
+	//   attributes (<name>_<cookie>_httponly, _secure, _samesite, _path, _domain, _expires).
+	// examples:
+	//   - name: Extract session cookie
+	//     value: >
+	//       []string{"PHPSESSID"}
+	Cookie []string `yaml:"cookie,omitempty" json:"cookie,omitempty" jsonschema:"title=cookies to extract from response,description=Names of cookies to extract from Set-Cookie headers"`
+
+	// description: |
+	//   EntropyThreshold is the minimum shannon entropy (bits per byte) of segments
+	//   extracted by entropy extractors. Default is 4.0.
+	//
+	//   Segments are runs of alphanumeric and base64/url-safe characters of the part.
+	// examples:
+	//     value: "4.5"
+	EntropyThreshold float64 `yaml:"entropy-threshold,omitempty" json:"entropy-threshold,omitempty" jsonschema:"title=minimum entropy of extracted segments,description=Minimum shannon entropy in bits per byte of extracted segments"`
+	// description: |
+	//   EntropyMinLength is the minimum length of segments extracted by entropy extractors. Default is 20.
+	EntropyMinLength int `yaml:"entropy-min-length,omitempty" json:"entropy-min-length,omitempty" jsonschema:"title=minimum length of extracted segments,description=Minimum length of segments extracted by entropy extractors"`
+	// description: |
+	//   EntropyWindow evaluates segments longer than the window by windows of the given size
+	//   extracting the spans of high entropy windows instead of whole segments.
+	EntropyWindow int `yaml:"entropy-window,omitempty" json:"entropy-window,omitempty" jsonschema:"title=window size of entropy calculation,description=Size of windows long segments are evaluated by"`
+
+	// description: |
+	//   BinaryOffset is the byte offset of the field extracted by binary extractors.
+	//
+	//   Negative offsets are relative to the end of the part. No value is extracted
+	//   if the field is out of range of the part.
+	// examples:
+	//   - value: "4"
+	BinaryOffset int `yaml:"binary-offset,omitempty" json:"binary-offset,omitempty" jsonschema:"title=byte offset of the field,description=Byte offset of the field extracted by binary extractors"`
+	// description: |
+	//   BinaryLength is the length in bytes of hex and ascii fields extracted by binary
+	//   extractors. The rest of the part is extracted if empty.
+	//
+	//   Length of integer fields is implied by their format.
+	// examples:
+	//   - value: "16"
+	BinaryLength int `yaml:"binary-length,omitempty" json:"binary-length,omitempty" jsonschema:"title=length of the field,description=Length in bytes of hex and ascii fields extracted by binary extractors"`
+	// description: |
+	//   BinaryFormat is the interpretation of the field extracted by binary extractors. Default is hex.
+	//
+	//   Integer formats are extracted as decimal numbers, ascii fields are extracted up to the first null byte.
+	// values:
+	//   - "hex"
+	//   - "ascii"
+	//   - "uint8"
+	//   - "int8"
+	//   - "uint16-be"
+	//   - "uint16-le"
+	//   - "int16-be"
+	//   - "int16-le"
+	//   - "uint32-be"
+	//   - "uint32-le"
+	//   - "int32-be"
+	//   - "int32-le"
+	//   - "uint64-be"
+	//   - "uint64-le"
+	//   - "int64-be"
+	//   - "int64-le"
+	BinaryFormat string `yaml:"binary-format,omitempty" json:"binary-format,omitempty" jsonschema:"title=interpretation of the field,description=Interpretation of the field extracted by binary extractors,enum=hex,enum=ascii,enum=uint8,enum=int8,enum=uint16-be,enum=uint16-le,enum=int16-be,enum=int16-le,enum=uint32-be,enum=uint32-le,enum=int32-be,enum=int32-le,enum=uint64-be,enum=uint64-le,enum=int64-be,enum=int64-le"`
+
+	// description: |
+	//   JSONSchema is an inline json schema (written as json or yaml) the part
+	//   is validated against by json-schema extractors extracting the validation errors.
+	// examples:
+	//   - value: >
+	//       "{\"type\": \"object\", \"required\": [\"id\"]}"
+	JSONSchema string `yaml:"json-schema,omitempty" json:"json-schema,omitempty" jsonschema:"title=json schema to validate,description=Inline json schema whose validation errors are extracted"`
+	schema     *jsonschema.Schema
+	// description: |
+	//   Frameworks limits error-page extractors to the error page signatures of
+	//   the listed frameworks. Detected framework names are extracted.
+	// examples:
+	//   - value: >
+	//       []string{"spring", "java"}
+	Frameworks []string `yaml:"frameworks,omitempty" json:"frameworks,omitempty" jsonschema:"title=frameworks to extract error pages of,description=Frameworks whose error page signatures are detected"`
+
 	// description: |
 	//   JSON allows using jq-style syntax to extract items from json response
 	//
@@ -79,11 +164,28 @@ type Extractor struct {
 	//       []string{"/html/body/div/p[2]/a"}
 	XPath []string `yaml:"xpath,omitempty" json:"xpath,omitempty" jsonschema:"title=html xpath expressions to extract data,description=XPath allows using xpath expressions to extract items from html response"`
 	// description: |
-	//   Attribute is an optional attribute to extract from response XPath.
+	//   Attribute is an optional attribute to extract from response XPath or CSS.
 	//
 	// examples:
 	//   - value: "\"href\""
-	Attribute string `yaml:"attribute,omitempty" json:"attribute,omitempty" jsonschema:"title=optional attribute to extract from xpath,description=Optional attribute to extract from response XPath"`
+	Attribute string `yaml:"attribute,omitempty" json:"attribute,omitempty" jsonschema:"title=optional attribute to extract from xpath or css,description=Optional attribute to extract from response XPath or CSS"`
+
+	// description: |
+	//   CSS allows using css selectors to extract items from html response.
+	//
+	//   The text of matched elements is extracted by default. A trailing
+	//   ::attr(name) extracts an attribute, ::html the inner html and ::text
+	//   the text. A trailing :eq(n) before them extracts only the nth matched
+	//   element (starting from 0, negative from the last). Attribute is used
+	//   if no pseudo element is specified.
+	// examples:
+	//   - value: >
+	//       []string{"input[name=csrf_token]::attr(value)"}
+	//   - value: >
+	//       []string{"table.users tr td:eq(-1)"}
+	CSS []string `yaml:"css,omitempty" json:"css,omitempty" jsonschema:"title=css selectors to extract data,description=CSS selectors to extract items from html response"`
+	// cssCompiled is the compiled variant
+	cssCompiled []*cssSelector
 
 	// jsonCompiled is the compiled variant
 	jsonCompiled []*gojq.Code
@@ -113,4 +215,30 @@ type Extractor struct {
 	//   - false
 	//   - true
 	CaseInsensitive bool `yaml:"case-insensitive,omitempty" json:"case-insensitive,omitempty" jsonschema:"title=use case insensitive extract,description=use case insensitive extract"`
+
+	// description: |
+	//   Sort specifies the order of extracted values. Default is lexical.
+	//
+	//   appearance (opt-in) orders values by their position in the extracted
+	//   part, which requires searching the part again for the extracted values.
+	// values:
+	//   - "lexical"
+	//   - "appearance"
+	Sort string `yaml:"sort,omitempty" json:"sort,omitempty" jsonschema:"title=order of extracted values,description=Order of extracted values,enum=lexical,enum=appearance"`
+
+	// description: |
+	//   DisableDedupe keeps duplicate values extracted by regex extractors
+	//   in the order they appear in the extracted part.
+	DisableDedupe bool `yaml:"disable-dedupe,omitempty" json:"disable-dedupe,omitempty" jsonschema:"title=disable deduplication of extracted values,description=Keep duplicate values extracted by regex extractors"`
+
+	// description: |
+	//   OutputFile appends extracted values to a file as they are found
+	//   instead of holding them in memory. The path must be relative to
+	//   the current directory.
+	// examples:
+	//   - value: "\"endpoints.txt\""
+	OutputFile string `yaml:"output-file,omitempty" json:"output-file,omitempty" jsonschema:"title=file to append extracted values,description=Relative path of file to append extracted values to"`
+	// description: |
+	//   OutputDedupe skips values already written to the output file.
+	OutputDedupe bool `yaml:"output-dedupe,omitempty" json:"output-dedupe,omitempty" jsonschema:"title=dedupe values written to output file,description=Skip values already written to the output file"`
 }
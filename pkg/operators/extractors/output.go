@@ -0,0 +1,123 @@
+package extractors
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// outputFlushInterval is the interval after which buffered
+// extracted values are flushed to the output file
+const outputFlushInterval = time.Second
+
+// outputFiles contains output file writers shared by all extractors
+// writing to the same file
+var (
+	outputFiles   = make(map[string]*outputFile)
+	outputFilesMu sync.Mutex
+)
+
+// outputDirectory is the directory output file paths are relative to
+// (current directory if empty)
+var outputDirectory string
+
+// outputFile is a concurrency safe buffered writer for extracted values
+type outputFile struct {
+	mu        sync.Mutex
+	file      *os.File
+	writer    *bufio.Writer
+	seen      map[string]struct{}
+	lastFlush time.Time
+}
+
+// validateOutputFile validates the output file path of the extractor.
+// Only paths local to the current directory are allowed so that
+// templates cannot write to arbitrary locations.
+func (e *Extractor) validateOutputFile() error {
+	if !filepath.IsLocal(e.OutputFile) {
+		return fmt.Errorf("output-file must be a relative path inside current directory: %s", e.OutputFile)
+	}
+	return nil
+}
+
+// getOutputFile returns the shared writer for the output file of the
+// extractor, creating and opening it for appending if required.
+func (e *Extractor) getOutputFile() (*outputFile, error) {
+	path := filepath.Join(outputDirectory, filepath.Clean(e.OutputFile))
+
+	outputFilesMu.Lock()
+	defer outputFilesMu.Unlock()
+
+	if output, ok := outputFiles[path]; ok {
+		return output, nil
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	output := &outputFile{
+		file:      file,
+		writer:    bufio.NewWriter(file),
+		seen:      make(map[string]struct{}),
+		lastFlush: time.Now(),
+	}
+	outputFiles[path] = output
+	return output, nil
+}
+
+// WriteOutput appends extracted values to the output file of the
+// extractor (if any), skipping already written values if dedupe is enabled.
+func (e *Extractor) WriteOutput(values []string) {
+	if e.OutputFile == "" || len(values) == 0 {
+		return
+	}
+	output, err := e.getOutputFile()
+	if err != nil {
+		gologger.Warning().Msgf("Could not open extractor output file %s: %s\n", e.OutputFile, err)
+		return
+	}
+
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	for _, value := range values {
+		if e.OutputDedupe {
+			if _, ok := output.seen[value]; ok {
+				continue
+			}
+			output.seen[value] = struct{}{}
+		}
+		_, _ = output.writer.WriteString(value)
+		_ = output.writer.WriteByte('\n')
+	}
+	if time.Since(output.lastFlush) >= outputFlushInterval {
+		_ = output.writer.Flush()
+		output.lastFlush = time.Now()
+	}
+}
+
+// CloseOutputFiles flushes and closes all extractor output files
+func CloseOutputFiles() {
+	outputFilesMu.Lock()
+	defer outputFilesMu.Unlock()
+
+	for path, output := range outputFiles {
+		output.mu.Lock()
+		if err := output.writer.Flush(); err != nil {
+			gologger.Warning().Msgf("Could not flush extractor output file %s: %s\n", path, err)
+		}
+		_ = output.file.Close()
+		output.mu.Unlock()
+		delete(outputFiles, path)
+	}
+}
@@ -1,6 +1,90 @@
 package extractors
 
+import (
+	"sort"
+	"strings"
+)
+
 // SupportsMap determines if the extractor type requires a map
 func SupportsMap(extractor *Extractor) bool {
 	return extractor.Type.ExtractorType == KValExtractor || extractor.Type.ExtractorType == DSLExtractor
 }
+
+// Sort orders supported by extractors
+const (
+	SortLexical    = "lexical"
+	SortAppearance = "appearance"
+)
+
+// RequiresCorpus returns true if ordering the results of the extractor
+// requires the corpus the values were extracted from.
+func (e *Extractor) RequiresCorpus() bool {
+	return e.Sort == SortAppearance || e.DisableDedupe
+}
+
+// OrderResults returns the extracted values ordered as per the extractor
+// configuration. corpus is the part values were extracted from and is only
+// used if RequiresCorpus returns true.
+func (e *Extractor) OrderResults(results map[string]struct{}, corpus string) []string {
+	values := make([]string, 0, len(results))
+	for value := range results {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	if !e.RequiresCorpus() {
+		return values
+	}
+
+	if e.GetType() == RegexExtractor {
+		if ordered := e.orderRegexResults(results, corpus); len(ordered) > 0 {
+			return ordered
+		}
+		return values
+	}
+	// values not present in corpus (ex: dsl) are kept at the end in lexical order
+	sort.SliceStable(values, func(i, j int) bool {
+		first, second := strings.Index(corpus, values[i]), strings.Index(corpus, values[j])
+		if first == -1 || second == -1 {
+			return second == -1 && first != -1
+		}
+		return first < second
+	})
+	return values
+}
+
+// orderRegexResults returns the regex matches in order of appearance
+// keeping duplicates if deduplication is disabled.
+func (e *Extractor) orderRegexResults(results map[string]struct{}, corpus string) []string {
+	type position struct {
+		index int
+		value string
+	}
+	var positions []position
+
+	for _, regex := range e.regexCompiled {
+		for _, match := range regex.FindAllStringSubmatchIndex(corpus, -1) {
+			if len(match) < 2*(e.RegexGroup+1) || match[2*e.RegexGroup] < 0 {
+				continue
+			}
+			start, end := match[2*e.RegexGroup], match[2*e.RegexGroup+1]
+			if _, ok := results[corpus[start:end]]; !ok {
+				continue
+			}
+			positions = append(positions, position{index: start, value: corpus[start:end]})
+		}
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].index < positions[j].index
+	})
+
+	seen := make(map[string]struct{}, len(results))
+	values := make([]string, 0, len(positions))
+	for _, p := range positions {
+		if _, ok := seen[p.value]; ok && !e.DisableDedupe {
+			continue
+		}
+		seen[p.value] = struct{}{}
+		values = append(values, p.value)
+	}
+	return values
+}
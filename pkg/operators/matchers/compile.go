@@ -4,11 +4,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/Knetic/govaluate"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/entropy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/golden"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/jsonschema"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/ssrf"
 )
 
 // CompileMatchers performs the initial setup operation on a matcher
@@ -37,14 +42,27 @@ func (matcher *Matcher) CompileMatchers() error {
 		return err
 	}
 
+	if matcher.Confidence < 0 || matcher.Confidence > 1 {
+		return fmt.Errorf("invalid confidence %v specified, must be between 0 and 1", matcher.Confidence)
+	}
+
+	// Redirect matchers match the location header by default
+	if matcher.Part == "" && matcher.GetType() == RedirectMatcher {
+		matcher.Part = "location"
+	}
+
 	// By default, match on body if user hasn't provided any specific items
-	if matcher.Part == "" && matcher.GetType() != DSLMatcher {
+	if matcher.Part == "" && !matcher.IsPartless() {
 		matcher.Part = "body"
 	}
 
 	// Compile the regexes
 	for _, regex := range matcher.Regex {
-		compiled, err := regexp.Compile(regex)
+		pattern := regex
+		if matcher.CaseInsensitive {
+			pattern = "(?i)" + pattern
+		}
+		compiled, err := regexp.Compile(pattern)
 		if err != nil {
 			return fmt.Errorf("could not compile regex: %s", regex)
 		}
@@ -69,6 +87,64 @@ func (matcher *Matcher) CompileMatchers() error {
 		matcher.dslCompiled = append(matcher.dslCompiled, compiledExpression)
 	}
 
+	// Compile the comparison expressions
+	for _, compareExpression := range matcher.Compare {
+		compiledExpression, err := govaluate.NewEvaluableExpressionWithFunctions(compareExpression, dsl.HelperFunctions)
+		if err != nil {
+			return &dsl.CompilationError{DslSignature: compareExpression, WrappedError: err}
+		}
+		matcher.compareCompiled = append(matcher.compareCompiled, compiledExpression)
+	}
+
+	if matcher.GetType() == EntropyMatcher {
+		if matcher.EntropyThreshold <= 0 {
+			matcher.EntropyThreshold = entropy.DefaultThreshold
+		}
+		if matcher.EntropyWindow < 0 {
+			return fmt.Errorf("entropy-window must not be negative")
+		}
+	}
+
+	if matcher.GetType() == JSONSchemaMatcher {
+		if matcher.JSONSchema == "" {
+			return fmt.Errorf("json-schema must be specified for json-schema matchers")
+		}
+		compiled, err := jsonschema.Compile(matcher.JSONSchema)
+		if err != nil {
+			return fmt.Errorf("could not compile json schema: %w", err)
+		}
+		matcher.schema = compiled
+	}
+
+	if matcher.GetType() == RedirectMatcher && len(matcher.RedirectHosts) == 0 {
+		return fmt.Errorf("redirect-hosts must be specified for redirect matchers")
+	}
+
+	if matcher.GetType() == SSRFMatcher {
+		for _, name := range matcher.SSRFTargets {
+			if ssrf.Lookup(strings.ToLower(name)) == nil {
+				return fmt.Errorf("unknown ssrf target %s specified", name)
+			}
+		}
+		if matcher.ControlHost == "" {
+			matcher.ControlHost = ssrf.DefaultControlHost
+		}
+	}
+
+	if matcher.GetType() == GoldenMatcher {
+		if matcher.GoldenFile == "" {
+			return fmt.Errorf("golden-file must be specified for golden matchers")
+		}
+		if matcher.goldenContents == nil {
+			return fmt.Errorf("golden file %s was not loaded, golden matchers are only supported by http requests", matcher.GoldenFile)
+		}
+		compiled, err := golden.Compile(*matcher.goldenContents, matcher.GoldenMasks)
+		if err != nil {
+			return err
+		}
+		matcher.golden = compiled
+	}
+
 	// Set up the condition type, if any.
 	if matcher.Condition != "" {
 		matcher.condition, ok = ConditionTypes[matcher.Condition]
@@ -80,16 +156,66 @@ func (matcher *Matcher) CompileMatchers() error {
 	}
 
 	if matcher.CaseInsensitive {
-		if matcher.GetType() != WordsMatcher {
-			return fmt.Errorf("case-insensitive flag is supported only for 'word' matchers (not '%s')", matcher.Type)
+		switch matcher.GetType() {
+		case WordsMatcher:
+			for i := range matcher.Words {
+				matcher.Words[i] = strings.ToLower(matcher.Words[i])
+			}
+		case BinaryMatcher:
+			for i := range matcher.binaryDecoded {
+				matcher.binaryDecoded[i] = toLowerASCII(matcher.binaryDecoded[i])
+			}
+		case RegexMatcher:
+			// regexes are compiled with the case-insensitive flag
+		default:
+			return fmt.Errorf("case-insensitive flag is supported only for 'word', 'binary' and 'regex' matchers (not '%s')", matcher.Type)
 		}
-		for i := range matcher.Words {
-			matcher.Words[i] = strings.ToLower(matcher.Words[i])
+	}
+
+	if matcher.NormalizeWhitespace {
+		switch matcher.GetType() {
+		case WordsMatcher:
+			for i := range matcher.Words {
+				matcher.Words[i] = normalizeWhitespace(matcher.Words[i])
+			}
+		case RegexMatcher:
+		default:
+			return fmt.Errorf("normalize-whitespace flag is supported only for 'word' and 'regex' matchers (not '%s')", matcher.Type)
+		}
+	}
+
+	// Set up the occurrence count condition, if any.
+	if matcher.Count != "" {
+		if err := matcher.compileCount(); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// countOperators contains supported count comparison operators.
+// two character operators are listed first so they are matched greedily.
+var countOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// compileCount parses the count condition into an operator and a value
+func (matcher *Matcher) compileCount() error {
+	count := strings.TrimSpace(matcher.Count)
+	matcher.countOperator = "=="
+	for _, operator := range countOperators {
+		if strings.HasPrefix(count, operator) {
+			matcher.countOperator = operator
+			count = strings.TrimSpace(strings.TrimPrefix(count, operator))
+			break
+		}
+	}
+	value, err := strconv.Atoi(count)
+	if err != nil || value < 0 {
+		return fmt.Errorf("invalid count condition specified: %s", matcher.Count)
+	}
+	matcher.countValue = value
+	return nil
+}
+
 // GetType returns the condition type of the matcher
 // todo: the field should be exposed natively
 func (matcher *Matcher) GetCondition() ConditionType {
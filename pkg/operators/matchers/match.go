@@ -2,6 +2,7 @@ package matchers
 
 import (
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/Knetic/govaluate"
@@ -11,7 +12,12 @@ import (
 	dslRepo "github.com/projectdiscovery/dsl"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/entropy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/errorpage"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/redirect"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/ssrf"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
@@ -57,6 +63,9 @@ func (matcher *Matcher) MatchWords(corpus string, data map[string]interface{}) (
 	if matcher.CaseInsensitive {
 		corpus = strings.ToLower(corpus)
 	}
+	if matcher.NormalizeWhitespace {
+		corpus = normalizeWhitespace(corpus)
+	}
 
 	var matchedWords []string
 	// Iterate over all the words accepted as valid
@@ -73,8 +82,11 @@ func (matcher *Matcher) MatchWords(corpus string, data map[string]interface{}) (
 				return false, []string{}
 			}
 		}
+		if matcher.NormalizeWhitespace {
+			word = normalizeWhitespace(word)
+		}
 		// Continue if the word doesn't match
-		if !strings.Contains(corpus, word) {
+		if !matcher.containsWithCount(corpus, word) {
 			// If we are in an AND request and a match failed,
 			// return false as the AND condition fails on any single mismatch.
 			switch matcher.condition {
@@ -104,11 +116,14 @@ func (matcher *Matcher) MatchWords(corpus string, data map[string]interface{}) (
 
 // MatchRegex matches a regex check against a corpus
 func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
+	if matcher.NormalizeWhitespace {
+		corpus = normalizeWhitespace(corpus)
+	}
 	var matchedRegexes []string
 	// Iterate over all the regexes accepted as valid
 	for i, regex := range matcher.regexCompiled {
 		// Continue if the regex doesn't match
-		if !regex.MatchString(corpus) {
+		if !matcher.regexWithCount(regex, corpus) {
 			// If we are in an AND request and a match failed,
 			// return false as the AND condition fails on any single mismatch.
 			switch matcher.condition {
@@ -140,10 +155,13 @@ func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
 
 // MatchBinary matches a binary check against a corpus
 func (matcher *Matcher) MatchBinary(corpus string) (bool, []string) {
+	if matcher.CaseInsensitive {
+		corpus = toLowerASCII(corpus)
+	}
 	var matchedBinary []string
 	// Iterate over all the words accepted as valid
 	for i, binary := range matcher.binaryDecoded {
-		if !strings.Contains(corpus, binary) {
+		if !matcher.containsWithCount(corpus, binary) {
 			// If we are in an AND request and a match failed,
 			// return false as the AND condition fails on any single mismatch.
 			switch matcher.condition {
@@ -169,6 +187,60 @@ func (matcher *Matcher) MatchBinary(corpus string) (bool, []string) {
 	return false, []string{}
 }
 
+// containsWithCount returns true if corpus contains value satisfying the count condition
+func (matcher *Matcher) containsWithCount(corpus, value string) bool {
+	if matcher.countOperator == "" {
+		return strings.Contains(corpus, value)
+	}
+	return matcher.matchCount(strings.Count(corpus, value))
+}
+
+// toLowerASCII lowercases ascii letters of binary values keeping other bytes
+func toLowerASCII(value string) string {
+	lowered := []byte(value)
+	for i, char := range lowered {
+		if 'A' <= char && char <= 'Z' {
+			lowered[i] = char + 'a' - 'A'
+		}
+	}
+	return string(lowered)
+}
+
+// normalizeWhitespace collapses runs of whitespace into single spaces
+func normalizeWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// regexWithCount returns true if corpus matches regex satisfying the count condition
+func (matcher *Matcher) regexWithCount(regex *regexp.Regexp, corpus string) bool {
+	if matcher.countOperator == "" {
+		return regex.MatchString(corpus)
+	}
+	return matcher.matchCount(len(regex.FindAllStringIndex(corpus, -1)))
+}
+
+// matchCount returns true if the number of occurrences of a value satisfies
+// the count condition. Without a count condition, any occurrence is a match.
+func (matcher *Matcher) matchCount(occurrences int) bool {
+	if matcher.countOperator == "" {
+		return occurrences > 0
+	}
+	switch matcher.countOperator {
+	case ">=":
+		return occurrences >= matcher.countValue
+	case "<=":
+		return occurrences <= matcher.countValue
+	case "!=":
+		return occurrences != matcher.countValue
+	case ">":
+		return occurrences > matcher.countValue
+	case "<":
+		return occurrences < matcher.countValue
+	default:
+		return occurrences == matcher.countValue
+	}
+}
+
 // MatchDSL matches on a generic map result
 func (matcher *Matcher) MatchDSL(data map[string]interface{}) bool {
 	logExpressionEvaluationFailure := func(matcherName string, err error) {
@@ -228,6 +300,138 @@ func (matcher *Matcher) MatchDSL(data map[string]interface{}) bool {
 	return false
 }
 
+// MatchCompare evaluates comparison expressions over the fields of a response.
+// An expression referencing a variable which is not populated for the response
+// is considered as not matched.
+func (matcher *Matcher) MatchCompare(data map[string]interface{}) bool {
+	for i, expression := range matcher.compareCompiled {
+		var result bool
+		if missing := missingVariables(expression, data); len(missing) > 0 {
+			gologger.Debug().Msgf("[%s] Skipping comparison %s with unpopulated variables: %s", data["template-id"], expression.String(), strings.Join(missing, ","))
+		} else if value, err := expression.Evaluate(data); err != nil {
+			if !matcher.ignoreErr(err) {
+				gologger.Warning().Msgf("[%s] %s", data["template-id"], err.Error())
+			}
+		} else if boolValue, ok := value.(bool); !ok {
+			gologger.Error().Label("WRN").Msgf("[%s] The return value of a compare statement must return a boolean value.", data["template-id"])
+		} else {
+			result = boolValue
+		}
+
+		switch {
+		case !result && matcher.condition == ANDCondition:
+			return false
+		case result && matcher.condition == ORCondition:
+			return true
+		case result && len(matcher.compareCompiled)-1 == i:
+			return true
+		}
+	}
+	return false
+}
+
+// missingVariables returns variables referenced by expression not present in data
+func missingVariables(expression *govaluate.EvaluableExpression, data map[string]interface{}) []string {
+	var missing []string
+	for _, variable := range expression.Vars() {
+		if _, ok := data[variable]; !ok {
+			missing = append(missing, variable)
+		}
+	}
+	return missing
+}
+
+// MatchEntropy matches if the entropy of the corpus (or of any window of it)
+// reaches the threshold returning the highest entropy window as snippet.
+func (matcher *Matcher) MatchEntropy(corpus string) (bool, []string) {
+	value, window := entropy.Max(corpus, matcher.EntropyWindow)
+	if value < matcher.EntropyThreshold {
+		return false, []string{}
+	}
+	return true, []string{window}
+}
+
+// MatchJSONSchema matches if the corpus is a json document conforming to the
+// schema. Negative matchers match json documents violating the schema returning
+// the validation errors as snippets. Corpus which is not json never matches.
+func (matcher *Matcher) MatchJSONSchema(corpus string) (bool, []string) {
+	valid, validationErrors, ok := matcher.schema.Validate(corpus)
+	if !ok {
+		return false, []string{}
+	}
+	if matcher.Negative {
+		return !valid, validationErrors
+	}
+	return valid, []string{}
+}
+
+// MatchGolden matches if the corpus is equal to the golden file once masked.
+// Negative matchers match on mismatch returning the diff to the golden file
+// as snippet.
+func (matcher *Matcher) MatchGolden(corpus string) (bool, []string) {
+	equal, diff := matcher.golden.Compare(corpus)
+	if matcher.Negative {
+		return !equal, diff
+	}
+	if !equal {
+		gologger.Debug().Msgf("Response does not match golden file %s:\n%s\n", matcher.GoldenFile, strings.Join(diff, "\n"))
+	}
+	return equal, []string{}
+}
+
+// MatchSSRF matches if the response of the internal target fetched by the
+// request (the corpus) confirms ssrf compared to the response of the control
+// request returning the target and the reason of the match as snippet.
+func (matcher *Matcher) MatchSSRF(corpus string, data map[string]interface{}) (bool, []string) {
+	name := types.ToString(data["ssrf_target"])
+	if name == "" || (len(matcher.SSRFTargets) > 0 && !stringsutil.EqualFoldAny(name, matcher.SSRFTargets...)) {
+		return false, []string{}
+	}
+	target := ssrf.Lookup(name)
+	if target == nil {
+		return false, []string{}
+	}
+	statusCode, _ := data["status_code"].(int)
+	controlStatusCode, _ := data["ssrf_control_status_code"].(int)
+	internal := ssrf.Response{StatusCode: statusCode, Body: corpus}
+	control := ssrf.Response{StatusCode: controlStatusCode, Body: types.ToString(data["ssrf_control_body"])}
+	confirmed, reason := target.Confirm(types.ToString(data["ssrf_host"]), internal, control, types.ToString(data["ssrf_control_host"]))
+	if !confirmed {
+		return false, []string{}
+	}
+	return true, []string{name + ": " + reason}
+}
+
+// MatchErrorPage matches if error page signatures of any of the frameworks are
+// found in corpus returning the detected frameworks as snippets.
+func (matcher *Matcher) MatchErrorPage(corpus string) (bool, []string) {
+	detected := errorpage.Detect(corpus, matcher.Frameworks)
+	return len(detected) > 0, detected
+}
+
+// MatchRedirect matches if the redirect target in corpus points to any of the
+// redirect hosts returning the parsed redirect host as snippet.
+func (matcher *Matcher) MatchRedirect(corpus string, data map[string]interface{}) (bool, []string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	target := redirect.Host(corpus)
+	if target == "" {
+		return false, []string{}
+	}
+	for _, host := range matcher.RedirectHosts {
+		host, err := expressions.Evaluate(host, data)
+		if err != nil {
+			gologger.Warning().Msgf("Error while evaluating redirect matcher: %q", host)
+			continue
+		}
+		if target == redirect.NormalizeHost(host) {
+			return true, []string{target}
+		}
+	}
+	return false, []string{}
+}
+
 // MatchXPath matches on a generic map result
 func (matcher *Matcher) MatchXPath(corpus string) bool {
 	if strings.HasPrefix(corpus, "<?xml") {
@@ -1,10 +1,13 @@
 package matchers
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Knetic/govaluate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/errorpage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,6 +67,34 @@ func TestRegexOrCondition(t *testing.T) {
 	require.Equal(t, []string{}, matched)
 }
 
+func TestCountCondition(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Words: []string{"payload"}, Count: ">=3"}
+	err := m.CompileMatchers()
+	require.Nil(t, err)
+
+	isMatched, _ := m.MatchWords("payload payload payload", nil)
+	require.True(t, isMatched, "Could not match words with valid count condition")
+
+	isMatched, _ = m.MatchWords("payload payload", nil)
+	require.False(t, isMatched, "Could match words with invalid count condition")
+	m.Negative = true
+	require.True(t, m.Result(isMatched), "Could not negate count condition")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RegexMatcher}, Regex: []string{"\\d+"}, Count: "2"}
+	err = m.CompileMatchers()
+	require.Nil(t, err)
+
+	isMatched, matched := m.MatchRegex("1 22")
+	require.True(t, isMatched, "Could not match regex with exact count condition")
+	require.Equal(t, []string{"1", "22"}, matched)
+
+	isMatched, _ = m.MatchRegex("1 22 333")
+	require.False(t, isMatched, "Could match regex with invalid exact count condition")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Words: []string{"a"}, Count: "~3"}
+	require.NotNil(t, m.CompileMatchers(), "Could compile invalid count condition")
+}
+
 func TestHexEncoding(t *testing.T) {
 	m := &Matcher{Encoding: "hex", Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Part: "body", Words: []string{"50494e47"}}
 	err := m.CompileMatchers()
@@ -74,6 +105,43 @@ func TestHexEncoding(t *testing.T) {
 	require.Equal(t, m.Words, matched)
 }
 
+func TestCaseInsensitiveAndNormalizeWhitespace(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: BinaryMatcher}, Binary: []string{"504850"}, CaseInsensitive: true}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile binary matcher")
+	isMatched, _ := m.MatchBinary("x-powered-by: asp\xff\x00")
+	require.False(t, isMatched, "could match binary missing value")
+	isMatched, _ = m.MatchBinary("X-Powered-By: php\xff")
+	require.True(t, isMatched, "could not match binary case-insensitively")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RegexMatcher}, Regex: []string{"server: (nginx|apache)"}, CaseInsensitive: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile regex matcher")
+	isMatched, matched := m.MatchRegex("Server: NGINX")
+	require.True(t, isMatched, "could not match regex case-insensitively")
+	require.Equal(t, []string{"Server: NGINX"}, matched)
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: WordsMatcher}, Words: []string{"Access  denied for\nuser"}, NormalizeWhitespace: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile word matcher")
+	isMatched, _ = m.MatchWords("<p>Access\n\t denied   for user</p>", nil)
+	require.True(t, isMatched, "could not match words with normalized whitespace")
+	isMatched, _ = m.MatchWords("<p>access denied for user</p>", nil)
+	require.False(t, isMatched, "could match words of different case")
+	require.Equal(t, []string{"normalize-whitespace"}, m.MatchOptions())
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RegexMatcher}, Regex: []string{"a b c"}, NormalizeWhitespace: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile regex matcher")
+	isMatched, _ = m.MatchRegex("a\n b\tc")
+	require.True(t, isMatched, "could not match regex with normalized whitespace")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: StatusMatcher}, Status: []int{200}, CaseInsensitive: true}
+	require.NotNil(t, m.CompileMatchers(), "could compile case-insensitive status matcher")
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: BinaryMatcher}, Binary: []string{"50"}, NormalizeWhitespace: true}
+	require.NotNil(t, m.CompileMatchers(), "could compile binary matcher with normalized whitespace")
+}
+
 func TestMatcher_MatchDSL(t *testing.T) {
 	compiled, err := govaluate.NewEvaluableExpressionWithFunctions("contains(body, \"{{VARIABLE}}\")", dsl.HelperFunctions)
 	require.Nil(t, err, "couldn't compile expression")
@@ -90,6 +158,89 @@ func TestMatcher_MatchDSL(t *testing.T) {
 	}
 }
 
+func TestMatcher_MatchCompare(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: CompareMatcher}, Compare: []string{"status_code == 200 && content_length == 0", "reflected == token"}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	require.True(t, m.MatchCompare(map[string]interface{}{"status_code": 200, "content_length": 0}))
+	require.False(t, m.MatchCompare(map[string]interface{}{"status_code": 200, "content_length": 10}))
+	require.True(t, m.MatchCompare(map[string]interface{}{"reflected": "abc", "token": "abc"}))
+	require.False(t, m.MatchCompare(map[string]interface{}{"reflected": "abc"}), "missing variable should not match")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: CompareMatcher}, Condition: "and", Compare: []string{"status_code == 200", "missing != 'x'"}}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+	require.False(t, m.MatchCompare(map[string]interface{}{"status_code": 200}))
+}
+
+func TestMatcher_MatchEntropy(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: EntropyMatcher}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+	require.Equal(t, 4.0, m.EntropyThreshold, "could not set default threshold")
+
+	matched, _ := m.MatchEntropy("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	require.False(t, matched, "matched low entropy corpus")
+	matched, _ = m.MatchEntropy("Zx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6Hf0Jg5Ce")
+	require.True(t, matched, "could not match high entropy corpus")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: EntropyMatcher}, EntropyThreshold: 4.5, EntropyWindow: 32}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+	matched, snippets := m.MatchEntropy("<html>hello hello hello hello</html> token=Zx9Kq2Lm7Vb4Nt1Rw8Ys3Pd6Hf0Jg5Ce <html>hello hello</html>")
+	require.True(t, matched, "could not match high entropy window")
+	require.Len(t, snippets, 1, "could not get matched window")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: EntropyMatcher}, EntropyThreshold: 2.0}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+	matched, _ = m.MatchEntropy("abcdabcdabcdabcd")
+	require.True(t, matched, "could not match entropy equal to threshold")
+}
+
+func TestMatcher_MatchJSONSchema(t *testing.T) {
+	schema := `type: object
+required: [id, user]
+properties:
+  id:
+    type: integer
+  user:
+    $ref: "#/definitions/user"
+definitions:
+  user:
+    type: object
+    required: [name]
+    properties:
+      name:
+        type: string`
+
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, JSONSchema: schema}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	matched, _ := m.MatchJSONSchema(`{"id": 1, "user": {"name": "nuclei"}}`)
+	require.True(t, matched, "could not match conforming document")
+	matched, _ = m.MatchJSONSchema(`{"id": "1", "user": {}}`)
+	require.False(t, matched, "matched violating document")
+	matched, _ = m.MatchJSONSchema(`<html>not json</html>`)
+	require.False(t, matched, "matched non json corpus")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, JSONSchema: `{"type": "object", "required": ["id"]}`, Negative: true}
+	err = m.CompileMatchers()
+	require.Nil(t, err, "could not compile negative matcher")
+	matched, snippets := m.MatchJSONSchema(`{"name": "nuclei"}`)
+	require.True(t, matched, "could not match violating document with negative matcher")
+	require.Len(t, snippets, 1, "could not get validation errors")
+	matched, _ = m.MatchJSONSchema(`{"id": 1}`)
+	require.False(t, matched, "matched conforming document with negative matcher")
+	matched, _ = m.MatchJSONSchema(`not json`)
+	require.False(t, matched, "matched non json corpus with negative matcher")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: JSONSchemaMatcher}, JSONSchema: `{"$ref": "http://127.0.0.1/schema.json"}`}
+	require.NotNil(t, m.CompileMatchers(), "compiled schema with external reference")
+}
+
 func TestMatcher_MatchXPath_HTML(t *testing.T) {
 	body := `<!doctype html>
 <html>
@@ -209,3 +360,141 @@ func TestMatcher_MatchXPath_XML(t *testing.T) {
 	isMatched = m.MatchXPath("<h1> not right <q id=2/>notvalid")
 	require.False(t, isMatched, "Invalid xpath did not return false")
 }
+
+func TestMatcher_MatchErrorPage(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: ErrorPageMatcher}}
+	err := m.CompileMatchers()
+	require.Nil(t, err, "could not compile matcher")
+
+	matched, frameworks := m.MatchErrorPage("<h1>Whitelabel Error Page</h1>\norg.springframework.web.HttpRequestMethodNotSupportedException")
+	require.True(t, matched, "could not match spring error page")
+	require.Equal(t, []string{"spring"}, frameworks, "could not get detected framework")
+
+	matched, frameworks = m.MatchErrorPage("<b>Warning</b>:  mysql_fetch_array() expects parameter 1 to be resource in <b>/var/www/index.php</b> on line <b>12</b>")
+	require.True(t, matched, "could not match php warning")
+	require.Equal(t, []string{"php"}, frameworks, "could not get detected framework")
+
+	matched, _ = m.MatchErrorPage("<html><body>Welcome</body></html>")
+	require.False(t, matched, "matched page without error")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: ErrorPageMatcher}, Frameworks: []string{"django"}}
+	require.Nil(t, m.CompileMatchers(), "could not compile matcher")
+	matched, _ = m.MatchErrorPage("<h1>Whitelabel Error Page</h1>")
+	require.False(t, matched, "matched framework not listed")
+
+	t.Run("override", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "signatures.yaml")
+		err := os.WriteFile(path, []byte("- framework: spring\n- framework: custom\n  regex: ['CustomFramework Exception']\n"), 0644)
+		require.Nil(t, err, "could not write signatures")
+		require.Nil(t, errorpage.Load(path), "could not load signatures")
+		defer func() {
+			_ = os.WriteFile(path, []byte("[]"), 0644)
+			_ = errorpage.Load(path)
+		}()
+
+		m := &Matcher{Type: MatcherTypeHolder{MatcherType: ErrorPageMatcher}}
+		require.Nil(t, m.CompileMatchers(), "could not compile matcher")
+		matched, _ := m.MatchErrorPage("<h1>Whitelabel Error Page</h1>")
+		require.False(t, matched, "matched disabled framework")
+		matched, frameworks := m.MatchErrorPage("CustomFramework Exception at line 1")
+		require.True(t, matched, "could not match custom signature")
+		require.Equal(t, []string{"custom"}, frameworks, "could not get custom framework")
+	})
+}
+
+func TestMatcher_MatchRedirect(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: RedirectMatcher}, RedirectHosts: []string{"{{canary}}.oast.example.com"}}
+	require.Nil(t, m.CompileMatchers(), "could not compile matcher")
+	require.Equal(t, "location", m.Part, "could not default part to location")
+
+	data := map[string]interface{}{"canary": "c0ffee"}
+	for _, location := range []string{
+		"https://c0ffee.oast.example.com/",
+		"//c0ffee.oast.example.com",
+		`/\c0ffee.oast.example.com`,
+		`https:\\c0ffee.oast.example.com`,
+		"https://trusted.com@C0FFEE.oast.example.com.:8443/path",
+		" \t//c0ffee.oast.example.com\n",
+		"ht\ttps://c0ffee.oast.exam\nple.com",
+	} {
+		matched, hosts := m.MatchRedirect(location, data)
+		require.True(t, matched, "could not match redirect %q", location)
+		require.Equal(t, []string{"c0ffee.oast.example.com"}, hosts, "could not get redirect host of %q", location)
+	}
+	for _, location := range []string{
+		"/c0ffee.oast.example.com",
+		"https://c0ffee.oast.example.com@trusted.com/",
+		"https://trusted.com/?next=c0ffee.oast.example.com",
+		"https://c0ffee.oast.example.com.trusted.com/",
+		"javascript://c0ffee.oast.example.com",
+	} {
+		matched, _ := m.MatchRedirect(location, data)
+		require.False(t, matched, "matched redirect %q", location)
+	}
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: RedirectMatcher}}
+	require.NotNil(t, m.CompileMatchers(), "could compile matcher without redirect hosts")
+}
+
+func TestMatcher_MatchGolden(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: GoldenMatcher}, GoldenFile: "users.json", GoldenMasks: []string{`"ts":\s*\d+`}}
+	m.SetGoldenContents("{\n\"id\": 1,\n\"ts\": 1700000000\n}\n")
+	require.Nil(t, m.CompileMatchers(), "could not compile matcher")
+
+	matched, _ := m.MatchGolden("{\n\"id\": 1,\n\"ts\": 1800000000\n}")
+	require.True(t, matched, "could not match masked golden response")
+	matched, _ = m.MatchGolden("{\n\"id\": 2,\n\"ts\": 1800000000\n}")
+	require.False(t, matched, "matched changed response")
+
+	m.Negative = true
+	matched, diff := m.MatchGolden("{\n\"id\": 2,\n\"ts\": 1800000000\n}")
+	require.True(t, matched, "could not match changed response with negative matcher")
+	require.Len(t, diff, 1, "could not get diff to golden file")
+	require.Contains(t, diff[0], "-\"id\": 1,", "could not get golden line in diff")
+	require.Contains(t, diff[0], "+\"id\": 2,", "could not get response line in diff")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: GoldenMatcher}, GoldenFile: "users.json"}
+	require.NotNil(t, m.CompileMatchers(), "could compile matcher without loaded golden file")
+}
+
+func TestMatcher_MatchSSRF(t *testing.T) {
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: SSRFMatcher}, SSRFTargets: []string{"cloud-metadata", "localhost"}}
+	require.Nil(t, m.CompileMatchers(), "could not compile matcher")
+	require.Equal(t, "example.com", m.ControlHost, "could not get default control host")
+
+	data := map[string]interface{}{
+		"status_code":              200,
+		"ssrf_target":              "cloud-metadata",
+		"ssrf_host":                "169.254.169.254",
+		"ssrf_control_host":        "example.com",
+		"ssrf_control_status_code": 200,
+		"ssrf_control_body":        "<html>Example Domain</html>",
+	}
+	matched, snippets := m.MatchSSRF("ami-id\nami-launch-index\ninstance-id", data)
+	require.True(t, matched, "could not match metadata response")
+	require.Equal(t, []string{"cloud-metadata: ami-id"}, snippets, "could not get matched target")
+
+	data["ssrf_control_body"] = "ami-id"
+	matched, _ = m.MatchSSRF("ami-id", data)
+	require.False(t, matched, "matched content present in control response")
+
+	data["ssrf_target"] = "localhost"
+	data["ssrf_host"] = "127.0.0.1"
+	data["ssrf_control_body"] = "fetched http://example.com/: <html>Example Domain</html>"
+	matched, _ = m.MatchSSRF("fetched http://127.0.0.1/: <html>admin panel</html>", data)
+	require.True(t, matched, "could not match response differing from control")
+	matched, _ = m.MatchSSRF("fetched http://127.0.0.1/: <html>Example Domain</html>", data)
+	require.False(t, matched, "matched response equal to control once hosts are masked")
+	matched, _ = m.MatchSSRF("could not connect to 127.0.0.1: connection refused", data)
+	require.False(t, matched, "matched failed fetch")
+	data["ssrf_control_body"] = "fetched http://EXAMPLE.COM/ at 2024-01-02T10:00:00Z: <html>Example Domain</html>"
+	matched, _ = m.MatchSSRF("fetched http://127.0.0.1/ at 2024-01-02T10:00:01Z: <html>Example Domain</html>", data)
+	require.False(t, matched, "matched response only differing from control by dynamic fragments")
+
+	data["ssrf_target"] = "private-network"
+	matched, _ = m.MatchSSRF("internal", data)
+	require.False(t, matched, "matched target not confirmed by matcher")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: SSRFMatcher}, SSRFTargets: []string{"unknown"}}
+	require.NotNil(t, m.CompileMatchers(), "could compile matcher with unknown target")
+}
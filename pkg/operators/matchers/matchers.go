@@ -4,13 +4,16 @@ import (
 	"regexp"
 
 	"github.com/Knetic/govaluate"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/golden"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/jsonschema"
 )
 
 // Matcher is used to match a part in the output from a protocol.
 type Matcher struct {
 	// description: |
 	//   Type is the type of the matcher.
-	Type MatcherTypeHolder `yaml:"type" json:"type" jsonschema:"title=type of matcher,description=Type of the matcher,enum=status,enum=size,enum=word,enum=regex,enum=binary,enum=dsl"`
+	Type MatcherTypeHolder `yaml:"type" json:"type" jsonschema:"title=type of matcher,description=Type of the matcher,enum=status,enum=size,enum=word,enum=regex,enum=binary,enum=dsl,enum=xpath,enum=compare"`
 	// description: |
 	//   Condition is the optional condition between two matcher variables. By default,
 	//   the condition is assumed to be OR.
@@ -104,23 +107,146 @@ type Matcher struct {
 	//       []string{"//a[@target=\"_blank\"]"}
 	XPath []string `yaml:"xpath,omitempty" json:"xpath,omitempty" jsonschema:"title=xpath queries to match in response,description=xpath are the XPath queries that will be evaluated against the response part of nuclei matching rules"`
 	// description: |
+	//   Compare are the boolean dsl expressions comparing multiple fields of the same response
+	//   (ex: status code, content length and extracted values).
+	//
+	//   Unlike dsl matchers, an expression only matches when every variable it references
+	//   is populated for the response, a missing variable never silently evaluates.
+	// examples:
+	//   - name: Compare status code and length of the response
+	//     value: >
+	//       []string{"status_code == 200 && content_length == 0"}
+	//   - name: Compare a reflected value against an extracted token
+	//     value: >
+	//       []string{"reflected == token"}
+	Compare []string `yaml:"compare,omitempty" json:"compare,omitempty" jsonschema:"title=comparison expressions to match in response,description=Compare are boolean dsl expressions comparing multiple fields of the same response"`
+	// description: |
+	//   EntropyThreshold is the minimum shannon entropy (bits per byte) of the part
+	//   entropy matchers match. Default is 4.0.
+	// examples:
+	//   - name: Match encrypted or compressed blobs
+	//     value: "7.5"
+	EntropyThreshold float64 `yaml:"entropy-threshold,omitempty" json:"entropy-threshold,omitempty" jsonschema:"title=entropy threshold to match,description=Minimum shannon entropy in bits per byte of the part the matcher matches"`
+	// description: |
+	//   EntropyWindow evaluates the part by windows of the given size matching
+	//   if any window exceeds the threshold. The whole part is evaluated by default.
+	EntropyWindow int `yaml:"entropy-window,omitempty" json:"entropy-window,omitempty" jsonschema:"title=window size of entropy calculation,description=Size of windows the part is evaluated by"`
+	// description: |
+	//   JSONSchema is an inline json schema (written as json or yaml) the part
+	//   is validated against.
+	//
+	//   The matcher matches json documents conforming to the schema. Negative
+	//   matchers match json documents violating the schema. Parts which are
+	//   not json documents never match.
+	// examples:
+	//   - name: Assert the response is an object with a numeric id
+	//     value: >
+	//       "{\"type\": \"object\", \"required\": [\"id\"], \"properties\": {\"id\": {\"type\": \"integer\"}}}"
+	JSONSchema string `yaml:"json-schema,omitempty" json:"json-schema,omitempty" jsonschema:"title=json schema to validate,description=Inline json schema the part is validated against"`
+	// description: |
+	//   Frameworks limits error-page matchers to the error page signatures of
+	//   the listed frameworks. All built-in frameworks are matched by default.
+	//
+	//   The detected frameworks are returned as matched values.
+	// examples:
+	//   - name: Match only django and rails debug pages
+	//     value: >
+	//       []string{"django", "rails"}
+	Frameworks []string `yaml:"frameworks,omitempty" json:"frameworks,omitempty" jsonschema:"title=frameworks to match error pages of,description=Frameworks whose error page signatures are matched"`
+	// description: |
+	//   RedirectHosts are the attacker controlled hosts (usually derived from the
+	//   payload) the redirect target of the part must point to for redirect
+	//   matchers to match. Helper expressions are evaluated before matching.
+	//
+	//   The redirect target is parsed the way browsers do, handling //host,
+	//   backslashes, credentials (trusted.com@host) and whitespace tricks.
+	//   The part defaults to the location header.
+	// examples:
+	//   - name: Match redirects to the canary host of the payload
+	//     value: >
+	//       []string{"{{canary}}.oast.example.com"}
+	RedirectHosts []string `yaml:"redirect-hosts,omitempty" json:"redirect-hosts,omitempty" jsonschema:"title=hosts the redirect target must point to,description=Attacker controlled hosts the parsed redirect target of the part must be equal to"`
+	// description: |
+	//   GoldenFile is the reference (golden) response the part is compared to
+	//   by golden matchers. Relative paths are resolved from the directory of
+	//   the template.
+	//
+	//   The matcher matches if the part is equal to the golden file. Negative
+	//   matchers match on mismatch returning the diff to the golden file.
+	// examples:
+	//   - name: Compare the response to a stored golden response
+	//     value: "\"golden/users.json\""
+	GoldenFile string `yaml:"golden-file,omitempty" json:"golden-file,omitempty" jsonschema:"title=golden file to compare to,description=Reference response file the part is compared to"`
+	// description: |
+	//   GoldenMasks are regexes of volatile values (ex: timestamps, ids) which
+	//   are masked in both the golden file and the part before comparison.
+	// examples:
+	//   - name: Ignore timestamps and request ids
+	//     value: >
+	//       []string{"\\d{4}-\\d{2}-\\d{2}T[\\d:.]+Z", "\"request_id\":\\s*\"[^\"]+\""}
+	GoldenMasks []string `yaml:"golden-masks,omitempty" json:"golden-masks,omitempty" jsonschema:"title=masks of volatile values,description=Regexes of values ignored when comparing to the golden file"`
+	// description: |
+	//   SSRFTargets are the names of the internal targets (ex: cloud-metadata,
+	//   localhost) confirmed by ssrf matchers. All targets are confirmed if empty.
+	//
+	//   Requests fetching an internal target (detected by host or ip range in
+	//   the path, query, body or headers) are sent again with the target
+	//   replaced by the control host. The matcher matches if the response
+	//   contains content distinctive of the target absent from the control
+	//   response or, for targets without distinctive content, if it succeeds
+	//   and is dissimilar to the control response once hosts and dynamic
+	//   fragments are masked. The part defaults to body.
+	// examples:
+	//   - name: Confirm ssrf to cloud metadata services
+	//     value: >
+	//       []string{"cloud-metadata", "gcp-metadata"}
+	SSRFTargets []string `yaml:"ssrf-targets,omitempty" json:"ssrf-targets,omitempty" jsonschema:"title=internal targets confirmed by ssrf matchers,description=Names of the internal targets confirmed by ssrf matchers"`
+	// description: |
+	//   ControlHost is the external host fetched by the control request of
+	//   ssrf matchers (default example.com).
+	// examples:
+	//   - name: Control host
+	//     value: "\"control.example.org\""
+	ControlHost string `yaml:"control-host,omitempty" json:"control-host,omitempty" jsonschema:"title=external control host of ssrf matchers,description=External host fetched by the control request of ssrf matchers"`
+	// description: |
 	//   Encoding specifies the encoding for the words field if any.
 	// values:
 	//   - "hex"
 	Encoding string `yaml:"encoding,omitempty" json:"encoding,omitempty" jsonschema:"title=encoding for word field,description=Optional encoding for the word fields,enum=hex"`
 	// description: |
 	//   CaseInsensitive enables case-insensitive matches. Default is false.
+	//
+	//   It is supported by word, binary (ascii letters) and regex matchers.
 	// values:
 	//   - false
 	//   - true
 	CaseInsensitive bool `yaml:"case-insensitive,omitempty" json:"case-insensitive,omitempty" jsonschema:"title=use case insensitive match,description=use case insensitive match"`
 	// description: |
+	//   NormalizeWhitespace collapses runs of whitespace of the matched part
+	//   (and of words) into single spaces before matching. Default is false.
+	//
+	//   It is supported by word and regex matchers.
+	// values:
+	//   - false
+	//   - true
+	NormalizeWhitespace bool `yaml:"normalize-whitespace,omitempty" json:"normalize-whitespace,omitempty" jsonschema:"title=normalize whitespace before match,description=Collapse runs of whitespace into single spaces before matching"`
+	// description: |
 	//   MatchAll enables matching for all matcher values. Default is false.
 	// values:
 	//   - false
 	//   - true
 	MatchAll bool `yaml:"match-all,omitempty" json:"match-all,omitempty" jsonschema:"title=match all values,description=match all matcher values ignoring condition"`
 	// description: |
+	//   Count is the number of occurrences required for each word, regex or binary
+	//   value to be considered as matched.
+	//
+	//   A comparison operator (>=, <=, ==, !=, >, <) followed by a number is expected.
+	//   When no operator is given, == is assumed.
+	// examples:
+	//   - name: Match when a value is reflected at least 3 times
+	//     value: "\">=3\""
+	Count string `yaml:"count,omitempty" json:"count,omitempty" jsonschema:"title=occurrence count condition,description=Number of occurrences required for value to be matched (ex: >=3)"`
+	// description: |
 	//  Internal when true hides the matcher from output. Default is false.
 	// It is meant to be used in multiprotocol / flow templates to create internal matcher condition without printing it in output.
 	// or other similar use cases.
@@ -128,13 +254,30 @@ type Matcher struct {
 	//   - false
 	//   - true
 	Internal bool `yaml:"internal,omitempty" json:"internal,omitempty" jsonschema:"title=hide matcher from output,description=hide matcher from output"`
+	// description: |
+	//   Confidence is the weight (between 0 and 1) of the matcher used to compute
+	//   the confidence of results. Default is 1 (full confidence).
+	//
+	//   The confidence of a result is aggregated from the matchers that fired
+	//   as 1 - (1 - c1) * (1 - c2) * ..., so that each additional matcher
+	//   raises the confidence of the result.
+	// examples:
+	//   - name: Matcher only weakly indicating the vulnerability
+	//     value: 0.4
+	Confidence float64 `yaml:"confidence,omitempty" json:"confidence,omitempty" jsonschema:"title=confidence weight of matcher,description=Confidence weight between 0 and 1 of the matcher used to score results,minimum=0,maximum=1"`
 
 	// cached data for the compiled matcher
-	condition     ConditionType // todo: this field should be the one used for overridden marshal ops
-	matcherType   MatcherType
-	binaryDecoded []string
-	regexCompiled []*regexp.Regexp
-	dslCompiled   []*govaluate.EvaluableExpression
+	condition       ConditionType // todo: this field should be the one used for overridden marshal ops
+	matcherType     MatcherType
+	binaryDecoded   []string
+	regexCompiled   []*regexp.Regexp
+	dslCompiled     []*govaluate.EvaluableExpression
+	compareCompiled []*govaluate.EvaluableExpression
+	countOperator   string
+	countValue      int
+	schema          *jsonschema.Schema
+	golden          *golden.Golden
+	goldenContents  *string
 }
 
 // ConditionType is the type of condition for matcher
@@ -161,6 +304,32 @@ func (matcher *Matcher) Result(data bool) bool {
 	return data
 }
 
+// SetGoldenContents sets the contents of the golden file of the matcher.
+// It must be called before compiling golden matchers.
+func (matcher *Matcher) SetGoldenContents(contents string) {
+	matcher.goldenContents = &contents
+}
+
+// GetConfidence returns the confidence weight of the matcher defaulting to 1
+func (matcher *Matcher) GetConfidence() float64 {
+	if matcher.Confidence <= 0 {
+		return 1
+	}
+	return matcher.Confidence
+}
+
+// MatchOptions returns the enabled options changing how values are matched
+func (matcher *Matcher) MatchOptions() []string {
+	var options []string
+	if matcher.CaseInsensitive {
+		options = append(options, "case-insensitive")
+	}
+	if matcher.NormalizeWhitespace {
+		options = append(options, "normalize-whitespace")
+	}
+	return options
+}
+
 // ResultWithMatchedSnippet returns true and the matched snippet, or false and an empty string
 func (matcher *Matcher) ResultWithMatchedSnippet(data bool, matchedSnippet []string) (bool, []string) {
 	if matcher.Negative {
@@ -27,18 +27,39 @@ const (
 	DSLMatcher
 	// name:xpath
 	XPathMatcher
+	// name:compare
+	CompareMatcher
+	// name:entropy
+	EntropyMatcher
+	// name:json-schema
+	JSONSchemaMatcher
+	// name:error-page
+	ErrorPageMatcher
+	// name:redirect
+	RedirectMatcher
+	// name:golden
+	GoldenMatcher
+	// name:ssrf
+	SSRFMatcher
 	limit
 )
 
 // MatcherTypes is a table for conversion of matcher type from string.
 var MatcherTypes = map[MatcherType]string{
-	StatusMatcher: "status",
-	SizeMatcher:   "size",
-	WordsMatcher:  "word",
-	RegexMatcher:  "regex",
-	BinaryMatcher: "binary",
-	DSLMatcher:    "dsl",
-	XPathMatcher:  "xpath",
+	StatusMatcher:     "status",
+	SizeMatcher:       "size",
+	WordsMatcher:      "word",
+	RegexMatcher:      "regex",
+	BinaryMatcher:     "binary",
+	DSLMatcher:        "dsl",
+	XPathMatcher:      "xpath",
+	CompareMatcher:    "compare",
+	EntropyMatcher:    "entropy",
+	JSONSchemaMatcher: "json-schema",
+	ErrorPageMatcher:  "error-page",
+	RedirectMatcher:   "redirect",
+	GoldenMatcher:     "golden",
+	SSRFMatcher:       "ssrf",
 }
 
 // GetType returns the type of the matcher
@@ -46,6 +67,13 @@ func (matcher *Matcher) GetType() MatcherType {
 	return matcher.Type.MatcherType
 }
 
+// IsPartless returns true if the matcher evaluates over all response
+// variables instead of a single part of the response
+func (matcher *Matcher) IsPartless() bool {
+	matcherType := matcher.GetType()
+	return matcherType == DSLMatcher || matcherType == CompareMatcher
+}
+
 // GetSupportedMatcherTypes returns list of supported types
 func GetSupportedMatcherTypes() []MatcherType {
 	var result []MatcherType
@@ -11,7 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-var commonExpectedFields = []string{"Type", "Condition", "Name", "MatchAll", "Negative", "Internal"}
+var commonExpectedFields = []string{"Type", "Condition", "Name", "MatchAll", "Negative", "Internal", "Confidence"}
 
 // Validate perform initial validation on the matcher structure
 func (matcher *Matcher) Validate() error {
@@ -34,13 +34,27 @@ func (matcher *Matcher) Validate() error {
 	case SizeMatcher:
 		expectedFields = append(commonExpectedFields, "Size", "Part")
 	case WordsMatcher:
-		expectedFields = append(commonExpectedFields, "Words", "Part", "Encoding", "CaseInsensitive")
+		expectedFields = append(commonExpectedFields, "Words", "Part", "Encoding", "CaseInsensitive", "NormalizeWhitespace", "Count")
 	case BinaryMatcher:
-		expectedFields = append(commonExpectedFields, "Binary", "Part", "Encoding", "CaseInsensitive")
+		expectedFields = append(commonExpectedFields, "Binary", "Part", "Encoding", "CaseInsensitive", "Count")
 	case RegexMatcher:
-		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive")
+		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive", "NormalizeWhitespace", "Count")
 	case XPathMatcher:
 		expectedFields = append(commonExpectedFields, "XPath", "Part")
+	case CompareMatcher:
+		expectedFields = append(commonExpectedFields, "Compare")
+	case EntropyMatcher:
+		expectedFields = append(commonExpectedFields, "EntropyThreshold", "EntropyWindow", "Part")
+	case JSONSchemaMatcher:
+		expectedFields = append(commonExpectedFields, "JSONSchema", "Part")
+	case ErrorPageMatcher:
+		expectedFields = append(commonExpectedFields, "Frameworks", "Part")
+	case RedirectMatcher:
+		expectedFields = append(commonExpectedFields, "RedirectHosts", "Part")
+	case GoldenMatcher:
+		expectedFields = append(commonExpectedFields, "GoldenFile", "GoldenMasks", "Part")
+	case SSRFMatcher:
+		expectedFields = append(commonExpectedFields, "SSRFTargets", "ControlHost", "Part")
 	}
 
 	if err = checkFields(matcher, matcherMap, expectedFields...); err != nil {
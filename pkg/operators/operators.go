@@ -11,6 +11,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	sliceutil "github.com/projectdiscovery/utils/slice"
 )
 
@@ -41,6 +42,16 @@ type Operators struct {
 	TemplateID string `json:"-" yaml:"-" jsonschema:"-"`
 	// ExcludeMatchers is a list of excludeMatchers items
 	ExcludeMatchers *excludematchers.ExcludeMatchers `json:"-" yaml:"-" jsonschema:"-"`
+	// PartialMatchThreshold is the percentage of matchers of the and condition
+	// which must fire for a failed match to be reported as partial (0 disables)
+	PartialMatchThreshold int `json:"-" yaml:"-" jsonschema:"-"`
+}
+
+// PartialMatch lists the matchers which fired and which did not for
+// operators whose and condition failed with enough matchers fired
+type PartialMatch struct {
+	Fired  []string `json:"fired"`
+	Missed []string `json:"missed"`
 }
 
 // Compile compiles the operators as well as their corresponding matchers and extractors
@@ -104,6 +115,13 @@ type Result struct {
 	// PayloadValues contains payload values provided by user. (Optional)
 	PayloadValues map[string]interface{}
 
+	// Confidence is the aggregated confidence (between 0 and 1) of the
+	// matchers that fired. Results without weighted matchers have full confidence.
+	Confidence float64
+	// PartialMatch contains the fired and missed matchers of a failed
+	// and condition if partial matches are reported
+	PartialMatch *PartialMatch
+
 	// Optional lineCounts for file protocol
 	LineCount string
 	// Operators is reference to operators that generated this result (Read-Only)
@@ -188,6 +206,9 @@ func (r *Result) Merge(result *Result) {
 	if !r.Extracted && result.Extracted {
 		r.Extracted = result.Extracted
 	}
+	if result.Confidence > r.Confidence {
+		r.Confidence = result.Confidence
+	}
 
 	for k, v := range result.Matches {
 		r.Matches[k] = sliceutil.Dedupe(append(r.Matches[k], v...))
@@ -245,15 +266,34 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 	// state variable to check if all extractors are internal
 	var allInternalExtractors bool = true
 
-	// Start with the extractors first and evaluate them.
+	// Start with the extractors first and evaluate them in the order they
+	// are declared. Values extracted by an extractor are visible to all
+	// following extractors and to every matcher of the same response.
 	for _, extractor := range operators.Extractors {
 		if !extractor.Internal && allInternalExtractors {
 			allInternalExtractors = false
 		}
-		var extractorResults []string
-		for match := range extract(data, extractor) {
-			extractorResults = append(extractorResults, match)
+		// extract returns a set, order it so that indexed values and
+		// matchers referencing them are deterministic across runs
+		var corpus string
+		if extractor.RequiresCorpus() || extractor.GetType() == extractors.CookieExtractor {
+			corpus = getExtractorCorpus(data, extractor.Part)
+		}
+		extractorResults := extractor.OrderResults(extract(data, extractor), corpus)
+		// stream results to output file as they are found (if enabled)
+		extractor.WriteOutput(extractorResults)
+
+		// expose each cookie with its attributes as a variable
+		if extractor.GetType() == extractors.CookieExtractor {
+			for name, value := range extractor.CookieVariables(corpus) {
+				data[name] = value
+				if extractor.Internal {
+					result.DynamicValues[name] = []string{types.ToString(value)}
+				}
+			}
+		}
 
+		for _, match := range extractorResults {
 			if extractor.Internal {
 				if data, ok := result.DynamicValues[extractor.Name]; !ok {
 					result.DynamicValues[extractor.Name] = []string{match}
@@ -271,12 +311,14 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 			result.Extracts[extractor.Name] = extractorResults
 		}
 		// update data with whatever was extracted doesn't matter if it is internal or not (skip unless it empty)
-		if len(extractorResults) > 0 {
+		if len(extractorResults) > 0 && extractor.Name != "" {
 			data[extractor.Name] = getExtractedValue(extractorResults)
 		}
 	}
 
-	// expose dynamic values to same request matchers
+	// expose dynamic values to same request matchers. Matchers are only
+	// evaluated once all extractors have run, so they can reference any
+	// named extractor value (or name0..nameN when multiple were extracted).
 	if len(result.DynamicValues) > 0 {
 		dataDynamicValues := make(map[string]interface{})
 		for dynName, dynValues := range result.DynamicValues {
@@ -294,6 +336,12 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 		data = generators.MergeMaps(data, dataDynamicValues)
 	}
 
+	// unconfirmed is the probability that none of the fired matchers
+	// confirm the result, used to aggregate their confidence weights.
+	unconfirmed := 1.0
+	// all matchers of the and condition are evaluated to report partial matches
+	reportPartial := matcherCondition == matchers.ANDCondition && operators.PartialMatchThreshold > 0 && len(operators.Matchers) > 1
+	var fired, missed []string
 	for matcherIndex, matcher := range operators.Matchers {
 		// Skip matchers that are in the blocklist
 		if operators.ExcludeMatchers != nil {
@@ -302,8 +350,12 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 			}
 		}
 		if isMatch, matched := match(data, matcher); isMatch {
+			if reportPartial {
+				fired = append(fired, GetMatcherName(matcher, matcherIndex))
+			}
+			unconfirmed *= 1 - matcher.GetConfidence()
 			if isDebug { // matchers without an explicit name or with AND condition should only be made visible if debug is enabled
-				matcherName := GetMatcherName(matcher, matcherIndex)
+				matcherName := GetMatcherStatusName(matcher, matcherIndex)
 				result.Matches[matcherName] = matched
 			} else { // if it's a "named" matcher with OR condition, then display it
 				if matcherCondition == matchers.ORCondition && matcher.Name != "" {
@@ -312,14 +364,32 @@ func (operators *Operators) Execute(data map[string]interface{}, match MatchFunc
 			}
 			matches = true
 		} else if matcherCondition == matchers.ANDCondition {
+			if reportPartial {
+				missed = append(missed, GetMatcherName(matcher, matcherIndex))
+				continue
+			}
 			if len(result.DynamicValues) > 0 {
 				return result, true
 			}
 			return result, false
 		}
 	}
+	if len(missed) > 0 {
+		if len(fired)*100 >= operators.PartialMatchThreshold*(len(fired)+len(missed)) {
+			result.PartialMatch = &PartialMatch{Fired: fired, Missed: missed}
+		}
+		if len(result.DynamicValues) > 0 {
+			return result, true
+		}
+		return result, false
+	}
 
 	result.Matched = matches
+	result.Confidence = 1 - unconfirmed
+	if unconfirmed == 1 {
+		// no matchers fired (ex: extractor only results)
+		result.Confidence = 1
+	}
 	result.Extracted = len(result.OutputExtracts) > 0
 	if len(result.DynamicValues) > 0 && allInternalExtractors {
 		// only return early if all extractors are internal
@@ -356,6 +426,16 @@ func GetMatcherName(matcher *matchers.Matcher, matcherIndex int) string {
 	}
 }
 
+// GetMatcherStatusName returns the matcher name of given matcher followed by
+// the options changing how its values are matched (ex: word-1 (case-insensitive))
+func GetMatcherStatusName(matcher *matchers.Matcher, matcherIndex int) string {
+	name := GetMatcherName(matcher, matcherIndex)
+	if options := matcher.MatchOptions(); len(options) > 0 {
+		name += " (" + strings.Join(options, ",") + ")"
+	}
+	return name
+}
+
 // ExecuteInternalExtractors executes internal dynamic extractors
 func (operators *Operators) ExecuteInternalExtractors(data map[string]interface{}, extract ExtractFunc) map[string]interface{} {
 	dynamicValues := make(map[string]interface{})
@@ -384,6 +464,21 @@ func (operators *Operators) Len() int {
 	return len(operators.Matchers) + len(operators.Extractors)
 }
 
+// getExtractorCorpus returns the part of data an extractor extracted values from.
+// When part is not directly available (ex: protocol specific aliases) the full
+// response is used as a fallback.
+func getExtractorCorpus(data map[string]interface{}, part string) string {
+	if part == "" {
+		part = "body"
+	}
+	for _, key := range []string{part, "response", "raw", "data"} {
+		if value, ok := data[key]; ok {
+			return types.ToString(value)
+		}
+	}
+	return ""
+}
+
 // getExtractedValue takes array of extracted values if it only has one value
 // then it is flattened and returned as a string else original type is returned
 func getExtractedValue(values []string) any {
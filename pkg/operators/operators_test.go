@@ -3,6 +3,7 @@ package operators
 import (
 	"testing"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,3 +56,68 @@ func TestMakeDynamicValuesCallback(t *testing.T) {
 		require.Equal(t, 1, count, "could not get correct result count")
 	})
 }
+
+func TestExecuteConfidence(t *testing.T) {
+	match := func(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+		return matcher.Name != "miss", nil
+	}
+	execute := func(items ...*matchers.Matcher) float64 {
+		operators := &Operators{Matchers: items}
+		require.Nil(t, operators.Compile(), "could not compile operators")
+		result, ok := operators.Execute(map[string]interface{}{}, match, nil, false)
+		require.True(t, ok, "could not get result")
+		return result.Confidence
+	}
+	newMatcher := func(name string, confidence float64) *matchers.Matcher {
+		return &matchers.Matcher{Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher}, DSL: []string{"true"}, Name: name, Confidence: confidence}
+	}
+
+	require.Equal(t, 1.0, execute(newMatcher("a", 0)), "could not get default confidence")
+	require.InDelta(t, 0.4, execute(newMatcher("a", 0.4), newMatcher("miss", 0.9)), 0.0001, "could not ignore matchers that did not fire")
+	require.InDelta(t, 0.64, execute(newMatcher("a", 0.4), newMatcher("b", 0.4)), 0.0001, "could not aggregate confidence")
+
+	invalid := &Operators{Matchers: []*matchers.Matcher{newMatcher("a", 1.5)}}
+	require.NotNil(t, invalid.Compile(), "could not reject invalid confidence")
+}
+
+func TestExecutePartialMatch(t *testing.T) {
+	match := func(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
+		return matcher.Name != "miss", nil
+	}
+	newMatcher := func(name string) *matchers.Matcher {
+		return &matchers.Matcher{Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher}, DSL: []string{"true"}, Name: name}
+	}
+	execute := func(threshold int, items ...*matchers.Matcher) (*Result, bool) {
+		operators := &Operators{Matchers: items, MatchersCondition: "and", PartialMatchThreshold: threshold}
+		require.Nil(t, operators.Compile(), "could not compile operators")
+		return operators.Execute(map[string]interface{}{}, match, nil, false)
+	}
+
+	result, ok := execute(60, newMatcher("a"), newMatcher("miss"), newMatcher("b"))
+	require.False(t, ok, "could match failed and condition")
+	require.NotNil(t, result.PartialMatch, "could not report partial match")
+	require.Equal(t, []string{"a", "b"}, result.PartialMatch.Fired)
+	require.Equal(t, []string{"miss"}, result.PartialMatch.Missed)
+
+	result, _ = execute(70, newMatcher("a"), newMatcher("miss"), newMatcher("b"))
+	require.Nil(t, result.PartialMatch, "could report partial match below threshold")
+
+	result, _ = execute(0, newMatcher("a"), newMatcher("miss"), newMatcher("b"))
+	require.Nil(t, result.PartialMatch, "could report partial match when disabled")
+
+	result, ok = execute(50, newMatcher("a"), newMatcher("b"))
+	require.True(t, ok, "could not match and condition")
+	require.Nil(t, result.PartialMatch, "could report partial match of full match")
+}
+
+func TestGetMatcherStatusName(t *testing.T) {
+	matcher := &matchers.Matcher{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}}
+	require.Equal(t, "word-2", GetMatcherStatusName(matcher, 1), "could not get matcher name without options")
+
+	matcher.CaseInsensitive = true
+	matcher.NormalizeWhitespace = true
+	require.Equal(t, "word-2 (case-insensitive,normalize-whitespace)", GetMatcherStatusName(matcher, 1), "could not get matcher options")
+
+	matcher.Name = "server"
+	require.Equal(t, "server (case-insensitive,normalize-whitespace)", GetMatcherStatusName(matcher, 1), "could not get named matcher options")
+}
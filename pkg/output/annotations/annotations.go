@@ -0,0 +1,145 @@
+// Package annotations implements optional enrichment of result events
+// with scan-time remediation, references and owner of templates loaded
+// from a sidecar mapping file, without modifying the templates.
+package annotations
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Annotation contains the scan-time information attached to results of a template
+type Annotation struct {
+	// Remediation replaces the remediation of the template
+	Remediation string `yaml:"remediation,omitempty"`
+	// References are appended to the references of the template
+	References []string `yaml:"references,omitempty"`
+	// Owner is added to the metadata of the template as owner
+	Owner string `yaml:"owner,omitempty"`
+}
+
+// IsEmpty returns true if the annotation does not contain any information
+func (a *Annotation) IsEmpty() bool {
+	return a == nil || (a.Remediation == "" && len(a.References) == 0 && a.Owner == "")
+}
+
+// Annotator annotates results with the annotations of their template
+type Annotator struct {
+	annotations map[string]*Annotation
+}
+
+// New creates a new annotator from a yaml mapping file of template ids to
+// annotations. A nil annotator is returned if no file is provided.
+//
+// Example:
+//
+//	CVE-2021-44228:
+//	  remediation: Upgrade log4j following the internal patching guide
+//	  references:
+//	    - https://jira.example.com/browse/SEC-1234
+//	  owner: platform-team
+func New(file string) (*Annotator, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read annotations file %s", file)
+	}
+	annotations := make(map[string]*Annotation)
+	if err := yaml.UnmarshalStrict(data, &annotations); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse annotations file %s", file)
+	}
+	if err := validate(annotations); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("invalid annotations file %s", file)
+	}
+	return &Annotator{annotations: annotations}, nil
+}
+
+// validate checks that annotations have a template id and information
+func validate(annotations map[string]*Annotation) error {
+	var problems []string
+	for id, annotation := range annotations {
+		switch {
+		case strings.TrimSpace(id) == "":
+			problems = append(problems, "empty template id")
+		case annotation.IsEmpty():
+			problems = append(problems, fmt.Sprintf("%s has no remediation, references or owner", id))
+		}
+		if annotation == nil {
+			continue
+		}
+		for _, reference := range annotation.References {
+			if strings.TrimSpace(reference) == "" {
+				problems = append(problems, fmt.Sprintf("%s has an empty reference", id))
+				break
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// SkipUnknown removes annotations of template ids which are not in ids
+// and returns the removed ids.
+func (a *Annotator) SkipUnknown(ids []string) []string {
+	if a == nil {
+		return nil
+	}
+	known := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		known[id] = struct{}{}
+	}
+	var unknown []string
+	for id := range a.annotations {
+		if _, ok := known[id]; !ok {
+			unknown = append(unknown, id)
+			delete(a.annotations, id)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// Apply returns info of the template annotated with its annotation. The
+// references and metadata of info are copied as they are shared with
+// the template.
+func (a *Annotator) Apply(templateID string, info model.Info) model.Info {
+	if a == nil {
+		return info
+	}
+	annotation, ok := a.annotations[templateID]
+	if !ok {
+		return info
+	}
+	if annotation.Remediation != "" {
+		info.Remediation = annotation.Remediation
+	}
+	if len(annotation.References) > 0 {
+		var references []string
+		if info.Reference != nil {
+			references = append(references, info.Reference.ToSlice()...)
+		}
+		references = append(references, annotation.References...)
+		info.Reference = stringslice.NewRawStringSlice(references)
+	}
+	if annotation.Owner != "" {
+		metadata := make(map[string]interface{}, len(info.Metadata)+1)
+		for k, v := range info.Metadata {
+			metadata[k] = v
+		}
+		metadata["owner"] = annotation.Owner
+		info.Metadata = metadata
+	}
+	return info
+}
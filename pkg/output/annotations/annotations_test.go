@@ -0,0 +1,59 @@
+package annotations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotator(t *testing.T) {
+	annotator, err := New("")
+	require.Nil(t, err, "could not create annotator without file")
+	require.Nil(t, annotator, "got annotator without file")
+	require.Equal(t, "fix", annotator.Apply("test", model.Info{Remediation: "fix"}).Remediation, "nil annotator changed info")
+
+	file := filepath.Join(t.TempDir(), "annotations.yaml")
+	require.Nil(t, os.WriteFile(file, []byte(`
+test:
+  remediation: internal fix
+  references:
+    - https://tickets.example.com/SEC-1
+  owner: platform
+unknown:
+  owner: nobody
+`), 0644))
+	annotator, err = New(file)
+	require.Nil(t, err, "could not create annotator")
+	require.Equal(t, []string{"unknown"}, annotator.SkipUnknown([]string{"test", "other"}), "could not skip unknown ids")
+
+	info := model.Info{
+		Remediation: "upstream fix",
+		Reference:   stringslice.NewRawStringSlice("https://example.com"),
+		Metadata:    map[string]interface{}{"verified": true},
+	}
+	annotated := annotator.Apply("test", info)
+	require.Equal(t, "internal fix", annotated.Remediation, "could not apply remediation")
+	require.Equal(t, []string{"https://example.com", "https://tickets.example.com/SEC-1"}, annotated.Reference.ToSlice(), "could not apply references")
+	require.Equal(t, map[string]interface{}{"verified": true, "owner": "platform"}, annotated.Metadata, "could not apply owner")
+	require.Equal(t, map[string]interface{}{"verified": true}, info.Metadata, "modified template metadata")
+	require.Equal(t, "upstream fix", annotator.Apply("other", info).Remediation, "annotated other template")
+}
+
+func TestAnnotatorInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field": "test:\n  severity: high\n",
+		"empty":         "test: {}\n",
+		"empty ref":     "test:\n  references: ['']\n",
+	} {
+		file := filepath.Join(t.TempDir(), "annotations.yaml")
+		require.Nil(t, os.WriteFile(file, []byte(data), 0644))
+		_, err := New(file)
+		require.NotNil(t, err, "could create annotator with %s", name)
+	}
+	_, err := New("not-existing.yaml")
+	require.NotNil(t, err, "could create annotator with missing file")
+}
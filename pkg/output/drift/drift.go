@@ -0,0 +1,307 @@
+// Package drift implements detection of changes of target fingerprints
+// across scans for continuous monitoring.
+//
+// The fingerprint of a target is built from the results of the scan for
+// the target (technologies detected by matchers, headers, versions and
+// certificate details extracted by extractors etc) and is compared to the
+// fingerprint stored in a baseline file by a previous scan. Targets whose
+// fingerprint changed beyond the threshold get a drift result event and
+// the baseline is updated with the fingerprints of the scan. Targets of the
+// baseline which were scanned without results drift as their fingerprint
+// was removed.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// Components of fingerprints
+const (
+	// ComponentMatches adds template ids and matcher names of results
+	ComponentMatches = "matches"
+	// ComponentExtracts adds extracted values of results by template id
+	// and extractor name
+	ComponentExtracts = "extracts"
+	// ComponentIP adds the ips of results
+	ComponentIP = "ip"
+)
+
+// DefaultComponents are the components of fingerprints by default
+var DefaultComponents = []string{ComponentMatches, ComponentExtracts}
+
+// ParseComponents validates the components of fingerprints returning the
+// default components if none are specified
+func ParseComponents(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return DefaultComponents, nil
+	}
+	components := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch value {
+		case ComponentMatches, ComponentExtracts, ComponentIP:
+			components = append(components, value)
+		default:
+			return nil, fmt.Errorf("invalid drift fingerprint component %s: expected %s, %s or %s", value, ComponentMatches, ComponentExtracts, ComponentIP)
+		}
+	}
+	return components, nil
+}
+
+// Fingerprint is the fingerprint of a target as a mapping of attributes
+// to their sorted values
+type Fingerprint map[string][]string
+
+// add adds the value to the values of the attribute
+func (f Fingerprint) add(key, value string) {
+	values := f[key]
+	index := sort.SearchStrings(values, value)
+	if index < len(values) && values[index] == value {
+		return
+	}
+	values = append(values, "")
+	copy(values[index+1:], values[index:])
+	values[index] = value
+	f[key] = values
+}
+
+// record is the stored fingerprint of a target
+type record struct {
+	Fingerprint Fingerprint `json:"fingerprint"`
+	Updated     time.Time   `json:"updated"`
+}
+
+// Drift is the change of the fingerprint of a target
+type Drift struct {
+	Host string
+	// Score is the percentage of changed attributes of the fingerprint
+	Score   float64
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Detector detects drift of target fingerprints from a baseline
+type Detector struct {
+	file       string
+	components map[string]struct{}
+	threshold  int
+
+	mutex    sync.Mutex
+	baseline map[string]*record
+	current  map[string]Fingerprint
+	// scanned are the targets (host:port) requests were sent to
+	scanned map[string]struct{}
+}
+
+// New creates a detector comparing fingerprints built from components to
+// the baseline stored in file. Drift is reported for targets whose score
+// is above threshold (0-100). A missing file is an empty baseline.
+func New(file string, components []string, threshold int) (*Detector, error) {
+	if threshold < 0 || threshold >= 100 {
+		return nil, errors.New("drift threshold must be between 0 and 99")
+	}
+	parsed, err := ParseComponents(components)
+	if err != nil {
+		return nil, err
+	}
+	detector := &Detector{
+		file:       file,
+		components: make(map[string]struct{}, len(parsed)),
+		threshold:  threshold,
+		baseline:   make(map[string]*record),
+		current:    make(map[string]Fingerprint),
+		scanned:    make(map[string]struct{}),
+	}
+	for _, component := range parsed {
+		detector.components[component] = struct{}{}
+	}
+	if fileutil.FileExists(file) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read drift baseline")
+		}
+		if err := json.Unmarshal(data, &detector.baseline); err != nil {
+			return nil, errors.Wrapf(err, "could not parse drift baseline %s", file)
+		}
+	}
+	return detector, nil
+}
+
+// Add adds the attributes of the result event to the fingerprint of its
+// target. Partial match and drift events are not part of fingerprints.
+func (d *Detector) Add(event *output.ResultEvent) {
+	if !event.MatcherStatus || event.Partial || event.Host == "" || event.TemplateID == TemplateID {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	fingerprint, ok := d.current[event.Host]
+	if !ok {
+		fingerprint = make(Fingerprint)
+		d.current[event.Host] = fingerprint
+	}
+	if d.has(ComponentMatches) && (event.MatcherName != "" || len(event.ExtractedResults) == 0) {
+		fingerprint.add("match:"+event.TemplateID, event.MatcherName)
+	}
+	if d.has(ComponentExtracts) {
+		key := "extract:" + event.TemplateID
+		if event.ExtractorName != "" {
+			key += ":" + event.ExtractorName
+		}
+		for _, value := range event.ExtractedResults {
+			fingerprint.add(key, value)
+		}
+	}
+	if d.has(ComponentIP) && event.IP != "" {
+		fingerprint.add("ip", event.IP)
+	}
+	if len(fingerprint) == 0 {
+		delete(d.current, event.Host)
+	}
+}
+
+// AddTarget records that a request was sent to the target (url or address)
+// so that baseline targets scanned without results are compared
+func (d *Detector) AddTarget(target string) {
+	key := targetKey(target)
+	if key == "" {
+		return
+	}
+	d.mutex.Lock()
+	d.scanned[key] = struct{}{}
+	d.mutex.Unlock()
+}
+
+// targetKey returns the lowercased host:port of the url or address (or the
+// host of addresses without port) identifying the target
+func targetKey(target string) string {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if strings.Contains(target, "://") {
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Hostname() == "" {
+			return ""
+		}
+		port := parsed.Port()
+		if port == "" {
+			switch parsed.Scheme {
+			case "http", "ws":
+				port = "80"
+			case "https", "wss":
+				port = "443"
+			default:
+				return parsed.Hostname()
+			}
+		}
+		return net.JoinHostPort(parsed.Hostname(), port)
+	}
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		return net.JoinHostPort(host, port)
+	}
+	return target
+}
+
+func (d *Detector) has(component string) bool {
+	_, ok := d.components[component]
+	return ok
+}
+
+// Drifts returns the drift of targets of the baseline whose fingerprint
+// changed in the scan beyond the threshold, sorted by host. Targets without
+// baseline and baseline targets which were not scanned are not compared.
+func (d *Detector) Drifts() []*Drift {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var drifts []*Drift
+	for host, stored := range d.baseline {
+		fingerprint, ok := d.current[host]
+		if !ok {
+			if _, scanned := d.scanned[targetKey(host)]; !scanned {
+				continue
+			}
+			fingerprint = Fingerprint{}
+		}
+		drift := compare(stored.Fingerprint, fingerprint)
+		if !drift.changed() || drift.Score <= float64(d.threshold) {
+			continue
+		}
+		drift.Host = host
+		drifts = append(drifts, drift)
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Host < drifts[j].Host })
+	return drifts
+}
+
+// compare returns the drift of the current fingerprint from the stored one
+func compare(stored, current Fingerprint) *Drift {
+	drift := &Drift{}
+	for key, values := range current {
+		previous, ok := stored[key]
+		switch {
+		case !ok:
+			drift.Added = append(drift.Added, key)
+		case strings.Join(previous, "\x00") != strings.Join(values, "\x00"):
+			drift.Changed = append(drift.Changed, key)
+		}
+	}
+	for key := range stored {
+		if _, ok := current[key]; !ok {
+			drift.Removed = append(drift.Removed, key)
+		}
+	}
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	sort.Strings(drift.Changed)
+
+	if total := len(stored) + len(drift.Added); total > 0 {
+		drift.Score = float64(len(drift.Added)+len(drift.Removed)+len(drift.Changed)) * 100 / float64(total)
+	}
+	return drift
+}
+
+// changed returns true if any attribute of the fingerprint changed
+func (d *Drift) changed() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// Save updates the baseline with the fingerprints of the targets of the scan
+func (d *Detector) Save() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := time.Now().UTC()
+	for host, fingerprint := range d.current {
+		d.baseline[host] = &record{Fingerprint: fingerprint, Updated: now}
+	}
+	data, err := json.MarshalIndent(d.baseline, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal drift baseline")
+	}
+	if dir := filepath.Dir(d.file); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrap(err, "could not create drift baseline directory")
+		}
+	}
+	// write to a temporary file first to not corrupt the baseline
+	tmp := d.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write drift baseline")
+	}
+	return os.Rename(tmp, d.file)
+}
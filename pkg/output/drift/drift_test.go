@@ -0,0 +1,67 @@
+package drift
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+func TestDetector(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	results := func(server, version string) []*output.ResultEvent {
+		return []*output.ResultEvent{
+			{TemplateID: "tech-detect", MatcherName: server, Host: "https://example.com", MatcherStatus: true},
+			{TemplateID: "version", ExtractorName: "server", ExtractedResults: []string{version}, Host: "https://example.com", MatcherStatus: true},
+			{TemplateID: "tech-detect", MatcherName: "php", Host: "https://example.com", MatcherStatus: true},
+			{TemplateID: "ssl-issuer", ExtractedResults: []string{"ca"}, Host: "example.com:443", MatcherStatus: true},
+		}
+	}
+	scan := func(threshold int, events []*output.ResultEvent) []*Drift {
+		detector, err := New(file, nil, threshold)
+		require.Nil(t, err, "could not create detector")
+		for _, event := range events {
+			detector.Add(event)
+		}
+		drifts := detector.Drifts()
+		require.Nil(t, detector.Save(), "could not save baseline")
+		return drifts
+	}
+
+	require.Empty(t, scan(0, results("nginx", "1.0")), "got drift without baseline")
+	require.Empty(t, scan(0, results("nginx", "1.0")), "got drift for same fingerprint")
+	partial := &output.ResultEvent{TemplateID: "tech-detect", MatcherName: "partial-match", Host: "https://example.com", MatcherStatus: true, Partial: true}
+	require.Empty(t, scan(0, append(results("nginx", "1.0"), partial)), "got drift for partial match")
+
+	drifts := scan(0, results("nginx", "1.1"))
+	require.Len(t, drifts, 1, "could not detect drift")
+	require.Equal(t, "https://example.com", drifts[0].Host, "could not get drifted host")
+	require.Equal(t, []string{"extract:version:server"}, drifts[0].Changed, "could not get changed attributes")
+	require.Equal(t, 50.0, drifts[0].Score, "could not get drift score")
+
+	drifts = scan(50, results("apache", "1.1")[:2])
+	require.Empty(t, drifts, "got drift below threshold")
+
+	drifts = scan(10, results("iis", "2.0")[1:])
+	require.Len(t, drifts, 1, "could not detect drift above threshold")
+	require.Equal(t, []string{"extract:version:server", "match:tech-detect"}, drifts[0].Changed, "could not get changed attributes")
+	require.Contains(t, drifts[0].Event().ExtractedResults, "changed extract:version:server", "could not get drift event")
+
+	// baseline targets are only compared if scanned
+	drifts = scan(0, nil)
+	require.Empty(t, drifts, "got drift for targets which were not scanned")
+	detector, err := New(file, nil, 0)
+	require.Nil(t, err, "could not create detector")
+	detector.AddTarget("https://EXAMPLE.com/login")
+	detector.Add(&output.ResultEvent{TemplateID: TemplateID, Host: "https://example.com", MatcherStatus: true})
+	drifts = detector.Drifts()
+	require.Len(t, drifts, 2, "could not detect drift of scanned targets without results")
+	require.Equal(t, "example.com:443", drifts[0].Host, "could not get drifted address")
+	require.Equal(t, "https://example.com", drifts[1].Host, "could not get drifted url")
+	require.Equal(t, 100.0, drifts[1].Score, "could not get drift score of removed fingerprint")
+
+	_, err = New(file, []string{"headers"}, 0)
+	require.NotNil(t, err, "created detector with invalid component")
+}
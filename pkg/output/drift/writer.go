@@ -0,0 +1,87 @@
+package drift
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// TemplateID is the template id of drift result events
+const TemplateID = "fingerprint-drift"
+
+// Writer is an output writer building the fingerprints of targets from the
+// results and requests of the scan. The baseline is saved when the writer
+// is closed.
+type Writer struct {
+	output.Writer
+	detector *Detector
+}
+
+// NewWriter wraps the writer to detect drift of fingerprints of targets
+func NewWriter(writer output.Writer, detector *Detector) *Writer {
+	return &Writer{Writer: writer, detector: detector}
+}
+
+// Write adds the result to the fingerprint of its target and writes it to
+// the wrapped writer
+func (w *Writer) Write(event *output.ResultEvent) error {
+	w.detector.Add(event)
+	return w.Writer.Write(event)
+}
+
+// Request records the target of successful requests as scanned and logs
+// the request to the wrapped writer
+func (w *Writer) Request(templateID, url, requestType string, err error) {
+	if err == nil {
+		w.detector.AddTarget(url)
+	}
+	w.Writer.Request(templateID, url, requestType, err)
+}
+
+// Close saves the baseline and closes the wrapped writer
+func (w *Writer) Close() {
+	if err := w.detector.Save(); err != nil {
+		gologger.Warning().Msgf("Could not save drift baseline: %s", err)
+	}
+	w.Writer.Close()
+}
+
+// Event returns the result event of the drift listing the added, removed
+// and changed attributes of the fingerprint as extracted results
+func (d *Drift) Event() *output.ResultEvent {
+	var extracted []string
+	for _, key := range d.Added {
+		extracted = append(extracted, "added "+key)
+	}
+	for _, key := range d.Removed {
+		extracted = append(extracted, "removed "+key)
+	}
+	for _, key := range d.Changed {
+		extracted = append(extracted, "changed "+key)
+	}
+	return &output.ResultEvent{
+		TemplateID: TemplateID,
+		Info: model.Info{
+			Name:           "Fingerprint Drift",
+			Description:    fmt.Sprintf("Fingerprint of %s changed since the baseline scan", d.Host),
+			SeverityHolder: severity.Holder{Severity: severity.Info},
+		},
+		Type:             "drift",
+		Host:             d.Host,
+		Matched:          d.Host,
+		ExtractedResults: extracted,
+		Metadata: map[string]interface{}{
+			"drift_score": math.Round(d.Score*100) / 100,
+			"added":       d.Added,
+			"removed":     d.Removed,
+			"changed":     d.Changed,
+		},
+		MatcherStatus: true,
+		Timestamp:     time.Now(),
+	}
+}
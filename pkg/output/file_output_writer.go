@@ -1,25 +1,15 @@
 package output
 
-import (
-	"os"
-	"sync"
-)
-
 // fileWriter is a concurrent file based output writer.
 type fileWriter struct {
-	file *os.File
-	mu   sync.Mutex
+	file *RotatingFile
 }
 
-// NewFileOutputWriter creates a new buffered writer for a file
-func newFileOutputWriter(file string, resume bool) (*fileWriter, error) {
-	var output *os.File
-	var err error
-	if resume {
-		output, err = os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	} else {
-		output, err = os.Create(file)
-	}
+// NewFileOutputWriter creates a new writer for a file rotated and flushed
+// as configured by rotate options
+func newFileOutputWriter(file string, resume bool, rotate RotateOptions) (*fileWriter, error) {
+	rotate.Append = resume
+	output, err := NewRotatingFile(file, rotate)
 	if err != nil {
 		return nil, err
 	}
@@ -28,22 +18,14 @@ func newFileOutputWriter(file string, resume bool) (*fileWriter, error) {
 
 // WriteString writes an output to the underlying file
 func (w *fileWriter) Write(data []byte) (int, error) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-	if _, err := w.file.Write(data); err != nil {
-		return 0, err
-	}
-	if _, err := w.file.Write([]byte("\n")); err != nil {
-		return 0, err
-	}
-	return len(data) + 1, nil
+	// the line is written at once so that it is never split across rotated files
+	line := make([]byte, 0, len(data)+1)
+	line = append(line, data...)
+	line = append(line, '\n')
+	return w.file.Write(line)
 }
 
 // Close closes the underlying writer flushing everything to disk
 func (w *fileWriter) Close() error {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-	//nolint:errcheck // we don't care whether sync failed or succeeded.
-	w.file.Sync()
 	return w.file.Close()
 }
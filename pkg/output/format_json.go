@@ -9,6 +9,7 @@ func (w *StandardWriter) formatJSON(output *ResultEvent) ([]byte, error) {
 	if !w.jsonReqResp { // don't show request-response in json if not asked
 		output.Request = ""
 		output.Response = ""
+		output.RequestBody = nil
 	}
 	return jsoniter.Marshal(output)
 }
@@ -30,7 +30,7 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 			builder.WriteString(w.aurora.BrightGreen(output.ExtractorName).Bold().String())
 		}
 
-		if w.matcherStatus {
+		if w.matcherStatus || output.ErrorType != "" {
 			builder.WriteString("] [")
 			if !output.MatcherStatus {
 				builder.WriteString(w.aurora.Red("failed").String())
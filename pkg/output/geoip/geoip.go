@@ -0,0 +1,124 @@
+// Package geoip implements optional enrichment of result events with
+// geo location and asn information of the resolved target ip using
+// user provided MaxMind databases.
+package geoip
+
+import (
+	"net"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Geo contains geo location information of an ip
+type Geo struct {
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country-code,omitempty"`
+	City        string `json:"city,omitempty"`
+}
+
+// ASN contains autonomous system information of an ip
+type ASN struct {
+	Number       uint   `json:"number,omitempty"`
+	Organization string `json:"organization,omitempty"`
+}
+
+// Info contains enrichment information for an ip
+type Info struct {
+	Geo *Geo
+	ASN *ASN
+}
+
+// record is the subset of fields decoded from MaxMind City, Country and ASN databases
+type record struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	AutonomousSystemNumber       uint   `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Enricher looks up geo and asn information from MaxMind databases
+// caching results per ip for the duration of the scan.
+type Enricher struct {
+	readers []*maxminddb.Reader
+	cache   sync.Map
+}
+
+// New creates a new enricher from MaxMind database files (ex: GeoLite2-City.mmdb,
+// GeoLite2-ASN.mmdb). A nil enricher is returned if no databases are provided.
+func New(databases []string) (*Enricher, error) {
+	if len(databases) == 0 {
+		return nil, nil
+	}
+	enricher := &Enricher{}
+	for _, database := range databases {
+		reader, err := maxminddb.Open(database)
+		if err != nil {
+			enricher.Close()
+			return nil, errorutil.NewWithErr(err).Msgf("could not open geoip database %s", database)
+		}
+		enricher.readers = append(enricher.readers, reader)
+	}
+	return enricher, nil
+}
+
+// Lookup returns enrichment information for an ip or nil
+// if the ip is invalid or not found in any database.
+func (e *Enricher) Lookup(ip string) *Info {
+	if e == nil || ip == "" {
+		return nil
+	}
+	if cached, ok := e.cache.Load(ip); ok {
+		return cached.(*Info)
+	}
+	info := e.lookup(ip)
+	e.cache.Store(ip, info)
+	return info
+}
+
+func (e *Enricher) lookup(ip string) *Info {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil
+	}
+	info := &Info{}
+	for _, reader := range e.readers {
+		var rec record
+		if err := reader.Lookup(parsed, &rec); err != nil {
+			continue
+		}
+		if rec.Country.ISOCode != "" && info.Geo == nil {
+			info.Geo = &Geo{
+				Country:     rec.Country.Names["en"],
+				CountryCode: rec.Country.ISOCode,
+				City:        rec.City.Names["en"],
+			}
+		}
+		if rec.AutonomousSystemNumber != 0 && info.ASN == nil {
+			info.ASN = &ASN{
+				Number:       rec.AutonomousSystemNumber,
+				Organization: rec.AutonomousSystemOrganization,
+			}
+		}
+	}
+	if info.Geo == nil && info.ASN == nil {
+		return nil
+	}
+	return info
+}
+
+// Close closes the underlying databases
+func (e *Enricher) Close() {
+	if e == nil {
+		return
+	}
+	for _, reader := range e.readers {
+		_ = reader.Close()
+	}
+}
@@ -0,0 +1,18 @@
+package geoip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnricherOptional(t *testing.T) {
+	enricher, err := New(nil)
+	require.Nil(t, err, "could not create enricher without databases")
+	require.Nil(t, enricher, "got enricher without databases")
+	require.Nil(t, enricher.Lookup("1.1.1.1"), "got info from nil enricher")
+	enricher.Close()
+
+	_, err = New([]string{"not-existing.mmdb"})
+	require.NotNil(t, err, "could create enricher with invalid database")
+}
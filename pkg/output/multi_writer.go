@@ -37,6 +37,14 @@ func (mw *MultiWriter) Write(event *ResultEvent) error {
 	return nil
 }
 
+func (mw *MultiWriter) Annotate(event *ResultEvent) {
+	for _, writer := range mw.writers {
+		if annotator, ok := writer.(EventAnnotator); ok {
+			annotator.Annotate(event)
+		}
+	}
+}
+
 func (mw *MultiWriter) WriteFailure(event *InternalWrappedEvent) error {
 	for _, writer := range mw.writers {
 		if err := writer.WriteFailure(event); err != nil {
@@ -52,6 +60,14 @@ func (mw *MultiWriter) Request(templateID, url, requestType string, err error) {
 	}
 }
 
+func (mw *MultiWriter) WriteSkip(event *SkipEvent) {
+	for _, writer := range mw.writers {
+		if skipWriter, ok := writer.(SkipWriter); ok {
+			skipWriter.WriteSkip(event)
+		}
+	}
+}
+
 func (mw *MultiWriter) WriteStoreDebugData(host, templateID, eventType string, data string) {
 	for _, writer := range mw.writers {
 		writer.WriteStoreDebugData(host, templateID, eventType, data)
@@ -25,6 +25,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output/annotations"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output/geoip"
 	protocolUtils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
@@ -48,6 +50,19 @@ type Writer interface {
 	WriteStoreDebugData(host, templateID, eventType string, data string)
 }
 
+// SkipWriter is optionally implemented by writers logging templates skipped
+// for an input (ex: out of scope) in the trace log and json output.
+type SkipWriter interface {
+	WriteSkip(*SkipEvent)
+}
+
+// EventAnnotator is optionally implemented by writers applying the
+// annotations of its template to an event (once, before it is exported
+// or written).
+type EventAnnotator interface {
+	Annotate(*ResultEvent)
+}
+
 // StandardWriter is a writer writing output to file and screen for results.
 type StandardWriter struct {
 	json                  bool
@@ -64,8 +79,13 @@ type StandardWriter struct {
 	storeResponse         bool
 	storeResponseDir      string
 	omitTemplate          bool
+	geoip                 *geoip.Enricher
+	annotations           *annotations.Annotator
+	errorTypes            bool
 	DisableStdout         bool
 	AddNewLinesOutputFile bool // by default this is only done for stdout
+	// ScreenWriter if set is used for writing results instead of stdout
+	ScreenWriter io.Writer
 }
 
 var decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -157,6 +177,10 @@ type ResultEvent struct {
 	Metadata map[string]interface{} `json:"meta,omitempty"`
 	// IP is the IP address for the found result event.
 	IP string `json:"ip,omitempty"`
+	// Geo is the optional geo location of the IP address (requires geoip database)
+	Geo *geoip.Geo `json:"geo,omitempty"`
+	// ASN is the optional autonomous system of the IP address (requires geoip database)
+	ASN *geoip.ASN `json:"asn,omitempty"`
 	// Timestamp is the time the result was found at.
 	Timestamp time.Time `json:"timestamp"`
 	// Interaction is the full details of interactsh interaction.
@@ -164,10 +188,26 @@ type ResultEvent struct {
 	// CURLCommand is an optional curl command to reproduce the request
 	// Only applicable if the report is for HTTP.
 	CURLCommand string `json:"curl-command,omitempty"`
+	// HTTPieCommand is an optional httpie command to reproduce the request
+	// Only applicable if the report is for HTTP.
+	HTTPieCommand string `json:"httpie-command,omitempty"`
+	// RequestBody is the binary body of the request read by the curl and
+	// httpie commands from a file (reproduce.BodyFile) and written in proof bundles.
+	// It is base64 encoded in json output.
+	RequestBody []byte `json:"request-body,omitempty"`
 	// MatcherStatus is the status of the match
 	MatcherStatus bool `json:"matcher-status"`
 	// Lines is the line count for the specified match
 	Lines []int `json:"matched-line,omitempty"`
+	// Confidence is the aggregated confidence (between 0 and 1) of the matchers
+	// that fired for the result
+	Confidence float64 `json:"confidence,omitempty"`
+	// Partial is true for informational events which are not findings: partial
+	// match events of templates whose and condition failed and waf-detected
+	// events of fuzzing templates.
+	Partial bool `json:"partial,omitempty"`
+	// PartialMatch lists the fired and missed matchers of partial match events
+	PartialMatch *operators.PartialMatch `json:"partial-match,omitempty"`
 
 	// IssueTrackers is the metadata for issue trackers
 	IssueTrackers map[string]IssueTrackerMetadata `json:"issue_trackers,omitempty"`
@@ -177,6 +217,12 @@ type ResultEvent struct {
 
 	FileToIndexPosition map[string]int `json:"-"`
 	Error               string         `json:"error,omitempty"`
+	// ErrorType is the category of the request error for failure events
+	// (ex: dns_error, tls_error, timeout, connection_refused, read_error)
+	ErrorType string `json:"error-type,omitempty"`
+
+	// annotated is true once annotations were applied to the event
+	annotated bool
 }
 
 type IssueTrackerMetadata struct {
@@ -194,9 +240,15 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	}
 	auroraColorizer := aurora.NewAurora(!options.NoColor)
 
+	// only the output file is rotated, all files are flushed periodically
+	flush := RotateOptions{FlushInterval: options.OutputFlushInterval}
 	var outputFile io.WriteCloser
 	if options.Output != "" {
-		output, err := newFileOutputWriter(options.Output, resumeBool)
+		output, err := newFileOutputWriter(options.Output, resumeBool, RotateOptions{
+			MaxSize:       int64(options.OutputRotateSize) * 1024 * 1024,
+			Interval:      options.OutputRotateInterval,
+			FlushInterval: options.OutputFlushInterval,
+		})
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create output file")
 		}
@@ -204,7 +256,7 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	}
 	var traceOutput io.WriteCloser
 	if options.TraceLogFile != "" {
-		output, err := newFileOutputWriter(options.TraceLogFile, resumeBool)
+		output, err := newFileOutputWriter(options.TraceLogFile, resumeBool, flush)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create output file")
 		}
@@ -212,7 +264,7 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 	}
 	var errorOutput io.WriteCloser
 	if options.ErrorLogFile != "" {
-		output, err := newFileOutputWriter(options.ErrorLogFile, resumeBool)
+		output, err := newFileOutputWriter(options.ErrorLogFile, resumeBool, flush)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create error file")
 		}
@@ -240,10 +292,35 @@ func NewStandardWriter(options *types.Options) (*StandardWriter, error) {
 		storeResponse:    options.StoreResponse,
 		storeResponseDir: options.StoreResponseDir,
 		omitTemplate:     options.OmitTemplate,
+		errorTypes:       options.RequestFailureEvents,
+	}
+	enricher, err := geoip.New(options.GeoIPDatabases)
+	if err != nil {
+		return nil, err
+	}
+	writer.geoip = enricher
+	annotator, err := annotations.New(options.AnnotationsFile)
+	if err != nil {
+		return nil, err
 	}
+	writer.annotations = annotator
 	return writer, nil
 }
 
+// Annotations returns the annotator of result events (if any)
+func (w *StandardWriter) Annotations() *annotations.Annotator {
+	return w.annotations
+}
+
+// Annotate applies the annotations of its template to the event
+func (w *StandardWriter) Annotate(event *ResultEvent) {
+	if event.annotated {
+		return
+	}
+	event.Info = w.annotations.Apply(event.TemplateID, event.Info)
+	event.annotated = true
+}
+
 // Write writes the event to file and/or screen.
 func (w *StandardWriter) Write(event *ResultEvent) error {
 	// Enrich the result event with extra metadata on the template-path and url.
@@ -253,6 +330,11 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 
 	event.Timestamp = time.Now()
 
+	if info := w.geoip.Lookup(event.IP); info != nil {
+		event.Geo, event.ASN = info.Geo, info.ASN
+	}
+	w.Annotate(event)
+
 	var data []byte
 	var err error
 
@@ -267,10 +349,17 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 	if len(data) == 0 {
 		return nil
 	}
+	return w.writeOutput(data)
+}
+
+// writeOutput writes formatted data to stdout and the output file
+func (w *StandardWriter) writeOutput(data []byte) error {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	if !w.DisableStdout {
+	if !w.DisableStdout && w.ScreenWriter != nil {
+		_, _ = w.ScreenWriter.Write(append(append([]byte{}, data...), '\n'))
+	} else if !w.DisableStdout {
 		_, _ = os.Stdout.Write(data)
 		_, _ = os.Stdout.Write([]byte("\n"))
 	}
@@ -280,7 +369,7 @@ func (w *StandardWriter) Write(event *ResultEvent) error {
 			data = decolorizerRegex.ReplaceAll(data, []byte(""))
 		}
 		if _, writeErr := w.outputFile.Write(data); writeErr != nil {
-			return errors.Wrap(err, "could not write to output")
+			return errors.Wrap(writeErr, "could not write to output")
 		}
 		if w.AddNewLinesOutputFile && w.json {
 			_, _ = w.outputFile.Write([]byte("\n"))
@@ -295,6 +384,42 @@ type JSONLogRequest struct {
 	Input    string `json:"input"`
 	Error    string `json:"error"`
 	Type     string `json:"type"`
+	// ErrorType is the category of the error (only with -request-failure-events)
+	ErrorType string `json:"error-type,omitempty"`
+}
+
+// SkipEvent is the event of a template skipped for an input (ex: the input
+// is out of the scope of the template)
+type SkipEvent struct {
+	Template   string    `json:"template"`
+	TemplateID string    `json:"template-id"`
+	Input      string    `json:"input"`
+	Type       string    `json:"type"`
+	Reason     string    `json:"reason"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// WriteSkip writes the skip event to the trace log and to the output in
+// json mode, where skip events are told apart from results by their type
+func (w *StandardWriter) WriteSkip(event *SkipEvent) {
+	if w.traceFile == nil && !w.json {
+		return
+	}
+	if event.Type == "" {
+		event.Type = "skip"
+	}
+	data, err := jsoniter.Marshal(event)
+	if err != nil {
+		return
+	}
+	if w.traceFile != nil {
+		_, _ = w.traceFile.Write(data)
+	}
+	if w.json {
+		if err := w.writeOutput(data); err != nil {
+			gologger.Warning().Msgf("Could not write skip event to output: %s\n", err)
+		}
+	}
 }
 
 // Request writes a log the requests trace log
@@ -309,6 +434,9 @@ func (w *StandardWriter) Request(templatePath, input, requestType string, reques
 	}
 	if unwrappedErr := utils.UnwrapError(requestErr); unwrappedErr != nil {
 		request.Error = unwrappedErr.Error()
+		if w.errorTypes {
+			request.ErrorType = protocolUtils.GetErrorType(requestErr)
+		}
 	} else {
 		request.Error = "none"
 	}
@@ -343,6 +471,7 @@ func (w *StandardWriter) Close() {
 	if w.errorFile != nil {
 		w.errorFile.Close()
 	}
+	w.geoip.Close()
 }
 
 // WriteFailure writes the failure event for template to file and/or screen.
@@ -2,6 +2,8 @@ package output
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -58,3 +60,33 @@ type testWriteCloser struct {
 func (w testWriteCloser) Close() error {
 	return nil
 }
+
+func TestStandardWriterSkip(t *testing.T) {
+	traceWriter := &testWriteCloser{}
+	outputWriter := &testWriteCloser{}
+
+	w, err := NewStandardWriter(&types.Options{JSONL: true})
+	require.NoError(t, err)
+	w.traceFile = traceWriter
+	w.outputFile = outputWriter
+	w.DisableStdout = true
+	// skips are written through the optional interface of wrapped writers
+	NewMultiWriter(w).WriteSkip(&SkipEvent{TemplateID: "wp-fuzz", Input: "https://example.org", Reason: "out of scope"})
+
+	require.Contains(t, traceWriter.String(), `"type":"skip"`, "could not write skip event to trace log")
+	require.Equal(t, traceWriter.String(), outputWriter.String(), "could not write skip event to json output")
+}
+
+func TestStandardWriterAnnotate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "annotations.yaml")
+	require.Nil(t, os.WriteFile(file, []byte("test:\n  references:\n    - https://tickets.example.com/SEC-1\n"), 0644))
+
+	w, err := NewStandardWriter(&types.Options{AnnotationsFile: file})
+	require.NoError(t, err)
+	w.DisableStdout = true
+	event := &ResultEvent{TemplateID: "test"}
+	// annotations are applied through the optional interface of wrapped writers
+	NewMultiWriter(w).Annotate(event)
+	require.Nil(t, w.Write(event), "could not write annotated event")
+	require.Equal(t, []string{"https://tickets.example.com/SEC-1"}, event.Info.Reference.ToSlice(), "could not annotate event once")
+}
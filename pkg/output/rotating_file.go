@@ -0,0 +1,209 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RotateOptions contains the configuration for rotating and flushing output files
+type RotateOptions struct {
+	// MaxSize is the size in bytes after which the file is rotated (0 to disable)
+	MaxSize int64
+	// Interval is the duration after which the file is rotated (0 to disable)
+	Interval time.Duration
+	// FlushInterval is the interval at which written data is synced to disk (0 to disable)
+	FlushInterval time.Duration
+	// Append appends to the existing file instead of truncating it (ex: resume)
+	Append bool
+}
+
+// RotatingFile is a concurrent file writer rotating the file by size or
+// time and periodically syncing written data to disk.
+//
+// The file being written always stays at its path so that it can be tailed.
+// Rotated files are renamed to <name>.<n><ext> (ex: results.1.jsonl) with n
+// increasing from the highest rotated file already present, so rotated files
+// of previous or resumed scans are never overwritten. Resumed scans append to
+// the file at the path, whose size counts towards the rotation size.
+//
+// Writes are never split across files: a file is rotated before a write
+// exceeding the size and once the interval elapsed on the next write.
+type RotatingFile struct {
+	path    string
+	options RotateOptions
+
+	mutex   sync.Mutex
+	file    *os.File
+	size    int64
+	opened  time.Time
+	index   int
+	dirty   bool
+	done    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewRotatingFile creates a rotating file writer for path
+func NewRotatingFile(path string, options RotateOptions) (*RotatingFile, error) {
+	index, err := lastRotatedIndex(path)
+	if err != nil {
+		return nil, err
+	}
+	writer := &RotatingFile{path: path, options: options, index: index}
+	if err := writer.open(options.Append); err != nil {
+		return nil, err
+	}
+	if options.FlushInterval > 0 {
+		writer.done = make(chan struct{})
+		writer.stopped.Add(1)
+		go writer.flushPeriodically()
+	}
+	return writer, nil
+}
+
+// RotatedName returns the name of the rotated file of path with index
+func RotatedName(path string, index int) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + strconv.Itoa(index) + ext
+}
+
+// Write writes data to the file rotating it before if needed
+func (w *RotatingFile) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.shouldRotate(int64(len(data))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	w.dirty = true
+	return n, err
+}
+
+// Sync commits written data of the file to disk
+func (w *RotatingFile) Sync() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.sync()
+}
+
+// Close syncs and closes the file
+func (w *RotatingFile) Close() error {
+	if w.done != nil {
+		close(w.done)
+		w.stopped.Wait()
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	//nolint:errcheck // we don't care whether sync failed or succeeded.
+	w.file.Sync()
+	return w.file.Close()
+}
+
+// shouldRotate returns true if the file must be rotated before writing size
+// bytes. Empty files are never rotated.
+func (w *RotatingFile) shouldRotate(size int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.options.MaxSize > 0 && w.size+size > w.options.MaxSize {
+		return true
+	}
+	return w.options.Interval > 0 && time.Since(w.opened) >= w.options.Interval
+}
+
+// rotate renames the file to the next rotated name and opens a new file at path
+func (w *RotatingFile) rotate() error {
+	//nolint:errcheck // we don't care whether sync failed or succeeded.
+	w.file.Sync()
+	if err := w.file.Close(); err != nil {
+		return errors.Wrapf(err, "could not close output file %s", w.path)
+	}
+	w.index++
+	if err := os.Rename(w.path, RotatedName(w.path, w.index)); err != nil {
+		// keep writing to the current file
+		_ = w.open(true)
+		return errors.Wrapf(err, "could not rotate output file %s", w.path)
+	}
+	return w.open(false)
+}
+
+// open opens the file at path appending to or truncating an existing file
+func (w *RotatingFile) open(appendFile bool) error {
+	var file *os.File
+	var err error
+	if appendFile {
+		file, err = os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	} else {
+		file, err = os.Create(w.path)
+	}
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file, w.size, w.opened, w.dirty = file, info.Size(), time.Now(), false
+	return nil
+}
+
+// sync commits written data to disk if any. The mutex must be held.
+func (w *RotatingFile) sync() error {
+	if !w.dirty {
+		return nil
+	}
+	w.dirty = false
+	return w.file.Sync()
+}
+
+func (w *RotatingFile) flushPeriodically() {
+	defer w.stopped.Done()
+
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			_ = w.Sync()
+		}
+	}
+}
+
+// lastRotatedIndex returns the highest index of rotated files of path
+func lastRotatedIndex(path string) (int, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "could not list rotated files of %s", path)
+	}
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(filepath.Base(path), ext) + "."
+	var last int
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err == nil && index > last {
+			last = index
+		}
+	}
+	return last, nil
+}
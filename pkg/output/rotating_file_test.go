@@ -0,0 +1,60 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.jsonl")
+
+	writer, err := NewRotatingFile(path, RotateOptions{MaxSize: 10})
+	require.Nil(t, err, "could not create rotating file")
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err = writer.Write([]byte(line))
+		require.Nil(t, err, "could not write line")
+	}
+	require.Nil(t, writer.Close(), "could not close rotating file")
+
+	readFile := func(name string) string {
+		data, err := os.ReadFile(name)
+		require.Nil(t, err, "could not read %s", name)
+		return string(data)
+	}
+	require.Equal(t, "first\n", readFile(filepath.Join(dir, "results.1.jsonl")), "could not rotate first file")
+	require.Equal(t, "second\n", readFile(filepath.Join(dir, "results.2.jsonl")), "could not rotate second file")
+	require.Equal(t, "third\n", readFile(path), "could not write current file")
+
+	// resumed scans append and continue numbering of rotated files
+	writer, err = NewRotatingFile(path, RotateOptions{MaxSize: 10, Append: true})
+	require.Nil(t, err, "could not create resumed rotating file")
+	_, err = writer.Write([]byte("fourth\n"))
+	require.Nil(t, err, "could not write line")
+	require.Nil(t, writer.Close(), "could not close rotating file")
+	require.Equal(t, "third\n", readFile(filepath.Join(dir, "results.3.jsonl")), "could not rotate resumed file")
+	require.Equal(t, "fourth\n", readFile(path), "could not write resumed file")
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results")
+
+	writer, err := NewRotatingFile(path, RotateOptions{Interval: 50 * time.Millisecond, FlushInterval: 10 * time.Millisecond})
+	require.Nil(t, err, "could not create rotating file")
+	_, _ = writer.Write([]byte("first\n"))
+	_, _ = writer.Write([]byte("second\n"))
+	time.Sleep(60 * time.Millisecond)
+	_, _ = writer.Write([]byte("third\n"))
+	require.Nil(t, writer.Close(), "could not close rotating file")
+
+	data, err := os.ReadFile(RotatedName(path, 1))
+	require.Nil(t, err, "could not read rotated file")
+	require.Equal(t, "first\nsecond\n", string(data), "could not rotate file after interval")
+	data, err = os.ReadFile(path)
+	require.Nil(t, err, "could not read current file")
+	require.Equal(t, "third\n", string(data), "could not write current file")
+}
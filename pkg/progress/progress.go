@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/projectdiscovery/clistats"
@@ -34,7 +36,34 @@ type Progress interface {
 	IncrementFailedRequestsBy(count int64)
 }
 
-var _ Progress = &StatsTicker{}
+// Extension is optionally implemented by progress drivers displaying
+// additional scan statistics. Callers must check for it using a type
+// assertion as custom Progress implementations may not support it.
+type Extension interface {
+	// SetTunedValues sets the concurrency values chosen by auto-tuning.
+	SetTunedValues(values map[string]int)
+	// IncrementTarpitHosts increments the tarpit suspected hosts counter by 1.
+	IncrementTarpitHosts()
+	// IncrementWAFHosts increments the waf blocked hosts counter by 1.
+	IncrementWAFHosts()
+	// SetHostQueues sets the provider of per-host request queue depths.
+	SetHostQueues(queues func() map[string]int)
+	// SetTemplateInFlight sets the provider of per-template in-flight request counts.
+	SetTemplateInFlight(inFlight func() map[string]int)
+	// SetProxyHealth sets the provider of the health of rotated proxies.
+	SetProxyHealth(health func() map[string]bool)
+}
+
+var (
+	_ Progress  = &StatsTicker{}
+	_ Extension = &StatsTicker{}
+)
+
+// maxDisplayedHostQueues is the number of deepest host queues printed in stats
+const maxDisplayedHostQueues = 3
+
+// maxDisplayedTemplates is the number of templates with most in-flight requests printed in stats
+const maxDisplayedTemplates = 3
 
 // StatsTicker is a progress instance for showing program stats
 type StatsTicker struct {
@@ -43,6 +72,13 @@ type StatsTicker struct {
 	outputJSON   bool
 	stats        clistats.StatisticsClient
 	tickDuration time.Duration
+
+	tunedMu sync.RWMutex
+	tuned   map[string]int
+
+	hostQueues       func() map[string]int
+	templateInFlight func() map[string]int
+	proxyHealth      func() map[string]bool
 }
 
 // NewStatsTicker creates and returns a new progress tracking object.
@@ -85,11 +121,13 @@ func (p *StatsTicker) Init(hostCount int64, rulesCount int, requestCount int64)
 	p.stats.AddCounter("errors", uint64(0))
 	p.stats.AddCounter("matched", uint64(0))
 	p.stats.AddCounter("total", uint64(requestCount))
+	p.stats.AddCounter("tarpit", uint64(0))
+	p.stats.AddCounter("waf", uint64(0))
 
 	if p.active {
 		var printCallbackFunc clistats.DynamicCallback
 		if p.outputJSON {
-			printCallbackFunc = printCallbackJSON
+			printCallbackFunc = p.printCallbackJSON
 		} else {
 			printCallbackFunc = p.makePrintCallback()
 		}
@@ -125,6 +163,13 @@ func (p *StatsTicker) SetRequests(count uint64) {
 	p.stats.IncrementCounter("requests", int(delta))
 }
 
+// RequestStats returns the number of requests performed and total requests of the scan
+func (p *StatsTicker) RequestStats() (requests uint64, total uint64) {
+	requests, _ = p.stats.GetCounter("requests")
+	total, _ = p.stats.GetCounter("total")
+	return requests, total
+}
+
 // IncrementMatched increments the matched counter by 1.
 func (p *StatsTicker) IncrementMatched() {
 	p.stats.IncrementCounter("matched", 1)
@@ -142,6 +187,128 @@ func (p *StatsTicker) IncrementFailedRequestsBy(count int64) {
 	p.stats.IncrementCounter("errors", int(count))
 }
 
+// IncrementTarpitHosts increments the tarpit suspected hosts counter by 1.
+func (p *StatsTicker) IncrementTarpitHosts() {
+	p.stats.IncrementCounter("tarpit", 1)
+}
+
+// IncrementWAFHosts increments the waf blocked hosts counter by 1.
+func (p *StatsTicker) IncrementWAFHosts() {
+	p.stats.IncrementCounter("waf", 1)
+}
+
+// SetTunedValues sets the concurrency values chosen by auto-tuning
+func (p *StatsTicker) SetTunedValues(values map[string]int) {
+	p.tunedMu.Lock()
+	p.tuned = values
+	p.tunedMu.Unlock()
+}
+
+func (p *StatsTicker) tunedValues() map[string]int {
+	p.tunedMu.RLock()
+	defer p.tunedMu.RUnlock()
+	return p.tuned
+}
+
+// SetHostQueues sets the provider of per-host request queue depths
+func (p *StatsTicker) SetHostQueues(queues func() map[string]int) {
+	p.tunedMu.Lock()
+	p.hostQueues = queues
+	p.tunedMu.Unlock()
+}
+
+func (p *StatsTicker) hostQueueDepths() map[string]int {
+	p.tunedMu.RLock()
+	queues := p.hostQueues
+	p.tunedMu.RUnlock()
+	if queues == nil {
+		return nil
+	}
+	return queues()
+}
+
+// SetTemplateInFlight sets the provider of per-template in-flight request counts
+func (p *StatsTicker) SetTemplateInFlight(inFlight func() map[string]int) {
+	p.tunedMu.Lock()
+	p.templateInFlight = inFlight
+	p.tunedMu.Unlock()
+}
+
+func (p *StatsTicker) templateInFlightCounts() map[string]int {
+	p.tunedMu.RLock()
+	inFlight := p.templateInFlight
+	p.tunedMu.RUnlock()
+	if inFlight == nil {
+		return nil
+	}
+	return inFlight()
+}
+
+// SetProxyHealth sets the provider of the health of rotated proxies
+func (p *StatsTicker) SetProxyHealth(health func() map[string]bool) {
+	p.tunedMu.Lock()
+	p.proxyHealth = health
+	p.tunedMu.Unlock()
+}
+
+func (p *StatsTicker) proxyHealthStates() map[string]bool {
+	p.tunedMu.RLock()
+	health := p.proxyHealth
+	p.tunedMu.RUnlock()
+	if health == nil {
+		return nil
+	}
+	return health()
+}
+
+// formatProxyHealth formats the health of rotated proxies as alive/total
+func formatProxyHealth(health map[string]bool) string {
+	var alive int
+	for _, ok := range health {
+		if ok {
+			alive++
+		}
+	}
+	return fmt.Sprintf("%d/%d alive", alive, len(health))
+}
+
+// formatHostQueues formats the deepest host queues as host=depth (also
+// used for templates with most in-flight requests as template=count)
+func formatHostQueues(depths map[string]int, max int) string {
+	hosts := make([]string, 0, len(depths))
+	for host := range depths {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if depths[hosts[i]] != depths[hosts[j]] {
+			return depths[hosts[i]] > depths[hosts[j]]
+		}
+		return hosts[i] < hosts[j]
+	})
+	if len(hosts) > max {
+		hosts = hosts[:max]
+	}
+	parts := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		parts = append(parts, fmt.Sprintf("%s=%d", host, depths[host]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatTunedValues formats tuned values as sorted cli flags
+func formatTunedValues(values map[string]int) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("-%s %d", key, values[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
 func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient) interface{} {
 	return func(stats clistats.StatisticsClient) interface{} {
 		builder := &strings.Builder{}
@@ -187,6 +354,36 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 			builder.WriteString(clistats.String(errors))
 		}
 
+		if tarpit, ok := stats.GetCounter("tarpit"); ok && tarpit > 0 {
+			builder.WriteString(" | Tarpit: ")
+			builder.WriteString(clistats.String(tarpit))
+		}
+
+		if waf, ok := stats.GetCounter("waf"); ok && waf > 0 {
+			builder.WriteString(" | WAF: ")
+			builder.WriteString(clistats.String(waf))
+		}
+
+		if tuned := p.tunedValues(); len(tuned) > 0 {
+			builder.WriteString(" | Tuned: ")
+			builder.WriteString(formatTunedValues(tuned))
+		}
+
+		if queues := p.hostQueueDepths(); len(queues) > 0 {
+			builder.WriteString(" | Queued: ")
+			builder.WriteString(formatHostQueues(queues, maxDisplayedHostQueues))
+		}
+
+		if inFlight := p.templateInFlightCounts(); len(inFlight) > 0 {
+			builder.WriteString(" | In-flight: ")
+			builder.WriteString(formatHostQueues(inFlight, maxDisplayedTemplates))
+		}
+
+		if health := p.proxyHealthStates(); len(health) > 0 {
+			builder.WriteString(" | Proxies: ")
+			builder.WriteString(formatProxyHealth(health))
+		}
+
 		if okRequests && okTotal {
 			if p.cloud {
 				builder.WriteString(" | Task: ")
@@ -210,9 +407,22 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 	}
 }
 
-func printCallbackJSON(stats clistats.StatisticsClient) interface{} {
+func (p *StatsTicker) printCallbackJSON(stats clistats.StatisticsClient) interface{} {
 	builder := &strings.Builder{}
-	if err := json.NewEncoder(builder).Encode(metricsMap(stats)); err == nil {
+	metrics := metricsMap(stats)
+	if tuned := p.tunedValues(); len(tuned) > 0 {
+		metrics["tuned"] = tuned
+	}
+	if queues := p.hostQueueDepths(); len(queues) > 0 {
+		metrics["host-queues"] = queues
+	}
+	if inFlight := p.templateInFlightCounts(); len(inFlight) > 0 {
+		metrics["template-in-flight"] = inFlight
+	}
+	if health := p.proxyHealthStates(); len(health) > 0 {
+		metrics["proxies"] = health
+	}
+	if err := json.NewEncoder(builder).Encode(metrics); err == nil {
 		fmt.Fprintf(os.Stderr, "%s", builder.String())
 	}
 	return builder.String()
@@ -246,6 +456,10 @@ func metricsMap(stats clistats.StatisticsClient) map[string]interface{} {
 	results["rps"] = clistats.String(uint64(float64(requests) / duration.Seconds()))
 	errors, _ := stats.GetCounter("errors")
 	results["errors"] = clistats.String(errors)
+	tarpit, _ := stats.GetCounter("tarpit")
+	results["tarpit"] = clistats.String(tarpit)
+	waf, _ := stats.GetCounter("waf")
+	results["waf"] = clistats.String(waf)
 
 	// nolint:gomnd // this is not a magic number
 	percentData := (float64(requests) * float64(100)) / float64(total)
@@ -270,7 +484,7 @@ func (p *StatsTicker) Stop() {
 	if p.active {
 		// Print one final summary
 		if p.outputJSON {
-			printCallbackJSON(p.stats)
+			p.printCallbackJSON(p.stats)
 		} else {
 			p.makePrintCallback()(p.stats)
 		}
@@ -0,0 +1,343 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/clistats"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/levels"
+	"github.com/projectdiscovery/gologger/writer"
+	"golang.org/x/term"
+)
+
+const (
+	// maxRecentMatches is the number of recent matches displayed
+	maxRecentMatches = 5
+	// maxErrorHosts is the number of hosts with most errors displayed
+	maxErrorHosts = 5
+	// tuiRefreshInterval is the interval after which the dashboard is redrawn
+	tuiRefreshInterval = time.Second
+)
+
+// TUI is a progress instance rendering a live dashboard of the running
+// scan in place at the bottom of the terminal. Logs and results are
+// printed above the dashboard.
+type TUI struct {
+	*StatsTicker
+
+	out      *os.File
+	interval time.Duration
+
+	mutex         sync.Mutex
+	lines         int
+	lastRequests  uint64
+	lastRenderAt  time.Time
+	currentRPS    uint64
+	recentMatches []string
+	hostErrors    map[string]int
+	fuzzTemplates map[string]struct{}
+	fuzzActive    map[string]struct{}
+	fuzzRequests  uint64
+	stopped       bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ Progress = &TUI{}
+
+// NewTUI creates a new live terminal dashboard progress instance. When
+// stderr is not a terminal, plain statistics logging is used instead.
+func NewTUI(duration int, port int) (Progress, error) {
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		gologger.Warning().Msgf("Terminal not detected, falling back to statistics logging\n")
+		return NewStatsTicker(duration, true, false, false, port)
+	}
+	// the ticker is inactive and only records counters, the
+	// dashboard is rendered by the tui itself
+	ticker, err := NewStatsTicker(-1, false, false, false, port)
+	if err != nil {
+		return nil, err
+	}
+	return &TUI{
+		StatsTicker:   ticker.(*StatsTicker),
+		out:           os.Stderr,
+		interval:      tuiRefreshInterval,
+		hostErrors:    make(map[string]int),
+		fuzzTemplates: make(map[string]struct{}),
+		fuzzActive:    make(map[string]struct{}),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// Init initializes the counters and starts rendering the dashboard
+func (p *TUI) Init(hostCount int64, rulesCount int, requestCount int64) {
+	p.StatsTicker.Init(hostCount, rulesCount, requestCount)
+
+	p.mutex.Lock()
+	p.lastRenderAt = time.Now()
+	p.mutex.Unlock()
+
+	// logs are printed above the dashboard
+	gologger.DefaultLogger.SetWriter(p)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.mutex.Lock()
+				p.render()
+				p.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// Write writes a log line above the dashboard
+func (p *TUI) Write(data []byte, level levels.Level) {
+	file := os.Stderr
+	if level == levels.LevelSilent {
+		file = os.Stdout
+	}
+	p.printAbove(file, append(append([]byte{}, data...), '\n'))
+}
+
+// ScreenWriter returns a writer printing results above the dashboard
+func (p *TUI) ScreenWriter() io.Writer {
+	return &tuiScreenWriter{tui: p}
+}
+
+type tuiScreenWriter struct {
+	tui *TUI
+}
+
+func (w *tuiScreenWriter) Write(data []byte) (int, error) {
+	w.tui.printAbove(os.Stdout, data)
+	return len(data), nil
+}
+
+// printAbove clears the dashboard, writes the data and redraws the dashboard
+func (p *TUI) printAbove(file *os.File, data []byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.clear()
+	_, _ = file.Write(data)
+	p.draw()
+}
+
+// SetFuzzingTemplates sets the templates whose requests are reported as fuzzing progress
+func (p *TUI) SetFuzzingTemplates(paths []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, path := range paths {
+		p.fuzzTemplates[path] = struct{}{}
+	}
+}
+
+// RecordResult records a result displayed in the recent matches
+func (p *TUI) RecordResult(templateID, severity, matched string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	line := fmt.Sprintf("%s [%s] [%s] %s", time.Now().Format("15:04:05"), templateID, severity, matched)
+	p.recentMatches = append(p.recentMatches, line)
+	if len(p.recentMatches) > maxRecentMatches {
+		p.recentMatches = p.recentMatches[len(p.recentMatches)-maxRecentMatches:]
+	}
+}
+
+// RecordRequest records a request performed by a template for an input
+func (p *TUI) RecordRequest(templatePath, input string, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err != nil {
+		p.hostErrors[hostOf(input)]++
+	}
+	if _, ok := p.fuzzTemplates[templatePath]; ok {
+		p.fuzzRequests++
+		p.fuzzActive[templatePath] = struct{}{}
+	}
+}
+
+// hostOf returns the host of the input if it is an url
+func hostOf(input string) string {
+	if parsed, err := url.Parse(input); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return input
+}
+
+// Stop stops rendering the dashboard leaving the final state on screen
+func (p *TUI) Stop() {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	close(p.done)
+	p.wg.Wait()
+
+	p.mutex.Lock()
+	if p.lastRenderAt.IsZero() {
+		// dashboard was never started
+		p.stopped = true
+		p.mutex.Unlock()
+		return
+	}
+	p.render()
+	p.stopped = true
+	p.lines = 0
+	p.mutex.Unlock()
+
+	gologger.DefaultLogger.SetWriter(writer.NewCLI())
+}
+
+// render redraws the dashboard in place. The mutex must be held.
+func (p *TUI) render() {
+	now := time.Now()
+	requests, _ := p.stats.GetCounter("requests")
+	if elapsed := now.Sub(p.lastRenderAt).Seconds(); elapsed > 0 && requests >= p.lastRequests {
+		p.currentRPS = uint64(float64(requests-p.lastRequests) / elapsed)
+	}
+	p.lastRequests = requests
+	p.lastRenderAt = now
+
+	p.clear()
+	p.draw()
+}
+
+// clear erases the previously drawn dashboard. The mutex must be held.
+func (p *TUI) clear() {
+	if p.lines > 0 {
+		fmt.Fprintf(p.out, "\033[%dA\033[J", p.lines)
+		p.lines = 0
+	}
+}
+
+// draw writes the dashboard at the cursor position. The mutex must be held.
+func (p *TUI) draw() {
+	if p.stopped || p.lastRenderAt.IsZero() {
+		return
+	}
+	lines := p.dashboard()
+
+	width, _, err := term.GetSize(int(p.out.Fd()))
+	if err != nil || width <= 0 {
+		width = 120
+	}
+	builder := &strings.Builder{}
+	for _, line := range lines {
+		// long lines are truncated to keep the line count for clearing
+		if runes := []rune(line); len(runes) > width-1 {
+			line = string(runes[:width-1])
+		}
+		builder.WriteString(line)
+		builder.WriteString("\033[K\n")
+	}
+	_, _ = io.WriteString(p.out, builder.String())
+	p.lines = len(lines)
+}
+
+// dashboard returns the lines of the dashboard. The mutex must be held.
+func (p *TUI) dashboard() []string {
+	stats := p.stats
+
+	var duration time.Duration
+	if startedAt, ok := stats.GetStatic("startedAt"); ok {
+		if startedAtTime, ok := startedAt.(time.Time); ok {
+			duration = time.Since(startedAtTime)
+		}
+	}
+	templates, _ := stats.GetStatic("templates")
+	hosts, _ := stats.GetStatic("hosts")
+	requests, _ := stats.GetCounter("requests")
+	total, _ := stats.GetCounter("total")
+	matched, _ := stats.GetCounter("matched")
+	errors, _ := stats.GetCounter("errors")
+	if total == 0 {
+		total = requests
+	}
+	var averageRPS, percent uint64
+	if duration > 0 {
+		averageRPS = uint64(float64(requests) / duration.Seconds())
+	}
+	if total > 0 {
+		percent = uint64(float64(requests) / float64(total) * 100.0)
+	}
+
+	separator := strings.Repeat("─", 60)
+	lines := []string{
+		separator,
+		fmt.Sprintf("[%s] Templates: %s | Hosts: %s | Matched: %d | Errors: %d", fmtDuration(duration), clistats.String(templates), clistats.String(hosts), matched, errors),
+		fmt.Sprintf("Requests: %d/%d (%d%%) %s | RPS: %d (avg %d)", requests, total, percent, progressBar(percent, 20), p.currentRPS, averageRPS),
+	}
+	if tuned := p.tunedValues(); len(tuned) > 0 {
+		lines = append(lines, "Tuned: "+formatTunedValues(tuned))
+	}
+	if tarpit, _ := stats.GetCounter("tarpit"); tarpit > 0 {
+		lines = append(lines, fmt.Sprintf("Tarpit suspected hosts: %d", tarpit))
+	}
+	if waf, _ := stats.GetCounter("waf"); waf > 0 {
+		lines = append(lines, fmt.Sprintf("WAF blocked hosts: %d", waf))
+	}
+	if len(p.fuzzTemplates) > 0 {
+		lines = append(lines, fmt.Sprintf("Fuzzing: %d requests | Templates: %d/%d active", p.fuzzRequests, len(p.fuzzActive), len(p.fuzzTemplates)))
+	}
+	if len(p.hostErrors) > 0 {
+		lines = append(lines, "Top hosts by errors:")
+		for _, host := range topHosts(p.hostErrors, maxErrorHosts) {
+			lines = append(lines, fmt.Sprintf("  %-50s %d", host, p.hostErrors[host]))
+		}
+	}
+	if len(p.recentMatches) > 0 {
+		lines = append(lines, "Recent matches:")
+		for i := len(p.recentMatches) - 1; i >= 0; i-- {
+			lines = append(lines, "  "+p.recentMatches[i])
+		}
+	}
+	return lines
+}
+
+// topHosts returns the hosts with most errors sorted in descending order
+func topHosts(hostErrors map[string]int, limit int) []string {
+	hosts := make([]string, 0, len(hostErrors))
+	for host := range hostErrors {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hostErrors[hosts[i]] != hostErrors[hosts[j]] {
+			return hostErrors[hosts[i]] > hostErrors[hosts[j]]
+		}
+		return hosts[i] < hosts[j]
+	})
+	if len(hosts) > limit {
+		hosts = hosts[:limit]
+	}
+	return hosts
+}
+
+// progressBar returns a text progress bar for the percentage
+func progressBar(percent uint64, width int) string {
+	if percent > 100 {
+		percent = 100
+	}
+	filled := int(percent) * width / 100
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
@@ -100,6 +100,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errors.Wrap(err, "could not compile operators")
@@ -163,7 +164,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	// add dynamic and previous variables
 	allvars = generators.MergeMaps(allvars, dynamicValues, previous)
 	// optionvars are vars passed from CLI or env variables
-	optionVars := generators.BuildPayloadFromOptions(request.options.Options)
+	optionVars := request.options.BuildPayloadFromOptions(input.MetaInput)
 	variablesMap := request.options.Variables.Evaluate(allvars)
 	// since we evaluate variables using allvars, give precedence to variablesMap
 	allvars = generators.MergeMaps(allvars, variablesMap, optionVars, request.options.Constants)
@@ -284,6 +285,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			Context:        input.Context(),
 		})
 	}
 
@@ -0,0 +1,222 @@
+// Package autotune implements scan-level concurrency auto-tuning. The tuner
+// starts conservative and periodically scales concurrency, bulk size, payload
+// concurrency and rate limit based on observed request error rate and latency,
+// bounded by the user provided values used as maxima.
+package autotune
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+const (
+	// DefaultInterval is the interval between tuning adjustments
+	DefaultInterval = 5 * time.Second
+	// startLevel is the initial fraction of maxima used
+	startLevel = 0.25
+	// minLevel is the lowest fraction of maxima used
+	minLevel = 0.05
+	// minSamples is the minimum number of requests in a window to adjust
+	minSamples = 10
+	// maxErrorRate is the error rate above which concurrency is decreased
+	maxErrorRate = 0.10
+	// okErrorRate is the error rate below which concurrency is increased
+	okErrorRate = 0.02
+)
+
+// Values are the tunable concurrency values of a scan
+type Values struct {
+	TemplateThreads    int
+	BulkSize           int
+	PayloadConcurrency int
+	RateLimit          int
+}
+
+// String returns the values as cli flags to pin them in later scans
+func (v Values) String() string {
+	return fmt.Sprintf("-c %d -bs %d -pc %d -rl %d", v.TemplateThreads, v.BulkSize, v.PayloadConcurrency, v.RateLimit)
+}
+
+// Map returns the values keyed by their cli flag names
+func (v Values) Map() map[string]int {
+	return map[string]int{
+		"concurrency":         v.TemplateThreads,
+		"bulk-size":           v.BulkSize,
+		"payload-concurrency": v.PayloadConcurrency,
+		"rate-limit":          v.RateLimit,
+	}
+}
+
+// Tuner adjusts concurrency of a scan at runtime. Starting values are
+// written to options on creation, before the scan starts. Adjusted values
+// are only published atomically (options are shared unsynchronized) and
+// read by the resize checkpoints of payload pools and rate limiter.
+type Tuner struct {
+	max      Values
+	interval time.Duration
+	onTune   func(Values)
+
+	requests atomic.Int64
+	errors   atomic.Int64
+	latency  atomic.Int64
+	current  atomic.Pointer[Values]
+
+	mu       sync.Mutex
+	level    float64
+	baseline time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New creates a new tuner using current option values as maxima. onTune is
+// an optional callback invoked with the values chosen after each adjustment.
+func New(options *types.Options, onTune func(Values)) *Tuner {
+	tuner := &Tuner{
+		interval: DefaultInterval,
+		onTune:   onTune,
+		level:    startLevel,
+		max: Values{
+			TemplateThreads:    options.TemplateThreads,
+			BulkSize:           options.BulkSize,
+			PayloadConcurrency: options.PayloadConcurrency,
+			RateLimit:          options.RateLimit,
+		},
+	}
+	values := tuner.apply()
+	options.TemplateThreads = values.TemplateThreads
+	options.BulkSize = values.BulkSize
+	options.PayloadConcurrency = values.PayloadConcurrency
+	options.RateLimit = values.RateLimit
+	return tuner
+}
+
+// Observe records the outcome of a request
+func (t *Tuner) Observe(duration time.Duration, err error) {
+	if t == nil {
+		return
+	}
+	t.requests.Add(1)
+	if err != nil {
+		t.errors.Add(1)
+		return
+	}
+	t.latency.Add(int64(duration))
+}
+
+// Start starts periodic tuning until Stop is called
+func (t *Tuner) Start() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.adjust()
+			}
+		}
+	}()
+}
+
+// Stop stops tuning and returns the last chosen values
+func (t *Tuner) Stop() Values {
+	if t == nil {
+		return Values{}
+	}
+	if t.cancel != nil {
+		t.cancel()
+		<-t.done
+	}
+	return t.Values()
+}
+
+// Values returns the currently chosen values
+func (t *Tuner) Values() Values {
+	return *t.current.Load()
+}
+
+// adjust scales the tuning level based on the window since previous adjustment
+func (t *Tuner) adjust() {
+	requests := t.requests.Swap(0)
+	errors := t.errors.Swap(0)
+	latency := t.latency.Swap(0)
+	if requests < minSamples {
+		return
+	}
+
+	t.mu.Lock()
+	errorRate := float64(errors) / float64(requests)
+	var average time.Duration
+	if succeeded := requests - errors; succeeded > 0 {
+		average = time.Duration(latency / succeeded)
+		if t.baseline == 0 || average < t.baseline {
+			t.baseline = average
+		}
+	}
+	slow := t.baseline > 0 && average > 2*t.baseline
+
+	previous := t.level
+	switch {
+	case errorRate > maxErrorRate || slow:
+		t.level = math.Max(minLevel, t.level*0.7)
+	case errorRate < okErrorRate && (t.baseline == 0 || average < t.baseline*3/2):
+		t.level = math.Min(1, t.level*1.3)
+	}
+	changed := previous != t.level
+	t.mu.Unlock()
+
+	if changed {
+		gologger.Verbose().Msgf("Auto-tune: error rate %.2f, latency %s, using %s\n", errorRate, average, t.apply())
+	}
+}
+
+// apply publishes the values of current level
+func (t *Tuner) apply() Values {
+	t.mu.Lock()
+	values := t.valuesLocked()
+	t.mu.Unlock()
+
+	t.current.Store(&values)
+	if t.onTune != nil {
+		t.onTune(values)
+	}
+	return values
+}
+
+func (t *Tuner) valuesLocked() Values {
+	return Values{
+		TemplateThreads:    scale(t.max.TemplateThreads, t.level),
+		BulkSize:           scale(t.max.BulkSize, t.level),
+		PayloadConcurrency: scale(t.max.PayloadConcurrency, t.level),
+		RateLimit:          scale(t.max.RateLimit, t.level),
+	}
+}
+
+// scale returns level fraction of max with a minimum of 1.
+// Unlimited (zero) maxima are left as is.
+func scale(max int, level float64) int {
+	if max <= 0 {
+		return max
+	}
+	value := int(math.Round(float64(max) * level))
+	if value < 1 {
+		return 1
+	}
+	return value
+}
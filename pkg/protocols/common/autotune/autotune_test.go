@@ -0,0 +1,59 @@
+package autotune
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTuner(t *testing.T) {
+	options := &types.Options{TemplateThreads: 40, BulkSize: 20, PayloadConcurrency: 20, RateLimit: 100}
+	var tuned Values
+	tuner := New(options, func(v Values) { tuned = v })
+	require.Equal(t, Values{TemplateThreads: 10, BulkSize: 5, PayloadConcurrency: 5, RateLimit: 25}, tuned, "should start conservative")
+	require.Equal(t, 10, options.TemplateThreads)
+
+	for i := 0; i < 20; i++ {
+		tuner.Observe(100*time.Millisecond, nil)
+	}
+	tuner.adjust()
+	require.Greater(t, tuner.Values().TemplateThreads, 10, "should increase without errors")
+	require.Equal(t, 10, options.TemplateThreads, "should not write adjusted values to options")
+
+	for i := 0; i < 20; i++ {
+		tuner.Observe(0, errors.New("timeout"))
+	}
+	previous := tuner.Values().TemplateThreads
+	tuner.adjust()
+	require.Less(t, tuner.Values().TemplateThreads, previous, "should decrease on errors")
+
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 20; j++ {
+			tuner.Observe(100*time.Millisecond, nil)
+		}
+		tuner.adjust()
+	}
+	require.Equal(t, Values{TemplateThreads: 40, BulkSize: 20, PayloadConcurrency: 20, RateLimit: 100}, tuner.Values(), "should be bounded by maxima")
+}
+
+func TestTunerConcurrentValues(t *testing.T) {
+	tuner := New(&types.Options{TemplateThreads: 40, BulkSize: 20, PayloadConcurrency: 20, RateLimit: 100}, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			for j := 0; j < minSamples; j++ {
+				tuner.Observe(time.Millisecond, nil)
+			}
+			tuner.adjust()
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		require.Positive(t, tuner.Values().PayloadConcurrency, "could not read tuned values")
+	}
+	<-done
+}
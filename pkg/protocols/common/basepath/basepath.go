@@ -0,0 +1,84 @@
+// Package basepath implements rewriting of input urls of hosts to an
+// environment specific base path so that request paths relative to the
+// input are resolved against the base path of the environment.
+//
+// Mappings are specified as host=/prefix (ex: staging.example.com=/app/v2)
+// where host may optionally contain a port taking precedence over mappings
+// of the host without port.
+//
+// The base path is applied to the input, so it takes effect for paths
+// relative to the input (ex: {{BaseURL}}/login and raw request paths).
+// Inputs already below the base path, requests to absolute urls of other
+// hosts and raw requests disabling path automerge are not rewritten.
+package basepath
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// Map is a mapping of hosts to base path prefixes
+type Map struct {
+	prefixes map[string]string
+}
+
+// New creates a base path map from host=/prefix entries
+func New(entries []string) (*Map, error) {
+	m := &Map{prefixes: make(map[string]string)}
+	for _, entry := range entries {
+		host, prefix, ok := strings.Cut(entry, "=")
+		host = strings.ToLower(strings.TrimSpace(host))
+		prefix = strings.TrimSpace(prefix)
+		if !ok || host == "" || strings.Contains(host, "/") {
+			return nil, errors.Errorf("invalid base path mapping %q, expected host=/prefix", entry)
+		}
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, errors.Errorf("invalid base path mapping %q, prefix must start with /", entry)
+		}
+		if _, exists := m.prefixes[host]; exists {
+			return nil, errors.Errorf("duplicate base path mapping for host %s", host)
+		}
+		m.prefixes[host] = strings.TrimSuffix(path.Clean(prefix), "/")
+	}
+	return m, nil
+}
+
+// Prefix returns the base path prefix of the host (with optional port)
+// or empty string if the host is not mapped
+func (m *Map) Prefix(host string) string {
+	if m == nil {
+		return ""
+	}
+	host = strings.ToLower(host)
+	if prefix, ok := m.prefixes[host]; ok {
+		return prefix
+	}
+	if index := strings.LastIndex(host, ":"); index != -1 && !strings.HasSuffix(host, "]") {
+		return m.prefixes[host[:index]]
+	}
+	return ""
+}
+
+// Rewrite prefixes the path of the url with the base path of its host unless
+// the path is already below the base path. It returns true if the url was rewritten.
+func (m *Map) Rewrite(u *urlutil.URL) bool {
+	if m == nil || u == nil || u.URL == nil {
+		return false
+	}
+	prefix := m.Prefix(u.Host)
+	if prefix == "" {
+		return false
+	}
+	if u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/") {
+		return false
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = prefix + u.Path
+	} else {
+		u.Path = prefix + "/" + strings.TrimPrefix(u.Path, "/")
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package basepath
+
+import (
+	"testing"
+
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite(t *testing.T) {
+	m, err := New([]string{"staging.example.com=/app/v2/", "staging.example.com:8443=/alt", "dev.example.com=/"})
+	require.NoError(t, err, "could not create base path map")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "https://staging.example.com", expected: "https://staging.example.com/app/v2"},
+		{input: "https://staging.example.com/", expected: "https://staging.example.com/app/v2/"},
+		{input: "https://STAGING.example.com/login?next=/", expected: "https://STAGING.example.com/app/v2/login?next=/"},
+		{input: "https://staging.example.com/app/v2/login", expected: "https://staging.example.com/app/v2/login"},
+		{input: "https://staging.example.com:8443/login", expected: "https://staging.example.com:8443/alt/login"},
+		{input: "https://staging.example.com:8080/login", expected: "https://staging.example.com:8080/app/v2/login"},
+		{input: "https://prod.example.com/login", expected: "https://prod.example.com/login"},
+		{input: "https://dev.example.com/login", expected: "https://dev.example.com/login"},
+	}
+	for _, test := range tests {
+		parsed, err := urlutil.ParseAbsoluteURL(test.input, false)
+		require.NoError(t, err, "could not parse url")
+		m.Rewrite(parsed)
+		require.Equal(t, test.expected, parsed.String(), "could not rewrite %s", test.input)
+	}
+
+	for _, entries := range [][]string{{"staging.example.com"}, {"=/app"}, {"staging.example.com=app"}, {"a.com=/x", "A.com=/y"}, {"https://a.com=/x"}} {
+		_, err := New(entries)
+		require.Error(t, err, "created base path map with invalid entries %v", entries)
+	}
+}
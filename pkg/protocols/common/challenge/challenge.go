@@ -0,0 +1,151 @@
+// Package challenge implements detection of anti-automation challenge pages
+// (ex: javascript challenges served by cdn and waf vendors) which break http
+// only scanning, and keeps the clearance obtained by passing a challenge with
+// the headless browser so that it is reused by subsequent http requests.
+//
+// A challenge is passed at most once per host during a scan since passing
+// requires a browser and is costly. Hosts whose challenge could not be passed
+// are scanned without clearance.
+package challenge
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// signature identifies the challenge page of a vendor
+type signature struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// signatures are matched against headers and body of responses
+var signatures = []signature{
+	{name: "cloudflare", pattern: regexp.MustCompile(`(?i)cf-mitigated: challenge|/cdn-cgi/challenge-platform/|<title>just a moment\.\.\.</title>|cf_chl_opt`)},
+	{name: "ddos-guard", pattern: regexp.MustCompile(`(?i)/\.well-known/ddos-guard/|ddos-guard\.net/`)},
+	{name: "sucuri", pattern: regexp.MustCompile(`(?i)sucuri_cloudproxy_js`)},
+	{name: "aws-waf", pattern: regexp.MustCompile(`(?i)awswafintegration|\.token\.awswaf\.com`)},
+	{name: "akamai", pattern: regexp.MustCompile(`(?i)sec-if-cpt-container|/_sec/cp_challenge/`)},
+	{name: "imperva", pattern: regexp.MustCompile(`(?i)/_incapsula_resource\?`)},
+	{name: "generic", pattern: regexp.MustCompile(`(?i)checking (if the site connection is secure|your browser before accessing)|enable javascript and cookies to continue`)},
+}
+
+// Clearance is the state obtained by passing the challenge of a host
+type Clearance struct {
+	// Cookies are the cookies set by the challenge
+	Cookies []*http.Cookie
+	// UserAgent is the user agent of the browser which passed the challenge
+	// since clearance cookies are usually bound to it
+	UserAgent string
+}
+
+// Handler detects challenge responses and keeps the clearance of hosts
+type Handler struct {
+	signatures []signature
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+
+	detected atomic.Int64
+	passed   atomic.Int64
+	failed   atomic.Int64
+}
+
+// hostState is the challenge state of a host
+type hostState struct {
+	once      sync.Once
+	detected  atomic.Bool
+	clearance atomic.Pointer[Clearance]
+}
+
+// New creates a new challenge handler. Custom patterns (regexes matched against
+// headers and body of responses) are detected in addition to built-in signatures.
+func New(patterns []string) (*Handler, error) {
+	handler := &Handler{signatures: signatures, hosts: make(map[string]*hostState)}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile challenge pattern %s: %w", pattern, err)
+		}
+		handler.signatures = append(handler.signatures, signature{name: "custom", pattern: compiled})
+	}
+	return handler, nil
+}
+
+// Detect returns the name of the challenge if the response is a challenge page
+func (h *Handler) Detect(headers, body string) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+	for _, signature := range h.signatures {
+		if signature.pattern.MatchString(headers) || signature.pattern.MatchString(body) {
+			return signature.name, true
+		}
+	}
+	return "", false
+}
+
+// MarkDetected records a challenge detected for the host returning
+// true the first time a challenge is detected for it
+func (h *Handler) MarkDetected(host string) bool {
+	if h.state(host).detected.Swap(true) {
+		return false
+	}
+	h.detected.Add(1)
+	return true
+}
+
+// Clearance returns the clearance of the host if its challenge was passed
+func (h *Handler) Clearance(host string) *Clearance {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	state, ok := h.hosts[host]
+	h.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return state.clearance.Load()
+}
+
+// Pass passes the challenge of the host with pass once per host. Concurrent
+// callers wait for the attempt in progress. The clearance of the host is
+// returned if passed along with the error of the attempt if done by this call.
+func (h *Handler) Pass(host string, pass func() (*Clearance, error)) (clearance *Clearance, err error) {
+	state := h.state(host)
+	state.once.Do(func() {
+		var passed *Clearance
+		if passed, err = pass(); err != nil || passed == nil {
+			h.failed.Add(1)
+			return
+		}
+		h.passed.Add(1)
+		state.clearance.Store(passed)
+	})
+	return state.clearance.Load(), err
+}
+
+// Stats returns the number of hosts a challenge was detected for,
+// passed and could not be passed
+func (h *Handler) Stats() (detected, passed, failed int64) {
+	if h == nil {
+		return 0, 0, 0
+	}
+	return h.detected.Load(), h.passed.Load(), h.failed.Load()
+}
+
+func (h *Handler) state(host string) *hostState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.hosts[host]
+	if !ok {
+		state = &hostState{}
+		h.hosts[host] = state
+	}
+	return state
+}
@@ -0,0 +1,60 @@
+package challenge
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	handler, err := New([]string{`(?i)please wait while we verify`})
+	require.Nil(t, err, "could not create handler")
+
+	name, detected := handler.Detect("HTTP/1.1 403 Forbidden\r\ncf-mitigated: challenge\r\n", "")
+	require.True(t, detected, "could not detect challenge header")
+	require.Equal(t, "cloudflare", name)
+
+	name, detected = handler.Detect("", "<html><title>Just a moment...</title></html>")
+	require.True(t, detected, "could not detect challenge page")
+	require.Equal(t, "cloudflare", name)
+
+	name, detected = handler.Detect("", "Please wait while we verify your browser")
+	require.True(t, detected, "could not detect custom challenge")
+	require.Equal(t, "custom", name)
+
+	_, detected = handler.Detect("HTTP/1.1 200 OK\r\n", "<html>welcome</html>")
+	require.False(t, detected, "detected challenge in regular page")
+
+	_, err = New([]string{"("})
+	require.NotNil(t, err, "could create handler with invalid pattern")
+}
+
+func TestPass(t *testing.T) {
+	handler, err := New(nil)
+	require.Nil(t, err, "could not create handler")
+
+	require.True(t, handler.MarkDetected("example.com"))
+	require.False(t, handler.MarkDetected("example.com"), "challenge was detected twice for host")
+	require.True(t, handler.MarkDetected("failed.com"))
+
+	var attempts int
+	pass := func() (*Clearance, error) {
+		attempts++
+		return &Clearance{Cookies: []*http.Cookie{{Name: "clearance", Value: "1"}}}, nil
+	}
+	clearance, err := handler.Pass("example.com", pass)
+	require.Nil(t, err, "could not pass challenge")
+	require.NotNil(t, clearance, "could not get clearance")
+	_, _ = handler.Pass("example.com", pass)
+	require.Equal(t, 1, attempts, "challenge was passed twice for host")
+	require.Equal(t, clearance, handler.Clearance("example.com"))
+
+	_, err = handler.Pass("failed.com", func() (*Clearance, error) { return nil, errors.New("timeout") })
+	require.NotNil(t, err, "could not get error of failed attempt")
+	require.Nil(t, handler.Clearance("failed.com"), "got clearance of failed attempt")
+
+	detected, passed, failed := handler.Stats()
+	require.Equal(t, []int64{2, 1, 1}, []int64{detected, passed, failed})
+}
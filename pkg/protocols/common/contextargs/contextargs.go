@@ -165,6 +165,17 @@ func (ctx *Context) HasArgs() bool {
 	return !ctx.args.IsEmpty()
 }
 
+// WithContext returns a shallow copy of the context using given context.Context.
+// Unlike Clone, the input, args and cookie jar are shared with the original.
+func (ctx *Context) WithContext(c context.Context) *Context {
+	return &Context{
+		ctx:       c,
+		MetaInput: ctx.MetaInput,
+		args:      ctx.args,
+		CookieJar: ctx.CookieJar,
+	}
+}
+
 func (ctx *Context) Clone() *Context {
 	newCtx := &Context{
 		ctx:       ctx.ctx,
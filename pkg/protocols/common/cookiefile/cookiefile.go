@@ -0,0 +1,297 @@
+// Package cookiefile implements a cookie jar loaded from a netscape
+// (cookies.txt) or json cookie file.
+//
+// Cookies of the jar are scoped by domain, path and secure attributes
+// and applied to matching requests. The jar can optionally be updated
+// by cookies set by responses for the duration of the scan.
+package cookiefile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// httpOnlyPrefix is the prefix of netscape cookie file lines of httponly cookies
+const httpOnlyPrefix = "#HttpOnly_"
+
+// entry is a cookie of the jar
+type entry struct {
+	name     string
+	value    string
+	domain   string
+	path     string
+	hostOnly bool
+	secure   bool
+	expires  time.Time
+}
+
+// Jar is a cookie jar loaded from a cookie file
+type Jar struct {
+	mu      sync.RWMutex
+	entries []*entry
+}
+
+// jsonCookie is a cookie of a json cookie file as exported by browsers
+type jsonCookie struct {
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Domain         string  `json:"domain"`
+	Path           string  `json:"path"`
+	HostOnly       *bool   `json:"hostOnly"`
+	Secure         bool    `json:"secure"`
+	Expires        float64 `json:"expires"`
+	ExpirationDate float64 `json:"expirationDate"`
+}
+
+// New loads a cookie jar from a netscape or json cookie file.
+// The format is detected from the contents of the file.
+func New(file string) (*Jar, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read cookie file")
+	}
+	jar := &Jar{}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		err = jar.loadJSON(trimmed)
+	} else {
+		err = jar.loadNetscape(data)
+	}
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse cookie file")
+	}
+	return jar, nil
+}
+
+func (j *Jar) loadJSON(data []byte) error {
+	var cookies []jsonCookie
+	if data[0] == '{' {
+		// single cookie object
+		var cookie jsonCookie
+		if err := json.Unmarshal(data, &cookie); err != nil {
+			return err
+		}
+		cookies = append(cookies, cookie)
+	} else if err := json.Unmarshal(data, &cookies); err != nil {
+		return err
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == "" || cookie.Domain == "" {
+			return errorutil.New("cookie %q has no name or domain", cookie.Name)
+		}
+		e := &entry{
+			name:     cookie.Name,
+			value:    cookie.Value,
+			domain:   normalizeDomain(cookie.Domain),
+			path:     cookie.Path,
+			hostOnly: !strings.HasPrefix(cookie.Domain, "."),
+			secure:   cookie.Secure,
+		}
+		if cookie.HostOnly != nil {
+			e.hostOnly = *cookie.HostOnly
+		}
+		expires := cookie.Expires
+		if expires == 0 {
+			expires = cookie.ExpirationDate
+		}
+		if expires > 0 {
+			e.expires = time.Unix(int64(expires), 0)
+		}
+		j.set(e)
+	}
+	return nil
+}
+
+func (j *Jar) loadNetscape(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		// httponly cookies are prefixed to not be treated as comments
+		text = strings.TrimPrefix(text, httpOnlyPrefix)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		// domain, include subdomains, path, secure, expiry, name, value
+		fields := strings.Split(text, "\t")
+		if len(fields) < 6 {
+			return errorutil.New("invalid cookie at line %d", line)
+		}
+		if len(fields) == 6 {
+			fields = append(fields, "")
+		}
+		e := &entry{
+			name:     fields[5],
+			value:    fields[6],
+			domain:   normalizeDomain(fields[0]),
+			path:     fields[2],
+			hostOnly: !strings.EqualFold(fields[1], "TRUE"),
+			secure:   strings.EqualFold(fields[3], "TRUE"),
+		}
+		if expiry, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expiry > 0 {
+			e.expires = time.Unix(expiry, 0)
+		}
+		j.set(e)
+	}
+	return scanner.Err()
+}
+
+// Len returns the number of cookies in the jar
+func (j *Jar) Len() int {
+	if j == nil {
+		return 0
+	}
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return len(j.entries)
+}
+
+// Cookies returns the unexpired cookies of the jar matching the url,
+// cookies with longer paths being returned first.
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	if j == nil || u == nil {
+		return nil
+	}
+	host := normalizeDomain(u.Hostname())
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	now := time.Now()
+
+	j.mu.RLock()
+	var matched []*entry
+	for _, e := range j.entries {
+		if !e.expires.IsZero() && !e.expires.After(now) {
+			continue
+		}
+		if e.secure && !secure {
+			continue
+		}
+		if !e.domainMatch(host) || !pathMatch(path, e.path) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	j.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, k int) bool {
+		return len(matched[i].path) > len(matched[k].path)
+	})
+	cookies := make([]*http.Cookie, 0, len(matched))
+	for _, e := range matched {
+		cookies = append(cookies, &http.Cookie{Name: e.name, Value: e.value})
+	}
+	return cookies
+}
+
+// SetCookies updates the jar with cookies set by a response for the url.
+// Cookies for domains not matching the url are ignored while expired
+// cookies are removed from the jar.
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if j == nil || u == nil {
+		return
+	}
+	host := normalizeDomain(u.Hostname())
+	now := time.Now()
+	for _, cookie := range cookies {
+		e := &entry{
+			name:     cookie.Name,
+			value:    cookie.Value,
+			domain:   host,
+			path:     cookie.Path,
+			hostOnly: true,
+			secure:   cookie.Secure,
+		}
+		if cookie.Domain != "" {
+			domain := normalizeDomain(cookie.Domain)
+			if domain != host && !strings.HasSuffix(host, "."+domain) {
+				continue
+			}
+			e.domain = domain
+			e.hostOnly = false
+		}
+		if e.path == "" || !strings.HasPrefix(e.path, "/") {
+			e.path = defaultPath(u.EscapedPath())
+		}
+		switch {
+		case cookie.MaxAge < 0:
+			e.expires = now
+		case cookie.MaxAge > 0:
+			e.expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		case !cookie.Expires.IsZero():
+			e.expires = cookie.Expires
+		}
+		j.mu.Lock()
+		j.set(e)
+		j.mu.Unlock()
+	}
+}
+
+// set adds or replaces a cookie with the same name, domain and path.
+// expired cookies remove the cookie they replace.
+func (j *Jar) set(e *entry) {
+	if e.path == "" {
+		e.path = "/"
+	}
+	expired := !e.expires.IsZero() && !e.expires.After(time.Now())
+	for i, existing := range j.entries {
+		if existing.name == e.name && existing.domain == e.domain && existing.path == e.path {
+			if expired {
+				j.entries = append(j.entries[:i], j.entries[i+1:]...)
+			} else {
+				j.entries[i] = e
+			}
+			return
+		}
+	}
+	if !expired {
+		j.entries = append(j.entries, e)
+	}
+}
+
+// domainMatch returns true if the cookie should be sent to host
+func (e *entry) domainMatch(host string) bool {
+	if host == e.domain {
+		return true
+	}
+	return !e.hostOnly && strings.HasSuffix(host, "."+e.domain)
+}
+
+// pathMatch returns true if the request path matches the cookie path (RFC 6265 5.1.4)
+func pathMatch(requestPath, cookiePath string) bool {
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+// defaultPath returns the default cookie path of a request path (RFC 6265 5.1.4)
+func defaultPath(requestPath string) string {
+	if requestPath == "" || requestPath[0] != '/' {
+		return "/"
+	}
+	index := strings.LastIndex(requestPath, "/")
+	if index == 0 {
+		return "/"
+	}
+	return requestPath[:index]
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+}
@@ -0,0 +1,97 @@
+package cookiefile
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCookieFile(t *testing.T, name, contents string) string {
+	file := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(file, []byte(contents), 0644), "could not write cookie file")
+	return file
+}
+
+func cookieNames(cookies []*http.Cookie) []string {
+	names := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		names = append(names, cookie.Name)
+	}
+	return names
+}
+
+func TestNetscapeCookieFile(t *testing.T) {
+	file := writeCookieFile(t, "cookies.txt", `# Netscape HTTP Cookie File
+.example.com	TRUE	/	FALSE	0	session	abc
+example.com	FALSE	/admin	TRUE	0	admin	xyz
+#HttpOnly_api.example.com	FALSE	/	FALSE	0	token	123
+example.com	FALSE	/	FALSE	1	expired	old
+`)
+	jar, err := New(file)
+	require.Nil(t, err, "could not load cookie file")
+	require.Equal(t, 3, jar.Len(), "could not load cookies")
+
+	u, _ := url.Parse("https://example.com/admin/users")
+	require.Equal(t, []string{"admin", "session"}, cookieNames(jar.Cookies(u)), "could not match cookies")
+
+	u, _ = url.Parse("http://example.com/admin")
+	require.Equal(t, []string{"session"}, cookieNames(jar.Cookies(u)), "matched secure cookie over http")
+
+	u, _ = url.Parse("https://example.com/administrator")
+	require.Equal(t, []string{"session"}, cookieNames(jar.Cookies(u)), "matched cookie path prefix")
+
+	u, _ = url.Parse("https://api.example.com/")
+	require.Equal(t, []string{"session", "token"}, cookieNames(jar.Cookies(u)), "could not match subdomain cookies")
+
+	u, _ = url.Parse("https://www.example.com/")
+	require.Equal(t, []string{"session"}, cookieNames(jar.Cookies(u)), "matched host only cookie")
+
+	u, _ = url.Parse("https://notexample.com/")
+	require.Empty(t, jar.Cookies(u), "matched cookie of another domain")
+}
+
+func TestJSONCookieFile(t *testing.T) {
+	file := writeCookieFile(t, "cookies.json", `[
+		{"name": "session", "value": "abc", "domain": ".example.com", "path": "/"},
+		{"name": "pref", "value": "dark", "domain": "example.com", "hostOnly": true, "expirationDate": 4102444800}
+	]`)
+	jar, err := New(file)
+	require.Nil(t, err, "could not load cookie file")
+
+	u, _ := url.Parse("https://example.com/")
+	cookies := jar.Cookies(u)
+	require.ElementsMatch(t, []string{"session", "pref"}, cookieNames(cookies), "could not match cookies")
+
+	u, _ = url.Parse("https://sub.example.com/")
+	require.Equal(t, []string{"session"}, cookieNames(jar.Cookies(u)), "matched host only cookie")
+
+	_, err = New(writeCookieFile(t, "invalid.json", `[{"name": "session"}]`))
+	require.NotNil(t, err, "loaded cookie without domain")
+}
+
+func TestSetCookies(t *testing.T) {
+	jar, err := New(writeCookieFile(t, "cookies.txt", ".example.com\tTRUE\t/\tFALSE\t0\tsession\tabc\n"))
+	require.Nil(t, err, "could not load cookie file")
+
+	u, _ := url.Parse("https://example.com/app/login")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "updated", Domain: "example.com", Path: "/"},
+		{Name: "csrf", Value: "token"},
+		{Name: "other", Value: "value", Domain: "other.com"},
+	})
+
+	u, _ = url.Parse("https://example.com/app/home")
+	cookies := jar.Cookies(u)
+	require.Equal(t, []string{"csrf", "session"}, cookieNames(cookies), "could not update cookies")
+	require.Equal(t, "updated", cookies[1].Value, "could not update cookie value")
+
+	u, _ = url.Parse("https://example.com/")
+	require.Equal(t, []string{"session"}, cookieNames(jar.Cookies(u)), "could not scope cookie to default path")
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Domain: "example.com", Path: "/", MaxAge: -1}})
+	require.Empty(t, jar.Cookies(u), "could not remove expired cookie")
+}
@@ -3,6 +3,9 @@
 package generators
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
@@ -15,6 +18,13 @@ type PayloadGenerator struct {
 	catalog  catalog.Catalog
 	payloads map[string][]string
 	options  *types.Options
+
+	// remote are the sources of remote payloads keyed by name
+	remote map[string]string
+	// remotePayloads are the values of remote payloads once loaded
+	remotePayloads map[string][]string
+	remoteOnce     sync.Once
+	remoteLoaded   atomic.Bool
 }
 
 // New creates a new generator structure for payload generation
@@ -66,13 +76,38 @@ type Iterator struct {
 	payloads    []*payloadIterator
 }
 
-// NewIterator creates a new iterator for the payloads generator
+// NewIterator creates a new iterator for the payloads generator. Remote
+// payloads are fetched on the first call.
 func (g *PayloadGenerator) NewIterator() *Iterator {
+	g.loadRemotePayloads()
+	return g.newIterator(g.remotePayloads)
+}
+
+// Total returns the amount of input combinations of the generator without
+// fetching remote payloads, each remote payload not loaded yet being counted
+// as a single value.
+func (g *PayloadGenerator) Total() int {
+	if g.remoteLoaded.Load() {
+		return g.newIterator(g.remotePayloads).Total()
+	}
+	placeholders := make(map[string][]string, len(g.remote))
+	for name := range g.remote {
+		placeholders[name] = []string{""}
+	}
+	return g.newIterator(placeholders).Total()
+}
+
+// newIterator creates a new iterator for the payloads of the generator and
+// the remote payloads
+func (g *PayloadGenerator) newIterator(remote map[string][]string) *Iterator {
 	var payloads []*payloadIterator
 
 	for name, values := range g.payloads {
 		payloads = append(payloads, &payloadIterator{name: name, values: values})
 	}
+	for name, values := range remote {
+		payloads = append(payloads, &payloadIterator{name: name, values: values})
+	}
 	iterator := &Iterator{
 		Type:     g.Type,
 		payloads: payloads,
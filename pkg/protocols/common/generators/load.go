@@ -21,6 +21,11 @@ func (generator *PayloadGenerator) loadPayloads(payloads map[string]interface{},
 			//golint:gomnd // this is not a magic number
 			if len(elements) >= 2 {
 				loadedPayloads[name] = elements
+			} else if IsRemotePayload(pt) {
+				// remote payloads are fetched when the generator is first iterated
+				if err := generator.addRemotePayload(name, strings.TrimSpace(pt)); err != nil {
+					return nil, errors.Wrap(err, "could not load remote payload")
+				}
 			} else {
 				file, err := generator.options.LoadHelperFile(pt, templatePath, generator.catalog)
 				if err != nil {
@@ -0,0 +1,282 @@
+package generators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// remotePayloadSchemes are the schemes of payload sources fetched remotely
+var remotePayloadSchemes = []string{"http://", "https://", "s3://", "gs://"}
+
+const (
+	// defaultRemotePayloadExpiry is the duration cached remote payloads are used
+	// without revalidation if no expiry is configured
+	defaultRemotePayloadExpiry = 24 * time.Hour
+	// remotePayloadFetchTimeout is the timeout for fetching a remote payload
+	remotePayloadFetchTimeout = time.Minute
+)
+
+// remotePayloadMutex serializes fetches of remote payloads so that templates
+// sharing a wordlist fetch it once
+var remotePayloadMutex sync.Mutex
+
+// errNotModified is returned by fetchers when the cached payload is up to date
+var errNotModified = errors.New("not modified")
+
+// remotePayloadMetadata is the metadata of a cached remote payload
+type remotePayloadMetadata struct {
+	Source  string    `json:"source"`
+	ETag    string    `json:"etag,omitempty"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// IsRemotePayload returns true if payload is a remote payload source url
+func IsRemotePayload(payload interface{}) bool {
+	value, ok := payload.(string)
+	if !ok || strings.Contains(value, "\n") {
+		return false
+	}
+	lower := strings.ToLower(strings.TrimSpace(value))
+	for _, scheme := range remotePayloadSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// addRemotePayload adds the remote payload source loaded when the generator
+// is first iterated so that no fetch happens for templates never executed
+func (generator *PayloadGenerator) addRemotePayload(name, source string) error {
+	if generator.options == nil || !generator.options.AllowRemotePayloads {
+		return errors.Errorf("remote payload %s is not allowed, use -allow-remote-payloads to enable", source)
+	}
+	if generator.remote == nil {
+		generator.remote = make(map[string]string)
+	}
+	generator.remote[name] = source
+	return nil
+}
+
+// loadRemotePayloads fetches the remote payloads of the generator once.
+// Remote payloads which can't be loaded have no values so that no requests
+// are generated for them.
+func (generator *PayloadGenerator) loadRemotePayloads() {
+	generator.remoteOnce.Do(func() {
+		defer generator.remoteLoaded.Store(true)
+		if len(generator.remote) == 0 {
+			return
+		}
+		generator.remotePayloads = make(map[string][]string, len(generator.remote))
+		for name, source := range generator.remote {
+			file, err := generator.loadRemotePayload(source)
+			if err != nil {
+				gologger.Error().Msgf("Could not load remote payload %s: %s\n", source, err)
+				generator.remotePayloads[name] = nil
+				continue
+			}
+			values, err := generator.loadPayloadsFromFile(file)
+			if err != nil {
+				gologger.Error().Msgf("Could not read remote payload %s: %s\n", source, err)
+			}
+			generator.remotePayloads[name] = values
+		}
+	})
+}
+
+// loadRemotePayload returns the locally cached copy of the remote payload source
+// fetching it if it is not cached or the cached copy has expired and changed.
+// The expired cached copy is used if the source can't be fetched.
+func (generator *PayloadGenerator) loadRemotePayload(source string) (io.ReadCloser, error) {
+	options := generator.options
+	cacheDir := options.RemotePayloadCacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(config.DefaultConfig.GetCacheDir(), "payloads")
+	}
+	expiry := options.RemotePayloadCacheExpiry
+	if expiry <= 0 {
+		expiry = defaultRemotePayloadExpiry
+	}
+
+	remotePayloadMutex.Lock()
+	defer remotePayloadMutex.Unlock()
+
+	hash := sha256.Sum256([]byte(source))
+	name := hex.EncodeToString(hash[:])
+	dataPath := filepath.Join(cacheDir, name+".txt")
+	metadataPath := filepath.Join(cacheDir, name+".json")
+
+	var metadata remotePayloadMetadata
+	cached := false
+	if data, err := os.ReadFile(metadataPath); err == nil && json.Unmarshal(data, &metadata) == nil {
+		_, statErr := os.Stat(dataPath)
+		cached = statErr == nil && metadata.Source == source
+	}
+	if cached && time.Since(metadata.Fetched) < expiry {
+		return os.Open(dataPath)
+	}
+	if !cached {
+		metadata = remotePayloadMetadata{Source: source}
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create remote payload cache directory")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remotePayloadFetchTimeout)
+	defer cancel()
+
+	temp, err := os.CreateTemp(cacheDir, name+"-*.tmp")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create remote payload cache file")
+	}
+	defer os.Remove(temp.Name())
+
+	etag, err := fetchRemotePayload(ctx, source, metadata.ETag, options, temp)
+	_ = temp.Close()
+	switch {
+	case errors.Is(err, errNotModified):
+	case err != nil && cached:
+		gologger.Warning().Msgf("Could not fetch remote payload %s, using cached copy fetched at %s: %s\n", source, metadata.Fetched.Format(time.RFC3339), err)
+		return os.Open(dataPath)
+	case err != nil:
+		return nil, errors.Wrapf(err, "could not fetch remote payload %s", source)
+	default:
+		if err := os.Rename(temp.Name(), dataPath); err != nil {
+			return nil, errors.Wrap(err, "could not write remote payload cache file")
+		}
+		metadata.ETag = etag
+	}
+	metadata.Fetched = time.Now()
+	if data, err := json.Marshal(metadata); err == nil {
+		_ = os.WriteFile(metadataPath, data, 0644)
+	}
+	return os.Open(dataPath)
+}
+
+// newRemotePayloadTransport returns the transport fetching remote payloads
+// through the http or socks5 proxy of the scan (if any)
+func newRemotePayloadTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy := types.ProxyURL
+	if proxy == "" {
+		proxy = types.ProxySocksURL
+	}
+	if proxy != "" {
+		if proxyURL, err := url.Parse(proxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	return transport
+}
+
+// fetchRemotePayload writes the remote payload source to writer returning its etag.
+// errNotModified is returned if the source matches etag.
+func fetchRemotePayload(ctx context.Context, source, etag string, options *types.Options, writer io.Writer) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(source))
+	if err != nil {
+		return "", err
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "s3":
+		return fetchS3Payload(ctx, parsed.Host, strings.TrimPrefix(parsed.Path, "/"), etag, options, writer)
+	case "gs":
+		// objects of google cloud storage are fetched using the xml api
+		parsed = &url.URL{Scheme: "https", Host: "storage.googleapis.com", Path: "/" + parsed.Host + parsed.Path}
+	}
+	return fetchHTTPPayload(ctx, parsed.String(), etag, options, writer)
+}
+
+// fetchHTTPPayload fetches a payload over http using a conditional request
+// sending the custom headers of the scan
+func fetchHTTPPayload(ctx context.Context, source, etag string, options *types.Options, writer io.Writer) (string, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, header := range options.CustomHeaders {
+		if parts := strings.SplitN(header, ":", 2); len(parts) == 2 {
+			req.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		}
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	httpOpts := retryablehttp.DefaultOptionsSingle
+	httpOpts.Timeout = remotePayloadFetchTimeout
+	client := retryablehttp.NewWithHTTPClient(&http.Client{Transport: newRemotePayloadTransport(), Timeout: remotePayloadFetchTimeout}, httpOpts)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return "", errNotModified
+	default:
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	if _, err := io.Copy(writer, resp.Body); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// fetchS3Payload fetches a payload object of a s3 bucket using the configured
+// aws credentials or the default credential chain.
+func fetchS3Payload(ctx context.Context, bucket, key, etag string, options *types.Options, writer io.Writer) (string, error) {
+	var configOptions []func(*awsconfig.LoadOptions) error
+	if options.AwsAccessKey != "" && options.AwsSecretKey != "" {
+		configOptions = append(configOptions, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(options.AwsAccessKey, options.AwsSecretKey, "")))
+	}
+	if options.AwsRegion != "" {
+		configOptions = append(configOptions, awsconfig.WithRegion(options.AwsRegion))
+	}
+	configOptions = append(configOptions, awsconfig.WithHTTPClient(&http.Client{Transport: newRemotePayloadTransport()}))
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, configOptions...)
+	if err != nil {
+		return "", err
+	}
+	input := &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}
+	if etag != "" {
+		input.IfNoneMatch = aws.String(etag)
+	}
+	output, err := s3.NewFromConfig(cfg).GetObject(ctx, input)
+	if err != nil {
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotModified {
+			return "", errNotModified
+		}
+		return "", err
+	}
+	defer output.Body.Close()
+
+	if _, err := io.Copy(writer, output.Body); err != nil {
+		return "", err
+	}
+	return aws.ToString(output.ETag), nil
+}
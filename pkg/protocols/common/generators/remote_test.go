@@ -0,0 +1,81 @@
+package generators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRemotePayloads(t *testing.T) {
+	var fetched, notModified atomic.Int32
+	var unavailable atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wordlist.txt" || r.Header.Get("Authorization") != "token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if unavailable.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetched.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("admin\n\nroot\n"))
+	}))
+	defer ts.Close()
+
+	options := &types.Options{AllowRemotePayloads: true, RemotePayloadCacheDir: t.TempDir(), RemotePayloadCacheExpiry: time.Hour, CustomHeaders: []string{"Authorization: token"}}
+	source := ts.URL + "/wordlist.txt"
+	values := func() []string {
+		generator, err := New(map[string]interface{}{"user": source}, BatteringRamAttack, "", nil, "", options)
+		require.NoError(t, err, "could not create generator")
+		require.Equal(t, 1, generator.Total(), "could not count remote payload without fetching it")
+
+		var values []string
+		iterator := generator.NewIterator()
+		for {
+			value, ok := iterator.Value()
+			if !ok {
+				break
+			}
+			values = append(values, value["user"].(string))
+		}
+		return values
+	}
+
+	generator, err := New(map[string]interface{}{"user": source}, BatteringRamAttack, "", nil, "", options)
+	require.NoError(t, err, "could not create generator")
+	require.Zero(t, fetched.Load(), "fetched remote payload before iterating")
+	require.Equal(t, 2, generator.NewIterator().Total(), "could not fetch remote payload when iterating")
+	require.Equal(t, 2, generator.Total(), "could not count loaded remote payload")
+
+	require.Equal(t, []string{"admin", "root"}, values(), "could not get cached remote values")
+	require.Equal(t, int32(1), fetched.Load(), "could not use cached remote payload")
+
+	options.RemotePayloadCacheExpiry = time.Nanosecond
+	require.Equal(t, []string{"admin", "root"}, values(), "could not get revalidated values")
+	require.Equal(t, int32(1), notModified.Load(), "could not revalidate remote payload using etag")
+
+	unavailable.Store(true)
+	require.Equal(t, []string{"admin", "root"}, values(), "could not fall back to stale cached remote payload")
+
+	generator, err = New(map[string]interface{}{"user": ts.URL + "/missing.txt"}, BatteringRamAttack, "", nil, "", options)
+	require.NoError(t, err, "could not create generator")
+	require.Zero(t, generator.NewIterator().Total(), "generated values of missing remote payload")
+
+	options.AllowRemotePayloads = false
+	_, err = New(map[string]interface{}{"user": source}, BatteringRamAttack, "", nil, "", options)
+	require.Error(t, err, "could load remote payloads without allowing them")
+	require.True(t, IsRemotePayload("s3://bucket/wordlist.txt"), "could not detect s3 payload")
+	require.False(t, IsRemotePayload("payloads/wordlist.txt"), "detected local payload as remote")
+}
@@ -22,6 +22,11 @@ func (g *PayloadGenerator) validate(payloads map[string]interface{}, templatePat
 				return errors.New("invalid number of lines in payload")
 			}
 
+			// remote payloads are fetched when loading
+			if IsRemotePayload(payloadType) {
+				continue
+			}
+
 			// check if it's a file and try to load it
 			if fileutil.FileExists(payloadType) {
 				continue
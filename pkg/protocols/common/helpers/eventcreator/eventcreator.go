@@ -1,10 +1,14 @@
 package eventcreator
 
 import (
+	"strings"
+
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -29,6 +33,11 @@ func CreateEventWithAdditionalOptions(request protocols.Request, outputEvent out
 	for _, compiledOperator := range request.GetCompiledOperators() {
 		if compiledOperator != nil {
 			result, ok := compiledOperator.Execute(outputEvent, request.Match, request.Extract, isResponseDebug)
+			if result != nil && result.PartialMatch != nil {
+				event.OperatorsResult = result
+				event.Results = append(event.Results, MakePartialMatchEvent(request, event))
+				continue
+			}
 			if ok && result != nil {
 				// if result has both extracted values and dynamic values, put dynamic values in data
 				// and remove dynamic values to avoid skipping legitimate event
@@ -49,6 +58,25 @@ func CreateEventWithAdditionalOptions(request protocols.Request, outputEvent out
 	return event
 }
 
+// PartialMatchName is the matcher name of partial match events
+const PartialMatchName = "partial-match"
+
+// MakePartialMatchEvent returns the informational event listing the fired
+// and missed matchers of the partial match of the event
+func MakePartialMatchEvent(request protocols.Request, event *output.InternalWrappedEvent) *output.ResultEvent {
+	partial := event.OperatorsResult.PartialMatch
+	data := request.MakeResultEventItem(event)
+	data.MatcherName = PartialMatchName
+	data.Partial = true
+	data.Info.SeverityHolder = severity.Holder{Severity: severity.Info}
+	data.PartialMatch = partial
+	data.Metadata = generators.MergeMaps(data.Metadata, map[string]interface{}{
+		"matchers_fired":  strings.Join(partial.Fired, ","),
+		"matchers_missed": strings.Join(partial.Missed, ","),
+	})
+	return data
+}
+
 func CreateEventWithOperatorResults(request protocols.Request, internalEvent output.InternalEvent, operatorResult *operators.Result) *output.InternalWrappedEvent {
 	event := &output.InternalWrappedEvent{InternalEvent: internalEvent}
 	event.OperatorsResult = operatorResult
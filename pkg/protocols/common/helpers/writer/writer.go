@@ -17,6 +17,16 @@ func WriteResult(data *output.InternalWrappedEvent, output output.Writer, progre
 	}
 	var matched bool
 	for _, result := range data.Results {
+		// partial matches are informational and not reported as issues
+		if result.Partial {
+			if err := output.Write(result); err != nil {
+				gologger.Warning().Msgf("Could not write output event: %s\n", err)
+			}
+			continue
+		}
+		// annotations are applied before the issue is created so that
+		// trackers receive the same information as the output
+		annotate(output, result)
 		if issuesClient != nil {
 			if err := issuesClient.CreateIssue(result); err != nil {
 				gologger.Warning().Msgf("Could not create issue on tracker: %s", err)
@@ -32,3 +42,11 @@ func WriteResult(data *output.InternalWrappedEvent, output output.Writer, progre
 	}
 	return matched
 }
+
+// annotate applies the annotations of its template to the result if the
+// writer supports it
+func annotate(writer output.Writer, result *output.ResultEvent) {
+	if annotator, ok := writer.(output.EventAnnotator); ok {
+		annotator.Annotate(result)
+	}
+}
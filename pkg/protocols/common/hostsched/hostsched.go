@@ -0,0 +1,143 @@
+// Package hostsched implements host-aware scheduling of requests. Requests
+// to a host are spaced evenly over the rate limit duration so that requests
+// of concurrent templates targeting the same host are interleaved with
+// requests to other hosts instead of being sent to it in bursts.
+package hostsched
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// sweepInterval is the interval after which idle hosts are forgotten
+const sweepInterval = time.Minute
+
+// Scheduler schedules requests to hosts at a per-host rate. A nil
+// scheduler never delays requests.
+type Scheduler struct {
+	interval time.Duration
+
+	mutex     sync.Mutex
+	hosts     map[string]*hostState
+	lastSweep time.Time
+}
+
+// hostState is the scheduling state of a host
+type hostState struct {
+	// next is the time of the next free slot of the host
+	next time.Time
+	// waiting is the number of requests waiting for their slot
+	waiting int
+}
+
+// New creates a scheduler allowing limit requests per duration to each host
+func New(limit int, duration time.Duration) *Scheduler {
+	if duration <= 0 {
+		duration = time.Second
+	}
+	return &Scheduler{
+		interval:  duration / time.Duration(limit),
+		hosts:     make(map[string]*hostState),
+		lastSweep: time.Now(),
+	}
+}
+
+// NewFromOptions creates a scheduler from the options of a scan or
+// returns nil if no per-host rate limit is configured
+func NewFromOptions(options *types.Options) *Scheduler {
+	if options.HostRateLimit <= 0 {
+		return nil
+	}
+	return New(options.HostRateLimit, options.RateLimitDuration)
+}
+
+// Take blocks until the next slot of the host of input. Slots are reserved
+// in order so that concurrent requests to a host are sent one interval apart.
+func (s *Scheduler) Take(ctx context.Context, input string) {
+	if s == nil {
+		return
+	}
+	host := hostOf(input)
+
+	s.mutex.Lock()
+	now := time.Now()
+	s.sweep(now)
+	state, ok := s.hosts[host]
+	if !ok {
+		state = &hostState{}
+		s.hosts[host] = state
+	}
+	slot := state.next
+	if slot.Before(now) {
+		slot = now
+	}
+	state.next = slot.Add(s.interval)
+	state.waiting++
+	s.mutex.Unlock()
+
+	var cancelled bool
+	if wait := time.Until(slot); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			cancelled = true
+		case <-timer.C:
+		}
+	}
+
+	s.mutex.Lock()
+	state.waiting--
+	// release the slot of a cancelled request if it is the last one reserved
+	if cancelled && state.next.Equal(slot.Add(s.interval)) {
+		state.next = slot
+	}
+	s.mutex.Unlock()
+}
+
+// QueueDepths returns the number of requests waiting for their slot by host
+func (s *Scheduler) QueueDepths() map[string]int {
+	depths := make(map[string]int)
+	if s == nil {
+		return depths
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for host, state := range s.hosts {
+		if state.waiting > 0 {
+			depths[host] = state.waiting
+		}
+	}
+	return depths
+}
+
+// sweep forgets hosts without waiting requests whose slots have passed
+func (s *Scheduler) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for host, state := range s.hosts {
+		if state.waiting == 0 && state.next.Before(now) {
+			delete(s.hosts, host)
+		}
+	}
+}
+
+// hostOf returns the lowercased host of the input
+func hostOf(input string) string {
+	if parsed, err := urlutil.Parse(input); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(input); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(input)
+}
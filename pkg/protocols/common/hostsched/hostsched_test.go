@@ -0,0 +1,57 @@
+package hostsched
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerSpacesHostRequests(t *testing.T) {
+	// 10 requests per second to a host are spaced 100ms apart
+	scheduler := New(10, time.Second)
+	ctx := context.Background()
+
+	start := time.Now()
+	scheduler.Take(ctx, "https://example.com/a")
+	scheduler.Take(ctx, "http://other.com")
+	require.Less(t, time.Since(start), 50*time.Millisecond, "first requests to hosts were delayed")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.Take(ctx, "example.com:443")
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return scheduler.QueueDepths()["example.com"] == 3
+	}, time.Second, 5*time.Millisecond, "could not get queue depth of host")
+
+	// other hosts are interleaved with the queued requests
+	otherStart := time.Now()
+	scheduler.Take(ctx, "https://third.com")
+	require.Less(t, time.Since(otherStart), 50*time.Millisecond, "request to other host was delayed")
+
+	wg.Wait()
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond, "requests to host were not spaced")
+	require.Empty(t, scheduler.QueueDepths(), "queue was not drained")
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	scheduler := New(1, time.Hour)
+	scheduler.Take(context.Background(), "https://example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	scheduler.Take(ctx, "https://example.com")
+	require.Less(t, time.Since(start), time.Second, "take was not cancelled")
+
+	var nilScheduler *Scheduler
+	nilScheduler.Take(context.Background(), "https://example.com")
+	require.Empty(t, nilScheduler.QueueDepths(), "nil scheduler has queues")
+}
@@ -0,0 +1,145 @@
+// Package inputvars implements per-input variables loaded from a csv or
+// json sidecar file keyed by target.
+//
+// Per-input variables are merged with variables passed using cli options
+// (-var) for the input they belong to and take precedence over them.
+// Template variables can reference them while payloads and values
+// extracted at runtime take precedence over them.
+package inputvars
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// targetColumns are the names of csv columns containing the target
+var targetColumns = []string{"target", "input", "host", "url"}
+
+// Store contains variables of inputs keyed by target
+type Store struct {
+	vars map[string]map[string]interface{}
+}
+
+// New loads per-input variables from a csv or json file.
+//
+// Json files contain an object mapping targets to objects of variables.
+// Csv files contain a header row naming the variables and a target column
+// (target, input, host or url), the first column being used if none exists.
+func New(file string) (*Store, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not open input vars file")
+	}
+	defer f.Close()
+
+	store := &Store{vars: make(map[string]map[string]interface{})}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = store.loadJSON(f)
+	case ".csv":
+		err = store.loadCSV(f)
+	default:
+		return nil, errorutil.New("unsupported input vars file format %s (supported: csv, json)", filepath.Ext(file))
+	}
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse input vars file")
+	}
+	return store, nil
+}
+
+func (s *Store) loadJSON(reader io.Reader) error {
+	var data map[string]map[string]interface{}
+	if err := json.NewDecoder(reader).Decode(&data); err != nil {
+		return err
+	}
+	for target, vars := range data {
+		s.add(target, vars)
+	}
+	return nil
+}
+
+func (s *Store) loadCSV(reader io.Reader) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	header := records[0]
+	targetIndex := 0
+	for i, column := range header {
+		if isTargetColumn(column) {
+			targetIndex = i
+			break
+		}
+	}
+	for _, record := range records[1:] {
+		vars := make(map[string]interface{}, len(header)-1)
+		for i, value := range record {
+			if i == targetIndex || i >= len(header) {
+				continue
+			}
+			vars[strings.TrimSpace(header[i])] = value
+		}
+		s.add(record[targetIndex], vars)
+	}
+	return nil
+}
+
+func isTargetColumn(column string) bool {
+	column = strings.ToLower(strings.TrimSpace(column))
+	for _, name := range targetColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) add(target string, vars map[string]interface{}) {
+	target = strings.TrimSpace(target)
+	if target == "" || len(vars) == 0 {
+		return
+	}
+	s.vars[target] = vars
+}
+
+// Get returns variables of input or nil if the input has none. Inputs are
+// looked up as is and then by their host (with and without port).
+func (s *Store) Get(input string) map[string]interface{} {
+	if s == nil || len(s.vars) == 0 {
+		return nil
+	}
+	if vars, ok := s.vars[input]; ok {
+		return vars
+	}
+	parsed, err := urlutil.Parse(input)
+	if err != nil {
+		return nil
+	}
+	if vars, ok := s.vars[parsed.Host]; ok {
+		return vars
+	}
+	if vars, ok := s.vars[parsed.Hostname()]; ok {
+		return vars
+	}
+	return nil
+}
+
+// Len returns the number of inputs with variables
+func (s *Store) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.vars)
+}
@@ -0,0 +1,39 @@
+package inputvars
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("csv", func(t *testing.T) {
+		file := filepath.Join(dir, "vars.csv")
+		err := os.WriteFile(file, []byte("token,target,user\nabc,https://example.com,admin\nxyz,test.com:8443,guest\n"), 0644)
+		require.Nil(t, err, "could not write csv file")
+
+		store, err := New(file)
+		require.Nil(t, err, "could not load csv file")
+		require.Equal(t, 2, store.Len())
+		require.Equal(t, map[string]interface{}{"token": "abc", "user": "admin"}, store.Get("https://example.com"))
+		require.Equal(t, "xyz", store.Get("https://test.com:8443/path")["token"], "could not lookup by host")
+		require.Nil(t, store.Get("https://unknown.com"))
+	})
+	t.Run("json", func(t *testing.T) {
+		file := filepath.Join(dir, "vars.json")
+		err := os.WriteFile(file, []byte(`{"example.com": {"token": "abc"}}`), 0644)
+		require.Nil(t, err, "could not write json file")
+
+		store, err := New(file)
+		require.Nil(t, err, "could not load json file")
+		require.Equal(t, "abc", store.Get("https://example.com:443/login")["token"], "could not lookup by hostname")
+	})
+	t.Run("nil", func(t *testing.T) {
+		var store *Store
+		require.Nil(t, store.Get("example.com"))
+	})
+}
@@ -2,6 +2,7 @@ package interactsh
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"regexp"
@@ -21,8 +22,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/tracing"
 	errorutil "github.com/projectdiscovery/utils/errors"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 	stringsutil "github.com/projectdiscovery/utils/strings"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client is a wrapped client for interactsh server.
@@ -52,10 +58,34 @@ type Client struct {
 	// determines if wait the cooldown period in case of generated URL
 	generated atomic.Bool
 	matched   atomic.Bool
+
+	// received and registered count the interactions received and the
+	// correlation ids registered since the last backoff of polling
+	received   atomic.Int64
+	registered atomic.Int64
+	// closing is set once the scan ended, late and lateMatched count the
+	// interactions received after it
+	closing     atomic.Bool
+	late        atomic.Int64
+	lateMatched atomic.Int64
+	// pollCallback processes the polled interactions
+	pollCallback client.InteractionCallback
+	// stopBackoff stops the backoff of polling and backoffDone is closed
+	// once stopped, setting currentPollDuration to the last poll interval
+	stopBackoff         chan struct{}
+	backoffDone         chan struct{}
+	currentPollDuration time.Duration
 }
 
 // New returns a new interactsh server client
 func New(options *Options) (*Client, error) {
+	if options.Proxy != "" {
+		httpClient, err := newProxyHTTPClient(options.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		options.HTTPClient = httpClient
+	}
 	requestsCache := gcache.New[string, *RequestData](options.CacheSize).LRU().Build()
 	interactionsCache := gcache.New[string, []*server.Interaction](defaultMaxInteractionsCount).LRU().Build()
 	matchedTemplateCache := gcache.New[string, bool](defaultMaxInteractionsCount).LRU().Build()
@@ -87,6 +117,11 @@ func (c *Client) poll() error {
 		KeepAliveInterval:   time.Minute,
 	})
 	if err != nil {
+		if c.options.Proxy != "" {
+			// registration failures via proxy are otherwise only visible as missing oast results
+			gologger.Error().Msgf("Could not reach interactsh server %s via proxy %s: %s", c.options.ServerURL, redactedProxy(c.options.Proxy), err)
+			return errorutil.NewWithErr(err).Msgf("could not create client via proxy %s", redactedProxy(c.options.Proxy))
+		}
 		return errorutil.NewWithErr(err).Msgf("could not create client")
 	}
 
@@ -94,11 +129,21 @@ func (c *Client) poll() error {
 
 	interactURL := interactsh.URL()
 	interactDomain := interactURL[strings.Index(interactURL, ".")+1:]
-	gologger.Info().Msgf("Using Interactsh Server: %s", interactDomain)
+	if c.options.Proxy != "" {
+		gologger.Info().Msgf("Using Interactsh Server: %s (via proxy %s)", interactDomain, redactedProxy(c.options.Proxy))
+	} else {
+		gologger.Info().Msgf("Using Interactsh Server: %s", interactDomain)
+	}
 
 	c.setHostname(interactDomain)
 
-	err = interactsh.StartPolling(c.pollDuration, func(interaction *server.Interaction) {
+	c.pollCallback = func(interaction *server.Interaction) {
+		c.received.Add(1)
+		late := c.closing.Load()
+		if late {
+			c.late.Add(1)
+		}
+
 		request, err := c.requests.Get(interaction.UniqueID)
 		// for more context in github actions
 		if strings.EqualFold(os.Getenv("GITHUB_ACTIONS"), "true") && c.options.Debug {
@@ -123,15 +168,98 @@ func (c *Client) poll() error {
 			}
 		}
 
-		_ = c.processInteractionForRequest(interaction, request)
-	})
+		span := c.startInteractionSpan(interaction, request)
+		matched := c.processInteractionForRequest(interaction, request)
+		tracing.SetAttributes(span, attribute.Bool("matched", matched))
+		tracing.End(span, nil)
+		if late && matched {
+			c.lateMatched.Add(1)
+		}
+	}
 
-	if err != nil {
+	if err := interactsh.StartPolling(c.pollDuration, c.pollCallback); err != nil {
 		return errorutil.NewWithErr(err).Msgf("could not perform interactsh polling")
 	}
+	c.currentPollDuration = c.pollDuration
+	if c.options.MaxPollDuration > c.pollDuration {
+		c.stopBackoff = make(chan struct{})
+		c.backoffDone = make(chan struct{})
+		go c.backoffPolling()
+	}
 	return nil
 }
 
+// backoffPolling doubles the poll interval up to the maximum poll duration
+// while neither interactions are received nor a batch of correlation ids is
+// registered, resetting it to the poll duration otherwise.
+func (c *Client) backoffPolling() {
+	defer close(c.backoffDone)
+
+	interval := c.pollDuration
+	for {
+		select {
+		case <-c.stopBackoff:
+			c.currentPollDuration = interval
+			return
+		case <-time.After(interval):
+		}
+
+		next := min(interval*2, c.options.MaxPollDuration)
+		if c.received.Swap(0) > 0 || c.registered.Swap(0) >= int64(max(c.options.BatchSize, 1)) {
+			next = c.pollDuration
+		}
+		if next == interval {
+			continue
+		}
+		if err := c.restartPolling(next); err != nil {
+			gologger.Warning().Msgf("Could not change interactsh poll interval: %s", err)
+			c.currentPollDuration = interval
+			return
+		}
+		interval = next
+	}
+}
+
+// restartPolling restarts polling of interactions with the interval
+func (c *Client) restartPolling(interval time.Duration) error {
+	if err := c.interactsh.StopPolling(); err != nil {
+		return err
+	}
+	return c.interactsh.StartPolling(interval, c.pollCallback)
+}
+
+// stopBackoffPolling stops the backoff of polling if enabled, polling
+// again with the poll duration so interactions of the last requests are
+// received without delay
+func (c *Client) stopBackoffPolling() {
+	if c.stopBackoff == nil {
+		return
+	}
+	close(c.stopBackoff)
+	<-c.backoffDone
+	c.stopBackoff = nil
+	if c.currentPollDuration != c.pollDuration {
+		if err := c.restartPolling(c.pollDuration); err != nil {
+			gologger.Warning().Msgf("Could not reset interactsh poll interval: %s", err)
+		}
+	}
+}
+
+// startInteractionSpan starts a tracing span for processing of a polled interaction
+func (c *Client) startInteractionSpan(interaction *server.Interaction, request *RequestData) trace.Span {
+	if !tracing.Enabled() {
+		return trace.SpanFromContext(context.Background())
+	}
+	request.Event.RLock()
+	templateID := types.ToString(request.Event.InternalEvent[templateIdAttribute])
+	host := types.ToString(request.Event.InternalEvent["host"])
+	request.Event.RUnlock()
+
+	_, span := tracing.Start(request.Context, "interactsh.interaction", templateID, host, interaction.Protocol)
+	tracing.SetAttributes(span, attribute.String("interactsh.unique-id", interaction.UniqueID))
+	return span
+}
+
 // requestShouldStopAtFirstmatch checks if further interactions should be stopped
 // note: extra care should be taken while using this function since internalEvent is
 // synchronized all the time and if caller functions has already acquired lock its best to explicitly specify that
@@ -155,6 +283,7 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 	var matched bool
 	data.Event.Lock()
 	data.Event.InternalEvent["interactsh_protocol"] = interaction.Protocol
+	data.Event.InternalEvent["interactsh_protocols"] = data.addProtocol(interaction.UniqueID, interaction.Protocol)
 	if strings.EqualFold(interaction.Protocol, "dns") {
 		data.Event.InternalEvent["interactsh_request"] = strings.ToLower(interaction.RawRequest)
 	} else {
@@ -162,6 +291,7 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 	}
 	data.Event.InternalEvent["interactsh_response"] = interaction.RawResponse
 	data.Event.InternalEvent["interactsh_ip"] = interaction.RemoteAddress
+	data.Event.InternalEvent["interactsh_exfil"] = exfiltratedData(interaction)
 	data.Event.Unlock()
 
 	if data.Operators != nil {
@@ -241,15 +371,24 @@ func (c *Client) URL() (string, error) {
 	return c.interactsh.URL(), nil
 }
 
-// Close the interactsh clients after waiting for cooldown period.
+// Close the interactsh clients after waiting for cooldown period and
+// draining interactions of pending requests.
 func (c *Client) Close() bool {
+	c.closing.Store(true)
+	c.stopBackoffPolling()
 	if c.cooldownDuration > 0 && c.generated.Load() {
 		time.Sleep(c.cooldownDuration)
 	}
+	if c.options.DrainTimeout > 0 && c.generated.Load() {
+		c.drain(c.options.DrainTimeout)
+	}
 	if c.interactsh != nil {
 		_ = c.interactsh.StopPolling()
 		c.interactsh.Close()
 	}
+	if late := c.late.Load(); late > 0 {
+		gologger.Info().Msgf("Received %d interactions after scan end (%d matched)", late, c.lateMatched.Load())
+	}
 
 	c.requests.Purge()
 	c.interactions.Purge()
@@ -259,9 +398,24 @@ func (c *Client) Close() bool {
 	return c.matched.Load()
 }
 
+// drain waits for interactions of requests pending correlation until none
+// are pending (matched or evicted) or the timeout elapses
+func (c *Client) drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for c.requests.Len(true) > 0 {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return
+		}
+		time.Sleep(min(wait, c.pollDuration))
+	}
+}
+
 // ReplaceMarkers replaces the default {{interactsh-url}} placeholders with interactsh urls
+// and {{interactsh-xxe}} placeholders with doctypes declaring external entities
 func (c *Client) Replace(data string, interactshURLs []string) (string, []string) {
-	return c.ReplaceWithMarker(data, interactshURLMarkerRegex, interactshURLs)
+	data, interactshURLs = c.ReplaceWithMarker(data, interactshURLMarkerRegex, interactshURLs)
+	return c.ReplaceXXE(data, interactshURLs)
 }
 
 // ReplaceMarkers replaces the placeholders with interactsh urls and appends them to interactshURLs
@@ -320,10 +474,32 @@ type RequestData struct {
 	Operators      *operators.Operators
 	MatchFunc      operators.MatchFunc
 	ExtractFunc    operators.ExtractFunc
+	// Context is the context of the request, interactions received for it
+	// are traced as children of its span (if any, may be nil)
+	Context context.Context
+
+	// protocols contains the distinct protocols of interactions received
+	// for each correlation id of the request (guarded by event lock)
+	protocols map[string][]string
+}
+
+// addProtocol records the protocol of an interaction received for the
+// correlation id returning all protocols received for it in order of arrival
+// (ex: dns,http) so matchers can require multiple interaction types.
+func (data *RequestData) addProtocol(id, protocol string) string {
+	if data.protocols == nil {
+		data.protocols = make(map[string][]string)
+	}
+	protocol = strings.ToLower(protocol)
+	if !sliceutil.Contains(data.protocols[id], protocol) {
+		data.protocols[id] = append(data.protocols[id], protocol)
+	}
+	return strings.Join(data.protocols[id], ",")
 }
 
 // RequestEvent is the event for a network request sent by nuclei.
 func (c *Client) RequestEvent(interactshURLs []string, data *RequestData) {
+	c.registered.Add(int64(len(interactshURLs)))
 	for _, interactshURL := range interactshURLs {
 		id := strings.TrimRight(strings.TrimSuffix(interactshURL, c.getHostname()), ".")
 
@@ -336,15 +512,21 @@ func (c *Client) RequestEvent(interactshURLs []string, data *RequestData) {
 
 		interactions, err := c.interactions.Get(id)
 		if interactions != nil && err == nil {
+			c.interactions.Remove(id)
+			matched := false
 			for _, interaction := range interactions {
 				if c.processInteractionForRequest(interaction, data) {
-					c.interactions.Remove(id)
+					matched = true
 					break
 				}
 			}
-		} else {
-			_ = c.requests.SetWithExpire(id, data, c.eviction)
+			if matched {
+				continue
+			}
 		}
+		// interactions received later for the id (ex: http after dns) are
+		// correlated with the ones already processed until the request expires
+		_ = c.requests.SetWithExpire(id, data, c.eviction)
 	}
 }
 
@@ -378,7 +560,7 @@ func HasMatchers(op *operators.Operators) bool {
 
 // HasMarkers checks if the text contains interactsh markers
 func HasMarkers(data string) bool {
-	return interactshURLMarkerRegex.Match([]byte(data))
+	return interactshURLMarkerRegex.Match([]byte(data)) || HasXXEMarkers(data)
 }
 
 func (c *Client) debugPrintInteraction(interaction *server.Interaction, event *operators.Result) {
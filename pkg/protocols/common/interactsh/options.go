@@ -16,6 +16,9 @@ type Options struct {
 	ServerURL string
 	// Authorization is the Authorization header value
 	Authorization string
+	// Proxy is the http(s) or socks5 proxy used for registration and polling
+	// of the interactsh server. It overrides HTTPClient if specified.
+	Proxy string
 	// CacheSize is the numbers of requests to keep track of at a time.
 	// Older items are discarded in LRU manner in favor of new requests.
 	CacheSize int
@@ -27,6 +30,16 @@ type Options struct {
 	CooldownPeriod time.Duration
 	// PollDuration is the time to wait before each poll to the server for interactions.
 	PollDuration time.Duration
+	// MaxPollDuration is the maximum time polling backs off to when neither
+	// interactions are received nor requests are registered. Backoff is
+	// disabled if it is not greater than PollDuration.
+	MaxPollDuration time.Duration
+	// BatchSize is the number of correlation ids registered since the last
+	// poll which resets backed off polling to PollDuration.
+	BatchSize int
+	// DrainTimeout is the maximum additional time to wait after the cooldown
+	// period for interactions of requests still pending correlation.
+	DrainTimeout time.Duration
 	// Output is the output writer for nuclei
 	Output output.Writer
 	// IssuesClient is a client for issue exporting
@@ -58,6 +71,7 @@ func DefaultOptions(output output.Writer, reporting reporting.Client, progress p
 		Eviction:            60 * time.Second,
 		CooldownPeriod:      5 * time.Second,
 		PollDuration:        5 * time.Second,
+		BatchSize:           1,
 		Output:              output,
 		IssuesClient:        reporting,
 		Progress:            progress,
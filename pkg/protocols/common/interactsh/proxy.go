@@ -0,0 +1,42 @@
+package interactsh
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// proxyTimeout is the timeout of registration and polling requests sent via proxy
+const proxyTimeout = 20 * time.Second
+
+// newProxyHTTPClient returns a http client sending the registration and
+// polling requests of the interactsh client via the http(s) or socks5 proxy
+func newProxyHTTPClient(proxy string) (*retryablehttp.Client, error) {
+	if err := types.ValidateProxyURL(proxy); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("invalid interactsh proxy %s", redactedProxy(proxy))
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+
+	httpOpts := retryablehttp.DefaultOptionsSingle
+	httpOpts.Timeout = proxyTimeout
+	return retryablehttp.NewWithHTTPClient(&http.Client{Transport: transport, Timeout: proxyTimeout}, httpOpts), nil
+}
+
+// redactedProxy returns the proxy url with the password replaced
+// by xxxxx for logging
+func redactedProxy(proxy string) string {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return proxy
+	}
+	return proxyURL.Redacted()
+}
@@ -0,0 +1,88 @@
+package interactsh
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/interactsh/pkg/server"
+)
+
+// XXEEntity is the name of the external entity declared for xxe markers
+const XXEEntity = "xxe"
+
+// xxeMarkerRegex matches the markers replaced by a doctype declaring an
+// external entity fetching an interactsh url
+var xxeMarkerRegex = regexp.MustCompile(`{{interactsh-xxe((?:_[0-9]+){0,3})}}`)
+
+// HasXXEMarkers checks if the text contains xxe markers
+func HasXXEMarkers(data string) bool {
+	return xxeMarkerRegex.MatchString(data)
+}
+
+// ReplaceXXEMarkersWith replaces the xxe markers of data with the replacement
+func ReplaceXXEMarkersWith(data, replacement string) string {
+	return xxeMarkerRegex.ReplaceAllLiteralString(data, replacement)
+}
+
+// XXEDoctype returns a doctype declaring the external entity xxe fetching
+// the interactsh url, referenced as &xxe; in the xml document
+func XXEDoctype(interactshURL string) string {
+	return fmt.Sprintf(`<!DOCTYPE root [<!ENTITY %s SYSTEM "%s">]>`, XXEEntity, XXEEntityURL(interactshURL))
+}
+
+// XXEEntityURL returns the system identifier of the external entity
+// fetching the interactsh url
+func XXEEntityURL(interactshURL string) string {
+	return "http://" + interactshURL + "/"
+}
+
+// NewXXEURL returns a new url for an external entity. The url is available
+// to matchers as interactsh-xxe-url and its id as interactsh-xxe-id.
+func (c *Client) NewXXEURL() (string, error) {
+	return c.NewURLWithData("{{interactsh-xxe-url}}")
+}
+
+// ReplaceXXE replaces xxe markers with doctypes declaring external entities
+// fetching interactsh urls and appends the urls to interactshURLs
+func (c *Client) ReplaceXXE(data string, interactshURLs []string) (string, []string) {
+	for _, marker := range xxeMarkerRegex.FindAllStringSubmatch(data, -1) {
+		if url, err := c.NewURLWithData("{{interactsh-xxe-url" + marker[1] + "}}"); err == nil {
+			interactshURLs = append(interactshURLs, url)
+			data = strings.Replace(data, marker[0], XXEDoctype(url), 1)
+		}
+	}
+	return data, interactshURLs
+}
+
+// exfiltratedData returns the data exfiltrated with an interaction: the
+// url decoded path, query and body of http requests or the subdomain
+// labels preceding the correlation id of dns requests (ex: the content
+// of a file read by an external entity and sent to the interactsh url).
+func exfiltratedData(interaction *server.Interaction) string {
+	switch strings.ToLower(interaction.Protocol) {
+	case "http":
+		head, body, _ := strings.Cut(interaction.RawRequest, "\r\n\r\n")
+		requestLine, _, _ := strings.Cut(head, "\r\n")
+		var parts []string
+		if fields := strings.Fields(requestLine); len(fields) >= 2 {
+			target := strings.TrimLeft(fields[1], "/")
+			if decoded, err := url.PathUnescape(target); err == nil {
+				target = decoded
+			}
+			if target != "" {
+				parts = append(parts, target)
+			}
+		}
+		if body != "" {
+			parts = append(parts, body)
+		}
+		return strings.Join(parts, "\n")
+	case "dns":
+		if index := strings.LastIndex(strings.ToLower(interaction.FullId), strings.ToLower(interaction.UniqueID)); index > 0 {
+			return strings.Trim(interaction.FullId[:index], ".")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+// Package pause implements pausing and resuming request dispatch of a
+// running scan. Requests in flight complete while new requests wait for
+// the scan to be resumed.
+package pause
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// Controller controls pausing of request dispatch. A nil controller is
+// never paused.
+type Controller struct {
+	mutex    sync.Mutex
+	paused   bool
+	pausedAt time.Time
+	resumed  chan struct{}
+}
+
+// New returns a new controller in running state
+func New() *Controller {
+	return &Controller{}
+}
+
+// Pause pauses request dispatch returning false if already paused
+func (c *Controller) Pause() bool {
+	if c == nil {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.paused {
+		return false
+	}
+	c.paused = true
+	c.pausedAt = time.Now()
+	c.resumed = make(chan struct{})
+	gologger.Info().Msgf("Scan paused, requests in flight are completed and new requests wait until resumed\n")
+	return true
+}
+
+// Resume resumes request dispatch returning false if not paused
+func (c *Controller) Resume() bool {
+	if c == nil {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.paused {
+		return false
+	}
+	c.paused = false
+	close(c.resumed)
+	gologger.Info().Msgf("Scan resumed after being paused for %s\n", time.Since(c.pausedAt).Round(time.Second))
+	return true
+}
+
+// Paused returns true if request dispatch is paused
+func (c *Controller) Paused() bool {
+	if c == nil {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.paused
+}
+
+// Wait blocks while request dispatch is paused. It returns the error of
+// the context if it is cancelled while waiting.
+func (c *Controller) Wait(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	if !c.paused {
+		c.mutex.Unlock()
+		return nil
+	}
+	resumed := c.resumed
+	c.mutex.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
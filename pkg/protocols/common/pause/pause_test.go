@@ -0,0 +1,39 @@
+package pause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestControllerPauseResume(t *testing.T) {
+	controller := New()
+	require.Nil(t, controller.Wait(context.Background()), "could not pass running controller")
+
+	require.True(t, controller.Pause(), "could not pause")
+	require.False(t, controller.Pause(), "paused twice")
+	require.True(t, controller.Paused(), "could not get paused state")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- controller.Wait(context.Background())
+	}()
+	select {
+	case <-done:
+		t.Fatal("wait returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.True(t, controller.Resume(), "could not resume")
+	require.Nil(t, <-done, "could not wait for resume")
+	require.False(t, controller.Resume(), "resumed twice")
+
+	controller.Pause()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, controller.Wait(ctx), context.DeadlineExceeded, "could not cancel wait while paused")
+
+	var nilController *Controller
+	require.Nil(t, nilController.Wait(context.Background()), "nil controller was paused")
+}
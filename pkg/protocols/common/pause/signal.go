@@ -0,0 +1,35 @@
+//go:build !windows
+
+package pause
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ListenSignals pauses request dispatch on SIGUSR1 and resumes it on
+// SIGUSR2 until the returned function is called.
+func (c *Controller) ListenSignals() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				if sig == syscall.SIGUSR1 {
+					c.Pause()
+				} else {
+					c.Resume()
+				}
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package pause
+
+// ListenSignals is a no-op on windows which has no user defined signals.
+// Request dispatch can still be paused using Pause and Resume.
+func (c *Controller) ListenSignals() func() {
+	return func() {}
+}
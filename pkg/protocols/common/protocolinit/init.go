@@ -2,6 +2,10 @@ package protocolinit
 
 import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/errorpage"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/ssrf"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
@@ -35,9 +39,19 @@ func Init(options *types.Options) error {
 	if err := compiler.Init(options); err != nil {
 		return err
 	}
+	if err := errorpage.Init(options); err != nil {
+		return err
+	}
+	if err := ssrf.Init(options); err != nil {
+		return err
+	}
+	if err := dsl.InitUserFunctions(options); err != nil {
+		return err
+	}
 	return nil
 }
 
 func Close() {
 	protocolstate.Dialer.Close()
+	extractors.CloseOutputFiles()
 }
@@ -0,0 +1,90 @@
+package protocolstate
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	iputil "github.com/projectdiscovery/utils/ip"
+)
+
+var (
+	// customDialer is the optional dial function used instead of Dialer
+	customDialer func(ctx context.Context, network, address string) (net.Conn, error)
+	// customDialerSNI is the custom tls server name used for custom dialer connections
+	customDialerSNI string
+)
+
+// HasCustomDialer returns true if connections are dialed using a custom dialer
+func HasCustomDialer() bool {
+	return customDialer != nil
+}
+
+// Dial dials address using the custom dialer if configured or Dialer otherwise
+func Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	if customDialer == nil {
+		return Dialer.Dial(ctx, network, address)
+	}
+	if err := validateCustomDialerAddress(address); err != nil {
+		return nil, err
+	}
+	return customDialer(ctx, network, address)
+}
+
+// DialTLSWithConfig dials address over tls using the custom dialer if
+// configured or Dialer otherwise. A nil tlsConfig uses the default config.
+func DialTLSWithConfig(ctx context.Context, network, address string, tlsConfig *tls.Config) (net.Conn, error) {
+	if customDialer == nil {
+		if tlsConfig == nil {
+			return Dialer.DialTLS(ctx, network, address)
+		}
+		return Dialer.DialTLSWithConfig(ctx, network, address, tlsConfig)
+	}
+	conn, err := Dial(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{
+			Renegotiation:      tls.RenegotiateOnceAsClient,
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS10,
+		}
+	}
+	config := tlsConfig.Clone()
+	if config.ServerName == "" {
+		hostname, _, _ := net.SplitHostPort(address)
+		switch {
+		case customDialerSNI != "":
+			config.ServerName = customDialerSNI
+		case ctx.Value(fastdialer.SniName) != nil:
+			config.ServerName, _ = ctx.Value(fastdialer.SniName).(string)
+		case !iputil.IsIP(hostname):
+			config.ServerName = hostname
+		}
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// validateCustomDialerAddress checks the host of address against the network
+// policy as the custom dialer bypasses the checks done by Dialer
+func validateCustomDialerAddress(address string) error {
+	if NetworkPolicy == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if !NetworkPolicy.Validate(host) {
+		return fmt.Errorf("address %s is excluded by network policy", address)
+	}
+	return nil
+}
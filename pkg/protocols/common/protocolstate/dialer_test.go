@@ -0,0 +1,46 @@
+package protocolstate
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/projectdiscovery/networkpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomDialer(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	var dialed []string
+	customDialer = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, ts.Listener.Addr().String())
+	}
+	defer func() { customDialer = nil }()
+	require.True(t, HasCustomDialer(), "could not detect custom dialer")
+
+	conn, err := Dial(context.Background(), "tcp", "example.com:80")
+	require.NoError(t, err, "could not dial using custom dialer")
+	_ = conn.Close()
+
+	conn, err = DialTLSWithConfig(context.Background(), "tcp", "example.com:443", nil)
+	require.NoError(t, err, "could not dial tls using custom dialer")
+	_ = conn.Close()
+	require.Equal(t, []string{"example.com:80", "example.com:443"}, dialed, "could not route dials to custom dialer")
+}
+
+func TestValidateCustomDialerAddress(t *testing.T) {
+	previous := NetworkPolicy
+	defer func() { NetworkPolicy = previous }()
+
+	policy, err := networkpolicy.New(networkpolicy.Options{DenyList: []string{"10.0.0.0/8"}})
+	require.NoError(t, err, "could not create network policy")
+	NetworkPolicy = policy
+	require.NoError(t, validateCustomDialerAddress("192.168.1.1:80"), "could not validate allowed address")
+	require.Error(t, validateCustomDialerAddress("10.1.1.1:80"), "could not enforce network policy")
+}
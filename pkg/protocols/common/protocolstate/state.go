@@ -13,6 +13,7 @@ import (
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/mapcidr/asn"
 	"github.com/projectdiscovery/networkpolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/sshtunnel"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/expand"
 )
@@ -20,6 +21,8 @@ import (
 // Dialer is a shared fastdialer instance for host DNS resolution
 var (
 	Dialer *fastdialer.Dialer
+	// sshTunnel is the connection to the ssh jump host requests are dialed through
+	sshTunnel *sshtunnel.Tunnel
 )
 
 // Init creates the Dialer instance based on user configuration
@@ -29,6 +32,17 @@ func Init(options *types.Options) error {
 	}
 
 	lfaAllowed = options.AllowLocalFileAccess
+	customDialer = options.CustomDialer
+	customDialerSNI = options.SNI
+	// custom dialer of embedding users takes precedence over the jump host
+	if customDialer == nil && options.SSHJumpHost != "" {
+		tunnel, err := sshtunnel.NewFromOptions(options)
+		if err != nil {
+			return err
+		}
+		sshTunnel = tunnel
+		customDialer = tunnel.Dial
+	}
 	opts := fastdialer.DefaultOptions
 	if options.DialerTimeout > 0 {
 		opts.DialerTimeout = options.DialerTimeout
@@ -210,5 +224,9 @@ func Close() {
 	if Dialer != nil {
 		Dialer.Close()
 	}
+	if sshTunnel != nil {
+		_ = sshTunnel.Close()
+		sshTunnel = nil
+	}
 	StopActiveMemGuardian()
 }
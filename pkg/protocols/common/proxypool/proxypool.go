@@ -0,0 +1,214 @@
+// Package proxypool rotates http requests (including fuzzing) through a
+// pool of http and socks5 proxies.
+//
+// The pool is used as the proxy function of http transports so that tls
+// connections to targets (and their SNI) are established through each
+// proxy by the transport as with a single proxy. Proxies whose connections
+// repeatedly fail are skipped for a while and reported in scan stats.
+package proxypool
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Rotation strategies of the pool
+const (
+	// RoundRobin uses the proxies of the pool in turn for each request
+	RoundRobin = "round-robin"
+	// Random uses a random proxy of the pool for each request
+	Random = "random"
+	// Sticky uses the same proxy for all requests of a host
+	Sticky = "sticky"
+)
+
+const (
+	// maxFailures is the number of consecutive failed connections after
+	// which a proxy is considered dead
+	maxFailures = 3
+	// deadCooldown is the duration dead proxies are skipped before being retried
+	deadCooldown = 30 * time.Second
+)
+
+// ErrNoAliveProxy is returned when all proxies of the pool are dead
+var ErrNoAliveProxy = errors.New("no alive proxy in proxy pool")
+
+// Default is the proxy pool of the scan (nil if proxy rotation is disabled)
+var Default *Pool
+
+// Pool is a pool of proxies rotated with a strategy
+type Pool struct {
+	strategy string
+	proxies  []*proxy
+	// byAddress maps the dialed host:port of proxies to them
+	byAddress map[string]*proxy
+
+	mutex  sync.Mutex
+	next   int
+	random *rand.Rand
+	sticky map[string]*proxy
+}
+
+type proxy struct {
+	url       *url.URL
+	failures  int
+	deadUntil time.Time
+}
+
+// IsValidStrategy returns true if strategy is a rotation strategy of the pool
+func IsValidStrategy(strategy string) bool {
+	switch strategy {
+	case RoundRobin, Random, Sticky:
+		return true
+	}
+	return false
+}
+
+// New creates a pool of http(s) and socks5 proxies rotated with strategy
+func New(proxies []string, strategy string) (*Pool, error) {
+	if !IsValidStrategy(strategy) {
+		return nil, errors.Errorf("invalid proxy rotation strategy %s (round-robin, random, sticky)", strategy)
+	}
+	if len(proxies) == 0 {
+		return nil, errors.New("proxy pool requires at least one proxy")
+	}
+	pool := &Pool{
+		strategy:  strategy,
+		byAddress: make(map[string]*proxy),
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		sticky:    make(map[string]*proxy),
+	}
+	for _, value := range proxies {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Host == "" {
+			return nil, errors.Errorf("invalid proxy %s in proxy pool", value)
+		}
+		switch parsed.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, errors.Errorf("unsupported scheme of proxy %s in proxy pool (http, https, socks5)", value)
+		}
+		if _, ok := pool.byAddress[proxyAddress(parsed)]; ok {
+			continue
+		}
+		item := &proxy{url: parsed}
+		pool.proxies = append(pool.proxies, item)
+		pool.byAddress[proxyAddress(parsed)] = item
+	}
+	return pool, nil
+}
+
+// Proxy returns the proxy of the pool for the request. It is used as the
+// proxy function of http transports.
+func (p *Pool) Proxy(req *http.Request) (*url.URL, error) {
+	return p.Pick(req.URL.Hostname())
+}
+
+// Pick returns the next alive proxy of the pool for host
+func (p *Pool) Pick(host string) (*url.URL, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if p.strategy == Sticky {
+		if item, ok := p.sticky[host]; ok && item.alive(now) {
+			return item.url, nil
+		}
+	}
+	alive := make([]*proxy, 0, len(p.proxies))
+	for _, item := range p.proxies {
+		if item.alive(now) {
+			alive = append(alive, item)
+		}
+	}
+	if len(alive) == 0 {
+		return nil, ErrNoAliveProxy
+	}
+
+	var item *proxy
+	switch p.strategy {
+	case Random:
+		item = alive[p.random.Intn(len(alive))]
+	default:
+		item = alive[p.next%len(alive)]
+		p.next++
+	}
+	if p.strategy == Sticky {
+		p.sticky[host] = item
+	}
+	return item.url, nil
+}
+
+// WrapDialer wraps the dial function of a transport to track the health of
+// proxies from the connections dialed to them
+func (p *Pool) WrapDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if item, ok := p.byAddress[address]; ok {
+			p.record(item, err)
+		}
+		return conn, err
+	}
+}
+
+// record records the result of a connection to the proxy
+func (p *Pool) record(item *proxy, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err == nil {
+		item.failures = 0
+		item.deadUntil = time.Time{}
+		return
+	}
+	// context cancellations are not failures of the proxy
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	item.failures++
+	if item.failures >= maxFailures {
+		item.deadUntil = time.Now().Add(deadCooldown)
+	}
+}
+
+// Health returns the proxies of the pool (without credentials) mapped to
+// whether they are alive
+func (p *Pool) Health() map[string]bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	health := make(map[string]bool, len(p.proxies))
+	for _, item := range p.proxies {
+		health[item.url.Redacted()] = item.alive(now)
+	}
+	return health
+}
+
+// alive returns true if the proxy is not skipped as dead
+func (item *proxy) alive(now time.Time) bool {
+	return item.deadUntil.IsZero() || now.After(item.deadUntil)
+}
+
+// proxyAddress returns the host:port dialed by transports for the proxy
+func proxyAddress(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
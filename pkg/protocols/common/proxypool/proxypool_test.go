@@ -0,0 +1,128 @@
+package proxypool
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStrategies(t *testing.T) {
+	proxies := []string{"http://127.0.0.1:8081", "http://127.0.0.1:8082", "socks5://127.0.0.1:1080"}
+
+	pool, err := New(proxies, RoundRobin)
+	require.Nil(t, err, "could not create pool")
+	var picked []string
+	for i := 0; i < 4; i++ {
+		proxyURL, err := pool.Pick("example.com")
+		require.Nil(t, err, "could not pick proxy")
+		picked = append(picked, proxyURL.String())
+	}
+	require.Equal(t, append(proxies, proxies[0]), picked, "could not rotate proxies in turn")
+
+	pool, err = New(proxies, Sticky)
+	require.Nil(t, err, "could not create pool")
+	first, _ := pool.Pick("a.example.com")
+	other, _ := pool.Pick("b.example.com")
+	again, _ := pool.Pick("a.example.com")
+	require.Equal(t, first, again, "could not stick proxy to host")
+	require.NotEqual(t, first, other, "could not rotate proxies of hosts")
+
+	_, err = New(proxies, "weighted")
+	require.Error(t, err, "could create pool with invalid strategy")
+	_, err = New([]string{"ftp://127.0.0.1:21"}, Random)
+	require.Error(t, err, "could create pool with unsupported proxy")
+}
+
+func TestPoolSkipsDeadProxies(t *testing.T) {
+	pool, err := New([]string{"http://127.0.0.1:8081", "http://127.0.0.1:8082"}, RoundRobin)
+	require.Nil(t, err, "could not create pool")
+
+	dial := pool.WrapDialer(func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	})
+	for i := 0; i < maxFailures; i++ {
+		_, _ = dial(context.Background(), "tcp", "127.0.0.1:8081")
+	}
+	require.Equal(t, map[string]bool{"http://127.0.0.1:8081": false, "http://127.0.0.1:8082": true}, pool.Health(), "could not report proxy health")
+	for i := 0; i < 3; i++ {
+		proxyURL, err := pool.Pick("example.com")
+		require.Nil(t, err, "could not pick proxy")
+		require.Equal(t, "127.0.0.1:8082", proxyURL.Host, "could not skip dead proxy")
+	}
+
+	for i := 0; i < maxFailures; i++ {
+		_, _ = dial(context.Background(), "tcp", "127.0.0.1:8082")
+	}
+	_, err = pool.Pick("example.com")
+	require.ErrorIs(t, err, ErrNoAliveProxy, "could pick proxy of dead pool")
+}
+
+func TestPoolTransportTLS(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.TLS.ServerName)
+	}))
+	defer target.Close()
+
+	var first, second atomic.Int32
+	proxy1 := httptest.NewServer(connectProxy(&first))
+	defer proxy1.Close()
+	proxy2 := httptest.NewServer(connectProxy(&second))
+	defer proxy2.Close()
+
+	pool, err := New([]string{proxy1.URL, proxy2.URL}, RoundRobin)
+	require.Nil(t, err, "could not create pool")
+
+	var dialer net.Dialer
+	transport := &http.Transport{
+		Proxy:             pool.Proxy,
+		DialContext:       pool.WrapDialer(dialer.DialContext),
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, ServerName: "sni.example.com"},
+		DisableKeepAlives: true,
+	}
+	client := &http.Client{Transport: transport}
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(target.URL)
+		require.Nil(t, err, "could not get target through proxy")
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.Equal(t, "sni.example.com", string(body), "could not send sni through proxy")
+	}
+	require.Equal(t, int32(2), first.Load(), "could not rotate to first proxy")
+	require.Equal(t, int32(2), second.Load(), "could not rotate to second proxy")
+}
+
+// connectProxy is a http proxy tunneling CONNECT requests counting them
+func connectProxy(count *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		count.Add(1)
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			_ = upstream.Close()
+			return
+		}
+		go func() {
+			_, _ = io.Copy(upstream, conn)
+			_ = upstream.Close()
+		}()
+		_, _ = io.Copy(conn, upstream)
+		_ = conn.Close()
+	})
+}
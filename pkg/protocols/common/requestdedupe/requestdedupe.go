@@ -0,0 +1,93 @@
+// Package requestdedupe implements optional deduplication of template
+// requests across inputs sharing the same host, skipping requests that
+// were already sent for another input during the same scan.
+package requestdedupe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Store keeps track of requests sent during a scan
+type Store struct {
+	mu      sync.Mutex
+	sent    map[string]string
+	skipped atomic.Int64
+}
+
+// New creates a new request deduplication store
+func New() *Store {
+	return &Store{sent: make(map[string]string)}
+}
+
+// IsDuplicate returns true if the same request of a template was already sent
+// for a different input. The first input sending a request owns it, so that
+// repeated requests of the same input (ex: multi-step templates) are never skipped.
+func (s *Store) IsDuplicate(templateID, input, method, rawURL string, body []byte) bool {
+	if s == nil {
+		return false
+	}
+	key := templateID + "|" + Key(method, rawURL, body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner, ok := s.sent[key]
+	if !ok {
+		s.sent[key] = input
+		return false
+	}
+	if owner == input {
+		return false
+	}
+	s.skipped.Add(1)
+	return true
+}
+
+// Skipped returns the number of duplicate requests skipped
+func (s *Store) Skipped() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.skipped.Load()
+}
+
+// Key returns the normalized (method, host, path, body-hash) key of a request
+func Key(method, rawURL string, body []byte) string {
+	hash := sha256.Sum256(body)
+	return strings.Join([]string{strings.ToUpper(method), normalizeURL(rawURL), hex.EncodeToString(hash[:])}, " ")
+}
+
+// normalizeURL lowercases scheme and host, removes default ports,
+// cleans the path and sorts query parameters of a url
+func normalizeURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	host := strings.ToLower(parsed.Host)
+	if hostname, port, err := net.SplitHostPort(host); err == nil {
+		if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+			host = hostname
+		}
+	}
+	cleaned := "/"
+	if parsed.Path != "" {
+		cleaned = path.Clean("/" + parsed.Path)
+		if strings.HasSuffix(parsed.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+	}
+	normalized := scheme + "://" + host + cleaned
+	if parsed.RawQuery != "" {
+		normalized += "?" + parsed.Query().Encode()
+	}
+	return normalized
+}
@@ -0,0 +1,25 @@
+package requestdedupe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	require.Equal(t, Key("get", "HTTPS://Example.com:443/a/../b?y=2&x=1", nil), Key("GET", "https://example.com/b?x=1&y=2", nil))
+	require.NotEqual(t, Key("GET", "https://example.com/b", nil), Key("POST", "https://example.com/b", nil))
+	require.NotEqual(t, Key("POST", "https://example.com/b", []byte("a=1")), Key("POST", "https://example.com/b", []byte("a=2")))
+}
+
+func TestStore(t *testing.T) {
+	store := New()
+	require.False(t, store.IsDuplicate("tpl", "https://example.com/x", "GET", "https://example.com/", nil))
+	require.False(t, store.IsDuplicate("tpl", "https://example.com/x", "GET", "https://example.com/", nil), "same input should not be skipped")
+	require.True(t, store.IsDuplicate("tpl", "https://example.com/y", "GET", "https://example.com/", nil))
+	require.False(t, store.IsDuplicate("other", "https://example.com/y", "GET", "https://example.com/", nil))
+	require.Equal(t, int64(1), store.Skipped())
+
+	var nilStore *Store
+	require.False(t, nilStore.IsDuplicate("tpl", "a", "GET", "https://example.com/", nil))
+}
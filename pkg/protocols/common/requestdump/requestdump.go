@@ -0,0 +1,79 @@
+// Package requestdump implements the request dump (dry-run) mode printing
+// the fully built requests of templates instead of sending them.
+//
+// The number of requests printed per template is capped so that templates
+// with huge payload sets only print a sample while all requests are counted.
+package requestdump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DefaultLimit is the default number of requests printed per template
+const DefaultLimit = 100
+
+// Dumper prints built requests and counts them per template
+type Dumper struct {
+	limit  int
+	writer io.Writer
+
+	mu     sync.Mutex
+	counts map[string]int
+	total  int
+	shown  int
+}
+
+// New creates a new request dumper printing up to limit requests per
+// template to stdout. A non-positive limit uses the default limit.
+func New(limit int) *Dumper {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Dumper{limit: limit, writer: os.Stdout, counts: make(map[string]int)}
+}
+
+// Dump prints the request of the template to url if the template has not
+// reached the limit and counts it otherwise
+func (d *Dumper) Dump(templateID, url string, request []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.total++
+	d.counts[templateID]++
+	count := d.counts[templateID]
+	if count > d.limit {
+		return
+	}
+	d.shown++
+	_, _ = fmt.Fprintf(d.writer, "[%s] Request #%d to %s\n%s\n\n", templateID, count, url, bytes.TrimRight(request, "\r\n"))
+	if count == d.limit {
+		_, _ = fmt.Fprintf(d.writer, "[%s] Request dump limit of %d reached, further requests are only counted\n\n", templateID, d.limit)
+	}
+}
+
+// Counts returns the number of requests built per template
+func (d *Dumper) Counts() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	counts := make(map[string]int, len(d.counts))
+	for templateID, count := range d.counts {
+		counts[templateID] = count
+	}
+	return counts
+}
+
+// Summary returns the total number of requests built and the number of
+// requests not printed due to the limit
+func (d *Dumper) Summary() (total, hidden int) {
+	if d == nil {
+		return 0, 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total, d.total - d.shown
+}
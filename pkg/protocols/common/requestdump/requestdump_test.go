@@ -0,0 +1,28 @@
+package requestdump
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumperLimit(t *testing.T) {
+	var buffer bytes.Buffer
+	dumper := New(2)
+	dumper.writer = &buffer
+
+	for i := 0; i < 5; i++ {
+		dumper.Dump("fuzz-template", "https://example.com/?q=1", []byte("GET /?q=1 HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	}
+	dumper.Dump("other-template", "https://example.com/", []byte("GET / HTTP/1.1\r\n\r\n"))
+
+	require.Equal(t, 3, strings.Count(buffer.String(), "] Request #"), "could not cap printed requests")
+	require.Contains(t, buffer.String(), "[fuzz-template] Request dump limit of 2 reached")
+	require.Equal(t, map[string]int{"fuzz-template": 5, "other-template": 1}, dumper.Counts(), "could not count requests")
+
+	total, hidden := dumper.Summary()
+	require.Equal(t, 6, total, "could not get total requests")
+	require.Equal(t, 3, hidden, "could not get hidden requests")
+}
@@ -0,0 +1,141 @@
+// Package responsecache implements an optional in-memory cache of responses
+// to safe http requests, so that identical requests made by different
+// templates during a scan (ex: fetching /robots.txt) are only sent once.
+//
+// Responses are keyed on the method, the normalized url and the headers of
+// the request except headers which do not change the response but vary
+// between requests (ex: the random User-Agent). Cached responses expire after
+// a ttl and the least recently used ones are evicted once the cache is full.
+package responsecache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Mzack9999/gcache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdedupe"
+)
+
+// ignoredHeaders are request headers not part of the cache key
+var ignoredHeaders = map[string]struct{}{
+	"User-Agent":     {},
+	"Connection":     {},
+	"Content-Length": {},
+}
+
+// Response is a cached http response
+type Response struct {
+	ProtoMajor int
+	ProtoMinor int
+	StatusCode int
+	Status     string
+	Header     http.Header
+	// Body is the raw (not decoded) body of the response
+	Body []byte
+}
+
+// Cache is a bounded in-memory cache of http responses
+type Cache struct {
+	responses gcache.Cache[string, *Response]
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New creates a new response cache with the maximum number of responses and their ttl
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{responses: gcache.New[string, *Response](size).LRU().Expiration(ttl).Build()}
+}
+
+// IsCacheable returns true if responses of requests with the method can be cached
+func IsCacheable(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	return false
+}
+
+// Key returns the cache key of a request
+func Key(method, rawURL string, header http.Header) string {
+	var builder strings.Builder
+	builder.WriteString(requestdedupe.Key(method, rawURL, nil))
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if _, ok := ignoredHeaders[http.CanonicalHeaderKey(name)]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		builder.WriteString("\n")
+		builder.WriteString(strings.ToLower(name))
+		builder.WriteString(": ")
+		builder.WriteString(strings.Join(header[name], ", "))
+	}
+	return builder.String()
+}
+
+// Get returns a new http response for the cached response of the key
+func (c *Cache) Get(key string) (*http.Response, bool) {
+	if c == nil {
+		return nil, false
+	}
+	cached, err := c.responses.Get(key)
+	if err != nil || cached == nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return &http.Response{
+		ProtoMajor:    cached.ProtoMajor,
+		ProtoMinor:    cached.ProtoMinor,
+		StatusCode:    cached.StatusCode,
+		Status:        cached.Status,
+		Header:        cached.Header.Clone(),
+		ContentLength: int64(len(cached.Body)),
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+	}, true
+}
+
+// Set caches the response of the key with its raw body
+func (c *Cache) Set(key string, resp *http.Response, body []byte) {
+	if c == nil || resp == nil {
+		return
+	}
+	_ = c.responses.Set(key, &Response{
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header.Clone(),
+		Body:       bytes.Clone(body),
+	})
+}
+
+// Stats returns the number of cache hits and misses
+func (c *Cache) Stats() (hits, misses int64) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.hits.Load(), c.misses.Load()
+}
+
+// IsCacheableResponse returns true if the response can be cached. Redirects
+// are not cached since they are followed depending on the request and
+// transient errors (rate limiting, server errors) are retried by requests.
+func IsCacheableResponse(resp *http.Response) bool {
+	if resp == nil || (resp.Request != nil && resp.Request.Response != nil) {
+		return false
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return false
+	}
+	return resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500
+}
@@ -0,0 +1,54 @@
+package responsecache
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	require.Equal(t,
+		Key("GET", "https://Example.com:443/robots.txt", http.Header{"User-Agent": {"a"}, "Accept": {"*/*"}}),
+		Key("get", "https://example.com/robots.txt", http.Header{"User-Agent": {"b"}, "Accept": {"*/*"}}),
+		"user agent should not be part of the key",
+	)
+	require.NotEqual(t,
+		Key("GET", "https://example.com/", http.Header{"Cookie": {"a=1"}}),
+		Key("GET", "https://example.com/", http.Header{"Cookie": {"a=2"}}),
+	)
+	require.NotEqual(t, Key("GET", "https://example.com/", nil), Key("HEAD", "https://example.com/", nil))
+}
+
+func TestCache(t *testing.T) {
+	cache := New(1, time.Minute)
+	key := Key("GET", "https://example.com/robots.txt", nil)
+
+	_, ok := cache.Get(key)
+	require.False(t, ok, "got response for empty cache")
+
+	cache.Set(key, &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/plain"}}}, []byte("User-agent: *"))
+	for i := 0; i < 2; i++ {
+		resp, ok := cache.Get(key)
+		require.True(t, ok, "could not get cached response")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "could not read cached body")
+		require.Equal(t, "User-agent: *", string(body))
+	}
+
+	cache.Set(Key("GET", "https://example.com/", nil), &http.Response{StatusCode: http.StatusOK}, nil)
+	_, ok = cache.Get(key)
+	require.False(t, ok, "could not bound cache size")
+
+	hits, misses := cache.Stats()
+	require.Equal(t, int64(2), hits)
+	require.Equal(t, int64(2), misses)
+
+	require.False(t, IsCacheableResponse(&http.Response{StatusCode: http.StatusFound}), "cached redirect")
+	require.False(t, IsCacheableResponse(&http.Response{StatusCode: http.StatusServiceUnavailable}), "cached server error")
+	require.True(t, IsCacheableResponse(&http.Response{StatusCode: http.StatusNotFound}))
+}
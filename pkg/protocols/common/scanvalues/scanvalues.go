@@ -0,0 +1,185 @@
+// Package scanvalues implements a store of values extracted by templates
+// shared with templates executed later on the same input (ex: the version
+// detected by a fingerprint template referenced by a fuzzing template).
+//
+// Values are scoped to the input they were extracted from and named after
+// the template and the extractor as <template_id>_<extractor> with characters
+// other than letters, digits and underscores replaced by underscores (ex:
+// wordpress_detect_version), a single value being stored as a string and
+// multiple values as an array. <template_id>_matched is set to true once a
+// template matched on the input. The first value stored for a name is kept.
+//
+// Values are only guaranteed to be available to templates executed after the
+// template extracting them, which is the case for templates of lower priority
+// when templates are executed in priority order. Requests referencing values
+// which were not extracted are skipped as their variables are unresolved.
+//
+// Technologies detected on an input are recorded for the scope of templates
+// (scope.tech): once a template matched on an input, its id, the names of
+// its matchers which matched (ex: wordpress for tech-detect) and the values
+// extracted by its extractors named tech are detected technologies of the
+// input, compared case insensitively.
+package scanvalues
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+)
+
+// nameRegex matches characters replaced in names of values
+var nameRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// TechExtractor is the name of extractors whose values are detected technologies
+const TechExtractor = "tech"
+
+// Store contains values extracted by templates and detected technologies
+// keyed by input. A nil store does not record values.
+type Store struct {
+	mutex  sync.RWMutex
+	values map[string]map[string]interface{}
+	techs  map[string]map[string]struct{}
+}
+
+// New returns a new empty store
+func New() *Store {
+	return &Store{values: make(map[string]map[string]interface{}), techs: make(map[string]map[string]struct{})}
+}
+
+// Record stores the values extracted by the template on the input along
+// with its match status from the result of its operators
+func (s *Store) Record(input, templateID string, result *operators.Result) {
+	if s == nil || result == nil || input == "" {
+		return
+	}
+	prefix := Name(templateID) + "_"
+	values := make(map[string]interface{})
+	for _, extracted := range []map[string][]string{result.DynamicValues, result.Extracts} {
+		for name, value := range extracted {
+			if name == "" || len(value) == 0 {
+				continue
+			}
+			if len(value) == 1 {
+				values[prefix+Name(name)] = value[0]
+			} else {
+				values[prefix+Name(name)] = value
+			}
+		}
+	}
+	if result.Matched {
+		values[prefix+"matched"] = true
+	}
+	techs := detectedTechs(templateID, result)
+	if len(values) == 0 && len(techs) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(techs) > 0 {
+		detected, ok := s.techs[input]
+		if !ok {
+			detected = make(map[string]struct{}, len(techs))
+			s.techs[input] = detected
+		}
+		for _, tech := range techs {
+			detected[tech] = struct{}{}
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	stored, ok := s.values[input]
+	if !ok {
+		stored = make(map[string]interface{}, len(values))
+		s.values[input] = stored
+	}
+	for name, value := range values {
+		if _, ok := stored[name]; !ok {
+			stored[name] = value
+		}
+	}
+}
+
+// Get returns a copy of the values of the input or nil if it has none
+func (s *Store) Get(input string) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stored, ok := s.values[input]
+	if !ok {
+		return nil
+	}
+	values := make(map[string]interface{}, len(stored))
+	for name, value := range stored {
+		values[name] = value
+	}
+	return values
+}
+
+// Techs returns the sorted technologies detected on the input
+func (s *Store) Techs(input string) []string {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	techs := make([]string, 0, len(s.techs[input]))
+	for tech := range s.techs[input] {
+		techs = append(techs, tech)
+	}
+	sort.Strings(techs)
+	return techs
+}
+
+// HasTech returns true if any of the technologies was detected on the input
+func (s *Store) HasTech(input string, techs ...string) bool {
+	if s == nil {
+		return false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	detected := s.techs[input]
+	for _, tech := range techs {
+		if _, ok := detected[strings.ToLower(tech)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// detectedTechs returns the lowercased technologies detected by the result
+// of the template (none if it did not match)
+func detectedTechs(templateID string, result *operators.Result) []string {
+	if !result.Matched {
+		return nil
+	}
+	techs := []string{strings.ToLower(templateID)}
+	for name := range result.Matches {
+		if name != "" {
+			techs = append(techs, strings.ToLower(name))
+		}
+	}
+	for _, extracted := range []map[string][]string{result.DynamicValues, result.Extracts} {
+		for _, value := range extracted[TechExtractor] {
+			if value = strings.TrimSpace(value); value != "" {
+				techs = append(techs, strings.ToLower(value))
+			}
+		}
+	}
+	return techs
+}
+
+// Name returns the name of a template or extractor used in names of values
+func Name(name string) string {
+	return nameRegex.ReplaceAllString(name, "_")
+}
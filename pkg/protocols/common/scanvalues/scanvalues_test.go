@@ -0,0 +1,58 @@
+package scanvalues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+)
+
+func TestStore(t *testing.T) {
+	store := New()
+	store.Record("https://example.com", "wordpress-detect", &operators.Result{
+		Matched:       true,
+		DynamicValues: map[string][]string{"version": {"6.4.2"}},
+		Extracts:      map[string][]string{"plugins": {"akismet", "jetpack"}},
+	})
+	store.Record("https://example.com", "wordpress-detect", &operators.Result{
+		DynamicValues: map[string][]string{"version": {"5.0"}},
+	})
+	store.Record("https://other.com", "tech-detect", &operators.Result{})
+
+	values := store.Get("https://example.com")
+	require.Equal(t, map[string]interface{}{
+		"wordpress_detect_version": "6.4.2",
+		"wordpress_detect_plugins": []string{"akismet", "jetpack"},
+		"wordpress_detect_matched": true,
+	}, values, "could not get values of input")
+	require.Nil(t, store.Get("https://other.com"), "got values of input without values")
+
+	values["wordpress_detect_version"] = "changed"
+	require.Equal(t, "6.4.2", store.Get("https://example.com")["wordpress_detect_version"], "store was modified by caller")
+
+	var nilStore *Store
+	nilStore.Record("https://example.com", "tech-detect", &operators.Result{Matched: true})
+	require.Nil(t, nilStore.Get("https://example.com"), "nil store has values")
+}
+
+func TestStoreTechs(t *testing.T) {
+	store := New()
+	store.Record("https://example.com", "tech-detect", &operators.Result{
+		Matched: true,
+		Matches: map[string][]string{"WordPress": {"wp-content"}, "": {"x"}},
+	})
+	store.Record("https://example.com", "cms-fingerprint", &operators.Result{
+		Matched:  true,
+		Extracts: map[string][]string{TechExtractor: {"Drupal "}},
+	})
+	store.Record("https://example.com", "nginx-detect", &operators.Result{
+		Extracts: map[string][]string{TechExtractor: {"nginx"}},
+	})
+
+	require.Equal(t, []string{"cms-fingerprint", "drupal", "tech-detect", "wordpress"}, store.Techs("https://example.com"), "could not get detected techs")
+	require.True(t, store.HasTech("https://example.com", "joomla", "WordPress"), "could not detect tech case insensitively")
+	require.False(t, store.HasTech("https://example.com", "nginx"), "detected tech of template which did not match")
+	require.False(t, store.HasTech("https://other.com", "wordpress"), "detected tech of other input")
+	require.Empty(t, store.Techs("https://other.com"), "got techs of input without techs")
+}
@@ -0,0 +1,120 @@
+// Package severitypolicy implements rate and concurrency policies applied
+// to templates depending on their severity, so that a single scan can run
+// info templates aggressively while throttling intrusive high severity ones.
+//
+// Policies are applied in addition to the global and per-host limits: a
+// request of a template takes from the global rate limiter, the rate limiter
+// of the template (if any), the rate limiter of the severity of the template
+// and the shared per-host limiter (if any), hence the strictest limit wins.
+// The concurrency of a severity bounds the number of template executions of
+// the severity running at the same time within the bulk-size and template
+// concurrency of the scan.
+package severitypolicy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/ratelimit"
+)
+
+// Policy is the rate and concurrency policy of a severity
+type Policy struct {
+	// RateLimit is the maximum number of requests per second (0 for no limit)
+	RateLimit int
+	// Concurrency is the maximum number of concurrent template executions (0 for no limit)
+	Concurrency int
+
+	limiter *ratelimit.Limiter
+	slots   chan struct{}
+}
+
+// Policies are the policies of severities
+type Policies struct {
+	policies map[severity.Severity]*Policy
+}
+
+// Parse parses policies in the format <severities>:<key>=<value>,... where
+// severities is a comma separated list of severities and keys are rate (requests
+// per second) and concurrency. ex: high,critical:rate=10,concurrency=2
+func Parse(values []string) (*Policies, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	policies := &Policies{policies: make(map[severity.Severity]*Policy)}
+	for _, value := range values {
+		names, settings, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(settings) == "" {
+			return nil, fmt.Errorf("invalid severity policy %s: expected <severities>:rate=<n>,concurrency=<n>", value)
+		}
+		var severities severity.Severities
+		if err := severities.Set(names); err != nil {
+			return nil, fmt.Errorf("invalid severity policy %s: %w", value, err)
+		}
+		policy := &Policy{}
+		for _, setting := range strings.Split(settings, ",") {
+			key, number, _ := strings.Cut(strings.TrimSpace(setting), "=")
+			parsed, err := strconv.Atoi(strings.TrimSpace(number))
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid severity policy %s: %s must be a positive number", value, key)
+			}
+			switch strings.ToLower(key) {
+			case "rate":
+				policy.RateLimit = parsed
+			case "concurrency":
+				policy.Concurrency = parsed
+			default:
+				return nil, fmt.Errorf("invalid severity policy %s: unknown setting %s", value, key)
+			}
+		}
+		for _, sev := range severities {
+			if _, ok := policies.policies[sev]; ok {
+				return nil, fmt.Errorf("duplicate severity policy for %s", sev)
+			}
+			policies.policies[sev] = policy
+		}
+	}
+	for _, policy := range policies.policies {
+		if policy.RateLimit > 0 && policy.limiter == nil {
+			policy.limiter = ratelimit.New(context.Background(), uint(policy.RateLimit), time.Second)
+		}
+		if policy.Concurrency > 0 && policy.slots == nil {
+			policy.slots = make(chan struct{}, policy.Concurrency)
+		}
+	}
+	return policies, nil
+}
+
+// Take takes from the rate limiter of the severity (if any)
+func (p *Policies) Take(sev severity.Severity) {
+	if policy := p.get(sev); policy != nil && policy.limiter != nil {
+		policy.limiter.Take()
+	}
+}
+
+// Acquire waits for a concurrency slot of the severity (if limited) returning
+// the function releasing it. False is returned if the context is done.
+func (p *Policies) Acquire(ctx context.Context, sev severity.Severity) (func(), bool) {
+	policy := p.get(sev)
+	if policy == nil || policy.slots == nil {
+		return func() {}, true
+	}
+	select {
+	case policy.slots <- struct{}{}:
+		return func() { <-policy.slots }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// get returns the policy of the severity if any
+func (p *Policies) get(sev severity.Severity) *Policy {
+	if p == nil {
+		return nil
+	}
+	return p.policies[sev]
+}
@@ -0,0 +1,54 @@
+package severitypolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	policies, err := Parse([]string{"high,critical:rate=10,concurrency=2", "info:concurrency=50"})
+	require.NoError(t, err, "could not parse policies")
+	require.Equal(t, 10, policies.get(severity.High).RateLimit)
+	require.Equal(t, 2, policies.get(severity.Critical).Concurrency)
+	require.Equal(t, 0, policies.get(severity.Info).RateLimit)
+	require.Nil(t, policies.get(severity.Low), "got policy of severity without policy")
+
+	for _, invalid := range []string{"high", "severe:rate=1", "high:rate=0", "high:burst=1", "high:rate=x"} {
+		_, err := Parse([]string{invalid})
+		require.Error(t, err, "could parse invalid policy %s", invalid)
+	}
+	_, err = Parse([]string{"high:rate=1", "high,low:concurrency=1"})
+	require.Error(t, err, "could parse duplicate policy")
+
+	policies, err = Parse(nil)
+	require.NoError(t, err)
+	require.Nil(t, policies)
+}
+
+func TestAcquire(t *testing.T) {
+	policies, err := Parse([]string{"high:concurrency=1"})
+	require.NoError(t, err, "could not parse policies")
+
+	release, ok := policies.Acquire(context.Background(), severity.High)
+	require.True(t, ok, "could not acquire slot")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok = policies.Acquire(ctx, severity.High)
+	require.False(t, ok, "could acquire slot over concurrency")
+
+	_, ok = policies.Acquire(ctx, severity.Low)
+	require.True(t, ok, "could not acquire slot of severity without policy")
+
+	release()
+	release, ok = policies.Acquire(context.Background(), severity.High)
+	require.True(t, ok, "could not acquire released slot")
+	release()
+
+	var nilPolicies *Policies
+	_, ok = nilPolicies.Acquire(ctx, severity.High)
+	require.True(t, ok)
+}
@@ -0,0 +1,180 @@
+// Package sharedlimit implements a per-host rate limit shared between
+// multiple nuclei processes using a redis coordinator.
+//
+// Processes increment a counter of the host for the current window and wait
+// for the next window once the shared budget is exhausted. When the
+// coordinator is unreachable, limiting falls back to the local rate limiter
+// of each process until it is reachable again.
+package sharedlimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// keyPrefix is the prefix of redis keys of host counters
+	keyPrefix = "nuclei:ratelimit"
+	// retryInterval is the interval after which an unreachable coordinator is retried
+	retryInterval = 30 * time.Second
+	// coordinatorTimeout is the timeout of operations with the coordinator
+	coordinatorTimeout = time.Second
+)
+
+// Store is a store of counters shared between processes
+type Store interface {
+	// Incr increments the counter of key returning the new value. The
+	// counter expires after the ttl.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Close closes the store
+	Close() error
+}
+
+// Limiter is a per-host rate limiter shared between processes
+type Limiter struct {
+	store    Store
+	limit    int64
+	duration time.Duration
+
+	mutex            sync.Mutex
+	unavailableUntil time.Time
+}
+
+// New creates a shared limiter with a redis coordinator at url (ex:
+// redis://localhost:6379/0) allowing limit requests per duration for
+// each host across processes.
+func New(url string, limit int, duration time.Duration) (*Limiter, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse rate limit coordinator url")
+	}
+	options.DialTimeout = coordinatorTimeout
+	options.ReadTimeout = coordinatorTimeout
+	options.WriteTimeout = coordinatorTimeout
+	options.MaxRetries = -1
+	return NewWithStore(&redisStore{client: redis.NewClient(options)}, limit, duration)
+}
+
+// NewFromOptions creates a shared limiter from the options of a scan or
+// returns nil if no coordinator is configured. The rate limit of the scan
+// is used when no shared rate limit is provided.
+func NewFromOptions(options *types.Options) (*Limiter, error) {
+	if options.RateLimitCoordinator == "" {
+		return nil, nil
+	}
+	limit := options.SharedRateLimit
+	if limit <= 0 {
+		limit = options.RateLimit
+	}
+	return New(options.RateLimitCoordinator, limit, options.RateLimitDuration)
+}
+
+// NewWithStore creates a shared limiter using a custom store
+func NewWithStore(store Store, limit int, duration time.Duration) (*Limiter, error) {
+	if limit <= 0 {
+		return nil, errors.New("shared rate limit must be greater than zero")
+	}
+	if duration <= 0 {
+		duration = time.Second
+	}
+	return &Limiter{store: store, limit: int64(limit), duration: duration}, nil
+}
+
+// Take blocks until a request to the host of input is allowed by the shared
+// budget. It returns immediately if the coordinator is unreachable.
+func (l *Limiter) Take(ctx context.Context, input string) {
+	host := hostOf(input)
+	for {
+		if !l.available() {
+			return
+		}
+		window := time.Now().Truncate(l.duration)
+		key := fmt.Sprintf("%s:%s:%d", keyPrefix, host, window.UnixNano())
+
+		opCtx, cancel := context.WithTimeout(ctx, coordinatorTimeout)
+		count, err := l.store.Incr(opCtx, key, 2*l.duration)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil {
+				l.markUnavailable(err)
+			}
+			return
+		}
+		if count <= l.limit {
+			return
+		}
+		// budget of the window is exhausted, wait for the next one
+		timer := time.NewTimer(time.Until(window.Add(l.duration)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// available returns true if the coordinator is expected to be reachable
+func (l *Limiter) available() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return time.Now().After(l.unavailableUntil)
+}
+
+// markUnavailable falls back to local limiting until the coordinator is retried
+func (l *Limiter) markUnavailable(err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if time.Now().Before(l.unavailableUntil) {
+		return
+	}
+	l.unavailableUntil = time.Now().Add(retryInterval)
+	gologger.Warning().Msgf("Rate limit coordinator unreachable, falling back to local rate limit for %s: %s\n", retryInterval, err)
+}
+
+// Close closes the connection to the coordinator
+func (l *Limiter) Close() error {
+	return l.store.Close()
+}
+
+// hostOf returns the host of the input used as budget key
+func hostOf(input string) string {
+	if parsed, err := urlutil.Parse(input); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(input); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(input)
+}
+
+// redisStore is a store of counters in redis
+type redisStore struct {
+	client *redis.Client
+}
+
+func (r *redisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *redisStore) Close() error {
+	return r.client.Close()
+}
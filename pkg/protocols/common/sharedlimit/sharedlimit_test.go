@@ -0,0 +1,68 @@
+package sharedlimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	mutex    sync.Mutex
+	counters map[string]int64
+	err      error
+	calls    int
+}
+
+func (m *memoryStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls++
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.counters[key]++
+	return m.counters[key], nil
+}
+
+func (m *memoryStore) Close() error { return nil }
+
+func TestLimiterSharedBudget(t *testing.T) {
+	store := &memoryStore{counters: make(map[string]int64)}
+	// two processes sharing the same store
+	first, err := NewWithStore(store, 2, time.Hour)
+	require.Nil(t, err, "could not create limiter")
+	second, err := NewWithStore(store, 2, time.Hour)
+	require.Nil(t, err, "could not create limiter")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	first.Take(ctx, "https://example.com/a")
+	second.Take(ctx, "example.com:443")
+	// other hosts have their own budget
+	first.Take(ctx, "https://other.com")
+	require.Less(t, time.Since(start), 100*time.Millisecond, "budget was exhausted early")
+
+	// budget of the host is exhausted so take blocks until cancelled
+	first.Take(ctx, "http://EXAMPLE.com")
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "budget was not shared")
+}
+
+func TestLimiterFallback(t *testing.T) {
+	store := &memoryStore{counters: make(map[string]int64), err: errors.New("connection refused")}
+	limiter, err := NewWithStore(store, 1, time.Second)
+	require.Nil(t, err, "could not create limiter")
+
+	limiter.Take(context.Background(), "example.com")
+	limiter.Take(context.Background(), "example.com")
+	require.Equal(t, 1, store.calls, "unreachable coordinator was not skipped")
+
+	_, err = NewWithStore(store, 0, time.Second)
+	require.NotNil(t, err, "created limiter without limit")
+}
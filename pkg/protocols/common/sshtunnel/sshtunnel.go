@@ -0,0 +1,199 @@
+// Package sshtunnel routes connections of requests through an ssh jump host
+// so that targets only reachable from inside a segmented network can be
+// scanned without a separate proxy setup.
+//
+// A single ssh connection is established to the jump host when the scan
+// starts and is reused by all requests: each connection of a request is a
+// local forward (direct-tcpip channel) to its address through the jump host.
+package sshtunnel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+const (
+	// defaultPort is the port of jump hosts specified without a port
+	defaultPort = 22
+	// defaultTimeout is the timeout of establishing the ssh connection
+	defaultTimeout = 15 * time.Second
+)
+
+// Tunnel is an established ssh connection to a jump host
+type Tunnel struct {
+	address string
+	client  *ssh.Client
+}
+
+// ParseJumpHost parses a jump host of the form [user@]host[:port] returning
+// the user (empty if not specified) and the address of the jump host
+func ParseJumpHost(value string) (string, string, error) {
+	var user string
+	host := strings.TrimSpace(value)
+	if index := strings.LastIndex(host, "@"); index != -1 {
+		user, host = host[:index], host[index+1:]
+		if user == "" {
+			return "", "", errors.Errorf("invalid ssh jump host %s: empty user", value)
+		}
+	}
+	if host == "" {
+		return "", "", errors.Errorf("invalid ssh jump host %s: empty host", value)
+	}
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		// host without port (ipv6 addresses must be bracketed with a port)
+		if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+			return "", "", errors.Errorf("invalid ssh jump host %s: ipv6 addresses require brackets", value)
+		}
+		return user, net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(defaultPort)), nil
+	}
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		return "", "", errors.Errorf("invalid ssh jump host %s: invalid port %s", value, port)
+	}
+	return user, net.JoinHostPort(hostname, port), nil
+}
+
+// NewFromOptions establishes the ssh connection to the jump host of the
+// options or returns nil if no jump host is configured
+func NewFromOptions(options *types.Options) (*Tunnel, error) {
+	if options.SSHJumpHost == "" {
+		return nil, nil
+	}
+	user, address, err := ParseJumpHost(options.SSHJumpHost)
+	if err != nil {
+		return nil, err
+	}
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+	if user == "" {
+		return nil, errors.Errorf("no user specified for ssh jump host %s", options.SSHJumpHost)
+	}
+
+	var auth []ssh.AuthMethod
+	if options.SSHJumpKey != "" {
+		signer, err := readPrivateKey(options.SSHJumpKey)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if options.SSHJumpPassword != "" {
+		auth = append(auth, ssh.Password(options.SSHJumpPassword))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("ssh jump host requires a private key (-sjk) or a password (-sjp)")
+	}
+
+	hostKeyCallback, err := hostKeyCallbackFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.SSHJumpInsecure {
+		gologger.Warning().Msgf("Host key of ssh jump host %s is not verified", address)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         defaultTimeout,
+	}
+	return Dial(address, config)
+}
+
+// Dial establishes the ssh connection to the jump host at address
+func Dial(address string, config *ssh.ClientConfig) (*Tunnel, error) {
+	conn, err := net.DialTimeout("tcp", address, config.Timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to ssh jump host %s", address)
+	}
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		_ = conn.Close()
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			return nil, errors.Wrapf(err, "could not verify host key of ssh jump host %s", address)
+		}
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return nil, errors.Wrapf(err, "could not authenticate to ssh jump host %s as %s", address, config.User)
+		}
+		return nil, errors.Wrapf(err, "could not establish ssh connection to jump host %s", address)
+	}
+	return &Tunnel{address: address, client: ssh.NewClient(sshConn, channels, requests)}, nil
+}
+
+// Dial dials address through the jump host. Only tcp connections can
+// be forwarded over ssh.
+func (t *Tunnel) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("could not dial %s through ssh jump host %s: unsupported network %s", address, t.address, network)
+	}
+	conn, err := t.client.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not dial %s through ssh jump host %s", address, t.address)
+	}
+	return conn, nil
+}
+
+// Close closes the ssh connection to the jump host
+func (t *Tunnel) Close() error {
+	if t == nil {
+		return nil
+	}
+	return t.client.Close()
+}
+
+// hostKeyCallbackFromOptions returns the callback verifying the host key of
+// the jump host against the known hosts file of the options (or of the user
+// if not specified) unless verification is disabled
+func hostKeyCallbackFromOptions(options *types.Options) (ssh.HostKeyCallback, error) {
+	if options.SSHJumpInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	knownHosts := options.SSHJumpKnownHosts
+	if knownHosts == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get home directory for ssh known hosts, use -sjkh to specify a known hosts file")
+		}
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read ssh known hosts %s, use -sjkh to specify a known hosts file or -sji to disable verification", knownHosts)
+	}
+	return callback, nil
+}
+
+// readPrivateKey reads and parses the private key at path
+func readPrivateKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read ssh private key %s", path)
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, errors.Errorf("ssh private key %s is passphrase protected, use an unencrypted key", path)
+		}
+		return nil, errors.Wrapf(err, "could not parse ssh private key %s", path)
+	}
+	return signer, nil
+}
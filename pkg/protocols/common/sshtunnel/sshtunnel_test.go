@@ -0,0 +1,167 @@
+package sshtunnel
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func TestParseJumpHost(t *testing.T) {
+	tests := []struct {
+		value   string
+		user    string
+		address string
+		err     bool
+	}{
+		{value: "bastion.internal", address: "bastion.internal:22"},
+		{value: "ops@bastion.internal:2222", user: "ops", address: "bastion.internal:2222"},
+		{value: "ops@[::1]:22", user: "ops", address: "[::1]:22"},
+		{value: "[::1]", address: "[::1]:22"},
+		{value: "::1", err: true},
+		{value: "@bastion", err: true},
+		{value: "ops@", err: true},
+		{value: "bastion:99999", err: true},
+	}
+	for _, test := range tests {
+		user, address, err := ParseJumpHost(test.value)
+		if test.err {
+			require.Error(t, err, "could not reject invalid jump host %s", test.value)
+			continue
+		}
+		require.Nil(t, err, "could not parse jump host %s", test.value)
+		require.Equal(t, test.user, user, "could not get user of %s", test.value)
+		require.Equal(t, test.address, address, "could not get address of %s", test.value)
+	}
+}
+
+func TestHostKeyCallbackFromOptions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	_, err := hostKeyCallbackFromOptions(&types.Options{})
+	require.ErrorContains(t, err, filepath.Join(home, ".ssh", "known_hosts"), "could not default to known hosts of the user")
+
+	_, err = hostKeyCallbackFromOptions(&types.Options{SSHJumpInsecure: true})
+	require.Nil(t, err, "could not disable host key verification")
+
+	knownHosts := filepath.Join(home, "known_hosts")
+	require.Nil(t, os.WriteFile(knownHosts, nil, 0600), "could not write known hosts")
+	_, err = hostKeyCallbackFromOptions(&types.Options{SSHJumpKnownHosts: knownHosts})
+	require.Nil(t, err, "could not read known hosts")
+}
+
+func TestTunnelDial(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+
+	address := startSSHServer(t, "ops", "secret")
+
+	_, err := Dial(address, &ssh.ClientConfig{User: "ops", Auth: []ssh.AuthMethod{ssh.Password("wrong")}, HostKeyCallback: ssh.InsecureIgnoreHostKey(), Timeout: time.Second})
+	require.ErrorContains(t, err, "could not authenticate to ssh jump host", "could not report auth failure")
+
+	tunnel, err := Dial(address, &ssh.ClientConfig{User: "ops", Auth: []ssh.AuthMethod{ssh.Password("secret")}, HostKeyCallback: ssh.InsecureIgnoreHostKey(), Timeout: time.Second})
+	require.Nil(t, err, "could not establish tunnel")
+	defer tunnel.Close()
+
+	client := &http.Client{Transport: &http.Transport{DialContext: tunnel.Dial}}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(target.URL)
+		require.Nil(t, err, "could not get target through tunnel")
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.Equal(t, "internal", string(body), "could not get target response")
+	}
+
+	_, err = tunnel.Dial(context.Background(), "udp", "127.0.0.1:53")
+	require.ErrorContains(t, err, "unsupported network", "could not reject udp network")
+}
+
+// startSSHServer starts an ssh server accepting the password of user and
+// forwarding direct-tcpip channels returning its address
+func startSSHServer(t *testing.T, user, password string) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err, "could not generate host key")
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	require.Nil(t, err, "could not create host key signer")
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, value []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && string(value) == password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+		// host string, port uint32, origin host string, origin port uint32
+		data := newChannel.ExtraData()
+		length := binary.BigEndian.Uint32(data)
+		host := string(data[4 : 4+length])
+		port := binary.BigEndian.Uint32(data[4+length:])
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+		go func() {
+			_, _ = io.Copy(channel, target)
+			_ = channel.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(target, channel)
+			_ = target.Close()
+		}()
+	}
+}
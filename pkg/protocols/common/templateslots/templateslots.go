@@ -0,0 +1,87 @@
+// Package templateslots bounds the number of in-flight requests of each
+// template so that a single expensive template (ex: fuzzing with a huge
+// wordlist) gets a bounded share of the workers of the scan instead of
+// starving the other templates.
+//
+// Slots are taken in addition to the global and per-host limits: a request
+// first waits for a slot of its template and then takes from the rate
+// limiters, so the bulk-size and payload-concurrency of the scan remain the
+// upper bounds and the template concurrency only lowers them per template.
+package templateslots
+
+import (
+	"context"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// Limiter limits the in-flight requests of each template. A nil limiter
+// never limits requests.
+type Limiter struct {
+	max int
+
+	mutex sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// New creates a limiter allowing max in-flight requests per template
+func New(max int) *Limiter {
+	return &Limiter{max: max, slots: make(map[string]chan struct{})}
+}
+
+// NewFromOptions creates a limiter from the options of a scan or returns
+// nil if no template concurrency is configured
+func NewFromOptions(options *types.Options) *Limiter {
+	if options.TemplateConcurrency <= 0 {
+		return nil
+	}
+	return New(options.TemplateConcurrency)
+}
+
+// Acquire waits for a slot of the template returning the function
+// releasing it. False is returned if the context is done.
+func (l *Limiter) Acquire(ctx context.Context, templateID string) (func(), bool) {
+	if l == nil {
+		return func() {}, true
+	}
+	slots := l.slotsOf(templateID)
+	select {
+	case slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-slots }) }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// slotsOf returns the slots of the template creating them if needed
+func (l *Limiter) slotsOf(templateID string) chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	slots, ok := l.slots[templateID]
+	if !ok {
+		slots = make(chan struct{}, l.max)
+		l.slots[templateID] = slots
+	}
+	return slots
+}
+
+// InFlight returns the number of in-flight requests of templates having
+// requests in flight
+func (l *Limiter) InFlight() map[string]int {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	inFlight := make(map[string]int)
+	for templateID, slots := range l.slots {
+		if count := len(slots); count > 0 {
+			inFlight[templateID] = count
+		}
+	}
+	return inFlight
+}
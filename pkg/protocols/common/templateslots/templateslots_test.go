@@ -0,0 +1,41 @@
+package templateslots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := New(2)
+
+	first, ok := limiter.Acquire(context.Background(), "fuzz-heavy")
+	require.True(t, ok, "could not acquire slot")
+	second, ok := limiter.Acquire(context.Background(), "fuzz-heavy")
+	require.True(t, ok, "could not acquire slot")
+	other, ok := limiter.Acquire(context.Background(), "other")
+	require.True(t, ok, "could not acquire slot of other template")
+	require.Equal(t, map[string]int{"fuzz-heavy": 2, "other": 1}, limiter.InFlight(), "could not get in-flight requests")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, ok = limiter.Acquire(ctx, "fuzz-heavy")
+	require.False(t, ok, "acquired slot above template concurrency")
+
+	first()
+	first()
+	third, ok := limiter.Acquire(context.Background(), "fuzz-heavy")
+	require.True(t, ok, "could not acquire released slot")
+	second()
+	third()
+	other()
+	require.Empty(t, limiter.InFlight(), "got in-flight requests after release")
+
+	var disabled *Limiter
+	release, ok := disabled.Acquire(context.Background(), "fuzz-heavy")
+	require.True(t, ok, "could not acquire slot of disabled limiter")
+	release()
+	require.Nil(t, disabled.InFlight())
+}
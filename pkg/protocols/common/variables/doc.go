@@ -3,7 +3,7 @@ package variables
 // There are total 5 sources of variables
 // 1. VariablesMap - Variables defined in the template  (available at Request.options.Variables in protocols)
 // 2. PayloadsMap  - Payloads defined in the template   (available at Request.generator in protocols)
-// 3. OptionsMap   - Variables passed using CLI Options (+ Env) (available at generators.BuildPayloadFromOptions)
+// 3. OptionsMap   - Variables passed using CLI Options (+ Env + per-input vars) (available at ExecutorOptions.BuildPayloadFromOptions)
 // 4. DynamicMap   - Variables Obtained by extracting data from templates  (available at Request.ExecuteWithResults + merged with previous internalEvent)
 // 5. ProtocolMap  - Variables generated by Evaluation Request / Responses of xyz protocol (available in Request.Make)
 // 6. ConstantsMap  - Constants defined in the template (available at Request.options.Constants in protocols)
@@ -102,7 +102,7 @@ func (variables *Variable) EvaluateWithInteractsh(values map[string]interface{},
 			return
 		}
 		valueString := types.ToString(value)
-		if strings.Contains(valueString, "interactsh-url") {
+		if strings.Contains(valueString, "interactsh-url") || interactsh.HasXXEMarkers(valueString) {
 			valueString, interactURLs = interact.Replace(valueString, interactURLs)
 		}
 		combined := generators.MergeMaps(values, result)
@@ -137,7 +137,7 @@ func (variables *Variable) checkForLazyEval() bool {
 		}
 		// this is a hotfix and not the best way to do it
 		// will be refactored once we move scan state to scanContext (see: https://github.com/projectdiscovery/nuclei/issues/4631)
-		if strings.Contains(types.ToString(value), "interactsh-url") {
+		if valueString := types.ToString(value); strings.Contains(valueString, "interactsh-url") || interactsh.HasXXEMarkers(valueString) {
 			variables.LazyEval = true
 			return
 		}
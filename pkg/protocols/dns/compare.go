@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns/dnsclientpool"
+)
+
+// resolverAnswer is the answer of a resolver queried in compare mode
+type resolverAnswer struct {
+	resolver string
+	response *dns.Msg
+	err      error
+}
+
+// queryResolvers sends the request to each of the resolvers of the request
+// independently. Failing resolvers do not prevent querying the others.
+func (request *Request) queryResolvers(ctx context.Context, domain string, compiledRequest *dns.Msg, metadata map[string]interface{}) ([]resolverAnswer, error) {
+	resolvers, err := request.evaluateResolvers(metadata)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]resolverAnswer, 0, len(resolvers))
+	for i, resolver := range resolvers {
+		if i > 0 {
+			request.options.RateLimitTake(ctx, domain)
+		}
+		answer := resolverAnswer{resolver: resolver}
+		client, err := dnsclientpool.Get(request.options.Options, &dnsclientpool.Configuration{
+			Retries:   request.Retries,
+			Resolvers: []string{resolver},
+		})
+		if err != nil {
+			answer.err = err
+		} else {
+			answer.response, answer.err = client.Do(compiledRequest.Copy())
+		}
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+// firstResponse returns the first successful response of the answers
+func firstResponse(answers []resolverAnswer) (*dns.Msg, error) {
+	var err error
+	for _, answer := range answers {
+		if answer.err == nil && answer.response != nil {
+			return answer.response, nil
+		}
+		err = answer.err
+	}
+	if err == nil {
+		err = errors.New("no resolver returned a response")
+	}
+	return nil, err
+}
+
+// compareValues returns the indexed variables of the answers of resolvers
+// along with whether the records of successful answers are consistent
+func compareValues(answers []resolverAnswer) map[string]interface{} {
+	values := make(map[string]interface{}, len(answers)*4+1)
+	consistent := true
+	var reference []string
+	var hasReference bool
+	for i, answer := range answers {
+		index := i + 1
+		values[fmt.Sprintf("resolver_%d", index)] = answer.resolver
+		if answer.err != nil || answer.response == nil {
+			errorMessage := "no response"
+			if answer.err != nil {
+				errorMessage = answer.err.Error()
+			}
+			values[fmt.Sprintf("error_%d", index)] = errorMessage
+			values[fmt.Sprintf("rcode_%d", index)] = -1
+			values[fmt.Sprintf("answer_%d", index)] = ""
+			continue
+		}
+		values[fmt.Sprintf("error_%d", index)] = ""
+		values[fmt.Sprintf("rcode_%d", index)] = answer.response.Rcode
+		values[fmt.Sprintf("answer_%d", index)] = rrToString(answer.response.Answer)
+
+		records := normalizeRecords(answer.response)
+		if !hasReference {
+			reference, hasReference = records, true
+			continue
+		}
+		if !equalRecords(reference, records) {
+			consistent = false
+		}
+	}
+	values["answers_consistent"] = consistent && hasReference
+	return values
+}
+
+// normalizeRecords returns the sorted answer records of a response along with
+// its rcode ignoring ttl values which differ between resolver caches
+func normalizeRecords(response *dns.Msg) []string {
+	records := make([]string, 0, len(response.Answer)+1)
+	records = append(records, dns.RcodeToString[response.Rcode])
+	for _, rr := range response.Answer {
+		copied := dns.Copy(rr)
+		copied.Header().Ttl = 0
+		records = append(records, copied.String())
+	}
+	sort.Strings(records[1:])
+	return records
+}
+
+func equalRecords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareValues(t *testing.T) {
+	newResponse := func(ttl uint32, ips ...string) *dns.Msg {
+		resp := new(dns.Msg)
+		for _, ip := range ips {
+			resp.Answer = append(resp.Answer, &dns.A{A: net.ParseIP(ip), Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl}})
+		}
+		return resp
+	}
+
+	values := compareValues([]resolverAnswer{
+		{resolver: "1.1.1.1:53", response: newResponse(300, "1.2.3.4", "5.6.7.8")},
+		{resolver: "8.8.8.8:53", response: newResponse(60, "5.6.7.8", "1.2.3.4")},
+		{resolver: "10.0.0.1:53", err: net.ErrClosed},
+	})
+	require.Equal(t, true, values["answers_consistent"], "could not ignore ttl and order of records")
+	require.Equal(t, "8.8.8.8:53", values["resolver_2"], "could not set resolver")
+	require.Equal(t, dns.RcodeSuccess, values["rcode_1"], "could not set rcode")
+	require.Contains(t, values["answer_1"], "1.2.3.4", "could not set answer")
+	require.Equal(t, net.ErrClosed.Error(), values["error_3"], "could not set resolver error")
+	require.Equal(t, "", values["error_1"], "set error for successful resolver")
+
+	values = compareValues([]resolverAnswer{
+		{resolver: "1.1.1.1:53", response: newResponse(300, "1.2.3.4")},
+		{resolver: "10.0.0.1:53", response: newResponse(300, "10.0.0.5")},
+	})
+	require.Equal(t, false, values["answers_consistent"], "could not detect inconsistent answers")
+
+	response, err := firstResponse([]resolverAnswer{{err: net.ErrClosed}, {response: newResponse(300, "1.2.3.4")}})
+	require.Nil(t, err, "could not get first successful response")
+	require.Len(t, response.Answer, 1, "could not get first successful response")
+
+	_, err = firstResponse([]resolverAnswer{{err: net.ErrClosed}})
+	require.NotNil(t, err, "got response when all resolvers failed")
+}
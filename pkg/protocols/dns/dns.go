@@ -94,28 +94,45 @@ type Request struct {
 	// description: |
 	//   Recursion determines if resolver should recurse all records to get fresh results.
 	Recursion *bool `yaml:"recursion,omitempty" json:"recursion,omitempty" jsonschema:"title=recurse all servers,description=Recursion determines if resolver should recurse all records to get fresh results"`
-	// Resolvers to use for the dns requests
+	// description: |
+	//   Resolvers to use for the dns requests of the template. Resolvers of the
+	//   template take precedence over resolvers passed using cli options.
+	// examples:
+	//   - value: >
+	//       []string{"1.1.1.1:53", "10.0.0.53:53"}
 	Resolvers []string `yaml:"resolvers,omitempty" json:"resolvers,omitempty" jsonschema:"title=Resolvers,description=Define resolvers to use within the template"`
+	// description: |
+	//   CompareResolvers sends the request to each of the resolvers independently
+	//   for detecting inconsistent DNS views (ex: split-horizon).
+	//
+	//   Answers of each resolver are exposed as indexed `resolver_N`, `rcode_N`,
+	//   `answer_N` and `error_N` variables (starting at 1) and `answers_consistent`
+	//   is true if all resolvers returned the same records. Failing resolvers only
+	//   set their `error_N` variable. Regular variables are populated from the
+	//   first successful answer.
+	CompareResolvers bool `yaml:"compare-resolvers,omitempty" json:"compare-resolvers,omitempty" jsonschema:"title=compare answers of resolvers,description=Query each resolver independently and expose their answers as indexed variables"`
 }
 
 // RequestPartDefinitions contains a mapping of request part definitions and their
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":   "ID of the template executed",
-	"template-info": "Info Block of the template executed",
-	"template-path": "Path of the template executed",
-	"host":          "Host is the input to the template",
-	"matched":       "Matched is the input which was matched upon",
-	"request":       "Request contains the DNS request in text format",
-	"type":          "Type is the type of request made",
-	"rcode":         "Rcode field returned for the DNS request",
-	"question":      "Question contains the DNS question field",
-	"extra":         "Extra contains the DNS response extra field",
-	"answer":        "Answer contains the DNS response answer field",
-	"ns":            "NS contains the DNS response NS field",
-	"raw,body,all":  "Raw contains the raw DNS response (default)",
-	"trace":         "Trace contains trace data for DNS request if enabled",
+	"template-id":        "ID of the template executed",
+	"template-info":      "Info Block of the template executed",
+	"template-path":      "Path of the template executed",
+	"host":               "Host is the input to the template",
+	"matched":            "Matched is the input which was matched upon",
+	"request":            "Request contains the DNS request in text format",
+	"type":               "Type is the type of request made",
+	"rcode":              "Rcode field returned for the DNS request",
+	"question":           "Question contains the DNS question field",
+	"extra":              "Extra contains the DNS response extra field",
+	"answer":             "Answer contains the DNS response answer field",
+	"ns":                 "NS contains the DNS response NS field",
+	"raw,body,all":       "Raw contains the raw DNS response (default)",
+	"trace":              "Trace contains trace data for DNS request if enabled",
+	"answers_consistent": "Whether all resolvers returned the same records (requires compare-resolvers)",
+	"<resolver_N>,<rcode_N>,<answer_N>,<error_N>": "Resolver, rcode, answer and error of the Nth resolver queried (requires compare-resolvers)",
 }
 
 func (request *Request) GetCompiledOperators() []*operators.Operators {
@@ -141,11 +158,8 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		recursion := true
 		request.Recursion = &recursion
 	}
-	dnsClientOptions := &dnsclientpool.Configuration{
-		Retries: request.Retries,
-	}
-	if len(request.Resolvers) > 0 {
-		dnsClientOptions.Resolvers = request.Resolvers
+	if request.CompareResolvers && len(request.Resolvers) < 2 {
+		return errors.New("compare-resolvers requires at least two resolvers")
 	}
 	// Create a dns client for the class
 	client, err := request.getDnsClient(options, nil)
@@ -157,6 +171,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errors.Wrap(err, "could not compile operators")
@@ -189,31 +204,37 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 }
 
 func (request *Request) getDnsClient(options *protocols.ExecutorOptions, metadata map[string]interface{}) (*retryabledns.Client, error) {
+	resolvers, err := request.evaluateResolvers(metadata)
+	if err != nil {
+		return nil, err
+	}
 	dnsClientOptions := &dnsclientpool.Configuration{
-		Retries: request.Retries,
+		Retries:   request.Retries,
+		Resolvers: resolvers,
 	}
-	if len(request.Resolvers) > 0 {
-		if len(request.Resolvers) > 0 {
-			for _, resolver := range request.Resolvers {
-				if expressions.ContainsUnresolvedVariables(resolver) != nil {
-					var err error
-					resolver, err = expressions.Evaluate(resolver, metadata)
-					if err != nil {
-						return nil, errors.Wrap(err, "could not resolve resolvers expressions")
-					}
-					dnsClientOptions.Resolvers = append(dnsClientOptions.Resolvers, resolver)
-				}
+	return dnsclientpool.Get(options.Options, dnsClientOptions)
+}
+
+// evaluateResolvers returns the resolvers of the request with expressions evaluated
+func (request *Request) evaluateResolvers(metadata map[string]interface{}) ([]string, error) {
+	resolvers := make([]string, 0, len(request.Resolvers))
+	for _, resolver := range request.Resolvers {
+		if expressions.ContainsUnresolvedVariables(resolver) != nil {
+			var err error
+			resolver, err = expressions.Evaluate(resolver, metadata)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not resolve resolvers expressions")
 			}
 		}
-		dnsClientOptions.Resolvers = request.Resolvers
+		resolvers = append(resolvers, resolver)
 	}
-	return dnsclientpool.Get(options.Options, dnsClientOptions)
+	return resolvers, nil
 }
 
 // Requests returns the total number of requests the YAML rule will perform
 func (request *Request) Requests() int {
 	if request.generator != nil {
-		payloadRequests := request.generator.NewIterator().Total()
+		payloadRequests := request.generator.Total()
 		return payloadRequests
 	}
 
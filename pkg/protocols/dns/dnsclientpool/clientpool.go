@@ -77,11 +77,12 @@ func Get(options *types.Options, configuration *Configuration) (*retryabledns.Cl
 	}
 	poolMutex.RUnlock()
 
+	// resolvers of the template take precedence over the global resolvers
 	resolvers := defaultResolvers
-	if len(options.InternalResolversList) > 0 {
-		resolvers = options.InternalResolversList
-	} else if len(configuration.Resolvers) > 0 {
+	if len(configuration.Resolvers) > 0 {
 		resolvers = configuration.Resolvers
+	} else if len(options.InternalResolversList) > 0 {
+		resolvers = options.InternalResolversList
 	}
 	client, err := retryabledns.New(resolvers, configuration.Retries)
 	if err != nil {
@@ -21,7 +21,7 @@ import (
 // Match matches a generic data response against a given matcher
 func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
 	item, ok := request.getMatchPart(matcher.Part, data)
-	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
+	if !ok && !matcher.IsPartless() {
 		return false, []string{}
 	}
 
@@ -42,6 +42,14 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(types.ToString(item)))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.CompareMatcher:
+		return matcher.Result(matcher.MatchCompare(data)), []string{}
+	case matchers.EntropyMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchEntropy(types.ToString(item)))
+	case matchers.JSONSchemaMatcher:
+		return matcher.MatchJSONSchema(types.ToString(item))
+	case matchers.ErrorPageMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchErrorPage(types.ToString(item)))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(types.ToString(item))), []string{}
 	}
@@ -62,6 +70,16 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(types.ToString(item))
+	case extractors.BinaryExtractor:
+		return extractor.ExtractBinary(types.ToString(item))
+	case extractors.JSONSchemaExtractor:
+		return extractor.ExtractJSONSchema(types.ToString(item))
+	case extractors.ErrorPageExtractor:
+		return extractor.ExtractErrorPage(types.ToString(item))
+	case extractors.CSSExtractor:
+		return extractor.ExtractCSS(types.ToString(item))
 	}
 	return nil
 }
@@ -54,7 +54,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 
 	vars := protocolutils.GenerateDNSVariables(domain)
 	// optionvars are vars passed from CLI or env variables
-	optionVars := generators.BuildPayloadFromOptions(request.options.Options)
+	optionVars := request.options.BuildPayloadFromOptions(input.MetaInput)
 	// merge with metadata (eg. from workflow context)
 	if request.options.HasTemplateCtx(input.MetaInput) {
 		vars = generators.MergeMaps(vars, metadata, optionVars, request.options.GetTemplateCtx(input.MetaInput).GetAll())
@@ -63,7 +63,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 	vars = generators.MergeMaps(vars, variablesMap, request.options.Constants)
 
 	// if request threads matches global payload concurrency we follow it
-	shouldFollowGlobal := request.Threads == request.options.Options.PayloadConcurrency
+	shouldFollowGlobal := request.Threads == request.options.PayloadConcurrency()
 
 	if request.generator != nil {
 		iterator := request.generator.NewIterator()
@@ -87,8 +87,8 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 			}
 
 			// resize check point - nop if there are no changes
-			if shouldFollowGlobal && swg.Size != request.options.Options.PayloadConcurrency {
-				if err := swg.Resize(input.Context(), request.options.Options.PayloadConcurrency); err != nil {
+			if shouldFollowGlobal && swg.Size != request.options.PayloadConcurrency() {
+				if err := swg.Resize(input.Context(), request.options.PayloadConcurrency()); err != nil {
 					return err
 				}
 			}
@@ -160,10 +160,25 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 		}
 	}
 
-	request.options.RateLimitTake()
+	releaseSlot, ok := request.options.AcquireTemplateSlot(input.Context())
+	if !ok {
+		return input.Context().Err()
+	}
+	request.options.RateLimitTake(input.Context(), domain)
 
 	// Send the request to the target servers
-	response, err := dnsClient.Do(compiledRequest)
+	var response *dns.Msg
+	var compareVars map[string]interface{}
+	if request.CompareResolvers {
+		var answers []resolverAnswer
+		if answers, err = request.queryResolvers(input.Context(), domain, compiledRequest, metadata); err == nil {
+			compareVars = compareValues(answers)
+			response, err = firstResponse(answers)
+		}
+	} else {
+		response, err = dnsClient.Do(compiledRequest)
+	}
+	releaseSlot()
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, domain, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -188,6 +203,9 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 
 	// Create the output event
 	outputEvent := request.responseToDSLMap(compiledRequest, response, domain, question, traceData)
+	for k, v := range compareVars {
+		outputEvent[k] = v
+	}
 	// expose response variables in proto_var format
 	// this is no-op if the template is not a multi protocol template
 	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
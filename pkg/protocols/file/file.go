@@ -105,6 +105,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	}
 	compiled := &request.Operators
 	compiled.ExcludeMatchers = options.ExcludeMatchers
+	compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 	compiled.TemplateID = options.TemplateID
 	if err := compiled.Compile(); err != nil {
 		return errors.Wrap(err, "could not compile operators")
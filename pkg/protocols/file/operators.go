@@ -15,7 +15,7 @@ import (
 // Match matches a generic data response again a given matcher
 func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
 	itemStr, ok := request.getMatchPart(matcher.Part, data)
-	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
+	if !ok && !matcher.IsPartless() {
 		return false, []string{}
 	}
 
@@ -30,6 +30,14 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(itemStr))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.CompareMatcher:
+		return matcher.Result(matcher.MatchCompare(data)), []string{}
+	case matchers.EntropyMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchEntropy(itemStr))
+	case matchers.JSONSchemaMatcher:
+		return matcher.MatchJSONSchema(itemStr)
+	case matchers.ErrorPageMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchErrorPage(itemStr))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(itemStr)), []string{}
 	}
@@ -54,6 +62,16 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractXPath(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(itemStr)
+	case extractors.BinaryExtractor:
+		return extractor.ExtractBinary(itemStr)
+	case extractors.JSONSchemaExtractor:
+		return extractor.ExtractJSONSchema(itemStr)
+	case extractors.ErrorPageExtractor:
+		return extractor.ExtractErrorPage(itemStr)
+	case extractors.CSSExtractor:
+		return extractor.ExtractCSS(itemStr)
 	}
 	return nil
 }
@@ -80,6 +80,9 @@ const (
 	// ActionWaitVisible waits until an element appears.
 	// name:waitvisible
 	ActionWaitVisible
+	// ActionRender injects a payload into an element and captures the rendered DOM.
+	// name:render
+	ActionRender
 	// limit
 	limit
 )
@@ -108,6 +111,7 @@ var ActionStringToAction = map[string]ActionType{
 	"debug":        ActionDebug,
 	"sleep":        ActionSleep,
 	"waitvisible":  ActionWaitVisible,
+	"render":       ActionRender,
 }
 
 // ActionToActionString converts an action from  internal representation to string
@@ -134,6 +138,7 @@ var ActionToActionString = map[ActionType]string{
 	ActionDebug:        "debug",
 	ActionSleep:        "sleep",
 	ActionWaitVisible:  "waitvisible",
+	ActionRender:       "render",
 }
 
 // GetSupportedActionTypes returns list of supported types
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// challengePollInterval is the interval the page is checked at while passing a challenge
+const challengePollInterval = 500 * time.Millisecond
+
+// PassChallenge navigates to the URL serving an anti-automation challenge and
+// waits for the browser to pass it, ie. until passed returns true for the html
+// of the page. The session of the page and the user agent of the browser are
+// returned once the challenge is passed.
+func (i *Instance) PassChallenge(URL string, timeout time.Duration, passed func(html string) bool) (*Session, string, error) {
+	page, err := i.engine.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = page.Close()
+	}()
+	page = page.Timeout(timeout)
+
+	if i.browser.customAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: i.browser.customAgent}); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := page.Navigate(URL); err != nil {
+		return nil, "", errorutil.NewWithErr(err).Msgf("could not navigate to %s", URL)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		_ = page.WaitLoad()
+		html, err := page.HTML()
+		if err != nil {
+			return nil, "", errorutil.NewWithErr(err).Msgf("could not get html of %s", URL)
+		}
+		if passed(html) {
+			break
+		}
+		if time.Now().Add(challengePollInterval).After(deadline) {
+			return nil, "", errorutil.New("challenge of %s was not passed within %s", URL, timeout)
+		}
+		time.Sleep(challengePollInterval)
+	}
+
+	userAgent, err := page.Eval(`() => navigator.userAgent`)
+	if err != nil {
+		return nil, "", errorutil.NewWithErr(err).Msgf("could not get user agent of browser")
+	}
+	session, err := (&Page{page: page}).Session(URL)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, userAgent.Value.Str(), nil
+}
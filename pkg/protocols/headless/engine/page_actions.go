@@ -121,6 +121,8 @@ func (p *Page) ExecuteActions(input *contextargs.Context, actions []*Action, var
 			err = p.SleepAction(act, outData)
 		case ActionWaitVisible:
 			err = p.WaitVisible(act, outData)
+		case ActionRender:
+			err = p.RenderElement(act, outData)
 		default:
 			continue
 		}
@@ -554,6 +556,58 @@ func (p *Page) FilesInput(act *Action, out map[string]string) error {
 	return nil
 }
 
+// RenderElement injects a payload into an input element (if a value is given),
+// waits for client-side rendering to settle and captures the rendered text and
+// html of the target element (default body) as <name>_text and <name>_html.
+func (p *Page) RenderElement(act *Action, out map[string]string) error {
+	timeout, err := getTimeout(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	if value := p.getActionArgWithDefaultValues(act, "value"); value != "" {
+		element, err := p.pageElementBy(act.Data)
+		if err != nil {
+			return errors.Wrap(err, errCouldNotGetElement)
+		}
+		if err = element.ScrollIntoView(); err != nil {
+			return errors.Wrap(err, errCouldNotScroll)
+		}
+		if err = element.Input(value); err != nil {
+			return errors.Wrap(err, "could not input element")
+		}
+		// notify frameworks listening on change events (ex: angularjs ng-model)
+		if _, err = element.Eval(`() => this.dispatchEvent(new Event("change", { bubbles: true }))`); err != nil {
+			return errors.Wrap(err, "could not dispatch change event")
+		}
+	}
+	// rendering not settling within timeout is not an error, capture what is rendered
+	_ = p.page.Timeout(timeout).WaitDOMStable(300*time.Millisecond, 0)
+
+	target := act.GetArg("target")
+	if target == "" {
+		target = "body"
+	}
+	element, err := p.page.Timeout(timeout).Element(target)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	text, err := element.Text()
+	if err != nil {
+		return errors.Wrap(err, "could not get element text node")
+	}
+	html, err := element.HTML()
+	if err != nil {
+		return errors.Wrap(err, "could not get element html")
+	}
+	name := act.Name
+	if name == "" {
+		name = "rendered"
+	}
+	out[name+"_text"] = text
+	out[name+"_html"] = html
+	return nil
+}
+
 // ExtractElement extracts from an element on the page.
 func (p *Page) ExtractElement(act *Action, out map[string]string) error {
 	element, err := p.pageElementBy(act.Data)
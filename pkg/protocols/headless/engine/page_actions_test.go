@@ -410,6 +410,36 @@ func TestActionExtract(t *testing.T) {
 	})
 }
 
+func TestActionRender(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Nuclei Test Page</title>
+			</head>
+			<body>
+				<input id="name" />
+				<div id="output"></div>
+				<script>
+					document.getElementById('name').addEventListener('change', function(e) {
+						document.getElementById('output').innerHTML = '<b>' + (7*7) + ' ' + e.target.value + '</b>';
+					});
+				</script>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionRender}, Data: map[string]string{"selector": "#name", "value": "nuclei", "target": "#output"}, Name: "ssti"},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "49 nuclei", out["ssti_text"], "could not get rendered text")
+		require.Equal(t, "<div id=\"output\"><b>49 nuclei</b></div>", out["ssti_html"], "could not get rendered html")
+	})
+}
+
 func TestActionSetMethod(t *testing.T) {
 	response := `
 		<html>
@@ -692,3 +722,31 @@ func getBoolFromEnv(key string, defaultValue bool) bool {
 	}
 	return strings.EqualFold(val, "true")
 }
+
+func TestPageSession(t *testing.T) {
+	response := `
+		<html>
+		<head>
+			<title>Nuclei Test Page</title>
+		</head>
+		<body>Nuclei Test Page</body>
+		<script>localStorage.setItem('token', 'local-token'); sessionStorage.setItem('csrf', 'session-csrf');</script>
+	</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+	testHeadless(t, actions, 20*time.Second, func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc"})
+		_, _ = fmt.Fprintln(w, response)
+	}, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		session, err := page.Session("")
+		require.Nil(t, err, "could not get session of page")
+		require.Len(t, session.Cookies, 1, "could not get cookies of page")
+		require.Equal(t, "abc", session.Cookies[0].Value, "could not get cookie value")
+		require.Equal(t, map[string]string{"token": "local-token"}, session.LocalStorage, "could not get local storage")
+		require.Equal(t, map[string]string{"csrf": "session-csrf"}, session.SessionStorage, "could not get session storage")
+	})
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/go-rod/rod/lib/proto"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Session contains the session state of a page after its actions
+// (ex: a login) used for authenticating subsequent http requests
+type Session struct {
+	// Cookies are the browser cookies applicable to the url of the session
+	Cookies []*proto.NetworkCookie
+	// LocalStorage contains the localStorage items of the page
+	LocalStorage map[string]string
+	// SessionStorage contains the sessionStorage items of the page
+	SessionStorage map[string]string
+}
+
+// Session returns the session state of the page for the URL (or the
+// current URL of the page if empty)
+func (p *Page) Session(URL string) (*Session, error) {
+	var urls []string
+	if URL != "" {
+		urls = append(urls, URL)
+	}
+	cookies, err := p.page.Cookies(urls)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not get cookies of page")
+	}
+	session := &Session{Cookies: cookies}
+	if session.LocalStorage, err = p.webStorage("localStorage"); err != nil {
+		return nil, err
+	}
+	if session.SessionStorage, err = p.webStorage("sessionStorage"); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// webStorage returns the items of the web storage (localStorage or sessionStorage) of the page
+func (p *Page) webStorage(name string) (map[string]string, error) {
+	result, err := p.page.Eval(`(name) => { try { return JSON.stringify(Object.assign({}, window[name])) } catch (e) { return "{}" } }`, name)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not get %s of page", name)
+	}
+	items := make(map[string]string)
+	if err := json.Unmarshal([]byte(result.Value.Str()), &items); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse %s of page", name)
+	}
+	return items, nil
+}
@@ -71,20 +71,33 @@ type Request struct {
 	// description: |
 	//   DisableCookie is an optional setting that disables cookie reuse
 	DisableCookie bool `yaml:"disable-cookie,omitempty" json:"disable-cookie,omitempty" jsonschema:"title=optional disable cookie reuse,description=Optional setting that disables cookie reuse"`
+
+	// description: |
+	//   CaptureSession exposes the session of the page after the steps (ex: a
+	//   javascript heavy login) to extractors as `session_cookies` (cookie header
+	//   value), `local_storage` and `session_storage` (json) variables.
+	//
+	//   Combined with a per-host dynamic secret using this template, the session
+	//   is injected into the http (and fuzzing) requests of the host.
+	CaptureSession bool `yaml:"capture-session,omitempty" json:"capture-session,omitempty" jsonschema:"title=capture session of the page,description=Expose cookies and web storage of the page after the steps to extractors"`
 }
 
 // RequestPartDefinitions contains a mapping of request part definitions and their
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":    "ID of the template executed",
-	"template-info":  "Info Block of the template executed",
-	"template-path":  "Path of the template executed",
-	"host":           "Host is the input to the template",
-	"matched":        "Matched is the input which was matched upon",
-	"type":           "Type is the type of request made",
-	"req":            "Headless request made from the client",
-	"resp,body,data": "Headless response received from client (default)",
+	"template-id":     "ID of the template executed",
+	"template-info":   "Info Block of the template executed",
+	"template-path":   "Path of the template executed",
+	"host":            "Host is the input to the template",
+	"matched":         "Matched is the input which was matched upon",
+	"type":            "Type is the type of request made",
+	"req":             "Headless request made from the client",
+	"resp,body,data":  "Headless response received from client (default)",
+	"session_cookies": "Cookies of the page after the steps in cookie header format (requires capture-session)",
+	"local_storage":   "localStorage items of the page after the steps as json (requires capture-session)",
+	"session_storage": "sessionStorage items of the page after the steps as json (requires capture-session)",
+	"session_cookie_<name>,local_storage_<key>,session_storage_<key>": "Value of a session cookie or web storage item by lowercased name with non alphanumeric characters replaced by underscores (requires capture-session)",
 }
 
 // Step is a headless protocol request step.
@@ -142,6 +155,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errors.Wrap(err, "could not compile operators")
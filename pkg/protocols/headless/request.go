@@ -52,7 +52,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 	}
 
 	vars := protocolutils.GenerateVariablesWithContextArgs(input, false)
-	payloads := generators.BuildPayloadFromOptions(request.options.Options)
+	payloads := request.options.BuildPayloadFromOptions(input.MetaInput)
 	// add templatecontext variables to varMap
 	values := generators.MergeMaps(vars, metadata, payloads)
 	if request.options.HasTemplateCtx(input.MetaInput) {
@@ -192,6 +192,14 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	for k, v := range out {
 		outputEvent[k] = v
 	}
+	if request.CaptureSession {
+		session, err := page.Session(navigatedURL)
+		if err != nil {
+			gologger.Warning().Msgf("[%s] Could not capture session of %s: %s\n", request.options.TemplateID, navigatedURL, err)
+		} else {
+			outputEvent = generators.MergeMaps(outputEvent, sessionValues(session))
+		}
+	}
 	for k, v := range payloads {
 		outputEvent[k] = v
 	}
@@ -208,6 +216,7 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			Context:        input.Context(),
 		})
 	}
 	if len(page.InteractshURLs) > 0 {
@@ -236,7 +245,7 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, payloads
 		}
 		newInput := input.Clone()
 		newInput.MetaInput.Input = gr.Request.URL.String()
-		if err := request.executeRequestWithPayloads(newInput, gr.DynamicValues, previous, callback); err != nil {
+		if err := request.executeRequestWithPayloads(newInput, fuzzDynamicValues(gr), previous, callback); err != nil {
 			return false
 		}
 		return true
@@ -266,6 +275,22 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, payloads
 	return nil
 }
 
+// fuzzDynamicValues returns dynamic values of a generated fuzz request along
+// with the fuzzed parameter and its value, allowing steps (ex: render) to
+// inject the same payload in the page.
+func fuzzDynamicValues(gr fuzz.GeneratedRequest) map[string]interface{} {
+	values := make(map[string]interface{}, len(gr.DynamicValues)+2)
+	for k, v := range gr.DynamicValues {
+		values[k] = v
+	}
+	if gr.Component == nil || gr.Parameter == "" {
+		return values
+	}
+	values["fuzz_parameter"] = gr.Parameter
+	values["fuzz_value"] = gr.FuzzedValue()
+	return values
+}
+
 // getLastNavigationURL returns last successfully navigated URL
 func (request *Request) getLastNavigationURLWithLog(reqLog map[string]string) string {
 	for i := len(request.Steps) - 1; i >= 0; i-- {
@@ -0,0 +1,37 @@
+package headless
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
+)
+
+// sessionNameRegex matches characters replaced in names of session variables
+var sessionNameRegex = regexp.MustCompile(`[^a-z0-9_]`)
+
+// sessionValues returns the variables of a page session for extractors
+func sessionValues(session *engine.Session) map[string]interface{} {
+	values := make(map[string]interface{})
+	cookies := make([]string, 0, len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		cookies = append(cookies, cookie.Name+"="+cookie.Value)
+		values["session_cookie_"+sessionVariableName(cookie.Name)] = cookie.Value
+	}
+	values["session_cookies"] = strings.Join(cookies, "; ")
+
+	for name, items := range map[string]map[string]string{"local_storage": session.LocalStorage, "session_storage": session.SessionStorage} {
+		encoded, _ := json.Marshal(items)
+		values[name] = string(encoded)
+		for key, value := range items {
+			values[name+"_"+sessionVariableName(key)] = value
+		}
+	}
+	return values
+}
+
+// sessionVariableName returns the normalized name of a cookie or storage item
+func sessionVariableName(name string) string {
+	return sessionNameRegex.ReplaceAllString(strings.ToLower(name), "_")
+}
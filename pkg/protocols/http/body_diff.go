@@ -0,0 +1,67 @@
+package http
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// lengthExpressionMarkers identify dsl matchers comparing response sizes
+var lengthExpressionMarkers = []string{"len(", "content_length"}
+
+// shouldCaptureBodyDiffs returns true if body diffs are enabled and the
+// request has size, compare or length based dsl matchers whose matches
+// usually stem from subtle response body differences
+func (request *Request) shouldCaptureBodyDiffs() bool {
+	if request.options.BodyDiff == nil || request.CompiledOperators == nil {
+		return false
+	}
+	for _, matcher := range request.CompiledOperators.Matchers {
+		switch matcher.GetType() {
+		case matchers.SizeMatcher, matchers.CompareMatcher:
+			return true
+		case matchers.DSLMatcher:
+			for _, expression := range matcher.DSL {
+				for _, marker := range lengthExpressionMarkers {
+					if strings.Contains(expression, marker) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// writeBodyDiff saves the baseline and matched fuzzing response bodies
+// along with their diff named by the template, host, parameter and payload.
+// Bodies are normalized if body normalization is enabled.
+func (request *Request) writeBodyDiff(gr fuzz.GeneratedRequest, input *contextargs.Context, baselineBody string, event *output.InternalWrappedEvent) {
+	if event == nil || event.InternalEvent == nil {
+		return
+	}
+	host := input.MetaInput.Input
+	if gr.Request != nil && gr.Request.URL.Host != "" {
+		host = gr.Request.URL.Host
+	}
+	name := strings.Join([]string{request.options.TemplateID, host, gr.Parameter, gr.FuzzedValue()}, "_")
+	body, ok := event.InternalEvent["normalized_body"]
+	if !ok {
+		body = event.InternalEvent["body"]
+	}
+	path, err := request.options.BodyDiff.Write(name, request.bodyNormalizer.Normalize(baselineBody), types.ToString(body))
+	if err != nil {
+		if !errors.Is(err, bodydiff.ErrLimitReached) {
+			gologger.Warning().Msgf("[%s] Could not write body diff for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		}
+		return
+	}
+	gologger.Verbose().Msgf("[%s] Saved body diff for %s to %s\n", request.options.TemplateID, input.MetaInput.Input, path)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// helperFiles caches the contents of files referenced by requests (ex: body
+// and golden files) by resolved path since the same fixture is often shared
+// by multiple templates
+var helperFiles = struct {
+	sync.RWMutex
+	items map[string]string
+}{items: make(map[string]string)}
+
+// loadBodyFile returns the contents of the body file of the request
+func (request *Request) loadBodyFile(options *protocols.ExecutorOptions) (string, error) {
+	body, err := loadHelperFile(options, request.BodyFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not load body file %s", request.BodyFile)
+	}
+	return body, nil
+}
+
+// resolveHelperFile returns the path of a file referenced by the template.
+// Relative paths are resolved from the directory of the template.
+func resolveHelperFile(options *protocols.ExecutorOptions, path string) string {
+	if !filepath.IsAbs(path) && options.TemplatePath != "" {
+		return filepath.Join(filepath.Dir(options.TemplatePath), path)
+	}
+	return path
+}
+
+// loadHelperFile returns the contents of a file referenced by the template
+func loadHelperFile(options *protocols.ExecutorOptions, path string) (string, error) {
+	path = resolveHelperFile(options, path)
+
+	helperFiles.RLock()
+	contents, ok := helperFiles.items[path]
+	helperFiles.RUnlock()
+	if ok {
+		return contents, nil
+	}
+
+	file, err := options.Options.LoadHelperFile(path, options.TemplatePath, options.Catalog)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	contents = string(data)
+
+	helperFiles.Lock()
+	helperFiles.items[path] = contents
+	helperFiles.Unlock()
+	return contents, nil
+}
+
+// bodyFileFuzzRequest returns the base fuzzing request of an url input with
+// the method and the interpolated body of the body file of the request
+func (request *Request) bodyFileFuzzRequest(input *contextargs.Context, parsed *urlutil.URL) (*retryablehttp.Request, error) {
+	values := generators.MergeMaps(protocolutils.GenerateVariables(parsed, false, contextargs.GenerateVariables(input)), request.options.BuildPayloadFromOptions(input.MetaInput))
+	values = generators.MergeMaps(values, request.options.Variables.Evaluate(values), request.options.Constants)
+
+	body, err := expressions.Evaluate(request.Body, values)
+	if err != nil {
+		return nil, ErrEvalExpression.Wrap(err)
+	}
+	if body, err = request.decodeRawBytes(body); err != nil {
+		return nil, err
+	}
+	method := request.Method.String()
+	if method == "" {
+		method = http.MethodGet
+	}
+	baseRequest, err := retryablehttp.NewRequestFromURL(method, parsed, body)
+	if err != nil {
+		return nil, err
+	}
+	for header, value := range request.Headers {
+		if value, err = expressions.Evaluate(value, values); err != nil {
+			return nil, ErrEvalExpression.Wrap(err)
+		}
+		baseRequest.Header.Set(header, value)
+	}
+	return baseRequest, nil
+}
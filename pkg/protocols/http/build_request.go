@@ -52,6 +52,20 @@ type generatedRequest struct {
 	dynamicValues        map[string]interface{}
 	interactshURLs       []string
 	customCancelFunction context.CancelFunc
+	// baselineHeaders are the response headers of baseline request used
+	// for detecting headers injected by fuzzing payloads (if enabled)
+	baselineHeaders http.Header
+	// fuzzedHeaders are the request headers fuzzed by the header component
+	// used for detecting their reflection (if cache poisoning variables are used)
+	fuzzedHeaders map[string]string
+	// bodyCompressed tracks if the request body was already gzip compressed
+	bodyCompressed bool
+	// paginated is true for requests of next pages followed by pagination
+	paginated bool
+	// resend is true for requests re-sent to verify a previous response (ex:
+	// match confirmations) or after passing a challenge which are never
+	// deduplicated or served from caches
+	resend bool
 	// requestURLPattern tracks unmodified request url pattern without values ( it is used for constant vuln_hash)
 	// ex: {{BaseURL}}/api/exp?param={{randstr}}
 	requestURLPattern string
@@ -168,6 +182,8 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 	if err != nil {
 		return nil, err
 	}
+	// prefix the path of the input with the environment specific base path (if any)
+	r.options.BasePaths.Rewrite(parsed)
 
 	// Non-Raw Requests ex `{{BaseURL}}/somepath` may or maynot have slash after variable and the same is the case for
 	// target url to avoid inconsistencies extra slash if exists has to removed from default variables
@@ -182,7 +198,7 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 	// contextargs generate extra vars that may/may not be available always (ex: "ip")
 	defaultReqVars := protocolutils.GenerateVariables(parsed, hasTrailingSlash, contextargs.GenerateVariables(input))
 	// optionvars are vars passed from CLI or env variables
-	optionVars := generators.BuildPayloadFromOptions(r.request.options.Options)
+	optionVars := r.request.options.BuildPayloadFromOptions(input.MetaInput)
 
 	variablesMap, interactURLs := r.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(defaultReqVars, optionVars), r.options.Interactsh)
 	if len(interactURLs) > 0 {
@@ -217,6 +233,9 @@ func (r *requestGenerator) Make(ctx context.Context, input *contextargs.Context,
 	}
 
 	if isRawRequest {
+		if reqData, err = r.request.decodeRawBytes(reqData); err != nil {
+			return nil, err
+		}
 		return r.generateRawRequest(ctx, reqData, parsed, finalVars, payloads)
 	}
 
@@ -260,6 +279,9 @@ func (r *requestGenerator) makeSelfContainedRequest(ctx context.Context, data st
 	// If the request is a raw request, get the URL from the request
 	// header and use it to make the request.
 	if isRawRequest {
+		if data, err = r.request.decodeRawBytes(data); err != nil {
+			return nil, err
+		}
 		// Get the hostname from the URL section to build the request.
 		reader := bufio.NewReader(strings.NewReader(data))
 	read_line:
@@ -337,21 +359,32 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 
 	var rawRequestData *raw.Request
 	var err error
+	// raw socket requests are sent as exact bytes like unsafe requests
+	unsafe := r.request.Unsafe || r.request.RawSocket
 	if r.request.SelfContained {
 		// in self contained requests baseURL is extracted from raw request itself
-		rawRequestData, err = raw.ParseRawRequest(rawRequest, r.request.Unsafe)
+		rawRequestData, err = raw.ParseRawRequest(rawRequest, unsafe)
 	} else {
-		rawRequestData, err = raw.Parse(rawRequest, baseURL, r.request.Unsafe, r.request.DisablePathAutomerge)
+		rawRequestData, err = raw.Parse(rawRequest, baseURL, unsafe, r.request.DisablePathAutomerge)
 	}
 	if err != nil {
 		return nil, errorutil.NewWithErr(err).Msgf("failed to parse raw request")
 	}
 
 	// Unsafe option uses rawhttp library
-	if r.request.Unsafe {
+	if unsafe {
 		if len(r.options.Options.CustomHeaders) > 0 {
 			_ = rawRequestData.TryFillCustomHeaders(r.options.Options.CustomHeaders)
 		}
+		if len(r.request.ConditionalHeaders) > 0 {
+			urlx, err := urlutil.ParseAbsoluteURL(rawRequestData.FullURL, true)
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("failed to parse url %v of raw request", rawRequestData.FullURL).WithTag("raw")
+			}
+			if err := r.request.applyConditionalRawHeaders(urlx, rawRequestData.Headers, finalVars); err != nil {
+				return nil, err
+			}
+		}
 		if rawRequestData.Data != "" && !stringsutil.EqualFoldAny(rawRequestData.Method, http.MethodHead, http.MethodGet) && rawRequestData.Headers["Transfer-Encoding"] != "chunked" {
 			rawRequestData.Headers["Content-Length"] = strconv.Itoa(len(rawRequestData.Data))
 		}
@@ -419,14 +452,21 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		if err != nil {
 			return nil, ErrEvalExpression.Wrap(err).Msgf("failed to evaluate while adding headers to request")
 		}
+		if value, err = r.request.decodeRawBytes(value); err != nil {
+			return nil, err
+		}
 		req.Header[header] = []string{value}
 		if header == "Host" {
 			req.Host = value
 		}
 	}
+	if err := r.request.applyConditionalHeaders(req, values); err != nil {
+		return nil, err
+	}
 
 	// In case of multiple threads the underlying connection should remain open to allow reuse
-	if r.request.Threads <= 0 && req.Header.Get("Connection") == "" && r.options.Options.ScanStrategy != scanstrategy.HostSpray.String() {
+	// as when connection behavior is tracked
+	if r.request.Threads <= 0 && !r.request.trackConnections && req.Header.Get("Connection") == "" && r.options.Options.ScanStrategy != scanstrategy.HostSpray.String() {
 		req.Close = true
 	}
 
@@ -440,6 +480,9 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		if err != nil {
 			return nil, ErrEvalExpression.Wrap(err)
 		}
+		if body, err = r.request.decodeRawBytes(body); err != nil {
+			return nil, err
+		}
 		bodyReader, err := readerutil.NewReusableReadCloser([]byte(body))
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create reusable reader for request body")
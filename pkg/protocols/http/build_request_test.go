@@ -2,6 +2,9 @@ package http
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -9,10 +12,12 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/basepath"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	urlutil "github.com/projectdiscovery/utils/url"
 )
 
 func TestMakeRequestFromModal(t *testing.T) {
@@ -50,6 +55,104 @@ func TestMakeRequestFromModal(t *testing.T) {
 	require.Equal(t, "username=test&password=pass", string(bodyBytes), "could not get correct request body")
 }
 
+func TestMakeRequestConditionalHeaders(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:      templateID,
+		Name:    "testing",
+		Path:    []string{"{{BaseURL}}"},
+		Method:  HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Headers: map[string]string{"Upgrade-Insecure-Requests": "1"},
+		ConditionalHeaders: []*ConditionalHeaders{
+			{Condition: `Scheme == "http"`, Remove: []string{"upgrade-insecure-requests"}},
+			{Condition: `Scheme == "https"`, Headers: map[string]string{"X-Origin": "{{Hostname}}"}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	build := func(input string) *generatedRequest {
+		generator := request.newGenerator(false)
+		inputData, payloads, _ := generator.nextValue()
+		req, err := generator.Make(context.Background(), contextargs.NewWithInput(context.Background(), input), inputData, payloads, map[string]interface{}{})
+		require.Nil(t, err, "could not make http request")
+		return req
+	}
+	req := build("http://example.com")
+	require.Empty(t, req.request.Header.Get("Upgrade-Insecure-Requests"), "could not remove header for http")
+	require.Empty(t, req.request.Header.Get("X-Origin"), "set header for http")
+
+	req = build("https://example.com")
+	require.Equal(t, "1", req.request.Header.Get("Upgrade-Insecure-Requests"), "removed header for https")
+	require.Equal(t, "example.com", req.request.Header.Get("X-Origin"), "could not set header for https")
+}
+
+func TestMakeRequestBasePath(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	paths, err := basepath.New([]string{"staging.example.com=/app/v2"})
+	require.Nil(t, err, "could not create base path map")
+	executerOpts.BasePaths = paths
+
+	build := func(request *Request, input string) *generatedRequest {
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+		generator := request.newGenerator(false)
+		inputData, payloads, _ := generator.nextValue()
+		req, err := generator.Make(context.Background(), contextargs.NewWithInput(context.Background(), input), inputData, payloads, map[string]interface{}{})
+		require.Nil(t, err, "could not make http request")
+		return req
+	}
+
+	request := &Request{ID: templateID, Name: "testing", Path: []string{"{{BaseURL}}/login"}, Method: HTTPMethodTypeHolder{MethodType: HTTPGet}}
+	require.Equal(t, "https://staging.example.com/app/v2/login", build(request, "https://staging.example.com").request.URL.String(), "could not prefix base path")
+	require.Equal(t, "https://prod.example.com/login", build(request, "https://prod.example.com").request.URL.String(), "prefixed base path of unmapped host")
+
+	request = &Request{ID: templateID, Name: "testing", Raw: []string{"GET /login HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n"}}
+	require.Equal(t, "https://staging.example.com/app/v2/login", build(request, "https://staging.example.com").request.URL.String(), "could not prefix base path of raw request")
+}
+
+func TestMakeRequestFromModalEncodedBody(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:       templateID,
+		Name:     "testing",
+		Path:     []string{"{{BaseURL}}/upload"},
+		Method:   HTTPMethodTypeHolder{MethodType: HTTPPost},
+		Body:     "00ff 0a{{hex_encode('end')}}",
+		Encoding: "hex",
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	generator := request.newGenerator(false)
+	inputData, payloads, _ := generator.nextValue()
+	req, err := generator.Make(context.Background(), contextargs.NewWithInput(context.Background(), "https://example.com"), inputData, payloads, map[string]interface{}{})
+	require.Nil(t, err, "could not make http request")
+	bodyBytes, _ := req.request.BodyBytes()
+	require.Equal(t, []byte("\x00\xff\nend"), bodyBytes, "could not get correct raw request body")
+}
+
 func TestMakeRequestFromModalTrimSuffixSlash(t *testing.T) {
 	options := testutils.DefaultOptions
 
@@ -217,3 +320,53 @@ func areUnique(elements []string) bool {
 	}
 	return true
 }
+
+func TestMakeRequestFromModalBodyFile(t *testing.T) {
+	options := testutils.DefaultOptions
+	options.AllowLocalFileAccess = true
+	defer func() { options.AllowLocalFileAccess = false }()
+
+	testutils.Init(options)
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "fixtures"), 0755)
+	require.Nil(t, err, "could not create fixtures directory")
+	err = os.WriteFile(filepath.Join(dir, "fixtures", "envelope.xml"), []byte("<soap:Envelope>\n  <host>{{Hostname}}</host>\n</soap:Envelope>\n"), 0644)
+	require.Nil(t, err, "could not write body file")
+
+	templateID := "testing-http"
+	request := &Request{
+		ID:       templateID,
+		Name:     "testing",
+		Path:     []string{"{{BaseURL}}/ws"},
+		Method:   HTTPMethodTypeHolder{MethodType: HTTPPost},
+		BodyFile: "fixtures/envelope.xml",
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Path: filepath.Join(dir, "template.yaml"),
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	generator := request.newGenerator(false)
+	inputData, payloads, _ := generator.nextValue()
+	req, err := generator.Make(context.Background(), contextargs.NewWithInput(context.Background(), "https://example.com"), inputData, payloads, map[string]interface{}{})
+	require.Nil(t, err, "could not make http request")
+	bodyBytes, _ := req.request.BodyBytes()
+	require.Equal(t, "<soap:Envelope>\n  <host>example.com</host>\n</soap:Envelope>\n", string(bodyBytes), "could not get interpolated body file")
+
+	parsed, err := urlutil.ParseAbsoluteURL("https://example.com/ws", true)
+	require.Nil(t, err, "could not parse url")
+	fuzzReq, err := request.bodyFileFuzzRequest(contextargs.NewWithInput(context.Background(), "https://example.com/ws"), parsed)
+	require.Nil(t, err, "could not make fuzzing base request")
+	bodyBytes, _ = fuzzReq.BodyBytes()
+	require.Equal(t, http.MethodPost, fuzzReq.Method, "could not get method of fuzzing base request")
+	require.Contains(t, string(bodyBytes), "<host>example.com</host>", "could not get body of fuzzing base request")
+
+	missing := &Request{ID: templateID, Path: []string{"{{BaseURL}}"}, BodyFile: "fixtures/missing.xml"}
+	require.NotNil(t, missing.Compile(executerOpts), "could compile request with missing body file")
+
+	both := &Request{ID: templateID, Path: []string{"{{BaseURL}}"}, Body: "a=b", BodyFile: "fixtures/envelope.xml"}
+	require.NotNil(t, both.Compile(executerOpts), "could compile request with body and body file")
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"strings"
+)
+
+// cachePoisoningVariables are the variables assessing cache poisoning
+// through the reflection of injected request headers
+var cachePoisoningVariables = []string{"is_cacheable", "reflected_header", "reflected_headers", "cache_status"}
+
+// usesCachePoisoning returns true if matchers or extractors of the request
+// run on or reference cache poisoning variables so that they are only
+// computed when needed
+func (request *Request) usesCachePoisoning() bool {
+	if request.CompiledOperators == nil {
+		return false
+	}
+	for _, matcher := range request.CompiledOperators.Matchers {
+		if referencesCachePoisoning(matcher.Part) || referencesCachePoisoning(matcher.DSL...) {
+			return true
+		}
+	}
+	for _, extractor := range request.CompiledOperators.Extractors {
+		if referencesCachePoisoning(extractor.Part) || referencesCachePoisoning(extractor.DSL...) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesCachePoisoning returns true if any expression references a cache poisoning variable
+func referencesCachePoisoning(expressions ...string) bool {
+	for _, expression := range expressions {
+		for _, variable := range cachePoisoningVariables {
+			if strings.Contains(expression, variable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// injectedRequestHeaders returns the request headers injected by the
+// request mapped to their values: the headers fuzzed by the header
+// component for fuzzing requests or the headers of the template otherwise.
+func injectedRequestHeaders(generatedRequest *generatedRequest) map[string]string {
+	if generatedRequest.fuzzedHeaders != nil {
+		return generatedRequest.fuzzedHeaders
+	}
+	if generatedRequest.original == nil || len(generatedRequest.original.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(generatedRequest.original.Headers))
+	for name := range generatedRequest.original.Headers {
+		switch {
+		case generatedRequest.request != nil:
+			headers[name] = generatedRequest.request.Header.Get(name)
+		case generatedRequest.rawRequest != nil:
+			headers[name] = generatedRequest.rawRequest.Headers[name]
+		}
+	}
+	return headers
+}
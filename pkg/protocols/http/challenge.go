@@ -0,0 +1,108 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/challenge"
+)
+
+// applyChallengeClearance adds the cookies and the user agent obtained by
+// passing the challenge of the host of the request returning true if the
+// host has a clearance
+func (request *Request) applyChallengeClearance(gr *generatedRequest) bool {
+	u := gr.cookieURL()
+	if request.options.Challenges == nil || u == nil {
+		return false
+	}
+	clearance := request.options.Challenges.Clearance(u.Hostname())
+	if clearance == nil {
+		return false
+	}
+	gr.addCookies(clearance.Cookies)
+	if clearance.UserAgent == "" {
+		return true
+	}
+	switch {
+	case gr.request != nil:
+		gr.request.Header.Set("User-Agent", clearance.UserAgent)
+	case gr.rawRequest != nil:
+		for k := range gr.rawRequest.Headers {
+			if strings.EqualFold(k, "User-Agent") {
+				delete(gr.rawRequest.Headers, k)
+			}
+		}
+		gr.rawRequest.Headers["User-Agent"] = clearance.UserAgent
+	}
+	return true
+}
+
+// handleChallenge detects challenge responses logging the first challenge
+// detected for a host and passes it with the headless browser if enabled.
+// True is returned if the challenge of the host is passed and hence the
+// request can be retried with the clearance.
+func (request *Request) handleChallenge(matchedURL, headers, body string) bool {
+	handler := request.options.Challenges
+	if handler == nil {
+		return false
+	}
+	name, detected := handler.Detect(headers, body)
+	if !detected {
+		return false
+	}
+	parsed, err := url.Parse(matchedURL)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	host := parsed.Hostname()
+	if handler.MarkDetected(host) {
+		gologger.Info().Msgf("[%s] Anti-automation challenge (%s) detected for %s\n", request.options.TemplateID, name, host)
+	}
+	if !request.options.Options.ChallengeSolve || request.options.Browser == nil {
+		return false
+	}
+
+	clearance, err := handler.Pass(host, func() (*challenge.Clearance, error) {
+		return request.passChallenge(handler, matchedURL)
+	})
+	if err != nil {
+		gologger.Warning().Msgf("[%s] Could not pass %s challenge of %s: %s\n", request.options.TemplateID, name, host, err)
+		return false
+	}
+	return clearance != nil
+}
+
+// passChallenge passes the challenge served at the url with the headless browser
+func (request *Request) passChallenge(handler *challenge.Handler, URL string) (*challenge.Clearance, error) {
+	instance, err := request.options.Browser.NewInstance()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = instance.Close()
+	}()
+
+	timeout := time.Duration(request.options.Options.PageTimeout) * time.Second
+	session, userAgent, err := instance.PassChallenge(URL, timeout, func(html string) bool {
+		_, detected := handler.Detect("", html)
+		return !detected
+	})
+	if err != nil {
+		return nil, err
+	}
+	clearance := &challenge.Clearance{UserAgent: userAgent}
+	for _, cookie := range session.Cookies {
+		clearance.Cookies = append(clearance.Cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	gologger.Info().Msgf("[%s] Passed challenge of %s with headless browser (%d cookies)\n", request.options.TemplateID, URL, len(clearance.Cookies))
+	return clearance, nil
+}
+
+// canRetryWithClearance returns true if the request can be sent again
+// once the challenge of its host is passed
+func (gr *generatedRequest) canRetryWithClearance() bool {
+	return !gr.original.Race && !gr.original.Pipeline && !gr.original.RawSocket
+}
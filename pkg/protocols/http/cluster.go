@@ -11,7 +11,7 @@ import (
 // are similar enough to be considered one and can be checked by
 // just adding the matcher/extractors for the request and the correct IDs.
 func (request *Request) CanCluster(other *Request) bool {
-	if len(request.Payloads) > 0 || len(request.Fuzzing) > 0 || len(request.Raw) > 0 || len(request.Body) > 0 || request.Unsafe || request.NeedsRequestCondition() || request.Name != "" {
+	if len(request.Payloads) > 0 || len(request.Fuzzing) > 0 || len(request.Raw) > 0 || len(request.Body) > 0 || request.BodyFile != "" || request.Unsafe || request.NeedsRequestCondition() || request.Name != "" || len(request.Prerequisites) > 0 || request.Pagination != nil || other.Pagination != nil {
 		return false
 	}
 	if request.Method != other.Method ||
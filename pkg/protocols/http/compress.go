@@ -0,0 +1,67 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strconv"
+
+	"github.com/projectdiscovery/utils/reader"
+)
+
+// compressBody gzip compresses the body of the generated request and sets
+// the content-encoding header. Requests without a body are left as is.
+func (gr *generatedRequest) compressBody() error {
+	if gr.bodyCompressed {
+		return nil
+	}
+	switch {
+	case gr.request != nil && gr.request.Body != nil:
+		data, err := io.ReadAll(gr.request.Body)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		compressed, err := gzipData(data)
+		if err != nil {
+			return err
+		}
+		body, err := reader.NewReusableReadCloser(compressed)
+		if err != nil {
+			return err
+		}
+		gr.request.Body = body
+		gr.request.ContentLength = int64(len(compressed))
+		gr.request.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+		gr.request.Header.Set("Content-Encoding", "gzip")
+	case gr.rawRequest != nil && gr.rawRequest.Data != "":
+		compressed, err := gzipData([]byte(gr.rawRequest.Data))
+		if err != nil {
+			return err
+		}
+		gr.rawRequest.Data = string(compressed)
+		if gr.rawRequest.Headers == nil {
+			gr.rawRequest.Headers = make(map[string]string)
+		}
+		gr.rawRequest.Headers["Content-Encoding"] = "gzip"
+	default:
+		return nil
+	}
+	gr.bodyCompressed = true
+	return nil
+}
+
+// gzipData returns gzip compressed data
+func gzipData(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
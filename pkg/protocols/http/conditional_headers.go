@@ -0,0 +1,126 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/Knetic/govaluate"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// ConditionalHeaders are headers applied to a request when the condition matches
+type ConditionalHeaders struct {
+	// description: |
+	//   Condition is the DSL expression evaluated for the request.
+	// examples:
+	//   - value: "\"Scheme == \\\"http\\\"\""
+	Condition string `yaml:"condition" json:"condition" jsonschema:"title=condition of the headers,description=DSL expression evaluated with the request url variables"`
+	// description: |
+	//   Headers are set when the condition matches.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers to set,description=Headers set when the condition matches"`
+	// description: |
+	//   Remove are header names removed when the condition matches.
+	Remove []string `yaml:"remove,omitempty" json:"remove,omitempty" jsonschema:"title=headers to remove,description=Header names removed when the condition matches"`
+
+	compiled *govaluate.EvaluableExpression
+}
+
+// Compile compiles the condition of the headers
+func (c *ConditionalHeaders) Compile() error {
+	if c.Condition == "" {
+		return errors.New("condition is required")
+	}
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(c.Condition, dsl.HelperFunctions)
+	if err != nil {
+		return errors.Wrap(err, "could not compile condition")
+	}
+	c.compiled = compiled
+	return nil
+}
+
+// matches returns true if the condition evaluates to true for the values
+func (c *ConditionalHeaders) matches(values map[string]interface{}) (bool, error) {
+	result, err := c.compiled.Evaluate(values)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not evaluate condition %s", c.Condition)
+	}
+	matched, _ := result.(bool)
+	return matched, nil
+}
+
+// conditionalHeaders returns the headers to set and remove for a request url
+// evaluating header values with the values.
+func (request *Request) conditionalHeaders(urlx *urlutil.URL, values map[string]interface{}) (map[string]string, []string, error) {
+	if len(request.ConditionalHeaders) == 0 {
+		return nil, nil, nil
+	}
+	// url variables of the request being built take precedence
+	values = generators.MergeMaps(values, protocolutils.GenerateVariables(urlx, false, nil))
+
+	set := make(map[string]string)
+	var remove []string
+	for _, conditional := range request.ConditionalHeaders {
+		matched, err := conditional.matches(values)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !matched {
+			continue
+		}
+		for header, value := range conditional.Headers {
+			value, err := expressions.Evaluate(value, values)
+			if err != nil {
+				return nil, nil, ErrEvalExpression.Wrap(err).Msgf("failed to evaluate conditional header %s", header)
+			}
+			set[header] = value
+		}
+		remove = append(remove, conditional.Remove...)
+	}
+	return set, remove, nil
+}
+
+// applyConditionalHeaders applies the matching conditional headers to the request
+func (request *Request) applyConditionalHeaders(req *retryablehttp.Request, values map[string]interface{}) error {
+	set, remove, err := request.conditionalHeaders(req.URL, values)
+	if err != nil {
+		return err
+	}
+	for header, value := range set {
+		req.Header[header] = []string{value}
+		if header == "Host" {
+			req.Host = value
+		}
+	}
+	for _, header := range remove {
+		for key := range req.Header {
+			if strings.EqualFold(key, header) {
+				delete(req.Header, key)
+			}
+		}
+	}
+	return nil
+}
+
+// applyConditionalRawHeaders applies the matching conditional headers to unsafe raw request headers
+func (request *Request) applyConditionalRawHeaders(urlx *urlutil.URL, headers map[string]string, values map[string]interface{}) error {
+	set, remove, err := request.conditionalHeaders(urlx, values)
+	if err != nil {
+		return err
+	}
+	for header, value := range set {
+		headers[header] = value
+	}
+	for _, header := range remove {
+		for key := range headers {
+			if strings.EqualFold(key, header) {
+				delete(headers, key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Confirmation statuses of matched fuzzing requests
+const (
+	confirmationConfirmed      = "confirmed"
+	confirmationFlaky          = "flaky"
+	confirmationControlMatched = "control-matched"
+)
+
+// shouldConfirmMatches returns true if matched fuzzing requests are re-sent for confirmation
+func (request *Request) shouldConfirmMatches() bool {
+	return (request.ConfirmMatches || request.options.Options.FuzzConfirm) && request.options.RequestDump == nil
+}
+
+// confirmMatch re-sends the matched fuzzing request (and the unmodified
+// request of the input if enabled) and returns the confirmation status
+func (request *Request) confirmMatch(input *contextargs.Context, state *fuzzInputState, confirmRequest *retryablehttp.Request, dynamicValues map[string]interface{}) string {
+	if !request.matchesRequest(input, confirmRequest, dynamicValues) {
+		return confirmationFlaky
+	}
+	if request.ConfirmControl && state.baseRequest != nil && request.matchesRequest(input, state.baseRequest.Clone(input.Context()), dynamicValues) {
+		return confirmationControlMatched
+	}
+	return confirmationConfirmed
+}
+
+// matchesRequest sends the request and returns true if any of its responses
+// matched. The request bypasses deduplication and caches as it re-sends an
+// already sent request.
+func (request *Request) matchesRequest(input *contextargs.Context, req *retryablehttp.Request, dynamicValues map[string]interface{}) bool {
+	request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+	request.options.Progress.AddToTotal(1)
+
+	var matched bool
+	generated := &generatedRequest{request: req, dynamicValues: dynamicValues, original: request, resend: true}
+	err := request.executeRequest(input, generated, dynamicValues, false, func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	}, 0)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] fuzz: could not send confirmation request for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+	}
+	return matched
+}
+
+// flagConfirmation adds the confirmation status to the results of the event.
+// Results of matches which were not confirmed are downgraded to info severity.
+func flagConfirmation(event *output.InternalWrappedEvent, status string) {
+	for _, result := range event.Results {
+		result.Metadata = generators.MergeMaps(result.Metadata, map[string]interface{}{"confirmation": status})
+		if status != confirmationConfirmed {
+			result.Info.SeverityHolder = severity.Holder{Severity: severity.Info}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package connbehavior records how connections of http requests are handled
+// by servers: whether a request was sent on a reused keep-alive connection
+// and whether the server closed the connection after its response, which
+// is a signal of anomalous connection handling (ex: request smuggling or
+// desync) when fuzzing.
+//
+// Connections of clients tracking behavior are wrapped to record reads
+// failing once the server closed the connection. The connection a request
+// is assigned by the transport (pooled or dialed) is attributed to the
+// request so that closes are reported for the request they followed.
+// As with raw headers, wrapped tls connections are not recognized by the
+// transport which as a result always uses HTTP/1.1 for them.
+package connbehavior
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// CloseGracePeriod is the duration waited after a response for the server
+// to close a connection it did not announce closing
+var CloseGracePeriod = 100 * time.Millisecond
+
+// DialFunc is a function dialing a network connection
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Conn is a connection recording if it was closed by the server
+type Conn struct {
+	net.Conn
+
+	closedLocally atomic.Bool
+	once          sync.Once
+	serverClosed  chan struct{}
+}
+
+// Wrap wraps a dial function so dialed connections record server closes
+func Wrap(dial DialFunc) DialFunc {
+	if dial == nil {
+		return nil
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &Conn{Conn: conn, serverClosed: make(chan struct{})}, nil
+	}
+}
+
+// Read reads data from the connection recording eof or reset errors of
+// connections not closed locally as closes by the server
+func (c *Conn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil && !c.closedLocally.Load() && (errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)) {
+		c.once.Do(func() { close(c.serverClosed) })
+	}
+	return n, err
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	c.closedLocally.Store(true)
+	return c.Conn.Close()
+}
+
+// NetConn returns the wrapped connection
+func (c *Conn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Capture contains the connection behavior of a request
+type Capture struct {
+	mu     sync.Mutex
+	conn   *Conn
+	reused bool
+}
+
+// WithCapture returns a context capturing the connection behavior of
+// requests using it. Only the last request is kept when redirects are followed.
+func WithCapture(ctx context.Context) (context.Context, *Capture) {
+	capture := &Capture{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			capture.mu.Lock()
+			defer capture.mu.Unlock()
+			capture.reused = info.Reused
+			capture.conn = unwrap(info.Conn)
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), capture
+}
+
+// unwrap returns the tracked connection of conn (ex: wrapped by tls) if any
+func unwrap(conn net.Conn) *Conn {
+	for conn != nil {
+		if tracked, ok := conn.(*Conn); ok {
+			return tracked
+		}
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil
+		}
+		conn = wrapper.NetConn()
+	}
+	return nil
+}
+
+// Values returns the connection_reused and connection_closed_by_server
+// variables of the request. It must be called after the body of resp was
+// read as the server closing the connection is observed by the transport
+// reading the next response. Closes announced by the response (connection:
+// close or HTTP/1.0 without keep-alive) are reported without waiting.
+func (c *Capture) Values(resp *http.Response) map[string]interface{} {
+	c.mu.Lock()
+	conn, reused := c.conn, c.reused
+	c.mu.Unlock()
+
+	closed := resp != nil && resp.Close
+	if !closed && conn != nil {
+		select {
+		case <-conn.serverClosed:
+			closed = true
+		case <-time.After(CloseGracePeriod):
+		}
+	}
+	return map[string]interface{}{
+		"connection_reused":           reused,
+		"connection_closed_by_server": closed,
+	}
+}
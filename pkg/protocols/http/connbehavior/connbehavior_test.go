@@ -0,0 +1,53 @@
+package connbehavior
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/close" {
+			_, _ = w.Write([]byte("ok"))
+			return
+		}
+		conn, bufrw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		_, _ = bufrw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+		_ = bufrw.Flush()
+		_ = conn.Close()
+	}))
+	defer ts.Close()
+
+	var dialer net.Dialer
+	client := &http.Client{Transport: &http.Transport{DialContext: Wrap(dialer.DialContext)}}
+	for _, test := range []struct {
+		path   string
+		reused bool
+		closed bool
+	}{
+		{path: "/", reused: false, closed: false},
+		{path: "/", reused: true, closed: false},
+		{path: "/close", reused: true, closed: true},
+		{path: "/", reused: false, closed: false},
+	} {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+test.path, nil)
+		require.Nil(t, err, "could not create request")
+		ctx, capture := WithCapture(req.Context())
+		resp, err := client.Do(req.WithContext(ctx))
+		require.Nil(t, err, "could not do request")
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		values := capture.Values(resp)
+		require.Equal(t, test.reused, values["connection_reused"], "could not capture reuse of %s", test.path)
+		require.Equal(t, test.closed, values["connection_closed_by_server"], "could not capture close of %s", test.path)
+	}
+}
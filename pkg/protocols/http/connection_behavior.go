@@ -0,0 +1,39 @@
+package http
+
+import (
+	"strings"
+)
+
+// connectionBehaviorVariables are the variables reporting how the
+// connection of the request was handled by the server
+var connectionBehaviorVariables = []string{"connection_reused", "connection_closed_by_server"}
+
+// usesConnectionBehavior returns true if matchers or extractors of the
+// request run on or reference connection behavior variables so that
+// connections are only tracked when needed. It is called before operators
+// are compiled as clients tracking connections are created beforehand.
+func (request *Request) usesConnectionBehavior() bool {
+	for _, matcher := range request.Matchers {
+		if referencesConnectionBehavior(matcher.Part) || referencesConnectionBehavior(matcher.DSL...) {
+			return true
+		}
+	}
+	for _, extractor := range request.Extractors {
+		if referencesConnectionBehavior(extractor.Part) || referencesConnectionBehavior(extractor.DSL...) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesConnectionBehavior returns true if any expression references a connection behavior variable
+func referencesConnectionBehavior(expressions ...string) bool {
+	for _, expression := range expressions {
+		for _, variable := range connectionBehaviorVariables {
+			if strings.Contains(expression, variable) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// applyCookieFile adds cookies of the cookie file jar matching the
+// request url. Cookies already set by the request are not overridden.
+func (request *Request) applyCookieFile(gr *generatedRequest) {
+	jar := request.options.CookieFile
+	if jar == nil {
+		return
+	}
+	if u := gr.cookieURL(); u != nil {
+		gr.addCookies(jar.Cookies(u))
+	}
+}
+
+// cookieURL returns the url of the generated request cookies are matched against
+func (gr *generatedRequest) cookieURL() *url.URL {
+	switch {
+	case gr.request != nil && gr.request.URL != nil:
+		return gr.request.URL.URL
+	case gr.rawRequest != nil:
+		u, err := url.Parse(gr.rawRequest.FullURL)
+		if err != nil {
+			return nil
+		}
+		return u
+	}
+	return nil
+}
+
+// addCookies adds the cookies to the generated request.
+// Cookies already set by the request are not overridden.
+func (gr *generatedRequest) addCookies(cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+	switch {
+	case gr.request != nil && gr.request.URL != nil:
+		for _, cookie := range cookies {
+			if _, err := gr.request.Cookie(cookie.Name); err == nil {
+				continue
+			}
+			gr.request.AddCookie(cookie)
+		}
+	case gr.rawRequest != nil:
+		if gr.rawRequest.Headers == nil {
+			gr.rawRequest.Headers = make(map[string]string)
+		}
+		headerName := "Cookie"
+		for k := range gr.rawRequest.Headers {
+			if strings.EqualFold(k, headerName) {
+				headerName = k
+				break
+			}
+		}
+		existing := &http.Request{Header: http.Header{"Cookie": {gr.rawRequest.Headers[headerName]}}}
+		values := []string{}
+		if value := strings.TrimSpace(gr.rawRequest.Headers[headerName]); value != "" {
+			values = append(values, value)
+		}
+		for _, cookie := range cookies {
+			if _, err := existing.Cookie(cookie.Name); err == nil {
+				continue
+			}
+			values = append(values, cookie.String())
+		}
+		gr.rawRequest.Headers[headerName] = strings.Join(values, "; ")
+	}
+}
+
+// updateCookieFile updates the cookie file jar with cookies set by the response
+// if enabled by the cookie-file-update option
+func (request *Request) updateCookieFile(formedURL string, resp *http.Response) {
+	if request.options.CookieFile == nil || !request.options.Options.CookieFileUpdate || resp == nil {
+		return
+	}
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return
+	}
+	u := &url.URL{}
+	if resp.Request != nil && resp.Request.URL != nil {
+		u = resp.Request.URL
+	} else if parsed, err := url.Parse(formedURL); err == nil {
+		u = parsed
+	}
+	request.options.CookieFile.SetCookies(u, cookies)
+}
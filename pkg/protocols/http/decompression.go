@@ -0,0 +1,47 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+const (
+	// DecompressionAuto decompresses response body based on content-encoding
+	DecompressionAuto = "auto"
+	// DecompressionNone uses the response body as received
+	DecompressionNone = "none"
+)
+
+// rawBodyReader reads the response body while capturing the raw bytes
+type rawBodyReader struct {
+	io.Reader
+	io.Closer
+}
+
+// captureRawBody wraps the response body to capture the bytes as received
+// from the server before any decompression is performed.
+func captureRawBody(resp *http.Response) *bytes.Buffer {
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+	raw := &bytes.Buffer{}
+	resp.Body = &rawBodyReader{Reader: io.TeeReader(resp.Body, raw), Closer: resp.Body}
+	return raw
+}
+
+// getDecompressionValues returns the body used for matching along with
+// raw_body and decompressed_body variables if they differ.
+func (request *Request) getDecompressionValues(raw *bytes.Buffer, decompressed string) (string, map[string]interface{}) {
+	if raw == nil || raw.String() == decompressed {
+		return decompressed, nil
+	}
+	values := map[string]interface{}{
+		"raw_body":          raw.String(),
+		"decompressed_body": decompressed,
+	}
+	if request.Decompression == DecompressionNone {
+		return raw.String(), values
+	}
+	return decompressed, values
+}
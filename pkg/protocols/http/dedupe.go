@@ -0,0 +1,30 @@
+package http
+
+import (
+	"bytes"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// isDuplicateRequest returns true if the generated request was already sent
+// by the template for another input. Fuzzing and re-sent requests are never
+// deduplicated.
+func (request *Request) isDuplicateRequest(input *contextargs.Context, generatedRequest *generatedRequest, dumpedRequest []byte) bool {
+	if request.options.RequestDedupe == nil || len(request.Fuzzing) > 0 || generatedRequest.resend {
+		return false
+	}
+	var method, formedURL string
+	switch {
+	case generatedRequest.rawRequest != nil:
+		method, formedURL = generatedRequest.rawRequest.Method, generatedRequest.rawRequest.FullURL
+	case generatedRequest.request != nil:
+		method, formedURL = generatedRequest.request.Method, generatedRequest.request.URL.String()
+	default:
+		return false
+	}
+	var body []byte
+	if index := bytes.Index(dumpedRequest, []byte("\r\n\r\n")); index != -1 {
+		body = dumpedRequest[index+4:]
+	}
+	return request.options.RequestDedupe.IsDuplicate(request.options.TemplateID, input.MetaInput.Input, method, formedURL, body)
+}
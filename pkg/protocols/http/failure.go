@@ -0,0 +1,41 @@
+package http
+
+import (
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// writeFailureEvent writes a failure event with categorized error type
+// for a failed request so that target-side failures (dns, tls, timeouts)
+// can be distinguished from template errors.
+func (request *Request) writeFailureEvent(input *contextargs.Context, data output.InternalEvent, err error) {
+	fields := protocolutils.GetJsonFieldsFromURL(input.MetaInput.Input)
+	if ip := types.ToString(data["ip"]); ip != "" {
+		fields.Ip = ip
+	}
+	event := &output.ResultEvent{
+		TemplateID:   request.options.TemplateID,
+		TemplatePath: request.options.TemplatePath,
+		Info:         request.options.TemplateInfo,
+		Type:         request.Type().String(),
+		Host:         fields.Host,
+		Port:         fields.Port,
+		Scheme:       fields.Scheme,
+		URL:          fields.URL,
+		Path:         fields.Path,
+		Matched:      types.ToString(data["matched"]),
+		IP:           fields.Ip,
+		Request:      types.ToString(data["request"]),
+		Timestamp:    time.Now(),
+		Error:        err.Error(),
+		ErrorType:    protocolutils.GetErrorType(err),
+	}
+	if writeErr := request.options.Output.Write(event); writeErr != nil {
+		gologger.Warning().Msgf("[%s] Could not write failure event: %s\n", request.options.TemplateID, writeErr)
+	}
+}
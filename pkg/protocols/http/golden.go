@@ -0,0 +1,27 @@
+package http
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// loadGoldenFiles loads the golden files of the golden matchers of the request
+func (request *Request) loadGoldenFiles(options *protocols.ExecutorOptions) error {
+	for _, matcher := range request.Matchers {
+		if matcher.GetType() != matchers.GoldenMatcher || matcher.GoldenFile == "" {
+			continue
+		}
+		if path := resolveHelperFile(options, matcher.GoldenFile); !fileutil.FileExists(path) {
+			return errors.Errorf("golden file %s does not exist (resolved to %s)", matcher.GoldenFile, path)
+		}
+		contents, err := loadHelperFile(options, matcher.GoldenFile)
+		if err != nil {
+			return errors.Wrapf(err, "could not load golden file %s", matcher.GoldenFile)
+		}
+		matcher.SetGoldenContents(contents)
+	}
+	return nil
+}
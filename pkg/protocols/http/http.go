@@ -11,6 +11,7 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/noise"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
@@ -18,6 +19,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	httputil "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/stats"
 	"github.com/projectdiscovery/rawhttp"
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -71,11 +73,26 @@ type Request struct {
 	//     value: "\"username=test&password=test\""
 	Body string `yaml:"body,omitempty" json:"body,omitempty" jsonschema:"title=body is the http request body,description=Body is an optional parameter which contains HTTP Request body"`
 	// description: |
+	//   BodyFile is the path of a file (relative to the template) whose contents
+	//   are used as the HTTP Request body. Variables in the file are interpolated
+	//   like an inline body.
+	//
+	//   For fuzzing templates, url inputs are fuzzed with the method and body of
+	//   the template so that body parts of the file can be fuzzed.
+	// examples:
+	//   - name: SOAP envelope of the request
+	//     value: "\"fixtures/get-user.xml\""
+	BodyFile string `yaml:"body-file,omitempty" json:"body-file,omitempty" jsonschema:"title=file containing the http request body,description=Path of a file relative to the template containing the HTTP Request body"`
+	// description: |
 	//   Payloads contains any payloads for the current request.
 	//
 	//   Payloads support both key-values combinations where a list
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
+	//
+	//   Remote payload files (http, https, s3 and gs urls) are fetched
+	//   when the request is first executed and cached locally when
+	//   -allow-remote-payloads is enabled.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the http request,description=Payloads contains any payloads for the current request"`
 
 	// description: |
@@ -85,6 +102,30 @@ type Request struct {
 	//       map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Content-Length": "1", "Any-Header": "Any-Value"}
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers to send with the http request,description=Headers contains HTTP Headers to send with the request"`
 	// description: |
+	//   ConditionalHeaders contains HTTP Headers set or removed only when the
+	//   DSL condition evaluates to true for the request being built.
+	//
+	//   Conditions are evaluated per input with variables of the request url
+	//   (Scheme, Hostname, Host, Port, Path etc) and are applied after headers.
+	//   They are also applied to base requests of fuzzing.
+	// examples:
+	//   - value: |
+	//       []*ConditionalHeaders{{Condition: "Scheme == \"https\"", Headers: map[string]string{"Upgrade-Insecure-Requests": "1"}}}
+	ConditionalHeaders []*ConditionalHeaders `yaml:"conditional-headers,omitempty" json:"conditional-headers,omitempty" jsonschema:"title=conditional headers of the http request,description=Headers set or removed when the DSL condition matches the request url"`
+	// description: |
+	//   Pagination follows the next pages of responses (ex: paginated api endpoints)
+	//   up to max-pages aggregating the results extracted from all pages into the
+	//   result of the first page.
+	//
+	//   The next page is detected from the Link header, a json cursor or an url
+	//   pattern and resolved against the url of the page after redirects. Only pages
+	//   of the same host are followed and each url is fetched once. Pagination can't
+	//   be used with unsafe, raw-socket, pipeline or race requests.
+	// examples:
+	//   - value: |
+	//       &Pagination{Type: "json", JSON: ".meta.next_cursor", Param: "cursor", MaxPages: 5}
+	Pagination *Pagination `yaml:"pagination,omitempty" json:"pagination,omitempty" jsonschema:"title=pagination of the http request,description=Follow next pages of responses aggregating extracted results"`
+	// description: |
 	//   RaceCount is the number of times to send a request in Race Condition Attack.
 	// examples:
 	//   - name: Send a request 5 times
@@ -126,6 +167,62 @@ type Request struct {
 
 	// Fuzzing describes schema to fuzz http requests
 	Fuzzing []*fuzz.Rule `yaml:"fuzzing,omitempty" json:"fuzzing,omitempty" jsonschema:"title=fuzzin rules for http fuzzing,description=Fuzzing describes rule schema to fuzz http requests"`
+	// description: |
+	//   CSRF enables automatic extraction of csrf token from a baseline response of
+	//   the input which is injected in matching fields of fuzzing requests.
+	CSRF *fuzz.CSRF `yaml:"csrf,omitempty" json:"csrf,omitempty" jsonschema:"title=automatic csrf token handling for fuzzing,description=Extract csrf token from baseline response and inject it in fuzzing requests"`
+	// description: |
+	//   ParamMining discovers hidden query and body parameters of the input
+	//   using a wordlist of parameter names before fuzzing. Discovered
+	//   parameters are fuzzed along with the existing ones.
+	//
+	//   Mining is only performed if enabled for all inputs (-fuzz-param-mining)
+	//   or for an input by its per-input variable param_mining.
+	ParamMining *fuzz.ParamMining `yaml:"param-mining,omitempty" json:"param-mining,omitempty" jsonschema:"title=parameter mining for fuzzing,description=Discover hidden query and body parameters before fuzzing"`
+	// description: |
+	//   DetectInjectedHeaders captures a baseline response of the input and
+	//   exposes response headers of fuzzing requests which are not present in
+	//   the baseline and are attributable to the payload (ex: CRLF injection)
+	//   to matchers as `injected_headers` variable.
+	DetectInjectedHeaders bool `yaml:"detect-injected-headers,omitempty" json:"detect-injected-headers,omitempty" jsonschema:"title=detect injected response headers,description=Expose response headers injected by fuzzing payloads as injected_headers"`
+	// description: |
+	//   VerifyControl sends the unmodified request of the input as control
+	//   before fuzzing it. The control is considered failed if the request
+	//   errors or returns a 5xx or 429 status code, in which case matches of
+	//   fuzzing requests are unreliable and are flagged with `control` in the
+	//   result metadata.
+	//
+	//   `control_failed` and `control_status_code` are available to matchers.
+	VerifyControl bool `yaml:"verify-control,omitempty" json:"verify-control,omitempty" jsonschema:"title=verify control request,description=Send the unmodified request as control before fuzzing and flag results if it failed"`
+	// description: |
+	//   ConfirmMatches re-sends fuzzing requests that matched and only reports
+	//   the match if it is reproduced. Matches which are not reproduced are
+	//   reported as info severity results with `confirmation: flaky` metadata.
+	//
+	//   Matches relying on interactsh interactions are not confirmed.
+	ConfirmMatches bool `yaml:"confirm-matches,omitempty" json:"confirm-matches,omitempty" jsonschema:"title=confirm fuzzing matches,description=Re-send matched fuzzing requests and only report reproduced matches"`
+	// description: |
+	//   ConfirmControl also re-sends the unmodified request of the input when
+	//   confirming a match. Matches also matching the unmodified request are not
+	//   caused by the payload and are reported with `confirmation: control-matched`.
+	ConfirmControl bool `yaml:"confirm-control,omitempty" json:"confirm-control,omitempty" jsonschema:"title=confirm fuzzing matches against control,description=Re-send the unmodified request when confirming matches and reject matches also matching it"`
+	// description: |
+	//   NormalizeBody masks common dynamic fragments of response bodies (csrf
+	//   tokens, nonces, uuids, timestamps and long hex identifiers) before
+	//   matching so that identical responses compare equal.
+	//
+	//   Matchers on the body part evaluate the normalized body, which is also
+	//   available as `normalized_body`. `body` and extractors are unchanged.
+	NormalizeBody bool `yaml:"normalize-body,omitempty" json:"normalize-body,omitempty" jsonschema:"title=normalize response body before matching,description=Mask dynamic fragments of the response body before matching"`
+	// description: |
+	//   NormalizeMasks are additional regexes of dynamic fragments masked in
+	//   response bodies before matching. Only the first group is masked for
+	//   regexes with capture groups. Enables body normalization.
+	// examples:
+	//   - name: Mask build numbers and the value of a session input
+	//     value: >
+	//       []string{"build-\\d+", "name=\"session\" value=\"([^\"]+)\""}
+	NormalizeMasks []string `yaml:"normalize-masks,omitempty" json:"normalize-masks,omitempty" jsonschema:"title=masks of dynamic body fragments,description=Regexes of dynamic fragments masked in the response body before matching"`
 
 	CompiledOperators *operators.Operators `yaml:"-" json:"-"`
 
@@ -135,7 +232,14 @@ type Request struct {
 	customHeaders     map[string]string
 	generator         *generators.PayloadGenerator // optional, only enabled when using payloads
 	httpClient        *retryablehttp.Client
+	http2Client       *retryablehttp.Client // optional, only enabled when comparing http/2 responses
+	ssrfControlHost   string                // optional, only enabled with ssrf matchers
+	bodyNormalizer    *noise.Normalizer     // optional, only enabled with body normalization
 	rawhttpClient     *rawhttp.Client
+	bodyFromFile      bool // body was loaded from the body file
+	rawRequest        bool // raw outgoing request is referenced by operators
+	cachePoisoning    bool // cache poisoning variables are referenced by operators
+	trackConnections  bool // connection behavior variables are referenced by operators
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -183,6 +287,25 @@ type Request struct {
 	//   control over the request, with no normalization performed by the client.
 	Unsafe bool `yaml:"unsafe,omitempty" json:"unsafe,omitempty" jsonschema:"title=use rawhttp non-strict-rfc client,description=Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests"`
 	// description: |
+	//   RawSocket writes the exact bytes of requests to a tcp/tls connection bypassing
+	//   the http client, allowing intentionally malformed requests (duplicate Content-Length,
+	//   invalid chunking, malformed headers) used in request smuggling and protocol confusion tests.
+	//
+	//   Raw requests are sent as written in the template while fuzzing requests are serialized
+	//   with header values written verbatim so malformed payloads reach the wire. The response
+	//   is read back as received, exposed as `raw_response` and parsed leniently for matchers.
+	//
+	//   Encoded path separators (ex: %2f, %2e%2e) written in paths and raw requests of templates
+	//   are sent verbatim by all send modes (http client, unsafe and raw socket) while paths of
+	//   inputs expanded by {{BaseURL}} are decoded. The path fuzzing part decodes the path and
+	//   encodes payloads (%2f is sent as %252f), the raw-path fuzzing part (raw socket only)
+	//   keeps both the input path and payloads verbatim. The path sent by raw socket requests
+	//   is exposed as `sent_path`.
+	//
+	//   Redirects, proxies and connection reuse are not supported with raw socket requests,
+	//   templates with raw socket requests fail to load if a proxy is set.
+	RawSocket bool `yaml:"raw-socket,omitempty" json:"raw-socket,omitempty" jsonschema:"title=send requests using raw sockets,description=Write exact bytes of requests to the connection bypassing the http client"`
+	// description: |
 	//   Race determines if all the request have to be attempted at the same time (Race Condition)
 	//
 	//   The actual number of requests that will be sent is determined by the `race_count`  field.
@@ -200,6 +323,36 @@ type Request struct {
 	//   SkipVariablesCheck skips the check for unresolved variables in request
 	SkipVariablesCheck bool `yaml:"skip-variables-check,omitempty" json:"skip-variables-check,omitempty" jsonschema:"title=skip variable checks,description=Skips the check for unresolved variables in request"`
 	// description: |
+	//   GzipBody compresses the request body with gzip and sets the
+	//   `Content-Encoding: gzip` header before the request is sent.
+	//
+	//   Compression is applied after fuzzing mutations so the plain body is fuzzed.
+	GzipBody bool `yaml:"gzip-body,omitempty" json:"gzip-body,omitempty" jsonschema:"title=gzip compress request body,description=Compress the request body with gzip before sending"`
+	// description: |
+	//   RawHeaders captures response headers as received preserving their order
+	//   and original casing, exposed to matchers as `header_order` (comma separated
+	//   header names) and `raw_headers` variables.
+	//
+	//   Raw headers are only available for HTTP/1.x responses of non-raw (unsafe)
+	//   requests as HTTP/2 lowercases header names and does not preserve their
+	//   order. Requests capturing raw headers are always sent using HTTP/1.1.
+	RawHeaders bool `yaml:"raw-headers,omitempty" json:"raw-headers,omitempty" jsonschema:"title=capture raw response headers,description=Expose response header order and original casing as header_order and raw_headers"`
+	// description: |
+	//   CompareHTTP2 sends each request over both HTTP/1.1 and HTTP/2 and
+	//   exposes the HTTP/2 response to matchers for detecting protocol
+	//   divergence (ex: request smuggling or desync issues).
+	//
+	//   The request is sent over HTTP/1.1 and the HTTP/2 response is available
+	//   as `http2_status_code`, `http2_content_length`, `http2_body`,
+	//   `http2_headers` and `http2_protocol` (the negotiated protocol)
+	//   variables. `protocol_status_diff`, `protocol_length_diff` and
+	//   `protocol_body_diff` compare both responses and `protocol_diff`
+	//   is true if status code or body length differ.
+	//
+	//   It doubles the number of requests sent and only applies to https
+	//   targets of non-raw (unsafe) requests.
+	CompareHTTP2 bool `yaml:"compare-http2,omitempty" json:"compare-http2,omitempty" jsonschema:"title=compare http/1.1 and http/2 responses,description=Send requests over both HTTP/1.1 and HTTP/2 exposing both responses to matchers"`
+	// description: |
 	//   IterateAll iterates all the values extracted from internal extractors
 	// Deprecated: Use flow instead . iterate-all will be removed in future releases
 	IterateAll bool `yaml:"iterate-all,omitempty" json:"iterate-all,omitempty" jsonschema:"title=iterate all the values,description=Iterates all the values extracted from internal extractors"`
@@ -219,6 +372,43 @@ type Request struct {
 	//  FuzzPreConditionOperator is the operator between multiple PreConditions for fuzzing Default is OR
 	FuzzPreConditionOperator string                 `yaml:"pre-condition-operator,omitempty" json:"pre-condition-operator,omitempty" jsonschema:"title=condition between the filters,description=Operator to use between multiple per-conditions,enum=and,enum=or"`
 	fuzzPreConditionOperator matchers.ConditionType `yaml:"-" json:"-"`
+	// description: |
+	//   Prerequisites are requests executed for an input before the main requests
+	//   (including fuzzing). Each prerequisite must match (or succeed if it has no
+	//   matchers) for the main requests to run, otherwise the input is skipped.
+	//
+	//   Values extracted by internal extractors of prerequisites (ex: session cookie
+	//   after login) are available to the main requests.
+	Prerequisites []*Request `yaml:"prerequisites,omitempty" json:"prerequisites,omitempty" jsonschema:"title=prerequisite requests,description=Requests which must match for the main requests to run"`
+	// description: |
+	//   Encoding specifies the encoding of raw requests, body and header values.
+	//
+	//   Values are decoded to raw bytes after evaluating helper expressions and are sent
+	//   verbatim allowing non-UTF8 and null bytes. Use with unsafe to avoid
+	//   validation of header values by the transport.
+	//
+	//   Fuzzing payloads are decoded too, raw bytes set in query parameters and form
+	//   bodies are percent encoded byte by byte.
+	// values:
+	//   - "hex"
+	//   - "base64"
+	Encoding string `yaml:"encoding,omitempty" json:"encoding,omitempty" jsonschema:"title=encoding of request values,description=Encoding of raw requests and body/header values decoded to raw bytes before sending,enum=hex,enum=base64"`
+	// description: |
+	//   MatchConnectionError evaluates matchers on fuzzing requests that failed with a
+	//   connection level error (timeout, reset, refused or eof).
+	//
+	//   The outcome is exposed to matchers as `connection_error` variable.
+	MatchConnectionError bool `yaml:"match-connection-error,omitempty" json:"match-connection-error,omitempty" jsonschema:"title=match connection errors,description=Evaluate matchers on fuzzing requests failing with connection level errors exposed as connection_error"`
+	// description: |
+	//   Decompression controls decompression of the response body for matching.
+	//
+	//   auto decompresses the body based on Content-Encoding (gzip, deflate, br) while none
+	//   matches on the raw bytes as received. When set, raw_body and decompressed_body are
+	//   also exposed if they differ. Unsupported encodings are left as is.
+	// values:
+	//   - "auto"
+	//   - "none"
+	Decompression string `yaml:"decompression,omitempty" json:"decompression,omitempty" jsonschema:"title=response body decompression,description=Decompression of response body for matching (auto or none),enum=auto,enum=none"`
 }
 
 func (e Request) JSONSchemaExtend(schema *jsonschema.Schema) {
@@ -253,22 +443,64 @@ func (r *Request) Options() *protocols.ExecutorOptions {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":           "ID of the template executed",
-	"template-info":         "Info Block of the template executed",
-	"template-path":         "Path of the template executed",
-	"host":                  "Host is the input to the template",
-	"matched":               "Matched is the input which was matched upon",
-	"type":                  "Type is the type of request made",
-	"request":               "HTTP request made from the client",
-	"response":              "HTTP response received from server",
-	"status_code":           "Status Code received from the Server",
-	"body":                  "HTTP response body received from server (default)",
-	"content_length":        "HTTP Response content length",
-	"header,all_headers":    "HTTP response headers",
-	"duration":              "HTTP request time duration",
-	"all":                   "HTTP response body + headers",
-	"cookies_from_response": "HTTP response cookies in name:value format",
-	"headers_from_response": "HTTP response headers in name:value format",
+	"template-id":                 "ID of the template executed",
+	"template-info":               "Info Block of the template executed",
+	"template-path":               "Path of the template executed",
+	"host":                        "Host is the input to the template",
+	"matched":                     "Matched is the input which was matched upon",
+	"type":                        "Type is the type of request made",
+	"request":                     "HTTP request made from the client",
+	"raw_request":                 "Raw bytes of the outgoing request captured before it is sent (after auth and body transformations)",
+	"response":                    "HTTP response received from server",
+	"status_code":                 "Status Code received from the Server",
+	"body":                        "HTTP response body received from server (default)",
+	"content_length":              "HTTP Response content length",
+	"alpn_protocols":              "ALPN protocol negotiated by the tls connection (empty for plain http)",
+	"header,all_headers":          "HTTP response headers",
+	"duration":                    "HTTP request time duration",
+	"all":                         "HTTP response body + headers",
+	"cookies_from_response":       "HTTP response cookies in name:value format",
+	"headers_from_response":       "HTTP response headers in name:value format",
+	"connection_error":            "Connection level error of failed fuzzing request (timeout, reset, refused or eof)",
+	"connection_host":             "Host dialed for the fuzzing request",
+	"sent_host":                   "Host header sent with the fuzzing request",
+	"raw_body":                    "HTTP response body as received before decompression (requires decompression)",
+	"decompressed_body":           "HTTP response body after decompression (requires decompression)",
+	"normalized_body":             "HTTP response body with dynamic fragments masked, evaluated by body matchers (requires normalize-body or normalize-masks)",
+	"injected_headers":            "Response headers of fuzzing request injected by the payload (requires detect-injected-headers)",
+	"http2_status_code":           "Status code of the HTTP/2 response (requires compare-http2)",
+	"http2_body":                  "Body of the HTTP/2 response (requires compare-http2)",
+	"protocol_diff":               "True if status code or body length of HTTP/1.1 and HTTP/2 responses differ (requires compare-http2)",
+	"ssrf_target":                 "Internal target fetched by the request (requires ssrf matchers)",
+	"ssrf_control_status_code":    "Status code of the ssrf control response (requires ssrf matchers)",
+	"ssrf_control_body":           "Body of the ssrf control response (requires ssrf matchers)",
+	"control_failed":              "True if the unmodified control request of fuzzing errored or returned 5xx/429 (requires verify-control)",
+	"control_status_code":         "Status code of the unmodified control request of fuzzing (requires verify-control)",
+	"reflection_context":          "HTML/JS context of the first reflection of the fuzzing canary (requires canary)",
+	"reflection_contexts":         "Comma separated HTML/JS contexts of all reflections of the fuzzing canary (requires canary)",
+	"reflected_header":            "Lowercased name of the first injected request header (fuzzed header or template header) reflected in the response",
+	"reflected_headers":           "Comma separated lowercased names of all injected request headers reflected in the response",
+	"is_cacheable":                "True if the response is cacheable by shared caches (cache-control, age, expires or cache status headers) and does not vary on the reflected headers",
+	"cache_status":                "Cache status reported by cache headers of the response (ex: x-cache, cf-cache-status) as hit, miss or empty",
+	"error_type":                  "Category of the error of a failed request (dns_error, tls_error, timeout, connection_refused, connection_reset, read_error)",
+	"raw_response":                "HTTP response exactly as received from the raw socket (requires raw-socket)",
+	"request_line":                "Request line (method, target and version) sent by the raw socket (requires raw-socket)",
+	"sent_path":                   "Path of the request target sent by the raw socket as written on the wire with encoded separators kept (requires raw-socket)",
+	"header_order":                "Comma separated response header names in received order with original casing (requires raw-headers, HTTP/1.x only)",
+	"raw_headers":                 "Response headers as received with original order and casing (requires raw-headers, HTTP/1.x only)",
+	"connection_reused":           "True if the request was sent on a reused keep-alive connection (keep-alive is enabled for requests referencing it)",
+	"connection_closed_by_server": "True if the server closed the connection after the response (announced by connection: close or observed shortly after, HTTP/1.x only)",
+	"redirect_host":               "Host of the redirect target of the location header as parsed by browsers",
+	"redirect_url":                "Redirect target of the location header resolved against the request url",
+	"redirect_path":               "Path of the resolved redirect target",
+	"redirect_query":              "Raw query of the resolved redirect target",
+	"redirect_params":             "Query parameters of the redirect target as a map by name with arrays for repeated parameters (ex: get_header(redirect_params, 'next'))",
+	"redirect_param_<name>":       "First value of a query parameter of the redirect target (ex: redirect_param_next == 'https://evil.com')",
+	"response_headers":            "HTTP response headers as a map by lowercased name with arrays for multi-valued headers (ex: get_header(response_headers, 'X-Token'))",
+	"server_timing":               "Server-Timing metrics and performance headers (ex: x-runtime) as a map of durations in milliseconds by lowercased name (ex: get_header(server_timing, 'db'))",
+	"server_timing_<metric>":      "Duration in milliseconds of a Server-Timing metric or performance header with dashes replaced by underscores (ex: server_timing_db > 500)",
+	"<header_name>":               "HTTP response header value by lowercased name with dashes replaced by underscores",
+	"<cookie_name>":               "HTTP response cookie value by lowercased name",
 }
 
 // GetID returns the unique ID of the request if any.
@@ -285,14 +517,24 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if err := request.validate(); err != nil {
 		return errors.Wrap(err, "validation error")
 	}
+	// raw sockets are dialed directly and would bypass the proxy
+	if request.RawSocket && (options.Proxy != "" || types.ProxyURL != "" || types.ProxySocksURL != "") {
+		return errors.New("'raw-socket' requests can't be sent through a proxy")
+	}
+	request.trackConnections = request.usesConnectionBehavior()
 
 	connectionConfiguration := &httpclientpool.Configuration{
 		Threads:       request.Threads,
 		MaxRedirects:  request.MaxRedirects,
 		NoTimeout:     false,
 		DisableCookie: request.DisableCookie,
+		Proxy:         options.Proxy,
+		RawHeaders:    request.RawHeaders,
+		// unsafe requests are sent by rawhttp and are not tracked
+		TrackConnections: request.trackConnections,
 		Connection: &httpclientpool.ConnectionConfiguration{
-			DisableKeepAlive: httputil.ShouldDisableKeepAlive(options.Options),
+			// connections are kept alive to observe their reuse and closes by servers
+			DisableKeepAlive: httputil.ShouldDisableKeepAlive(options.Options) && !request.trackConnections,
 		},
 		RedirectFlow: httpclientpool.DontFollowRedirect,
 	}
@@ -310,6 +552,16 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			connectionConfiguration.NoTimeout = true
 		}
 	}
+	if request.CompareHTTP2 {
+		connectionConfiguration.HTTPVersion = httpclientpool.HTTPVersion11
+		http2Configuration := *connectionConfiguration
+		http2Configuration.HTTPVersion = httpclientpool.HTTPVersion2
+		http2Client, err := httpclientpool.Get(options.Options, &http2Configuration)
+		if err != nil {
+			return errors.Wrap(err, "could not get http2 client")
+		}
+		request.http2Client = http2Client
+	}
 	request.connConfiguration = connectionConfiguration
 
 	client, err := httpclientpool.Get(options.Options, connectionConfiguration)
@@ -327,25 +579,54 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		request.customHeaders[parts[0]] = strings.TrimSpace(parts[1])
 	}
 
-	if request.Body != "" && !strings.Contains(request.Body, "\r\n") {
+	if request.Body != "" && request.Encoding == "" && !strings.Contains(request.Body, "\r\n") {
 		request.Body = strings.ReplaceAll(request.Body, "\n", "\r\n")
 	}
+	// body files are used as is without line ending conversion
+	if request.BodyFile != "" {
+		body, err := request.loadBodyFile(options)
+		if err != nil {
+			return err
+		}
+		request.Body = body
+		request.bodyFromFile = true
+	}
 	if len(request.Raw) > 0 {
 		for i, raw := range request.Raw {
-			if !strings.Contains(raw, "\r\n") {
+			if request.Encoding == "" && !strings.Contains(raw, "\r\n") {
 				request.Raw[i] = strings.ReplaceAll(raw, "\n", "\r\n")
 			}
 		}
 		request.rawhttpClient = httpclientpool.GetRawHTTP(options.Options)
 	}
+	if err := request.loadGoldenFiles(options); err != nil {
+		return err
+	}
+	if request.NormalizeBody || len(request.NormalizeMasks) > 0 {
+		normalizer, err := noise.New(request.NormalizeMasks, request.NormalizeBody)
+		if err != nil {
+			return errors.Wrap(err, "could not compile body normalization masks")
+		}
+		request.bodyNormalizer = normalizer
+	}
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if compileErr := compiled.Compile(); compileErr != nil {
 			return errors.Wrap(compileErr, "could not compile operators")
 		}
 		request.CompiledOperators = compiled
+
+		for _, matcher := range compiled.Matchers {
+			if matcher.GetType() == matchers.SSRFMatcher {
+				request.ssrfControlHost = matcher.ControlHost
+				break
+			}
+		}
+		request.rawRequest = request.usesRawRequest()
+		request.cachePoisoning = request.usesCachePoisoning()
 	}
 
 	// === fuzzing filters ===== //
@@ -362,6 +643,27 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 	}
 
+	for i, conditional := range request.ConditionalHeaders {
+		if err := conditional.Compile(); err != nil {
+			return errors.Wrapf(err, "could not compile conditional headers %d", i)
+		}
+	}
+
+	if request.Pagination != nil {
+		if err := request.Pagination.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile pagination")
+		}
+	}
+
+	for i, prerequisite := range request.Prerequisites {
+		if len(prerequisite.Prerequisites) > 0 {
+			return errors.Errorf("prerequisite %d must not have prerequisites", i)
+		}
+		if err := prerequisite.Compile(options); err != nil {
+			return errors.Wrapf(err, "could not compile prerequisite %d", i)
+		}
+	}
+
 	// Resolve payload paths from vars if they exists
 	for name, payload := range request.options.Options.Vars.AsMap() {
 		payloadStr, ok := payload.(string)
@@ -409,6 +711,15 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	for payload := range unusedPayloads {
 		delete(request.Payloads, payload)
 	}
+	// fuzzing templates are not executed without dast, so remote
+	// payloads of their rules are not fetched
+	if len(request.Fuzzing) > 0 && !options.Options.DAST {
+		for name, payload := range request.Payloads {
+			if generators.IsRemotePayload(payload) {
+				delete(request.Payloads, name)
+			}
+		}
+	}
 
 	if len(request.Payloads) > 0 {
 		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, request.options.Catalog, request.options.Options.AttackType, request.options.Options)
@@ -434,6 +745,16 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 				return errors.Wrap(err, "could not compile fuzzing rule")
 			}
 		}
+		if request.CSRF != nil {
+			if err := request.CSRF.Compile(); err != nil {
+				return errors.Wrap(err, "could not compile csrf patterns")
+			}
+		}
+		if request.ParamMining != nil {
+			if err := request.ParamMining.Compile(request.options); err != nil {
+				return errors.Wrap(err, "could not compile param mining")
+			}
+		}
 	}
 	if len(request.Payloads) > 0 {
 		// Due to a known issue (https://github.com/projectdiscovery/nuclei/issues/5015),
@@ -472,7 +793,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 // Requests returns the total number of requests the YAML rule will perform
 func (request *Request) Requests() int {
 	if request.generator != nil {
-		payloadRequests := request.generator.NewIterator().Total()
+		payloadRequests := request.generator.Total()
 		if len(request.Raw) > 0 {
 			payloadRequests = payloadRequests * len(request.Raw)
 		}
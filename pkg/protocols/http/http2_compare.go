@@ -0,0 +1,66 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// http2Response is the response of a request sent over http/2 for
+// comparison with the http/1.1 response
+type http2Response struct {
+	statusCode int
+	protocol   string
+	headers    string
+	body       string
+}
+
+// prepareHTTP2Request returns a copy of the request sent over http/2
+// if the request compares http versions and targets a https url
+func (request *Request) prepareHTTP2Request(req *retryablehttp.Request) *retryablehttp.Request {
+	if request.http2Client == nil || req == nil || !strings.EqualFold(req.URL.Scheme, "https") {
+		return nil
+	}
+	return req.Clone(req.Context())
+}
+
+// executeHTTP2Request sends the request over http/2 returning its response
+func (request *Request) executeHTTP2Request(input *contextargs.Context, req *retryablehttp.Request) *http2Response {
+	if req == nil {
+		return nil
+	}
+	request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+	resp, err := request.http2Client.Do(req)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] Could not send http2 request to %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxBodyRead))
+	headers := &strings.Builder{}
+	_ = resp.Header.Write(headers)
+	return &http2Response{statusCode: resp.StatusCode, protocol: resp.Proto, headers: headers.String(), body: string(body)}
+}
+
+// values returns the http/2 response variables compared against the
+// status code and body of the http/1.1 response
+func (response *http2Response) values(resp *http.Response, body string) map[string]interface{} {
+	statusDiff := resp.StatusCode != response.statusCode
+	lengthDiff := len(body) != len(response.body)
+	return map[string]interface{}{
+		"http2_status_code":    response.statusCode,
+		"http2_content_length": len(response.body),
+		"http2_body":           response.body,
+		"http2_headers":        response.headers,
+		"http2_protocol":       response.protocol,
+		"protocol_status_diff": statusDiff,
+		"protocol_length_diff": lengthDiff,
+		"protocol_body_diff":   body != response.body,
+		"protocol_diff":        statusDiff || lengthDiff,
+	}
+}
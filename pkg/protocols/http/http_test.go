@@ -1,12 +1,16 @@
 package http
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 )
@@ -40,3 +44,38 @@ Accept-Encoding: gzip`},
 	require.Equal(t, 6, request.Requests(), "could not get correct number of requests")
 	require.Equal(t, map[string]string{"User-Agent": "test", "Hello": "World"}, request.customHeaders, "could not get correct custom headers")
 }
+
+func TestHTTPCompileGoldenFile(t *testing.T) {
+	options := testutils.DefaultOptions
+	options.AllowLocalFileAccess = true
+	defer func() { options.AllowLocalFileAccess = false }()
+
+	testutils.Init(options)
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "golden.json"), []byte(`{"status": "ok"}`), 0644)
+	require.Nil(t, err, "could not write golden file")
+
+	newRequest := func(goldenFile string) *Request {
+		return &Request{
+			Name: "testing",
+			Path: []string{"{{BaseURL}}/health"},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.GoldenMatcher}, GoldenFile: goldenFile}},
+			},
+		}
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   "testing-http",
+		Path: filepath.Join(dir, "template.yaml"),
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	request := newRequest("golden.json")
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+	matched, _ := request.Matchers[0].MatchGolden(`{"status": "ok"}`)
+	require.True(t, matched, "could not match golden file")
+
+	err = newRequest("missing.json").Compile(executerOpts)
+	require.ErrorContains(t, err, "golden file missing.json does not exist", "could not get missing golden file error")
+}
@@ -19,12 +19,17 @@ import (
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/fastdialer/fastdialer/ja3/impersonate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/proxypool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/connbehavior"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/rawheaders"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/tlsfingerprint"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types/scanstrategy"
 	"github.com/projectdiscovery/rawhttp"
 	"github.com/projectdiscovery/retryablehttp-go"
 	mapsutil "github.com/projectdiscovery/utils/maps"
+	proxyutils "github.com/projectdiscovery/utils/proxy"
 )
 
 var (
@@ -35,6 +40,9 @@ var (
 	forceMaxRedirects int
 	normalClient      *retryablehttp.Client
 	clientPool        *mapsutil.SyncLockMap[string, *retryablehttp.Client]
+	// trackedTransports are the transports of clients tracking connections
+	// shared by clients with cookie jars which are not pooled
+	trackedTransports *mapsutil.SyncLockMap[string, *http.Transport]
 	// ResponseHeaderTimeout is the timeout for response headers
 	// to be read from the server (this prevents infinite hang started by server if any)
 	ResponseHeaderTimeout = time.Duration(5) * time.Second
@@ -59,6 +67,9 @@ func Init(options *types.Options) error {
 	clientPool = &mapsutil.SyncLockMap[string, *retryablehttp.Client]{
 		Map: make(mapsutil.Map[string, *retryablehttp.Client]),
 	}
+	trackedTransports = &mapsutil.SyncLockMap[string, *http.Transport]{
+		Map: make(mapsutil.Map[string, *http.Transport]),
+	}
 
 	client, err := wrappedGet(options, &Configuration{})
 	if err != nil {
@@ -97,6 +108,12 @@ func (cc *ConnectionConfiguration) HasCookieJar() bool {
 	return cc.cookiejar != nil
 }
 
+// http versions which can be forced for a client
+const (
+	HTTPVersion11 = "1.1"
+	HTTPVersion2  = "2"
+)
+
 // Configuration contains the custom configuration options for a client
 type Configuration struct {
 	// Threads contains the threads for the client
@@ -111,6 +128,14 @@ type Configuration struct {
 	RedirectFlow RedirectFlow
 	// Connection defines custom connection configuration
 	Connection *ConnectionConfiguration
+	// Proxy is the http or socks5 proxy overriding the global proxy
+	Proxy string
+	// RawHeaders wraps connections to capture raw response headers
+	RawHeaders bool
+	// TrackConnections wraps connections to track their reuse and closes by servers
+	TrackConnections bool
+	// HTTPVersion forces the http version (1.1 or 2) of requests if set
+	HTTPVersion string
 }
 
 // Hash returns the hash of the configuration to allow client pooling
@@ -129,13 +154,27 @@ func (c *Configuration) Hash() string {
 	builder.WriteString(strconv.FormatBool(c.DisableCookie))
 	builder.WriteString("c")
 	builder.WriteString(strconv.FormatBool(c.Connection != nil))
+	if c.Proxy != "" {
+		builder.WriteString("p")
+		builder.WriteString(c.Proxy)
+	}
+	if c.RawHeaders {
+		builder.WriteString("h")
+	}
+	if c.TrackConnections {
+		builder.WriteString("k")
+	}
+	if c.HTTPVersion != "" {
+		builder.WriteString("v")
+		builder.WriteString(c.HTTPVersion)
+	}
 	hash := builder.String()
 	return hash
 }
 
 // HasStandardOptions checks whether the configuration requires custom settings
 func (c *Configuration) HasStandardOptions() bool {
-	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && c.DisableCookie && c.Connection == nil && !c.NoTimeout
+	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && c.DisableCookie && c.Connection == nil && !c.NoTimeout && c.Proxy == "" && !c.RawHeaders && !c.TrackConnections && c.HTTPVersion == ""
 }
 
 // GetRawHTTP returns the rawhttp request client
@@ -183,8 +222,8 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 	maxConnsPerHost := 0
 	maxIdleConnsPerHost := -1
 
-	if configuration.Threads > 0 || options.ScanStrategy == scanstrategy.HostSpray.String() {
-		// Single host
+	if configuration.Threads > 0 || options.ScanStrategy == scanstrategy.HostSpray.String() || configuration.TrackConnections {
+		// Single host (or connections kept alive to track their behavior)
 		retryableHttpOptions = retryablehttp.DefaultOptionsSingle
 		disableKeepAlives = false
 		maxIdleConnsPerHost = 500
@@ -235,14 +274,22 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		return nil, errors.Wrap(err, "could not create client certificate")
 	}
 
+	// http/2 is negotiated only if forced globally or by the configuration
+	forceHTTP2 := options.ForceAttemptHTTP2
+	switch configuration.HTTPVersion {
+	case HTTPVersion11:
+		forceHTTP2 = false
+	case HTTPVersion2:
+		forceHTTP2 = true
+	}
 	transport := &http.Transport{
-		ForceAttemptHTTP2: options.ForceAttemptHTTP2,
+		ForceAttemptHTTP2: forceHTTP2,
 		DialContext:       Dialer.Dial,
 		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 			if options.TlsImpersonate {
 				return Dialer.DialTLSWithConfigImpersonate(ctx, network, addr, tlsConfig, impersonate.Random, nil)
 			}
-			if options.HasClientCertificates() || options.ForceAttemptHTTP2 {
+			if options.HasClientCertificates() || forceHTTP2 {
 				return Dialer.DialTLSWithConfig(ctx, network, addr, tlsConfig)
 			}
 			return Dialer.DialTLS(ctx, network, addr)
@@ -255,12 +302,37 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		ResponseHeaderTimeout: ResponseHeaderTimeout,
 	}
 
-	if types.ProxyURL != "" {
-		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
+	// custom dialer of embedding users replaces the shared dialer
+	if protocolstate.HasCustomDialer() {
+		transport.DialContext = protocolstate.Dial
+		transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return protocolstate.DialTLSWithConfig(ctx, network, addr, tlsConfig)
+		}
+	}
+
+	// template level proxy takes precedence over the global proxy
+	proxyURL, proxySocksURL := types.ProxyURL, types.ProxySocksURL
+	if configuration.Proxy != "" {
+		proxyURL, proxySocksURL = "", ""
+		if strings.HasPrefix(configuration.Proxy, proxyutils.SOCKS5+"://") {
+			proxySocksURL = configuration.Proxy
+		} else {
+			proxyURL = configuration.Proxy
+		}
+	}
+
+	if configuration.Proxy == "" && proxypool.Default != nil {
+		// requests are rotated through the proxy pool, the transport
+		// establishes tls connections to targets through each proxy
+		transport.Proxy = proxypool.Default.Proxy
+		transport.DialContext = proxypool.Default.WrapDialer(transport.DialContext)
+		transport.DialTLSContext = proxypool.Default.WrapDialer(transport.DialTLSContext)
+	} else if proxyURL != "" {
+		if proxyURL, err := url.Parse(proxyURL); err == nil {
 			transport.Proxy = http.ProxyURL(proxyURL)
 		}
-	} else if types.ProxySocksURL != "" {
-		socksURL, proxyErr := url.Parse(types.ProxySocksURL)
+	} else if proxySocksURL != "" {
+		socksURL, proxyErr := url.Parse(proxySocksURL)
 		if proxyErr != nil {
 			return nil, proxyErr
 		}
@@ -285,6 +357,33 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		}
 	}
 
+	// tls handshakes are performed over dialed connections to fingerprint
+	// them or send a custom client hello
+	if options.TLSFingerprint || options.TLSClientHello != "" {
+		var profile *tlsfingerprint.Profile
+		if options.TLSClientHello != "" {
+			if profile, err = tlsfingerprint.ParseProfile(options.TLSClientHello); err != nil {
+				return nil, err
+			}
+		}
+		transport.DialTLSContext = tlsfingerprint.Wrap(transport.DialContext, tlsConfig, profile)
+	}
+
+	if configuration.RawHeaders {
+		transport.DialContext = rawheaders.Wrap(transport.DialContext)
+		transport.DialTLSContext = rawheaders.Wrap(transport.DialTLSContext)
+	}
+	if configuration.TrackConnections {
+		transport.DialContext = connbehavior.Wrap(transport.DialContext)
+		transport.DialTLSContext = connbehavior.Wrap(transport.DialTLSContext)
+		// connections are reused across clients of the configuration
+		if shared, ok := trackedTransports.Get(hash); ok {
+			transport = shared
+		} else if err := trackedTransports.Set(hash, transport); err != nil {
+			return nil, err
+		}
+	}
+
 	var jar *cookiejar.Jar
 	if configuration.Connection != nil && configuration.Connection.HasCookieJar() {
 		jar = configuration.Connection.GetCookieJar()
@@ -2,16 +2,20 @@ package http
 
 import (
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/redirect"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/tlsfingerprint"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
@@ -19,8 +23,15 @@ import (
 // Match matches a generic data response again a given matcher
 // TODO: Try to consolidate this in protocols.MakeDefaultMatchFunc to avoid any inconsistencies
 func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
-	item, ok := request.getMatchPart(matcher.Part, data)
-	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
+	part := matcher.Part
+	// matchers on the body evaluate the normalized body if enabled
+	if request.bodyNormalizer != nil && (part == "" || part == "body") {
+		if _, ok := data["normalized_body"]; ok {
+			part = "normalized_body"
+		}
+	}
+	item, ok := request.getMatchPart(part, data)
+	if !ok && !matcher.IsPartless() {
 		return false, []string{}
 	}
 
@@ -41,6 +52,20 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.CompareMatcher:
+		return matcher.Result(matcher.MatchCompare(data)), []string{}
+	case matchers.EntropyMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchEntropy(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.MatchJSONSchema(item)
+	case matchers.ErrorPageMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchErrorPage(item))
+	case matchers.RedirectMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchRedirect(item, data))
+	case matchers.GoldenMatcher:
+		return matcher.MatchGolden(item)
+	case matchers.SSRFMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchSSRF(item, data))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
@@ -76,6 +101,18 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractJSON(item)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.CookieExtractor:
+		return extractor.ExtractCookie(item)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(item)
+	case extractors.BinaryExtractor:
+		return extractor.ExtractBinary(item)
+	case extractors.JSONSchemaExtractor:
+		return extractor.ExtractJSONSchema(item)
+	case extractors.ErrorPageExtractor:
+		return extractor.ExtractErrorPage(item)
+	case extractors.CSSExtractor:
+		return extractor.ExtractCSS(item)
 	}
 	return nil
 }
@@ -125,6 +162,9 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	request.setHashOrDefault(data, "response", rawResp)
 	data["status_code"] = resp.StatusCode
 	request.setHashOrDefault(data, "body", body)
+	if request.bodyNormalizer != nil {
+		request.setHashOrDefault(data, "normalized_body", request.bodyNormalizer.Normalize(body))
+	}
 	request.setHashOrDefault(data, "all_headers", headers)
 	request.setHashOrDefault(data, "header", headers)
 	data["duration"] = duration.Seconds()
@@ -132,7 +172,40 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	data["template-info"] = request.options.TemplateInfo
 	data["template-path"] = request.options.TemplatePath
 
+	// connections wrapped for tls fingerprints set alpn_protocols in extra
+	// as their state is not exposed by responses
+	if resp.TLS != nil {
+		data["alpn_protocols"] = tlsfingerprint.ALPNProtocols(resp.TLS.NegotiatedProtocol)
+	} else if _, ok := data["alpn_protocols"]; !ok {
+		data["alpn_protocols"] = []string{}
+	}
 	data["content_length"] = utils.CalculateContentLength(resp.ContentLength, int64(len(body)))
+	data["response_headers"] = responseHeadersMap(resp.Header)
+
+	// server_timing holds the parsed metrics instead of the raw header value
+	// which is available in response_headers
+	timings := serverTimingMetrics(resp.Header)
+	data["server_timing"] = timings
+	for k, v := range serverTimingVariables(timings) {
+		data[k] = v
+	}
+
+	// relative redirects stay on the host of the request
+	if location := resp.Header.Get("Location"); location != "" {
+		redirectHost := redirect.Host(location)
+		if redirectHost == "" {
+			if parsed, err := url.Parse(matched); err == nil {
+				redirectHost = redirect.NormalizeHost(parsed.Host)
+			}
+		}
+		data["redirect_host"] = redirectHost
+
+		if target, err := redirect.Resolve(location, matched); err == nil {
+			for k, v := range redirectVariables(target) {
+				data[k] = v
+			}
+		}
+	}
 
 	if request.StopAtFirstMatch || request.options.StopAtFirstMatch {
 		data["stop-at-first-match"] = true
@@ -140,6 +213,52 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	return data
 }
 
+// responseHeadersMap returns the response headers by lowercased name.
+// Values of multi-valued headers are returned as arrays.
+func responseHeadersMap(header http.Header) map[string]interface{} {
+	headers := make(map[string]interface{}, len(header))
+	for k, v := range header {
+		k = strings.ToLower(k)
+		if len(v) == 1 {
+			headers[k] = v[0]
+		} else {
+			headers[k] = v
+		}
+	}
+	return headers
+}
+
+// redirectParamRegex matches characters of query parameter names
+// replaced in the names of redirect_param_<name> variables
+var redirectParamRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// redirectVariables returns the components of the resolved redirect target.
+// Query parameters are returned as a map by name with arrays for repeated
+// parameters along with flat redirect_param_<name> variables of first values.
+func redirectVariables(target *url.URL) map[string]interface{} {
+	query := target.Query()
+	params := make(map[string]interface{}, len(query))
+	variables := map[string]interface{}{
+		"redirect_url":    target.String(),
+		"redirect_scheme": target.Scheme,
+		"redirect_path":   target.Path,
+		"redirect_query":  target.RawQuery,
+		"redirect_params": params,
+	}
+	for name, values := range query {
+		if len(values) == 1 {
+			params[name] = values[0]
+		} else {
+			params[name] = values
+		}
+		flat := "redirect_param_" + redirectParamRegex.ReplaceAllString(name, "_")
+		if _, ok := variables[flat]; !ok && len(values) > 0 {
+			variables[flat] = values[0]
+		}
+	}
+	return variables
+}
+
 // TODO: disabling hdd storage while testing backpressure mechanism
 func (request *Request) setHashOrDefault(data output.InternalEvent, k string, v string) {
 	// if hash, err := request.options.Storage.SetString(v); err == nil {
@@ -185,9 +304,13 @@ func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent
 		Request:          types.ToString(wrapped.InternalEvent["request"]),
 		Response:         request.truncateResponse(wrapped.InternalEvent["response"]),
 		CURLCommand:      types.ToString(wrapped.InternalEvent["curl-command"]),
+		HTTPieCommand:    types.ToString(wrapped.InternalEvent["httpie-command"]),
 		TemplateEncoded:  request.options.EncodeTemplate(),
 		Error:            types.ToString(wrapped.InternalEvent["error"]),
 	}
+	if body, ok := wrapped.InternalEvent["curl-data-binary"].([]byte); ok {
+		data.RequestBody = body
+	}
 	return data
 }
 
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"crypto/tls"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,9 +43,44 @@ func TestResponseToDSLMap(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 18, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
+	require.Equal(t, []string{}, event["alpn_protocols"], "could not get alpn protocols of plain response")
+
+	resp.TLS = &tls.ConnectionState{NegotiatedProtocol: "h2"}
+	event = request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+	require.Equal(t, []string{"h2"}, event["alpn_protocols"], "could not get negotiated alpn protocol")
+}
+
+func TestRequestPartDefinitions(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:     templateID,
+		Name:   "testing",
+		Path:   []string{"{{BaseURL}}"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile file request")
+
+	documented := make(map[string]struct{})
+	for names := range RequestPartDefinitions {
+		for _, name := range strings.Split(names, ",") {
+			documented[name] = struct{}{}
+		}
+	}
+	event := request.responseToDSLMap(&http.Response{Header: make(http.Header)}, "http://example.com", "http://example.com", exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+	for key := range event {
+		require.Contains(t, documented, key, "variable %s is not documented in request part definitions", key)
+	}
 }
 
 func TestHTTPOperatorMatch(t *testing.T) {
@@ -71,7 +108,7 @@ func TestHTTPOperatorMatch(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 18, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
 
@@ -132,6 +169,67 @@ func TestHTTPOperatorMatch(t *testing.T) {
 		require.True(t, isMatched, "could not match valid response")
 		require.Equal(t, []string{"example domain"}, matched)
 	})
+
+	t.Run("redirect", func(t *testing.T) {
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set("Location", `/\evil.com/path`)
+		event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+		require.Equal(t, "evil.com", event["redirect_host"], "could not get redirect host")
+
+		matcher := &matchers.Matcher{
+			Type:          matchers.MatcherTypeHolder{MatcherType: matchers.RedirectMatcher},
+			RedirectHosts: []string{"evil.com"},
+		}
+		err = matcher.CompileMatchers()
+		require.Nil(t, err, "could not compile matcher")
+
+		isMatched, matchedHosts := request.Match(event, matcher)
+		require.True(t, isMatched, "could not match redirect to host")
+		require.Equal(t, []string{"evil.com"}, matchedHosts)
+
+		resp.Header.Set("Location", "/login?next=evil.com")
+		event = request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+		require.Equal(t, "example.com", event["redirect_host"], "could not get host of relative redirect")
+		isMatched, _ = request.Match(event, matcher)
+		require.False(t, isMatched, "matched relative redirect")
+		require.Equal(t, "http://example.com/login?next=evil.com", event["redirect_url"], "could not resolve relative redirect")
+		require.Equal(t, "/login", event["redirect_path"], "could not get redirect path")
+		require.Equal(t, "evil.com", event["redirect_param_next"], "could not get redirect query parameter")
+
+		resp.Header.Set("Location", `https:/\evil.com/cb?token=a&token=b&redirect-uri=%2F%2Fevil.com`)
+		event = request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+		require.Equal(t, "https://evil.com/cb?token=a&token=b&redirect-uri=%2F%2Fevil.com", event["redirect_url"], "could not resolve absolute redirect")
+		require.Equal(t, "//evil.com", event["redirect_param_redirect_uri"], "could not get decoded redirect query parameter")
+		require.Equal(t, []string{"a", "b"}, event["redirect_params"].(map[string]interface{})["token"], "could not get repeated redirect query parameter")
+
+		dslMatcher := &matchers.Matcher{
+			Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+			DSL:  []string{`redirect_param_redirect_uri == "//evil.com" && redirect_path == "/cb"`},
+		}
+		err = dslMatcher.CompileMatchers()
+		require.Nil(t, err, "could not compile matcher")
+		isMatched, _ = request.Match(event, dslMatcher)
+		require.True(t, isMatched, "could not match redirect query parameter")
+	})
+
+	t.Run("responseHeaders", func(t *testing.T) {
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set("X-Token", "abc")
+		resp.Header.Add("X-Trace", "a")
+		resp.Header.Add("X-Trace", "b")
+		event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
+		require.Equal(t, map[string]interface{}{"x-token": "abc", "x-trace": []string{"a", "b"}}, event["response_headers"], "could not get response headers map")
+
+		matcher := &matchers.Matcher{
+			Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+			DSL:  []string{`get_header(response_headers, "X-TOKEN") == "abc" && index(get_header(response_headers, "x-trace"), 1) == "b" && get_header(response_headers, "x-missing") == ""`},
+		}
+		err = matcher.CompileMatchers()
+		require.Nil(t, err, "could not compile matcher")
+
+		isMatched, _ := request.Match(event, matcher)
+		require.True(t, isMatched, "could not match response headers map")
+	})
 }
 
 func TestHTTPOperatorExtract(t *testing.T) {
@@ -159,7 +257,7 @@ func TestHTTPOperatorExtract(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 18, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test_header"], "could not get correct resp for header")
 
@@ -286,7 +384,7 @@ func TestHTTPMakeResult(t *testing.T) {
 	matched := "http://example.com/test/?test=1"
 
 	event := request.responseToDSLMap(resp, host, matched, exampleRawRequest, exampleRawResponse, exampleResponseBody, exampleResponseHeader, 1*time.Second, map[string]interface{}{})
-	require.Len(t, event, 15, "could not get correct number of items in dsl map")
+	require.Len(t, event, 18, "could not get correct number of items in dsl map")
 	require.Equal(t, exampleRawResponse, event["response"], "could not get correct resp")
 	require.Equal(t, "Test-Response", event["test"], "could not get correct resp for header")
 
@@ -304,6 +402,55 @@ func TestHTTPMakeResult(t *testing.T) {
 	require.Equal(t, "1.1.1.1", finalEvent.Results[0].ExtractedResults[0], "could not get correct extracted results")
 }
 
+func TestHTTPOperatorMatchExtractedValues(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:     templateID,
+		Name:   "testing",
+		Path:   []string{"{{BaseURL}}?test=1"},
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Operators: operators.Operators{
+			MatchersCondition: "and",
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"ip == '1.2.3.4'"},
+			}, {
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"contains(body, ip) && numbers0 == '1' && numbers3 == '4'"},
+			}},
+			Extractors: []*extractors.Extractor{{
+				Name:  "ip",
+				Part:  "body",
+				Type:  extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+				Regex: []string{"[0-9]+\\.[0-9]+\\.[0-9]+\\.[0-9]+"},
+			}, {
+				Name:     "numbers",
+				Part:     "ip",
+				Internal: true,
+				Type:     extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+				Regex:    []string{"[0-9]+"},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile file request")
+
+	for i := 0; i < 10; i++ {
+		event := output.InternalEvent{"body": "<html>1.2.3.4</html>"}
+		result, ok := request.CompiledOperators.Execute(event, request.Match, request.Extract, false)
+		require.True(t, ok, "could not match extracted values")
+		require.True(t, result.Matched, "could not match extracted values")
+		require.Equal(t, []string{"1", "2", "3", "4"}, result.DynamicValues["numbers"], "could not get deterministic extracted results")
+	}
+}
+
 const exampleRawRequest = `GET / HTTP/1.1
 Host: example.com
 Upgrade-Insecure-Requests: 1
@@ -443,3 +590,27 @@ const exampleJSONResponseBody = `
   ]
 }
 `
+
+func TestServerTimingMetrics(t *testing.T) {
+	header := make(http.Header)
+	header.Add("Server-Timing", `cache;desc="Cache Read, miss";dur=23.2, db;dur=53`)
+	header.Add("Server-Timing", `app;dur=47.2, missedCache, db;dur=1`)
+	header.Set("X-Runtime", "0.125")
+	header.Set("X-Response-Time", "12ms")
+
+	metrics := serverTimingMetrics(header)
+	require.Equal(t, map[string]interface{}{
+		"cache":           23.2,
+		"db":              53.0,
+		"app":             47.2,
+		"missedcache":     0.0,
+		"x-runtime":       125.0,
+		"x-response-time": 12.0,
+	}, metrics, "could not parse server timing metrics")
+
+	variables := serverTimingVariables(metrics)
+	require.Equal(t, 53.0, variables["server_timing_db"], "could not get metric variable")
+	require.Equal(t, 125.0, variables["server_timing_x_runtime"], "could not get performance header variable")
+
+	require.Empty(t, serverTimingMetrics(make(http.Header)), "could not handle absent headers")
+}
@@ -0,0 +1,247 @@
+package http
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// Next page detections of pagination
+const (
+	// PaginationLink follows the url of the Link header with rel="next"
+	PaginationLink = "link"
+	// PaginationJSON follows the cursor or url returned by a json query on the body
+	PaginationJSON = "json"
+	// PaginationRegex follows the url matched by a regex in the body
+	PaginationRegex = "regex"
+)
+
+// defaultMaxPages is the number of pages (including the first) fetched if not set
+const defaultMaxPages = 10
+
+// linkNextRegex matches the url of the next page in Link headers
+var linkNextRegex = regexp.MustCompile(`<([^>]*)>[^,]*;\s*rel="?next"?`)
+
+// Pagination follows the next pages of responses aggregating the results
+// extracted from all pages into the event of the first page.
+type Pagination struct {
+	// description: |
+	//   Type is the detection of the next page.
+	//
+	//   link follows the Link header with rel="next", json follows the cursor (set as
+	//   the query parameter param) or url returned by the json query and regex follows
+	//   the url matched by the regex (first group if any) in the body.
+	// values:
+	//   - "link"
+	//   - "json"
+	//   - "regex"
+	Type string `yaml:"type" json:"type" jsonschema:"title=detection of the next page,description=Detection of the next page,enum=link,enum=json,enum=regex"`
+	// description: |
+	//   JSON is the json query returning the cursor or url of the next page.
+	// examples:
+	//   - value: "\".meta.next_cursor\""
+	JSON string `yaml:"json,omitempty" json:"json,omitempty" jsonschema:"title=json query of the next page,description=JSON query returning the cursor or url of the next page"`
+	// description: |
+	//   Param is the query parameter the cursor returned by the json query is sent in.
+	//   The cursor is followed as an url if not set.
+	// examples:
+	//   - value: "\"cursor\""
+	Param string `yaml:"param,omitempty" json:"param,omitempty" jsonschema:"title=query parameter of the cursor,description=Query parameter the cursor of the next page is sent in"`
+	// description: |
+	//   Regex is the regex matching the url of the next page in the body.
+	// examples:
+	//   - value: "\"href=\\\"([^\\\"]+)\\\"[^>]*>Next\""
+	Regex string `yaml:"regex,omitempty" json:"regex,omitempty" jsonschema:"title=regex of the next page,description=Regex matching the url of the next page in the body"`
+	// description: |
+	//   MaxPages is the maximum number of pages fetched including the first page.
+	//
+	//   Default is 10.
+	MaxPages int `yaml:"max-pages,omitempty" json:"max-pages,omitempty" jsonschema:"title=maximum number of pages,description=Maximum number of pages fetched including the first page"`
+
+	jsonCompiled  *gojq.Code
+	regexCompiled *regexp.Regexp
+}
+
+// Compile compiles the next page detection of the pagination
+func (p *Pagination) Compile() error {
+	switch p.Type {
+	case PaginationLink:
+	case PaginationJSON:
+		if p.JSON == "" {
+			return errors.New("json query is required for json pagination")
+		}
+		query, err := gojq.Parse(p.JSON)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse json query %s", p.JSON)
+		}
+		if p.jsonCompiled, err = gojq.Compile(query); err != nil {
+			return errors.Wrapf(err, "could not compile json query %s", p.JSON)
+		}
+	case PaginationRegex:
+		if p.Regex == "" {
+			return errors.New("regex is required for regex pagination")
+		}
+		compiled, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return errors.Wrapf(err, "could not compile regex %s", p.Regex)
+		}
+		p.regexCompiled = compiled
+	default:
+		return errors.Errorf("invalid pagination type '%s' specified, supported values are link, json and regex", p.Type)
+	}
+	if p.MaxPages < 0 {
+		return errors.New("max-pages can't be negative")
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = defaultMaxPages
+	}
+	return nil
+}
+
+// next returns the url of the next page of the response of the event for
+// the page at current or an empty string if there is none. Pages of other
+// hosts are not followed.
+func (p *Pagination) next(event output.InternalEvent, current *url.URL) string {
+	var next string
+	switch p.Type {
+	case PaginationLink:
+		if match := linkNextRegex.FindStringSubmatch(types.ToString(event["link"])); match != nil {
+			next = match[1]
+		}
+	case PaginationJSON:
+		cursor := p.cursor(types.ToString(event["body"]))
+		if cursor == "" {
+			return ""
+		}
+		if p.Param == "" {
+			next = cursor
+			break
+		}
+		nextURL := *current
+		query := nextURL.Query()
+		query.Set(p.Param, cursor)
+		nextURL.RawQuery = query.Encode()
+		return nextURL.String()
+	case PaginationRegex:
+		if match := p.regexCompiled.FindStringSubmatch(types.ToString(event["body"])); match != nil {
+			next = match[len(match)-1]
+		}
+	}
+	if next = strings.TrimSpace(next); next == "" {
+		return ""
+	}
+	resolved, err := current.Parse(next)
+	if err != nil || resolved.Host != current.Host {
+		return ""
+	}
+	return resolved.String()
+}
+
+// pageURL returns the final url (after redirects) of the page of the event
+// or fallback if the event has none
+func pageURL(event output.InternalEvent, fallback *url.URL) *url.URL {
+	if parsed, err := url.Parse(types.ToString(event["matched"])); err == nil && parsed.Host != "" {
+		return parsed
+	}
+	return fallback
+}
+
+// cursor returns the first scalar value returned by the json query on body
+func (p *Pagination) cursor(body string) string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return ""
+	}
+	iter := p.jsonCompiled.Run(data)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			return ""
+		}
+		if _, ok := value.(error); ok || value == nil {
+			return ""
+		}
+		if cursor, err := types.JSONScalarToString(value); err == nil {
+			return cursor
+		}
+	}
+}
+
+// followPages fetches the next pages of the first request of the event until no
+// next page is found or max pages are fetched and merges the results
+// extracted from them into the event.
+func (request *Request) followPages(input *contextargs.Context, first *generatedRequest, previousEvent output.InternalEvent, event *output.InternalWrappedEvent) {
+	current := first.request.Request.URL
+	visited := map[string]struct{}{current.String(): {}}
+	pageEvent := event.InternalEvent
+
+	var merged bool
+	for page := 1; page < request.Pagination.MaxPages; page++ {
+		// next pages are resolved against the url of the page after redirects
+		current = pageURL(pageEvent, current)
+		next := request.Pagination.next(pageEvent, current)
+		if next == "" {
+			break
+		}
+		if _, ok := visited[next]; ok {
+			break
+		}
+		visited[next] = struct{}{}
+
+		nextURL, err := urlutil.ParseURL(next, true)
+		if err != nil {
+			break
+		}
+		req := first.request.Clone(input.Context())
+		req.SetURL(nextURL)
+		pageRequest := &generatedRequest{
+			original:      request,
+			request:       req,
+			meta:          first.meta,
+			dynamicValues: first.dynamicValues,
+			paginated:     true,
+		}
+
+		request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+		request.options.Progress.AddToTotal(1)
+		pageEvent = nil
+		err = request.executeRequest(input, pageRequest, previousEvent, false, func(wrapped *output.InternalWrappedEvent) {
+			// the final response of the page is returned first for redirect chains
+			if pageEvent == nil {
+				pageEvent = wrapped.InternalEvent
+			}
+			if wrapped.OperatorsResult == nil {
+				return
+			}
+			result := wrapped.OperatorsResult
+			result.PayloadValues = nil
+			if event.OperatorsResult == nil {
+				result.PayloadValues = first.meta
+				event.OperatorsResult = result
+			} else {
+				event.OperatorsResult.Merge(result)
+			}
+			merged = true
+		}, 0)
+		if err != nil {
+			gologger.Verbose().Msgf("[%s] Could not fetch page %d of %s: %s\n", request.options.TemplateID, page+1, input.MetaInput.Input, err)
+			break
+		}
+		if pageEvent == nil {
+			break
+		}
+		current = req.Request.URL
+	}
+	if merged {
+		event.Results = request.MakeResultEvent(event)
+	}
+}
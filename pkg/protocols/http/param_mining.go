@@ -0,0 +1,122 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/rs/xid"
+)
+
+// paramMiningVar is the per-input variable enabling parameter mining for the input
+const paramMiningVar = "param_mining"
+
+// shouldMineParameters returns true if the template mines parameters and mining
+// is enabled for all inputs (-fuzz-param-mining) or for the input by its
+// per-input variables (param_mining=true). Mining sends up to max-requests
+// requests per input so it is never performed implicitly.
+func (request *Request) shouldMineParameters(input *contextargs.Context) bool {
+	if request.ParamMining == nil {
+		return false
+	}
+	if request.options.Options.FuzzParamMining {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(types.ToString(request.options.InputVars.Get(input.MetaInput.Input)[paramMiningVar]))
+	return enabled
+}
+
+// mineParameters tries parameter names of the mining wordlist on the base
+// request and returns the base request with discovered parameters added.
+// The base request is returned as is if no parameters were discovered.
+func (request *Request) mineParameters(input *contextargs.Context, baseRequest *retryablehttp.Request) *retryablehttp.Request {
+	// mining requests are not sent in request dump mode
+	if request.options.RequestDump != nil {
+		return baseRequest
+	}
+	mining := request.ParamMining
+
+	// repeated baseline responses gauge the natural size variance
+	// of the response so dynamic content is not mistaken for a parameter
+	first, err := request.fetchMiningBody(input, baseRequest)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] fuzz: could not fetch param mining baseline for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return baseRequest
+	}
+	second, err := request.fetchMiningBody(input, baseRequest)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] fuzz: could not fetch param mining baseline for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return baseRequest
+	}
+	variance := len(first) - len(second)
+	if variance < 0 {
+		variance = -variance
+	}
+	sent := 2
+
+	mined := baseRequest
+	var discovered []string
+mining:
+	for _, part := range mining.Parts {
+		if part == fuzz.MiningPartBody && (baseRequest.Method == http.MethodGet || baseRequest.Method == http.MethodHead) {
+			continue
+		}
+		for _, name := range mining.Words() {
+			if sent >= mining.MaxRequests {
+				gologger.Verbose().Msgf("[%s] fuzz: param mining request limit reached for %s\n", request.options.TemplateID, input.MetaInput.Input)
+				break mining
+			}
+			select {
+			case <-input.Context().Done():
+				break mining
+			default:
+			}
+			if part == fuzz.MiningPartQuery && baseRequest.URL.Query().Has(name) {
+				continue
+			}
+
+			canary := xid.New().String()
+			probe, err := fuzz.AddParameter(baseRequest, part, name, canary)
+			if err != nil {
+				gologger.Verbose().Msgf("[%s] fuzz: could not mine %s parameters for %s: %s\n", request.options.TemplateID, part, input.MetaInput.Input, err)
+				continue mining
+			}
+			sent++
+			body, err := request.fetchMiningBody(input, probe)
+			if err != nil {
+				continue
+			}
+			if !mining.IsDiscovered(len(first), variance, body, canary) {
+				continue
+			}
+			if updated, err := fuzz.AddParameter(mined, part, name, "1"); err == nil {
+				mined = updated
+				discovered = append(discovered, part+":"+name)
+			}
+		}
+	}
+	if len(discovered) > 0 {
+		gologger.Verbose().Msgf("[%s] fuzz: discovered parameters for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, strings.Join(discovered, ","))
+	}
+	return mined
+}
+
+// fetchMiningBody sends a param mining request and returns its response body
+func (request *Request) fetchMiningBody(input *contextargs.Context, req *retryablehttp.Request) (string, error) {
+	resp, err := request.sendAuxiliaryRequest(input, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodyRead))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// ErrPrerequisiteFailed is returned when a prerequisite of a request did not pass
+var ErrPrerequisiteFailed = errors.New("prerequisite failed")
+
+// executePrerequisites executes prerequisite requests of request for an input
+// returning values extracted by them. An error describing the failed prerequisite
+// is returned if any of them did not match.
+func (request *Request) executePrerequisites(input *contextargs.Context, dynamicValues, previous output.InternalEvent) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for i, prerequisite := range request.Prerequisites {
+		name := prerequisite.ID
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		request.options.Progress.AddToTotal(int64(prerequisite.Requests()))
+
+		var matched, responded bool
+		err := prerequisite.ExecuteWithResults(input, dynamicValues, previous, func(event *output.InternalWrappedEvent) {
+			if event == nil || event.InternalEvent == nil {
+				return
+			}
+			if _, ok := event.InternalEvent["error_type"]; !ok {
+				responded = true
+			}
+			if event.OperatorsResult == nil {
+				return
+			}
+			if event.OperatorsResult.Matched {
+				matched = true
+			}
+			for k, v := range event.OperatorsResult.DynamicValues {
+				if len(v) > 0 {
+					values[k] = v[0]
+				}
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrapf(ErrPrerequisiteFailed, "prerequisite %s: %s", name, err)
+		}
+		if prerequisite.CompiledOperators == nil || len(prerequisite.CompiledOperators.Matchers) == 0 {
+			if !responded {
+				return nil, errors.Wrapf(ErrPrerequisiteFailed, "prerequisite %s: no response", name)
+			}
+			continue
+		}
+		if !matched {
+			return nil, errors.Wrapf(ErrPrerequisiteFailed, "prerequisite %s: matchers did not match", name)
+		}
+	}
+	return values, nil
+}
@@ -320,6 +320,9 @@ func (r *Request) ApplyAuthStrategy(strategy authx.AuthStrategy) {
 		r.Headers["Authorization"] = "Bearer " + s.Data.Token
 	case *authx.BasicAuthStrategy:
 		r.Headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.Data.Username+":"+s.Data.Password))
+	case *authx.DigestAuthStrategy:
+		// digest auth requires a challenge-response handshake handled by the http client
+		gologger.Warning().Msgf("[raw-request] digest auth is not supported for unsafe raw requests")
 	default:
 		gologger.Warning().Msgf("[raw-request] unknown auth strategy: %T", s)
 	}
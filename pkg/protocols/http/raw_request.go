@@ -0,0 +1,48 @@
+package http
+
+import (
+	"strings"
+)
+
+// rawRequestPart is the part containing the raw bytes of the outgoing request
+const rawRequestPart = "raw_request"
+
+// usesRawRequest returns true if matchers or extractors of the request run
+// on or reference the raw outgoing request so that it is only captured
+// when needed
+func (request *Request) usesRawRequest() bool {
+	if request.CompiledOperators == nil {
+		return false
+	}
+	for _, matcher := range request.CompiledOperators.Matchers {
+		if matcher.Part == rawRequestPart || referencesRawRequest(matcher.DSL) {
+			return true
+		}
+	}
+	for _, extractor := range request.CompiledOperators.Extractors {
+		if extractor.Part == rawRequestPart || referencesRawRequest(extractor.DSL) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesRawRequest returns true if any dsl expression references the raw request
+func referencesRawRequest(expressions []string) bool {
+	for _, expression := range expressions {
+		if strings.Contains(expression, rawRequestPart) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawRequestBytes returns the bytes of the request as it is sent: the
+// unsafe raw bytes of unsafe requests or the dump of the built request
+// after auth strategies and body transformations were applied.
+func rawRequestBytes(generatedRequest *generatedRequest, reqURL string) ([]byte, error) {
+	if generatedRequest.rawRequest != nil && len(generatedRequest.rawRequest.UnsafeRawBytes) > 0 && (generatedRequest.original == nil || !generatedRequest.original.RawSocket) {
+		return generatedRequest.rawRequest.UnsafeRawBytes, nil
+	}
+	return dump(generatedRequest, reqURL)
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/rawsocket"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// rawSocketResult is the result of a request sent using a raw socket
+type rawSocketResult struct {
+	resp      *http.Response
+	formedURL string
+	hostname  string
+	// raw is the response as received
+	raw []byte
+}
+
+// executeRawSocket writes the exact bytes of the generated request to a
+// raw socket and returns the leniently parsed response.
+func (request *Request) executeRawSocket(input *contextargs.Context, generatedRequest *generatedRequest) (*rawSocketResult, error) {
+	result := &rawSocketResult{}
+	var data []byte
+	method := http.MethodGet
+	switch {
+	case generatedRequest.rawRequest != nil:
+		result.formedURL = generatedRequest.rawRequest.FullURL
+		if result.formedURL == "" {
+			result.formedURL = input.MetaInput.Input + generatedRequest.rawRequest.Path
+		}
+		method = generatedRequest.rawRequest.Method
+		data = generatedRequest.rawRequest.UnsafeRawBytes
+	case generatedRequest.request != nil:
+		result.formedURL = generatedRequest.request.URL.String()
+		method = generatedRequest.request.Method
+		dumped, err := rawsocket.DumpRequest(generatedRequest.request)
+		if err != nil {
+			return result, err
+		}
+		data = dumped
+	}
+	if len(data) == 0 {
+		return result, errorutil.New("no raw request data to send for %s", result.formedURL)
+	}
+
+	parsed, err := url.Parse(result.formedURL)
+	if err != nil {
+		return result, errorutil.NewWithErr(err).Msgf("could not parse url %s", result.formedURL)
+	}
+	result.hostname = parsed.Host
+	address := parsed.Host
+	if parsed.Port() == "" {
+		port := "80"
+		if parsed.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	raw, err := rawsocket.Send(input.Context(), protocolstate.Dialer, parsed.Scheme, address, data, rawsocket.Options{
+		Timeout:         httpclientpool.GetHttpTimeout(request.options.Options),
+		MaxResponseSize: request.MaxSize,
+		SNI:             request.options.Options.SNI,
+		Method:          method,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.raw = raw
+	result.resp = rawsocket.ParseResponse(raw, &http.Request{Method: method, URL: parsed})
+	return result, nil
+}
+
+// rawSocketDump returns the exact bytes sent for a raw socket request
+func rawSocketDump(req *generatedRequest) ([]byte, error) {
+	if req.rawRequest != nil {
+		return req.rawRequest.UnsafeRawBytes, nil
+	}
+	data, err := rawsocket.DumpRequest(req.request)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).WithTag("http").Msgf("could not dump raw socket request: %v", req.request.URL.String())
+	}
+	return data, nil
+}
+
+// sentPath returns the path of the request target of a request line as
+// written on the wire (without query and authority of absolute targets)
+func sentPath(requestLine string) string {
+	_, target, found := strings.Cut(requestLine, " ")
+	if !found {
+		return ""
+	}
+	if index := strings.LastIndex(target, " "); index != -1 {
+		target = target[:index]
+	}
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = "/"
+		if index := strings.Index(rest, "/"); index != -1 {
+			target = rest[index:]
+		}
+	}
+	path, _, _ := strings.Cut(target, "?")
+	return path
+}
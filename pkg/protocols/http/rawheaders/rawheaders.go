@@ -0,0 +1,198 @@
+// Package rawheaders captures response headers as received on the wire
+// preserving their original order and casing which are normalized away
+// by the go http client.
+//
+// Connections of clients capturing raw headers are wrapped to record the
+// response header block read after a request is assigned a connection.
+// Capturing is only possible for HTTP/1.x responses since HTTP/2 header
+// names are always lowercase and hpack decoding does not keep their order.
+// Wrapped tls connections are not recognized by the transport which as a
+// result always uses HTTP/1.1 for them.
+package rawheaders
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxHeaderSize is the maximum size of the captured header block
+const MaxHeaderSize = 64 * 1024
+
+var headerTerminator = []byte("\r\n\r\n")
+
+// DialFunc is a function dialing a network connection
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Header is a response header with its original casing
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Capture contains the raw header block of a response
+type Capture struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+	done   bool
+}
+
+// Conn is a connection recording response header blocks for captures
+type Conn struct {
+	net.Conn
+	capture atomic.Pointer[Capture]
+}
+
+// Wrap wraps a dial function so dialed connections record raw headers
+func Wrap(dial DialFunc) DialFunc {
+	if dial == nil {
+		return nil
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &Conn{Conn: conn}, nil
+	}
+}
+
+// Read reads data from the connection recording it to the armed capture
+func (c *Conn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		if capture := c.capture.Load(); capture != nil && capture.write(b[:n]) {
+			c.capture.CompareAndSwap(capture, nil)
+		}
+	}
+	return n, err
+}
+
+// NetConn returns the wrapped connection
+func (c *Conn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// WithCapture returns a context capturing raw headers of the response of
+// requests using it. Only the headers of the last response are kept when
+// redirects are followed.
+func WithCapture(ctx context.Context) (context.Context, *Capture) {
+	capture := &Capture{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if conn, ok := info.Conn.(*Conn); ok {
+				capture.reset()
+				conn.capture.Store(capture)
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), capture
+}
+
+func (c *Capture) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buffer.Reset()
+	c.done = false
+}
+
+// write records data read from the connection and returns true once the
+// header block of the final (non 1xx) response has been recorded
+func (c *Capture) write(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done {
+		return true
+	}
+	for len(data) > 0 {
+		// only scan the newly appended data along with a possible partial terminator
+		start := c.buffer.Len() - len(headerTerminator) + 1
+		if start < 0 {
+			start = 0
+		}
+		c.buffer.Write(data)
+		index := bytes.Index(c.buffer.Bytes()[start:], headerTerminator)
+		if index == -1 {
+			if c.buffer.Len() > MaxHeaderSize {
+				c.buffer.Reset()
+				c.done = true
+			}
+			return c.done
+		}
+		end := start + index + len(headerTerminator)
+		block := c.buffer.Bytes()[:end]
+		if !isInformational(block) {
+			c.buffer.Truncate(end)
+			c.done = true
+			return true
+		}
+		// skip informational responses preceding the final response
+		data = append([]byte{}, c.buffer.Bytes()[end:]...)
+		c.buffer.Reset()
+	}
+	return false
+}
+
+// isInformational returns true if the header block is of a 1xx response
+// other than 101 switching protocols after which no response follows
+func isInformational(block []byte) bool {
+	parts := bytes.SplitN(block, []byte(" "), 3)
+	if len(parts) < 2 || len(parts[1]) != 3 {
+		return false
+	}
+	return parts[1][0] == '1' && string(parts[1]) != "101"
+}
+
+// Raw returns the raw header block of the response excluding the status line.
+// An empty string is returned if headers could not be captured.
+func (c *Capture) Raw() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.done || !bytes.HasPrefix(c.buffer.Bytes(), []byte("HTTP/1.")) {
+		return ""
+	}
+	block := strings.TrimSuffix(c.buffer.String(), string(headerTerminator))
+	if index := strings.Index(block, "\r\n"); index != -1 {
+		return block[index+2:]
+	}
+	return ""
+}
+
+// Headers returns the captured headers in the received order
+func (c *Capture) Headers() []Header {
+	raw := c.Raw()
+	if raw == "" {
+		return nil
+	}
+	var headers []Header
+	for _, line := range strings.Split(raw, "\r\n") {
+		// obsolete line folding continues the previous header value
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(headers) > 0 {
+			headers[len(headers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		headers = append(headers, Header{Name: name, Value: strings.TrimSpace(value)})
+	}
+	return headers
+}
+
+// Order returns the names of headers in the received order with their
+// original casing separated by commas
+func Order(headers []Header) string {
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		names = append(names, header.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,68 @@
+package rawheaders
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					if _, err := http.ReadRequest(reader); err != nil {
+						return
+					}
+					_, _ = io.WriteString(conn, "HTTP/1.1 100 Continue\r\n\r\nHTTP/1.1 200 OK\r\nserver: test\r\nX-Custom-HEADER: value\r\nContent-Length: 2\r\n\r\nok")
+				}
+			}(conn)
+		}
+	}()
+
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &http.Transport{DialContext: Wrap(dialer.DialContext)}}
+
+	for i := 0; i < 2; i++ {
+		ctx, capture := WithCapture(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+listener.Addr().String(), nil)
+		require.Nil(t, err, "could not create request")
+		resp, err := client.Do(req)
+		require.Nil(t, err, "could not do request")
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.Equal(t, "ok", string(body), "could not read body")
+
+		require.Equal(t, "server: test\r\nX-Custom-HEADER: value\r\nContent-Length: 2", capture.Raw(), "could not capture raw headers")
+		headers := capture.Headers()
+		require.Equal(t, "server,X-Custom-HEADER,Content-Length", Order(headers), "could not capture header order")
+		require.Equal(t, Header{Name: "X-Custom-HEADER", Value: "value"}, headers[1], "could not parse header")
+	}
+}
+
+func TestCaptureIncomplete(t *testing.T) {
+	capture := &Capture{}
+	require.False(t, capture.write([]byte("HTTP/1.1 200 OK\r\nServer: te")), "completed partial header block")
+	require.Empty(t, capture.Raw(), "returned partial header block")
+	require.True(t, capture.write([]byte("st\r\n\r\nbody")), "could not complete header block")
+	require.Equal(t, "Server: test", capture.Raw(), "could not capture split header block")
+
+	capture = &Capture{}
+	require.True(t, capture.write([]byte("\x00\x00\x12\x04\r\n\r\n")), "could not complete header block")
+	require.Empty(t, capture.Raw(), "returned non http/1 header block")
+}
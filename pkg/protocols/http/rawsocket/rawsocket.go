@@ -0,0 +1,234 @@
+// Package rawsocket sends http requests as exact bytes written to a tcp or
+// tls connection bypassing the normalization and validation done by the go
+// http client, allowing malformed requests (ex: duplicate Content-Length,
+// invalid chunking, bare line feeds in headers) to reach the target.
+//
+// Responses are read back as raw bytes and parsed leniently so matchers can
+// be evaluated even on malformed responses.
+package rawsocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/retryablehttp-go"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// DefaultMaxResponseSize is the default maximum size of the raw response read
+const DefaultMaxResponseSize = 10 * 1024 * 1024
+
+var headerTerminator = []byte("\r\n\r\n")
+
+// Options contains the options for sending a raw request
+type Options struct {
+	// Timeout is the timeout for the whole exchange
+	Timeout time.Duration
+	// MaxResponseSize is the maximum size of the raw response read
+	MaxResponseSize int
+	// SNI is the tls server name sent for https targets
+	SNI string
+	// Method is the method of the request used to detect responses without body
+	Method string
+}
+
+// Send writes data to a connection dialed to address (over tls for https
+// scheme) and returns the raw response read until the connection is closed,
+// the response is complete or the timeout is reached. Partial responses read
+// before the timeout are returned without error.
+func Send(ctx context.Context, dialer *fastdialer.Dialer, scheme, address string, data []byte, options Options) ([]byte, error) {
+	if options.MaxResponseSize <= 0 {
+		options.MaxResponseSize = DefaultMaxResponseSize
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	var conn net.Conn
+	var err error
+	switch {
+	case strings.EqualFold(scheme, "https") && protocolstate.HasCustomDialer():
+		tlsConfig := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, ServerName: options.SNI}
+		conn, err = protocolstate.DialTLSWithConfig(ctx, "tcp", address, tlsConfig)
+	case strings.EqualFold(scheme, "https"):
+		tlsConfig := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, ServerName: options.SNI}
+		conn, err = dialer.DialTLSWithConfig(ctx, "tcp", address, tlsConfig)
+	case protocolstate.HasCustomDialer():
+		conn, err = protocolstate.Dial(ctx, "tcp", address)
+	default:
+		conn, err = dialer.Dial(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not dial %s", address)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(data); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not write raw request")
+	}
+
+	var response bytes.Buffer
+	buffer := make([]byte, 32*1024)
+	for response.Len() < options.MaxResponseSize {
+		n, err := conn.Read(buffer)
+		if n > 0 {
+			response.Write(buffer[:n])
+			if IsComplete(response.Bytes(), options.Method) {
+				break
+			}
+		}
+		if err != nil {
+			if response.Len() > 0 || err == io.EOF {
+				break
+			}
+			return nil, errorutil.NewWithErr(err).Msgf("could not read raw response")
+		}
+	}
+	data = response.Bytes()
+	if len(data) > options.MaxResponseSize {
+		data = data[:options.MaxResponseSize]
+	}
+	return data, nil
+}
+
+// IsComplete returns true if data contains a complete http response as per
+// its Content-Length or chunked encoding. Responses delimited by the closing
+// of the connection are never complete.
+func IsComplete(data []byte, method string) bool {
+	for {
+		index := bytes.Index(data, headerTerminator)
+		if index == -1 {
+			return false
+		}
+		head, body := data[:index], data[index+len(headerTerminator):]
+		statusCode, headers := parseHead(head)
+		// skip informational responses preceding the final response
+		if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+			data = body
+			continue
+		}
+		if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified || strings.EqualFold(method, http.MethodHead) {
+			return true
+		}
+		if strings.Contains(strings.ToLower(headers.Get("Transfer-Encoding")), "chunked") {
+			return bytes.HasSuffix(body, []byte("0\r\n\r\n"))
+		}
+		if value := headers.Get("Content-Length"); value != "" {
+			length, err := strconv.Atoi(strings.TrimSpace(value))
+			return err == nil && len(body) >= length
+		}
+		return false
+	}
+}
+
+// parseHead leniently parses the status code and headers of a response head
+func parseHead(head []byte) (int, http.Header) {
+	headers := make(http.Header)
+	lines := strings.Split(string(head), "\n")
+	var statusCode int
+	if parts := strings.Fields(lines[0]); len(parts) >= 2 {
+		statusCode, _ = strconv.Atoi(parts[1])
+	}
+	for _, line := range lines[1:] {
+		name, value, found := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !found {
+			continue
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return statusCode, headers
+}
+
+// ParseResponse leniently parses a raw response. Responses which cannot be
+// parsed are returned with a zero status code and the raw response as body.
+// Bodies with invalid chunking are returned as received.
+func ParseResponse(data []byte, req *http.Request) *http.Response {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return &http.Response{
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader(data)),
+			Request:    req,
+		}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if index := bytes.Index(data, headerTerminator); index != -1 {
+			body = data[index+len(headerTerminator):]
+		}
+		resp.TransferEncoding = nil
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp
+}
+
+// DumpRequest serializes a request to bytes without the sanitization of the go
+// http client. Header values are written verbatim and duplicate headers are
+// preserved. A Content-Length header is only added if the request has a body
+// and neither Content-Length nor Transfer-Encoding headers are set.
+func DumpRequest(req *retryablehttp.Request) ([]byte, error) {
+	var buffer bytes.Buffer
+	// request target and version are written verbatim if set (ex: by request-line fuzzing)
+	requestURI := req.Request.RequestURI
+	if requestURI == "" {
+		requestURI = req.Request.URL.RequestURI()
+	}
+	version := req.Proto
+	if version == "" {
+		version = "HTTP/1.1"
+	}
+	buffer.WriteString(req.Method + " " + requestURI + " " + version + "\r\n")
+
+	host := req.Host
+	if host == "" {
+		host = req.Request.URL.Host
+	}
+	if len(req.Header.Values("Host")) == 0 {
+		buffer.WriteString("Host: " + host + "\r\n")
+	}
+
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read request body")
+	}
+	keys := make([]string, 0, len(req.Header))
+	hasLength := false
+	for key := range req.Header {
+		keys = append(keys, key)
+		if strings.EqualFold(key, "Content-Length") || strings.EqualFold(key, "Transfer-Encoding") {
+			hasLength = true
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range req.Header[key] {
+			buffer.WriteString(key + ": " + value + "\r\n")
+		}
+	}
+	if len(body) > 0 && !hasLength {
+		buffer.WriteString("Content-Length: " + strconv.Itoa(len(body)) + "\r\n")
+	}
+	buffer.WriteString("\r\n")
+	buffer.Write(body)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+package rawsocket
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffer := make([]byte, 1024)
+		n, _ := conn.Read(buffer)
+		received <- string(buffer[:n])
+		// response is complete as per content-length, connection is kept open
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+		time.Sleep(2 * time.Second)
+	}()
+
+	options := fastdialer.DefaultOptions
+	options.EnableFallback = true
+	dialer, err := fastdialer.NewDialer(options)
+	require.Nil(t, err, "could not create dialer")
+
+	data := "POST / HTTP/1.1\r\nHost: test\r\nContent-Length: 4\r\nContent-Length: 0\r\n\r\ntest"
+	response, err := Send(context.Background(), dialer, "http", listener.Addr().String(), []byte(data), Options{Timeout: 5 * time.Second, Method: http.MethodPost})
+	require.Nil(t, err, "could not send raw request")
+	require.Equal(t, data, <-received, "could not send exact bytes")
+	require.Equal(t, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok", string(response), "could not read raw response")
+}
+
+func TestIsComplete(t *testing.T) {
+	require.True(t, IsComplete([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"), http.MethodGet), "could not complete content-length response")
+	require.False(t, IsComplete([]byte("HTTP/1.1 200 OK\r\nContent-Length: 4\r\n\r\nok"), http.MethodGet), "completed partial response")
+	require.True(t, IsComplete([]byte("HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n2\r\nok\r\n0\r\n\r\n"), http.MethodGet), "could not complete chunked response")
+	require.True(t, IsComplete([]byte("HTTP/1.1 100 Continue\r\n\r\nHTTP/1.1 204 No Content\r\n\r\n"), http.MethodGet), "could not skip informational response")
+	require.True(t, IsComplete([]byte("HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\n"), http.MethodHead), "could not complete head response")
+	require.False(t, IsComplete([]byte("HTTP/1.1 200 OK\r\n\r\nbody"), http.MethodGet), "completed connection delimited response")
+}
+
+func TestParseResponse(t *testing.T) {
+	resp := ParseResponse([]byte("HTTP/1.1 200 OK\r\nX-Test: value\r\nTransfer-Encoding: chunked\r\n\r\nzz\r\ninvalid"), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "could not parse status code")
+	require.Equal(t, "value", resp.Header.Get("X-Test"), "could not parse header")
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, "zz\r\ninvalid", string(body), "could not return malformed chunked body")
+
+	resp = ParseResponse([]byte("GARBAGE"), nil)
+	require.Equal(t, 0, resp.StatusCode, "parsed malformed status line")
+	body, _ = io.ReadAll(resp.Body)
+	require.Equal(t, "GARBAGE", string(body), "could not return malformed response as body")
+}
+
+func TestDumpRequest(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodPost, "http://example.com/path?a=1", strings.NewReader("body"))
+	require.Nil(t, err, "could not create request")
+	req.Header["Content-Length"] = []string{"4", "0"}
+	req.Header["X-Injected"] = []string{"value\r\nX-Smuggled: yes"}
+
+	dumped, err := DumpRequest(req)
+	require.Nil(t, err, "could not dump request")
+	require.Equal(t, "POST /path?a=1 HTTP/1.1\r\nHost: example.com\r\nContent-Length: 4\r\nContent-Length: 0\r\nX-Injected: value\r\nX-Smuggled: yes\r\n\r\nbody", string(dumped), "could not dump request verbatim")
+}
+
+func TestDumpRequestRequestLine(t *testing.T) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, "http://example.com/path", nil)
+	require.Nil(t, err, "could not create request")
+	req.Method = "GET /injected"
+	req.Request.RequestURI = "*"
+	req.Proto = "HTTP/9.9"
+
+	dumped, err := DumpRequest(req)
+	require.Nil(t, err, "could not dump request")
+	require.Equal(t, "GET /injected * HTTP/9.9\r\nHost: example.com\r\n\r\n", string(dumped), "could not dump request line verbatim")
+}
@@ -0,0 +1,118 @@
+// Package reproduce generates shell commands (curl and httpie) reproducing
+// http requests as sent, including fuzzed payloads of paths, headers and
+// bodies.
+//
+// Arguments are single quoted so that no shell expansion happens. Binary
+// bodies, which cannot be passed as arguments, are read from BodyFile which
+// must contain the body when the command is run. Proof bundles include the
+// file while json output and reports include the body base64 encoded.
+package reproduce
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// BodyFile is the file binary request bodies are read from by commands
+const BodyFile = "request-body.bin"
+
+// skippedHeaders are the headers computed by the clients from the request
+var skippedHeaders = map[string]struct{}{
+	"Content-Length": {},
+}
+
+// Curl returns the curl command sending the request with body. Paths are
+// sent as is so that traversal payloads are not normalized by curl.
+func Curl(req *http.Request, body []byte) string {
+	args := []string{"curl", "-k", "--path-as-is", "-g"}
+	if req.Method == http.MethodHead {
+		args = append(args, "-I")
+	} else {
+		args = append(args, "-X", quote(req.Method))
+	}
+	for _, header := range headersOf(req) {
+		// empty headers are sent with a semicolon, a colon removes them
+		if header[1] == "" {
+			args = append(args, "-H", quote(header[0]+";"))
+		} else {
+			args = append(args, "-H", quote(header[0]+": "+header[1]))
+		}
+	}
+	switch {
+	case len(body) == 0:
+	case IsBinary(body):
+		args = append(args, "--data-binary", quote("@"+BodyFile))
+	default:
+		args = append(args, "--data-raw", quote(string(body)))
+	}
+	args = append(args, quote(req.URL.String()))
+	return strings.Join(args, " ")
+}
+
+// HTTPie returns the httpie command sending the request with body
+func HTTPie(req *http.Request, body []byte) string {
+	args := []string{"http", "--verify=no", "--path-as-is"}
+	switch {
+	case len(body) == 0:
+		args = append(args, "--ignore-stdin")
+	case !IsBinary(body):
+		args = append(args, "--raw", quote(string(body)))
+	}
+	args = append(args, quote(req.Method), quote(req.URL.String()))
+	for _, header := range headersOf(req) {
+		// empty headers are sent with a semicolon, a colon removes them
+		if header[1] == "" {
+			args = append(args, quote(header[0]+";"))
+		} else {
+			args = append(args, quote(header[0]+":"+header[1]))
+		}
+	}
+	if len(body) > 0 && IsBinary(body) {
+		args = append(args, "<", quote(BodyFile))
+	}
+	return strings.Join(args, " ")
+}
+
+// IsBinary returns true if the body is not valid utf-8 text or contains
+// control characters other than whitespace
+func IsBinary(body []byte) bool {
+	if !utf8.Valid(body) {
+		return true
+	}
+	for _, c := range body {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' {
+			return true
+		}
+	}
+	return false
+}
+
+// headersOf returns the names and values of the headers of the request sorted
+// by name (and of the host header if it differs from the host of the url)
+func headersOf(req *http.Request) [][2]string {
+	var headers [][2]string
+	if req.Host != "" && req.Host != req.URL.Host {
+		headers = append(headers, [2]string{"Host", req.Host})
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if _, ok := skippedHeaders[http.CanonicalHeaderKey(name)]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			headers = append(headers, [2]string{name, value})
+		}
+	}
+	return headers
+}
+
+// quote single quotes value for posix shells
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
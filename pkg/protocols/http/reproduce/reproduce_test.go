@@ -0,0 +1,32 @@
+package reproduce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/a/../b?q=it's", nil)
+	require.Nil(t, err, "could not create request")
+	req.Host = "internal.example.com"
+	req.Header.Set("X-Payload", "'; id #")
+	req.Header.Set("X-Empty", "")
+	req.Header.Set("Content-Length", "12")
+
+	body := []byte(`{"q":"a'b"}`)
+	require.Equal(t, `curl -k --path-as-is -g -X 'POST' -H 'Host: internal.example.com' -H 'X-Empty;' -H 'X-Payload: '\''; id #' --data-raw '{"q":"a'\''b"}' 'http://example.com/a/../b?q=it'\''s'`, Curl(req, body), "could not generate curl command")
+	require.Equal(t, `http --verify=no --path-as-is --raw '{"q":"a'\''b"}' 'POST' 'http://example.com/a/../b?q=it'\''s' 'Host:internal.example.com' 'X-Empty;' 'X-Payload:'\''; id #'`, HTTPie(req, body), "could not generate httpie command")
+
+	binary := []byte{0x00, 0xff, 'a'}
+	require.True(t, IsBinary(binary), "could not detect binary body")
+	require.False(t, IsBinary([]byte("line\r\n\tnext")), "detected text body as binary")
+	require.True(t, strings.HasSuffix(Curl(req, binary), `--data-binary '@`+BodyFile+`' 'http://example.com/a/../b?q=it'\''s'`), "could not reference binary body file in curl command")
+	require.True(t, strings.HasSuffix(HTTPie(req, binary), `< '`+BodyFile+`'`), "could not read binary body file in httpie command")
+
+	head, _ := http.NewRequest(http.MethodHead, "https://example.com/", nil)
+	require.Equal(t, "curl -k --path-as-is -g -I 'https://example.com/'", Curl(head, nil), "could not generate head curl command")
+	require.Equal(t, "http --verify=no --path-as-is --ignore-stdin 'HEAD' 'https://example.com/'", HTTPie(head, nil), "could not generate head httpie command")
+}
@@ -15,10 +15,11 @@ import (
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
-	"moul.io/http2curl"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/authprovider/authx"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
@@ -29,13 +30,20 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/connbehavior"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httputils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/rawheaders"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/reproduce"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signerpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/tlsfingerprint"
+	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/tracing"
 	"github.com/projectdiscovery/rawhttp"
+	"github.com/projectdiscovery/retryablehttp-go"
 	convUtil "github.com/projectdiscovery/utils/conversion"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	httpUtils "github.com/projectdiscovery/utils/http"
@@ -43,6 +51,7 @@ import (
 	sliceutil "github.com/projectdiscovery/utils/slice"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	urlutil "github.com/projectdiscovery/utils/url"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -186,7 +195,7 @@ func (request *Request) executeParallelHTTP(input *contextargs.Context, dynamicV
 	maxWorkers := request.Threads
 
 	// if request threads matches global payload concurrency we follow it
-	shouldFollowGlobal := maxWorkers == request.options.Options.PayloadConcurrency
+	shouldFollowGlobal := maxWorkers == request.options.PayloadConcurrency()
 
 	if protocolstate.IsLowOnMemory() {
 		maxWorkers = protocolstate.GuardThreadsOrDefault(request.Threads)
@@ -242,8 +251,8 @@ func (request *Request) executeParallelHTTP(input *contextargs.Context, dynamicV
 		}
 
 		// resize check point - nop if there are no changes
-		if shouldFollowGlobal && spmHandler.Size() != request.options.Options.PayloadConcurrency {
-			if err := spmHandler.Resize(input.Context(), request.options.Options.PayloadConcurrency); err != nil {
+		if shouldFollowGlobal && spmHandler.Size() != request.options.PayloadConcurrency() {
+			if err := spmHandler.Resize(input.Context(), request.options.PayloadConcurrency()); err != nil {
 				return err
 			}
 		}
@@ -272,7 +281,7 @@ func (request *Request) executeParallelHTTP(input *contextargs.Context, dynamicV
 				return
 			}
 			// putting ratelimiter here prevents any unnecessary waiting if any
-			request.options.RateLimitTake()
+			request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
 
 			// after ratelimit take, check if we need to stop
 			if spmHandler.FoundFirstMatch() || request.isUnresponsiveHost(input) || spmHandler.Cancelled() {
@@ -412,6 +421,16 @@ func (request *Request) executeTurboHTTP(input *contextargs.Context, dynamicValu
 
 // ExecuteWithResults executes the final request on a URL
 func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	if len(request.Prerequisites) > 0 {
+		prerequisiteValues, err := request.executePrerequisites(input, dynamicValues, previous)
+		if err != nil {
+			gologger.Verbose().Msgf("[%s] Skipping %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+			request.options.Output.Request(request.options.TemplatePath, input.MetaInput.Input, request.Type().String(), err)
+			request.options.Progress.IncrementFailedRequestsBy(int64(request.Requests()))
+			return nil
+		}
+		dynamicValues = generators.MergeMaps(dynamicValues, prerequisiteValues)
+	}
 	if request.Pipeline || request.Race && request.RaceNumberRequests > 0 || request.Threads > 0 {
 		variablesMap := request.options.Variables.Evaluate(generators.MergeMaps(dynamicValues, previous))
 		dynamicValues = generators.MergeMaps(variablesMap, dynamicValues, request.options.Constants)
@@ -445,7 +464,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		executeFunc := func(data string, payloads, dynamicValue map[string]interface{}) (bool, error) {
 			hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 
-			request.options.RateLimitTake()
+			request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
 
 			ctx := request.newContext(input)
 			ctxWithTimeout, cancel := context.WithTimeout(ctx, httpclientpool.GetHttpTimeout(request.options.Options))
@@ -484,6 +503,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 						Operators:      request.CompiledOperators,
 						MatchFunc:      request.Match,
 						ExtractFunc:    request.Extract,
+						Context:        input.Context(),
 					}
 					allOASTUrls := httputils.GetInteractshURLSFromEvent(event.InternalEvent)
 					allOASTUrls = append(allOASTUrls, generatedHttpRequest.interactshURLs...)
@@ -566,6 +586,16 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		return fmt.Errorf("hostErrorsCache : host %s is unresponsive", input.MetaInput.Input)
 	}
 
+	// requests built by generator carry input context, use it as parent span
+	spanCtx := input.Context()
+	if generatedRequest.request != nil {
+		spanCtx = generatedRequest.request.Context()
+	}
+	spanCtx, span := tracing.Start(spanCtx, "http.request", request.options.TemplateID, input.MetaInput.Input, request.Type().String())
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// wrap one more callback for validation and fixing event
 	callback := func(event *output.InternalWrappedEvent) {
 		// validateNFixEvent performs necessary validation on generated event
@@ -576,6 +606,14 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	}
 
 	request.setCustomHeaders(generatedRequest)
+	request.applyCookieFile(generatedRequest)
+	hasClearance := request.applyChallengeClearance(generatedRequest)
+
+	if request.GzipBody {
+		if err := generatedRequest.compressBody(); err != nil {
+			return errors.Wrap(err, "could not compress request body")
+		}
+	}
 
 	// Try to evaluate any payloads before replacement
 	finalMap := generators.MergeMaps(generatedRequest.dynamicValues, generatedRequest.meta)
@@ -595,6 +633,23 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		resp          *http.Response
 		fromCache     bool
 		dumpedRequest []byte
+		rawHeaders    *rawheaders.Capture
+		// connCapture captures the behavior of the connection (if referenced)
+		connCapture *connbehavior.Capture
+		// tlsCapture captures the tls fingerprints of the connection (if enabled)
+		tlsCapture *tlsfingerprint.Capture
+		// rawSocketResponse is the response as received by raw socket requests
+		rawSocketResponse []byte
+		// rawRequest is the outgoing request captured before it is sent (if referenced)
+		rawRequest []byte
+		// http2Request is the copy of the request sent over http/2 (if compared)
+		http2Request *retryablehttp.Request
+		// ssrfRequest is the control request of ssrf matchers (if fetching an internal target)
+		ssrfRequest *ssrfControlRequest
+		// responseCacheKey is the response cache key of cacheable requests
+		// and cachedBody the raw body of their responses to be cached
+		responseCacheKey string
+		cachedBody       *bytes.Buffer
 	)
 
 	// Dump request for variables checks
@@ -647,11 +702,41 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 	}
 
+	// skip requests already sent for another input of the same host (if enabled)
+	if !generatedRequest.original.Race && request.isDuplicateRequest(input, generatedRequest, dumpedRequest) {
+		gologger.Verbose().Msgf("[%s] Skipping duplicate request for %s\n", request.options.TemplateID, input.MetaInput.Input)
+		return nil
+	}
+
 	// === apply auth strategies ===
 	if generatedRequest.request != nil {
 		generatedRequest.ApplyAuth(request.options.AuthProvider)
 	}
+	if request.rawRequest && !generatedRequest.original.Race {
+		captured, captureErr := rawRequestBytes(generatedRequest, input.MetaInput.Input)
+		if captureErr != nil {
+			gologger.Verbose().Msgf("[%s] Could not capture raw request for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, captureErr)
+		}
+		rawRequest = captured
+	}
 
+	// print the fully built request instead of sending it in request dump mode
+	if request.options.RequestDump != nil {
+		built, err := dump(generatedRequest, input.MetaInput.Input)
+		if err != nil {
+			built = dumpedRequest
+		}
+		request.options.RequestDump.Dump(request.options.TemplateID, generatedRequest.URL(), built)
+		return nil
+	}
+
+	// bound the in-flight requests of the template (if limited), the slot
+	// is released once the response is received
+	releaseSlot, ok := request.options.AcquireTemplateSlot(input.Context())
+	if !ok {
+		return input.Context().Err()
+	}
+	defer releaseSlot()
 	var formedURL string
 	var hostname string
 	timeStart := time.Now()
@@ -666,6 +751,13 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		} else if generatedRequest.request != nil {
 			resp, err = generatedRequest.pipelinedClient.Dor(generatedRequest.request)
 		}
+	} else if generatedRequest.original.RawSocket || (generatedRequest.original.Unsafe && generatedRequest.rawRequest != nil && protocolstate.HasCustomDialer()) {
+		// if request is a raw socket request, write the exact bytes to the connection.
+		// unsafe requests are sent the same way with custom dialers (ex: ssh jump host)
+		// as the rawhttp client can only dial connections itself.
+		var result *rawSocketResult
+		result, err = request.executeRawSocket(input, generatedRequest)
+		resp, formedURL, hostname, rawSocketResponse = result.resp, result.formedURL, result.hostname, result.raw
 	} else if generatedRequest.original.Unsafe && generatedRequest.rawRequest != nil {
 		// if request is a unsafe request, use the rawhttp client
 		formedURL = generatedRequest.rawRequest.FullURL
@@ -701,7 +793,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		hostname = generatedRequest.request.URL.Host
 		formedURL = generatedRequest.request.URL.String()
 		// if nuclei-project is available check if the request was already sent previously
-		if request.options.ProjectFile != nil {
+		if request.options.ProjectFile != nil && !generatedRequest.resend {
 			// if unavailable fail silently
 			fromCache = true
 			resp, err = request.options.ProjectFile.Get(dumpedRequest)
@@ -710,7 +802,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			}
 		}
 		if resp == nil {
-			if errSignature := request.handleSignature(generatedRequest); errSignature != nil {
+			if errSignature := request.handleSignature(input, generatedRequest); errSignature != nil {
 				return errSignature
 			}
 
@@ -724,9 +816,40 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				}
 				httpclient = client
 			}
-			resp, err = httpclient.Do(generatedRequest.request)
+			// explicitly request compression so that the transport
+			// does not transparently decompress the response body
+			if request.Decompression != "" && generatedRequest.request.Header.Get("Accept-Encoding") == "" {
+				generatedRequest.request.Header.Set("Accept-Encoding", "gzip")
+			}
+			if request.RawHeaders {
+				var ctx context.Context
+				ctx, rawHeaders = rawheaders.WithCapture(generatedRequest.request.Context())
+				generatedRequest.request = generatedRequest.request.WithContext(ctx)
+			}
+			if request.options.Options.TLSFingerprint || request.options.Options.TLSClientHello != "" {
+				var ctx context.Context
+				ctx, tlsCapture = tlsfingerprint.WithCapture(generatedRequest.request.Context())
+				generatedRequest.request = generatedRequest.request.WithContext(ctx)
+			}
+			if request.trackConnections {
+				var ctx context.Context
+				ctx, connCapture = connbehavior.WithCapture(generatedRequest.request.Context())
+				generatedRequest.request = generatedRequest.request.WithContext(ctx)
+			}
+			http2Request = request.prepareHTTP2Request(generatedRequest.request)
+			ssrfRequest = request.prepareSSRFControlRequest(generatedRequest.request)
+			if responseCacheKey = request.responseCacheKey(input, generatedRequest); responseCacheKey != "" {
+				resp, fromCache = request.getCachedResponse(input, generatedRequest, responseCacheKey)
+			}
+			if resp == nil {
+				resp, err = httpclient.Do(generatedRequest.request)
+				if err == nil && responseCacheKey != "" {
+					cachedBody = captureRawBody(resp)
+				}
+			}
 		}
 	}
+	releaseSlot()
 	// use request url as matched url if empty
 	if formedURL == "" {
 		formedURL = input.MetaInput.Input
@@ -764,6 +887,8 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 		request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 		request.options.Progress.IncrementErrorsBy(1)
+		request.options.AutoTuner.Observe(time.Since(timeStart), err)
+		request.options.Tarpit.Observe(input.MetaInput.Input, time.Since(timeStart))
 
 		// In case of interactsh markers and request times out, still send
 		// a callback event so in case we receive an interaction, correlation is possible.
@@ -778,6 +903,13 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		} else {
 			outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
 		}
+		outputEvent["error_type"] = protocolutils.GetErrorType(err)
+		if rawRequest != nil {
+			outputEvent[rawRequestPart] = convUtil.String(rawRequest)
+		}
+		if request.options.Options.RequestFailureEvents {
+			request.writeFailureEvent(input, outputEvent, err)
+		}
 
 		if len(generatedRequest.interactshURLs) > 0 {
 			// according to logic we only need to trigger a callback if interactsh was used
@@ -790,14 +922,19 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 		return err
 	}
+	request.updateCookieFile(formedURL, resp)
 
-	var curlCommand string
+	var curlCommand, httpieCommand string
+	var binaryBody []byte
 	if !request.Unsafe && resp != nil && generatedRequest.request != nil && resp.Request != nil && !request.Race {
 		bodyBytes, _ := generatedRequest.request.BodyBytes()
 		resp.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-		command, err := http2curl.GetCurlCommand(generatedRequest.request.Request)
-		if err == nil && command != nil {
-			curlCommand = command.String()
+		curlCommand = reproduce.Curl(generatedRequest.request.Request, bodyBytes)
+		if request.options.Options.HTTPieCommand {
+			httpieCommand = reproduce.HTTPie(generatedRequest.request.Request, bodyBytes)
+		}
+		if reproduce.IsBinary(bodyBytes) {
+			binaryBody = bodyBytes
 		}
 	}
 
@@ -805,6 +942,10 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 
 	duration := time.Since(timeStart)
+	request.options.AutoTuner.Observe(duration, nil)
+	request.options.Tarpit.Observe(input.MetaInput.Input, duration)
+	h2Response := request.executeHTTP2Request(input, http2Request)
+	ssrfResponse := request.executeSSRFControlRequest(input, ssrfRequest)
 
 	// define max body read limit
 	maxBodylimit := MaxBodyRead // 10MB
@@ -815,6 +956,21 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		maxBodylimit = int64(request.options.Options.ResponseReadSize)
 	}
 
+	tracing.SetAttributes(span, attribute.Int("status_code", resp.StatusCode))
+
+	// re-run the per host warmup of dynamic secrets if the request was unauthorized
+	if resp.StatusCode == http.StatusUnauthorized && request.options.AuthProvider != nil && generatedRequest.request != nil {
+		if dynamic, ok := request.options.AuthProvider.LookupURLX(generatedRequest.request.URL).(*authx.DynamicAuthStrategy); ok {
+			dynamic.Reauthenticate(generatedRequest.request.URL.URL)
+		}
+	}
+
+	// capture raw response body before decompression if requested
+	var rawBody *bytes.Buffer
+	if request.Decompression != "" {
+		rawBody = captureRawBody(resp)
+	}
+
 	// respChain is http response chain that reads response body
 	// efficiently by reusing buffers and does all decoding and optimizations
 	respChain := httpUtils.NewResponseChain(resp, maxBodylimit)
@@ -825,11 +981,15 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	var errx error
 	onceFunc := sync.OnceFunc(func() {
 		// if nuclei-project is enabled store the response if not previously done
-		if request.options.ProjectFile != nil && !fromCache {
+		if request.options.ProjectFile != nil && !fromCache && !generatedRequest.resend {
 			if err := request.options.ProjectFile.Set(dumpedRequest, resp, respChain.Body().Bytes()); err != nil {
 				errx = errors.Wrap(err, "could not store in project file")
 			}
 		}
+		// cache the response of cacheable requests if not previously done
+		if !fromCache {
+			request.setCachedResponse(responseCacheKey, resp, cachedBody)
+		}
 	})
 
 	// evaluate responses continiously until first redirect request in reverse order
@@ -859,7 +1019,29 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		}
 		finalEvent := make(output.InternalEvent)
 
-		outputEvent := request.responseToDSLMap(respChain.Response(), input.MetaInput.Input, matchedURL, convUtil.String(dumpedRequest), respChain.FullResponse().String(), respChain.Body().String(), respChain.Headers().String(), duration, generatedRequest.meta)
+		body, fullResponse := respChain.Body().String(), respChain.FullResponse().String()
+		extraValues := generatedRequest.meta
+		// raw body is only captured for the final response of the chain
+		if rawBody != nil && respChain.Response() == resp {
+			var decompressionValues map[string]interface{}
+			body, decompressionValues = request.getDecompressionValues(rawBody, body)
+			if decompressionValues != nil {
+				fullResponse = respChain.Headers().String() + body
+				extraValues = generators.MergeMaps(generatedRequest.meta, decompressionValues)
+			}
+		}
+		if tlsValues := tlsCapture.Values(); tlsValues != nil {
+			extraValues = generators.MergeMaps(extraValues, tlsValues)
+			generatedRequest.meta = generators.MergeMaps(generatedRequest.meta, tlsValues)
+		}
+		// retry once with the clearance if the challenge of the host was passed
+		if request.handleChallenge(matchedURL, respChain.Headers().String(), body) && !hasClearance && generatedRequest.canRetryWithClearance() {
+			request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+			request.options.Progress.AddToTotal(1)
+			generatedRequest.resend = true
+			return request.executeRequest(input, generatedRequest, previousEvent, hasInteractMatchers, processEvent, requestCount)
+		}
+		outputEvent := request.responseToDSLMap(respChain.Response(), input.MetaInput.Input, matchedURL, convUtil.String(dumpedRequest), fullResponse, body, respChain.Headers().String(), duration, extraValues)
 		// add response fields to template context and merge templatectx variables to output event
 		request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
 		if request.options.HasTemplateCtx(input.MetaInput) {
@@ -869,6 +1051,52 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			hostname = hostname[:i]
 		}
 		outputEvent["curl-command"] = curlCommand
+		if httpieCommand != "" {
+			outputEvent["httpie-command"] = httpieCommand
+		}
+		if binaryBody != nil {
+			outputEvent["curl-data-binary"] = binaryBody
+		}
+		if rawRequest != nil {
+			outputEvent[rawRequestPart] = convUtil.String(rawRequest)
+		}
+		// raw headers are only captured for the final response of the chain
+		if rawHeaders != nil && respChain.Response() == resp {
+			outputEvent["raw_headers"] = strings.ReplaceAll(rawHeaders.Raw(), "\r\n", "\n")
+			outputEvent["header_order"] = rawheaders.Order(rawHeaders.Headers())
+		}
+		// connection behavior is observed after the body of the final response was read
+		if connCapture != nil && !fromCache && respChain.Response() == resp {
+			outputEvent = generators.MergeMaps(outputEvent, connCapture.Values(resp))
+		}
+		if rawSocketResponse != nil {
+			outputEvent["raw_response"] = convUtil.String(rawSocketResponse)
+			requestLine, _, _ := strings.Cut(convUtil.String(dumpedRequest), "\n")
+			outputEvent["request_line"] = strings.TrimSuffix(requestLine, "\r")
+			outputEvent["sent_path"] = sentPath(outputEvent["request_line"].(string))
+		}
+		if generatedRequest.baselineHeaders != nil {
+			outputEvent["injected_headers"] = strings.Join(fuzz.InjectedHeaders(generatedRequest.baselineHeaders, respChain.Response().Header, convUtil.String(dumpedRequest)), "\n")
+		}
+		// http/2 response is only compared with the final response of the chain
+		if h2Response != nil && respChain.Response() == resp {
+			outputEvent = generators.MergeMaps(outputEvent, h2Response.values(resp, body))
+		}
+		if ssrfResponse != nil && respChain.Response() == resp {
+			outputEvent = generators.MergeMaps(outputEvent, ssrfResponse.values())
+		}
+		// reflections of the canary of fuzzing requests are classified by their html/js context
+		if canary := types.ToString(generatedRequest.dynamicValues["canary"]); canary != "" {
+			for k, v := range fuzz.ReflectionValues(body, canary) {
+				outputEvent[k] = v
+			}
+		}
+		// reflections of injected request headers are assessed for cacheability
+		if request.cachePoisoning {
+			for k, v := range fuzz.CachePoisoningValues(injectedRequestHeaders(generatedRequest), respChain.Response().Header, body) {
+				outputEvent[k] = v
+			}
+		}
 		if input.MetaInput.CustomIP != "" {
 			outputEvent["ip"] = input.MetaInput.CustomIP
 		} else {
@@ -897,9 +1125,12 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		// prune signature internal values if any
 		request.pruneSignatureInternalValues(generatedRequest.meta)
 
+		_, operatorsSpan := tracing.Start(spanCtx, "operators.execute", request.options.TemplateID, input.MetaInput.Input, request.Type().String())
 		event := eventcreator.CreateEventWithAdditionalOptions(request, generators.MergeMaps(generatedRequest.dynamicValues, finalEvent), request.options.Options.Debug || request.options.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
 			internalWrappedEvent.OperatorsResult.PayloadValues = generatedRequest.meta
 		})
+		tracing.SetAttributes(operatorsSpan, attribute.Bool("matched", event.HasResults()))
+		tracing.End(operatorsSpan, nil)
 		if hasInteractMatchers {
 			event.UsesInteractsh = true
 		}
@@ -916,6 +1147,11 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		isResponseTruncated := request.MaxSize > 0 && respChain.Body().Len() >= request.MaxSize
 		dumpResponse(event, request, respChain.FullResponse().Bytes(), formedURL, responseContentType, isResponseTruncated, input.MetaInput.Input)
 
+		// next pages are followed from the final response of the first page
+		if request.Pagination != nil && !generatedRequest.paginated && generatedRequest.request != nil && respChain.Response() == resp {
+			request.followPages(input, generatedRequest, previousEvent, event)
+		}
+
 		callback(event)
 
 		// Skip further responses if we have stop-at-first-match and a match
@@ -965,11 +1201,11 @@ func (request *Request) validateNFixEvent(input *contextargs.Context, gr *genera
 }
 
 // handleSignature of the http request
-func (request *Request) handleSignature(generatedRequest *generatedRequest) error {
+func (request *Request) handleSignature(input *contextargs.Context, generatedRequest *generatedRequest) error {
 	switch request.Signature.Value {
 	case AWSSignature:
 		var awsSigner signer.Signer
-		allvars := generators.MergeMaps(request.options.Options.Vars.AsMap(), generatedRequest.dynamicValues)
+		allvars := generators.MergeMaps(request.options.BuildPayloadFromOptions(input.MetaInput), generatedRequest.dynamicValues)
 		awsopts := signer.AWSOptions{
 			AwsID:          types.ToString(allvars["aws-id"]),
 			AwsSecretToken: types.ToString(allvars["aws-secret"]),
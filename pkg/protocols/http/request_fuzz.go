@@ -8,24 +8,34 @@ package http
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/waf"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httputils"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/tracing"
 	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/projectdiscovery/useragent"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 	urlutil "github.com/projectdiscovery/utils/url"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // executeFuzzingRule executes fuzzing request for a URL
@@ -38,6 +48,11 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 	// if it is applicable, we execute all requests
 	// if it is not applicable, we log and fail silently
 
+	// scan was cancelled (ex: max duration reached) before fuzzing started
+	if input.Context().Err() != nil {
+		return nil
+	}
+
 	// check if target should be fuzzed or not
 	if !request.ShouldFuzzTarget(input) {
 		urlx, _ := input.MetaInput.URL()
@@ -60,6 +75,10 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		if err != nil {
 			return errors.Wrap(err, "fuzz: could not build request obtained from target file")
 		}
+		// base path is applied before components of the request are discovered
+		if request.options.BasePaths.Rewrite(baseRequest.URL) {
+			baseRequest.Update()
+		}
 		input.MetaInput.Input = baseRequest.URL.String()
 		// execute with one value first to checks its applicability
 		err = request.executeAllFuzzingRules(input, previous, baseRequest, callback)
@@ -86,7 +105,13 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 	if err != nil {
 		return errors.Wrap(err, "fuzz: could not parse input url")
 	}
-	baseRequest, err := retryablehttp.NewRequestFromURL(http.MethodGet, parsed, nil)
+	request.options.BasePaths.Rewrite(parsed)
+	var baseRequest *retryablehttp.Request
+	if request.bodyFromFile {
+		baseRequest, err = request.bodyFileFuzzRequest(inputx, parsed)
+	} else {
+		baseRequest, err = retryablehttp.NewRequestFromURL(http.MethodGet, parsed, nil)
+	}
 	if err != nil {
 		return errors.Wrap(err, "fuzz: could not build request from url")
 	}
@@ -113,14 +138,67 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 // executeAllFuzzingRules executes all fuzzing rules defined in template for a given base request
 func (request *Request) executeAllFuzzingRules(input *contextargs.Context, values map[string]interface{}, baseRequest *retryablehttp.Request, callback protocols.OutputEventCallback) error {
 	applicable := false
+
+	state := &fuzzInputState{
+		history: newRequestHistory(request.options.Options.FuzzReplayWindow),
+	}
+
+	if err := request.applyConditionalHeaders(baseRequest, values); err != nil {
+		return err
+	}
+
+	if request.shouldMineParameters(input) {
+		baseRequest = request.mineParameters(input, baseRequest)
+	}
+
+	state.baseRequest = baseRequest
+
+	var csrfToken string
+	if request.CSRF != nil && !request.CSRF.Refresh {
+		csrfToken = request.fetchCSRFToken(input, baseRequest)
+	}
+	captureDiffs := request.shouldCaptureBodyDiffs()
+	if request.DetectInjectedHeaders || request.options.Options.FuzzWAFDetect || captureDiffs || request.VerifyControl || request.hasBaselineStatusFilter() {
+		baseline := request.fetchBaseline(input, baseRequest)
+		if baseline != nil {
+			state.baselineStatus = baseline.statusCode
+		}
+		if request.VerifyControl && request.options.RequestDump == nil {
+			state.control = controlValues(baseline)
+			if state.control["control_failed"] == true {
+				gologger.Warning().Msgf("[%s] fuzz: control request for %s failed (status %v), results are unreliable\n", request.options.TemplateID, input.MetaInput.Input, state.control["control_status_code"])
+			}
+		}
+		if baseline != nil && request.DetectInjectedHeaders {
+			state.baselineHeaders = baseline.headers
+		}
+		if baseline != nil && captureDiffs {
+			state.baselineBody = &baseline.body
+		}
+		if request.options.Options.FuzzWAFDetect {
+			state.wafDetector = waf.New(request.options.Options.FuzzWAFThreshold)
+			if baseline != nil {
+				state.wafDetector.SetControl(baseline.statusCode, baseline.body)
+			}
+		}
+	}
 	for _, rule := range request.Fuzzing {
+		state.rule = rule
+		state.earlyAborts, state.abortedParams = nil, nil
+		state.skippedComponents = nil
 		select {
 		case <-input.Context().Done():
 			return input.Context().Err()
 		default:
 		}
+		// inputs whose baseline status is not of interest are not fuzzed by the rule
+		if rule.StatusFilter.GatesBaseline() && state.baselineStatus != 0 && !rule.StatusFilter.Allows(state.baselineStatus) {
+			gologger.Verbose().Msgf("[%s] fuzz: skipping rule for %s: baseline status %d filtered\n", request.options.TemplateID, input.MetaInput.Input, state.baselineStatus)
+			applicable = true
+			continue
+		}
 
-		err := rule.Execute(&fuzz.ExecuteRuleInput{
+		ruleInput := &fuzz.ExecuteRuleInput{
 			Input: input,
 			Callback: func(gr fuzz.GeneratedRequest) bool {
 				select {
@@ -129,12 +207,33 @@ func (request *Request) executeAllFuzzingRules(input *contextargs.Context, value
 				default:
 				}
 
+				if request.CSRF != nil {
+					token := csrfToken
+					if request.CSRF.Refresh {
+						token = request.fetchCSRFToken(input, baseRequest)
+					}
+					if token != "" {
+						csrfReq, err := request.CSRF.Apply(gr, token)
+						if err != nil {
+							gologger.Verbose().Msgf("[%s] fuzz: could not inject csrf token: %s\n", request.options.TemplateID, err)
+						} else {
+							gr.Request = csrfReq
+						}
+					}
+				}
+
 				// TODO: replace this after scanContext Refactor
-				return request.executeGeneratedFuzzingRequest(gr, input, callback)
+				return request.executeGeneratedFuzzingRequest(gr, input, state, callback)
 			},
 			Values:      values,
 			BaseRequest: baseRequest.Clone(context.TODO()),
-		})
+		}
+		// payloads are declared in the encoding of the request
+		if request.Encoding != "" {
+			ruleInput.DecodePayload = request.decodeRawBytes
+		}
+		state.ruleInput = ruleInput
+		err := rule.Execute(ruleInput)
 		if err == nil {
 			applicable = true
 			continue
@@ -143,6 +242,11 @@ func (request *Request) executeAllFuzzingRules(input *contextargs.Context, value
 			continue
 		}
 		if err == types.ErrNoMoreRequests {
+			// only the rule reaching its match limit is stopped
+			if state.maxMatchesReached() {
+				applicable = true
+				continue
+			}
 			return nil
 		}
 		return errors.Wrap(err, "could not execute rule")
@@ -154,40 +258,378 @@ func (request *Request) executeAllFuzzingRules(input *contextargs.Context, value
 	return nil
 }
 
+// fetchCSRFToken fetches baseline response for the base request using GET method
+// and returns csrf token extracted from it (if any)
+func (request *Request) fetchCSRFToken(input *contextargs.Context, baseRequest *retryablehttp.Request) string {
+	if request.options.RequestDump != nil {
+		return ""
+	}
+	baselineReq, err := retryablehttp.NewRequestFromURLWithContext(input.Context(), http.MethodGet, baseRequest.URL, nil)
+	if err != nil {
+		return ""
+	}
+	for k, v := range baseRequest.Header {
+		baselineReq.Header[k] = v
+	}
+	baselineReq.Header.Del("Content-Type")
+
+	resp, err := request.sendAuxiliaryRequest(input, baselineReq)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] fuzz: could not fetch csrf baseline for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxBodyRead))
+
+	token := request.CSRF.Extract(resp, string(body))
+	if token == "" {
+		gologger.Verbose().Msgf("[%s] fuzz: no csrf token found in baseline for %s\n", request.options.TemplateID, input.MetaInput.Input)
+	}
+	return token
+}
+
+// fuzzInputState contains the state shared by fuzzing requests of an input
+type fuzzInputState struct {
+	// history of preceding requests dumped on a match (if enabled)
+	history *requestHistory
+	// baselineHeaders are the response headers of the unmodified request
+	// used for detecting headers injected by fuzzing payloads (if enabled)
+	baselineHeaders http.Header
+	// baselineBody is the response body of the unmodified request
+	// diffed against bodies of matched fuzzing responses (if enabled)
+	baselineBody *string
+	// wafDetector detects waf blocking fuzzing payloads (if enabled)
+	wafDetector *waf.Detector
+	// rule is the fuzzing rule currently executed
+	rule *fuzz.Rule
+	// ruleInput is the execute input of the current rule used for
+	// feeding values extracted by chained rules to the next payloads
+	ruleInput *fuzz.ExecuteRuleInput
+	// ruleMatches is the number of matches of each rule for the input
+	ruleMatches map[*fuzz.Rule]int
+	// earlyAborts track responses of each fuzzed parameter of the current rule
+	// to skip its remaining payloads once they prove ineffective (if enabled)
+	earlyAborts map[string]*fuzz.EarlyAbortTracker
+	// abortedParams are the fuzzed parameters of the current rule whose
+	// remaining payloads are skipped
+	abortedParams map[string]struct{}
+	// baselineStatus is the status code of the unmodified request (if fetched)
+	baselineStatus int
+	// skippedComponents are the components of the current rule whose
+	// remaining payloads are skipped by its status filter
+	skippedComponents map[string]struct{}
+	// control contains the outcome of the unmodified control request
+	// exposed to fuzzing requests (if enabled)
+	control map[string]interface{}
+	// baseRequest is the unmodified request of the input re-sent
+	// as control when confirming matches (if enabled)
+	baseRequest *retryablehttp.Request
+}
+
+// addMatch records a match for the current rule
+func (state *fuzzInputState) addMatch() {
+	if state.ruleMatches == nil {
+		state.ruleMatches = make(map[*fuzz.Rule]int)
+	}
+	state.ruleMatches[state.rule]++
+}
+
+// maxMatchesReached returns true if the current rule has reached its max-matches limit
+func (state *fuzzInputState) maxMatchesReached() bool {
+	if state.rule == nil || state.rule.MaxMatches <= 0 {
+		return false
+	}
+	return state.ruleMatches[state.rule] >= state.rule.MaxMatches
+}
+
+// hasBaselineStatusFilter returns true if any fuzzing rule is limited
+// on the baseline status of inputs
+func (request *Request) hasBaselineStatusFilter() bool {
+	for _, rule := range request.Fuzzing {
+		if rule.StatusFilter.GatesBaseline() {
+			return true
+		}
+	}
+	return false
+}
+
+// filterStatus drops the operator results of the event if its status is
+// filtered by the status filter of the current rule, skipping the remaining
+// payloads of the component if configured
+func (request *Request) filterStatus(state *fuzzInputState, gr fuzz.GeneratedRequest, input *contextargs.Context, event *output.InternalWrappedEvent) {
+	if state.rule == nil || !state.rule.StatusFilter.GatesResponses() {
+		return
+	}
+	statusCode, _ := event.InternalEvent["status_code"].(int)
+	if state.rule.StatusFilter.Allows(statusCode) {
+		return
+	}
+	event.OperatorsResult = nil
+	event.Results = nil
+	if state.rule.StatusFilter.SkipComponent && gr.Component != nil {
+		if state.skippedComponents == nil {
+			state.skippedComponents = make(map[string]struct{})
+		}
+		if _, ok := state.skippedComponents[gr.Component.Name()]; !ok {
+			state.skippedComponents[gr.Component.Name()] = struct{}{}
+			gologger.Verbose().Msgf("[%s] fuzz: skipping remaining payloads of %s for %s: status %d filtered\n", request.options.TemplateID, gr.Component.Name(), input.MetaInput.Input, statusCode)
+		}
+	}
+}
+
+// chainFuzzValues runs the chain extractors of the current rule on the event
+// and feeds the extracted values to the next payloads of the rule
+func (request *Request) chainFuzzValues(state *fuzzInputState, event *output.InternalWrappedEvent) {
+	if state.ruleInput == nil || !state.rule.IsChained() {
+		return
+	}
+	values := make(map[string]interface{})
+	for _, extractor := range state.rule.Chain {
+		if results := extractor.OrderResults(request.Extract(event.InternalEvent, extractor), ""); len(results) > 0 {
+			values[extractor.Name] = results[0]
+		}
+	}
+	state.ruleInput.Chain(values)
+}
+
+// earlyAbortKey returns the key of the parameter fuzzed by the request
+// tracked for early abort (the component in multiple mode)
+func earlyAbortKey(gr fuzz.GeneratedRequest) string {
+	if gr.Component == nil {
+		return gr.Parameter
+	}
+	return gr.Component.Name() + ":" + gr.Parameter
+}
+
+// isEarlyAborted returns true if the remaining payloads of the parameter
+// fuzzed by the request are skipped
+func (state *fuzzInputState) isEarlyAborted(gr fuzz.GeneratedRequest) bool {
+	_, ok := state.abortedParams[earlyAbortKey(gr)]
+	return ok
+}
+
+// observeEarlyAbort records the response of the event for the early abort
+// of the fuzzed parameter and skips its remaining payloads once ineffective
+func (request *Request) observeEarlyAbort(state *fuzzInputState, gr fuzz.GeneratedRequest, input *contextargs.Context, event *output.InternalWrappedEvent) {
+	if state.rule == nil || state.rule.EarlyAbort == nil || state.isEarlyAborted(gr) {
+		return
+	}
+	key := earlyAbortKey(gr)
+	tracker, ok := state.earlyAborts[key]
+	if !ok {
+		if state.earlyAborts == nil {
+			state.earlyAborts = make(map[string]*fuzz.EarlyAbortTracker)
+		}
+		tracker = state.rule.NewEarlyAbortTracker()
+		state.earlyAborts[key] = tracker
+	}
+	statusCode, _ := event.InternalEvent["status_code"].(int)
+	matched := event.OperatorsResult != nil && event.OperatorsResult.Matched
+	if abort, reason := tracker.Observe(statusCode, types.ToString(event.InternalEvent["body"]), matched); abort {
+		if state.abortedParams == nil {
+			state.abortedParams = make(map[string]struct{})
+		}
+		state.abortedParams[key] = struct{}{}
+		gologger.Verbose().Msgf("[%s] fuzz: skipping remaining payloads of %s for %s: %s\n", request.options.TemplateID, key, input.MetaInput.Input, reason)
+	}
+}
+
+// baselineResponse is the response of the unmodified base request
+type baselineResponse struct {
+	statusCode int
+	headers    http.Header
+	body       string
+}
+
+// fetchBaseline sends the unmodified base request and returns its response
+// used as benign control for fuzzing requests of the input
+func (request *Request) fetchBaseline(input *contextargs.Context, baseRequest *retryablehttp.Request) *baselineResponse {
+	if request.options.RequestDump != nil {
+		return nil
+	}
+	resp, err := request.sendAuxiliaryRequest(input, baseRequest)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] fuzz: could not fetch baseline for %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxBodyRead))
+	return &baselineResponse{statusCode: resp.StatusCode, headers: resp.Header, body: string(body)}
+}
+
+// controlValues returns the variables describing the outcome of the control
+// request. A control is failed if it could not be sent (nil baseline) or
+// returned a server error or rate limiting status code.
+func controlValues(baseline *baselineResponse) map[string]interface{} {
+	var statusCode int
+	if baseline != nil {
+		statusCode = baseline.statusCode
+	}
+	failed := statusCode == 0 || statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests
+	return map[string]interface{}{
+		"control_failed":      failed,
+		"control_status_code": statusCode,
+	}
+}
+
+// sendAuxiliaryRequest sends a request which is not generated by the fuzzing
+// rules (baseline, csrf and param mining requests) honoring the safe mode
+// filter, host errors, rate limits and template slots of fuzzing requests.
+// Auxiliary requests are added to the total of the progress.
+func (request *Request) sendAuxiliaryRequest(input *contextargs.Context, req *retryablehttp.Request) (*http.Response, error) {
+	if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.Input) {
+		return nil, errors.New("host errors limit reached")
+	}
+	if request.options.Tarpit.Check(input.MetaInput.Input) {
+		return nil, errors.New("host suspected as tarpit")
+	}
+	if destructive, pattern := request.options.SafeModeFilter.IsDestructive(req); destructive {
+		return nil, errors.Errorf("destructive request skipped (safe-mode: %s)", pattern)
+	}
+	request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+	releaseSlot, ok := request.options.AcquireTemplateSlot(input.Context())
+	if !ok {
+		return nil, input.Context().Err()
+	}
+	defer releaseSlot()
+
+	request.options.Progress.AddToTotal(1)
+	resp, err := request.baselineClient(input).Do(req.Clone(input.Context()))
+	if err != nil {
+		request.options.Progress.IncrementFailedRequestsBy(1)
+		return nil, err
+	}
+	request.options.Progress.IncrementRequests()
+	return resp, nil
+}
+
+// baselineClient returns the http client used for baseline requests of the input
+func (request *Request) baselineClient(input *contextargs.Context) *retryablehttp.Client {
+	if input.CookieJar != nil {
+		connConfiguration := request.connConfiguration
+		connConfiguration.Connection.SetCookieJar(input.CookieJar)
+		if client, err := httpclientpool.Get(request.options.Options, connConfiguration); err == nil {
+			return client
+		}
+	}
+	return request.httpClient
+}
+
 // executeGeneratedFuzzingRequest executes a generated fuzzing request after building it using rules and payloads
-func (request *Request) executeGeneratedFuzzingRequest(gr fuzz.GeneratedRequest, input *contextargs.Context, callback protocols.OutputEventCallback) bool {
+func (request *Request) executeGeneratedFuzzingRequest(gr fuzz.GeneratedRequest, input *contextargs.Context, state *fuzzInputState, callback protocols.OutputEventCallback) bool {
 	hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 	hasInteractMarkers := len(gr.InteractURLs) > 0
 	if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.Input) {
 		return false
 	}
-	request.options.RateLimitTake()
+	if request.options.Tarpit.Check(input.MetaInput.Input) {
+		return false
+	}
+	if destructive, pattern := request.options.SafeModeFilter.IsDestructive(gr.Request); destructive {
+		gologger.Verbose().Msgf("[%s] Skipping destructive fuzzing request to %s (safe-mode: %s)\n", request.options.TemplateID, input.MetaInput.Input, pattern)
+		return true
+	}
+	if gr.Component != nil && state.skippedComponents != nil {
+		if _, ok := state.skippedComponents[gr.Component.Name()]; ok {
+			return true
+		}
+	}
+	// payloads of parameters which proved ineffective are skipped
+	if state.isEarlyAborted(gr) {
+		return true
+	}
+	if gr.Component != nil && request.options.SeenParams != nil && request.isSeenParams(input, gr) {
+		return true
+	}
+	request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+
+	spanCtx, span := tracing.Start(input.Context(), "http.fuzz", request.options.TemplateID, input.MetaInput.Input, request.Type().String())
+	if tracing.Enabled() {
+		tracing.SetAttributes(span, attribute.String("fuzz.component", gr.Component.Name()))
+		// preserve the tls server name set by the fuzzing rule if any
+		if sni := gr.Request.Context().Value(fastdialer.SniName); sni != nil {
+			spanCtx = context.WithValue(spanCtx, fastdialer.SniName, sni)
+		}
+		gr.Request = gr.Request.WithContext(spanCtx)
+	}
+	gr.DynamicValues = generators.MergeMaps(gr.DynamicValues, getHostValues(gr.Request), state.control)
+	meta := gr.GraphQLMetadata()
+	if meta == nil {
+		meta = gr.MultiPartMetadata()
+	}
+	if meta == nil {
+		meta = gr.CookieMetadata()
+	}
+	if mutation := gr.JSONMutationMetadata(); mutation != nil {
+		meta = generators.MergeMaps(meta, mutation)
+	}
+	// results of an input whose control failed are flagged as unreliable
+	if state.control["control_failed"] == true {
+		meta = generators.MergeMaps(meta, map[string]interface{}{
+			"control": fmt.Sprintf("failed (status %v)", state.control["control_status_code"]),
+		})
+	}
 	req := &generatedRequest{
-		request:        gr.Request,
-		dynamicValues:  gr.DynamicValues,
-		interactshURLs: gr.InteractURLs,
-		original:       request,
+		request:         gr.Request,
+		dynamicValues:   gr.DynamicValues,
+		interactshURLs:  gr.InteractURLs,
+		original:        request,
+		baselineHeaders: state.baselineHeaders,
+		meta:            meta,
+	}
+	if request.cachePoisoning {
+		req.fuzzedHeaders = gr.FuzzedHeaders()
+	}
+	// matches are confirmed with a copy of the request taken before it is sent
+	var confirmRequest *retryablehttp.Request
+	var confirmation string
+	if request.shouldConfirmMatches() && !(hasInteractMarkers && hasInteractMatchers) {
+		confirmRequest = gr.Request.Clone(input.Context())
 	}
 	var gotMatches bool
 	requestErr := request.executeRequest(input, req, gr.DynamicValues, hasInteractMatchers, func(event *output.InternalWrappedEvent) {
-		if hasInteractMarkers && hasInteractMatchers && request.options.Interactsh != nil {
+		// all urls of the request are registered including the ones of
+		// dynamic values so that interactions of any of them are correlated
+		var allOASTUrls []string
+		if hasInteractMatchers && request.options.Interactsh != nil {
+			allOASTUrls = sliceutil.Dedupe(append(httputils.GetInteractshURLSFromEvent(event.InternalEvent), gr.InteractURLs...))
+		}
+		if len(allOASTUrls) > 0 {
 			requestData := &interactsh.RequestData{
 				MakeResultFunc: request.MakeResultEvent,
 				Event:          event,
 				Operators:      request.CompiledOperators,
 				MatchFunc:      request.Match,
 				ExtractFunc:    request.Extract,
+				Context:        spanCtx,
 			}
-			request.options.Interactsh.RequestEvent(gr.InteractURLs, requestData)
+			request.options.Interactsh.RequestEvent(allOASTUrls, requestData)
 			gotMatches = request.options.Interactsh.AlreadyMatched(requestData)
 		} else {
+			request.filterStatus(state, gr, input, event)
+			if confirmRequest != nil && event.HasOperatorResult() && event.OperatorsResult.Matched {
+				if confirmation == "" {
+					confirmation = request.confirmMatch(input, state, confirmRequest, gr.DynamicValues)
+					if confirmation != confirmationConfirmed {
+						gologger.Warning().Msgf("[%s] fuzz: match on %s was not confirmed (%s)\n", request.options.TemplateID, input.MetaInput.Input, confirmation)
+					}
+				}
+				flagConfirmation(event, confirmation)
+			}
 			callback(event)
 		}
 		// Add the extracts to the dynamic values if any.
 		if event.OperatorsResult != nil {
-			gotMatches = event.OperatorsResult.Matched
+			gotMatches = event.OperatorsResult.Matched && (confirmation == "" || confirmation == confirmationConfirmed)
+		}
+		request.chainFuzzValues(state, event)
+		request.observeEarlyAbort(state, gr, input, event)
+		request.observeWAF(state.wafDetector, event, callback)
+		if gotMatches && state.baselineBody != nil {
+			request.writeBodyDiff(gr, input, *state.baselineBody, event)
 		}
 	}, 0)
+	tracing.End(span, requestErr)
 	// If a variable is unresolved, skip all further requests
 	if errors.Is(requestErr, ErrMissingVars) {
 		return false
@@ -197,17 +639,76 @@ func (request *Request) executeGeneratedFuzzingRequest(gr fuzz.GeneratedRequest,
 			request.options.HostErrorsCache.MarkFailed(input.MetaInput.Input, requestErr)
 		}
 		gologger.Verbose().Msgf("[%s] Error occurred in request: %s\n", request.options.TemplateID, requestErr)
+
+		if request.MatchConnectionError {
+			if connErr := protocolutils.GetConnectionErrorType(requestErr); connErr != "" {
+				if request.matchConnectionError(gr, input, requestErr, connErr, callback) {
+					gotMatches = true
+				}
+			}
+		}
 	}
 	request.options.Progress.IncrementRequests()
 
+	if state.history != nil {
+		dumped, _ := gr.Request.Dump()
+		if gotMatches {
+			request.dumpRequestHistory(state.history, input, string(dumped))
+		}
+		state.history.Add(string(dumped))
+	}
+
 	// If this was a match, and we want to stop at first match, skip all further requests.
 	shouldStopAtFirstMatch := request.options.Options.StopAtFirstMatch || request.StopAtFirstMatch
 	if shouldStopAtFirstMatch && gotMatches {
 		return false
 	}
+	// If the rule has collected max matches for the input, skip its further requests.
+	if gotMatches {
+		state.addMatch()
+		if state.maxMatchesReached() {
+			return false
+		}
+	}
 	return true
 }
 
+// getHostValues returns the host dialed for the request and
+// the host sent in the host header of the request
+func getHostValues(req *retryablehttp.Request) map[string]interface{} {
+	sentHost := req.Host
+	if sentHost == "" {
+		sentHost = req.URL.Host
+	}
+	return map[string]interface{}{
+		"connection_host": req.URL.Host,
+		"sent_host":       sentHost,
+	}
+}
+
+// matchConnectionError evaluates operators on an event created for a fuzzing request
+// that failed with a connection level error and returns true if it was matched.
+func (request *Request) matchConnectionError(gr fuzz.GeneratedRequest, input *contextargs.Context, requestErr error, connErr string, callback protocols.OutputEventCallback) bool {
+	if request.CompiledOperators == nil {
+		return false
+	}
+	var dumpedRequest []byte
+	if gr.Request != nil {
+		dumpedRequest, _ = gr.Request.Dump()
+	}
+	matched := input.MetaInput.Input
+	if gr.Request != nil && gr.Request.URL != nil {
+		matched = gr.Request.URL.String()
+	}
+	outputEvent := request.responseToDSLMap(&http.Response{}, input.MetaInput.Input, matched, string(dumpedRequest), "", "", "", 0, gr.DynamicValues)
+	outputEvent["connection_error"] = connErr
+	outputEvent["error"] = requestErr.Error()
+
+	event := eventcreator.CreateEventWithAdditionalOptions(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse, nil)
+	callback(event)
+	return event.OperatorsResult != nil && event.OperatorsResult.Matched
+}
+
 // ShouldFuzzTarget checks if given target should be fuzzed or not using `filter` field in template
 func (request *Request) ShouldFuzzTarget(input *contextargs.Context) bool {
 	if len(request.FuzzPreCondition) == 0 {
@@ -218,7 +719,7 @@ func (request *Request) ShouldFuzzTarget(input *contextargs.Context) bool {
 		isMatch, _ := request.Match(request.filterDataMap(input), filter)
 		status = append(status, isMatch)
 		if request.options.Options.MatcherStatus {
-			gologger.Debug().Msgf("[%s] [%s] Filter => %s : %v", input.MetaInput.Target(), request.options.TemplateID, operators.GetMatcherName(filter, index), isMatch)
+			gologger.Debug().Msgf("[%s] [%s] Filter => %s : %v", input.MetaInput.Target(), request.options.TemplateID, operators.GetMatcherStatusName(filter, index), isMatch)
 		}
 	}
 	if len(status) == 0 {
@@ -283,3 +784,36 @@ func (request *Request) filterDataMap(input *contextargs.Context) map[string]int
 	}
 	return m
 }
+
+// isSeenParams returns true if the parameters fuzzed by the request were
+// fuzzed by the template on the input in a previous run, recording them as
+// fuzzed otherwise. All parameters of the component are fuzzed in multiple
+// mode so the request is only skipped if all of them were seen.
+func (request *Request) isSeenParams(input *contextargs.Context, gr fuzz.GeneratedRequest) bool {
+	parameters := []string{gr.Parameter}
+	if gr.Parameter == "" {
+		parameters = parameters[:0]
+		_ = gr.Component.Iterate(func(key string, _ interface{}) error {
+			parameters = append(parameters, key)
+			return nil
+		})
+	}
+	if len(parameters) == 0 {
+		return false
+	}
+	seen := true
+	for _, parameter := range parameters {
+		if !request.options.SeenParams.Seen(request.options.TemplateID, input.MetaInput.Input, gr.Component.Name(), parameter) {
+			seen = false
+			break
+		}
+	}
+	if seen {
+		gologger.Verbose().Msgf("[%s] Skipping already fuzzed parameters %s of %s (%s)\n", request.options.TemplateID, strings.Join(parameters, ","), input.MetaInput.Input, gr.Component.Name())
+		return true
+	}
+	for _, parameter := range parameters {
+		request.options.SeenParams.Add(request.options.TemplateID, input.MetaInput.Input, gr.Component.Name(), parameter)
+	}
+	return false
+}
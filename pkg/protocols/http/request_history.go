@@ -0,0 +1,75 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+const (
+	// maxReplayWindow is the maximum number of requests kept in history
+	maxReplayWindow = 100
+	// maxReplayRequestSize is the maximum size of a single request kept in history
+	maxReplayRequestSize = 16 * 1024
+)
+
+// requestHistory is a bounded rolling window of requests sent for an input
+type requestHistory struct {
+	items []string
+	next  int
+	full  bool
+}
+
+// newRequestHistory creates a new request history of given size
+// or returns nil if size is not positive.
+func newRequestHistory(size int) *requestHistory {
+	if size <= 0 {
+		return nil
+	}
+	if size > maxReplayWindow {
+		size = maxReplayWindow
+	}
+	return &requestHistory{items: make([]string, size)}
+}
+
+// Add adds a dumped request to history evicting the oldest one if full
+func (h *requestHistory) Add(request string) {
+	if h == nil {
+		return
+	}
+	if len(request) > maxReplayRequestSize {
+		request = request[:maxReplayRequestSize] + "\n[truncated]"
+	}
+	h.items[h.next] = request
+	h.next = (h.next + 1) % len(h.items)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Items returns requests in history from oldest to newest
+func (h *requestHistory) Items() []string {
+	if h == nil {
+		return nil
+	}
+	if !h.full {
+		return append([]string{}, h.items[:h.next]...)
+	}
+	return append(append([]string{}, h.items[h.next:]...), h.items[:h.next]...)
+}
+
+// dumpRequestHistory dumps the requests preceding a match for an input
+func (request *Request) dumpRequestHistory(history *requestHistory, input *contextargs.Context, matchedRequest string) {
+	items := history.Items()
+	if len(items) == 0 {
+		return
+	}
+	var builder strings.Builder
+	for i, item := range items {
+		builder.WriteString(fmt.Sprintf("--- request %d/%d ---\n%s\n", i+1, len(items), item))
+	}
+	builder.WriteString(fmt.Sprintf("--- matched request ---\n%s\n", matchedRequest))
+	gologger.Info().Msgf("[%s] Replaying last %d fuzzing requests before match for %s\n\n%s", request.options.TemplateID, len(items), input.MetaInput.Input, builder.String())
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHistory(t *testing.T) {
+	require.Nil(t, newRequestHistory(0), "could create disabled history")
+
+	history := newRequestHistory(3)
+	history.Add("a")
+	history.Add("b")
+	require.Equal(t, []string{"a", "b"}, history.Items())
+
+	history.Add("c")
+	history.Add("d")
+	require.Equal(t, []string{"b", "c", "d"}, history.Items(), "could not evict oldest request")
+
+	require.Len(t, newRequestHistory(1000).items, maxReplayWindow, "could not bound history size")
+}
@@ -1,23 +1,43 @@
 package http
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/seenparams"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/projectfile"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/challenge"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/cookiefile"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/responsecache"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/retryablehttp-go"
 )
 
 func TestHTTPExtractMultipleReuse(t *testing.T) {
@@ -257,3 +277,1338 @@ func TestReqURLPattern(t *testing.T) {
 	require.NotEmpty(t, finalEvent.Results[0].ReqURLPattern, "could not get req url pattern")
 	require.Equal(t, `/{{rand_char("abc")}}/{{interactsh-url}}/123?query={{rand_int(1, 10)}}&data={{randstr}}`, finalEvent.Results[0].ReqURLPattern)
 }
+
+func TestDecompressionNone(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:            templateID,
+		Method:        HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:          []string{"{{BaseURL}}"},
+		Decompression: DecompressionNone,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:   matchers.MatcherTypeHolder{MatcherType: matchers.BinaryMatcher},
+				Binary: []string{"1f8b"}, // gzip magic bytes
+			}, {
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"decompressed_body == 'compressed content'"},
+			}},
+			MatchersCondition: "and",
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte("compressed content"))
+		_ = gz.Close()
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match on raw compressed body")
+}
+
+func TestNormalizeBody(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:             templateID,
+		Method:         HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:           []string{"{{BaseURL}}"},
+		NormalizeMasks: []string{`build-\d+`},
+		NormalizeBody:  true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{`name="csrf" value="<masked>"> version <masked>`},
+			}},
+			Extractors: []*extractors.Extractor{{
+				Type:       extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+				Regex:      []string{`value="([a-z0-9]+)"`},
+				RegexGroup: 1,
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<input name="csrf" value="x7k2q9"> version build-1337`))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var finalEvent *output.InternalWrappedEvent
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match on normalized body")
+	require.Equal(t, []string{"x7k2q9"}, finalEvent.OperatorsResult.OutputExtracts, "could not extract from raw body")
+	require.Contains(t, finalEvent.InternalEvent["body"], "build-1337", "normalized raw body")
+}
+
+func TestGzipBody(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:       templateID,
+		Method:   HTTPMethodTypeHolder{MethodType: HTTPPost},
+		Path:     []string{"{{BaseURL}}"},
+		Body:     "name=compressed",
+		GzipBody: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"received: name=compressed"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(gz)
+		_, _ = fmt.Fprintf(w, "received: %s", data)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match on decompressed request body")
+}
+
+func TestCookieFile(t *testing.T) {
+	options := testutils.DefaultOptions
+	options.CookieFileUpdate = true
+	defer func() {
+		options.CookieFileUpdate = false
+	}()
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/login", "{{BaseURL}}/check"},
+		// cookies are only persisted by the cookie file jar
+		DisableCookie: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:      matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words:     []string{"session=abc", "csrf=token"},
+				Condition: "and",
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "token", Path: "/"})
+		}
+		_, _ = fmt.Fprintf(w, "cookies: %s", r.Header.Get("Cookie"))
+	}))
+	defer ts.Close()
+
+	cookieFile := filepath.Join(t.TempDir(), "cookies.txt")
+	err := os.WriteFile(cookieFile, []byte("127.0.0.1\tFALSE\t/\tFALSE\t0\tsession\tabc\n"), 0644)
+	require.Nil(t, err, "could not write cookie file")
+	jar, err := cookiefile.New(cookieFile)
+	require.Nil(t, err, "could not load cookie file")
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.CookieFile = jar
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	ctxArgs.CookieJar = nil
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not send cookies of cookie file")
+}
+
+func TestRawHeaders(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:         templateID,
+		Path:       []string{"{{BaseURL}}"},
+		RawHeaders: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`header_order == "server,X-Custom-HEADER,Content-Length" && contains(raw_headers, "X-Custom-HEADER: value")`},
+			}},
+		},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				if _, err := http.ReadRequest(bufio.NewReader(conn)); err != nil {
+					return
+				}
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 OK\r\nserver: test\r\nX-Custom-HEADER: value\r\nContent-Length: 2\r\n\r\nok")
+			}(conn)
+		}
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), "http://"+listener.Addr().String())
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match on raw headers")
+}
+
+func TestRawSocket(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:        templateID,
+		RawSocket: true,
+		Raw:       []string{"POST / HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 4\r\nContent-Length: 0\r\n\r\ntest"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "raw_response",
+				Words: []string{"HTTP/1.1 400 Duplicate Content-Length"},
+			}},
+		},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buffer := make([]byte, 1024)
+				n, _ := conn.Read(buffer)
+				if strings.Count(string(buffer[:n]), "Content-Length:") == 2 {
+					_, _ = io.WriteString(conn, "HTTP/1.1 400 Duplicate Content-Length\r\n\r\n")
+				}
+			}(conn)
+		}
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.Proxy = "http://127.0.0.1:8080"
+	err = request.Compile(executerOpts)
+	require.Error(t, err, "could compile raw socket request with proxy")
+	executerOpts.Proxy = ""
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), "http://"+listener.Addr().String())
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not send malformed request using raw socket")
+}
+
+func TestRawSocketRawPath(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:        templateID,
+		RawSocket: true,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "raw-path", Type: "postfix", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"/%2e%2e%2fadmin"}}},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"sent_path == '/static/%2fcss/%2e%2e%2fadmin'"},
+			}},
+		},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	requestLines := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buffer := make([]byte, 1024)
+				n, _ := conn.Read(buffer)
+				requestLine, _, _ := strings.Cut(string(buffer[:n]), "\r\n")
+				requestLines <- requestLine
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n")
+			}(conn)
+		}
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), "http://"+listener.Addr().String()+"/static/%2fcss?v=1")
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, "GET /static/%2fcss/%2e%2e%2fadmin?v=1 HTTP/1.1", <-requestLines, "could not send encoded separators verbatim")
+	require.True(t, matched, "could not match sent path")
+
+	request.RawSocket = false
+	require.Error(t, request.Compile(executerOpts), "could compile raw-path fuzzing without raw-socket")
+}
+
+func TestFuzzingMaxMatches(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID: templateID,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "query", Type: "replace", Mode: "single", MaxMatches: 2, Fuzz: fuzz.SliceOrMapSlice{Value: []string{"vuln1", "vuln2", "vuln3"}}},
+			{Part: "query", Type: "postfix", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"vuln"}}},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"vuln"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.RawQuery)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	for _, input := range []string{ts.URL + "/?a=1&b=2", ts.URL + "/?c=3&d=4"} {
+		var matches int
+		ctxArgs := contextargs.NewWithInput(context.Background(), input)
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matches++
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Equal(t, 4, matches, "could not limit matches of rule for %s", input)
+	}
+}
+
+func TestFuzzingMaxDurationReached(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID: templateID,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "query", Type: "postfix", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"vuln"}}},
+		},
+	}
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	ctxArgs := contextargs.NewWithInput(ctx, ts.URL+"/?a=1&b=2")
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not stop fuzzing after max duration")
+	require.Zero(t, requests.Load(), "sent fuzzing requests after max duration")
+}
+
+func TestFuzzingWAFDetected(t *testing.T) {
+	options := testutils.DefaultOptions
+	options.FuzzWAFDetect = true
+	options.FuzzWAFThreshold = 2
+	defer func() {
+		options.FuzzWAFDetect = false
+		options.FuzzWAFThreshold = 0
+	}()
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID: templateID,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "query", Type: "postfix", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"'", "<script>", "../"}}},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"vulnerable"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "a=1" {
+			_, _ = io.WriteString(w, "ok")
+			return
+		}
+		w.Header().Set("Server", "cloudflare")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.High}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var results []*output.ResultEvent
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+"/?a=1")
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		results = append(results, event.Results...)
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Len(t, results, 1, "could not report waf once")
+	require.Equal(t, wafDetectedName, results[0].MatcherName, "could not name waf event")
+	require.True(t, results[0].Partial, "could not flag waf event as not a finding")
+	require.Equal(t, severity.Info, results[0].Info.SeverityHolder.Severity, "could not report waf event as info")
+	require.Equal(t, []string{"cloudflare"}, results[0].ExtractedResults, "could not extract waf vendor")
+	require.Equal(t, "cloudflare", results[0].Metadata["waf_vendor"], "could not add waf vendor to metadata")
+}
+
+func TestFuzzingStatusFilter(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if strings.Contains(r.URL.RawQuery, "error") {
+			w.WriteHeader(http.StatusInternalServerError)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = io.WriteString(w, r.URL.RawQuery)
+	}))
+	defer ts.Close()
+
+	execute := func(filter *fuzz.StatusFilter, payloads []string) (int, int32) {
+		request := &Request{
+			ID: templateID,
+			Fuzzing: []*fuzz.Rule{
+				{Part: "query", Type: "replace", Mode: "single", StatusFilter: filter, Fuzz: fuzz.SliceOrMapSlice{Value: payloads}},
+			},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Part:  "body",
+					Words: []string{"vuln"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		requests.Store(0)
+		var matches int
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+"/?a=1&b=2")
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matches++
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		return matches, requests.Load()
+	}
+
+	matches, sent := execute(&fuzz.StatusFilter{Status: []string{"5xx"}}, []string{"vuln", "vuln-error"})
+	require.Equal(t, 2, matches, "could not skip matchers of filtered status")
+	require.Equal(t, int32(4), sent, "could not send all payloads")
+
+	matches, sent = execute(&fuzz.StatusFilter{Status: []string{"5xx"}, SkipComponent: true}, []string{"vuln", "vuln-error"})
+	require.Equal(t, 0, matches, "could not skip component of filtered status")
+	require.Equal(t, int32(1), sent, "could not skip remaining payloads of component")
+
+	matches, sent = execute(&fuzz.StatusFilter{Status: []string{"5xx"}, Baseline: true}, []string{"vuln", "vuln-error"})
+	require.Equal(t, 0, matches, "could not skip rule of filtered baseline status")
+	require.Equal(t, int32(1), sent, "could not skip rule after baseline request")
+
+	matches, _ = execute(&fuzz.StatusFilter{Status: []string{"404"}, Baseline: true}, []string{"vuln", "vuln-error"})
+	require.Equal(t, 4, matches, "could not fuzz rule of allowed baseline status")
+}
+
+func TestFuzzingVerifyControl(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:            templateID,
+		VerifyControl: true,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "query", Type: "replace", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"vuln"}}},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"vuln"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the unmodified request fails for the broken path
+		if r.URL.Path == "/broken" && r.URL.Query().Get("a") == "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = io.WriteString(w, r.URL.RawQuery)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	for path, failed := range map[string]bool{"/ok": false, "/broken": true} {
+		var event *output.InternalWrappedEvent
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+path+"?a=1")
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {
+			event = e
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.NotNil(t, event, "could not get event for %s", path)
+		require.Equal(t, failed, event.InternalEvent["control_failed"], "could not verify control for %s", path)
+		_, flagged := event.OperatorsResult.PayloadValues["control"]
+		require.Equal(t, failed, flagged, "could not flag result for %s", path)
+	}
+}
+
+func TestFuzzingConfirmMatches(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:             templateID,
+		ConfirmMatches: true,
+		ConfirmControl: true,
+		Fuzzing: []*fuzz.Rule{
+			{Part: "query", Type: "replace", Mode: "single", Fuzz: fuzz.SliceOrMapSlice{Value: []string{"vuln"}}},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"vuln"},
+			}},
+		},
+	}
+	var flakyRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			// only the first fuzzing request is matched
+			if flakyRequests.Add(1) > 1 {
+				return
+			}
+		case "/control":
+			_, _ = io.WriteString(w, "vuln")
+			return
+		}
+		_, _ = io.WriteString(w, r.URL.RawQuery)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	// confirmations must not be served the stored responses of the project
+	projectFile, err := projectfile.New(&projectfile.Options{Path: t.TempDir(), Cleanup: true})
+	require.Nil(t, err, "could not create project file")
+	executerOpts.ProjectFile = projectFile
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	for path, expected := range map[string]string{"/stable": confirmationConfirmed, "/flaky": confirmationFlaky, "/control": confirmationControlMatched} {
+		var results []*output.ResultEvent
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+path+"?a=1")
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {
+			results = append(results, e.Results...)
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, results, 1, "could not get result for %s", path)
+		require.Equal(t, expected, results[0].Metadata["confirmation"], "could not confirm match for %s", path)
+
+		expectedSeverity := severity.Low
+		if expected != confirmationConfirmed {
+			expectedSeverity = severity.Info
+		}
+		require.Equal(t, expectedSeverity, results[0].Info.SeverityHolder.Severity, "could not get severity of result for %s", path)
+	}
+}
+
+func TestFuzzingEarlyAbortPerParameter(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID: templateID,
+		Fuzzing: []*fuzz.Rule{{
+			Part:       "query",
+			Type:       "replace",
+			Mode:       "single",
+			Fuzz:       fuzz.SliceOrMapSlice{Value: []string{"1", "2", "3", "4"}},
+			EarlyAbort: &fuzz.EarlyAbort{After: 2},
+		}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"vuln"},
+			}},
+		},
+	}
+	requests := map[string]int{}
+	var mutex sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for key, values := range r.URL.Query() {
+			if values[0] != "x" {
+				requests[key]++
+			}
+		}
+		_, _ = io.WriteString(w, "static")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+"/?a=x&b=x")
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, map[string]int{"a": 2, "b": 2}, requests, "could not abort each parameter early")
+}
+
+func TestFuzzingSeenParams(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	var sent atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		_, _ = io.WriteString(w, "static")
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "seen.txt")
+	execute := func(templateID string) int32 {
+		store, err := seenparams.New(path)
+		require.Nil(t, err, "could not open seen parameters store")
+		defer store.Close()
+
+		request := &Request{
+			ID: templateID,
+			Fuzzing: []*fuzz.Rule{{
+				Part: "query",
+				Type: "replace",
+				Mode: "multiple",
+				Fuzz: fuzz.SliceOrMapSlice{Value: []string{"1"}},
+			}},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.SeenParams = store
+		err = request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		sent.Store(0)
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL+"/?a=x&b=x")
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {})
+		require.Nil(t, err, "could not execute http request")
+		return sent.Load()
+	}
+	require.Equal(t, int32(1), execute("first-template"), "could not fuzz new parameters")
+	require.Zero(t, execute("first-template"), "fuzzed parameters seen in a previous run")
+	require.Equal(t, int32(1), execute("second-template"), "could not fuzz parameters seen by another template")
+}
+
+func TestRequestPrerequisites(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-prerequisites"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/admin?token={{token}}"},
+		Prerequisites: []*Request{{
+			ID:   "login",
+			Path: []string{"{{BaseURL}}/login"},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type:   matchers.MatcherTypeHolder{MatcherType: matchers.StatusMatcher},
+					Status: []int{200},
+				}},
+				Extractors: []*extractors.Extractor{{
+					Part:     "body",
+					Name:     "token",
+					Type:     extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+					Regex:    []string{"[a-z0-9]{6}"},
+					Internal: true,
+				}},
+			},
+		}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Part:  "body",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"welcome"},
+			}},
+		},
+	}
+	var loginUp bool
+	var adminRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if !loginUp {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("abc123"))
+		case "/admin":
+			adminRequests++
+			if r.URL.Query().Get("token") == "abc123" {
+				_, _ = w.Write([]byte("welcome"))
+			}
+		}
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	execute := func() (matched bool) {
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+		err := request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = true
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		return matched
+	}
+
+	require.False(t, execute(), "should not match when prerequisite fails")
+	require.Equal(t, 0, adminRequests, "main request should be skipped when prerequisite fails")
+
+	loginUp = true
+	require.True(t, execute(), "could not match with extracted prerequisite value")
+	require.Equal(t, 1, adminRequests)
+}
+
+func TestCompareHTTP2(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:           templateID,
+		Path:         []string{"{{BaseURL}}"},
+		CompareHTTP2: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`protocol_diff && status_code == 200 && http2_status_code == 404 && http2_protocol == "HTTP/2.0"`},
+			}},
+		},
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match on http2 divergence")
+}
+
+func TestSSRFMatcher(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/fetch?url=http://169.254.169.254/latest/meta-data/"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:        matchers.MatcherTypeHolder{MatcherType: matchers.SSRFMatcher},
+				SSRFTargets: []string{"cloud-metadata"},
+			}},
+		},
+	}
+	var controlURL atomic.Value
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched := r.URL.Query().Get("url")
+		if strings.Contains(fetched, "169.254.169.254") {
+			_, _ = w.Write([]byte("ami-id\ninstance-id\nlocal-ipv4"))
+			return
+		}
+		controlURL.Store(fetched)
+		_, _ = w.Write([]byte("<html>Example Domain</html>"))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var matched bool
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched = true
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched, "could not match ssrf to metadata service")
+	require.Equal(t, "http://example.com/latest/meta-data/", controlURL.Load(), "could not send control request")
+}
+
+func TestChallengeClearance(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "body",
+				Words: []string{"welcome"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err == nil && cookie.Value == "passed" && r.UserAgent() == "challenge-browser" {
+			_, _ = io.WriteString(w, "welcome")
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, "<html><title>Just a moment...</title></html>")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	handler, err := challenge.New(nil)
+	require.Nil(t, err, "could not create challenge handler")
+	executerOpts.Challenges = handler
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	execute := func() bool {
+		var matched bool
+		err := request.ExecuteWithResults(contextargs.NewWithInput(context.Background(), ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = true
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		return matched
+	}
+	require.False(t, execute(), "matched challenge page")
+	detected, _, _ := handler.Stats()
+	require.Equal(t, int64(1), detected, "could not detect challenge")
+
+	host := strings.Split(strings.TrimPrefix(ts.URL, "http://"), ":")[0]
+	_, err = handler.Pass(host, func() (*challenge.Clearance, error) {
+		return &challenge.Clearance{Cookies: []*http.Cookie{{Name: "cf_clearance", Value: "passed"}}, UserAgent: "challenge-browser"}, nil
+	})
+	require.Nil(t, err, "could not pass challenge")
+	require.True(t, execute(), "could not reuse challenge clearance")
+}
+
+func TestResponseCache(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	var sent atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		_, _ = io.WriteString(w, "User-agent: *\nDisallow: /admin")
+	}))
+	defer ts.Close()
+
+	cache := responsecache.New(10, time.Minute)
+	execute := func(templateID, customIP string) bool {
+		request := &Request{
+			ID:   templateID,
+			Path: []string{"{{BaseURL}}/robots.txt"},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Part:  "body",
+					Words: []string{"Disallow"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.ResponseCache = cache
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		var matched bool
+		input := contextargs.NewWithInput(context.Background(), ts.URL)
+		input.MetaInput.CustomIP = customIP
+		err = request.ExecuteWithResults(input, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = true
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		u, _ := url.Parse(ts.URL)
+		require.Len(t, input.CookieJar.Cookies(u), 1, "could not set cookies of response")
+		return matched
+	}
+	require.True(t, execute("first-template", ""), "could not match response")
+	require.True(t, execute("second-template", ""), "could not match cached response")
+	require.Equal(t, int32(1), sent.Load(), "could not serve request from cache")
+
+	// responses of other addresses of the input are not served from cache
+	require.True(t, execute("third-template", "127.0.0.1"), "could not match response")
+	require.Equal(t, int32(2), sent.Load(), "served response of another address from cache")
+
+	hits, misses := cache.Stats()
+	require.Equal(t, int64(1), hits)
+	require.Equal(t, int64(2), misses)
+}
+
+func TestRawRequestMatchers(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:     templateID,
+		Unsafe: true,
+		Raw:    []string{"GET /?q=payload HTTP/1.1\r\nHost: {{Hostname}}\r\nX-Marker:  spaced\r\n\r\n"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Part:  "raw_request",
+				Words: []string{"X-Marker:  spaced"},
+			}},
+			Extractors: []*extractors.Extractor{{
+				Type:  extractors.ExtractorTypeHolder{ExtractorType: extractors.RegexExtractor},
+				Part:  "raw_request",
+				Regex: []string{`q=[a-z]+`},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	var event *output.InternalWrappedEvent
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {
+		event = e
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotNil(t, event, "could not get event")
+	require.Contains(t, event.InternalEvent["raw_request"], "X-Marker:  spaced\r\n", "could not get raw request bytes")
+	require.True(t, event.OperatorsResult != nil && event.OperatorsResult.Matched, "could not match raw request")
+	require.Contains(t, event.OperatorsResult.OutputExtracts, "q=payload", "could not extract from raw request")
+
+	request.rawRequest = false
+	event = nil
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(e *output.InternalWrappedEvent) {
+		event = e
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.NotContains(t, event.InternalEvent, "raw_request", "captured unreferenced raw request")
+}
+
+func TestCachePoisoningVariables(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:      templateID,
+		Path:    []string{"{{BaseURL}}/static", "{{BaseURL}}/keyed"},
+		Headers: map[string]string{"X-Forwarded-Host": "poison.example"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"is_cacheable && reflected_header == 'x-forwarded-host'"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if r.URL.Path == "/keyed" {
+			w.Header().Set("Vary", "X-Forwarded-Host")
+		}
+		_, _ = fmt.Fprintf(w, `<script src="//%s/app.js"></script>`, r.Header.Get("X-Forwarded-Host"))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+	require.True(t, request.cachePoisoning, "could not detect cache poisoning variables")
+
+	matched := make(map[string]bool)
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		matched[fmt.Sprint(event.InternalEvent["matched"])] = event.OperatorsResult != nil && event.OperatorsResult.Matched
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.True(t, matched[ts.URL+"/static"], "could not match cacheable reflection")
+	require.False(t, matched[ts.URL+"/keyed"], "matched reflection of header in vary")
+}
+
+func TestConnectionBehaviorVariables(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	request := &Request{
+		ID:   templateID,
+		Path: []string{"{{BaseURL}}/first", "{{BaseURL}}/second", "{{BaseURL}}/close", "{{BaseURL}}/announced"},
+		Operators: operators.Operators{
+			Extractors: []*extractors.Extractor{{
+				Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.DSLExtractor},
+				DSL:  []string{"connection_reused + ',' + connection_closed_by_server"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/close":
+			// close the connection after the response without announcing it
+			conn, bufrw, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			_, _ = bufrw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+			_ = bufrw.Flush()
+			_ = conn.Close()
+		case "/announced":
+			w.Header().Set("Connection", "close")
+			_, _ = w.Write([]byte("ok"))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err := request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+	require.True(t, request.trackConnections, "could not detect connection behavior variables")
+
+	behavior := make(map[string]string)
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		behavior[fmt.Sprint(event.InternalEvent["matched"])] = fmt.Sprintf("%v,%v", event.InternalEvent["connection_reused"], event.InternalEvent["connection_closed_by_server"])
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, "false,false", behavior[ts.URL+"/first"], "could not track new connection")
+	require.Equal(t, "true,false", behavior[ts.URL+"/second"], "could not track reused connection")
+	require.Equal(t, "true,true", behavior[ts.URL+"/close"], "could not track unannounced close")
+	require.Equal(t, "false,true", behavior[ts.URL+"/announced"], "could not track announced close")
+}
+
+func TestPagination(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		switch r.URL.Path {
+		case "/old/list":
+			http.Redirect(w, r, "/v2/list?page=0", http.StatusFound)
+			return
+		case "/v2/list":
+			// next pages are relative to the url after redirects
+			if page < 2 {
+				w.Header().Set("Link", fmt.Sprintf(`<list?page=%d>; rel="next"`, page+1))
+			}
+			_, _ = fmt.Fprintf(w, `{"items":["v%d"]}`, page)
+			return
+		}
+		if r.URL.Path == "/api" {
+			// pages of the api are linked by json cursors
+			next := ""
+			if page < 3 {
+				next = strconv.Itoa(page + 1)
+			}
+			_, _ = fmt.Fprintf(w, `{"items":["user%d"],"next":%q}`, page, next)
+			return
+		}
+		// pages of the web endpoint are linked by link headers and loop back
+		w.Header().Set("Link", fmt.Sprintf(`</web?page=%d>; rel="next"`, (page+1)%3))
+		_, _ = fmt.Fprintf(w, `{"items":["web%d"]}`, page)
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	tests := []struct {
+		path       string
+		pagination *Pagination
+		redirects  bool
+		extracted  []string
+		requests   int32
+	}{
+		{path: "/api?page=1", pagination: &Pagination{Type: PaginationJSON, JSON: ".next", Param: "page"}, extracted: []string{"user1", "user2", "user3"}, requests: 3},
+		{path: "/api?page=1", pagination: &Pagination{Type: PaginationJSON, JSON: ".next", Param: "page", MaxPages: 2}, extracted: []string{"user1", "user2"}, requests: 2},
+		{path: "/web?page=0", pagination: &Pagination{Type: PaginationLink}, extracted: []string{"web0", "web1", "web2"}, requests: 3},
+		{path: "/old/list", pagination: &Pagination{Type: PaginationLink}, redirects: true, extracted: []string{"v0", "v1", "v2"}, requests: 4},
+	}
+	for _, test := range tests {
+		request := &Request{
+			ID:         templateID,
+			Path:       []string{"{{BaseURL}}" + test.path},
+			Pagination: test.pagination,
+			Redirects:  test.redirects,
+			Operators: operators.Operators{
+				Extractors: []*extractors.Extractor{{
+					Type: extractors.ExtractorTypeHolder{ExtractorType: extractors.JSONExtractor},
+					JSON: []string{".items[]"},
+				}},
+			},
+		}
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile http request")
+
+		requests.Store(0)
+		var extracted []string
+		ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+		err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			for _, result := range event.Results {
+				extracted = append(extracted, result.ExtractedResults...)
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.ElementsMatch(t, test.extracted, extracted, "could not aggregate extracted results of %s", test.path)
+		require.Equal(t, test.requests, requests.Load(), "could not cap pages of %s", test.path)
+	}
+
+	err := (&Pagination{Type: PaginationJSON}).Compile()
+	require.Error(t, err, "could compile json pagination without query")
+
+	unsafe := &Request{ID: templateID, Raw: []string{"GET / HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n"}, Unsafe: true, Pagination: &Pagination{Type: PaginationLink}}
+	err = unsafe.Compile(executerOpts)
+	require.Error(t, err, "could compile pagination of unsafe request")
+}
+
+func TestWriteBodyDiffHosts(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-http"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	dir := t.TempDir()
+	writer, err := bodydiff.New(dir, 0, 0)
+	require.Nil(t, err, "could not create body diff writer")
+	executerOpts.BodyDiff = writer
+	request := &Request{ID: templateID, options: executerOpts}
+
+	event := &output.InternalWrappedEvent{InternalEvent: output.InternalEvent{"body": "current"}}
+	for _, host := range []string{"https://a.example.com/?id=1", "https://b.example.com/?id=1"} {
+		req, err := retryablehttp.NewRequest(http.MethodGet, host, nil)
+		require.Nil(t, err, "could not create request")
+		request.writeBodyDiff(fuzz.GeneratedRequest{Request: req, Parameter: "id"}, contextargs.NewWithInput(context.Background(), host), "baseline", event)
+	}
+	diffs, err := filepath.Glob(filepath.Join(dir, "*.diff"))
+	require.Nil(t, err, "could not list body diffs")
+	require.Len(t, diffs, 2, "could not save body diffs of each host")
+	require.Contains(t, filepath.Base(diffs[0]), "a.example.com", "could not include host in body diff name")
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/responsecache"
+)
+
+// responseCacheKey returns the response cache key of the generated request
+// if its response can be cached. Only safe requests sent by the standard
+// client without side effects (fuzzing, interactsh, signatures) are cached.
+func (request *Request) responseCacheKey(input *contextargs.Context, gr *generatedRequest) string {
+	if request.options.ResponseCache == nil || gr.request == nil || gr.resend || len(request.Fuzzing) > 0 || len(gr.interactshURLs) > 0 {
+		return ""
+	}
+	if request.Signature.Value != 0 || request.Decompression != "" || request.RawHeaders || !responsecache.IsCacheable(gr.request.Method) {
+		return ""
+	}
+	header := gr.request.Header.Clone()
+	// cookies of the jar are added by the client and vary the response
+	if input.CookieJar != nil {
+		for _, cookie := range input.CookieJar.Cookies(gr.request.URL.URL) {
+			header.Add("Cookie", cookie.String())
+		}
+	}
+	key := responsecache.Key(gr.request.Method, gr.request.URL.String(), header)
+	// responses vary with the address dialed, the host header and the
+	// server name sent in the tls handshake
+	if input.MetaInput.CustomIP != "" {
+		key += "\nip: " + input.MetaInput.CustomIP
+	}
+	if gr.request.Host != "" && gr.request.Host != gr.request.URL.Host {
+		key += "\nhost: " + gr.request.Host
+	}
+	if sni := requestSNI(gr.request.Context(), request.options.Options.SNI); sni != "" {
+		key += "\nsni: " + sni
+	}
+	return key
+}
+
+// requestSNI returns the server name sent for the request context
+// (overridden by annotations) or the global one
+func requestSNI(ctx context.Context, sni string) string {
+	if value, ok := ctx.Value(fastdialer.SniName).(string); ok && value != "" {
+		return value
+	}
+	return sni
+}
+
+// getCachedResponse returns the cached response of the key updating the
+// cookie jar of the input with its cookies as if the request was sent
+func (request *Request) getCachedResponse(input *contextargs.Context, gr *generatedRequest, key string) (*http.Response, bool) {
+	resp, ok := request.options.ResponseCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if input.CookieJar != nil {
+		if cookies := resp.Cookies(); len(cookies) > 0 {
+			input.CookieJar.SetCookies(gr.request.URL.URL, cookies)
+		}
+	}
+	return resp, true
+}
+
+// setCachedResponse caches the response of the key if it was fully read
+func (request *Request) setCachedResponse(key string, resp *http.Response, rawBody *bytes.Buffer) {
+	if key == "" || rawBody == nil || !responsecache.IsCacheableResponse(resp) {
+		return
+	}
+	// partially read (ex: truncated) bodies are flagged by the response chain
+	if resp.Header.Get("x-nuclei-ignore-error") != "" {
+		return
+	}
+	request.options.ResponseCache.Set(key, resp, rawBody.Bytes())
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// performanceHeaders are non standard headers carrying the processing
+// time of the backend exposed along with the server-timing metrics
+var performanceHeaders = []string{"X-Runtime", "X-Response-Time", "X-Processing-Time", "X-Request-Duration"}
+
+// serverTimingMetrics returns the server-timing metrics of the response as a map
+// of the duration in milliseconds by lowercased metric name along with the
+// durations of known performance headers (ex: x-runtime). An empty map is
+// returned if the response has no timing headers.
+func serverTimingMetrics(header http.Header) map[string]interface{} {
+	metrics := make(map[string]interface{})
+	for _, value := range header.Values("Server-Timing") {
+		for _, entry := range splitServerTiming(value) {
+			name, duration, ok := parseServerTimingMetric(entry)
+			if !ok {
+				continue
+			}
+			// the first metric wins for duplicate names as per the spec
+			if _, ok := metrics[name]; !ok {
+				metrics[name] = duration
+			}
+		}
+	}
+	for _, name := range performanceHeaders {
+		if duration, ok := parsePerformanceHeader(name, header.Get(name)); ok {
+			metrics[strings.ToLower(name)] = duration
+		}
+	}
+	return metrics
+}
+
+// serverTimingVariables returns the metrics as flat variables named
+// server_timing_<metric> so that they can be compared in dsl expressions
+func serverTimingVariables(metrics map[string]interface{}) map[string]interface{} {
+	variables := make(map[string]interface{}, len(metrics))
+	for name, duration := range metrics {
+		variables["server_timing_"+strings.ReplaceAll(name, "-", "_")] = duration
+	}
+	return variables
+}
+
+// splitServerTiming splits a server-timing header value into metric entries
+// ignoring commas in quoted descriptions
+func splitServerTiming(value string) []string {
+	var entries []string
+	var quoted, escaped bool
+	start := 0
+	for i, c := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quoted:
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			entries = append(entries, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(entries, value[start:])
+}
+
+// parseServerTimingMetric parses a metric entry (ex: db;desc="Query";dur=53.2)
+// returning the lowercased name and the duration. Metrics without a valid
+// duration have a duration of 0.
+func parseServerTimingMetric(entry string) (string, float64, bool) {
+	params := strings.Split(entry, ";")
+	name := strings.ToLower(strings.TrimSpace(params[0]))
+	if name == "" || strings.ContainsAny(name, " \t\"=") {
+		return "", 0, false
+	}
+	var duration float64
+	for _, param := range params[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "dur") {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(value), `"`), 64); err == nil {
+			duration = parsed
+		}
+		break
+	}
+	return name, duration, true
+}
+
+// parsePerformanceHeader parses the duration of a performance header in
+// milliseconds. X-Runtime is in seconds as set by rails and rack, values
+// of other headers are in milliseconds unless they have a unit (ex: 12ms, 0.5s).
+func parsePerformanceHeader(name, value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+		if strings.EqualFold(name, "X-Runtime") {
+			return parsed * 1000, true
+		}
+		return parsed, true
+	}
+	if parsed, err := time.ParseDuration(strings.ReplaceAll(value, " ", "")); err == nil {
+		return float64(parsed) / float64(time.Millisecond), true
+	}
+	return 0, false
+}
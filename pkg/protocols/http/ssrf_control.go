@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/ssrf"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/retryablehttp-go"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// ssrfControlRequest is a copy of a request fetching an internal target
+// with the target replaced by the control host of ssrf matchers
+type ssrfControlRequest struct {
+	request *retryablehttp.Request
+	target  *ssrf.Target
+	host    string
+}
+
+// ssrfControlResponse is the response of a control request
+type ssrfControlResponse struct {
+	target      *ssrf.Target
+	host        string
+	controlHost string
+	statusCode  int
+	body        string
+}
+
+// prepareSSRFControlRequest returns the control request of the request if
+// the request has ssrf matchers and fetches an internal target
+func (request *Request) prepareSSRFControlRequest(req *retryablehttp.Request) *ssrfControlRequest {
+	if request.ssrfControlHost == "" || req == nil || req.URL == nil {
+		return nil
+	}
+	body, _ := req.BodyBytes()
+
+	// the scanned host itself is not an internal target fetched by the request
+	// (ex: internal hosts scanned with the base url in headers)
+	origin := req.URL.Scheme + "://" + req.URL.Host
+	location := strings.TrimPrefix(req.URL.String(), origin)
+	corpus := &strings.Builder{}
+	corpus.WriteString(location)
+	for k, values := range req.Header {
+		if strings.EqualFold(k, "Host") {
+			continue
+		}
+		for _, value := range values {
+			corpus.WriteString("\n" + value)
+		}
+	}
+	corpus.WriteString("\n" + string(body))
+
+	detected := corpus.String()
+	if req.URL.Host != "" {
+		detected = strings.ReplaceAll(detected, req.URL.Host, "")
+	}
+	target, host := ssrf.Detect(detected)
+	if target == nil {
+		return nil
+	}
+	replace := func(value string) string {
+		return ssrf.ReplaceFold(value, host, request.ssrfControlHost)
+	}
+
+	controlURL, err := urlutil.ParseAbsoluteURL(origin+replace(location), true)
+	if err != nil {
+		return nil
+	}
+	controlReq, err := retryablehttp.NewRequestFromURLWithContext(req.Context(), req.Method, controlURL, bytes.NewReader([]byte(replace(string(body)))))
+	if err != nil {
+		return nil
+	}
+	for k, values := range req.Header {
+		for _, value := range values {
+			if !strings.EqualFold(k, "Host") {
+				value = replace(value)
+			}
+			controlReq.Header.Add(k, value)
+		}
+	}
+	controlReq.Header.Del("Content-Length")
+	controlReq.Host = req.Host
+	return &ssrfControlRequest{request: controlReq, target: target, host: host}
+}
+
+// executeSSRFControlRequest sends the control request returning its response
+func (request *Request) executeSSRFControlRequest(input *contextargs.Context, ctrl *ssrfControlRequest) *ssrfControlResponse {
+	if ctrl == nil {
+		return nil
+	}
+	request.options.RateLimitTake(input.Context(), input.MetaInput.Input)
+	resp, err := request.httpClient.Do(ctrl.request)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] Could not send ssrf control request to %s: %s\n", request.options.TemplateID, input.MetaInput.Input, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxBodyRead))
+	return &ssrfControlResponse{
+		target:      ctrl.target,
+		host:        ctrl.host,
+		controlHost: request.ssrfControlHost,
+		statusCode:  resp.StatusCode,
+		body:        string(body),
+	}
+}
+
+// values returns the variables of the control response compared by ssrf matchers
+func (response *ssrfControlResponse) values() map[string]interface{} {
+	return map[string]interface{}{
+		"ssrf_target":              response.target.Name,
+		"ssrf_host":                response.host,
+		"ssrf_control_host":        response.controlHost,
+		"ssrf_control_status_code": response.statusCode,
+		"ssrf_control_body":        response.body,
+	}
+}
@@ -0,0 +1,58 @@
+package tlsfingerprint
+
+import (
+	"strconv"
+)
+
+// extensionNames are the names of tls extensions in the iana registry
+var extensionNames = map[uint16]string{
+	0:     "server_name",
+	1:     "max_fragment_length",
+	5:     "status_request",
+	10:    "supported_groups",
+	11:    "ec_point_formats",
+	13:    "signature_algorithms",
+	15:    "heartbeat",
+	16:    "application_layer_protocol_negotiation",
+	18:    "signed_certificate_timestamp",
+	21:    "padding",
+	22:    "encrypt_then_mac",
+	23:    "extended_master_secret",
+	27:    "compress_certificate",
+	28:    "record_size_limit",
+	35:    "session_ticket",
+	41:    "pre_shared_key",
+	42:    "early_data",
+	43:    "supported_versions",
+	44:    "cookie",
+	45:    "psk_key_exchange_modes",
+	51:    "key_share",
+	13172: "next_protocol_negotiation",
+	65281: "renegotiation_info",
+}
+
+// ExtensionName returns the iana name of a tls extension or its number
+// if it is not known
+func ExtensionName(id uint16) string {
+	if name, ok := extensionNames[id]; ok {
+		return name
+	}
+	return strconv.Itoa(int(id))
+}
+
+// ServerExtensions returns the names of the extensions of a server hello
+// handshake message in the order they were sent.
+//
+// Extensions of tls 1.3 servers other than key_share, supported_versions
+// and pre_shared_key (ex: alpn) are encrypted and not part of the server hello.
+func ServerExtensions(message []byte) ([]string, error) {
+	hello, err := parseHello(message, serverHelloType)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(hello.extensions))
+	for _, id := range hello.extensions {
+		names = append(names, ExtensionName(id))
+	}
+	return names, nil
+}
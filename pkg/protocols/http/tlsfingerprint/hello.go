@@ -0,0 +1,199 @@
+package tlsfingerprint
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+const (
+	// maxRecordedSize is the maximum size of records recorded to find the
+	// first handshake message of a direction
+	maxRecordedSize = 64 * 1024
+
+	handshakeRecordType = 22
+	clientHelloType     = 1
+	serverHelloType     = 2
+
+	supportedGroupsExtension = 10
+	pointFormatsExtension    = 11
+)
+
+var errMalformedHello = errors.New("malformed hello message")
+
+// recordingConn is a connection recording the first handshake message
+// written and read over it
+type recordingConn struct {
+	net.Conn
+	written handshakeRecorder
+	read    handshakeRecorder
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	c.written.record(b)
+	return c.Conn.Write(b)
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.read.record(b[:n])
+	}
+	return n, err
+}
+
+// handshakeRecorder records tls records until the first handshake message
+// they contain is complete
+type handshakeRecorder struct {
+	mu       sync.Mutex
+	records  []byte
+	complete []byte
+	done     bool
+}
+
+func (r *handshakeRecorder) record(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.records = append(r.records, data...)
+	message, complete, err := firstHandshakeMessage(r.records)
+	if complete {
+		r.complete = message
+	}
+	if complete || err != nil || len(r.records) > maxRecordedSize {
+		r.records = nil
+		r.done = true
+	}
+}
+
+// message returns the first handshake message or nil if it was not recorded
+func (r *handshakeRecorder) message() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.complete
+}
+
+// firstHandshakeMessage returns the first handshake message of the records
+// which may be fragmented over multiple records
+func firstHandshakeMessage(records []byte) ([]byte, bool, error) {
+	var message []byte
+	for len(records) >= 5 {
+		if records[0] != handshakeRecordType {
+			return nil, false, errMalformedHello
+		}
+		length := int(records[3])<<8 | int(records[4])
+		if len(records) < 5+length {
+			break
+		}
+		message = append(message, records[5:5+length]...)
+		records = records[5+length:]
+		if len(message) >= 4 {
+			size := 4 + (int(message[1])<<16 | int(message[2])<<8 | int(message[3]))
+			if len(message) >= size {
+				return message[:size], true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// hello contains the fields of a hello message used by fingerprints
+type hello struct {
+	version    uint16
+	ciphers    []uint16
+	extensions []uint16
+	curves     []uint16
+	points     []uint16
+}
+
+// parseHello parses a client or server hello handshake message
+func parseHello(message []byte, messageType byte) (*hello, error) {
+	if len(message) < 4 || message[0] != messageType {
+		return nil, errMalformedHello
+	}
+	r := &reader{data: message[4:]}
+	h := &hello{version: r.uint16()}
+	r.skip(32) // random
+	r.skip(int(r.uint8()))
+	if messageType == clientHelloType {
+		ciphers := &reader{data: r.bytes(int(r.uint16()))}
+		for !ciphers.empty() {
+			h.ciphers = append(h.ciphers, ciphers.uint16())
+		}
+		r.skip(int(r.uint8()))
+	} else {
+		h.ciphers = []uint16{r.uint16()}
+		r.skip(1)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.empty() {
+		return h, nil
+	}
+	extensions := &reader{data: r.bytes(int(r.uint16()))}
+	for !extensions.empty() && extensions.err == nil {
+		extensionType := extensions.uint16()
+		data := &reader{data: extensions.bytes(int(extensions.uint16()))}
+		h.extensions = append(h.extensions, extensionType)
+		if messageType != clientHelloType {
+			continue
+		}
+		switch extensionType {
+		case supportedGroupsExtension:
+			curves := &reader{data: data.bytes(int(data.uint16()))}
+			for !curves.empty() && curves.err == nil {
+				h.curves = append(h.curves, curves.uint16())
+			}
+		case pointFormatsExtension:
+			for _, point := range data.bytes(int(data.uint8())) {
+				h.points = append(h.points, uint16(point))
+			}
+		}
+	}
+	if r.err != nil || extensions.err != nil {
+		return nil, errMalformedHello
+	}
+	return h, nil
+}
+
+// reader is a reader of big endian values of a hello message
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) empty() bool {
+	return len(r.data) == 0
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = errMalformedHello
+		r.data = nil
+		return nil
+	}
+	value := r.data[:n]
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *reader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *reader) uint8() uint8 {
+	if value := r.bytes(1); value != nil {
+		return value[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if value := r.bytes(2); value != nil {
+		return uint16(value[0])<<8 | uint16(value[1])
+	}
+	return 0
+}
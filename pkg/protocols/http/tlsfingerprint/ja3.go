@@ -0,0 +1,139 @@
+package tlsfingerprint
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+
+	utls "github.com/refraction-networking/utls"
+)
+
+// signatureAlgorithms are the signature algorithms advertised by the
+// signature algorithms extensions of JA3 strings which only list ids
+var signatureAlgorithms = []utls.SignatureScheme{
+	utls.ECDSAWithP256AndSHA256,
+	utls.PSSWithSHA256,
+	utls.PKCS1WithSHA256,
+	utls.ECDSAWithP384AndSHA384,
+	utls.PSSWithSHA384,
+	utls.PKCS1WithSHA384,
+	utls.PSSWithSHA512,
+	utls.PKCS1WithSHA512,
+	utls.PKCS1WithSHA1,
+}
+
+// parseJA3 returns a client hello spec sending the version, ciphers,
+// extensions, curves and point formats of a JA3 string. Extensions whose
+// content is not known by utls are sent empty.
+func parseJA3(value string) (*utls.ClientHelloSpec, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid ja3 string: expected 5 fields, got %d", len(fields))
+	}
+	version, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ja3 version %s", fields[0])
+	}
+	ciphers, err := parseJA3Values(fields[1])
+	if err != nil || len(ciphers) == 0 {
+		return nil, fmt.Errorf("invalid ja3 ciphers %s", fields[1])
+	}
+	extensionIDs, err := parseJA3Values(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ja3 extensions %s", fields[2])
+	}
+	curveIDs, err := parseJA3Values(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ja3 curves %s", fields[3])
+	}
+	pointIDs, err := parseJA3Values(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ja3 point formats %s", fields[4])
+	}
+
+	curves := make([]utls.CurveID, 0, len(curveIDs))
+	for _, curve := range curveIDs {
+		curves = append(curves, utls.CurveID(curve))
+	}
+	points := make([]uint8, 0, len(pointIDs))
+	for _, point := range pointIDs {
+		points = append(points, uint8(point))
+	}
+
+	spec := &utls.ClientHelloSpec{
+		TLSVersMin:         uint16(version),
+		TLSVersMax:         uint16(version),
+		CipherSuites:       ciphers,
+		CompressionMethods: []uint8{0},
+		GetSessionID:       sha256.Sum256,
+	}
+	for _, id := range extensionIDs {
+		switch id {
+		case 0:
+			spec.Extensions = append(spec.Extensions, &utls.SNIExtension{})
+		case 5:
+			spec.Extensions = append(spec.Extensions, &utls.StatusRequestExtension{})
+		case supportedGroupsExtension:
+			spec.Extensions = append(spec.Extensions, &utls.SupportedCurvesExtension{Curves: curves})
+		case pointFormatsExtension:
+			spec.Extensions = append(spec.Extensions, &utls.SupportedPointsExtension{SupportedPoints: points})
+		case 13:
+			spec.Extensions = append(spec.Extensions, &utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: signatureAlgorithms})
+		case 16:
+			spec.Extensions = append(spec.Extensions, &utls.ALPNExtension{AlpnProtocols: []string{"http/1.1"}})
+		case 18:
+			spec.Extensions = append(spec.Extensions, &utls.SCTExtension{})
+		case 21:
+			spec.Extensions = append(spec.Extensions, &utls.UtlsPaddingExtension{GetPaddingLen: utls.BoringPaddingStyle})
+		case 23:
+			spec.Extensions = append(spec.Extensions, &utls.ExtendedMasterSecretExtension{})
+		case 27:
+			spec.Extensions = append(spec.Extensions, &utls.UtlsCompressCertExtension{Algorithms: []utls.CertCompressionAlgo{utls.CertCompressionBrotli}})
+		case 35:
+			spec.Extensions = append(spec.Extensions, &utls.SessionTicketExtension{})
+		case 43:
+			spec.TLSVersMax = utls.VersionTLS13
+			spec.Extensions = append(spec.Extensions, &utls.SupportedVersionsExtension{Versions: []uint16{utls.VersionTLS13, utls.VersionTLS12}})
+		case 45:
+			spec.Extensions = append(spec.Extensions, &utls.PSKKeyExchangeModesExtension{Modes: []uint8{utls.PskModeDHE}})
+		case 50:
+			spec.Extensions = append(spec.Extensions, &utls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: signatureAlgorithms})
+		case 51:
+			spec.Extensions = append(spec.Extensions, &utls.KeyShareExtension{KeyShares: keySharesOf(curves)})
+		case 65281:
+			spec.Extensions = append(spec.Extensions, &utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateOnceAsClient})
+		default:
+			spec.Extensions = append(spec.Extensions, &utls.GenericExtension{Id: id})
+		}
+	}
+	return spec, nil
+}
+
+// keySharesOf returns the key share of the first curve supported for key
+// shares by utls
+func keySharesOf(curves []utls.CurveID) []utls.KeyShare {
+	for _, curve := range curves {
+		switch curve {
+		case utls.X25519, utls.CurveP256, utls.CurveP384, utls.CurveP521:
+			return []utls.KeyShare{{Group: curve}}
+		}
+	}
+	return []utls.KeyShare{{Group: utls.X25519}}
+}
+
+// parseJA3Values parses dash separated values of a JA3 field
+func parseJA3Values(field string) ([]uint16, error) {
+	if field == "" {
+		return nil, nil
+	}
+	var values []uint16
+	for _, part := range strings.Split(field, "-") {
+		value, err := strconv.ParseUint(part, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, uint16(value))
+	}
+	return values, nil
+}
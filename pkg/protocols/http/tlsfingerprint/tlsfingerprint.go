@@ -0,0 +1,306 @@
+// Package tlsfingerprint captures the JA3 fingerprint of the tls client
+// hello sent and the JA3S fingerprint of the server hello received by http
+// connections and allows sending the client hello of a browser profile or
+// a JA3 string instead of the one of the go tls stack.
+//
+// Connections are dialed in clear and the handshake is performed over a
+// connection recording the first handshake message in each direction.
+// Limitations:
+//   - the client hello of the go tls stack cannot be customized, custom
+//     profiles are sent using utls which does not implement every extension
+//     advertised by browsers (ex: some post-quantum key shares) and the
+//     handshake fails with servers negotiating them.
+//   - wrapped tls connections are not recognized by the transport which as a
+//     result always uses HTTP/1.1 for them (ALPN only offers http/1.1) and
+//     does not expose the tls connection state of responses.
+//   - client certificates are not sent with custom profiles.
+//   - connections tunneled through http proxies are upgraded to tls by the
+//     transport and are not fingerprinted.
+package tlsfingerprint
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	utls "github.com/refraction-networking/utls"
+)
+
+// DialFunc is a function dialing a network connection
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// profiles are the browser client hello profiles available by name
+var profiles = map[string]utls.ClientHelloID{
+	"chrome":  utls.HelloChrome_Auto,
+	"firefox": utls.HelloFirefox_Auto,
+	"safari":  utls.HelloSafari_Auto,
+	"edge":    utls.HelloEdge_Auto,
+	"ios":     utls.HelloIOS_Auto,
+}
+
+// Profile is a custom client hello sent instead of the go tls one
+type Profile struct {
+	id  *utls.ClientHelloID
+	ja3 string
+}
+
+// ParseProfile parses a client hello profile which is either the name of
+// a browser (chrome, firefox, safari, edge, ios) or a JA3 string
+func ParseProfile(value string) (*Profile, error) {
+	value = strings.TrimSpace(value)
+	if id, ok := profiles[strings.ToLower(value)]; ok {
+		return &Profile{id: &id}, nil
+	}
+	if _, err := parseJA3(value); err != nil {
+		return nil, fmt.Errorf("invalid client hello %s: expected one of chrome, firefox, safari, edge, ios or a ja3 string (%s)", value, err)
+	}
+	return &Profile{ja3: value}, nil
+}
+
+// spec returns a new client hello spec of the profile. Specs hold the state
+// of a handshake and cannot be shared between connections.
+func (p *Profile) spec() (*utls.ClientHelloSpec, error) {
+	var spec *utls.ClientHelloSpec
+	if p.id != nil {
+		parsed, err := utls.UTLSIdToSpec(*p.id)
+		if err != nil {
+			return nil, err
+		}
+		spec = &parsed
+	} else {
+		parsed, err := parseJA3(p.ja3)
+		if err != nil {
+			return nil, err
+		}
+		spec = parsed
+	}
+	// only http/1.1 can be negotiated as wrapped connections are not
+	// recognized by the transport for http/2
+	for _, extension := range spec.Extensions {
+		if alpn, ok := extension.(*utls.ALPNExtension); ok {
+			alpn.AlpnProtocols = []string{"http/1.1"}
+		}
+	}
+	return spec, nil
+}
+
+// Conn is a tls connection with the fingerprints of its handshake
+type Conn struct {
+	net.Conn
+
+	// JA3 is the JA3 string of the client hello sent
+	JA3 string
+	// JA3S is the JA3S string of the server hello received
+	JA3S string
+	// Extensions are the names of the extensions of the server hello received
+	Extensions []string
+	// ALPN is the application protocol negotiated by the handshake
+	ALPN string
+}
+
+// Wrap returns a function dialing tls connections over connections of
+// dial, sending the client hello of the profile (if any) and recording
+// the fingerprints of the handshake.
+func Wrap(dial DialFunc, config *tls.Config, profile *Profile) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		rawConn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		recorder := &recordingConn{Conn: rawConn}
+		serverName := serverNameOf(ctx, config, addr)
+
+		var conn net.Conn
+		var alpn string
+		if profile != nil {
+			spec, err := profile.spec()
+			if err != nil {
+				_ = rawConn.Close()
+				return nil, err
+			}
+			uConn := utls.UClient(recorder, &utls.Config{
+				InsecureSkipVerify: config.InsecureSkipVerify,
+				ServerName:         serverName,
+			}, utls.HelloCustom)
+			if err := uConn.ApplyPreset(spec); err != nil {
+				_ = rawConn.Close()
+				return nil, errors.Wrap(err, "could not apply client hello profile")
+			}
+			if err := uConn.HandshakeContext(ctx); err != nil {
+				_ = rawConn.Close()
+				return nil, err
+			}
+			conn = uConn
+			alpn = uConn.ConnectionState().NegotiatedProtocol
+		} else {
+			tlsConfig := config.Clone()
+			tlsConfig.ServerName = serverName
+			tlsConfig.NextProtos = nil
+			tlsConn := tls.Client(recorder, tlsConfig)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				_ = rawConn.Close()
+				return nil, err
+			}
+			conn = tlsConn
+			alpn = tlsConn.ConnectionState().NegotiatedProtocol
+		}
+
+		fingerprinted := &Conn{Conn: conn, ALPN: alpn}
+		if clientHello := recorder.written.message(); clientHello != nil {
+			fingerprinted.JA3, _ = JA3(clientHello)
+		}
+		if serverHello := recorder.read.message(); serverHello != nil {
+			fingerprinted.JA3S, _ = JA3S(serverHello)
+			fingerprinted.Extensions, _ = ServerExtensions(serverHello)
+		}
+		return fingerprinted, nil
+	}
+}
+
+// serverNameOf returns the server name sent for the address, the sni of
+// the configuration or of the context taking precedence over the host
+func serverNameOf(ctx context.Context, config *tls.Config, addr string) string {
+	if config.ServerName != "" {
+		return config.ServerName
+	}
+	if sni, ok := ctx.Value(fastdialer.SniName).(string); ok && sni != "" {
+		return sni
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	return host
+}
+
+// ALPNProtocols returns the negotiated application protocol as a list
+// matching the alpn_protocols of ssl requests (empty if none negotiated)
+func ALPNProtocols(negotiated string) []string {
+	if negotiated == "" {
+		return []string{}
+	}
+	return []string{negotiated}
+}
+
+// Hash returns the md5 hash of a JA3 or JA3S string as commonly shared
+func Hash(fingerprint string) string {
+	if fingerprint == "" {
+		return ""
+	}
+	sum := md5.Sum([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+// Capture contains the fingerprints of the connection of a request
+type Capture struct {
+	mu   sync.Mutex
+	conn *Conn
+}
+
+// WithCapture returns a context capturing the fingerprints of the
+// connection used by requests using it
+func WithCapture(ctx context.Context) (context.Context, *Capture) {
+	capture := &Capture{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			conn := info.Conn
+			for conn != nil {
+				if fingerprinted, ok := conn.(*Conn); ok {
+					capture.mu.Lock()
+					capture.conn = fingerprinted
+					capture.mu.Unlock()
+					return
+				}
+				// unwrap connections wrapping the fingerprinted connection
+				wrapper, ok := conn.(interface{ NetConn() net.Conn })
+				if !ok {
+					return
+				}
+				conn = wrapper.NetConn()
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), capture
+}
+
+// Values returns the fingerprints of the captured connection as ja3,
+// ja3_hash, ja3s, ja3s_hash, tls_extensions (server hello extensions) and
+// alpn_protocols (negotiated protocol) or nil if no tls connection was
+// captured
+func (c *Capture) Values() map[string]interface{} {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"ja3":            c.conn.JA3,
+		"ja3_hash":       Hash(c.conn.JA3),
+		"ja3s":           c.conn.JA3S,
+		"ja3s_hash":      Hash(c.conn.JA3S),
+		"tls_extensions": c.conn.Extensions,
+		"alpn_protocols": ALPNProtocols(c.conn.ALPN),
+	}
+}
+
+// JA3 returns the JA3 string of a client hello handshake message in the
+// format version,ciphers,extensions,curves,point formats ignoring grease
+// values
+func JA3(message []byte) (string, error) {
+	hello, err := parseHello(message, clientHelloType)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		strconv.Itoa(int(hello.version)),
+		joinValues(hello.ciphers),
+		joinValues(hello.extensions),
+		joinValues(hello.curves),
+		joinValues(hello.points),
+	}, ","), nil
+}
+
+// JA3S returns the JA3S string of a server hello handshake message in the
+// format version,cipher,extensions
+func JA3S(message []byte) (string, error) {
+	hello, err := parseHello(message, serverHelloType)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		strconv.Itoa(int(hello.version)),
+		joinValues(hello.ciphers),
+		joinValues(hello.extensions),
+	}, ","), nil
+}
+
+// joinValues joins values separated by dashes ignoring grease values
+func joinValues(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if !isGREASE(value) {
+			parts = append(parts, strconv.Itoa(int(value)))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// isGREASE returns true for the reserved grease values (RFC 8701)
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
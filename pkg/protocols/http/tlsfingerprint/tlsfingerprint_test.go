@@ -0,0 +1,65 @@
+package tlsfingerprint
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	_, err := ParseProfile("netscape")
+	require.NotNil(t, err, "parsed unknown client hello profile")
+
+	fingerprint := func(profile *Profile) map[string]interface{} {
+		dialer := &net.Dialer{}
+		transport := &http.Transport{DialTLSContext: Wrap(dialer.DialContext, &tls.Config{InsecureSkipVerify: true}, profile)}
+		defer transport.CloseIdleConnections()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.Nil(t, err, "could not create request")
+		ctx, capture := WithCapture(req.Context())
+		resp, err := (&http.Client{Transport: transport}).Do(req.WithContext(ctx))
+		require.Nil(t, err, "could not make request")
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.Equal(t, "ok", string(body), "could not read response")
+
+		values := capture.Values()
+		require.NotNil(t, values, "could not capture fingerprints")
+		require.Len(t, strings.Split(values["ja3"].(string), ","), 5, "could not get ja3")
+		require.Len(t, strings.Split(values["ja3s"].(string), ","), 3, "could not get ja3s")
+		require.Len(t, values["ja3_hash"], 32, "could not hash ja3")
+		require.Contains(t, values["tls_extensions"], "supported_versions", "could not get server hello extensions")
+		return values
+	}
+
+	native := fingerprint(nil)
+	chrome, err := ParseProfile("chrome")
+	require.Nil(t, err, "could not parse client hello profile")
+	custom := fingerprint(chrome)
+	require.NotEqual(t, native["ja3"], custom["ja3"], "could not send client hello of profile")
+
+	// sni (0) is not sent to ip addresses
+	replayed := "771,4865-4866-4867-49195-49199-49196-49200,23-65281-10-11-35-16-5-13-18-50-51-45-43,29-23-24,0"
+	ja3, err := ParseProfile(replayed)
+	require.Nil(t, err, "could not parse ja3 client hello profile")
+	require.Equal(t, replayed, fingerprint(ja3)["ja3"], "could not replay ja3 client hello")
+}
+
+func TestIsGREASE(t *testing.T) {
+	require.True(t, isGREASE(0x0a0a), "could not detect grease value")
+	require.True(t, isGREASE(0xfafa), "could not detect grease value")
+	require.False(t, isGREASE(0x0a1a), "detected non grease value")
+	require.False(t, isGREASE(0x1301), "detected cipher as grease value")
+}
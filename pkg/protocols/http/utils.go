@@ -1,8 +1,11 @@
 package http
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"io"
 	"strings"
+	"unicode"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/rawhttp"
@@ -11,6 +14,9 @@ import (
 
 // dump creates a dump of the http request in form of a byte slice
 func dump(req *generatedRequest, reqURL string) ([]byte, error) {
+	if req.original != nil && req.original.RawSocket {
+		return rawSocketDump(req)
+	}
 	if req.request != nil {
 		bin, err := req.request.Dump()
 		if err != nil {
@@ -25,3 +31,30 @@ func dump(req *generatedRequest, reqURL string) ([]byte, error) {
 	}
 	return bin, nil
 }
+
+// decodeRawBytes decodes value as per the encoding specified in template
+// into raw bytes. Whitespace in the encoded value is ignored.
+func (request *Request) decodeRawBytes(value string) (string, error) {
+	if request.Encoding == "" {
+		return value, nil
+	}
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, value)
+
+	var decoded []byte
+	var err error
+	switch request.Encoding {
+	case "hex":
+		decoded, err = hex.DecodeString(value)
+	case "base64":
+		decoded, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil {
+		return "", errorutil.NewWithErr(err).WithTag("http").Msgf("could not decode %v value", request.Encoding)
+	}
+	return string(decoded), nil
+}
@@ -1,6 +1,10 @@
 package http
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+)
 
 func (request *Request) validate() error {
 	if request.Race && request.NeedsRequestCondition() {
@@ -11,5 +15,39 @@ func (request *Request) validate() error {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if request.RawSocket && (request.Pipeline || request.Race) {
+		return errors.New("'raw-socket' can't be used with 'pipeline' or 'race'")
+	}
+
+	if request.CompareHTTP2 && (request.isRaw() && request.Unsafe || request.Pipeline || request.Race) {
+		return errors.New("'compare-http2' can't be used with 'unsafe', 'pipeline' or 'race'")
+	}
+
+	if request.Pagination != nil && (request.isRaw() && request.Unsafe || request.RawSocket || request.Pipeline || request.Race) {
+		return errors.New("'pagination' can't be used with 'unsafe', 'raw-socket', 'pipeline' or 'race'")
+	}
+
+	if request.BodyFile != "" && (request.Body != "" && !request.bodyFromFile || request.isRaw()) {
+		return errors.New("'body-file' can't be used with 'body' or 'raw'")
+	}
+
+	for _, rule := range request.Fuzzing {
+		if (rule.Part == component.RequestLineComponent || rule.Part == component.RequestRawPathComponent) && !request.RawSocket {
+			return errors.Errorf("fuzzing part '%s' requires 'raw-socket'", rule.Part)
+		}
+	}
+
+	switch request.Encoding {
+	case "", "hex", "base64":
+	default:
+		return errors.Errorf("invalid encoding '%s' specified, supported values are hex and base64", request.Encoding)
+	}
+
+	switch request.Decompression {
+	case "", DecompressionAuto, DecompressionNone:
+	default:
+		return errors.Errorf("invalid decompression '%s' specified, supported values are auto and none", request.Decompression)
+	}
+
 	return nil
 }
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/waf"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// wafDetectedName is the matcher name of informational waf events
+const wafDetectedName = "waf-detected"
+
+// observeWAF records the response of a fuzzing request and emits an
+// informational waf-detected event naming the vendor once payloads are
+// consistently blocked. Like partial match events, waf events are not
+// findings (not counted by exit codes nor reported to issue trackers).
+func (request *Request) observeWAF(detector *waf.Detector, event *output.InternalWrappedEvent, callback protocols.OutputEventCallback) {
+	if detector == nil || event == nil || event.InternalEvent == nil {
+		return
+	}
+	statusCode, _ := event.InternalEvent["status_code"].(int)
+	detected, vendor := detector.Observe(statusCode, types.ToString(event.InternalEvent["all_headers"]), types.ToString(event.InternalEvent["body"]))
+	if !detected {
+		return
+	}
+	if vendor == "" {
+		vendor = "unknown"
+	}
+	gologger.Warning().Msgf("[%s] Fuzzing payloads for %s appear to be blocked by a waf (vendor: %s)\n", request.options.TemplateID, event.InternalEvent["host"], vendor)
+	if stats, ok := request.options.Progress.(progress.Extension); ok {
+		stats.IncrementWAFHosts()
+	}
+
+	wafEvent := &output.InternalWrappedEvent{
+		InternalEvent: event.InternalEvent,
+		OperatorsResult: &operators.Result{
+			Matched:        true,
+			Matches:        map[string][]string{wafDetectedName: {}},
+			OutputExtracts: []string{vendor},
+			Operators:      &operators.Operators{},
+		},
+	}
+	wafEvent.Results = request.MakeResultEvent(wafEvent)
+	for _, result := range wafEvent.Results {
+		result.Partial = true
+		result.Info.SeverityHolder = severity.Holder{Severity: severity.Info}
+		result.Metadata = generators.MergeMaps(result.Metadata, map[string]interface{}{"waf_vendor": vendor})
+	}
+	callback(wafEvent)
+}
@@ -116,9 +116,10 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		for _, matcher := range compiled.Matchers {
-			if matcher.Part == "" && matcher.Type.MatcherType != matchers.DSLMatcher {
+			if matcher.Part == "" && !matcher.IsPartless() {
 				matcher.Part = "response"
 			}
 		}
@@ -268,7 +269,7 @@ func (request *Request) Requests() int {
 		pre_conditions = 1
 	}
 	if request.generator != nil {
-		payloadRequests := request.generator.NewIterator().Total()
+		payloadRequests := request.generator.Total()
 		return payloadRequests + pre_conditions
 	}
 	return 1 + pre_conditions
@@ -303,7 +304,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 	requestOptions := request.options
 	templateCtx := request.options.GetTemplateCtx(input.MetaInput)
 
-	payloadValues := generators.BuildPayloadFromOptions(request.options.Options)
+	payloadValues := request.options.BuildPayloadFromOptions(target.MetaInput)
 	for k, v := range dynamicValues {
 		payloadValues[k] = v
 	}
@@ -414,7 +415,7 @@ func (request *Request) executeRequestParallel(ctxParent context.Context, hostPo
 	gotmatches := &atomic.Bool{}
 
 	// if request threads matches global payload concurrency we follow it
-	shouldFollowGlobal := threads == request.options.Options.PayloadConcurrency
+	shouldFollowGlobal := threads == request.options.PayloadConcurrency()
 
 	sg, _ := syncutil.New(syncutil.WithSize(threads))
 
@@ -433,8 +434,8 @@ func (request *Request) executeRequestParallel(ctxParent context.Context, hostPo
 			}
 
 			// resize check point - nop if there are no changes
-			if shouldFollowGlobal && sg.Size != request.options.Options.PayloadConcurrency {
-				if err := sg.Resize(ctxParent, request.options.Options.PayloadConcurrency); err != nil {
+			if shouldFollowGlobal && sg.Size != request.options.PayloadConcurrency() {
+				if err := sg.Resize(ctxParent, request.options.PayloadConcurrency()); err != nil {
 					gologger.Warning().Msgf("Could not resize workpool: %s\n", err)
 				}
 			}
@@ -627,6 +628,7 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			Context:        input.Context(),
 		})
 	}
 	return nil
@@ -0,0 +1,126 @@
+// Package matchervars lists the variables exposed to matchers and extractors
+// by each protocol, sourced from the request part definitions maintained
+// alongside the code populating protocol output events.
+package matchervars
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/code"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/file"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/javascript"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/offlinehttp"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/ssl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/websocket"
+	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// AllProtocols is the protocol name used to list variables of every protocol
+const AllProtocols = "all"
+
+// definitions contains request part definitions of each protocol
+var definitions = map[string]map[string]string{
+	templateTypes.CodeProtocol.String():       code.RequestPartDefinitions,
+	templateTypes.DNSProtocol.String():        dns.RequestPartDefinitions,
+	templateTypes.FileProtocol.String():       file.RequestPartDefinitions,
+	templateTypes.HeadlessProtocol.String():   headless.RequestPartDefinitions,
+	templateTypes.HTTPProtocol.String():       http.RequestPartDefinitions,
+	templateTypes.JavascriptProtocol.String(): javascript.RequestPartDefinitions,
+	templateTypes.NetworkProtocol.String():    network.RequestPartDefinitions,
+	"offlinehttp":                             offlinehttp.RequestPartDefinitions,
+	templateTypes.SSLProtocol.String():        ssl.RequestPartDefinitions,
+	templateTypes.WebsocketProtocol.String():  websocket.RequestPartDefinitions,
+}
+
+// aliases contains alternative names of protocols
+var aliases = map[string]string{
+	"network": templateTypes.NetworkProtocol.String(),
+	"js":      templateTypes.JavascriptProtocol.String(),
+}
+
+// Variable is a variable exposed to matchers and extractors
+type Variable struct {
+	// Name is the name of the variable
+	Name string `json:"name"`
+	// Description is the description of the variable
+	Description string `json:"description"`
+}
+
+// Protocols returns the sorted names of protocols with known variables
+func Protocols() []string {
+	protocols := make([]string, 0, len(definitions))
+	for protocol := range definitions {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+	return protocols
+}
+
+// List returns the sorted variables exposed to matchers by protocol.
+// Definitions of names generated on runtime (prefixed & suffixed by <>)
+// are placeholders and not listed.
+func List(protocol string) ([]Variable, error) {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if alias, ok := aliases[protocol]; ok {
+		protocol = alias
+	}
+	partDefinitions, ok := definitions[protocol]
+	if !ok {
+		return nil, errorutil.New("unknown protocol %s, supported protocols are: %s", protocol, strings.Join(Protocols(), ","))
+	}
+	var variables []Variable
+	for names, description := range partDefinitions {
+		// multiple definitions are separated by commas
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if isPlaceholder(name) {
+				continue
+			}
+			variables = append(variables, Variable{Name: name, Description: description})
+		}
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, nil
+}
+
+// isPlaceholder returns true if the definition name is not a real
+// variable name but a placeholder of names generated on runtime
+func isPlaceholder(name string) bool {
+	return strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">")
+}
+
+// GetPrintableVariables returns the printable variables of a protocol
+// or of every protocol if protocol is `all`
+func GetPrintableVariables(protocol string, noColor bool) (string, error) {
+	protocols := []string{protocol}
+	if strings.EqualFold(protocol, AllProtocols) {
+		protocols = Protocols()
+	}
+	au := aurora.NewAurora(!noColor)
+
+	var builder strings.Builder
+	for i, name := range protocols {
+		variables, err := List(name)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("%s:\n", au.Bold(au.BrightYellow(name)).String()))
+		for _, variable := range variables {
+			builder.WriteString(fmt.Sprintf("  %s: %s\n", au.Cyan(variable.Name).String(), variable.Description))
+		}
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,33 @@
+package matchervars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	for _, protocol := range Protocols() {
+		variables, err := List(protocol)
+		require.Nil(t, err, "could not list variables of %s", protocol)
+		require.NotEmpty(t, variables, "no variables listed for %s", protocol)
+		for _, variable := range variables {
+			require.False(t, isPlaceholder(variable.Name), "listed placeholder %s of %s", variable.Name, protocol)
+		}
+	}
+
+	variables, err := List("http")
+	require.Nil(t, err, "could not list http variables")
+	names := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		names = append(names, variable.Name)
+	}
+	require.Contains(t, names, "status_code", "could not list status_code")
+	require.Contains(t, names, "all_headers", "could not split comma separated definitions")
+	require.NotContains(t, names, "<header_name>", "could list header name placeholder")
+
+	_, err = List("network")
+	require.Nil(t, err, "could not list variables by alias")
+	_, err = List("unknown")
+	require.NotNil(t, err, "could list variables of unknown protocol")
+}
@@ -80,6 +80,22 @@ type Request struct {
 	// examples:
 	//   - value: false
 	ReadAll bool `yaml:"read-all,omitempty" json:"read-all,omitempty" jsonschema:"title=read all response stream,description=Read all response stream till the server stops sending"`
+	// description: |
+	//   ReadFirst is the number of bytes of unsolicited data (ex: SSH, FTP or SMTP
+	//   banners) to wait for after connecting and before writing any input.
+	//
+	//   The data is available to matchers as `banner`. If no data arrives in time
+	//   the request continues with an empty `banner` and `banner_received` set to false.
+	// examples:
+	//   - value: "1024"
+	ReadFirst int `yaml:"read-first,omitempty" json:"read-first,omitempty" jsonschema:"title=bytes of server data to read first,description=Number of bytes of unsolicited server data to read before writing any input"`
+	// description: |
+	//   ReadFirstTimeout is the number of seconds to wait for unsolicited server data.
+	//
+	//   Default value for read-first-timeout is 5 seconds.
+	// examples:
+	//   - value: "10"
+	ReadFirstTimeout int `yaml:"read-first-timeout,omitempty" json:"read-first-timeout,omitempty" jsonschema:"title=timeout for reading server data first,description=Seconds to wait for unsolicited server data"`
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -103,15 +119,17 @@ type Request struct {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":   "ID of the template executed",
-	"template-info": "Info Block of the template executed",
-	"template-path": "Path of the template executed",
-	"host":          "Host is the input to the template",
-	"matched":       "Matched is the input which was matched upon",
-	"type":          "Type is the type of request made",
-	"request":       "Network request made from the client",
-	"body,all,data": "Network response received from server (default)",
-	"raw":           "Full Network protocol data",
+	"template-id":     "ID of the template executed",
+	"template-info":   "Info Block of the template executed",
+	"template-path":   "Path of the template executed",
+	"host":            "Host is the input to the template",
+	"matched":         "Matched is the input which was matched upon",
+	"type":            "Type is the type of request made",
+	"request":         "Network request made from the client",
+	"body,all,data":   "Network response received from server (default)",
+	"raw":             "Full Network protocol data",
+	"banner":          "Unsolicited data sent by server before any input (requires read-first)",
+	"banner_received": "Whether server sent any data before any input (requires read-first)",
 }
 
 type addressKV struct {
@@ -242,6 +260,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errors.Wrap(err, "could not compile operators")
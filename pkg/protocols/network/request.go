@@ -64,7 +64,7 @@ func (request *Request) getOpenPorts(target *contextargs.Context) ([]string, err
 			errs = append(errs, err)
 			continue
 		}
-		conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", addr)
+		conn, err := protocolstate.Dial(context.TODO(), "tcp", addr)
 		if err != nil {
 			errs = append(errs, err)
 			continue
@@ -165,7 +165,7 @@ func (request *Request) executeOnTarget(input *contextargs.Context, visited maps
 // executeAddress executes the request for an address
 func (request *Request) executeAddress(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, shouldUseTLS bool, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	variables = generators.MergeMaps(variables, map[string]interface{}{"Hostname": address})
-	payloads := generators.BuildPayloadFromOptions(request.options.Options)
+	payloads := request.options.BuildPayloadFromOptions(input.MetaInput)
 
 	if !strings.Contains(actualAddress, ":") {
 		err := errors.New("no port provided in network protocol request")
@@ -175,7 +175,7 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 	}
 
 	// if request threads matches global payload concurrency we follow it
-	shouldFollowGlobal := request.Threads == request.options.Options.PayloadConcurrency
+	shouldFollowGlobal := request.Threads == request.options.PayloadConcurrency()
 
 	if request.generator != nil {
 		iterator := request.generator.NewIterator()
@@ -199,8 +199,8 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 			}
 
 			// resize check point - nop if there are no changes
-			if shouldFollowGlobal && swg.Size != request.options.Options.PayloadConcurrency {
-				if err := swg.Resize(input.Context(), request.options.Options.PayloadConcurrency); err != nil {
+			if shouldFollowGlobal && swg.Size != request.options.PayloadConcurrency() {
+				if err := swg.Resize(input.Context(), request.options.PayloadConcurrency()); err != nil {
 					m.Lock()
 					multiErr = multierr.Append(multiErr, err)
 					m.Unlock()
@@ -241,9 +241,14 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		hostname = host
 	}
 
-	if shouldUseTLS {
+	switch {
+	case protocolstate.HasCustomDialer() && shouldUseTLS:
+		conn, err = protocolstate.DialTLSWithConfig(context.Background(), "tcp", actualAddress, nil)
+	case protocolstate.HasCustomDialer():
+		conn, err = protocolstate.Dial(context.Background(), "tcp", actualAddress)
+	case shouldUseTLS:
 		conn, err = request.dialer.DialTLS(context.Background(), "tcp", actualAddress)
-	} else {
+	default:
 		conn, err = request.dialer.Dial(context.Background(), "tcp", actualAddress)
 	}
 	if err != nil {
@@ -266,6 +271,15 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 
 	inputEvents := make(map[string]interface{})
 
+	if request.ReadFirst > 0 {
+		banner := request.readFirst(conn, actualAddress)
+		responseBuilder.Write(banner)
+		inputEvents["banner"] = string(banner)
+		inputEvents["banner_received"] = len(banner) > 0
+		interimValues["banner"] = string(banner)
+		_ = conn.SetDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
+	}
+
 	for _, input := range request.Inputs {
 		data := []byte(input.Data)
 
@@ -400,6 +414,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			Context:        input.Context(),
 		})
 	}
 	if len(interactshURLs) > 0 {
@@ -411,6 +426,24 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 	return nil
 }
 
+// defaultReadFirstTimeout is the default time to wait for unsolicited server data
+const defaultReadFirstTimeout = 5 * time.Second
+
+// readFirst reads unsolicited data sent by the server before any input is
+// written. No data arriving is not an error and an empty slice is returned.
+func (request *Request) readFirst(conn net.Conn, address string) []byte {
+	timeout := defaultReadFirstTimeout
+	if request.ReadFirstTimeout > 0 {
+		timeout = time.Duration(request.ReadFirstTimeout) * time.Second
+	}
+	data, err := ConnReadNWithTimeout(conn, int64(request.ReadFirst), timeout)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] No data received from %s before writing input: %s\n", request.options.TemplateID, address, err)
+		return nil
+	}
+	return data
+}
+
 func dumpResponse(event *output.InternalWrappedEvent, request *Request, response string, actualAddress, address string) {
 	cliOptions := request.options.Options
 	if cliOptions.Debug || cliOptions.DebugResponse || cliOptions.StoreResponse {
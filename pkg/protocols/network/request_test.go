@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -156,3 +157,55 @@ var exampleBody = `<!doctype html>
 </body>
 </html>
 `
+
+func TestNetworkReadFirst(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-read-first"
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_8.9\r\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	request := &Request{
+		ID:               templateID,
+		Address:          []string{"{{Hostname}}"},
+		ReadFirst:        1024,
+		ReadFirstTimeout: 2,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:  "banner",
+				Part:  "banner",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"SSH-2.0"},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	var finalEvent *output.InternalWrappedEvent
+	ctxArgs := contextargs.NewWithInput(context.Background(), listener.Addr().String())
+	err = request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute network request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, true, finalEvent.InternalEvent["banner_received"], "could not get banner status")
+	require.Equal(t, 1, len(finalEvent.Results), "could not match banner")
+}
@@ -19,7 +19,7 @@ import (
 // Match matches a generic data response again a given matcher
 func (request *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) (bool, []string) {
 	item, ok := getMatchPart(matcher.Part, data)
-	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
+	if !ok && !matcher.IsPartless() {
 		return false, []string{}
 	}
 
@@ -40,6 +40,20 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
+	case matchers.CompareMatcher:
+		return matcher.Result(matcher.MatchCompare(data)), []string{}
+	case matchers.EntropyMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchEntropy(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.MatchJSONSchema(item)
+	case matchers.ErrorPageMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchErrorPage(item))
+	case matchers.RedirectMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchRedirect(item, data))
+	case matchers.GoldenMatcher:
+		return matcher.MatchGolden(item)
+	case matchers.SSRFMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchSSRF(item, data))
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
@@ -71,6 +85,18 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.CookieExtractor:
+		return extractor.ExtractCookie(item)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(item)
+	case extractors.BinaryExtractor:
+		return extractor.ExtractBinary(item)
+	case extractors.JSONSchemaExtractor:
+		return extractor.ExtractJSONSchema(item)
+	case extractors.ErrorPageExtractor:
+		return extractor.ExtractErrorPage(item)
+	case extractors.CSSExtractor:
+		return extractor.ExtractCSS(item)
 	}
 	return nil
 }
@@ -13,6 +13,10 @@ import (
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/authprovider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/bodydiff"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/safemode"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/seenparams"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/tarpit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
 	"github.com/projectdiscovery/nuclei/v3/pkg/loader/parser"
@@ -23,14 +27,30 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/projectfile"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/autotune"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/basepath"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/challenge"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/cookiefile"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostsched"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/inputvars"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/pause"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdedupe"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/requestdump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/responsecache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/severitypolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/sharedlimit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/templateslots"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/variables"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
@@ -84,6 +104,10 @@ type ExecutorOptions struct {
 	// Stop execution once first match is found (Assigned while parsing templates)
 	// Note: this is different from Options.StopAtFirstMatch (Assigned from CLI option)
 	StopAtFirstMatch bool
+	// Proxy overrides the global proxy for http requests (Assigned while parsing templates)
+	Proxy string
+	// TemplateRateLimiter is an optional rate limiter of the template (Assigned while parsing templates)
+	TemplateRateLimiter *ratelimit.Limiter
 	// Variables is a list of variables from template
 	Variables variables.Variable
 	// Constants is a list of constants from template
@@ -117,6 +141,45 @@ type ExecutorOptions struct {
 	//TemporaryDirectory is the directory to store temporary files
 	TemporaryDirectory string
 	Parser             parser.Parser
+	// SafeModeFilter is an optional filter for skipping destructive fuzzing requests
+	SafeModeFilter *safemode.Filter
+	// SeenParams is an optional store of parameters fuzzed in previous runs
+	SeenParams *seenparams.Store
+	// Challenges is an optional handler of anti-automation challenge responses
+	Challenges *challenge.Handler
+	// RequestDedupe is an optional store for skipping duplicate requests across inputs
+	RequestDedupe *requestdedupe.Store
+	// ResponseCache is an optional cache of responses to safe requests shared by templates
+	ResponseCache *responsecache.Cache
+	// RequestDump is an optional dumper printing built requests instead of sending them
+	RequestDump *requestdump.Dumper
+	// AutoTuner is an optional tuner adjusting scan concurrency at runtime
+	AutoTuner *autotune.Tuner
+	// InputVars is an optional store of per-input variables keyed by target
+	InputVars *inputvars.Store
+	// BasePaths is an optional mapping of input hosts to base paths of http requests
+	BasePaths *basepath.Map
+	// CookieFile is an optional cookie jar loaded from a cookie file
+	CookieFile *cookiefile.Jar
+	// BodyDiff is an optional writer saving diffs of matched fuzzing response bodies
+	BodyDiff *bodydiff.Writer
+	// TrustStore is an optional store of certificates templates are verified against
+	TrustStore *signer.TrustStore
+	// SharedLimiter is an optional per-host rate limiter shared between processes
+	SharedLimiter *sharedlimit.Limiter
+	// HostScheduler is an optional scheduler spacing requests to each host
+	HostScheduler *hostsched.Scheduler
+	// ScanValues is an optional store of values extracted by templates shared
+	// with templates executed later on the same input
+	ScanValues *scanvalues.Store
+	// SeverityPolicies are optional rate and concurrency policies of template severities
+	SeverityPolicies *severitypolicy.Policies
+	// TemplateSlots is an optional limiter of in-flight requests per template
+	TemplateSlots *templateslots.Limiter
+	// Pauser is an optional controller pausing request dispatch of the scan
+	Pauser *pause.Controller
+	// Tarpit is an optional detector of hosts deliberately slowing down responses
+	Tarpit *tarpit.Detector
 	// ExportReqURLPattern exports the request URL pattern
 	// in ResultEvent it contains the exact url pattern (ex: {{BaseURL}}/{{randstr}}/xyz) used in the request
 	ExportReqURLPattern bool
@@ -125,12 +188,57 @@ type ExecutorOptions struct {
 // todo: centralizing components is not feasible with current clogged architecture
 // a possible approach could be an internal event bus with pub-subs? This would be less invasive than
 // reworking dep injection from scratch
-func (eo *ExecutorOptions) RateLimitTake() {
-	if eo.RateLimiter.GetLimit() != uint(eo.Options.RateLimit) {
-		eo.RateLimiter.SetLimit(uint(eo.Options.RateLimit))
+//
+// Templates with their own rate limit take from the rate limiter of the template
+// instead of the global rate limiter. Templates also take from the rate limiter
+// of their severity if a severity policy is configured.
+// When a shared limiter is configured, the per-host budget shared with other
+// processes is also consulted for the input. When a host scheduler is configured,
+// requests wait for the next slot of the host of the input before taking from
+// the global rate limiter so that hosts are interleaved. It blocks while the
+// scan is paused.
+func (eo *ExecutorOptions) RateLimitTake(ctx context.Context, input string) {
+	if err := eo.Pauser.Wait(ctx); err != nil {
+		return
+	}
+	if rateLimit := eo.RateLimit(); eo.RateLimiter.GetLimit() != uint(rateLimit) {
+		eo.RateLimiter.SetLimit(uint(rateLimit))
 		eo.RateLimiter.SetDuration(eo.Options.RateLimitDuration)
 	}
-	eo.RateLimiter.Take()
+	eo.SeverityPolicies.Take(eo.TemplateInfo.SeverityHolder.Severity)
+	eo.HostScheduler.Take(ctx, input)
+	if eo.TemplateRateLimiter != nil {
+		eo.TemplateRateLimiter.Take()
+	} else {
+		eo.RateLimiter.Take()
+	}
+	if eo.SharedLimiter != nil {
+		eo.SharedLimiter.Take(ctx, input)
+	}
+}
+
+// AcquireTemplateSlot waits for an in-flight request slot of the template if
+// template concurrency is limited returning the function releasing it. False
+// is returned if the context is done.
+func (eo *ExecutorOptions) AcquireTemplateSlot(ctx context.Context) (func(), bool) {
+	return eo.TemplateSlots.Acquire(ctx, eo.TemplateID)
+}
+
+// BuildPayloadFromOptions returns the variables passed using cli options merged
+// with per-input variables of input (if any) which take precedence over them
+// and with values extracted from input by previously executed templates (if any)
+func (e *ExecutorOptions) BuildPayloadFromOptions(input *contextargs.MetaInput) map[string]interface{} {
+	optionVars := generators.BuildPayloadFromOptions(e.Options)
+	if input == nil {
+		return optionVars
+	}
+	if vars := e.InputVars.Get(input.Input); len(vars) > 0 {
+		optionVars = generators.MergeMaps(optionVars, vars)
+	}
+	if values := e.ScanValues.Get(input.Input); len(values) > 0 {
+		optionVars = generators.MergeMaps(optionVars, values)
+	}
+	return optionVars
 }
 
 // GetThreadsForPayloadRequests returns the number of threads to use as default for
@@ -140,9 +248,27 @@ func (e *ExecutorOptions) GetThreadsForNPayloadRequests(totalRequests int, curre
 		return currentThreads
 	}
 
+	return e.PayloadConcurrency()
+}
+
+// PayloadConcurrency returns the payload concurrency of the scan (as
+// adjusted at runtime if auto-tuning is enabled)
+func (e *ExecutorOptions) PayloadConcurrency() int {
+	if e.AutoTuner != nil {
+		return e.AutoTuner.Values().PayloadConcurrency
+	}
 	return e.Options.PayloadConcurrency
 }
 
+// RateLimit returns the rate limit of the scan (as adjusted at runtime if
+// auto-tuning is enabled)
+func (e *ExecutorOptions) RateLimit() int {
+	if e.AutoTuner != nil {
+		return e.AutoTuner.Values().RateLimit
+	}
+	return e.Options.RateLimit
+}
+
 // CreateTemplateCtxStore creates template context store (which contains templateCtx for every scan)
 func (e *ExecutorOptions) CreateTemplateCtxStore() {
 	e.templateCtxStore = &mapsutil.SyncLockMap[string, *contextargs.Context]{
@@ -280,6 +406,7 @@ func MakeDefaultResultEvent(request Request, wrapped *output.InternalWrappedEven
 		for matcherNames := range wrapped.OperatorsResult.Matches {
 			data := request.MakeResultEventItem(wrapped)
 			data.MatcherName = matcherNames
+			data.Confidence = wrapped.OperatorsResult.Confidence
 			results = append(results, data)
 		}
 	} else if len(wrapped.OperatorsResult.Extracts) > 0 {
@@ -287,10 +414,12 @@ func MakeDefaultResultEvent(request Request, wrapped *output.InternalWrappedEven
 			data := request.MakeResultEventItem(wrapped)
 			data.ExtractorName = k
 			data.ExtractedResults = v
+			data.Confidence = wrapped.OperatorsResult.Confidence
 			results = append(results, data)
 		}
 	} else {
 		data := request.MakeResultEventItem(wrapped)
+		data.Confidence = wrapped.OperatorsResult.Confidence
 		results = append(results, data)
 	}
 	return results
@@ -320,6 +449,18 @@ func MakeDefaultExtractFunc(data map[string]interface{}, extractor *extractors.E
 		return extractor.ExtractXPath(itemStr)
 	case extractors.DSLExtractor:
 		return extractor.ExtractDSL(data)
+	case extractors.CookieExtractor:
+		return extractor.ExtractCookie(itemStr)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(itemStr)
+	case extractors.BinaryExtractor:
+		return extractor.ExtractBinary(itemStr)
+	case extractors.JSONSchemaExtractor:
+		return extractor.ExtractJSONSchema(itemStr)
+	case extractors.ErrorPageExtractor:
+		return extractor.ExtractErrorPage(itemStr)
+	case extractors.CSSExtractor:
+		return extractor.ExtractCSS(itemStr)
 	}
 	return nil
 }
@@ -332,7 +473,7 @@ func MakeDefaultMatchFunc(data map[string]interface{}, matcher *matchers.Matcher
 	}
 
 	partItem, ok := data[part]
-	if !ok && matcher.Type.MatcherType != matchers.DSLMatcher {
+	if !ok && !matcher.IsPartless() {
 		return false, nil
 	}
 	item := types.ToString(partItem)
@@ -349,6 +490,18 @@ func MakeDefaultMatchFunc(data map[string]interface{}, matcher *matchers.Matcher
 		return matcher.ResultWithMatchedSnippet(matcher.MatchBinary(item))
 	case matchers.DSLMatcher:
 		return matcher.Result(matcher.MatchDSL(data)), nil
+	case matchers.CompareMatcher:
+		return matcher.Result(matcher.MatchCompare(data)), nil
+	case matchers.EntropyMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchEntropy(item))
+	case matchers.JSONSchemaMatcher:
+		return matcher.MatchJSONSchema(item)
+	case matchers.ErrorPageMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchErrorPage(item))
+	case matchers.RedirectMatcher:
+		return matcher.ResultWithMatchedSnippet(matcher.MatchRedirect(item, data))
+	case matchers.GoldenMatcher:
+		return matcher.MatchGolden(item)
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
 	}
@@ -0,0 +1,207 @@
+package ssl
+
+import (
+	"context"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Mzack9999/gcache"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+)
+
+// ctLogAPI is the certificate transparency search api queried by the
+// serial number of certificates. The api indexes both precertificates and
+// certificates so certificates are found even if only their precertificate
+// was logged (which has a different fingerprint).
+var ctLogAPI = "https://crt.sh/?serial=%s&output=json"
+
+// ctEntryTimestampLayout is the layout of entry timestamps of the ct api
+const ctEntryTimestampLayout = "2006-01-02T15:04:05.999999999"
+
+// ctLookupTimeout is the maximum duration of a ct log lookup
+const ctLookupTimeout = 15 * time.Second
+
+// ctFailureTTL is the duration failed lookups are cached for so that an
+// unavailable api is not queried again for every host
+const ctFailureTTL = 5 * time.Minute
+
+// ctLookupCacheSize is the maximum number of cached ct log lookups
+const ctLookupCacheSize = 10000
+
+// sctListOID is the oid of the x509 extension of embedded signed certificate
+// timestamps (rfc 6962)
+var sctListOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// ctLookupResult is the result of a ct log lookup of a certificate
+type ctLookupResult struct {
+	logged    bool
+	firstSeen string
+	// failed is true for failed lookups (cached for ctFailureTTL)
+	failed bool
+}
+
+var (
+	// ctLookupCache caches the ct log lookups by certificate fingerprint
+	ctLookupCache = gcache.New[string, *ctLookupResult](ctLookupCacheSize).LRU().Build()
+	// ctLookupGroup deduplicates concurrent lookups of the same certificate
+	ctLookupGroup singleflight.Group
+)
+
+// ctLogEntry is an entry of the ct api response
+type ctLogEntry struct {
+	IssuerName     string `json:"issuer_name"`
+	EntryTimestamp string `json:"entry_timestamp"`
+}
+
+// lookupCTLog adds ct_logged and ct_first_seen for the leaf certificate
+// of the response to data. Signed certificate timestamps embedded in the
+// certificate are used if present, the ct api is queried otherwise.
+// Nothing is added if the lookup fails.
+func (request *Request) lookupCTLog(response *clients.Response, data map[string]interface{}) {
+	cert := response.CertificateResponse
+	if cert == nil || cert.FingerprintHash.SHA256 == "" {
+		return
+	}
+	fingerprint := strings.ToLower(cert.FingerprintHash.SHA256)
+
+	result, err := ctLookupCache.Get(fingerprint)
+	if err != nil {
+		value, _, _ := ctLookupGroup.Do(fingerprint, func() (interface{}, error) {
+			return request.lookupCertificate(cert, fingerprint), nil
+		})
+		result = value.(*ctLookupResult)
+	}
+	if result.failed {
+		return
+	}
+
+	data["ct_logged"] = result.logged
+	if result.firstSeen != "" {
+		data["ct_first_seen"] = result.firstSeen
+	}
+}
+
+// lookupCertificate looks up the certificate in ct logs and caches the result
+func (request *Request) lookupCertificate(cert *clients.CertificateResponse, fingerprint string) *ctLookupResult {
+	result := embeddedSCTs(cert.Certificate)
+	if result == nil {
+		var err error
+		if result, err = request.queryCTLog(cert); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not lookup certificate %s in ct logs: %s", request.options.TemplateID, fingerprint, err)
+			result = &ctLookupResult{failed: true}
+			_ = ctLookupCache.SetWithExpire(fingerprint, result, ctFailureTTL)
+			return result
+		}
+	}
+	_ = ctLookupCache.Set(fingerprint, result)
+	return result
+}
+
+// embeddedSCTs returns the lookup result of the signed certificate timestamps
+// embedded in the pem encoded certificate or nil if it has none
+func embeddedSCTs(certificate string) *ctLookupResult {
+	if certificate == "" {
+		return nil
+	}
+	cert, err := parsePEMCertificate(certificate)
+	if err != nil {
+		return nil
+	}
+	for _, extension := range cert.Extensions {
+		if !extension.Id.Equal(sctListOID) {
+			continue
+		}
+		timestamps := parseSCTTimestamps(extension.Value)
+		if len(timestamps) == 0 {
+			return nil
+		}
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+		return &ctLookupResult{logged: true, firstSeen: timestamps[0].Format(time.RFC3339)}
+	}
+	return nil
+}
+
+// parseSCTTimestamps returns the timestamps of the v1 signed certificate
+// timestamps of the value of a sct list extension
+func parseSCTTimestamps(value []byte) []time.Time {
+	var list []byte
+	if _, err := asn1.Unmarshal(value, &list); err != nil || len(list) < 2 {
+		return nil
+	}
+	list = list[2:]
+	var timestamps []time.Time
+	for len(list) >= 2 {
+		length := int(binary.BigEndian.Uint16(list))
+		if len(list) < 2+length {
+			break
+		}
+		// version (1 byte), log id (32 bytes) and timestamp in milliseconds (8 bytes)
+		if sct := list[2 : 2+length]; len(sct) >= 41 && sct[0] == 0 {
+			timestamps = append(timestamps, time.UnixMilli(int64(binary.BigEndian.Uint64(sct[33:41]))).UTC())
+		}
+		list = list[2+length:]
+	}
+	return timestamps
+}
+
+// queryCTLog queries the ct api for the entries of the serial number of the
+// certificate issued by its issuer (serial numbers are unique per issuer)
+func (request *Request) queryCTLog(cert *clients.CertificateResponse) (*ctLookupResult, error) {
+	serial := strings.ToLower(strings.ReplaceAll(cert.Serial, ":", ""))
+	if serial == "" {
+		return nil, errors.New("certificate has no serial number")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ctLookupTimeout)
+	defer cancel()
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(ctLogAPI, serial), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := request.ctClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ctLogEntry
+	if err := jsoniter.Unmarshal(body, &entries); err != nil {
+		return nil, errors.Wrap(err, "could not decode ct api response")
+	}
+	result := &ctLookupResult{}
+	var timestamps []time.Time
+	for _, entry := range entries {
+		if cert.IssuerCN != "" && !strings.Contains(entry.IssuerName, "CN="+cert.IssuerCN) {
+			continue
+		}
+		result.logged = true
+		if timestamp, err := time.Parse(ctEntryTimestampLayout, entry.EntryTimestamp); err == nil {
+			timestamps = append(timestamps, timestamp.UTC())
+		}
+	}
+	if len(timestamps) > 0 {
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+		result.firstSeen = timestamps[0].Format(time.RFC3339)
+	}
+	return result, nil
+}
@@ -0,0 +1,260 @@
+package ssl
+
+import (
+	"context"
+	"net"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/tlsfingerprint"
+)
+
+// probedALPNProtocols are the alpn protocols probed for support
+var probedALPNProtocols = []string{"h2", "http/1.1", "http/1.0", "spdy/3.1"}
+
+// probedGroups are the supported groups probed for support mapped to their names
+var probedGroups = []struct {
+	id   utls.CurveID
+	name string
+}{
+	{utls.X25519, "x25519"},
+	{utls.CurveP256, "secp256r1"},
+	{utls.CurveP384, "secp384r1"},
+	{utls.CurveP521, "secp521r1"},
+}
+
+// probedSignatureAlgorithms are the signature algorithms probed for support
+// mapped to their names
+var probedSignatureAlgorithms = []struct {
+	id   utls.SignatureScheme
+	name string
+}{
+	{utls.ECDSAWithP256AndSHA256, "ecdsa_secp256r1_sha256"},
+	{utls.ECDSAWithP384AndSHA384, "ecdsa_secp384r1_sha384"},
+	{utls.ECDSAWithP521AndSHA512, "ecdsa_secp521r1_sha512"},
+	{utls.Ed25519, "ed25519"},
+	{utls.PSSWithSHA256, "rsa_pss_rsae_sha256"},
+	{utls.PSSWithSHA384, "rsa_pss_rsae_sha384"},
+	{utls.PSSWithSHA512, "rsa_pss_rsae_sha512"},
+	{utls.PKCS1WithSHA256, "rsa_pkcs1_sha256"},
+	{utls.PKCS1WithSHA384, "rsa_pkcs1_sha384"},
+	{utls.PKCS1WithSHA512, "rsa_pkcs1_sha512"},
+	{utls.PKCS1WithSHA1, "rsa_pkcs1_sha1"},
+	{utls.ECDSAWithSHA1, "ecdsa_sha1"},
+}
+
+// tls12CipherSuites are the cipher suites of ecdhe key exchanges of tls 1.2
+var tls12CipherSuites = []uint16{
+	utls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	utls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	utls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	utls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	utls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	utls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+	utls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	utls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	utls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	utls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+}
+
+// ecdheCipherSuites are the cipher suites of tls 1.3 and ecdhe key exchanges
+// of tls 1.2. Groups and signature algorithms are only probed with them as
+// servers negotiating rsa key exchanges ignore both.
+var ecdheCipherSuites = append([]uint16{
+	utls.TLS_AES_128_GCM_SHA256,
+	utls.TLS_AES_256_GCM_SHA384,
+	utls.TLS_CHACHA20_POLY1305_SHA256,
+}, tls12CipherSuites...)
+
+// allCipherSuites are the cipher suites offered by handshakes not probing
+// groups or signature algorithms
+var allCipherSuites = append(append([]uint16{}, ecdheCipherSuites...),
+	utls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	utls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	utls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	utls.TLS_RSA_WITH_AES_256_CBC_SHA,
+)
+
+// extensionsProbe is the client hello of a handshake probing extensions
+type extensionsProbe struct {
+	alpn       []string
+	groups     []utls.CurveID
+	signatures []utls.SignatureScheme
+	ciphers    []uint16
+	// tls12 restricts the handshake to tls 1.2 and older versions
+	tls12 bool
+}
+
+// enumerateExtensions adds the extensions supported by the server at address
+// to data as alpn_protocols, supported_groups, signature_algorithms and
+// tls_extensions.
+//
+// A handshake offering all probed values is performed first, then one
+// handshake per probed alpn protocol, group and signature algorithm offering
+// only it. Signature algorithms are probed with tls 1.2 first as tls 1.3
+// does not allow some of them (ex: rsa_pkcs1_sha256) in handshakes, and
+// with tls 1.3 for servers not supporting tls 1.2. If the first handshake
+// fails, lists are empty and the error is added as tls_extensions_error.
+func (request *Request) enumerateExtensions(address, serverName string, data map[string]interface{}) {
+	data["alpn_protocols"] = []string{}
+	data["supported_groups"] = []string{}
+	data["signature_algorithms"] = []string{}
+	data["tls_extensions"] = []string{}
+	data["tls_extensions_error"] = ""
+
+	groups := make([]utls.CurveID, 0, len(probedGroups))
+	for _, group := range probedGroups {
+		groups = append(groups, group.id)
+	}
+	signatures := make([]utls.SignatureScheme, 0, len(probedSignatureAlgorithms))
+	for _, signature := range probedSignatureAlgorithms {
+		signatures = append(signatures, signature.id)
+	}
+
+	conn, err := request.probeExtensions(address, serverName, &extensionsProbe{
+		alpn:       probedALPNProtocols,
+		groups:     groups,
+		signatures: signatures,
+		ciphers:    allCipherSuites,
+	})
+	if err != nil {
+		data["tls_extensions_error"] = err.Error()
+		return
+	}
+	if names := negotiatedExtensions(conn); names != nil {
+		data["tls_extensions"] = names
+	}
+	_ = conn.Close()
+
+	var alpnProtocols, supportedGroups, signatureAlgorithms []string
+	for _, protocol := range probedALPNProtocols {
+		conn, err := request.probeExtensions(address, serverName, &extensionsProbe{alpn: []string{protocol}, groups: groups, signatures: signatures, ciphers: allCipherSuites})
+		if err != nil {
+			continue
+		}
+		if conn.ConnectionState().NegotiatedProtocol == protocol {
+			alpnProtocols = append(alpnProtocols, protocol)
+		}
+		_ = conn.Close()
+	}
+	for _, group := range probedGroups {
+		conn, err := request.probeExtensions(address, serverName, &extensionsProbe{groups: []utls.CurveID{group.id}, signatures: signatures, ciphers: ecdheCipherSuites})
+		if err != nil {
+			continue
+		}
+		supportedGroups = append(supportedGroups, group.name)
+		_ = conn.Close()
+	}
+	for _, signature := range probedSignatureAlgorithms {
+		conn, err := request.probeExtensions(address, serverName, &extensionsProbe{groups: groups, signatures: []utls.SignatureScheme{signature.id}, ciphers: tls12CipherSuites, tls12: true})
+		if err != nil {
+			conn, err = request.probeExtensions(address, serverName, &extensionsProbe{groups: groups, signatures: []utls.SignatureScheme{signature.id}, ciphers: ecdheCipherSuites})
+		}
+		if err != nil {
+			continue
+		}
+		signatureAlgorithms = append(signatureAlgorithms, signature.name)
+		_ = conn.Close()
+	}
+	if alpnProtocols != nil {
+		data["alpn_protocols"] = alpnProtocols
+	}
+	if supportedGroups != nil {
+		data["supported_groups"] = supportedGroups
+	}
+	if signatureAlgorithms != nil {
+		data["signature_algorithms"] = signatureAlgorithms
+	}
+}
+
+// probeExtensions performs a handshake with the server at address sending
+// the client hello of the probe
+func (request *Request) probeExtensions(address, serverName string, probe *extensionsProbe) (*utls.UConn, error) {
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rawConn, err := request.dialer.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(serverName) != nil {
+		serverName = ""
+	}
+	conn := utls.UClient(rawConn, &utls.Config{InsecureSkipVerify: true, ServerName: serverName}, utls.HelloCustom)
+	if err := conn.ApplyPreset(probe.spec()); err != nil {
+		_ = rawConn.Close()
+		return nil, err
+	}
+	if err := conn.HandshakeContext(ctx); err != nil {
+		_ = rawConn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// spec returns the client hello spec of the probe. Extensions whose support
+// is only reported by servers echoing them (ex: heartbeat) are offered too.
+func (probe *extensionsProbe) spec() *utls.ClientHelloSpec {
+	versions := []uint16{utls.VersionTLS13, utls.VersionTLS12, utls.VersionTLS11, utls.VersionTLS10}
+	maxVersion := uint16(utls.VersionTLS13)
+	if probe.tls12 {
+		versions = versions[1:]
+		maxVersion = utls.VersionTLS12
+	}
+	extensions := []utls.TLSExtension{
+		&utls.SNIExtension{},
+		&utls.StatusRequestExtension{},
+		&utls.SupportedCurvesExtension{Curves: probe.groups},
+		&utls.SupportedPointsExtension{SupportedPoints: []uint8{0}},
+		&utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: probe.signatures},
+		&utls.GenericExtension{Id: 15, Data: []byte{1}},
+		&utls.SCTExtension{},
+		&utls.GenericExtension{Id: 22},
+		&utls.ExtendedMasterSecretExtension{},
+		&utls.SessionTicketExtension{},
+		&utls.SupportedVersionsExtension{Versions: versions},
+		&utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateOnceAsClient},
+	}
+	if !probe.tls12 {
+		extensions = append(extensions,
+			&utls.PSKKeyExchangeModesExtension{Modes: []uint8{utls.PskModeDHE}},
+			&utls.KeyShareExtension{KeyShares: []utls.KeyShare{{Group: probe.groups[0]}}},
+		)
+	}
+	if len(probe.alpn) > 0 {
+		extensions = append(extensions, &utls.ALPNExtension{AlpnProtocols: probe.alpn})
+	}
+	return &utls.ClientHelloSpec{
+		TLSVersMin:         utls.VersionTLS10,
+		TLSVersMax:         maxVersion,
+		CipherSuites:       probe.ciphers,
+		CompressionMethods: []uint8{0},
+		Extensions:         extensions,
+	}
+}
+
+// negotiatedExtensions returns the names of the extensions of the server
+// hello of the connection and of the extensions negotiated in encrypted
+// extensions of tls 1.3 (alpn, ocsp stapling and sct)
+func negotiatedExtensions(conn *utls.UConn) []string {
+	var names []string
+	if serverHello := conn.HandshakeState.ServerHello; serverHello != nil {
+		names, _ = tlsfingerprint.ServerExtensions(serverHello.Raw)
+	}
+	state := conn.ConnectionState()
+	if state.Version != utls.VersionTLS13 {
+		return names
+	}
+	if state.NegotiatedProtocol != "" {
+		names = append(names, tlsfingerprint.ExtensionName(16))
+	}
+	if len(state.OCSPResponse) > 0 {
+		names = append(names, tlsfingerprint.ExtensionName(5))
+	}
+	if len(state.SignedCertificateTimestamps) > 0 {
+		names = append(names, tlsfingerprint.ExtensionName(18))
+	}
+	return names
+}
@@ -1,6 +1,7 @@
 package ssl
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
 	"time"
@@ -23,10 +24,12 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
 	"github.com/projectdiscovery/tlsx/pkg/tlsx/openssl"
@@ -92,11 +95,43 @@ type Request struct {
 	//   - "secure"
 	//   - "all"
 	TLSCipherTypes []string `yaml:"tls_cipher_types,omitempty" json:"tls_cipher_types,omitempty" jsonschema:"title=TLS Cipher Types,description=TLS Cipher Types to enumerate,enum=weak,enum=secure,enum=insecure,enum=all"`
+	// description: |
+	//   TLS Extensions Enum - false if not specified
+	//   Enumerates the alpn protocols, supported groups and signature algorithms
+	//   supported by the server exposing alpn_protocols, supported_groups,
+	//   signature_algorithms and tls_extensions (negotiated extensions).
+	//   tls_extensions_error is set if the handshake fails before extensions are negotiated.
+	TLSExtensionsEnum bool `yaml:"tls_extensions_enum,omitempty" json:"tls_extensions_enum,omitempty" jsonschema:"title=Enumerate Extensions,description=Enumerate supported TLS extensions - false if not specified"`
+	// description: |
+	//   Verify Chain - false if not specified
+	//   Verifies the presented certificate chain against the trust store
+	//   (or system roots) exposing chain_trusted and chain_error.
+	VerifyChain bool `yaml:"verify_chain,omitempty" json:"verify_chain,omitempty" jsonschema:"title=Verify Chain,description=Verify the presented certificate chain - false if not specified"`
+	// description: |
+	//   Trust Store is the pem encoded ca bundle the chain is verified against.
+	//   System roots are used if not specified.
+	// examples:
+	//   - value: "\"corporate-ca.pem\""
+	TrustStore string `yaml:"trust_store,omitempty" json:"trust_store,omitempty" jsonschema:"title=Trust Store,description=PEM encoded CA bundle the chain is verified against - system roots if not specified"`
+	// description: |
+	//   Verify Hostname - false if not specified
+	//   Verifies the leaf certificate is valid for the hostname exposing
+	//   hostname_verified and hostname_error. Requires verify_chain.
+	VerifyHostname bool `yaml:"verify_hostname,omitempty" json:"verify_hostname,omitempty" jsonschema:"title=Verify Hostname,description=Verify the leaf certificate is valid for the hostname - false if not specified"`
+	// description: |
+	//   CT Lookup - false if not specified
+	//   Looks up the leaf certificate in certificate transparency logs
+	//   exposing ct_logged and ct_first_seen. Signed certificate timestamps
+	//   embedded in the certificate are used if present, crt.sh is queried by
+	//   serial number and issuer otherwise. Both are absent if the lookup fails.
+	CTLookup bool `yaml:"ct_lookup,omitempty" json:"ct_lookup,omitempty" jsonschema:"title=CT Lookup,description=Lookup the leaf certificate in certificate transparency logs - false if not specified"`
 
 	// cache any variables that may be needed for operation.
-	dialer  *fastdialer.Dialer
-	tlsx    *tlsx.Service
-	options *protocols.ExecutorOptions
+	dialer   *fastdialer.Dialer
+	tlsx     *tlsx.Service
+	roots    *x509.CertPool
+	ctClient *retryablehttp.Client
+	options  *protocols.ExecutorOptions
 }
 
 // CanCluster returns true if the request can be clustered.
@@ -104,6 +139,9 @@ func (request *Request) CanCluster(other *Request) bool {
 	if len(request.CipherSuites) > 0 || request.MinVersion != "" || request.MaxVersion != "" {
 		return false
 	}
+	if request.VerifyChain != other.VerifyChain || request.TrustStore != other.TrustStore || request.VerifyHostname != other.VerifyHostname || request.CTLookup != other.CTLookup || request.TLSExtensionsEnum != other.TLSExtensionsEnum {
+		return false
+	}
 	if request.Address != other.Address || request.ScanMode != other.ScanMode {
 		return false
 	}
@@ -139,6 +177,26 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		// by default only look for insecure ciphers
 		request.TLSCipherTypes = []string{"insecure"}
 	}
+	if request.VerifyHostname && !request.VerifyChain {
+		return errorutil.NewWithTag(request.TemplateID, "verify_hostname requires verify_chain")
+	}
+	if request.TrustStore != "" {
+		if !request.VerifyChain {
+			return errorutil.NewWithTag(request.TemplateID, "trust_store requires verify_chain")
+		}
+		roots, err := request.loadTrustStore()
+		if err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "could not load trust store").Wrap(err)
+		}
+		request.roots = roots
+	}
+	if request.CTLookup {
+		ctClient, err := httpclientpool.Get(options.Options, &httpclientpool.Configuration{})
+		if err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "could not get ct lookup client").Wrap(err)
+		}
+		request.ctClient = ctClient
+	}
 
 	tlsxOptions := &clients.Options{
 		AllCiphers:        true,
@@ -160,6 +218,8 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		TlsVersionsEnum:   request.TLSVersionsEnum,
 		TlsCiphersEnum:    request.TLSCiphersEnum,
 		TLsCipherLevel:    request.TLSCipherTypes,
+		TLSChain:          request.VerifyChain,
+		Cert:              request.VerifyChain || request.CTLookup,
 	}
 
 	tlsxService, err := tlsx.New(tlsxOptions)
@@ -171,6 +231,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errorutil.NewWithTag(request.TemplateID, "could not compile operators got %v", err)
@@ -204,7 +265,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	hostname, port, _ := net.SplitHostPort(hostPort)
 
 	requestOptions := request.options
-	payloadValues := generators.BuildPayloadFromOptions(request.options.Options)
+	payloadValues := request.options.BuildPayloadFromOptions(input.MetaInput)
 	for k, v := range dynamicValues {
 		payloadValues[k] = v
 	}
@@ -326,6 +387,31 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		data[tag] = f.Value()
 	}
 
+	if request.VerifyChain {
+		request.verifyResponse(response, host, data)
+		for _, key := range []string{"chain_trusted", "chain_error", "hostname_verified", "hostname_error"} {
+			if value, ok := data[key]; ok {
+				request.options.AddTemplateVar(input.MetaInput, request.Type(), request.ID, key, value)
+			}
+		}
+	}
+
+	if request.CTLookup {
+		request.lookupCTLog(response, data)
+		for _, key := range []string{"ct_logged", "ct_first_seen"} {
+			if value, ok := data[key]; ok {
+				request.options.AddTemplateVar(input.MetaInput, request.Type(), request.ID, key, value)
+			}
+		}
+	}
+
+	if request.TLSExtensionsEnum {
+		request.enumerateExtensions(net.JoinHostPort(hostIp, port), host, data)
+		for _, key := range []string{"alpn_protocols", "supported_groups", "signature_algorithms", "tls_extensions", "tls_extensions_error"} {
+			request.options.AddTemplateVar(input.MetaInput, request.Type(), request.ID, key, data[key])
+		}
+	}
+
 	// add response fields ^ to template context and merge templatectx variables to output event
 	if request.options.HasTemplateCtx(input.MetaInput) {
 		data = generators.MergeMaps(data, request.options.GetTemplateCtx(input.MetaInput).GetAll())
@@ -354,6 +440,19 @@ var RequestPartDefinitions = map[string]string{
 	"not_after": "Timestamp after which the remote cert expires",
 	"host":      "Host is the input to the template",
 	"matched":   "Matched is the input which was matched upon",
+
+	"chain_trusted":     "Chain Trusted is true if the presented chain is trusted (verify_chain)",
+	"chain_error":       "Chain Error is the chain verification error if untrusted (verify_chain)",
+	"hostname_verified": "Hostname Verified is true if the leaf certificate is valid for the hostname (verify_hostname)",
+	"hostname_error":    "Hostname Error is the hostname verification error (verify_hostname)",
+	"ct_logged":         "CT Logged is true if the leaf certificate is present in certificate transparency logs (ct_lookup)",
+	"ct_first_seen":     "CT First Seen is the earliest certificate transparency log entry timestamp of the leaf certificate (ct_lookup)",
+
+	"alpn_protocols":       "ALPN Protocols are the alpn protocols supported by the server (tls_extensions_enum)",
+	"supported_groups":     "Supported Groups are the key exchange groups supported by the server (tls_extensions_enum)",
+	"signature_algorithms": "Signature Algorithms are the signature algorithms supported by the server (tls_extensions_enum)",
+	"tls_extensions":       "TLS Extensions are the extensions negotiated by the server (tls_extensions_enum)",
+	"tls_extensions_error": "TLS Extensions Error is the handshake error if extensions could not be negotiated (tls_extensions_enum)",
 }
 
 // getAddress returns the address of the host to make request to
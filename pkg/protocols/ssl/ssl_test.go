@@ -2,7 +2,22 @@ package ssl
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -10,7 +25,10 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
 )
 
 func TestSSLProtocol(t *testing.T) {
@@ -41,3 +59,202 @@ func TestGetAddress(t *testing.T) {
 	address, _ := getAddress("https://scanme.sh")
 	require.Equal(t, "scanme.sh:443", address, "could not get correct address")
 }
+
+func TestVerifyResponse(t *testing.T) {
+	newCert := func(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.Nil(t, err, "could not generate key")
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+		require.Nil(t, err, "could not create certificate")
+		cert, err := x509.ParseCertificate(raw)
+		require.Nil(t, err, "could not parse certificate")
+		return cert, key
+	}
+	ca, caKey := newCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nuclei test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	leaf, _ := newCert(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "app.example.com"},
+		DNSNames:     []string{"app.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+
+	response := &clients.Response{
+		CertificateResponse: &clients.CertificateResponse{Certificate: clients.PemEncode(leaf.Raw)},
+		Chain:               []*clients.CertificateResponse{{Certificate: clients.PemEncode(leaf.Raw)}},
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	request := &Request{VerifyChain: true, VerifyHostname: true, roots: roots}
+	data := make(map[string]interface{})
+	request.verifyResponse(response, "app.example.com", data)
+	require.Equal(t, true, data["chain_trusted"], "could not verify chain trusted by trust store")
+	require.Equal(t, "", data["chain_error"], "got chain error for trusted chain")
+	require.Equal(t, true, data["hostname_verified"], "could not verify hostname")
+
+	data = make(map[string]interface{})
+	request.verifyResponse(response, "other.example.com", data)
+	require.Equal(t, false, data["hostname_verified"], "verified mismatched hostname")
+	require.NotEmpty(t, data["hostname_error"], "could not get hostname error")
+
+	request = &Request{VerifyChain: true, roots: x509.NewCertPool()}
+	data = make(map[string]interface{})
+	request.verifyResponse(response, "app.example.com", data)
+	require.Equal(t, false, data["chain_trusted"], "trusted chain of unknown authority")
+	require.Contains(t, data["chain_error"], "unknown authority", "could not get chain verification error")
+	require.NotContains(t, data, "hostname_verified", "verified hostname without verify_hostname")
+}
+
+func TestLookupCTLog(t *testing.T) {
+	ctLookupCache.Purge()
+	var lookups atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		switch r.URL.Query().Get("serial") {
+		case "0ee5":
+			// slow response so that concurrent lookups are in flight together
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte(`[]`))
+		case "0aa1":
+			_, _ = w.Write([]byte(`[{"issuer_name":"C=US, O=Let's Encrypt, CN=R3","entry_timestamp":"2024-03-02T10:00:00.123"},{"issuer_name":"C=US, O=Let's Encrypt, CN=R3","entry_timestamp":"2024-03-01T09:30:00.5"},{"issuer_name":"CN=Other CA","entry_timestamp":"2020-01-01T00:00:00"}]`))
+		case "0bb2":
+			_, _ = w.Write([]byte(`[{"issuer_name":"CN=Other CA","entry_timestamp":"2020-01-01T00:00:00"}]`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	defaultAPI := ctLogAPI
+	ctLogAPI = ts.URL + "/?serial=%s&output=json"
+	defer func() { ctLogAPI = defaultAPI }()
+
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	request := &Request{
+		CTLookup: true,
+		options:  testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{ID: "testing-ct"}),
+		ctClient: retryablehttp.NewClient(retryablehttp.Options{RetryMax: 0, HttpClient: ts.Client()}),
+	}
+	lookup := func(cert *clients.CertificateResponse) map[string]interface{} {
+		data := make(map[string]interface{})
+		request.lookupCTLog(&clients.Response{CertificateResponse: cert}, data)
+		return data
+	}
+	newCert := func(fingerprint, serial string) *clients.CertificateResponse {
+		return &clients.CertificateResponse{
+			Serial:          serial,
+			IssuerCN:        "R3",
+			FingerprintHash: clients.CertificateResponseFingerprintHash{SHA256: fingerprint},
+		}
+	}
+
+	data := lookup(newCert("AA11", "0A:A1"))
+	require.Equal(t, true, data["ct_logged"], "could not get logged certificate")
+	require.Equal(t, "2024-03-01T09:30:00Z", data["ct_first_seen"], "could not get earliest log timestamp of issuer")
+	_ = lookup(newCert("aa11", "0A:A1"))
+	require.Equal(t, int32(1), lookups.Load(), "could not cache lookup by fingerprint")
+
+	data = lookup(newCert("bb22", "0B:B2"))
+	require.Equal(t, false, data["ct_logged"], "got certificate of other issuer as logged")
+	require.NotContains(t, data, "ct_first_seen", "got timestamp for unlogged certificate")
+
+	data = lookup(newCert("cc33", "0C:C3"))
+	require.Empty(t, data, "got ct variables for failed lookup")
+	_ = lookup(newCert("cc33", "0C:C3"))
+	require.Equal(t, int32(3), lookups.Load(), "could not cache failed lookup")
+
+	// embedded timestamps are used without querying the api
+	sctList := []byte{0, 0}
+	for _, millis := range []uint64{1709290000000, 1709280000000} {
+		sct := make([]byte, 41+4)
+		binary.BigEndian.PutUint64(sct[33:41], millis)
+		sctList = binary.BigEndian.AppendUint16(sctList, uint16(len(sct)))
+		sctList = append(sctList, sct...)
+	}
+	binary.BigEndian.PutUint16(sctList, uint16(len(sctList)-2))
+	value, err := asn1.Marshal(sctList)
+	require.Nil(t, err, "could not marshal sct list")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, "could not generate key")
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(3565),
+		Subject:         pkix.Name{CommonName: "nuclei test"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{{Id: sctListOID, Value: value}},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err, "could not create certificate")
+	cert := newCert("dd44", "0D:ED")
+	cert.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}))
+	data = lookup(cert)
+	require.Equal(t, true, data["ct_logged"], "could not get certificate with embedded timestamps as logged")
+	require.Equal(t, time.UnixMilli(1709280000000).UTC().Format(time.RFC3339), data["ct_first_seen"], "could not get earliest embedded timestamp")
+	require.Equal(t, int32(3), lookups.Load(), "queried api for certificate with embedded timestamps")
+
+	// concurrent lookups of a certificate query the api once
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := make(map[string]interface{})
+			request.lookupCTLog(&clients.Response{CertificateResponse: newCert("ee55", "0E:E5")}, data)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(4), lookups.Load(), "could not deduplicate concurrent lookups")
+}
+
+func TestEnumerateExtensions(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{
+		NextProtos:       []string{"h2", "http/1.1"},
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+	}
+	server.StartTLS()
+	defer server.Close()
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	dialer, err := networkclientpool.Get(options, &networkclientpool.Configuration{})
+	require.Nil(t, err, "could not get network client")
+	request := &Request{
+		TLSExtensionsEnum: true,
+		dialer:            dialer,
+		options:           testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{ID: "testing-extensions"}),
+	}
+
+	data := make(map[string]interface{})
+	request.enumerateExtensions(server.Listener.Addr().String(), "127.0.0.1", data)
+	require.Equal(t, "", data["tls_extensions_error"], "could not negotiate extensions")
+	require.Equal(t, []string{"h2", "http/1.1"}, data["alpn_protocols"], "could not enumerate alpn protocols")
+	require.Equal(t, []string{"x25519", "secp256r1"}, data["supported_groups"], "could not enumerate supported groups")
+	require.Contains(t, data["signature_algorithms"], "rsa_pss_rsae_sha256", "could not enumerate signature algorithms")
+	require.Contains(t, data["signature_algorithms"], "rsa_pkcs1_sha256", "could not enumerate tls 1.2 signature algorithms")
+	require.NotContains(t, data["signature_algorithms"], "ecdsa_secp256r1_sha256", "enumerated signature algorithm of other key type")
+	require.Contains(t, data["tls_extensions"], "application_layer_protocol_negotiation", "could not get negotiated extensions")
+
+	data = make(map[string]interface{})
+	request.enumerateExtensions(plain.Listener.Addr().String(), "127.0.0.1", data)
+	require.NotEmpty(t, data["tls_extensions_error"], "could not get handshake error")
+	require.Empty(t, data["alpn_protocols"], "enumerated alpn protocols of failed handshake")
+	require.Empty(t, data["tls_extensions"], "got extensions of failed handshake")
+}
@@ -0,0 +1,91 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+)
+
+// loadTrustStore loads the pem encoded ca certificates of the trust store
+func (request *Request) loadTrustStore() (*x509.CertPool, error) {
+	file, err := request.options.Options.LoadHelperFile(request.TrustStore, request.options.TemplatePath, request.options.Catalog)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load trust store")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read trust store")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no certificates found in trust store %s", request.TrustStore)
+	}
+	return pool, nil
+}
+
+// verifyResponse verifies the certificate chain presented in the response
+// adding chain_trusted and chain_error (and hostname_verified and hostname_error
+// when hostname verification is enabled) to data.
+func (request *Request) verifyResponse(response *clients.Response, hostname string, data map[string]interface{}) {
+	leaf, intermediates, err := parseResponseChain(response)
+	if err == nil {
+		_, err = leaf.Verify(x509.VerifyOptions{Roots: request.roots, Intermediates: intermediates})
+	}
+	data["chain_trusted"] = err == nil
+	data["chain_error"] = errorString(err)
+
+	if !request.VerifyHostname {
+		return
+	}
+	if leaf != nil {
+		err = leaf.VerifyHostname(hostname)
+	}
+	data["hostname_verified"] = err == nil
+	data["hostname_error"] = errorString(err)
+}
+
+// parseResponseChain parses the leaf and intermediate certificates of the response
+func parseResponseChain(response *clients.Response) (*x509.Certificate, *x509.CertPool, error) {
+	if response.CertificateResponse == nil || response.Certificate == "" {
+		return nil, nil, errors.New("no certificate presented")
+	}
+	leaf, err := parsePEMCertificate(response.Certificate)
+	if err != nil {
+		return nil, nil, err
+	}
+	intermediates := x509.NewCertPool()
+	for _, item := range response.Chain {
+		if item == nil || item.Certificate == "" {
+			continue
+		}
+		cert, err := parsePEMCertificate(item.Certificate)
+		if err != nil || bytes.Equal(cert.Raw, leaf.Raw) {
+			continue
+		}
+		intermediates.AddCert(cert)
+	}
+	return leaf, intermediates, nil
+}
+
+// parsePEMCertificate parses a pem encoded certificate
+func parsePEMCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("could not decode certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
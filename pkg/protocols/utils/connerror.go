@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Connection level error types exposed to matchers as `connection_error`
+const (
+	ConnectionErrorTimeout = "timeout"
+	ConnectionErrorReset   = "reset"
+	ConnectionErrorRefused = "refused"
+	ConnectionErrorEOF     = "eof"
+)
+
+// GetConnectionErrorType returns the connection level outcome of a failed
+// request (timeout, reset, refused or eof) or empty string if err is not
+// a connection level error.
+func GetConnectionErrorType(err error) string {
+	if err == nil {
+		return ""
+	}
+	switch {
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return ConnectionErrorReset
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectionErrorRefused
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ConnectionErrorEOF
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return ConnectionErrorTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ConnectionErrorTimeout
+	}
+
+	// errors are often wrapped as strings by http clients
+	// so fallback to matching on the error message
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errStr, "connection reset"), strings.Contains(errStr, "broken pipe"):
+		return ConnectionErrorReset
+	case strings.Contains(errStr, "connection refused"):
+		return ConnectionErrorRefused
+	case strings.Contains(errStr, "timeout"), strings.Contains(errStr, "deadline exceeded"):
+		return ConnectionErrorTimeout
+	case strings.HasSuffix(errStr, "eof"):
+		return ConnectionErrorEOF
+	}
+	return ""
+}
+
+// Request error categories attached to failure events as `error-type`
+const (
+	ErrorTypeDNS               = "dns_error"
+	ErrorTypeTLS               = "tls_error"
+	ErrorTypeTimeout           = "timeout"
+	ErrorTypeConnectionRefused = "connection_refused"
+	ErrorTypeConnectionReset   = "connection_reset"
+	ErrorTypeRead              = "read_error"
+	ErrorTypeUnknown           = "unknown_error"
+)
+
+// GetErrorType returns the category of a request error allowing to
+// distinguish target-side failures from template errors.
+func GetErrorType(err error) string {
+	if err == nil {
+		return ""
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && !dnsErr.IsTimeout {
+		return ErrorTypeDNS
+	}
+	var recordErr tls.RecordHeaderError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateErr x509.CertificateInvalidError
+	if errors.As(err, &recordErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certificateErr) {
+		return ErrorTypeTLS
+	}
+
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errStr, "no such host"), strings.Contains(errStr, "could not resolve"), strings.Contains(errStr, "no address found"):
+		return ErrorTypeDNS
+	case strings.Contains(errStr, "tls:"), strings.Contains(errStr, "x509:"), strings.Contains(errStr, "handshake"):
+		return ErrorTypeTLS
+	}
+
+	switch GetConnectionErrorType(err) {
+	case ConnectionErrorTimeout:
+		return ErrorTypeTimeout
+	case ConnectionErrorRefused:
+		return ErrorTypeConnectionRefused
+	case ConnectionErrorReset:
+		return ErrorTypeConnectionReset
+	case ConnectionErrorEOF:
+		return ErrorTypeRead
+	}
+	if strings.Contains(errStr, "read") {
+		return ErrorTypeRead
+	}
+	return ErrorTypeUnknown
+}
@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -25,3 +30,40 @@ func TestCalculateContentLength(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConnectionErrorType(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{fmt.Errorf("read tcp: %w", syscall.ECONNRESET), ConnectionErrorReset},
+		{fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), ConnectionErrorRefused},
+		{fmt.Errorf("could not read: %w", io.EOF), ConnectionErrorEOF},
+		{errors.New("GET http://example.com giving up after 1 attempts: context deadline exceeded (Client.Timeout exceeded while awaiting headers)"), ConnectionErrorTimeout},
+		{errors.New("could not parse template"), ""},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, GetConnectionErrorType(test.err), "could not get correct connection error type")
+	}
+}
+
+func TestGetErrorType(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}, ErrorTypeDNS},
+		{errors.New("could not resolve host: example.invalid"), ErrorTypeDNS},
+		{errors.New("remote error: tls: handshake failure"), ErrorTypeTLS},
+		{fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), ErrorTypeConnectionRefused},
+		{fmt.Errorf("read tcp: %w", syscall.ECONNRESET), ErrorTypeConnectionReset},
+		{fmt.Errorf("could not read: %w", io.EOF), ErrorTypeRead},
+		{errors.New("context deadline exceeded (Client.Timeout exceeded while awaiting headers)"), ErrorTypeTimeout},
+		{errors.New("could not parse template"), ErrorTypeUnknown},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, GetErrorType(test.err), "could not get correct error type")
+	}
+}
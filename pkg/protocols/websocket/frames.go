@@ -0,0 +1,139 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// maxFrameSize is the maximum size of a received message when the
+// response read size is not limited
+const maxFrameSize = 10 * 1024 * 1024
+
+// frameTypes are the names of frame types exposed to matchers
+var frameTypes = map[ws.OpCode]string{
+	ws.OpText:   "text",
+	ws.OpBinary: "binary",
+	ws.OpPing:   "ping",
+	ws.OpPong:   "pong",
+	ws.OpClose:  "close",
+}
+
+// inputOpCodes are the frame types which can be sent by inputs
+var inputOpCodes = map[string]ws.OpCode{
+	"text":   ws.OpText,
+	"binary": ws.OpBinary,
+	"ping":   ws.OpPing,
+	"close":  ws.OpClose,
+}
+
+// frame is a message or control frame received from the server
+type frame struct {
+	opCode ws.OpCode
+	data   []byte
+}
+
+// frameReader reads the frames of a connection reassembling fragmented
+// messages and answering pings with pongs
+type frameReader struct {
+	reader  io.Reader
+	writer  io.Writer
+	maxSize int64
+
+	// pending is the fragmented message being reassembled
+	pending *frame
+}
+
+// read returns the next message or control frame received
+func (r *frameReader) read() (*frame, error) {
+	for {
+		header, err := ws.ReadHeader(r.reader)
+		if err != nil {
+			return nil, err
+		}
+		size := header.Length
+		if r.pending != nil && !header.OpCode.IsControl() {
+			size += int64(len(r.pending.data))
+		}
+		if size > r.maxSize {
+			return nil, fmt.Errorf("frame exceeds max size of %d bytes", r.maxSize)
+		}
+		payload := make([]byte, header.Length)
+		if _, err := io.ReadFull(r.reader, payload); err != nil {
+			return nil, err
+		}
+		if header.Masked {
+			ws.Cipher(payload, header.Mask, 0)
+		}
+
+		if header.OpCode.IsControl() {
+			if header.OpCode == ws.OpPing {
+				_ = wsutil.WriteClientMessage(r.writer, ws.OpPong, payload)
+			}
+			// control frames may be interleaved in fragmented messages
+			return &frame{opCode: header.OpCode, data: payload}, nil
+		}
+		if header.OpCode == ws.OpContinuation {
+			if r.pending == nil {
+				return nil, errors.New("continuation frame without message")
+			}
+			r.pending.data = append(r.pending.data, payload...)
+		} else {
+			r.pending = &frame{opCode: header.OpCode, data: payload}
+		}
+		if header.Fin {
+			message := r.pending
+			r.pending = nil
+			return message, nil
+		}
+	}
+}
+
+// readFrames reads up to count frames stopping early on read timeouts or
+// once a close frame is received
+func (r *frameReader) readFrames(count int) ([]*frame, error) {
+	var frames []*frame
+	for len(frames) < count {
+		received, err := r.read()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return frames, nil
+			}
+			return frames, err
+		}
+		frames = append(frames, received)
+		if received.opCode == ws.OpClose {
+			break
+		}
+	}
+	return frames, nil
+}
+
+// frameEvents returns the events of received frames: data and type of each
+// frame indexed from 0 as frame_N and frame_N_type, their count as frames,
+// the list of their types as frame_types and the status code and reason of
+// a close frame as close_code and close_reason.
+func frameEvents(frames []*frame) map[string]interface{} {
+	events := map[string]interface{}{"frames": len(frames)}
+	types := make([]string, 0, len(frames))
+	for i, received := range frames {
+		name := frameTypes[received.opCode]
+		events["frame_"+strconv.Itoa(i)] = string(received.data)
+		events["frame_"+strconv.Itoa(i)+"_type"] = name
+		types = append(types, name)
+		if received.opCode == ws.OpClose {
+			code, reason := ws.ParseCloseFrameData(received.data)
+			events["close_code"] = int(code)
+			events["close_reason"] = reason
+		}
+	}
+	events["frame_types"] = strings.Join(types, ",")
+	return events
+}
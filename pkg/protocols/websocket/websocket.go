@@ -86,6 +86,26 @@ type Input struct {
 	//   - value: "\"hex_decode('50494e47')\""
 	Data string `yaml:"data,omitempty" json:"data,omitempty" jsonschema:"title=data to send as input,description=Data is the data to send as the input"`
 	// description: |
+	//   Type is the type of frame sent with the data.
+	//
+	//   Default value is text. Inputs without data and type only read frames.
+	// values:
+	//   - "text"
+	//   - "binary"
+	//   - "ping"
+	//   - "close"
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of frame sent,description=Type of frame sent with the data,enum=text,enum=binary,enum=ping,enum=close"`
+	// description: |
+	//   Read is the number of frames read after sending the data (default 1).
+	//
+	//   Reading stops early once the response read timeout expires or a close
+	//   frame is received. Pings are answered with pongs. Every frame received
+	//   (including ping, pong and close frames) is available to matchers in
+	//   order as frame_0..N with its type as frame_0_type..N.
+	// examples:
+	//   - value: "2"
+	Read int `yaml:"read,omitempty" json:"read,omitempty" jsonschema:"title=frames to read,description=Number of frames read after sending the data"`
+	// description: |
 	//   Name is the optional name of the data read to provide matching on.
 	// examples:
 	//   - value: "\"prefix\""
@@ -107,6 +127,15 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	}
 	request.dialer = client
 
+	for _, input := range request.Inputs {
+		if _, ok := inputOpCodes[input.Type]; input.Type != "" && !ok {
+			return fmt.Errorf("invalid input type %s: expected text, binary, ping or close", input.Type)
+		}
+		if input.Read < 0 {
+			return fmt.Errorf("invalid input read %d: frames to read must be positive", input.Read)
+		}
+	}
+
 	if len(request.Payloads) > 0 {
 		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, options.Catalog, options.Options.AttackType, types.DefaultOptions())
 		if err != nil {
@@ -117,6 +146,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.PartialMatchThreshold = options.Options.PartialMatchThreshold
 		compiled.TemplateID = options.TemplateID
 		if err := compiled.Compile(); err != nil {
 			return errors.Wrap(err, "could not compile operators")
@@ -129,7 +159,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 // Requests returns the total number of requests the rule will perform
 func (request *Request) Requests() int {
 	if request.generator != nil {
-		return request.generator.NewIterator().Total()
+		return request.generator.Total()
 	}
 	return 1
 }
@@ -177,7 +207,7 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 		return errors.Wrap(err, parseUrlErrorMessage)
 	}
 	defaultVars := protocolutils.GenerateVariables(parsed, false, nil)
-	optionVars := generators.BuildPayloadFromOptions(request.options.Options)
+	optionVars := request.options.BuildPayloadFromOptions(target.MetaInput)
 	// add templatecontext variables to varMap
 	variables := request.options.Variables.Evaluate(generators.MergeMaps(defaultVars, optionVars, dynamicValues, request.options.GetTemplateCtx(target.MetaInput).GetAll()))
 	payloadValues := generators.MergeMaps(variables, defaultVars, optionVars, dynamicValues, request.options.Constants)
@@ -236,12 +266,19 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	}
 	defer conn.Close()
 
-	responseBuilder := &strings.Builder{}
+	// frames sent right after the handshake are buffered by the dialer
+	var reader io.Reader = conn
 	if readBuffer != nil {
-		_, _ = io.Copy(responseBuilder, readBuffer) // Copy initial response
+		reader = readBuffer
 	}
+	maxSize := int64(maxFrameSize)
+	if requestOptions.Options.ResponseReadSize > 0 {
+		maxSize = int64(requestOptions.Options.ResponseReadSize)
+	}
+	frames := &frameReader{reader: reader, writer: conn, maxSize: maxSize}
 
-	events, requestOutput, err := request.readWriteInputWebsocket(conn, payloadValues, input, responseBuilder)
+	responseBuilder := &strings.Builder{}
+	events, requestOutput, err := request.readWriteInputWebsocket(conn, frames, payloadValues, input, responseBuilder)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -291,44 +328,64 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	return nil
 }
 
-func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map[string]interface{}, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
+func (request *Request) readWriteInputWebsocket(conn net.Conn, frames *frameReader, payloadValues map[string]interface{}, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
 	reqBuilder := &strings.Builder{}
 	inputEvents := make(map[string]interface{})
+	var received []*frame
 
 	requestOptions := request.options
 	for _, req := range request.Inputs {
-		reqBuilder.Grow(len(req.Data))
+		if req.Data != "" || req.Type != "" {
+			reqBuilder.Grow(len(req.Data))
+
+			finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
+			if dataErr != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return nil, "", errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+			}
+			reqBuilder.WriteString(string(finalData))
 
-		finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
-		if dataErr != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+			opCode, ok := inputOpCodes[req.Type]
+			if !ok {
+				opCode = ws.OpText
+			}
+			if opCode == ws.OpClose {
+				finalData = ws.NewCloseFrameBody(ws.StatusNormalClosure, string(finalData))
+			}
+			err = wsutil.WriteClientMessage(conn, opCode, finalData)
+			if err != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return nil, "", errors.Wrap(err, "could not write request to server")
+			}
 		}
-		reqBuilder.WriteString(string(finalData))
 
-		err = wsutil.WriteClientMessage(conn, ws.OpText, finalData)
-		if err != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+		count := req.Read
+		if count == 0 {
+			count = 1
 		}
-
-		msg, opCode, err := wsutil.ReadServerData(conn)
-		if err != nil {
+		_ = conn.SetReadDeadline(time.Now().Add(requestOptions.Options.ResponseReadTimeout))
+		stepFrames, err := frames.readFrames(count)
+		_ = conn.SetReadDeadline(time.Time{})
+		if err != nil && len(stepFrames) == 0 {
 			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+			return nil, "", errors.Wrap(err, "could not read response from server")
 		}
-		// Only perform matching and writes in case we receive
-		// text or binary opcode from the websocket server.
-		if opCode != ws.OpText && opCode != ws.OpBinary {
-			continue
+		received = append(received, stepFrames...)
+
+		// Only perform matching and writes on text or binary
+		// frames received from the websocket server.
+		stepData := &strings.Builder{}
+		for _, stepFrame := range stepFrames {
+			if stepFrame.opCode == ws.OpText || stepFrame.opCode == ws.OpBinary {
+				stepData.Write(stepFrame.data)
+			}
 		}
-
-		respBuilder.Write(msg)
-		if req.Name != "" {
-			bufferStr := string(msg)
+		respBuilder.WriteString(stepData.String())
+		if req.Name != "" && stepData.Len() > 0 {
+			bufferStr := stepData.String()
 			inputEvents[req.Name] = bufferStr
 
 			// Run any internal extractors for the request here and add found values to map.
@@ -339,6 +396,13 @@ func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map
 				}
 			}
 		}
+		// no frames can be exchanged once the server closed the connection
+		if err != nil || (len(stepFrames) > 0 && stepFrames[len(stepFrames)-1].opCode == ws.OpClose) {
+			break
+		}
+	}
+	for k, v := range frameEvents(received) {
+		inputEvents[k] = v
 	}
 	return inputEvents, reqBuilder.String(), nil
 }
@@ -386,12 +450,18 @@ func (request *Request) GetCompiledOperators() []*operators.Operators {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":     "Type is the type of request made",
-	"success":  "Success specifies whether websocket connection was successful",
-	"request":  "Websocket request made to the server",
-	"response": "Websocket response received from the server",
-	"host":     "Host is the input to the template",
-	"matched":  "Matched is the input which was matched upon",
+	"type":           "Type is the type of request made",
+	"success":        "Success specifies whether websocket connection was successful",
+	"request":        "Websocket request made to the server",
+	"response":       "Websocket response received from the server",
+	"host":           "Host is the input to the template",
+	"matched":        "Matched is the input which was matched upon",
+	"frames":         "Frames is the number of frames received from the server",
+	"frame_types":    "Frame Types is the comma separated list of types (text,binary,ping,pong,close) of frames received",
+	"<frame_N>":      "Data of the Nth frame received from the server (from 0)",
+	"<frame_N_type>": "Type of the Nth frame received from the server (from 0)",
+	"close_code":     "Close Code is the status code of the close frame received from the server",
+	"close_reason":   "Close Reason is the reason of the close frame received from the server",
 }
 
 func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
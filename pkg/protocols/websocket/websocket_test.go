@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+func TestWebsocketFrames(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msg, opCode, err := wsutil.ReadClientData(conn)
+			if err != nil {
+				return
+			}
+			switch {
+			case opCode == ws.OpText && string(msg) == "hello":
+				_ = ws.WriteFrame(conn, ws.NewPingFrame([]byte("alive")))
+				// fragmented message
+				_ = ws.WriteFrame(conn, ws.NewFrame(ws.OpText, false, []byte("wor")))
+				_ = ws.WriteFrame(conn, ws.NewFrame(ws.OpContinuation, true, []byte("ld")))
+			case opCode == ws.OpText && string(msg) == "bye":
+				_ = ws.WriteFrame(conn, ws.NewCloseFrame(ws.NewCloseFrameBody(ws.StatusNormalClosure, "done")))
+				return
+			}
+		}
+	}))
+	defer ts.Close()
+
+	templateID := "testing-websocket"
+	request := &Request{
+		Address: "{{Scheme}}://{{Hostname}}",
+		Inputs: []*Input{
+			{Data: "hello", Read: 2, Name: "greeting"},
+			{Data: "bye"},
+		},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`frame_types == "ping,text,close" && frame_1 == "world" && greeting == "world" && close_code == 1000`},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	require.Nil(t, request.Compile(executerOpts), "could not compile websocket request")
+
+	var finalEvent *output.InternalWrappedEvent
+	target := strings.Replace(ts.URL, "http://", "ws://", 1)
+	err := request.ExecuteWithResults(contextargs.NewWithInput(context.Background(), target), nil, nil, func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute websocket request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 3, finalEvent.InternalEvent["frames"], "could not get received frames")
+	require.Equal(t, "alive", finalEvent.InternalEvent["frame_0"], "could not get ping frame data")
+	require.Equal(t, "done", finalEvent.InternalEvent["close_reason"], "could not get close reason")
+	require.Equal(t, "world", finalEvent.InternalEvent["response"], "could not get response")
+	require.True(t, finalEvent.OperatorsResult != nil && finalEvent.OperatorsResult.Matched, "could not match frames")
+
+	invalid := &Request{Address: "{{Scheme}}://{{Hostname}}", Inputs: []*Input{{Data: "x", Type: "pong"}}}
+	require.NotNil(t, invalid.Compile(executerOpts), "compiled invalid input type")
+}
@@ -0,0 +1,360 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/reproduce"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// Bundle files written for each finding
+const (
+	findingFile     = "finding.json"
+	templateFile    = "template.yaml"
+	operatorsFile   = "operators.yaml"
+	requestFile     = "request.txt"
+	responseFile    = "response.txt"
+	curlFile        = "curl.sh"
+	httpieFile      = "httpie.sh"
+	environmentFile = "environment.json"
+)
+
+// Options contains the configuration options for the proof bundle exporter
+type Options struct {
+	// Directory is the directory bundles are written to
+	Directory string `yaml:"directory"`
+	// Zip writes each bundle as a zip archive instead of a directory
+	Zip bool `yaml:"zip"`
+}
+
+// Exporter is an exporter writing a self-contained proof bundle for each
+// finding: the result, the template, the operators that fired, the request,
+// the response and the environment of the scan. Raw requests and responses
+// are always written since they are required to reproduce the finding.
+type Exporter struct {
+	options     *Options
+	environment []byte
+	count       atomic.Int64
+}
+
+// environment is the environment of the scan which produced the findings
+type environment struct {
+	NucleiVersion    string `json:"nuclei-version"`
+	TemplatesVersion string `json:"templates-version,omitempty"`
+	GoVersion        string `json:"go-version"`
+	OS               string `json:"os"`
+	Arch             string `json:"arch"`
+	CommandLine      string `json:"command-line"`
+}
+
+// redactedFlags are the flags whose values may contain credentials and are
+// redacted from the command line recorded in the environment
+var redactedFlags = map[string]struct{}{
+	"H": {}, "header": {},
+	"V": {}, "var": {},
+	"sf": {}, "secret-file": {},
+	"p": {}, "proxy": {},
+	"iproxy": {}, "interactsh-proxy": {},
+	"itoken": {}, "interactsh-token": {},
+	"sjh": {}, "ssh-jump-host": {},
+	"sjp": {}, "ssh-jump-password": {},
+}
+
+// redactedValue replaces the values of redacted flags
+const redactedValue = "[redacted]"
+
+// redactCommandLine returns the command line of args with values of flags
+// which may contain credentials redacted
+func redactCommandLine(args []string) string {
+	redacted := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		if redactNext {
+			redacted = append(redacted, redactedValue)
+			redactNext = false
+			continue
+		}
+		if name := strings.TrimLeft(arg, "-"); name != arg {
+			key, _, hasValue := strings.Cut(name, "=")
+			if _, ok := redactedFlags[key]; ok {
+				if !hasValue {
+					redactNext = true
+				} else {
+					arg = arg[:len(arg)-len(name)] + key + "=" + redactedValue
+				}
+			}
+		}
+		redacted = append(redacted, arg)
+	}
+	return strings.Join(redacted, " ")
+}
+
+// New creates a new proof bundle exporter
+func New(options *Options) (*Exporter, error) {
+	if options.Directory == "" {
+		return nil, errors.New("bundle directory is required")
+	}
+	if err := os.MkdirAll(options.Directory, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create bundle directory")
+	}
+	env := environment{
+		NucleiVersion: config.Version,
+		GoVersion:     runtime.Version(),
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		CommandLine:   redactCommandLine(os.Args),
+	}
+	if config.DefaultConfig != nil {
+		env.TemplatesVersion = config.DefaultConfig.TemplateVersion
+	}
+	data, err := marshalJSON(env)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{options: options, environment: data}, nil
+}
+
+// Export writes the proof bundle of the result event
+func (exporter *Exporter) Export(event *output.ResultEvent) error {
+	files, err := exporter.bundleFiles(event)
+	if err != nil {
+		return err
+	}
+	name := bundleName(event)
+	if exporter.options.Zip {
+		err = writeZip(filepath.Join(exporter.options.Directory, name+".zip"), files)
+	} else {
+		err = writeDirectory(filepath.Join(exporter.options.Directory, name), files)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not write bundle %s", name)
+	}
+	exporter.count.Add(1)
+	return nil
+}
+
+// bundleFiles returns the files of the bundle of the result event by name
+func (exporter *Exporter) bundleFiles(event *output.ResultEvent) (map[string][]byte, error) {
+	files := map[string][]byte{environmentFile: exporter.environment}
+
+	template := loadTemplate(event)
+	// the template is written as is instead of encoded in the finding
+	finding := *event
+	finding.TemplateEncoded = ""
+	data, err := marshalJSON(finding)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal finding")
+	}
+	files[findingFile] = data
+
+	if len(template) > 0 {
+		files[templateFile] = template
+		if operators := firedOperators(template, event); operators != nil {
+			files[operatorsFile] = operators
+		}
+	}
+	if event.Request != "" {
+		files[requestFile] = []byte(event.Request)
+	}
+	if event.Response != "" {
+		files[responseFile] = []byte(event.Response)
+	}
+	// commands are run from the bundle so that they read the binary body from it
+	if event.CURLCommand != "" {
+		files[curlFile] = []byte("#!/bin/sh\ncd \"$(dirname \"$0\")\"\n" + event.CURLCommand + "\n")
+	}
+	if event.HTTPieCommand != "" {
+		files[httpieFile] = []byte("#!/bin/sh\ncd \"$(dirname \"$0\")\"\n" + event.HTTPieCommand + "\n")
+	}
+	if len(event.RequestBody) > 0 {
+		files[reproduce.BodyFile] = event.RequestBody
+	}
+	return files, nil
+}
+
+// marshalJSON returns the indented json of the value without escaping
+// html characters so that payloads are written as sent
+func marshalJSON(value interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// loadTemplate returns the template of the result event from the
+// encoded template or from the template file if not encoded
+func loadTemplate(event *output.ResultEvent) []byte {
+	if event.TemplateEncoded != "" {
+		if data, err := base64.StdEncoding.DecodeString(event.TemplateEncoded); err == nil {
+			return data
+		}
+	}
+	if event.TemplatePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(event.TemplatePath)
+	if err != nil {
+		gologger.Verbose().Msgf("Could not read template %s for bundle: %s", event.TemplatePath, err)
+		return nil
+	}
+	return data
+}
+
+// firedOperators returns the matchers and extractors of the template named as
+// the matcher and extractor of the result event, all of them if unnamed.
+func firedOperators(template []byte, event *output.ResultEvent) []byte {
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(template, &parsed); err != nil {
+		return nil
+	}
+	fired := make(map[string]interface{})
+	var matchers, extractors []interface{}
+	walkOperators(parsed, func(key string, operator map[string]interface{}) {
+		name, _ := operator["name"].(string)
+		switch key {
+		case "matchers":
+			if event.MatcherName == "" || name == event.MatcherName {
+				matchers = append(matchers, operator)
+			}
+		case "extractors":
+			if event.ExtractorName == "" || name == event.ExtractorName {
+				extractors = append(extractors, operator)
+			}
+		}
+	})
+	if event.MatcherName != "" {
+		fired["matcher-name"] = event.MatcherName
+	}
+	if len(matchers) > 0 {
+		fired["matchers"] = matchers
+	}
+	if event.ExtractorName != "" {
+		fired["extractor-name"] = event.ExtractorName
+	}
+	if len(extractors) > 0 && (event.ExtractorName != "" || len(event.ExtractedResults) > 0) {
+		fired["extractors"] = extractors
+	}
+	if len(matchers) == 0 && len(extractors) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(fired)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// walkOperators calls the callback for each matcher and extractor of the template
+func walkOperators(value interface{}, callback func(key string, operator map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// keys are sorted so that operators are in a stable order
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item := v[key]
+			if list, ok := item.([]interface{}); ok && (key == "matchers" || key == "extractors") {
+				for _, entry := range list {
+					if operator, ok := entry.(map[string]interface{}); ok {
+						callback(key, operator)
+					}
+				}
+				continue
+			}
+			walkOperators(item, callback)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkOperators(item, callback)
+		}
+	}
+}
+
+// bundleName returns the name of the bundle of the result event, unique
+// for each finding (template, matcher, location and request)
+func bundleName(event *output.ResultEvent) string {
+	hash := sha1.New()
+	for _, value := range []string{event.TemplateID, event.MatcherName, event.ExtractorName, event.Matched, event.Request, strings.Join(event.ExtractedResults, ",")} {
+		_, _ = hash.Write([]byte(value))
+		_, _ = hash.Write([]byte{0})
+	}
+	name := event.TemplateID + "-" + event.Host
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	name = stringsutil.ReplaceAll(name, "_", "?", "/", "\\", ">", "|", ":", ";", "*", "<", "\"", "'", " ")
+	return name + "-" + hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// writeDirectory writes the files to the directory
+func writeDirectory(directory string, files map[string][]byte) error {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeZip writes the files to the zip archive
+func writeZip(path string, files map[string][]byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := zip.NewWriter(file)
+	for _, name := range names {
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// Close closes the exporter after operation
+func (exporter *Exporter) Close() error {
+	if count := exporter.count.Load(); count > 0 {
+		gologger.Info().Msgf("Wrote %d proof bundles to %s", count, exporter.options.Directory)
+	}
+	return nil
+}
@@ -2,8 +2,8 @@ package jsonl
 
 import (
 	"encoding/json"
-	"os"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
@@ -12,7 +12,7 @@ import (
 type Exporter struct {
 	options *Options
 	mutex   *sync.Mutex
-	rows    []output.ResultEvent
+	file    *output.RotatingFile
 }
 
 // Options contains the configuration options for JSONL exporter client
@@ -20,20 +20,37 @@ type Options struct {
 	// File is the file to export found JSONL result to
 	File    string `yaml:"file"`
 	OmitRaw bool   `yaml:"omit-raw"`
+	// RotateSize is the size in MB after which the file is rotated
+	RotateSize int `yaml:"rotate-size"`
+	// RotateInterval is the duration after which the file is rotated
+	RotateInterval time.Duration `yaml:"rotate-interval"`
+	// FlushInterval is the interval at which results are synced to disk
+	FlushInterval time.Duration `yaml:"flush-interval"`
+	// Append appends results to the existing file (ex: resume)
+	Append bool `yaml:"-"`
 }
 
 // New creates a new JSONL exporter integration client based on options.
+// Results are written to the file as they are exported.
 func New(options *Options) (*Exporter, error) {
+	file, err := output.NewRotatingFile(options.File, output.RotateOptions{
+		MaxSize:       int64(options.RotateSize) * 1024 * 1024,
+		Interval:      options.RotateInterval,
+		FlushInterval: options.FlushInterval,
+		Append:        options.Append,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create JSONL file")
+	}
 	exporter := &Exporter{
 		mutex:   &sync.Mutex{},
 		options: options,
-		rows:    []output.ResultEvent{},
+		file:    file,
 	}
 	return exporter, nil
 }
 
-// Export appends the passed result event to the list of objects to be exported to
-// the resulting JSONL file
+// Export writes the passed result event as a line of the resulting JSONL file
 func (exporter *Exporter) Export(event *output.ResultEvent) error {
 	exporter.mutex.Lock()
 	defer exporter.mutex.Unlock()
@@ -43,45 +60,28 @@ func (exporter *Exporter) Export(event *output.ResultEvent) error {
 		event.Response = ""
 	}
 
-	// Add the event to the rows
-	exporter.rows = append(exporter.rows, *event)
+	// Convert the row to JSON byte array and append a trailing newline. This is treated as a single line in JSONL
+	obj, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate row for JSONL report")
+	}
 
+	// Add a trailing newline to the JSON byte array to confirm with the JSONL format
+	obj = append(obj, '\n')
+
+	if _, err = exporter.file.Write(obj); err != nil {
+		return errors.Wrap(err, "failed to append JSONL line")
+	}
 	return nil
 }
 
-// Close writes the in-memory data to the JSONL file specified by options.JSONLExport
-// and closes the exporter after operation
+// Close flushes the JSONL file to disk and closes the exporter after operation
 func (exporter *Exporter) Close() error {
 	exporter.mutex.Lock()
 	defer exporter.mutex.Unlock()
 
-	// Open the JSONL file for writing and create it if it doesn't exist
-	f, err := os.OpenFile(exporter.options.File, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return errors.Wrap(err, "failed to create JSONL file")
-	}
-
-	// Loop through the rows and convert each to a JSON byte array and write to file
-	for _, row := range exporter.rows {
-		// Convert the row to JSON byte array and append a trailing newline. This is treated as a single line in JSONL
-		obj, err := json.Marshal(row)
-		if err != nil {
-			return errors.Wrap(err, "failed to generate row for JSONL report")
-		}
-
-		// Add a trailing newline to the JSON byte array to confirm with the JSONL format
-		obj = append(obj, '\n')
-
-		// Attempt to append the JSON line to file specified in options.JSONLExport
-		if _, err = f.Write(obj); err != nil {
-			return errors.Wrap(err, "failed to append JSONL line")
-		}
-	}
-
-	// Close the file
-	if err := f.Close(); err != nil {
+	if err := exporter.file.Close(); err != nil {
 		return errors.Wrap(err, "failed to close JSONL file")
 	}
-
 	return nil
 }
@@ -6,8 +6,10 @@ import (
 	"os"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/sarif"
@@ -20,12 +22,20 @@ type Exporter struct {
 	rulemap map[string]*int // contains rule-id && ruleIndex
 	rules   []sarif.ReportingDescriptor
 	options *Options
+
+	// dirty tracks results exported since the report was last written
+	dirty bool
+	done  chan struct{}
+	wg    sync.WaitGroup
 }
 
 // Options contains the configuration options for sarif exporter client
 type Options struct {
 	// File is the file to export found sarif result to
 	File string `yaml:"file"`
+	// FlushInterval is the interval at which the report of results found
+	// so far is written to the file (0 to write it on close only)
+	FlushInterval time.Duration `yaml:"flush-interval"`
 }
 
 // New creates a new sarif exporter integration client based on options.
@@ -38,6 +48,12 @@ func New(options *Options) (*Exporter, error) {
 		rulemap: map[string]*int{},
 		options: options,
 	}
+	exporter.addInvocation()
+	if options.FlushInterval > 0 {
+		exporter.done = make(chan struct{})
+		exporter.wg.Add(1)
+		go exporter.flushPeriodically()
+	}
 	return exporter, nil
 }
 
@@ -59,7 +75,10 @@ func (exporter *Exporter) addToolDetails() {
 		Rules:           exporter.rules,
 	}
 	exporter.sarif.RegisterTool(driver)
+}
 
+// addInvocation adds details of the invocation of the tool
+func (exporter *Exporter) addInvocation() {
 	reportLocation := sarif.ArtifactLocation{
 		Uri: "file:///" + exporter.options.File,
 		Description: &sarif.Message{
@@ -167,30 +186,71 @@ func (exporter *Exporter) Export(event *output.ResultEvent) error {
 	}
 
 	exporter.sarif.RegisterResult(*result)
+	exporter.dirty = true
 
 	return nil
 
 }
 
-// Close Writes data and closes the exporter after operation
-func (exporter *Exporter) Close() error {
-	exporter.mutex.Lock()
-	defer exporter.mutex.Unlock()
+func (exporter *Exporter) flushPeriodically() {
+	defer exporter.wg.Done()
 
-	if len(exporter.rules) == 0 {
-		// no output if there are no results
-		return nil
+	ticker := time.NewTicker(exporter.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exporter.done:
+			return
+		case <-ticker.C:
+			exporter.mutex.Lock()
+			if exporter.dirty {
+				if err := exporter.write(); err != nil {
+					gologger.Warning().Msgf("Could not write sarif report: %s\n", err)
+				}
+			}
+			exporter.mutex.Unlock()
+		}
 	}
+}
+
+// write writes the report of results exported so far to the file. The
+// file is replaced atomically so that it is always a valid report. The
+// mutex must be held.
+func (exporter *Exporter) write() error {
 	// links results and rules/templates
 	exporter.addToolDetails()
 
+	// the report is exported as a snapshot of the current run
+	runs := exporter.sarif.Sarif.Runs
 	bin, err := exporter.sarif.Export()
+	exporter.sarif.Sarif.Runs = runs
 	if err != nil {
 		return errors.Wrap(err, "failed to generate sarif report")
 	}
-	if err := os.WriteFile(exporter.options.File, bin, 0644); err != nil {
+	tmpFile := exporter.options.File + ".tmp"
+	if err := os.WriteFile(tmpFile, bin, 0644); err != nil {
 		return errors.Wrap(err, "failed to create sarif file")
 	}
-
+	if err := os.Rename(tmpFile, exporter.options.File); err != nil {
+		_ = os.Remove(tmpFile)
+		return errors.Wrap(err, "failed to create sarif file")
+	}
+	exporter.dirty = false
 	return nil
 }
+
+// Close Writes data and closes the exporter after operation
+func (exporter *Exporter) Close() error {
+	if exporter.done != nil {
+		close(exporter.done)
+		exporter.wg.Wait()
+	}
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	if len(exporter.rules) == 0 {
+		// no output if there are no results
+		return nil
+	}
+	return exporter.write()
+}
@@ -0,0 +1,262 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	// pure go driver so that the exporter works in builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// DefaultBatchSize is the default number of results written in a single transaction
+	DefaultBatchSize = 100
+	// DefaultFlushInterval is the default interval after which pending results are written
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// schema is the normalized schema of the results database. Scans, targets
+// and raw request/response pairs are stored separately from findings to
+// allow querying findings across scans.
+const schema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at DATETIME NOT NULL
+);
+CREATE TABLE IF NOT EXISTS targets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	host TEXT NOT NULL UNIQUE,
+	ip TEXT,
+	scheme TEXT,
+	port TEXT
+);
+CREATE TABLE IF NOT EXISTS findings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	scan_id INTEGER NOT NULL REFERENCES scans(id),
+	target_id INTEGER NOT NULL REFERENCES targets(id),
+	template_id TEXT NOT NULL,
+	template_path TEXT,
+	name TEXT,
+	severity TEXT,
+	tags TEXT,
+	type TEXT,
+	matcher_name TEXT,
+	extractor_name TEXT,
+	matched_at TEXT,
+	extracted_results TEXT,
+	metadata TEXT,
+	curl_command TEXT,
+	timestamp DATETIME NOT NULL
+);
+CREATE TABLE IF NOT EXISTS requests (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	finding_id INTEGER NOT NULL REFERENCES findings(id),
+	request TEXT,
+	response TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_findings_template_id ON findings(template_id);
+CREATE INDEX IF NOT EXISTS idx_findings_severity ON findings(severity);
+CREATE INDEX IF NOT EXISTS idx_findings_target_id ON findings(target_id);
+CREATE INDEX IF NOT EXISTS idx_findings_scan_id ON findings(scan_id);
+CREATE INDEX IF NOT EXISTS idx_requests_finding_id ON requests(finding_id);
+`
+
+// Options contains the configuration options for SQLite exporter client
+type Options struct {
+	// File is the sqlite database file to export results to
+	File string `yaml:"file"`
+	// BatchSize is the number of results written in a single transaction
+	BatchSize int `yaml:"batch-size"`
+	// FlushInterval is the interval after which pending results are written
+	FlushInterval time.Duration `yaml:"flush-interval"`
+	OmitRaw       bool          `yaml:"omit-raw"`
+}
+
+// Exporter is an exporter writing results to a sqlite database
+type Exporter struct {
+	options *Options
+	db      *sql.DB
+	scanID  int64
+
+	mutex   *sync.Mutex
+	pending []*output.ResultEvent
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates a new SQLite exporter integration client based on options.
+// Results of each scan are recorded under a new scan of the database.
+func New(options *Options) (*Exporter, error) {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+	db, err := sql.Open("sqlite", options.File+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open sqlite database")
+	}
+	// sqlite allows a single writer
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "could not create sqlite schema")
+	}
+	result, err := db.Exec("INSERT INTO scans (started_at) VALUES (?)", time.Now())
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "could not create scan")
+	}
+	scanID, err := result.LastInsertId()
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "could not get scan id")
+	}
+
+	exporter := &Exporter{
+		options: options,
+		db:      db,
+		scanID:  scanID,
+		mutex:   &sync.Mutex{},
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.flushPeriodically()
+	return exporter, nil
+}
+
+// Export queues the result event which is written to the database once
+// the batch size is reached or periodically
+func (exporter *Exporter) Export(event *output.ResultEvent) error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	exporter.pending = append(exporter.pending, event)
+	if len(exporter.pending) >= exporter.options.BatchSize {
+		return exporter.flush()
+	}
+	return nil
+}
+
+func (exporter *Exporter) flushPeriodically() {
+	defer exporter.wg.Done()
+
+	ticker := time.NewTicker(exporter.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exporter.done:
+			return
+		case <-ticker.C:
+			exporter.mutex.Lock()
+			if err := exporter.flush(); err != nil {
+				gologger.Warning().Msgf("Could not write results to sqlite database: %s\n", err)
+			}
+			exporter.mutex.Unlock()
+		}
+	}
+}
+
+// flush writes pending results in a single transaction. Results of a
+// failed transaction are requeued and written by the next flush. The mutex
+// must be held.
+func (exporter *Exporter) flush() error {
+	if len(exporter.pending) == 0 {
+		return nil
+	}
+	events := exporter.pending
+	exporter.pending = nil
+
+	if err := exporter.write(events); err != nil {
+		exporter.pending = append(events, exporter.pending...)
+		return err
+	}
+	return nil
+}
+
+// write inserts events in a single transaction
+func (exporter *Exporter) write(events []*output.ResultEvent) error {
+	tx, err := exporter.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	for _, event := range events {
+		if err := exporter.insert(tx, event); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not commit results")
+	}
+	return nil
+}
+
+func (exporter *Exporter) insert(tx *sql.Tx, event *output.ResultEvent) error {
+	host := event.Host
+	if host == "" {
+		host = event.Matched
+	}
+	if _, err := tx.Exec(`INSERT INTO targets (host, ip, scheme, port) VALUES (?, ?, ?, ?)
+		ON CONFLICT(host) DO UPDATE SET ip = COALESCE(NULLIF(excluded.ip, ''), targets.ip)`, host, event.IP, event.Scheme, event.Port); err != nil {
+		return errors.Wrap(err, "could not insert target")
+	}
+	var targetID int64
+	if err := tx.QueryRow("SELECT id FROM targets WHERE host = ?", host).Scan(&targetID); err != nil {
+		return errors.Wrap(err, "could not get target")
+	}
+
+	extracted, err := json.Marshal(event.ExtractedResults)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal extracted results")
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal metadata")
+	}
+	result, err := tx.Exec(`INSERT INTO findings (scan_id, target_id, template_id, template_path, name, severity, tags, type,
+		matcher_name, extractor_name, matched_at, extracted_results, metadata, curl_command, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		exporter.scanID, targetID, event.TemplateID, event.TemplatePath, event.Info.Name, event.Info.SeverityHolder.Severity.String(),
+		event.Info.Tags.String(), event.Type, event.MatcherName, event.ExtractorName, event.Matched, string(extracted), string(metadata),
+		event.CURLCommand, event.Timestamp)
+	if err != nil {
+		return errors.Wrap(err, "could not insert finding")
+	}
+	if exporter.options.OmitRaw || (event.Request == "" && event.Response == "") {
+		return nil
+	}
+	findingID, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "could not get finding id")
+	}
+	if _, err := tx.Exec("INSERT INTO requests (finding_id, request, response) VALUES (?, ?, ?)", findingID, event.Request, event.Response); err != nil {
+		return errors.Wrap(err, "could not insert request")
+	}
+	return nil
+}
+
+// Close writes pending results to the database and closes it
+func (exporter *Exporter) Close() error {
+	close(exporter.done)
+	exporter.wg.Wait()
+
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	if err := exporter.flush(); err != nil {
+		_ = exporter.db.Close()
+		return err
+	}
+	if err := exporter.db.Close(); err != nil {
+		return errors.Wrap(err, "could not close sqlite database")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+func TestSQLiteExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "results.db")
+	exporter, err := New(&Options{File: file, BatchSize: 2})
+	require.Nil(t, err, "could not create exporter")
+
+	// a failing transaction requeues its results for the next flush
+	_, err = exporter.db.Exec("ALTER TABLE findings RENAME TO findings_tmp")
+	require.Nil(t, err, "could not rename findings table")
+	for _, id := range []string{"first", "second"} {
+		_ = exporter.Export(&output.ResultEvent{TemplateID: id, Host: "example.com", Timestamp: time.Now()})
+	}
+	require.Len(t, exporter.pending, 2, "could not requeue failed results")
+	_, err = exporter.db.Exec("ALTER TABLE findings_tmp RENAME TO findings")
+	require.Nil(t, err, "could not restore findings table")
+
+	err = exporter.Export(&output.ResultEvent{TemplateID: "third", Host: "other.com", Request: "GET / HTTP/1.1", Response: "HTTP/1.1 200 OK", Timestamp: time.Now()})
+	require.Nil(t, err, "could not export result")
+	require.Nil(t, exporter.Close(), "could not close exporter")
+
+	db, err := sql.Open("sqlite", file)
+	require.Nil(t, err, "could not open database")
+	defer db.Close()
+
+	count := func(query string) int {
+		var n int
+		require.Nil(t, db.QueryRow(query).Scan(&n), "could not query database")
+		return n
+	}
+	require.Equal(t, 3, count("SELECT COUNT(*) FROM findings"), "could not write findings")
+	require.Equal(t, 2, count("SELECT COUNT(*) FROM targets"), "could not write targets")
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM requests"), "could not write requests")
+	require.Equal(t, 1, count("SELECT COUNT(*) FROM findings WHERE template_id = 'first'"), "could not write requeued findings once")
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/projectdiscovery/useragent"
+)
+
+const (
+	// DefaultQueueSize is the default number of results buffered for delivery
+	DefaultQueueSize = 1000
+	// DefaultMaxRetries is the default number of retries of a failed delivery
+	DefaultMaxRetries = 3
+	// DefaultTimeout is the default timeout of a delivery request
+	DefaultTimeout = 10 * time.Second
+	// DefaultSignatureHeader is the default header carrying the hmac signature of the body
+	DefaultSignatureHeader = "X-Nuclei-Signature"
+
+	// flushInterval is the interval after which a partial batch is sent
+	flushInterval = 5 * time.Second
+	// initialBackoff is the wait before the first retry, doubled for each retry
+	initialBackoff = time.Second
+	// maxBackoff is the max wait between retries
+	maxBackoff = 30 * time.Second
+)
+
+// Options contains the configuration options for the webhook exporter
+type Options struct {
+	// URL is the url results are posted to
+	URL string `yaml:"url" validate:"required"`
+	// Headers are additional headers sent with each request (ex: authorization)
+	Headers map[string]string `yaml:"headers"`
+	// Secret (optional) signs the body of requests with hmac-sha256, the hex
+	// encoded signature is sent in the signature header as sha256=<signature>
+	Secret string `yaml:"secret"`
+	// SignatureHeader is the header carrying the signature (default X-Nuclei-Signature)
+	SignatureHeader string `yaml:"signature-header"`
+	// BatchSize is the number of results sent in a single request. Results
+	// are sent as a json object if 1 (default) else as a json array.
+	BatchSize int `yaml:"batch-size"`
+	// QueueSize is the number of results buffered for delivery, results
+	// exceeding it are dropped and reported as failed instead of blocking the scan
+	QueueSize int `yaml:"queue-size"`
+	// MaxRetries is the number of retries with exponential backoff of requests
+	// failing with a network error, 429 or 5xx status code (default 3, -1 disables)
+	MaxRetries int `yaml:"max-retries"`
+	// Timeout is the timeout of a request
+	Timeout time.Duration `yaml:"timeout"`
+	OmitRaw bool          `yaml:"omit-raw"`
+
+	HttpClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Exporter is an exporter posting results to a webhook as they are found
+type Exporter struct {
+	options *Options
+	client  *http.Client
+
+	queue chan []byte
+	wg    sync.WaitGroup
+
+	delivered atomic.Int64
+	failed    atomic.Int64
+	lastErr   atomic.Value
+}
+
+// New creates a new webhook exporter. Results are delivered asynchronously
+// by a background worker so that exporting does not block the scan.
+func New(options *Options) (*Exporter, error) {
+	if options.URL == "" {
+		return nil, errors.New("webhook url is required")
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultQueueSize
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	} else if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultMaxRetries
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.SignatureHeader == "" {
+		options.SignatureHeader = DefaultSignatureHeader
+	}
+
+	var client *http.Client
+	if options.HttpClient != nil {
+		client = options.HttpClient.HTTPClient
+	} else {
+		transport := &http.Transport{MaxIdleConns: 10, MaxIdleConnsPerHost: 10}
+		if protocolstate.Dialer != nil {
+			transport.DialContext = protocolstate.Dialer.Dial
+			transport.DialTLSContext = protocolstate.Dialer.DialTLS
+		}
+		client = &http.Client{Timeout: options.Timeout, Transport: transport}
+	}
+
+	exporter := &Exporter{
+		options: options,
+		client:  client,
+		queue:   make(chan []byte, options.QueueSize),
+	}
+	exporter.wg.Add(1)
+	go exporter.deliver()
+	return exporter, nil
+}
+
+// Export queues the result event for delivery. The event is marshalled
+// before queueing as it may be modified by other exporters once returned.
+// Results are dropped (and reported as failed) if the queue is full.
+func (exporter *Exporter) Export(event *output.ResultEvent) error {
+	if exporter.options.OmitRaw {
+		copied := *event
+		copied.Request = ""
+		copied.Response = ""
+		event = &copied
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		exporter.fail(1, errors.Wrap(err, "could not marshal result"))
+		return errors.Wrap(err, "could not marshal result")
+	}
+	select {
+	case exporter.queue <- data:
+	default:
+		exporter.failed.Add(1)
+		exporter.lastErr.Store("queue is full")
+	}
+	return nil
+}
+
+// deliver sends queued results in batches until the queue is closed
+func (exporter *Exporter) deliver() {
+	defer exporter.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, exporter.options.BatchSize)
+	for {
+		select {
+		case event, ok := <-exporter.queue:
+			if !ok {
+				exporter.send(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= exporter.options.BatchSize {
+				exporter.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			exporter.send(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// send posts the batch of marshalled results retrying failed requests with backoff
+func (exporter *Exporter) send(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	body := batch[0]
+	if exporter.options.BatchSize > 1 {
+		body = append(append([]byte{'['}, bytes.Join(batch, []byte{','})...), ']')
+	}
+
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := exporter.post(body)
+		if err == nil {
+			exporter.delivered.Add(int64(len(batch)))
+			return
+		}
+		if !retry || attempt >= exporter.options.MaxRetries {
+			exporter.fail(len(batch), err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// post posts the body to the webhook returning true if a failure can be retried
+func (exporter *Exporter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, exporter.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "could not make request")
+	}
+	req.Header.Set("User-Agent", useragent.PickRandom().Raw)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range exporter.options.Headers {
+		req.Header.Set(k, v)
+	}
+	if exporter.options.Secret != "" {
+		req.Header.Set(exporter.options.SignatureHeader, "sha256="+Sign(exporter.options.Secret, body))
+	}
+
+	resp, err := exporter.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return retry, fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+func (exporter *Exporter) fail(count int, err error) {
+	exporter.failed.Add(int64(count))
+	exporter.lastErr.Store(err.Error())
+}
+
+// Sign returns the hex encoded hmac-sha256 signature of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Stats returns the number of results delivered and failed to be delivered
+func (exporter *Exporter) Stats() (delivered, failed int64) {
+	return exporter.delivered.Load(), exporter.failed.Load()
+}
+
+// Close delivers the queued results and reports delivery failures
+func (exporter *Exporter) Close() error {
+	close(exporter.queue)
+	exporter.wg.Wait()
+
+	delivered, failed := exporter.Stats()
+	if delivered > 0 {
+		gologger.Info().Msgf("Delivered %d results to webhook", delivered)
+	}
+	if failed > 0 {
+		lastErr, _ := exporter.lastErr.Load().(string)
+		gologger.Warning().Msgf("Could not deliver %d results to webhook: %s", failed, lastErr)
+		return fmt.Errorf("could not deliver %d results to webhook", failed)
+	}
+	return nil
+}
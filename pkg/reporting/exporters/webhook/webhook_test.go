@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+func TestWebhookExporter(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(DefaultSignatureHeader) != "sha256="+Sign("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var events []map[string]interface{}
+		if err := json.Unmarshal(body, &events); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, events...)
+	}))
+	defer server.Close()
+
+	exporter, err := New(&Options{URL: server.URL, Secret: "secret", BatchSize: 2, OmitRaw: true})
+	require.Nil(t, err, "could not create exporter")
+
+	for _, id := range []string{"first", "second", "third"} {
+		event := &output.ResultEvent{TemplateID: id, Request: "GET / HTTP/1.1"}
+		err = exporter.Export(event)
+		require.Nil(t, err, "could not export result")
+		// events are modified by other exporters once exported
+		event.TemplateID = "modified"
+	}
+	require.Nil(t, exporter.Close(), "could not deliver results")
+
+	delivered, failed := exporter.Stats()
+	require.Equal(t, int64(3), delivered, "could not get delivered results")
+	require.Equal(t, int64(0), failed, "could not get failed results")
+	require.Equal(t, 3, attempts, "could not retry failed delivery")
+	require.Len(t, received, 3, "could not receive results")
+	require.Equal(t, "first", received[0]["template-id"], "could not keep order of results")
+	require.NotContains(t, received[0], "request", "could not omit raw request")
+}
+
+func TestWebhookExporterFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter, err := New(&Options{URL: server.URL})
+	require.Nil(t, err, "could not create exporter")
+	require.Nil(t, exporter.Export(&output.ResultEvent{TemplateID: "test"}), "could not export result")
+	require.NotNil(t, exporter.Close(), "could not report delivery failure")
+
+	delivered, failed := exporter.Stats()
+	require.Equal(t, int64(0), delivered, "could not get delivered results")
+	require.Equal(t, int64(1), failed, "could not get failed results without retrying client errors")
+}
@@ -2,6 +2,7 @@ package format
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/reproduce"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown/util"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
@@ -147,6 +149,16 @@ func CreateReportDescription(event *output.ResultEvent, formatter ResultFormatte
 			formatter.CreateCodeBlock("CURL command", types.ToHexOrString(event.CURLCommand), "sh"),
 		)
 	}
+	if event.HTTPieCommand != "" {
+		builder.WriteString(
+			formatter.CreateCodeBlock("HTTPie command", types.ToHexOrString(event.HTTPieCommand), "sh"),
+		)
+	}
+	if len(event.RequestBody) > 0 && !omitRaw {
+		builder.WriteString(
+			formatter.CreateCodeBlock(fmt.Sprintf("Request body (base64, decode to %s to run the commands)", reproduce.BodyFile), base64.StdEncoding.EncodeToString(event.RequestBody), ""),
+		)
+	}
 
 	builder.WriteString("\n" + formatter.CreateHorizontalLine() + "\n")
 	builder.WriteString(fmt.Sprintf("Generated by %s", formatter.CreateLink("Nuclei "+config.Version, "https://github.com/projectdiscovery/nuclei")))
@@ -7,6 +7,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown/util"
 	"github.com/stretchr/testify/require"
 )
@@ -46,3 +47,17 @@ func TestToMarkdownTableString(t *testing.T) {
 	require.Equal(t, strings.Split(expectedOrderedAttributes, "\n"), actualAttributeSlice[:dynamicAttributeIndex]) // the first part of the result is ordered
 	require.ElementsMatch(t, expectedDynamicAttributes, actualAttributeSlice[dynamicAttributeIndex:])              // dynamic parameters are not ordered
 }
+
+func TestCreateReportDescriptionRequestBody(t *testing.T) {
+	event := &output.ResultEvent{
+		Info:        model.Info{Name: "test", SeverityHolder: severity.Holder{Severity: severity.High}},
+		Host:        "https://example.com",
+		CURLCommand: "curl -k --data-binary '@request-body.bin' 'https://example.com'",
+		RequestBody: []byte{0x00, 0x01, 0xff},
+	}
+	description := CreateReportDescription(event, util.MarkdownFormatter{}, false)
+	require.Contains(t, description, "AAH/", "could not include base64 request body")
+
+	description = CreateReportDescription(event, util.MarkdownFormatter{}, true)
+	require.NotContains(t, description, "AAH/", "could include request body with omit raw")
+}
@@ -1,12 +1,15 @@
 package reporting
 
 import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/bundle"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/es"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/jsonexporter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/jsonl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sarif"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/splunk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sqlite"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/webhook"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/filters"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/gitea"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/github"
@@ -41,6 +44,12 @@ type Options struct {
 	JSONExporter *jsonexporter.Options `yaml:"json"`
 	// JSONLExporter contains configuration options for JSONL Exporter Module
 	JSONLExporter *jsonl.Options `yaml:"jsonl"`
+	// SQLiteExporter contains configuration options for SQLite Exporter Module
+	SQLiteExporter *sqlite.Options `yaml:"sqlite"`
+	// WebhookExporter contains configuration options for Webhook Exporter Module
+	WebhookExporter *webhook.Options `yaml:"webhook"`
+	// BundleExporter contains configuration options for Proof Bundle Exporter Module
+	BundleExporter *bundle.Options `yaml:"bundle"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 	OmitRaw    bool                  `yaml:"-"`
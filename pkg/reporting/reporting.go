@@ -19,10 +19,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/dedupe"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/bundle"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/es"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/markdown"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sarif"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/splunk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sqlite"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/webhook"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/filters"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/gitea"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/github"
@@ -140,6 +143,30 @@ func New(options *Options, db string, doNotDedupe bool) (Client, error) {
 		}
 		client.exporters = append(client.exporters, exporter)
 	}
+	if options.SQLiteExporter != nil && options.SQLiteExporter.File != "" {
+		options.SQLiteExporter.OmitRaw = options.SQLiteExporter.OmitRaw || options.OmitRaw
+		exporter, err := sqlite.New(options.SQLiteExporter)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Wrap(ErrExportClientCreation)
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.WebhookExporter != nil && options.WebhookExporter.URL != "" {
+		options.WebhookExporter.HttpClient = options.HttpClient
+		options.WebhookExporter.OmitRaw = options.WebhookExporter.OmitRaw || options.OmitRaw
+		exporter, err := webhook.New(options.WebhookExporter)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Wrap(ErrExportClientCreation)
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.BundleExporter != nil && options.BundleExporter.Directory != "" {
+		exporter, err := bundle.New(options.BundleExporter)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Wrap(ErrExportClientCreation)
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
 	if options.ElasticsearchExporter != nil {
 		options.ElasticsearchExporter.HttpClient = options.HttpClient
 		exporter, err := es.New(options.ElasticsearchExporter)
@@ -201,6 +228,9 @@ func CreateConfigIfNotExists() error {
 		SplunkExporter:        &splunk.Options{},
 		JSONExporter:          &json_exporter.Options{},
 		JSONLExporter:         &jsonl.Options{},
+		SQLiteExporter:        &sqlite.Options{},
+		WebhookExporter:       &webhook.Options{},
+		BundleExporter:        &bundle.Options{},
 	}
 	reportingFile, err := os.Create(reportingConfig)
 	if err != nil {
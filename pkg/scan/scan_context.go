@@ -52,6 +52,11 @@ func (s *ScanContext) Context() context.Context {
 	return s.ctx
 }
 
+// SetContext sets the context of the scan
+func (s *ScanContext) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
 // GenerateResult returns final results slice from all events
 func (s *ScanContext) GenerateResult() []*output.ResultEvent {
 	s.m.Lock()
@@ -10,6 +10,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
@@ -61,7 +62,8 @@ func Cluster(list []*Template) [][]*Template {
 		}
 
 		// it is not possible to cluster flow and multiprotocol due to dependent execution
-		if template.Flow != "" || template.Options.IsMultiProtocol {
+		// and templates with their own rate limit or scope
+		if template.Flow != "" || template.Options.IsMultiProtocol || template.RateLimit > 0 || template.Scope != nil {
 			_ = skip.Set(key, struct{}{})
 			final = append(final, []*Template{template})
 			continue
@@ -90,7 +92,8 @@ func Cluster(list []*Template) [][]*Template {
 			}
 
 			// it is not possible to cluster flow and multiprotocol due to dependent execution
-			if other.Flow != "" || other.Options.IsMultiProtocol {
+			// and templates with their own rate limit or scope
+			if other.Flow != "" || other.Options.IsMultiProtocol || other.RateLimit > 0 || other.Scope != nil {
 				_ = skip.Set(otherKey, struct{}{})
 				final = append(final, []*Template{other})
 				continue
@@ -216,6 +219,7 @@ func NewClusterExecuter(requests []*Template, options *protocols.ExecutorOptions
 	appendOperator := func(req *Template, operator *operators.Operators) {
 		operator.TemplateID = req.ID
 		operator.ExcludeMatchers = options.ExcludeMatchers
+		operator.PartialMatchThreshold = options.Options.PartialMatchThreshold
 
 		executer.operators = append(executer.operators, &clusteredOperator{
 			operator:     operator,
@@ -276,10 +280,18 @@ func (e *ClusterExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 		}
 		for _, operator := range e.operators {
 			result, matched := operator.operator.Execute(event.InternalEvent, e.requests.Match, e.requests.Extract, e.options.Options.Debug || e.options.Options.DebugResponse)
+			e.recordScanValues(ctx, operator, result)
 			event.InternalEvent["template-id"] = operator.templateID
 			event.InternalEvent["template-path"] = operator.templatePath
 			event.InternalEvent["template-info"] = operator.templateInfo
 
+			if result != nil && result.PartialMatch != nil {
+				event.OperatorsResult = result
+				event.Results = []*output.ResultEvent{eventcreator.MakePartialMatchEvent(e.requests, event)}
+				_ = writer.WriteResult(event, e.options.Output, e.options.Progress, e.options.IssuesClient)
+				ctx.LogEvent(&output.InternalWrappedEvent{InternalEvent: event.InternalEvent, OperatorsResult: result, Results: event.Results})
+				continue
+			}
 			if result == nil && !matched && e.options.Options.MatcherStatus {
 				if err := e.options.Output.WriteFailure(event); err != nil {
 					gologger.Warning().Msgf("Could not write failure event to output: %s\n", err)
@@ -292,6 +304,9 @@ func (e *ClusterExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 				results = true
 
 				_ = writer.WriteResult(event, e.options.Output, e.options.Progress, e.options.IssuesClient)
+				// results of each clustered template are logged so that
+				// they are returned by the scan context
+				ctx.LogEvent(&output.InternalWrappedEvent{InternalEvent: event.InternalEvent, OperatorsResult: result, Results: event.Results})
 			}
 		}
 	})
@@ -303,7 +318,6 @@ func (e *ClusterExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 func (e *ClusterExecuter) ExecuteWithResults(ctx *scan.ScanContext) ([]*output.ResultEvent, error) {
-	scanCtx := scan.NewScanContext(ctx.Context(), ctx.Input)
 	dynamicValues := make(map[string]interface{})
 
 	inputItem := ctx.Input.Clone()
@@ -315,13 +329,19 @@ func (e *ClusterExecuter) ExecuteWithResults(ctx *scan.ScanContext) ([]*output.R
 	err := e.requests.ExecuteWithResults(inputItem, dynamicValues, nil, func(event *output.InternalWrappedEvent) {
 		for _, operator := range e.operators {
 			result, matched := operator.operator.Execute(event.InternalEvent, e.requests.Match, e.requests.Extract, e.options.Options.Debug || e.options.Options.DebugResponse)
-			if matched && result != nil {
+			e.recordScanValues(ctx, operator, result)
+			partial := result != nil && result.PartialMatch != nil
+			if (matched && result != nil) || partial {
 				event.OperatorsResult = result
 				event.InternalEvent["template-id"] = operator.templateID
 				event.InternalEvent["template-path"] = operator.templatePath
 				event.InternalEvent["template-info"] = operator.templateInfo
-				event.Results = e.requests.MakeResultEvent(event)
-				scanCtx.LogEvent(event)
+				if partial {
+					event.Results = []*output.ResultEvent{eventcreator.MakePartialMatchEvent(e.requests, event)}
+				} else {
+					event.Results = e.requests.MakeResultEvent(event)
+				}
+				ctx.LogEvent(&output.InternalWrappedEvent{InternalEvent: event.InternalEvent, OperatorsResult: result, Results: event.Results})
 			}
 		}
 	})
@@ -332,5 +352,14 @@ func (e *ClusterExecuter) ExecuteWithResults(ctx *scan.ScanContext) ([]*output.R
 	if err != nil && e.options.HostErrorsCache != nil {
 		e.options.HostErrorsCache.MarkFailed(ctx.Input.MetaInput.Input, err)
 	}
-	return scanCtx.GenerateResult(), err
+	return ctx.GenerateResult(), err
+}
+
+// recordScanValues records the values extracted by the operators of a
+// clustered template for templates executed later on the same input (if enabled)
+func (e *ClusterExecuter) recordScanValues(ctx *scan.ScanContext, operator *clusteredOperator, result *operators.Result) {
+	if e.options.ScanValues == nil || result == nil {
+		return
+	}
+	e.options.ScanValues.Record(ctx.Input.MetaInput.Input, operator.templateID, result)
 }
@@ -2,12 +2,14 @@ package templates
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
@@ -22,7 +24,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/offlinehttp"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
+	"github.com/projectdiscovery/ratelimit"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
@@ -30,6 +34,7 @@ import (
 var (
 	ErrCreateTemplateExecutor          = errors.New("cannot create template executer")
 	ErrIncompatibleWithOfflineMatching = errors.New("template can't be used for offline matching")
+	ErrIncompatibleWithRequestDump     = errors.New("template can't be used in request dump mode as it has non-http requests")
 	// track how many templates are verfied and by which signer
 	SignatureStats = map[string]*atomic.Uint64{}
 )
@@ -143,6 +148,10 @@ func (template *Template) compileProtocolRequests(options *protocols.ExecutorOpt
 	if options.Options.OfflineHTTP {
 		return template.compileOfflineHTTPRequest(options)
 	}
+	// only http requests are built without being sent in request dump mode
+	if options.Options.RequestDump && templateRequests-len(template.Workflows) != len(template.RequestsHTTP) {
+		return ErrIncompatibleWithRequestDump
+	}
 
 	var requests []protocols.Request
 
@@ -345,6 +354,23 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 	options.TemplateID = template.ID
 	options.TemplateInfo = template.Info
 	options.StopAtFirstMatch = template.StopAtFirstMatch
+	if template.Proxy != "" {
+		if err := types.ValidateProxyURL(template.Proxy); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("invalid proxy for template %s", template.ID)
+		}
+	}
+	options.Proxy = template.Proxy
+	if template.RateLimit < 0 {
+		return nil, errorutil.New("invalid rate-limit for template %s: must not be negative", template.ID)
+	}
+	if template.RateLimit > 0 {
+		options.TemplateRateLimiter = ratelimit.New(context.Background(), uint(template.RateLimit), time.Second)
+	}
+	if template.Scope != nil {
+		if err := template.Scope.Compile(); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("invalid scope for template %s", template.ID)
+		}
+	}
 
 	if template.Variables.Len() > 0 {
 		options.Variables = template.Variables
@@ -403,6 +429,10 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 		}
 	}
 
+	if options.TrustStore != nil {
+		template.Untrusted = !options.TrustStore.Check(options.TemplatePath, data, template)
+	}
+
 	if !(template.Verified && verifier.Identifier() == "projectdiscovery/nuclei-templates") {
 		template.Options.RawTemplate = data
 	}
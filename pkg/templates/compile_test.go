@@ -196,4 +196,64 @@ func Test_WrongTemplate(t *testing.T) {
 	got, err = templates.Parse(filePath, nil, executerOpts)
 	require.Nil(t, got, "could not parse template")
 	require.ErrorContains(t, err, "no requests defined ")
+
+	filePath = "tests/invalid-proxy.yaml"
+	got, err = templates.Parse(filePath, nil, executerOpts)
+	require.Nil(t, got, "could not parse template")
+	require.ErrorContains(t, err, "invalid proxy for template")
+
+	filePath = "tests/invalid-rate-limit.yaml"
+	got, err = templates.Parse(filePath, nil, executerOpts)
+	require.Nil(t, got, "could not parse template")
+	require.ErrorContains(t, err, "invalid rate-limit for template")
+}
+
+func Test_ParseRequestDump(t *testing.T) {
+	setup()
+
+	options := *executerOpts.Options
+	options.RequestDump = true
+	dumpOpts := executerOpts
+	dumpOpts.Options = &options
+	dumpOpts.Parser = templates.NewParser()
+
+	got, err := templates.Parse("tests/multiproto.yaml", nil, dumpOpts)
+	require.Nil(t, got, "could not reject non-http template")
+	require.ErrorIs(t, err, templates.ErrIncompatibleWithRequestDump)
+
+	got, err = templates.Parse("tests/match-1.yaml", nil, dumpOpts)
+	require.Nil(t, err, "could not parse http template")
+	require.NotNil(t, got, "could not parse http template")
+}
+
+func Test_ParseRateLimit(t *testing.T) {
+	setup()
+
+	// the global rate limiter only allows a single request per hour
+	options := *executerOpts.Options
+	options.RateLimit = 1
+	options.RateLimitDuration = time.Hour
+	limitOpts := executerOpts
+	limitOpts.Options = &options
+	limitOpts.Parser = templates.NewParser()
+	limitOpts.RateLimiter = ratelimit.New(context.Background(), 1, time.Hour)
+	defer limitOpts.RateLimiter.Stop()
+
+	got, err := templates.Parse("tests/rate-limit.yaml", nil, limitOpts)
+	require.Nil(t, err, "could not parse template")
+	require.NotNil(t, got.Options.TemplateRateLimiter, "could not create template rate limiter")
+	defer got.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			got.Options.RateLimitTake(context.Background(), "example.com")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("template rate limit did not override the global rate limit")
+	}
 }
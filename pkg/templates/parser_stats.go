@@ -11,4 +11,5 @@ const (
 	ExludedDastTmplStats         = "fuzz-flag-missing-warnings"
 	SkippedUnsignedStats         = "skipped-unsigned-stats" // tracks loading of unsigned templates
 	SkippedRequestSignatureStats = "skipped-request-signature-stats"
+	SkippedUntrustedStats        = "skipped-untrusted-stats" // tracks templates failing trust store verification
 )
@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// Scope restricts the inputs a template is executed on. The template is
+// skipped for inputs whose host does not match the host patterns or on which
+// none of the technologies were detected by templates executed before it.
+type Scope struct {
+	// description: |
+	//   Hosts are the patterns of hosts the template is executed on. Patterns are
+	//   globs (ex: *.example.com) matched case insensitively or cidr ranges matching
+	//   ip hosts. All hosts are in scope if not set.
+	// examples:
+	//   - value: >
+	//       []string{"*.example.com", "10.0.0.0/8"}
+	Hosts []string `yaml:"hosts,omitempty" json:"hosts,omitempty" jsonschema:"title=hosts in scope,description=Glob patterns or cidr ranges of hosts the template is executed on"`
+	// description: |
+	//   ExcludeHosts are the patterns of hosts the template is not executed on
+	//   (taking precedence over hosts).
+	ExcludeHosts []string `yaml:"exclude-hosts,omitempty" json:"exclude-hosts,omitempty" jsonschema:"title=hosts out of scope,description=Glob patterns or cidr ranges of hosts the template is not executed on"`
+	// description: |
+	//   Tech are the technologies of which at least one must have been detected on
+	//   the input by templates of a higher priority (requires -priority-order).
+	//
+	//   Detected technologies are the ids of templates which matched, the names of
+	//   their matchers which matched and the values of their extractors named tech.
+	// examples:
+	//   - value: >
+	//       []string{"wordpress", "wordpress-detect"}
+	Tech []string `yaml:"tech,omitempty" json:"tech,omitempty" jsonschema:"title=technologies in scope,description=Technologies of which one must have been detected on the input by templates of a higher priority"`
+}
+
+// Compile validates the patterns of the scope
+func (s *Scope) Compile() error {
+	for _, pattern := range append(append([]string{}, s.Hosts...), s.ExcludeHosts...) {
+		if strings.Contains(pattern, "/") {
+			if _, _, err := net.ParseCIDR(pattern); err != nil {
+				return errors.Wrapf(err, "invalid cidr %s in scope", pattern)
+			}
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid host pattern %s in scope", pattern)
+		}
+	}
+	for _, tech := range s.Tech {
+		if strings.TrimSpace(tech) == "" {
+			return errors.New("empty tech in scope")
+		}
+	}
+	return nil
+}
+
+// Check returns the reason the input is out of scope or an empty string if
+// it is in scope. Technologies are not checked if the store is nil.
+func (s *Scope) Check(input string, store *scanvalues.Store) string {
+	if s == nil {
+		return ""
+	}
+	host := inputHost(input)
+	if len(s.Hosts) > 0 && !matchHost(s.Hosts, host) {
+		return fmt.Sprintf("host %s does not match scope hosts", host)
+	}
+	if matchHost(s.ExcludeHosts, host) {
+		return fmt.Sprintf("host %s matches excluded scope hosts", host)
+	}
+	if len(s.Tech) > 0 && store != nil && !store.HasTech(input, s.Tech...) {
+		return fmt.Sprintf("none of tech %s detected", strings.Join(s.Tech, ","))
+	}
+	return ""
+}
+
+// matchHost returns true if the host matches any of the patterns
+func matchHost(patterns []string, host string) bool {
+	ip := net.ParseIP(host)
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			if _, network, err := net.ParseCIDR(pattern); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(strings.ToLower(pattern), host); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// inputHost returns the lowercased host (without port) of the input
+func inputHost(input string) string {
+	host := input
+	if parsed, err := urlutil.Parse(input); err == nil && parsed.Hostname() != "" {
+		host = parsed.Hostname()
+	} else if hostname, _, err := net.SplitHostPort(input); err == nil {
+		host = hostname
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
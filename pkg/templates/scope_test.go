@@ -0,0 +1,32 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/scanvalues"
+)
+
+func TestScopeCheck(t *testing.T) {
+	scope := &Scope{Hosts: []string{"*.example.com", "10.0.0.0/8"}, ExcludeHosts: []string{"admin.example.com"}}
+	require.Nil(t, scope.Compile(), "could not compile scope")
+	require.Empty(t, scope.Check("https://WWW.example.com/path", nil), "could not match host pattern")
+	require.Empty(t, scope.Check("10.1.2.3:8443", nil), "could not match cidr")
+	require.NotEmpty(t, scope.Check("https://example.org", nil), "matched host out of scope")
+	require.NotEmpty(t, scope.Check("https://admin.example.com", nil), "matched excluded host")
+
+	store := scanvalues.New()
+	store.Record("https://blog.example.com", "wordpress-detect", &operators.Result{Matched: true})
+	scope = &Scope{Tech: []string{"WordPress-Detect"}}
+	require.Empty(t, scope.Check("https://blog.example.com", store), "could not match detected tech")
+	require.Contains(t, scope.Check("https://shop.example.com", store), "none of tech", "matched undetected tech")
+	require.Empty(t, scope.Check("https://shop.example.com", nil), "checked tech without store")
+
+	var unscoped *Scope
+	require.Empty(t, unscoped.Check("https://example.org", store), "nil scope is not unrestricted")
+
+	require.NotNil(t, (&Scope{Hosts: []string{"[a-"}}).Compile(), "compiled invalid host pattern")
+	require.NotNil(t, (&Scope{Hosts: []string{"10.0.0.0/33"}}).Compile(), "compiled invalid cidr")
+}
@@ -0,0 +1,184 @@
+package signer
+
+import (
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Trust store policies applied to templates failing verification
+const (
+	// TrustPolicyWarn reports untrusted templates but loads them
+	TrustPolicyWarn = "warn"
+	// TrustPolicyReject skips untrusted templates
+	TrustPolicyReject = "reject"
+	// TrustPolicyFail skips untrusted templates and fails the scan
+	TrustPolicyFail = "fail"
+)
+
+var (
+	ErrTemplateNotSigned = errors.New("template is not signed")
+	ErrUntrustedSigner   = errors.New("signature does not match any trusted key")
+)
+
+// TrustFailure is a template which failed trust store verification
+type TrustFailure struct {
+	// Path is the path of the template
+	Path string
+	// Reason is the reason of the verification failure
+	Reason string
+}
+
+// TrustStore verifies templates only against a set of trusted
+// certificates, ignoring the default template verifiers.
+type TrustStore struct {
+	policy    string
+	verifiers []*TemplateSigner
+
+	mutex    sync.Mutex
+	failures map[string]string
+}
+
+// NewTrustStore loads trusted certificates from the given files or
+// directories (*.crt, *.pem) and returns a trust store with the policy.
+func NewTrustStore(paths []string, policy string) (*TrustStore, error) {
+	switch policy {
+	case "":
+		policy = TrustPolicyReject
+	case TrustPolicyWarn, TrustPolicyReject, TrustPolicyFail:
+	default:
+		return nil, errorutil.NewWithTag("signer", "invalid trust store policy %s (supported: warn, reject, fail)", policy)
+	}
+	store := &TrustStore{policy: policy, failures: make(map[string]string)}
+	for _, path := range paths {
+		files, err := trustStoreFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			verifiers, err := loadTrustedCerts(file)
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("could not load trusted certificate %s", file)
+			}
+			store.verifiers = append(store.verifiers, verifiers...)
+		}
+	}
+	if len(store.verifiers) == 0 {
+		return nil, errorutil.NewWithTag("signer", "no trusted certificates found in trust store")
+	}
+	return store, nil
+}
+
+// trustStoreFiles returns the certificate files of a trust store path
+func trustStoreFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read trust store %s", path)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read trust store %s", path)
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".crt" && ext != ".pem") {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	return files, nil
+}
+
+// loadTrustedCerts returns verifiers for each certificate of a pem file
+func loadTrustedCerts(file string) ([]*TemplateSigner, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var verifiers []*TemplateSigner
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		verifier, err := NewTemplateSigVerifier(pem.EncodeToMemory(block))
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, verifier)
+	}
+	if len(verifiers) == 0 {
+		return nil, errorutil.NewWithTag("signer", "no pem encoded certificate found")
+	}
+	return verifiers, nil
+}
+
+// Policy returns the policy of the trust store
+func (t *TrustStore) Policy() string {
+	return t.policy
+}
+
+// Verify verifies the template against the trusted certificates and
+// returns the identifier of the certificate which signed it.
+func (t *TrustStore) Verify(data []byte, tmpl SignableTemplate) (string, error) {
+	if len(GetSignatureFromData(data)) == 0 {
+		return "", ErrTemplateNotSigned
+	}
+	for _, verifier := range t.verifiers {
+		// errors only denote the signature was not created by the verifier
+		if verified, _ := verifier.Verify(data, tmpl); verified {
+			return verifier.Identifier(), nil
+		}
+	}
+	return "", ErrUntrustedSigner
+}
+
+// Check verifies the template recording verification failures and
+// returns true if the template is allowed to be loaded by the policy.
+func (t *TrustStore) Check(path string, data []byte, tmpl SignableTemplate) bool {
+	if _, err := t.Verify(data, tmpl); err != nil {
+		t.mutex.Lock()
+		t.failures[path] = err.Error()
+		t.mutex.Unlock()
+		return t.policy == TrustPolicyWarn
+	}
+	return true
+}
+
+// Err returns an error if templates failed verification and the policy
+// requires failing the scan
+func (t *TrustStore) Err() error {
+	if t.policy != TrustPolicyFail {
+		return nil
+	}
+	if failures := t.Failures(); len(failures) > 0 {
+		return errorutil.NewWithTag("signer", "%d template(s) failed trust store verification", len(failures))
+	}
+	return nil
+}
+
+// Failures returns the templates which failed verification sorted by path
+func (t *TrustStore) Failures() []TrustFailure {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	failures := make([]TrustFailure, 0, len(t.failures))
+	for path, reason := range t.failures {
+		failures = append(failures, TrustFailure{Path: path, Reason: reason})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Path < failures[j].Path
+	})
+	return failures
+}
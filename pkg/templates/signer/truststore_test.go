@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSignableTemplate struct{}
+
+func (t *testSignableTemplate) GetFileImports() []string { return nil }
+
+func (t *testSignableTemplate) HasCodeProtocol() bool { return false }
+
+func newTestSigner(t *testing.T, identifier string) (*TemplateSigner, []byte) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, "could not generate key")
+	handler := &KeyHandler{}
+	cert, err := handler.generateCertWithKey(identifier, privateKey)
+	require.Nil(t, err, "could not generate cert")
+	noUserPassphrase = true
+	key, err := handler.marshalPrivateKey(privateKey)
+	require.Nil(t, err, "could not marshal key")
+	signer, err := NewTemplateSigner(cert, key)
+	require.Nil(t, err, "could not create signer")
+	return signer, cert
+}
+
+func TestTrustStore(t *testing.T) {
+	trusted, trustedCert := newTestSigner(t, "trusted")
+	untrusted, _ := newTestSigner(t, "untrusted")
+
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "trusted.crt"), trustedCert, 0600), "could not write cert")
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0600), "could not write file")
+
+	template := []byte("id: test\ninfo:\n  name: test\n")
+	sign := func(signer *TemplateSigner) []byte {
+		signature, err := signer.Sign(template, &testSignableTemplate{})
+		require.Nil(t, err, "could not sign template")
+		return append(append([]byte{}, template...), []byte("\n"+signature)...)
+	}
+
+	store, err := NewTrustStore([]string{dir}, TrustPolicyReject)
+	require.Nil(t, err, "could not create trust store")
+
+	identifier, err := store.Verify(sign(trusted), &testSignableTemplate{})
+	require.Nil(t, err, "could not verify trusted template")
+	require.Equal(t, "trusted", identifier, "could not get trusted identifier")
+
+	require.True(t, store.Check("trusted.yaml", sign(trusted), &testSignableTemplate{}), "rejected trusted template")
+	require.False(t, store.Check("untrusted.yaml", sign(untrusted), &testSignableTemplate{}), "allowed untrusted template")
+	require.False(t, store.Check("unsigned.yaml", template, &testSignableTemplate{}), "allowed unsigned template")
+	require.Equal(t, []TrustFailure{
+		{Path: "unsigned.yaml", Reason: ErrTemplateNotSigned.Error()},
+		{Path: "untrusted.yaml", Reason: ErrUntrustedSigner.Error()},
+	}, store.Failures(), "could not report failures")
+	require.Nil(t, store.Err(), "returned error with reject policy")
+
+	store, err = NewTrustStore([]string{filepath.Join(dir, "trusted.crt")}, TrustPolicyWarn)
+	require.Nil(t, err, "could not create trust store from file")
+	require.True(t, store.Check("unsigned.yaml", template, &testSignableTemplate{}), "rejected template with warn policy")
+	require.Len(t, store.Failures(), 1, "could not report failure with warn policy")
+
+	store, err = NewTrustStore([]string{dir}, TrustPolicyFail)
+	require.Nil(t, err, "could not create trust store with fail policy")
+	require.True(t, store.Check("trusted.yaml", sign(trusted), &testSignableTemplate{}), "rejected trusted template with fail policy")
+	require.Nil(t, store.Err(), "returned error without failures")
+	require.False(t, store.Check("unsigned.yaml", template, &testSignableTemplate{}), "allowed unsigned template with fail policy")
+	require.NotNil(t, store.Err(), "could not return error with fail policy")
+
+	_, err = NewTrustStore([]string{dir}, "invalid")
+	require.NotNil(t, err, "created trust store with invalid policy")
+	_, err = NewTrustStore([]string{t.TempDir()}, TrustPolicyFail)
+	require.NotNil(t, err, "created trust store without certificates")
+}
@@ -129,6 +129,44 @@ type Template struct {
 	// description: |
 	//  Stop execution once first match is found
 	StopAtFirstMatch bool `yaml:"stop-at-first-match,omitempty" json:"stop-at-first-match,omitempty" jsonschema:"title=stop at first match,description=Stop at first match for the template"`
+	// description: |
+	//   Proxy is the http or socks5 proxy used for http requests (including fuzzing
+	//   requests) of the template.
+	//
+	//   It takes precedence over the global proxy (-proxy) which is used when unset.
+	// examples:
+	//   - value: "\"http://127.0.0.1:8080\""
+	Proxy string `yaml:"proxy,omitempty" json:"proxy,omitempty" jsonschema:"title=proxy for the template,description=Proxy overriding the global proxy for http requests of the template"`
+	// description: |
+	//   RateLimit is the maximum number of requests per second sent by the template
+	//   (including fuzzing requests) to self-limit against rate limited apis.
+	//
+	//   It overrides the global rate limit (-rate-limit) for requests of the template
+	//   while the per-host shared rate limit still applies.
+	// examples:
+	//   - value: "5"
+	RateLimit int `yaml:"rate-limit,omitempty" json:"rate-limit,omitempty" jsonschema:"title=rate limit of the template,description=Maximum number of requests per second sent by the template"`
+	// description: |
+	//   Priority is the execution priority of the template when templates are
+	//   executed in priority order (-priority-order). Templates of a higher
+	//   priority (ex: fingerprint templates) are executed on all inputs before
+	//   templates of a lower priority, which can reference the values they
+	//   extracted from the same input as <template_id>_<extractor> variables.
+	//
+	//   Templates without priority have a priority of 0.
+	// examples:
+	//   - value: "10"
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty" jsonschema:"title=execution priority of the template,description=Templates of a higher priority are executed before templates of a lower priority in priority order"`
+	// description: |
+	//   Scope restricts the inputs the template (including its fuzzing requests)
+	//   is executed on to hosts matching patterns and to inputs on which
+	//   technologies were detected by templates of a higher priority.
+	//
+	//   Skipped inputs are reported in the trace log (-trace-log).
+	// examples:
+	//   - value: >
+	//       &Scope{Tech: []string{"wordpress"}}
+	Scope *Scope `yaml:"scope,omitempty" json:"scope,omitempty" jsonschema:"title=scope of the template,description=Hosts and technologies of inputs the template is executed on"`
 
 	// description: |
 	//   Signature is the request signature method
@@ -156,6 +194,8 @@ type Template struct {
 	Verified bool `yaml:"-" json:"-"`
 	// TemplateVerifier is identifier verifier used to verify the template (default nuclei-templates have projectdiscovery/nuclei-templates)
 	TemplateVerifier string `yaml:"-" json:"-"`
+	// Untrusted defines if the template failed trust store verification and is not allowed by the trust store policy
+	Untrusted bool `yaml:"-" json:"-"`
 	// RequestsQueue contains all template requests in order (both protocol & request order)
 	RequestsQueue []protocols.Request `yaml:"-" json:"-"`
 
@@ -193,6 +233,14 @@ func (template *Template) Type() types.ProtocolType {
 	}
 }
 
+// Close stops the rate limiter of the template (if any). The template must
+// not be executed after being closed.
+func (template *Template) Close() {
+	if template.Options != nil && template.Options.TemplateRateLimiter != nil {
+		template.Options.TemplateRateLimiter.Stop()
+	}
+}
+
 // IsFuzzing returns true if the template is a fuzzing template
 func (template *Template) IsFuzzing() bool {
 	if len(template.RequestsHTTP) == 0 && len(template.RequestsHeadless) == 0 {
@@ -116,6 +116,7 @@ type MockOutputWriter struct {
 	RequestCallback func(templateID, url, requestType string, err error)
 	FailureCallback func(result *output.InternalEvent)
 	WriteCallback   func(o *output.ResultEvent)
+	SkipCallback    func(event *output.SkipEvent)
 }
 
 // NewMockOutputWriter creates a new mock output writer
@@ -146,6 +147,13 @@ func (m *MockOutputWriter) Request(templateID, url, requestType string, err erro
 	}
 }
 
+// WriteSkip writes the skip event to the skip callback
+func (m *MockOutputWriter) WriteSkip(event *output.SkipEvent) {
+	if m.SkipCallback != nil {
+		m.SkipCallback(event)
+	}
+}
+
 // WriteFailure writes the event to file and/or screen.
 func (m *MockOutputWriter) WriteFailure(wrappedEvent *output.InternalWrappedEvent) error {
 	// if failure event has more than one result, write them all
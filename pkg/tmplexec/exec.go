@@ -19,6 +19,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/flow"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/generic"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/multiproto"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TemplateExecutor is an executor for a template
@@ -119,6 +122,9 @@ func (e *TemplateExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 	}()
 	// ==== end of stats ====
 
+	span, restore := e.startSpan(ctx)
+	defer restore()
+
 	// executed contains status of execution if it was successfully executed or not
 	// doesn't matter if it was matched or not
 	executed := &atomic.Bool{}
@@ -145,6 +151,7 @@ func (e *TemplateExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 			// something went wrong
 			return
 		}
+		e.recordScanValues(ctx, event)
 		// check for internal true matcher event
 		if event.HasOperatorResult() && event.OperatorsResult.Matched && event.OperatorsResult.Operators != nil {
 			// note all matchers should have internal:true if it is a combination then print it
@@ -200,11 +207,26 @@ func (e *TemplateExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 	if lastMatcherEvent != nil {
 		writeFailureCallback(lastMatcherEvent, e.options.Options.MatcherStatus)
 	}
+	tracing.SetAttributes(span, attribute.Bool("matched", matched.Load()))
+	tracing.End(span, errx)
 	return executed.Load() || matched.Load(), errx
 }
 
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 func (e *TemplateExecuter) ExecuteWithResults(ctx *scan.ScanContext) ([]*output.ResultEvent, error) {
+	span, restore := e.startSpan(ctx)
+	defer restore()
+
+	if e.options.ScanValues != nil {
+		onResult := ctx.OnResult
+		ctx.OnResult = func(event *output.InternalWrappedEvent) {
+			e.recordScanValues(ctx, event)
+			if onResult != nil {
+				onResult(event)
+			}
+		}
+	}
+
 	var errx error
 	if e.options.Flow != "" {
 		flowexec, err := flow.NewFlowExecutor(e.requests, ctx, e.options, e.results, e.program)
@@ -223,10 +245,39 @@ func (e *TemplateExecuter) ExecuteWithResults(ctx *scan.ScanContext) ([]*output.
 	if errx != nil {
 		ctx.LogError(errx)
 	}
-	return ctx.GenerateResult(), errx
+	results := ctx.GenerateResult()
+	tracing.SetAttributes(span, attribute.Bool("matched", len(results) > 0))
+	tracing.End(span, errx)
+	return results, errx
+}
+
+// recordScanValues records the values extracted by the event for
+// templates executed later on the same input (if enabled)
+func (e *TemplateExecuter) recordScanValues(ctx *scan.ScanContext, event *output.InternalWrappedEvent) {
+	if e.options.ScanValues == nil || event == nil || !event.HasOperatorResult() {
+		return
+	}
+	e.options.ScanValues.Record(ctx.Input.MetaInput.Input, e.options.TemplateID, event.OperatorsResult)
 }
 
 // getTemplateType returns the template type of the template
+// startSpan starts the template execution span and carries it on the scan
+// context and its input so that protocol request spans are its children.
+// The returned function restores the previous contexts.
+func (e *TemplateExecuter) startSpan(ctx *scan.ScanContext) (trace.Span, func()) {
+	spanCtx, span := tracing.Start(ctx.Context(), "template.execute", e.options.TemplateID, ctx.Input.MetaInput.Input, e.getTemplateType())
+	if !tracing.Enabled() {
+		return span, func() {}
+	}
+	previousCtx, previousInput := ctx.Context(), ctx.Input
+	ctx.SetContext(spanCtx)
+	ctx.Input = ctx.Input.WithContext(tracing.WithSpan(ctx.Input.Context(), span))
+	return span, func() {
+		ctx.SetContext(previousCtx)
+		ctx.Input = previousInput
+	}
+}
+
 func (e *TemplateExecuter) getTemplateType() string {
 	if len(e.requests) == 0 {
 		return "null"
@@ -109,7 +109,7 @@ func (f *FlowExecutor) Compile() error {
 	// load all variables and evaluate with existing data
 	variableMap := f.options.Variables.Evaluate(f.options.GetTemplateCtx(f.ctx.Input.MetaInput).GetAll())
 	// cli options
-	optionVars := generators.BuildPayloadFromOptions(f.options.Options)
+	optionVars := f.options.BuildPayloadFromOptions(f.ctx.Input.MetaInput)
 	// constants
 	constants := f.options.Constants
 	allVars := generators.MergeMaps(variableMap, constants, optionVars)
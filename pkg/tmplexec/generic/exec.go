@@ -1,7 +1,9 @@
 package generic
 
 import (
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/projectdiscovery/gologger"
@@ -44,12 +46,18 @@ func (g *Generic) ExecuteWithResults(ctx *scan.ScanContext) error {
 	}
 	previous := mapsutil.NewSyncLockMap[string, any]()
 
+	positions := make(map[string]int)
+
 	for _, req := range g.requests {
 		select {
 		case <-ctx.Context().Done():
 			return ctx.Context().Err()
 		default:
 		}
+		// requests of all protocols wait while the scan is paused
+		if err := g.options.Pauser.Wait(ctx.Context()); err != nil {
+			return err
+		}
 
 		inputItem := ctx.Input.Clone()
 		if g.options.InputHelper != nil && ctx.Input.MetaInput.Input != "" {
@@ -58,7 +66,11 @@ func (g *Generic) ExecuteWithResults(ctx *scan.ScanContext) error {
 			}
 		}
 
-		err := req.ExecuteWithResults(inputItem, dynamicValues, output.InternalEvent(previous.GetAll()), func(event *output.InternalWrappedEvent) {
+		previousEvent := output.InternalEvent(previous.GetAll())
+		positions[req.Type().String()]++
+		positionalID := req.Type().String() + "_" + strconv.Itoa(positions[req.Type().String()])
+		var positional sync.Once
+		err := req.ExecuteWithResults(inputItem, dynamicValues, previousEvent, func(event *output.InternalWrappedEvent) {
 			// this callback is not concurrent safe so mutex should be used to synchronize
 			if event == nil {
 				// ideally this should never happen since protocol exits on error and callback is not called
@@ -75,6 +87,20 @@ func (g *Generic) ExecuteWithResults(ctx *scan.ScanContext) error {
 					builder.Reset()
 				}
 			}
+			// preserve values of the request by its position among requests of the same
+			// protocol (ex: http_1_status_code) so that later requests can compare against them.
+			// Requests emitting multiple events (ex: payloads) preserve the values of their
+			// first event only so that positional values all belong to the same response.
+			if len(g.requests) > 1 {
+				positional.Do(func() {
+					for k, v := range event.InternalEvent {
+						if _, ok := previousEvent[k]; ok {
+							continue
+						}
+						_ = previous.Set(positionalID+"_"+k, v)
+					}
+				})
+			}
 			if event.HasOperatorResult() {
 				g.results.CompareAndSwap(false, true)
 			}
@@ -0,0 +1,101 @@
+package generic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/pause"
+	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRequest is a request counting its executions
+type countingRequest struct {
+	protocols.Request
+	executed atomic.Int32
+}
+
+func (r *countingRequest) ExecuteWithResults(_ *contextargs.Context, _, _ output.InternalEvent, _ protocols.OutputEventCallback) error {
+	r.executed.Add(1)
+	return nil
+}
+
+func (r *countingRequest) Type() templateTypes.ProtocolType {
+	return templateTypes.NetworkProtocol
+}
+
+func TestGenericPause(t *testing.T) {
+	request := &countingRequest{}
+	pauser := pause.New()
+	engine := NewGenericEngine([]protocols.Request{request}, &protocols.ExecutorOptions{Pauser: pauser}, nil)
+
+	pauser.Pause()
+	done := make(chan error, 1)
+	go func() {
+		done <- engine.ExecuteWithResults(scan.NewScanContext(context.Background(), contextargs.NewWithInput(context.Background(), "example.com:80")))
+	}()
+	time.Sleep(100 * time.Millisecond)
+	require.Zero(t, request.executed.Load(), "could not hold request while paused")
+
+	pauser.Resume()
+	require.NoError(t, <-done, "could not execute request after resume")
+	require.Equal(t, int32(1), request.executed.Load(), "could not execute request after resume")
+
+	pauser.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- engine.ExecuteWithResults(scan.NewScanContext(ctx, contextargs.NewWithInput(ctx, "example.com:80")))
+	}()
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled, "could not stop waiting on cancelled context")
+	require.Equal(t, int32(1), request.executed.Load(), "request executed while paused")
+}
+
+// eventsRequest is a request emitting events and recording the values of
+// previous requests it was executed with
+type eventsRequest struct {
+	protocols.Request
+	events   []output.InternalEvent
+	previous output.InternalEvent
+}
+
+func (r *eventsRequest) ExecuteWithResults(_ *contextargs.Context, _, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	r.previous = previous
+	for _, event := range r.events {
+		callback(&output.InternalWrappedEvent{InternalEvent: event})
+	}
+	return nil
+}
+
+func (r *eventsRequest) Type() templateTypes.ProtocolType {
+	return templateTypes.HTTPProtocol
+}
+
+func (r *eventsRequest) GetID() string {
+	return ""
+}
+
+func TestGenericPositionalValues(t *testing.T) {
+	first := &eventsRequest{events: []output.InternalEvent{
+		{"status_code": 200, "body": "first"},
+		{"status_code": 302, "body": "second"},
+	}}
+	second := &eventsRequest{events: []output.InternalEvent{{"status_code": 400}}}
+	third := &eventsRequest{}
+	engine := NewGenericEngine([]protocols.Request{first, second, third}, &protocols.ExecutorOptions{Pauser: pause.New(), Options: &types.Options{}}, nil)
+
+	err := engine.ExecuteWithResults(scan.NewScanContext(context.Background(), contextargs.NewWithInput(context.Background(), "https://example.com")))
+	require.NoError(t, err, "could not execute requests")
+
+	require.Empty(t, first.previous, "could not execute first request without previous values")
+	require.Equal(t, output.InternalEvent{"http_1_status_code": 200, "http_1_body": "first"}, second.previous, "could not preserve values of first event of request")
+	require.Equal(t, 200, third.previous["http_1_status_code"], "could not preserve values of first request")
+	require.Equal(t, 400, third.previous["http_2_status_code"], "could not preserve values of second request")
+}
@@ -52,6 +52,10 @@ func (m *MultiProtocol) ExecuteWithResults(ctx *scan.ScanContext) error {
 
 	// put all readonly args into template context
 	m.options.GetTemplateCtx(ctx.Input.MetaInput).Merge(m.readOnlyArgs)
+	// per-input variables take precedence over cli variables
+	if vars := m.options.InputVars.Get(ctx.Input.MetaInput.Input); len(vars) > 0 {
+		m.options.GetTemplateCtx(ctx.Input.MetaInput).Merge(vars)
+	}
 
 	// add all input args to template context
 	ctx.Input.ForEach(func(key string, value interface{}) {
@@ -107,6 +111,10 @@ func (m *MultiProtocol) ExecuteWithResults(ctx *scan.ScanContext) error {
 			return ctx.Context().Err()
 		default:
 		}
+		// requests of all protocols wait while the scan is paused
+		if err := m.options.Pauser.Wait(ctx.Context()); err != nil {
+			return err
+		}
 
 		values := m.options.GetTemplateCtx(ctx.Input.MetaInput).GetAll()
 		err := req.ExecuteWithResults(ctx.Input, output.InternalEvent(values), nil, multiProtoCallback)
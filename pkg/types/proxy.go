@@ -1,5 +1,12 @@
 package types
 
+import (
+	"fmt"
+	"net/url"
+
+	proxyutils "github.com/projectdiscovery/utils/proxy"
+)
+
 const (
 	HTTP_PROXY_ENV = "HTTP_PROXY"
 )
@@ -10,3 +17,20 @@ var (
 	// ProxySocksURL is the URL for the proxy socks server
 	ProxySocksURL string
 )
+
+// ValidateProxyURL validates a http(s) or socks5 proxy url
+func ValidateProxyURL(proxy string) error {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	switch proxyURL.Scheme {
+	case proxyutils.HTTP, proxyutils.HTTPS, proxyutils.SOCKS5:
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return fmt.Errorf("proxy host is empty")
+	}
+	return nil
+}
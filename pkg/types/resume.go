@@ -24,6 +24,8 @@ type ResumeCfg struct {
 	sync.RWMutex
 	ResumeFrom map[string]*ResumeInfo `json:"resumeFrom"`
 	Current    map[string]*ResumeInfo `json:"-"`
+	// Shard is the shard (index/count) of the targets the scan was run with
+	Shard string `json:"shard,omitempty"`
 }
 
 type ResumeInfo struct {
@@ -83,6 +85,7 @@ func (resumeCfg *ResumeCfg) Clone() *ResumeCfg {
 	return &ResumeCfg{
 		ResumeFrom: resumeFrom,
 		Current:    current,
+		Shard:      resumeCfg.Shard,
 	}
 }
 
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"context"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -46,8 +48,16 @@ type Options struct {
 	ExcludeMatchers goflags.StringSlice
 	// CustomHeaders is the list of custom global headers to send with each request.
 	CustomHeaders goflags.StringSlice
+	// CookieFile is a netscape or json cookie file applied to matching http requests
+	CookieFile string
+	// CookieFileUpdate updates cookies of the cookie file with cookies set by responses
+	CookieFileUpdate bool
 	// Vars is the list of custom global vars
 	Vars goflags.RuntimeMap
+	// InputVarsFile is a csv or json file with per-input variables keyed by target
+	InputVarsFile string
+	// BasePathMap maps input hosts to a base path prefix of http request paths in host=/prefix format
+	BasePathMap goflags.StringSlice
 	// Severities filters templates based on their severity and only run the matching ones.
 	Severities severity.Severities
 	// ExcludeSeverities specifies severities to exclude
@@ -74,22 +84,49 @@ type Options struct {
 	InteractshURL string
 	// Interactsh Authorization header value for self-hosted servers
 	InteractshToken string
+	// InteractshProxy is the http(s)/socks5 proxy used for interactsh
+	// registration and polling (independent of the scan proxy)
+	InteractshProxy string
 	// Target URLs/Domains to scan using a template
 	Targets goflags.StringSlice
 	// ExcludeTargets URLs/Domains to exclude from scanning
 	ExcludeTargets goflags.StringSlice
+	// ShardIndex is the index of the slice of targets scanned by this instance
+	ShardIndex int
+	// ShardCount is the number of slices the targets are partitioned into
+	ShardCount int
 	// TargetsFilePath specifies the targets from a file to scan using templates.
 	TargetsFilePath string
 	// Resume the scan from the state stored in the resume config file
 	Resume string
 	// Output is the file to write found results to.
 	Output string
+	// OutputRotateSize is the size in MB after which output files are rotated
+	OutputRotateSize int
+	// OutputRotateInterval is the duration after which output files are rotated
+	OutputRotateInterval time.Duration
+	// OutputFlushInterval is the interval at which output files are synced to disk
+	OutputFlushInterval time.Duration
 	// ProxyInternal requests
 	ProxyInternal bool
 	// Show all supported DSL signatures
 	ListDslSignatures bool
+	// ListMatcherVariables is the protocol (or all) to list variables available to matchers for
+	ListMatcherVariables string
 	// List of HTTP(s)/SOCKS5 proxy to use (comma separated or file input)
 	Proxy goflags.StringSlice
+	// ProxyRotation is the strategy http requests are rotated through all proxies with
+	ProxyRotation string
+	// SSHJumpHost is the [user@]host[:port] of the ssh jump host requests are tunneled through
+	SSHJumpHost string
+	// SSHJumpKey is the private key file used to authenticate to the ssh jump host
+	SSHJumpKey string
+	// SSHJumpPassword is the password used to authenticate to the ssh jump host
+	SSHJumpPassword string
+	// SSHJumpKnownHosts is the known hosts file the host key of the ssh jump host is verified against
+	SSHJumpKnownHosts string
+	// SSHJumpInsecure disables the verification of the host key of the ssh jump host
+	SSHJumpInsecure bool
 	// TemplatesDirectory is the directory to use for storing templates
 	NewTemplatesDirectory string
 	// TraceLogFile specifies a file to write with the trace of all requests
@@ -139,6 +176,12 @@ type Options struct {
 	RateLimitMinute int
 	// PageTimeout is the maximum time to wait for a page in seconds
 	PageTimeout int
+	// ChallengeDetect enables detection of anti-automation challenge responses
+	ChallengeDetect bool
+	// ChallengeSolve passes detected challenges with the headless browser reusing its cookies
+	ChallengeSolve bool
+	// ChallengePatterns are custom regexes of challenge responses
+	ChallengePatterns goflags.StringSlice
 	// InteractionsCacheSize is the number of interaction-url->req to keep in cache at a time.
 	InteractionsCacheSize int
 	// InteractionsPollDuration is the number of seconds to wait before each interaction poll
@@ -149,6 +192,13 @@ type Options struct {
 	// InteractionsCoolDownPeriod is additional seconds to wait for interactions after closing
 	// of the poller.
 	InteractionsCoolDownPeriod int
+	// InteractionsPollMaxDuration is the maximum number of seconds idle polling backs off to
+	InteractionsPollMaxDuration int
+	// InteractionsBatchSize is the number of correlation ids registered resetting backed off polling
+	InteractionsBatchSize int
+	// InteractionsDrainTimeout is the maximum number of seconds to wait for interactions
+	// of pending requests after the cooldown period
+	InteractionsDrainTimeout int
 	// MaxRedirects is the maximum numbers of redirects to be followed.
 	MaxRedirects int
 	// FollowRedirects enables following redirects for http request module
@@ -185,6 +235,11 @@ type Options struct {
 	DebugRequests bool
 	// DebugResponse mode allows debugging response for the engine
 	DebugResponse bool
+	// RequestDump prints the fully built http requests of templates without sending them.
+	// Templates with non-http requests are not loaded in request dump mode.
+	RequestDump bool
+	// RequestDumpLimit is the maximum number of requests printed per template in request dump mode
+	RequestDumpLimit int
 	// DisableHTTPProbe disables http probing feature of input normalization
 	DisableHTTPProbe bool
 	// LeaveDefaultPorts skips normalization of default ports
@@ -215,12 +270,27 @@ type Options struct {
 	OmitRawRequests bool
 	// OmitTemplate omits encoded template from JSON output
 	OmitTemplate bool
+	// HTTPieCommand includes the httpie command reproducing http requests in results
+	HTTPieCommand bool
+	// GeoIPDatabases contains MaxMind databases used to enrich results with geo and asn of the ip
+	GeoIPDatabases goflags.StringSlice
+	// AnnotationsFile is a yaml mapping of template ids to remediation, references
+	// and owner merged into results of the templates
+	AnnotationsFile string
 	// JSONExport is the file to export JSON output format to
 	JSONExport string
 	// JSONLExport is the file to export JSONL output format to
 	JSONLExport string
+	// SQLiteExport is the sqlite database file to export results to
+	SQLiteExport string
+	// WebhookExport is the url results are posted to as they are found
+	WebhookExport string
+	// BundleExport is the directory proof bundles of findings are written to
+	BundleExport string
 	// EnableProgressBar enables progress bar
 	EnableProgressBar bool
+	// TUI enables the live terminal dashboard of the running scan
+	TUI bool
 	// TemplateDisplay displays the template contents
 	TemplateDisplay bool
 	// TemplateList lists available templates
@@ -249,6 +319,22 @@ type Options struct {
 	EnvironmentVariables bool
 	// MatcherStatus displays optional status for the failed matches as well
 	MatcherStatus bool
+	// PartialMatchThreshold is the percentage of matchers of templates with
+	// and condition which must fire to report failed matches as partial (0 disables)
+	PartialMatchThreshold int
+	// RequestFailureEvents writes failure events with categorized error type for failed requests
+	RequestFailureEvents bool
+	// ExitCodes are the exit codes of the process for findings of severities
+	// (ex: high,critical:code=2,count=1). The process exits with 0 by default.
+	ExitCodes goflags.StringSlice
+	// DriftBaseline is the file storing fingerprints of targets compared
+	// across scans to write drift events for changed fingerprints
+	DriftBaseline string
+	// DriftFingerprint contains the components of fingerprints (matches, extracts, ip)
+	DriftFingerprint goflags.StringSlice
+	// DriftThreshold is the percentage of changed fingerprint attributes
+	// above which drift events are written
+	DriftThreshold int
 	// ClientCertFile client certificate file (PEM-encoded) used for authenticating against scanned hosts
 	ClientCertFile string
 	// ClientKeyFile client key file (PEM-encoded) used for authenticating against scanned hosts
@@ -259,6 +345,20 @@ type Options struct {
 	ZTLS bool
 	// AllowLocalFileAccess allows local file access from templates payloads
 	AllowLocalFileAccess bool
+	// AllowRemotePayloads allows loading templates payloads from remote urls (http/s3/gs)
+	AllowRemotePayloads bool
+	// RemotePayloadCacheDir is the directory remote payloads are cached in
+	RemotePayloadCacheDir string
+	// RemotePayloadCacheExpiry is the duration after which cached remote payloads are revalidated
+	RemotePayloadCacheExpiry time.Duration
+	// ErrorPageSignatures is a yaml file of error page signatures replacing or extending the built-in ones
+	ErrorPageSignatures string
+	// SSRFTargets is a yaml file of internal targets replacing or extending the built-in ones of ssrf matchers
+	SSRFTargets string
+	// DSLFunctions is a javascript file of user defined functions callable from dsl expressions
+	DSLFunctions string
+	// DSLFunctionTimeout is the max execution time of a call of a user defined dsl function
+	DSLFunctionTimeout time.Duration
 	// RestrictLocalNetworkAccess restricts local network access from templates requests
 	RestrictLocalNetworkAccess bool
 	// ShowMatchLine enables display of match line number
@@ -275,10 +375,15 @@ type Options struct {
 	SNI string
 	// InputFileMode specifies the mode of input file (jsonl, burp, openapi, swagger, etc)
 	InputFileMode string
+	// ReplayTargets are the targets requests of the input file are replayed against
+	ReplayTargets goflags.StringSlice
 	// DialerTimeout sets the timeout for network requests.
 	DialerTimeout time.Duration
 	// DialerKeepAlive sets the keep alive duration for network requests.
 	DialerKeepAlive time.Duration
+	// CustomDialer is an optional function dialing connections of http (including
+	// fuzzing) and network requests instead of the default dialer (sdk only).
+	CustomDialer func(ctx context.Context, network, address string) (net.Conn, error)
 	// Interface to use for network scan
 	Interface string
 	// SourceIP sets custom source IP address for network requests
@@ -295,8 +400,20 @@ type Options struct {
 	HealthCheck bool
 	// Time to wait between each input read operation before closing the stream
 	InputReadTimeout time.Duration
+	// MaxDuration is the wall-clock budget of the whole scan after which it is stopped gracefully
+	MaxDuration time.Duration
 	// Disable stdin for input processing
 	DisableStdin bool
+	// StdinStream consumes stdin targets as a stream with bounded buffering
+	StdinStream bool
+	// RequestDedupe skips duplicate non-fuzz requests of a template across inputs sharing a host
+	RequestDedupe bool
+	// ResponseCache caches responses of safe non-fuzz requests shared by templates
+	ResponseCache bool
+	// ResponseCacheTTL is the duration responses are cached for
+	ResponseCacheTTL time.Duration
+	// ResponseCacheSize is the maximum number of cached responses
+	ResponseCacheSize int
 	// IncludeConditions is the list of conditions templates should match
 	IncludeConditions goflags.StringSlice
 	// Enable uncover engine
@@ -361,6 +478,12 @@ type Options struct {
 	FuzzingMode string
 	// TlsImpersonate enables TLS impersonation
 	TlsImpersonate bool
+	// TLSFingerprint exposes the ja3 of the client hello sent and the ja3s
+	// of the server hello received by http requests
+	TLSFingerprint bool
+	// TLSClientHello is the client hello profile (browser name or ja3 string)
+	// sent by http requests instead of the go tls one
+	TLSClientHello string
 	// CodeTemplateSignaturePublicKey is the custom public key used to verify the template signature (algorithm is automatically inferred from the length)
 	CodeTemplateSignaturePublicKey string
 	// CodeTemplateSignatureAlgorithm specifies the sign algorithm (rsa, ecdsa)
@@ -371,6 +494,10 @@ type Options struct {
 	EnableCodeTemplates bool
 	// DisableUnsignedTemplates disables processing of unsigned templates
 	DisableUnsignedTemplates bool
+	// TrustStore is the list of certificate files or directories templates are verified against
+	TrustStore goflags.StringSlice
+	// TrustStorePolicy is the policy for templates failing trust store verification (warn, reject, fail)
+	TrustStorePolicy string
 	// Disables cloud upload
 	EnableCloudUpload bool
 	// ScanID is the scan ID to use for cloud upload
@@ -387,12 +514,58 @@ type Options struct {
 	SkipFormatValidation bool
 	// PayloadConcurrency is the number of concurrent payloads to run per template
 	PayloadConcurrency int
+	// AutoTune adjusts concurrency and rate limit at runtime based on observed
+	// error rate and latency using the user provided values as maxima
+	AutoTune bool
+	// RateLimitCoordinator is the redis url of the coordinator sharing per-host rate limit between processes
+	RateLimitCoordinator string
+	// SharedRateLimit is the maximum number of requests per rate limit duration to a host across processes
+	SharedRateLimit int
+	// HostRateLimit is the maximum number of requests per rate limit duration to a single host.
+	// Requests to a host are spaced evenly so that requests to different hosts are interleaved.
+	HostRateLimit int
+	// PriorityOrder executes templates in groups of decreasing priority sharing values
+	// extracted by templates of earlier groups with later ones for the same input
+	PriorityOrder bool
+	// SeverityPolicies are the rate and concurrency policies of template severities
+	SeverityPolicies goflags.StringSlice
+	// TemplateConcurrency is the maximum number of in-flight requests of a single
+	// template across hosts and payloads (0 for no limit)
+	TemplateConcurrency int
 	// ProbeConcurrency is the number of concurrent http probes to run with httpx
 	ProbeConcurrency int
 	// Dast only runs DAST templates
 	DAST bool
+	// SafeMode skips fuzzing requests matching known-destructive patterns
+	SafeMode bool
+	// SafeModeDenylist is a file containing additional destructive patterns for safe mode
+	SafeModeDenylist string
+	// FuzzReplayWindow is the number of preceding fuzzing requests per input dumped on a match
+	FuzzReplayWindow int
+	// FuzzParamMining enables parameter mining of templates for all inputs
+	FuzzParamMining bool
+	// FuzzSeenParams is a file storing parameters fuzzed in previous runs which are skipped
+	FuzzSeenParams string
+	// FuzzWAFDetect enables detection of waf blocking fuzzing payloads
+	FuzzWAFDetect bool
+	// FuzzWAFThreshold is the number of blocked fuzzing responses of an input after which a waf is reported
+	FuzzWAFThreshold int
+	// FuzzDiffDir is the directory to save baseline and matched fuzzing response body diffs to
+	FuzzDiffDir string
+	// FuzzDiffMax is the maximum number of body diffs saved
+	FuzzDiffMax int
+	// FuzzDiffMaxSize is the maximum size in bytes of bodies saved for diffs
+	FuzzDiffMaxSize int
+	// FuzzTarpitThreshold is the median response latency of a host above which it is suspected to be a tarpit
+	FuzzTarpitThreshold time.Duration
+	// FuzzTarpitWindow is the number of requests per host the median response latency is computed over
+	FuzzTarpitWindow int
+	// FuzzConfirm re-sends matched fuzzing requests and only reports reproduced matches
+	FuzzConfirm bool
 	// HttpApiEndpoint is the experimental http api endpoint
 	HttpApiEndpoint string
+	// OtelEndpoint is the opentelemetry otlp/http endpoint to export execution traces to
+	OtelEndpoint string
 }
 
 // ShouldLoadResume resume file
@@ -0,0 +1,129 @@
+// Package tracing implements opentelemetry based tracing of template
+// execution, protocol requests, matcher evaluation and interactsh polling.
+//
+// Template execution spans are carried on the scan context and its input so
+// that request and interaction spans are their children. Only http requests
+// (including fuzzing) are instrumented as individual request spans, requests
+// of other protocols are covered by the template.execute span.
+//
+// Tracing is disabled by default and all helpers are no-op (they neither
+// allocate attributes nor create spans) until Init is called with an
+// exporter endpoint.
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+const instrumentationName = "github.com/projectdiscovery/nuclei/v3"
+
+// Span attribute keys set on all nuclei spans
+const (
+	TemplateIDKey = attribute.Key("template-id")
+	HostKey       = attribute.Key("host")
+	ProtocolKey   = attribute.Key("protocol")
+)
+
+var (
+	enabled  atomic.Bool
+	tracer   trace.Tracer = noop.NewTracerProvider().Tracer(instrumentationName)
+	noopSpan              = trace.SpanFromContext(context.Background())
+)
+
+// Init configures an OTLP/HTTP exporter sending spans to given endpoint
+// (ex: http://localhost:4318) and enables tracing. Standard OTEL_EXPORTER_OTLP_*
+// environment variables (like headers) are honoured by the exporter.
+//
+// The returned function must be called before exiting to flush pending spans.
+func Init(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create otlp exporter")
+	}
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.BinaryName),
+		semconv.ServiceVersion(config.Version),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	tracer = provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(config.Version))
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// Enabled returns true if tracing was configured using Init
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Start starts a new span with given name as a child of span in ctx (if any)
+// with template-id, host and protocol attributes.
+//
+// When tracing is disabled, ctx is returned as-is with a no-op span.
+func Start(ctx context.Context, name, templateID, host, protocol string) (context.Context, trace.Span) {
+	if !enabled.Load() {
+		return ctx, noopSpan
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		TemplateIDKey.String(templateID),
+		HostKey.String(host),
+		ProtocolKey.String(protocol),
+	))
+}
+
+// End records err (if any) on the span and ends it
+func End(span trace.Span, err error) {
+	if !span.IsRecording() {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetAttributes sets additional attributes on a span if it is recording
+func SetAttributes(span trace.Span, attrs ...attribute.KeyValue) {
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attrs...)
+}
+
+// WithSpan returns a copy of ctx carrying span so that spans started from it
+// are its children. ctx is returned as-is when tracing is disabled.
+func WithSpan(ctx context.Context, span trace.Span) context.Context {
+	if !enabled.Load() {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return trace.ContextWithSpan(ctx, span)
+}
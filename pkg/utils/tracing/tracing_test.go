@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartDisabled(t *testing.T) {
+	require.False(t, Enabled(), "tracing should be disabled by default")
+
+	ctx := context.WithValue(context.Background(), struct{}{}, "value")
+	gotCtx, span := Start(ctx, "test", "template-id", "host", "http")
+	require.Equal(t, ctx, gotCtx, "context should not be modified when disabled")
+	require.False(t, span.IsRecording(), "span should not be recording when disabled")
+
+	// must not panic on no-op spans
+	SetAttributes(span, TemplateIDKey.String("test"))
+	End(span, errors.New("test error"))
+}
+
+func TestWithSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := tracer
+	tracer = provider.Tracer(instrumentationName)
+	enabled.Store(true)
+	defer func() {
+		tracer = previous
+		enabled.Store(false)
+	}()
+
+	_, parent := Start(context.Background(), "template.execute", "template-id", "host", "http")
+	// spans started from a context carrying the parent span are its children
+	inputCtx := WithSpan(context.WithValue(context.Background(), struct{}{}, "value"), parent)
+	_, child := Start(inputCtx, "http.request", "template-id", "host", "http")
+	End(child, nil)
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2, "could not record spans")
+	require.Equal(t, "http.request", spans[0].Name(), "could not get child span")
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID(), "could not parent span to template span")
+	require.Equal(t, "value", inputCtx.Value(struct{}{}), "could not preserve context values")
+}